@@ -0,0 +1,250 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/contract"
+	"github.com/pkg/errors"
+)
+
+// reserved are the names used by the generated methods, which parameters may not use
+var reserved = map[string]bool{"c": true, "ctx": true, "options": true, "result": true, "err": true, "contract": true, "channel": true}
+
+// generator generates the Go bindings of the contracts of a chaincode
+type generator struct {
+	metadata *contract.Metadata
+	buf      bytes.Buffer
+}
+
+// generate returns the formatted Go source of the bindings
+func generate(metadata *contract.Metadata, pkg string) ([]byte, error) {
+	g := &generator{metadata: metadata}
+
+	g.printf("// Code generated by fabric-contract-gen. DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", pkg)
+	g.printf("import (\n\"context\"\n\n\"github.com/hyperledger/fabric-sdk-go/pkg/client/channel\"\n\"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/contract\"\n)\n\n")
+	g.printf("var _ context.Context\nvar _ channel.RequestOption\n\n")
+
+	for _, name := range sortedSchemaNames(metadata.Components.Schemas) {
+		if err := g.genStruct(name, metadata.Components.Schemas[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range sortedContractNames(metadata.Contracts) {
+		if err := g.genContract(metadata.Contracts[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to format generated source")
+	}
+	return src, nil
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) genStruct(name string, schema *contract.Schema) error {
+	typeName := exportedName(name)
+	g.printf("// %s is the %s object of the contract\n", typeName, name)
+	g.printf("type %s struct {\n", typeName)
+
+	var properties []string
+	for property := range schema.Properties {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	for _, property := range properties {
+		goType, err := g.goType(schema.Properties[property])
+		if err != nil {
+			return errors.WithMessage(err, "invalid property ["+property+"] of ["+name+"]")
+		}
+		tag := property
+		if !schema.IsRequired(property) {
+			tag += ",omitempty"
+		}
+		g.printf("%s %s `json:\"%s\"`\n", exportedName(property), goType, tag)
+	}
+	g.printf("}\n\n")
+	return nil
+}
+
+func (g *generator) genContract(c *contract.ContractMetadata) error {
+	typeName := exportedName(c.Name) + "Client"
+
+	g.printf("// %s invokes the transactions of the %s contract\n", typeName, c.Name)
+	g.printf("type %s struct {\ncontract *contract.Contract\n}\n\n", typeName)
+	g.printf("// New%s returns a client of the %s contract of the given chaincode\n", typeName, c.Name)
+	g.printf("func New%s(client contract.Executor, chaincodeID string) *%s {\n", typeName, typeName)
+	g.printf("return &%s{contract: contract.New(client, chaincodeID, %q)}\n}\n\n", typeName, c.Name)
+
+	for _, tx := range c.Transactions {
+		if err := g.genTransaction(typeName, tx); err != nil {
+			return errors.WithMessage(err, "invalid transaction ["+tx.Name+"] of contract ["+c.Name+"]")
+		}
+	}
+	return nil
+}
+
+func (g *generator) genTransaction(typeName string, tx *contract.TransactionMetadata) error {
+	var params, args []string
+	for i, param := range tx.Parameters {
+		goType, err := g.goType(param.Schema)
+		if err != nil {
+			return errors.WithMessage(err, "invalid parameter ["+param.Name+"]")
+		}
+		name := paramName(param.Name, i)
+		params = append(params, name+" "+goType)
+		args = append(args, name)
+	}
+	params = append([]string{"ctx context.Context"}, params...)
+	params = append(params, "options ...channel.RequestOption")
+
+	invoke, verb := "Submit", "submits"
+	if !tx.IsSubmit() {
+		invoke, verb = "Evaluate", "evaluates"
+	}
+
+	method := exportedName(tx.Name)
+	g.printf("// %s %s the %s transaction\n", method, verb, tx.Name)
+
+	if tx.Returns == nil {
+		g.printf("func (c *%s) %s(%s) error {\n", typeName, method, strings.Join(params, ", "))
+		g.printf("return c.contract.%s(ctx, %q, []interface{}{%s}, nil, options...)\n}\n\n", invoke, tx.Name, strings.Join(args, ", "))
+		return nil
+	}
+
+	returnType, err := g.goType(tx.Returns)
+	if err != nil {
+		return errors.WithMessage(err, "invalid returns")
+	}
+	g.printf("func (c *%s) %s(%s) (%s, error) {\n", typeName, method, strings.Join(params, ", "), returnType)
+	g.printf("var result %s\n", returnType)
+	g.printf("err := c.contract.%s(ctx, %q, []interface{}{%s}, &result, options...)\n", invoke, tx.Name, strings.Join(args, ", "))
+	g.printf("return result, err\n}\n\n")
+	return nil
+}
+
+// goType returns the Go type of a schema
+func (g *generator) goType(schema *contract.Schema) (string, error) {
+	if schema == nil {
+		return "interface{}", nil
+	}
+
+	if schema.Ref != "" {
+		name := schema.RefName()
+		if _, ok := g.metadata.Components.Schemas[name]; !ok {
+			return "", errors.Errorf("schema [%s] not found in components", schema.Ref)
+		}
+		return exportedName(name), nil
+	}
+
+	switch schema.Type {
+	case "string":
+		return "string", nil
+	case "boolean":
+		return "bool", nil
+	case "integer":
+		switch schema.Format {
+		case "int32":
+			return "int32", nil
+		case "uint32":
+			return "uint32", nil
+		case "uint64":
+			return "uint64", nil
+		default:
+			return "int64", nil
+		}
+	case "number":
+		if schema.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "array":
+		itemType, err := g.goType(schema.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + itemType, nil
+	case "object", "":
+		return "map[string]interface{}", nil
+	default:
+		return "", errors.Errorf("unsupported schema type [%s]", schema.Type)
+	}
+}
+
+// exportedName returns an exported Go identifier for a contract, transaction or property name,
+// e.g. "org.example.assets" becomes OrgExampleAssets and "createAsset" becomes CreateAsset
+func exportedName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	s := b.String()
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "X" + s
+	}
+	return s
+}
+
+// paramName returns a Go identifier for the i-th parameter of a transaction
+func paramName(name string, i int) string {
+	if name == "" {
+		return fmt.Sprintf("arg%d", i)
+	}
+
+	s := exportedName(name)
+	runes := []rune(s)
+	runes[0] = unicode.ToLower(runes[0])
+	s = string(runes)
+
+	if token.Lookup(s).IsKeyword() || reserved[s] {
+		s += "Arg"
+	}
+	return s
+}
+
+func sortedSchemaNames(schemas map[string]*contract.Schema) []string {
+	var names []string
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedContractNames(contracts map[string]*contract.ContractMetadata) []string {
+	var names []string
+	for name := range contracts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/contract"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMetadata = `{
+	"contracts": {
+		"org.example.assets": {
+			"name": "org.example.assets",
+			"transactions": [
+				{"name": "CreateAsset", "tag": ["submit"], "parameters": [{"name": "asset", "schema": {"$ref": "#/components/schemas/Asset"}}]},
+				{"name": "ReadAsset", "tag": ["evaluate"], "parameters": [{"name": "id", "schema": {"type": "string"}}], "returns": {"$ref": "#/components/schemas/Asset"}},
+				{"name": "GetAllAssets", "tag": ["EVALUATE"], "returns": [{"name": "success", "schema": {"type": "array", "items": {"$ref": "#/components/schemas/Asset"}}}]},
+				{"name": "transfer", "parameters": [{"name": "type", "schema": {"type": "string"}}, {"name": "count", "schema": {"type": "integer", "format": "int32"}}], "returns": {"type": "boolean"}}
+			]
+		}
+	},
+	"components": {
+		"schemas": {
+			"Asset": {
+				"$id": "Asset",
+				"type": "object",
+				"required": ["ID"],
+				"properties": {"ID": {"type": "string"}, "size": {"type": "number"}, "tags": {"type": "array", "items": {"type": "string"}}}
+			}
+		}
+	}
+}`
+
+func TestGenerate(t *testing.T) {
+	metadata, err := contract.ParseMetadata([]byte(testMetadata))
+	require.NoError(t, err)
+
+	src, err := generate(metadata, "assets")
+	require.NoError(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "contract.gen.go", src, 0)
+	require.NoError(t, err, "expecting valid Go source")
+
+	s := string(src)
+	assert.Contains(t, s, "package assets")
+	assert.Contains(t, s, "ID   string   `json:\"ID\"`")
+	assert.Contains(t, s, "Size float64  `json:\"size,omitempty\"`")
+	assert.Contains(t, s, "Tags []string `json:\"tags,omitempty\"`")
+	assert.Contains(t, s, `contract.New(client, chaincodeID, "org.example.assets")`)
+	assert.Contains(t, s, "func NewOrgExampleAssetsClient(client contract.Executor, chaincodeID string) *OrgExampleAssetsClient")
+	assert.Contains(t, s, "func (c *OrgExampleAssetsClient) CreateAsset(ctx context.Context, asset Asset, options ...channel.RequestOption) error")
+	assert.Contains(t, s, `c.contract.Submit(ctx, "CreateAsset", []interface{}{asset}, nil, options...)`)
+	assert.Contains(t, s, "func (c *OrgExampleAssetsClient) ReadAsset(ctx context.Context, id string, options ...channel.RequestOption) (Asset, error)")
+	assert.Contains(t, s, `c.contract.Evaluate(ctx, "ReadAsset", []interface{}{id}, &result, options...)`)
+	assert.Contains(t, s, "GetAllAssets(ctx context.Context, options ...channel.RequestOption) ([]Asset, error)")
+	assert.Contains(t, s, "Transfer(ctx context.Context, typeArg string, count int32, options ...channel.RequestOption) (bool, error)")
+	assert.Contains(t, s, `c.contract.Submit(ctx, "transfer", []interface{}{typeArg, count}, &result, options...)`)
+}
+
+func TestGenerateInvalidSchema(t *testing.T) {
+	metadata, err := contract.ParseMetadata([]byte(`{"contracts": {"c": {"transactions": [{"name": "tx", "returns": {"$ref": "#/components/schemas/Missing"}}]}}}`))
+	require.NoError(t, err)
+
+	_, err = generate(metadata, "test")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found in components")
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "OrgExampleAssets", exportedName("org.example.assets"))
+	assert.Equal(t, "CreateAsset", exportedName("createAsset"))
+	assert.Equal(t, "X2fa", exportedName("2fa"))
+	assert.Equal(t, "assetID", paramName("assetID", 0))
+	assert.Equal(t, "ctxArg", paramName("ctx", 0))
+	assert.Equal(t, "arg1", paramName("", 1))
+}
@@ -0,0 +1,92 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// fabric-contract-gen generates typed Go client bindings for a chaincode implemented with the Fabric
+// contract API, from the metadata returned by the chaincode's org.hyperledger.fabric:GetMetadata
+// transaction. The bindings invoke the chaincode through the channel client (see package
+// pkg/client/channel/contract), so that calls become client.CreateAsset(ctx, asset) instead of
+// requests with string arguments:
+//
+//  fabric-contract-gen -metadata metadata.json -package assets -out contract.gen.go
+//
+// The generator is typically run with go:generate:
+//
+//  //go:generate fabric-contract-gen -metadata metadata.json -package assets -out contract.gen.go
+//
+// One client type is generated per contract, with one method per transaction, and one struct
+// type per object schema of the metadata components. Transactions tagged as evaluate are queried
+// and all other transactions are submitted.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/contract"
+	"github.com/pkg/errors"
+)
+
+type options struct {
+	metadataFile string
+	pkg          string
+	out          string
+}
+
+func main() {
+	opts := &options{}
+
+	flags := flag.NewFlagSet("fabric-contract-gen", flag.ExitOnError)
+	flags.StringVar(&opts.metadataFile, "metadata", "", "Contract metadata file ('-' for stdin)")
+	flags.StringVar(&opts.pkg, "package", "", "Package of the generated code (defaults to $GOPACKAGE when run with go:generate)")
+	flags.StringVar(&opts.out, "out", "", "Output file (defaults to stdout)")
+	flags.Parse(os.Args[1:]) // nolint: errcheck
+
+	if err := run(opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(opts *options) error {
+	if opts.metadataFile == "" {
+		return errors.New("-metadata is required")
+	}
+	if opts.pkg == "" {
+		opts.pkg = os.Getenv("GOPACKAGE")
+	}
+	if opts.pkg == "" {
+		return errors.New("-package is required")
+	}
+
+	var b []byte
+	var err error
+	if opts.metadataFile == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(opts.metadataFile)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to read contract metadata")
+	}
+
+	metadata, err := contract.ParseMetadata(b)
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(metadata, opts.pkg)
+	if err != nil {
+		return err
+	}
+
+	if opts.out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return errors.Wrap(ioutil.WriteFile(opts.out, src, 0644), "failed to write bindings")
+}
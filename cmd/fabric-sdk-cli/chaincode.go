@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/pkg/errors"
+)
+
+type chaincodeFlags struct {
+	channelID string
+	ccID      string
+	fcn       string
+	args      string
+	isInit    bool
+}
+
+func parseChaincodeFlags(name string, args []string, withInit bool) (*chaincodeFlags, error) {
+	f := &chaincodeFlags{}
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.StringVar(&f.channelID, "channel", "", "Channel ID")
+	flags.StringVar(&f.ccID, "cc", "", "Chaincode ID")
+	flags.StringVar(&f.fcn, "fcn", "", "Chaincode function")
+	flags.StringVar(&f.args, "args", "[]", "Chaincode arguments as a JSON array of strings, e.g. '[\"a\",\"b\"]'")
+	if withInit {
+		flags.BoolVar(&f.isInit, "init", false, "Invoke the chaincode's initialization function")
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if f.ccID == "" || f.fcn == "" {
+		return nil, errors.New("-cc and -fcn are required")
+	}
+	return f, nil
+}
+
+func (f *chaincodeFlags) request() (channel.Request, error) {
+	args, err := parseArgs(f.args)
+	if err != nil {
+		return channel.Request{}, err
+	}
+	return channel.Request{ChaincodeID: f.ccID, Fcn: f.fcn, Args: args, IsInit: f.isInit}, nil
+}
+
+// parseArgs parses chaincode arguments from a JSON array of strings
+func parseArgs(s string) ([][]byte, error) {
+	var strArgs []string
+	if err := json.Unmarshal([]byte(s), &strArgs); err != nil {
+		return nil, errors.Wrap(err, "invalid -args: expecting a JSON array of strings")
+	}
+
+	args := make([][]byte, len(strArgs))
+	for i, a := range strArgs {
+		args[i] = []byte(a)
+	}
+	return args, nil
+}
+
+func queryCmd(env *environment, args []string) error {
+	return invokeChaincode(env, "query", args, false)
+}
+
+func invokeCmd(env *environment, args []string) error {
+	return invokeChaincode(env, "invoke", args, true)
+}
+
+func invokeChaincode(env *environment, name string, args []string, execute bool) error {
+	f, err := parseChaincodeFlags(name, args, execute)
+	if err != nil {
+		return err
+	}
+	request, err := f.request()
+	if err != nil {
+		return err
+	}
+
+	ctx, err := env.channelContext(f.channelID)
+	if err != nil {
+		return err
+	}
+	client, err := channel.New(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create channel client")
+	}
+
+	if !execute {
+		response, err := client.Query(request)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(env.out, "%s\n", response.Payload)
+		return nil
+	}
+
+	response, err := client.Execute(request)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.out, "Transaction [%s] committed with status %s\n", response.TransactionID, response.TxValidationCode)
+	if len(response.Payload) > 0 {
+		fmt.Fprintf(env.out, "%s\n", response.Payload)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	"github.com/pkg/errors"
+)
+
+func configCmd(env *environment, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expecting 'config fetch' or 'config update'")
+	}
+
+	switch args[0] {
+	case "fetch":
+		return configFetchCmd(env, args[1:])
+	case "update":
+		return configUpdateCmd(env, args[1:])
+	default:
+		return errors.Errorf("unknown config command [%s], expecting fetch or update", args[0])
+	}
+}
+
+func configFetchCmd(env *environment, args []string) error {
+	var channelID, outFile, format string
+
+	flags := flag.NewFlagSet("config fetch", flag.ContinueOnError)
+	flags.StringVar(&channelID, "channel", "", "Channel ID")
+	flags.StringVar(&outFile, "out", "", "Output file (defaults to stdout)")
+	flags.StringVar(&format, "format", "json", "Output format: json (decoded configuration) or block (config block protobuf)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if channelID == "" {
+		return errors.New("-channel is required")
+	}
+
+	client, err := env.resmgmtClient()
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch format {
+	case "json":
+		config, err := client.QueryDecodedConfigFromOrderer(channelID)
+		if err != nil {
+			return err
+		}
+		data, err = config.JSON()
+		if err != nil {
+			return err
+		}
+	case "block":
+		block, err := client.QueryConfigBlockFromOrderer(channelID)
+		if err != nil {
+			return err
+		}
+		data, err = proto.Marshal(block)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal config block")
+		}
+	default:
+		return errors.Errorf("invalid -format [%s], expecting json or block", format)
+	}
+
+	if outFile == "" {
+		_, err = env.out.Write(data)
+		return err
+	}
+	return errors.Wrap(ioutil.WriteFile(outFile, data, 0644), "failed to write output file")
+}
+
+func configUpdateCmd(env *environment, args []string) error {
+	var channelID, txFile, anchorPeers, acls, batchTimeout string
+
+	flags := flag.NewFlagSet("config update", flag.ContinueOnError)
+	flags.StringVar(&channelID, "channel", "", "Channel ID")
+	flags.StringVar(&txFile, "tx", "", "Config update transaction (e.g. created by configtxgen or configtxlator)")
+	flags.StringVar(&anchorPeers, "anchor-peers", "", "Sets the anchor peers of the organization (-org) to a comma separated list of <host>:<port>")
+	flags.StringVar(&acls, "acl", "", "Comma separated list of <resource>=<policy> ACLs to set")
+	flags.StringVar(&batchTimeout, "batch-timeout", "", "Sets the batch timeout of the orderer, e.g. 2s")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if channelID == "" {
+		return errors.New("-channel is required")
+	}
+
+	client, err := env.resmgmtClient()
+	if err != nil {
+		return err
+	}
+
+	var response resmgmt.SaveChannelResponse
+	if txFile != "" {
+		tx, err := ioutil.ReadFile(txFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to read config update transaction")
+		}
+		response, err = client.SaveChannel(resmgmt.SaveChannelRequest{ChannelID: channelID, ChannelConfig: bytes.NewReader(tx)})
+		if err != nil {
+			return err
+		}
+	} else {
+		update, err := env.configUpdate(anchorPeers, acls, batchTimeout)
+		if err != nil {
+			return err
+		}
+		response, err = client.UpdateChannelConfig(resmgmt.UpdateChannelConfigRequest{ChannelID: channelID, Update: update})
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(env.out, "Channel [%s] updated with transaction [%s]\n", channelID, response.TransactionID)
+	return nil
+}
+
+// configUpdate returns the function which applies the requested changes to the channel configuration
+func (env *environment) configUpdate(anchorPeers, acls, batchTimeout string) (func(editor *configtx.Editor) error, error) {
+	if anchorPeers == "" && acls == "" && batchTimeout == "" {
+		return nil, errors.New("one of -tx, -anchor-peers, -acl or -batch-timeout is required")
+	}
+
+	peers, err := parseAnchorPeers(anchorPeers)
+	if err != nil {
+		return nil, err
+	}
+	if len(peers) > 0 && env.org == "" {
+		return nil, errors.New("-org is required for -anchor-peers")
+	}
+
+	aclMap, err := parseKeyValues(acls)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid -acl")
+	}
+
+	return func(editor *configtx.Editor) error {
+		if len(peers) > 0 {
+			if err := editor.SetAnchorPeers(env.org, peers); err != nil {
+				return err
+			}
+		}
+		for _, acl := range aclMap {
+			if err := editor.SetACL(acl[0], acl[1]); err != nil {
+				return err
+			}
+		}
+		if batchTimeout != "" {
+			return editor.SetBatchTimeout(batchTimeout)
+		}
+		return nil
+	}, nil
+}
+
+func (env *environment) resmgmtClient() (*resmgmt.Client, error) {
+	ctx, err := env.clientContext("")
+	if err != nil {
+		return nil, err
+	}
+	client, err := resmgmt.New(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create resource management client")
+	}
+	return client, nil
+}
+
+// parseAnchorPeers parses a comma separated list of <host>:<port>
+func parseAnchorPeers(s string) ([]configtx.AnchorPeer, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var peers []configtx.AnchorPeer
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		i := strings.LastIndex(v, ":")
+		if i <= 0 {
+			return nil, errors.Errorf("invalid anchor peer [%s]: expecting <host>:<port>", v)
+		}
+		port, err := strconv.ParseInt(v[i+1:], 10, 32)
+		if err != nil {
+			return nil, errors.Errorf("invalid port of anchor peer [%s]", v)
+		}
+		peers = append(peers, configtx.AnchorPeer{Host: v[:i], Port: int32(port)})
+	}
+	return peers, nil
+}
+
+// parseKeyValues parses a comma separated list of <key>=<value> (in order)
+func parseKeyValues(s string) ([][2]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var kvs [][2]string
+	for _, v := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(v), "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid value [%s]: expecting <key>=<value>", v)
+		}
+		kvs = append(kvs, [2]string{parts[0], parts[1]})
+	}
+	return kvs, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/deploy"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/pkg/errors"
+)
+
+func deployCmd(env *environment, args []string) error {
+	var channelID, pkgFile, orgs, policy string
+	def := resmgmt.LifecycleCCDefinition{}
+
+	flags := flag.NewFlagSet("deploy", flag.ContinueOnError)
+	flags.StringVar(&channelID, "channel", "", "Channel ID")
+	flags.StringVar(&pkgFile, "package", "", "Chaincode package (tar.gz) created by 'peer lifecycle chaincode package'")
+	flags.StringVar(&orgs, "orgs", "", "Comma separated list of <MSP ID>=<organization> which install and approve the chaincode")
+	flags.StringVar(&def.Name, "name", "", "Chaincode name")
+	flags.StringVar(&def.Version, "version", "1.0", "Chaincode version")
+	flags.Int64Var(&def.Sequence, "sequence", 1, "Sequence of the chaincode definition")
+	flags.StringVar(&policy, "policy", "", "Endorsement (signature) policy, e.g. \"OR('Org1MSP.member','Org2MSP.member')\"")
+	flags.StringVar(&def.ChannelConfigPolicy, "channel-config-policy", "", "Endorsement policy from the channel configuration")
+	flags.BoolVar(&def.InitRequired, "init-required", false, "The chaincode must be initialized (see 'invoke -init')")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if channelID == "" || pkgFile == "" || def.Name == "" {
+		return errors.New("-channel, -package and -name are required")
+	}
+
+	if policy != "" {
+		var err error
+		def.SignaturePolicy, err = cauthdsl.FromString(policy)
+		if err != nil {
+			return errors.WithMessage(err, "invalid -policy")
+		}
+	}
+
+	pkg, err := ioutil.ReadFile(pkgFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to read chaincode package")
+	}
+
+	deployOrgs, err := env.deployOrgs(orgs)
+	if err != nil {
+		return err
+	}
+
+	deployer, err := deploy.New(deployOrgs, deploy.WithStatusHandler(func(s deploy.StepStatus) {
+		fmt.Fprintf(env.out, "%-15s %-10s %-10s %s\n", s.Step, s.MSPID, s.State, s.Info)
+	}))
+	if err != nil {
+		return err
+	}
+
+	progress, err := deployer.Deploy(deploy.Request{ChannelID: channelID, Package: pkg, Definition: def}, nil)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.out, "Chaincode [%s] deployed with package ID [%s]\n", def.Name, progress.PackageID)
+	return nil
+}
+
+// deployOrgs returns the organizations of the deployment along with the context of the user
+// (e.g. Admin) in each organization
+func (env *environment) deployOrgs(s string) ([]deploy.Org, error) {
+	orgs, err := parseOrgs(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(orgs) == 0 {
+		return nil, errors.New("-orgs is required")
+	}
+
+	var deployOrgs []deploy.Org
+	for _, o := range orgs {
+		ctx, err := env.clientContext(o.name)
+		if err != nil {
+			return nil, err
+		}
+		deployOrgs = append(deployOrgs, deploy.Org{MSPID: o.mspID, Context: ctx})
+	}
+	return deployOrgs, nil
+}
+
+type orgRef struct {
+	mspID string
+	name  string
+}
+
+// parseOrgs parses a comma separated list of <MSP ID>=<organization>
+func parseOrgs(s string) ([]orgRef, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var orgs []orgRef
+	for _, v := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(v), "=")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid organization [%s]: expecting <MSP ID>=<organization>", v)
+		}
+		orgs = append(orgs, orgRef{mspID: parts[0], name: parts[1]})
+	}
+	return orgs, nil
+}
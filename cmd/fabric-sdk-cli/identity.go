@@ -0,0 +1,55 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/pkg/errors"
+)
+
+func enrollCmd(env *environment, args []string) error {
+	var enrollmentID, secret string
+
+	flags := flag.NewFlagSet("enroll", flag.ContinueOnError)
+	flags.StringVar(&enrollmentID, "id", "", "Enrollment ID of a registered identity")
+	flags.StringVar(&secret, "secret", "", "Enrollment secret")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if enrollmentID == "" || secret == "" {
+		return errors.New("-id and -secret are required")
+	}
+
+	sdk, err := env.fabricSDK()
+	if err != nil {
+		return err
+	}
+
+	var opts []msp.ClientOption
+	if env.org != "" {
+		opts = append(opts, msp.WithOrg(env.org))
+	}
+	client, err := msp.New(sdk.Context(), opts...)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create MSP client")
+	}
+
+	if err := client.Enroll(enrollmentID, msp.WithSecret(secret)); err != nil {
+		return err
+	}
+
+	// The enrollment certificate and key are stored in the SDK's credential store
+	identity, err := client.GetSigningIdentity(enrollmentID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to retrieve enrolled identity")
+	}
+	fmt.Fprintf(env.out, "Identity [%s] of [%s] enrolled\n%s", identity.Identifier().ID, identity.Identifier().MSPID, identity.EnrollmentCertificate())
+	return nil
+}
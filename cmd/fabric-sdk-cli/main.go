@@ -0,0 +1,154 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// fabric-sdk-cli exposes operations of the SDK's clients on the command line. All commands are driven
+// by the SDK configuration (connection profile):
+//
+//  fabric-sdk-cli -config config.yaml -org org1 -user User1 <command> [flags]
+//
+// Commands:
+//  query          queries a chaincode
+//  invoke         invokes (executes) a chaincode transaction
+//  deploy         deploys a chaincode package with the chaincode lifecycle
+//  config fetch   fetches the configuration of a channel
+//  config update  updates the configuration of a channel
+//  enroll         enrolls an identity with the CA of the organization
+//  shell          opens an interactive debug shell for a channel
+//  profile        generates a connection profile from a bootstrap peer (doesn't use -config)
+//
+// Run 'fabric-sdk-cli <command> -h' for the flags of a command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/pkg/errors"
+)
+
+// command runs a command with the given arguments (excluding the command name)
+type command func(env *environment, args []string) error
+
+var commands = map[string]command{
+	"query":   queryCmd,
+	"invoke":  invokeCmd,
+	"deploy":  deployCmd,
+	"config":  configCmd,
+	"enroll":  enrollCmd,
+	"shell":   shellCmd,
+	"profile": profileCmd,
+}
+
+// environment holds the global flags and the SDK instance
+type environment struct {
+	configFile string
+	org        string
+	user       string
+	out        io.Writer
+	sdk        *fabsdk.FabricSDK
+}
+
+func main() {
+	env := &environment{out: os.Stdout}
+
+	flags := flag.NewFlagSet("fabric-sdk-cli", flag.ExitOnError)
+	flags.StringVar(&env.configFile, "config", "", "SDK configuration file (connection profile)")
+	flags.StringVar(&env.org, "org", "", "Organization of the user (defaults to the client organization)")
+	flags.StringVar(&env.user, "user", "User1", "User which signs the requests")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: fabric-sdk-cli [flags] <command> [command flags]\n\nCommands: %v\n\nFlags:\n", commandNames())
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[1:]) // nolint: errcheck
+
+	if err := run(env, flags.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(env *environment, args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("command is required, one of %v", commandNames())
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return errors.Errorf("unknown command [%s], expecting one of %v", args[0], commandNames())
+	}
+
+	defer env.close()
+	return cmd(env, args[1:])
+}
+
+func commandNames() []string {
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fabricSDK returns the SDK instance, which is created on first use
+func (env *environment) fabricSDK() (*fabsdk.FabricSDK, error) {
+	if env.sdk != nil {
+		return env.sdk, nil
+	}
+	if env.configFile == "" {
+		return nil, errors.New("-config is required")
+	}
+
+	sdk, err := fabsdk.New(config.FromFile(env.configFile))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create SDK")
+	}
+	env.sdk = sdk
+	return sdk, nil
+}
+
+func (env *environment) contextOptions(org string) []fabsdk.ContextOption {
+	opts := []fabsdk.ContextOption{fabsdk.WithUser(env.user)}
+	if org != "" {
+		opts = append(opts, fabsdk.WithOrg(org))
+	}
+	return opts
+}
+
+// clientContext returns the context of the user of the given organization (or of the global org if empty)
+func (env *environment) clientContext(org string) (context.ClientProvider, error) {
+	sdk, err := env.fabricSDK()
+	if err != nil {
+		return nil, err
+	}
+	if org == "" {
+		org = env.org
+	}
+	return sdk.Context(env.contextOptions(org)...), nil
+}
+
+func (env *environment) channelContext(channelID string) (context.ChannelProvider, error) {
+	if channelID == "" {
+		return nil, errors.New("-channel is required")
+	}
+	sdk, err := env.fabricSDK()
+	if err != nil {
+		return nil, err
+	}
+	return sdk.ChannelContext(channelID, env.contextOptions(env.org)...), nil
+}
+
+func (env *environment) close() {
+	if env.sdk != nil {
+		env.sdk.Close()
+	}
+}
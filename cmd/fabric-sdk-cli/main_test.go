@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	env := &environment{out: &bytes.Buffer{}}
+
+	err := run(env, nil)
+	assert.Error(t, err, "expecting error for missing command")
+
+	err = run(env, []string{"unknown"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown command")
+
+	err = run(env, []string{"query", "-channel", "mychannel", "-cc", "cc", "-fcn", "get"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "-config is required")
+
+	err = run(env, []string{"query", "-channel", "mychannel"})
+	assert.Error(t, err, "expecting error for missing chaincode ID")
+
+	err = run(env, []string{"config", "delete"})
+	assert.Error(t, err, "expecting error for unknown config command")
+
+	err = run(env, []string{"config", "update", "-channel", "mychannel"})
+	assert.Error(t, err, "expecting error for missing update")
+
+	err = run(env, []string{"shell"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "-channel is required")
+
+	err = run(env, []string{"profile", "-peer", "grpcs://peer0.org1.example.com:7051"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "are required")
+}
+
+func TestParseArgs(t *testing.T) {
+	args, err := parseArgs(`["a","b"]`)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, args)
+
+	_, err = parseArgs("a,b")
+	assert.Error(t, err)
+}
+
+func TestParseOrgs(t *testing.T) {
+	orgs, err := parseOrgs("Org1MSP=org1, Org2MSP=org2")
+	require.NoError(t, err)
+	assert.Equal(t, []orgRef{{mspID: "Org1MSP", name: "org1"}, {mspID: "Org2MSP", name: "org2"}}, orgs)
+
+	orgs, err = parseOrgs("")
+	require.NoError(t, err)
+	assert.Empty(t, orgs)
+
+	_, err = parseOrgs("Org1MSP")
+	assert.Error(t, err)
+}
+
+func TestParseAnchorPeers(t *testing.T) {
+	peers, err := parseAnchorPeers("peer0.org1.example.com:7051,peer1.org1.example.com:8051")
+	require.NoError(t, err)
+	assert.Equal(t, []configtx.AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}, {Host: "peer1.org1.example.com", Port: 8051}}, peers)
+
+	_, err = parseAnchorPeers("peer0.org1.example.com")
+	assert.Error(t, err)
+	_, err = parseAnchorPeers("peer0.org1.example.com:x")
+	assert.Error(t, err)
+}
+
+func TestParseKeyValues(t *testing.T) {
+	kvs, err := parseKeyValues("peer/Propose=/Channel/Application/Writers,qscc/GetChainInfo=/Channel/Application/Readers")
+	require.NoError(t, err)
+	assert.Equal(t, [][2]string{
+		{"peer/Propose", "/Channel/Application/Writers"},
+		{"qscc/GetChainInfo", "/Channel/Application/Readers"},
+	}, kvs)
+
+	_, err = parseKeyValues("peer/Propose")
+	assert.Error(t, err)
+}
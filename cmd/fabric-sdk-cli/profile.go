@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/connprofile"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/pkg/errors"
+)
+
+// profileCmd generates a connection profile from a bootstrap peer. Unlike the other commands it
+// doesn't use -config since the profile is what it creates.
+func profileCmd(env *environment, args []string) error {
+	var peerURL, tlsCAFile, mspID, certFile, keyFile, channels, outFile string
+
+	flags := flag.NewFlagSet("profile", flag.ContinueOnError)
+	flags.StringVar(&peerURL, "peer", "", "URL of the bootstrap peer, e.g. grpcs://peer0.org1.example.com:7051")
+	flags.StringVar(&tlsCAFile, "peer-tls-ca", "", "TLS CA certificate of the bootstrap peer (required for grpcs)")
+	flags.StringVar(&mspID, "msp", "", "MSP ID of the bootstrap peer's organization")
+	flags.StringVar(&certFile, "cert", "", "Certificate of an admin of the organization")
+	flags.StringVar(&keyFile, "key", "", "Private key of the admin")
+	flags.StringVar(&channels, "channels", "", "Comma separated list of channels (defaults to the channels joined by the bootstrap peer)")
+	flags.StringVar(&outFile, "out", "", "Output file (defaults to stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if peerURL == "" || mspID == "" || certFile == "" || keyFile == "" {
+		return errors.New("-peer, -msp, -cert and -key are required")
+	}
+
+	bootstrap := connprofile.Bootstrap{PeerURL: peerURL, MSPID: mspID}
+	var err error
+	if tlsCAFile != "" {
+		if bootstrap.PeerTLSCACert, err = ioutil.ReadFile(tlsCAFile); err != nil {
+			return errors.Wrap(err, "failed to read TLS CA certificate")
+		}
+	}
+	if bootstrap.AdminCert, err = ioutil.ReadFile(certFile); err != nil {
+		return errors.Wrap(err, "failed to read admin certificate")
+	}
+	if bootstrap.AdminKey, err = ioutil.ReadFile(keyFile); err != nil {
+		return errors.Wrap(err, "failed to read admin private key")
+	}
+
+	sdk, err := fabsdk.New(connprofile.BootstrapConfig(bootstrap))
+	if err != nil {
+		return errors.WithMessage(err, "failed to create SDK")
+	}
+	env.sdk = sdk
+
+	generator, err := connprofile.New(sdk.Context(fabsdk.WithUser(connprofile.AdminUser)), peerURL)
+	if err != nil {
+		return err
+	}
+
+	var channelIDs []string
+	for _, ch := range strings.Split(channels, ",") {
+		if ch = strings.TrimSpace(ch); ch != "" {
+			channelIDs = append(channelIDs, ch)
+		}
+	}
+
+	profile, err := generator.Generate(channelIDs...)
+	if err != nil {
+		return err
+	}
+	data, err := profile.YAML()
+	if err != nil {
+		return err
+	}
+
+	if outFile == "" {
+		_, err = env.out.Write(data)
+		return err
+	}
+	return errors.Wrap(ioutil.WriteFile(outFile, data, 0644), "failed to write output file")
+}
@@ -0,0 +1,35 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/shell"
+)
+
+func shellCmd(env *environment, args []string) error {
+	var channelID string
+
+	flags := flag.NewFlagSet("shell", flag.ContinueOnError)
+	flags.StringVar(&channelID, "channel", "", "Channel ID")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, err := env.channelContext(channelID)
+	if err != nil {
+		return err
+	}
+
+	s, err := shell.New(ctx, shell.WithOutput(env.out))
+	if err != nil {
+		return err
+	}
+	return s.Run(os.Stdin)
+}
@@ -0,0 +1,441 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package sw
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Key derivation functions of the encrypted file key store
+const (
+	KDFScrypt   = "scrypt"
+	KDFArgon2id = "argon2id"
+)
+
+// encryptedKeyPEMType is the PEM type of the key files written by the encrypted file key store.
+// The PEM headers hold the KDF parameters, the salt and the nonce; the content is the AES-256-GCM
+// encrypted cleartext PEM of the key as written by the file based key store.
+const encryptedKeyPEMType = "SDK ENCRYPTED KEY"
+
+const (
+	encryptionKeyLength = 32
+	saltLength          = 16
+)
+
+// PassphraseProvider returns the passphrase that protects the keys of an encrypted file key store
+type PassphraseProvider func() ([]byte, error)
+
+// EncryptedFileKeystoreOpts contains the key derivation options of the encrypted file key store.
+// Zero values are replaced by the defaults.
+type EncryptedFileKeystoreOpts struct {
+	// KDF is KDFScrypt (default) or KDFArgon2id
+	KDF string
+
+	ScryptN int
+	ScryptR int
+	ScryptP int
+
+	Argon2Time    uint32
+	Argon2Memory  uint32 // KiB
+	Argon2Threads uint8
+}
+
+func (o EncryptedFileKeystoreOpts) withDefaults() EncryptedFileKeystoreOpts {
+	if o.KDF == "" {
+		o.KDF = KDFScrypt
+	}
+	if o.ScryptN == 0 {
+		o.ScryptN = 1 << 15
+	}
+	if o.ScryptR == 0 {
+		o.ScryptR = 8
+	}
+	if o.ScryptP == 0 {
+		o.ScryptP = 1
+	}
+	if o.Argon2Time == 0 {
+		o.Argon2Time = 1
+	}
+	if o.Argon2Memory == 0 {
+		o.Argon2Memory = 64 * 1024
+	}
+	if o.Argon2Threads == 0 {
+		o.Argon2Threads = 4
+	}
+	return o
+}
+
+// NewEncryptedFileKeyStore returns a file based key store that encrypts private and secret keys
+// with AES-256-GCM using a key derived from the passphrase. Public keys are stored in cleartext.
+// Cleartext keys written by the file based key store are read transparently and, unless the key
+// store is read only, encrypted in place: all of them when the key store is opened and any key
+// added later when it is loaded.
+// The passphrase provider is called once, when the passphrase is first needed.
+func NewEncryptedFileKeyStore(path string, passphrase PassphraseProvider, opts *EncryptedFileKeystoreOpts, readOnly bool) (bccsp.KeyStore, error) {
+	if passphrase == nil {
+		return nil, errors.New("passphrase provider is required")
+	}
+
+	o := EncryptedFileKeystoreOpts{}
+	if opts != nil {
+		o = *opts
+	}
+	o = o.withDefaults()
+	if o.KDF != KDFScrypt && o.KDF != KDFArgon2id {
+		return nil, errors.Errorf("unsupported key derivation function [%s]", o.KDF)
+	}
+
+	fks := &fileBasedKeyStore{}
+	if err := fks.Init(nil, path, readOnly); err != nil {
+		return nil, err
+	}
+
+	ks := &encryptedFileKeyStore{fileBasedKeyStore: fks, passphraseProvider: passphrase, opts: o}
+	if !readOnly {
+		if err := ks.migrate(); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+// encryptedFileKeyStore reuses the file layout of fileBasedKeyStore (<ski>_sk, <ski>_pk and <ski>_key files)
+type encryptedFileKeyStore struct {
+	*fileBasedKeyStore
+
+	passphraseProvider PassphraseProvider
+	passphrase         []byte
+	passphraseLock     sync.Mutex
+
+	opts EncryptedFileKeystoreOpts
+}
+
+// GetKey returns a key object whose SKI is the one passed.
+func (ks *encryptedFileKeyStore) GetKey(ski []byte) (bccsp.Key, error) {
+	if len(ski) == 0 {
+		return nil, errors.New("Invalid SKI. Cannot be of zero length.")
+	}
+
+	alias := hex.EncodeToString(ski)
+	switch ks.getSuffix(alias) {
+	case "sk":
+		k, err := ks.loadFile(alias + "_sk")
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("Failed loading secret key [%x]", ski))
+		}
+		return k, nil
+	case "key":
+		k, err := ks.loadFile(alias + "_key")
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("Failed loading key [%x]", ski))
+		}
+		return k, nil
+	case "pk":
+		return ks.fileBasedKeyStore.GetKey(ski)
+	default:
+		return ks.searchKeystoreForSKI(ski)
+	}
+}
+
+// StoreKey stores the key k in this KeyStore.
+// If this KeyStore is read only then the method will fail.
+func (ks *encryptedFileKeyStore) StoreKey(k bccsp.Key) error {
+	if ks.readOnly {
+		return errors.New("Read only KeyStore.")
+	}
+
+	var privKey interface{}
+	switch kk := k.(type) {
+	case *ecdsaPrivateKey:
+		privKey = kk.privKey
+	case *rsaPrivateKey:
+		privKey = kk.privKey
+	case *aesPrivateKey:
+		return ks.storeFile(hex.EncodeToString(k.SKI())+"_key", utils.AEStoPEM(kk.privKey))
+	default:
+		// Public keys aren't secret
+		return ks.fileBasedKeyStore.StoreKey(k)
+	}
+
+	cleartext, err := utils.PrivateKeyToPEM(privKey, nil)
+	if err != nil {
+		return errors.WithMessage(err, "Failed converting private key to PEM")
+	}
+	return ks.storeFile(hex.EncodeToString(k.SKI())+"_sk", cleartext)
+}
+
+// searchKeystoreForSKI looks for a private key whose file name doesn't contain its SKI
+func (ks *encryptedFileKeyStore) searchKeystoreForSKI(ski []byte) (bccsp.Key, error) {
+	files, _ := ioutil.ReadDir(ks.path)
+	for _, f := range files {
+		if f.IsDir() || f.Size() > (1<<16) || strings.HasSuffix(f.Name(), "_pk") || strings.HasSuffix(f.Name(), "_key") {
+			continue
+		}
+
+		k, err := ks.loadFile(f.Name())
+		if err != nil || !k.Private() || !bytes.Equal(k.SKI(), ski) {
+			continue
+		}
+		return k, nil
+	}
+	return nil, errors.Errorf("Key with SKI %s not found in %s", hex.EncodeToString(ski), ks.path)
+}
+
+// loadFile reads a private or secret key file, encrypting it first if it is in cleartext
+func (ks *encryptedFileKeyStore) loadFile(name string) (bccsp.Key, error) {
+	raw, err := ioutil.ReadFile(filepath.Join(ks.path, name))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read key file")
+	}
+
+	cleartext := raw
+	encrypted := isEncryptedKey(raw)
+	if encrypted {
+		cleartext, err = ks.decrypt(name, raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	k, err := keyFromPEM(cleartext)
+	if err != nil {
+		return nil, err
+	}
+
+	if !encrypted && !ks.readOnly {
+		logger.Debugf("Encrypting cleartext key file [%s]", name)
+		if err := ks.storeFile(name, cleartext); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// storeFile encrypts the cleartext PEM and writes it to the key file, replacing any previous content atomically
+func (ks *encryptedFileKeyStore) storeFile(name string, cleartext []byte) error {
+	raw, err := ks.encrypt(name, cleartext)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(ks.path, name)
+	if err := ioutil.WriteFile(path+".tmp", raw, 0600); err != nil {
+		return errors.Wrap(err, "failed to write key file")
+	}
+	return errors.Wrap(os.Rename(path+".tmp", path), "failed to write key file")
+}
+
+// migrate encrypts the cleartext private and secret keys of the key store
+func (ks *encryptedFileKeyStore) migrate() error {
+	files, err := ioutil.ReadDir(ks.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read key store")
+	}
+
+	for _, f := range files {
+		if f.IsDir() || !(strings.HasSuffix(f.Name(), "_sk") || strings.HasSuffix(f.Name(), "_key")) {
+			continue
+		}
+		raw, err := ioutil.ReadFile(filepath.Join(ks.path, f.Name()))
+		if err != nil {
+			return errors.Wrap(err, "failed to read key file")
+		}
+		if isEncryptedKey(raw) {
+			continue
+		}
+		if _, err := ks.loadFile(f.Name()); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("failed to encrypt key file [%s]", f.Name()))
+		}
+	}
+	return nil
+}
+
+func (ks *encryptedFileKeyStore) encrypt(name string, cleartext []byte) ([]byte, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt")
+	}
+
+	headers, err := ks.kdfHeaders()
+	if err != nil {
+		return nil, err
+	}
+	headers["Salt"] = hex.EncodeToString(salt)
+
+	aead, err := ks.aead(headers, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	headers["Nonce"] = hex.EncodeToString(nonce)
+
+	// The file name is authenticated so that key files can't be swapped
+	ciphertext := aead.Seal(nil, nonce, cleartext, []byte(name))
+	return pem.EncodeToMemory(&pem.Block{Type: encryptedKeyPEMType, Headers: headers, Bytes: ciphertext}), nil
+}
+
+func (ks *encryptedFileKeyStore) decrypt(name string, raw []byte) ([]byte, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil || block.Type != encryptedKeyPEMType {
+		return nil, errors.New("invalid encrypted key")
+	}
+
+	salt, err := hex.DecodeString(block.Headers["Salt"])
+	if err != nil || len(salt) == 0 {
+		return nil, errors.New("invalid salt")
+	}
+	nonce, err := hex.DecodeString(block.Headers["Nonce"])
+	if err != nil {
+		return nil, errors.New("invalid nonce")
+	}
+
+	aead, err := ks.aead(block.Headers, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+
+	cleartext, err := aead.Open(nil, nonce, block.Bytes, []byte(name))
+	if err != nil {
+		return nil, errors.New("decryption failed: incorrect passphrase or corrupted key file")
+	}
+	return cleartext, nil
+}
+
+// kdfHeaders returns the PEM headers describing the configured key derivation function
+func (ks *encryptedFileKeyStore) kdfHeaders() (map[string]string, error) {
+	switch ks.opts.KDF {
+	case KDFScrypt:
+		return map[string]string{
+			"KDF":        KDFScrypt,
+			"KDF-Params": fmt.Sprintf("N=%d,r=%d,p=%d", ks.opts.ScryptN, ks.opts.ScryptR, ks.opts.ScryptP),
+		}, nil
+	case KDFArgon2id:
+		return map[string]string{
+			"KDF":        KDFArgon2id,
+			"KDF-Params": fmt.Sprintf("t=%d,m=%d,p=%d", ks.opts.Argon2Time, ks.opts.Argon2Memory, ks.opts.Argon2Threads),
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported key derivation function [%s]", ks.opts.KDF)
+	}
+}
+
+// aead derives the encryption key with the KDF described by the headers, so that keys written
+// with other KDF options can still be read
+func (ks *encryptedFileKeyStore) aead(headers map[string]string, salt []byte) (cipher.AEAD, error) {
+	passphrase, err := ks.getPassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	var key []byte
+	switch headers["KDF"] {
+	case KDFScrypt:
+		var n, r, p int
+		if _, err := fmt.Sscanf(headers["KDF-Params"], "N=%d,r=%d,p=%d", &n, &r, &p); err != nil {
+			return nil, errors.Wrap(err, "invalid scrypt parameters")
+		}
+		key, err = scrypt.Key(passphrase, salt, n, r, p, encryptionKeyLength)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to derive key")
+		}
+	case KDFArgon2id:
+		var t, m uint32
+		var p uint8
+		if _, err := fmt.Sscanf(headers["KDF-Params"], "t=%d,m=%d,p=%d", &t, &m, &p); err != nil {
+			return nil, errors.Wrap(err, "invalid argon2id parameters")
+		}
+		if t == 0 || p == 0 {
+			return nil, errors.New("invalid argon2id parameters")
+		}
+		key = argon2.IDKey(passphrase, salt, t, m, p, encryptionKeyLength)
+	default:
+		return nil, errors.Errorf("unsupported key derivation function [%s]", headers["KDF"])
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	return aead, errors.Wrap(err, "failed to create cipher")
+}
+
+func (ks *encryptedFileKeyStore) getPassphrase() ([]byte, error) {
+	ks.passphraseLock.Lock()
+	defer ks.passphraseLock.Unlock()
+
+	if ks.passphrase != nil {
+		return ks.passphrase, nil
+	}
+	passphrase, err := ks.passphraseProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get key store passphrase")
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("key store passphrase is empty")
+	}
+	ks.passphrase = passphrase
+	return passphrase, nil
+}
+
+func isEncryptedKey(raw []byte) bool {
+	block, _ := pem.Decode(raw)
+	return block != nil && block.Type == encryptedKeyPEMType
+}
+
+// keyFromPEM returns the key of a cleartext PEM as written by the file based key store
+func keyFromPEM(raw []byte) (bccsp.Key, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("Failed decoding PEM")
+	}
+	if block.Type == "AES PRIVATE KEY" {
+		return &aesPrivateKey{block.Bytes, false}, nil
+	}
+
+	key, err := utils.PEMtoPrivateKey(raw, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		return &ecdsaPrivateKey{k}, nil
+	case *rsa.PrivateKey:
+		return &rsaPrivateKey{k}, nil
+	default:
+		return nil, errors.New("Secret key type not recognized")
+	}
+}
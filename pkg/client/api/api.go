@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package api defines the interfaces implemented by the SDK clients (channel, resmgmt, ledger and event).
+// Applications may depend on these interfaces instead of the concrete clients so that the clients can be
+// replaced in unit tests by the generated mocks (pkg/client/test/mockclientapi) or by the in-memory fakes
+// (pkg/client/test/fakeclient).
+package api
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/event"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	clientdisp "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// ChannelClient is implemented by channel.Client
+type ChannelClient interface {
+	Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	QueryInto(request channel.Request, result interface{}, options ...channel.RequestOption) (channel.Response, error)
+	ExecuteInto(request channel.Request, result interface{}, options ...channel.RequestOption) (channel.Response, error)
+	QueryAtHeight(request channel.Request, height uint64, options ...channel.RequestOption) (channel.Response, error)
+	AwaitPeerHeight(peer fab.Peer, height uint64, options ...channel.RequestOption) error
+	InvokeHandler(handler invoke.Handler, request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	SubmitEnvelope(envelope []byte, options ...channel.RequestOption) (channel.Response, error)
+	ImplicitCollectionName() string
+	QueryImplicitCollection(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	ExecuteImplicitCollection(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	NewPageIterator(request channel.Request, pageSize int32, decoder channel.PageDecoder, options ...channel.RequestOption) *channel.PageIterator
+	VerifyPrivateData(request channel.Request, value []byte, options ...channel.RequestOption) error
+	RegisterChaincodeEvent(chainCodeID string, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error)
+	UnregisterChaincodeEvent(registration fab.Registration)
+}
+
+// ResMgmtClient is implemented by resmgmt.Client
+type ResMgmtClient interface {
+	JoinChannel(channelID string, options ...resmgmt.RequestOption) error
+	InstallCC(req resmgmt.InstallCCRequest, options ...resmgmt.RequestOption) ([]resmgmt.InstallCCResponse, error)
+	InstantiateCC(channelID string, req resmgmt.InstantiateCCRequest, options ...resmgmt.RequestOption) (resmgmt.InstantiateCCResponse, error)
+	UpgradeCC(channelID string, req resmgmt.UpgradeCCRequest, options ...resmgmt.RequestOption) (resmgmt.UpgradeCCResponse, error)
+	QueryInstalledChaincodes(options ...resmgmt.RequestOption) (*pb.ChaincodeQueryResponse, error)
+	QueryInstantiatedChaincodes(channelID string, options ...resmgmt.RequestOption) (*pb.ChaincodeQueryResponse, error)
+	QueryChannels(options ...resmgmt.RequestOption) (*pb.ChannelQueryResponse, error)
+	LifecycleInstallCC(req resmgmt.LifecycleInstallCCRequest, options ...resmgmt.RequestOption) ([]resmgmt.LifecycleInstallCCResponse, error)
+	LifecycleQueryInstalledCC(options ...resmgmt.RequestOption) ([]resmgmt.LifecycleInstalledCC, error)
+	LifecycleVerifyInstalledCC(packageID string, options ...resmgmt.RequestOption) error
+	LifecycleApproveCC(channelID string, req resmgmt.LifecycleApproveCCRequest, options ...resmgmt.RequestOption) (fab.TransactionID, error)
+	LifecycleCommitCC(channelID string, def resmgmt.LifecycleCCDefinition, options ...resmgmt.RequestOption) (fab.TransactionID, error)
+	LifecycleQueryApprovedCC(channelID string, req resmgmt.LifecycleQueryApprovedCCRequest, options ...resmgmt.RequestOption) (resmgmt.LifecycleApprovedCC, error)
+	LifecycleQueryCommittedCC(channelID string, req resmgmt.LifecycleQueryCommittedCCRequest, options ...resmgmt.RequestOption) ([]resmgmt.LifecycleCommittedCC, error)
+	LifecycleCheckCCCommitReadiness(channelID string, def resmgmt.LifecycleCCDefinition, options ...resmgmt.RequestOption) (map[string]bool, error)
+	LifecycleQueryApprovalMatrix(channelID string, def resmgmt.LifecycleCCDefinition, options ...resmgmt.RequestOption) (resmgmt.LifecycleApprovalMatrix, error)
+	SaveChannel(req resmgmt.SaveChannelRequest, options ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error)
+	BootstrapChannel(req resmgmt.BootstrapChannelRequest, options ...resmgmt.RequestOption) (resmgmt.BootstrapChannelResponse, error)
+	WaitForPeerCatchUp(req resmgmt.PeerCatchUpRequest, options ...resmgmt.RequestOption) ([]resmgmt.PeerCatchUpStatus, error)
+	QueryConfigFromOrderer(channelID string, options ...resmgmt.RequestOption) (fab.ChannelCfg, error)
+	QueryConfigBlockFromOrderer(channelID string, options ...resmgmt.RequestOption) (*common.Block, error)
+	QueryBlockFromOrderer(channelID string, blockNumber uint64, options ...resmgmt.RequestOption) (*common.Block, error)
+	QueryNewestBlockFromOrderer(channelID string, options ...resmgmt.RequestOption) (*common.Block, error)
+	QueryGenesisBlockFromOrderer(channelID string, options ...resmgmt.RequestOption) (*common.Block, error)
+	QueryDecodedConfigFromOrderer(channelID string, options ...resmgmt.RequestOption) (*configtx.Config, error)
+	UpdateChannelConfig(req resmgmt.UpdateChannelConfigRequest, options ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error)
+	AddConsenter(req resmgmt.ConsenterRequest, options ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error)
+	RemoveConsenter(req resmgmt.ConsenterRequest, options ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error)
+	UpdateAnchorPeers(channelID, org string, anchorPeers []configtx.AnchorPeer, options ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error)
+	UpdateRevocationList(req resmgmt.RevocationListRequest, options ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error)
+	SubmitSnapshotRequest(channelID string, blockNumber uint64, options ...resmgmt.RequestOption) error
+	CancelSnapshotRequest(channelID string, blockNumber uint64, options ...resmgmt.RequestOption) error
+	QueryPendingSnapshotRequests(channelID string, options ...resmgmt.RequestOption) ([]uint64, error)
+	QueryLogSpec(options ...resmgmt.RequestOption) ([]resmgmt.LogSpecResponse, error)
+	SetLogSpec(spec string, options ...resmgmt.RequestOption) error
+	QueryOrdererLogSpec(options ...resmgmt.RequestOption) ([]resmgmt.LogSpecResponse, error)
+	SetOrdererLogSpec(spec string, options ...resmgmt.RequestOption) error
+}
+
+// LedgerClient is implemented by ledger.Client
+type LedgerClient interface {
+	QueryInfo(options ...ledger.RequestOption) (*fab.BlockchainInfoResponse, error)
+	QueryBlockByHash(blockHash []byte, options ...ledger.RequestOption) (*common.Block, error)
+	QueryBlockByTxID(txID fab.TransactionID, options ...ledger.RequestOption) (*common.Block, error)
+	QueryBlock(blockNumber uint64, options ...ledger.RequestOption) (*common.Block, error)
+	QueryTransaction(transactionID fab.TransactionID, options ...ledger.RequestOption) (*pb.ProcessedTransaction, error)
+	QueryConfig(options ...ledger.RequestOption) (fab.ChannelCfg, error)
+	QueryMissingPrivateData(req ledger.MissingPrivateDataRequest, options ...ledger.RequestOption) ([]ledger.MissingPrivateData, error)
+}
+
+// EventClient is implemented by event.Client
+type EventClient interface {
+	RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error)
+	RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error)
+	RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error)
+	RegisterDecodedChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *event.DecodedCCEvent, error)
+	RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error)
+	Unregister(reg fab.Registration)
+	StreamState() (*clientdisp.StreamState, error)
+}
+
+var _ ChannelClient = (*channel.Client)(nil)
+var _ ResMgmtClient = (*resmgmt.Client)(nil)
+var _ LedgerClient = (*ledger.Client)(nil)
+var _ EventClient = (*event.Client)(nil)
@@ -0,0 +1,41 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/event"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/test/mockclientapi"
+)
+
+var _ ChannelClient = (*mockclientapi.MockChannelClient)(nil)
+var _ ResMgmtClient = (*mockclientapi.MockResMgmtClient)(nil)
+var _ LedgerClient = (*mockclientapi.MockLedgerClient)(nil)
+var _ EventClient = (*mockclientapi.MockEventClient)(nil)
+
+// TestInterfacesCoverClients ensures that the interfaces are kept in sync with the clients, i.e. that
+// every exported method of a client is also part of its interface (and therefore of the mocks)
+func TestInterfacesCoverClients(t *testing.T) {
+	assertCovers(t, reflect.TypeOf((*ChannelClient)(nil)).Elem(), reflect.TypeOf((*channel.Client)(nil)))
+	assertCovers(t, reflect.TypeOf((*ResMgmtClient)(nil)).Elem(), reflect.TypeOf((*resmgmt.Client)(nil)))
+	assertCovers(t, reflect.TypeOf((*LedgerClient)(nil)).Elem(), reflect.TypeOf((*ledger.Client)(nil)))
+	assertCovers(t, reflect.TypeOf((*EventClient)(nil)).Elem(), reflect.TypeOf((*event.Client)(nil)))
+}
+
+func assertCovers(t *testing.T, iface reflect.Type, client reflect.Type) {
+	for i := 0; i < client.NumMethod(); i++ {
+		method := client.Method(i)
+		if _, ok := iface.MethodByName(method.Name); !ok {
+			t.Errorf("method %s of %s is missing from interface %s", method.Name, client, iface.Name())
+		}
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/inspect"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -28,6 +30,16 @@ type requestOptions struct {
 	Timeouts      map[fab.TimeoutType]time.Duration //timeout options for channel client operations
 	ParentContext reqContext.Context                //parent grpc context for channel client operations (query, execute, invokehandler)
 	CCFilter      invoke.CCFilter
+
+	ProposalResponseValidator invoke.ProposalResponseValidator
+	SimulationOnly            bool
+	IncludeRWSets             bool
+	IncludeWireArtifacts      bool
+	PeerRole                  *filter.EndpointType
+	EndorsingOrgs             []string
+	ResponseDecoder           func(payload []byte, result interface{}) error
+	HedgeDelay                time.Duration
+	MinBlockHeight            uint64
 }
 
 // RequestOption func for each Opts argument
@@ -49,6 +61,10 @@ type Request struct {
 	// The invoked chaincode (specified by ChaincodeID) may optionally be added to the invocation
 	// chain along with any collections, otherwise it may be omitted.
 	InvocationChain []*fab.ChaincodeCall
+
+	// IsInit must be set when invoking the chaincode's initialization function if the chaincode
+	// was committed with the init required flag (Fabric 2.0 chaincode lifecycle)
+	IsInit bool
 }
 
 //Response contains response parameters for query and execute an invocation transaction
@@ -59,6 +75,17 @@ type Response struct {
 	TxValidationCode pb.TxValidationCode
 	ChaincodeStatus  int32
 	Payload          []byte
+	// RWSets contains the read-write sets produced by the endorsers, including the hashed read-write sets of
+	// private data collections (only set if requested with WithRWSets or in simulation-only mode)
+	RWSets []inspect.NsRWSet
+	// SignedProposal is the marshalled pb.SignedProposal which was sent to the endorsers and Envelope is the
+	// marshalled common.Envelope which was sent to the orderer (only set if requested with WithWireArtifacts)
+	SignedProposal []byte
+	Envelope       []byte
+	// Orderer is the URL of the orderer which accepted the transaction
+	Orderer string
+	// BlockNumber is the number of the block in which the transaction was committed (only set by Execute)
+	BlockNumber uint64
 }
 
 //WithTargets allows overriding of the target peers for the request
@@ -154,3 +181,121 @@ func WithChaincodeFilter(ccFilter invoke.CCFilter) RequestOption {
 		return nil
 	}
 }
+
+// WithProposalResponseValidator specifies a function that is given the endorsement responses
+// before the transaction is submitted to the orderer. If the validator returns an error then
+// the transaction is not submitted and the error is returned to the caller.
+func WithProposalResponseValidator(validator invoke.ProposalResponseValidator) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.ProposalResponseValidator = validator
+		return nil
+	}
+}
+
+// WithSimulationOnly performs the selection of endorsers and the endorsement of an Execute request but
+// doesn't submit the transaction to the orderer. The response contains the proposal responses and the
+// read-write sets of the simulation; its TxValidationCode is NOT_VALIDATED. The proposal response
+// validator (if any) is still invoked.
+func WithSimulationOnly() RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.SimulationOnly = true
+		return nil
+	}
+}
+
+// WithRWSets includes the read-write sets of the endorsement (public and private hashed) in the response
+// of Query or Execute, e.g. for implementing optimistic concurrency strategies or predicting conflicts
+func WithRWSets() RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.IncludeRWSets = true
+		return nil
+	}
+}
+
+// WithPeerRole selects the endorsers by the given role of the channel peers in the configuration
+// (e.g. filter.ChaincodeQuery for dedicated query peers) instead of the default role of the request,
+// which is filter.ChaincodeQuery for Query and filter.EndorsingPeer for Execute. If a target filter is
+// also specified then a peer must be accepted by both. The role is ignored if targets are specified.
+func WithPeerRole(role filter.EndpointType) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.PeerRole = &role
+		return nil
+	}
+}
+
+// WithEndorsingOrgs restricts the endorsers to the peers of the given organizations (MSP IDs). The
+// selection service computes a combination of endorsers which satisfies the endorsement policy within
+// these organizations; if none exists then the request fails without retrying. The organizations are
+// ignored if targets are specified.
+func WithEndorsingOrgs(mspIDs ...string) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.EndorsingOrgs = mspIDs
+		return nil
+	}
+}
+
+// peerRole returns the role specified with WithPeerRole or else the given default role
+func (o *requestOptions) peerRole(defaultRole filter.EndpointType) filter.EndpointType {
+	if o.PeerRole != nil {
+		return *o.PeerRole
+	}
+	return defaultRole
+}
+
+// WithWireArtifacts includes the exact signed proposal and transaction envelope bytes which were sent to
+// the endorsers and the orderer in the response (also if the request fails after they were sent), so that
+// they may be archived. If the request was retried then the artifacts of the last attempt are returned.
+func WithWireArtifacts() RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.IncludeWireArtifacts = true
+		return nil
+	}
+}
+
+// WithHedging hedges queries: if the primary peer hasn't responded after the given delay then the
+// query is also sent to a backup peer, and so on, and the first response is returned. If the targets
+// aren't specified then peers of the organizations of the selected endorsers are used as backups.
+// The option is ignored by Execute.
+func WithHedging(delay time.Duration) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if delay <= 0 {
+			return errors.New("hedge delay must be positive")
+		}
+		o.HedgeDelay = delay
+		return nil
+	}
+}
+
+// WithMinBlockHeight asserts that the query is answered by a peer whose ledger has reached the given height
+// (i.e. which has committed the blocks up to height-1). The ledger heights of the target peers are queried
+// concurrently and the most up-to-date peer is queried. The selected endorsers are considered along with the
+// other peers of their organizations; if none of the targets set with WithTargets has reached the height,
+// the other peers of their organizations are considered. Otherwise it waits until a peer reaches the height
+// (bounded by the request timeout). The option is ignored by Execute.
+func WithMinBlockHeight(height uint64) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.MinBlockHeight = height
+		return nil
+	}
+}
+
+// WithReadYourWrites asserts that the query is answered by a peer which has committed the block of the
+// given Execute response, so that the query observes the writes of the transaction (see WithMinBlockHeight)
+func WithReadYourWrites(response Response) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		height := response.LedgerHeight()
+		if height == 0 {
+			return errors.New("the response doesn't contain the block number of a committed transaction")
+		}
+		o.MinBlockHeight = height
+		return nil
+	}
+}
+
+// WithResponseDecoder sets the decoder which QueryInto uses to decode the response payload (JSONDecoder by default)
+func WithResponseDecoder(decoder func(payload []byte, result interface{}) error) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.ResponseDecoder = decoder
+		return nil
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
@@ -99,3 +100,81 @@ func TestTimeoutOptions(t *testing.T) {
 	assert.True(t, opts.Timeouts[fab.Query] == 45*time.Second, "timeout value by type didn't match with one supplied")
 
 }
+
+type rolesConfig struct {
+	*fcmocks.MockConfig
+	peers []fab.ChannelPeer
+}
+
+func (c *rolesConfig) ChannelPeers(name string) ([]fab.ChannelPeer, bool) {
+	return c.peers, true
+}
+
+func (c *rolesConfig) PeerConfig(nameOrURL string) (*fab.PeerConfig, bool) {
+	for _, p := range c.peers {
+		if p.URL == nameOrURL {
+			return &p.PeerConfig, true
+		}
+	}
+	return nil, false
+}
+
+func TestWithPeerRole(t *testing.T) {
+	ctx := setupMockTestContext("test", "Org1MSP")
+	ctx.SetEndpointConfig(&rolesConfig{
+		MockConfig: &fcmocks.MockConfig{},
+		peers: []fab.ChannelPeer{
+			{
+				PeerChannelConfig: fab.PeerChannelConfig{ChaincodeQuery: true},
+				NetworkPeer:       fab.NetworkPeer{PeerConfig: fab.PeerConfig{URL: "query.example.com"}},
+			},
+			{
+				PeerChannelConfig: fab.PeerChannelConfig{EndorsingPeer: true},
+				NetworkPeer:       fab.NetworkPeer{PeerConfig: fab.PeerConfig{URL: "endorser.example.com"}},
+			},
+		},
+	})
+	chCtx := fcmocks.NewMockChannelContext(ctx, "mychannel")
+
+	queryPeer := fcmocks.NewMockPeer("query", "query.example.com")
+	endorser := fcmocks.NewMockPeer("endorser", "endorser.example.com")
+
+	applyOpts := func(defaultRole filter.EndpointType, options ...RequestOption) requestOptions {
+		opts := requestOptions{}
+		for _, option := range append(options, addDefaultTargetFilter(chCtx, defaultRole)) {
+			assert.NoError(t, option(ctx, &opts))
+		}
+		return opts
+	}
+
+	// Default roles
+	opts := applyOpts(filter.ChaincodeQuery)
+	assert.True(t, opts.TargetFilter.Accept(queryPeer))
+	assert.False(t, opts.TargetFilter.Accept(endorser))
+
+	opts = applyOpts(filter.EndorsingPeer)
+	assert.False(t, opts.TargetFilter.Accept(queryPeer))
+	assert.True(t, opts.TargetFilter.Accept(endorser))
+
+	// Role overridden per call
+	opts = applyOpts(filter.ChaincodeQuery, WithPeerRole(filter.EndorsingPeer))
+	assert.False(t, opts.TargetFilter.Accept(queryPeer))
+	assert.True(t, opts.TargetFilter.Accept(endorser))
+
+	// Role combined with a target filter
+	opts = applyOpts(filter.ChaincodeQuery, WithPeerRole(filter.EndorsingPeer), WithTargetFilter(&urlFilter{url: "query.example.com"}))
+	assert.False(t, opts.TargetFilter.Accept(queryPeer))
+	assert.False(t, opts.TargetFilter.Accept(endorser))
+
+	// Target filter without role replaces the default role
+	opts = applyOpts(filter.ChaincodeQuery, WithTargetFilter(&urlFilter{url: "endorser.example.com"}))
+	assert.True(t, opts.TargetFilter.Accept(endorser))
+}
+
+type urlFilter struct {
+	url string
+}
+
+func (f *urlFilter) Accept(peer fab.Peer) bool {
+	return peer.URL() == f.url
+}
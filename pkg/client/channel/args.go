@@ -0,0 +1,172 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ArgEncoder encodes a Go value as a chaincode argument or transient value
+type ArgEncoder func(value interface{}) ([]byte, error)
+
+// JSONEncoder is the default ArgEncoder. Strings and byte slices are encoded as is (so that they
+// aren't quoted) and other values are encoded as JSON.
+func JSONEncoder(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "JSON marshal failed")
+		}
+		return b, nil
+	}
+}
+
+// NewRequest returns a request for the given chaincode function with the arguments encoded
+// with JSONEncoder, e.g. NewRequest("assetcc", "create", asset, 10)
+func NewRequest(chaincodeID, fcn string, args ...interface{}) (Request, error) {
+	encodedArgs, err := EncodeArgs(nil, args...)
+	if err != nil {
+		return Request{}, err
+	}
+	return Request{ChaincodeID: chaincodeID, Fcn: fcn, Args: encodedArgs}, nil
+}
+
+// EncodeArgs encodes Go values as chaincode arguments
+//  Parameters:
+//  encoder encodes each value (JSONEncoder if nil)
+//  values are the arguments
+//
+//  Returns:
+//  the arguments of a request
+func EncodeArgs(encoder ArgEncoder, values ...interface{}) ([][]byte, error) {
+	if encoder == nil {
+		encoder = JSONEncoder
+	}
+
+	args := make([][]byte, len(values))
+	for i, value := range values {
+		arg, err := encoder(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode argument %d", i)
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+// EncodeTransientMap encodes a struct or a map with string keys as the transient map of a request.
+// The keys of a struct are the names of its exported fields, or the names given by their json tags.
+// Fields tagged with "-" are skipped, as are empty fields tagged with omitempty.
+//  Parameters:
+//  encoder encodes each value (JSONEncoder if nil)
+//  value is the struct (or pointer to struct) or map
+//
+//  Returns:
+//  the transient map of a request
+func EncodeTransientMap(encoder ArgEncoder, value interface{}) (map[string][]byte, error) {
+	if encoder == nil {
+		encoder = JSONEncoder
+	}
+
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return nil, nil
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	transientMap := make(map[string][]byte)
+	add := func(key string, value interface{}) error {
+		b, err := encoder(value)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encode transient value [%s]", key)
+		}
+		transientMap[key] = b
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, errors.New("transient map keys must be strings")
+		}
+		for _, key := range v.MapKeys() {
+			if err := add(key.String(), v.MapIndex(key).Interface()); err != nil {
+				return nil, err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				// unexported
+				continue
+			}
+			key, omitEmpty := transientKey(field)
+			if key == "-" {
+				continue
+			}
+			fv := v.Field(i)
+			if omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			if err := add(key, fv.Interface()); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, errors.Errorf("unsupported transient data type [%s]: expecting a struct or a map", v.Type())
+	}
+	return transientMap, nil
+}
+
+// transientKey returns the key of a struct field and whether it is omitted when empty
+func transientKey(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "-", false
+	}
+
+	parts := strings.Split(tag, ",")
+	key := parts[0]
+	if key == "" {
+		key = field.Name
+	}
+
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			return key, true
+		}
+	}
+	return key, false
+}
+
+// isEmptyValue returns true if the value is empty as defined by the omitempty option of encoding/json
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	default:
+		return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testAsset struct {
+	ID    string   `json:"id"`
+	Owner string   `json:"owner,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	Size  int
+	Notes string `json:"-"`
+	color string
+}
+
+func TestNewRequest(t *testing.T) {
+	request, err := NewRequest("assetcc", "create", testAsset{ID: "asset1", Size: 5}, "blue", 10, true, []byte("raw"))
+	require.NoError(t, err)
+	assert.Equal(t, "assetcc", request.ChaincodeID)
+	assert.Equal(t, "create", request.Fcn)
+	assert.Equal(t, [][]byte{[]byte(`{"id":"asset1","Size":5}`), []byte("blue"), []byte("10"), []byte("true"), []byte("raw")}, request.Args)
+
+	_, err = NewRequest("assetcc", "create", make(chan int))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to encode argument 0")
+}
+
+func TestEncodeArgs(t *testing.T) {
+	upper := func(value interface{}) ([]byte, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("expecting string")
+		}
+		return []byte(strings.ToUpper(s)), nil
+	}
+
+	args, err := EncodeArgs(upper, "a", "b")
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("A"), []byte("B")}, args)
+
+	_, err = EncodeArgs(upper, "a", 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to encode argument 1")
+
+	args, err = EncodeArgs(nil)
+	require.NoError(t, err)
+	assert.Empty(t, args)
+}
+
+func TestEncodeTransientMap(t *testing.T) {
+	transientMap, err := EncodeTransientMap(nil, &testAsset{ID: "asset1", Notes: "secret", color: "blue"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"id": []byte("asset1"), "Size": []byte("0")}, transientMap)
+
+	transientMap, err = EncodeTransientMap(nil, testAsset{ID: "asset1", Owner: "tom", Tags: []string{"a"}})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("tom"), transientMap["owner"])
+	assert.Equal(t, []byte(`["a"]`), transientMap["tags"])
+
+	transientMap, err = EncodeTransientMap(nil, map[string]interface{}{"price": 10, "asset": testAsset{ID: "asset1"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"price": []byte("10"), "asset": []byte(`{"id":"asset1","Size":0}`)}, transientMap)
+
+	transientMap, err = EncodeTransientMap(nil, (*testAsset)(nil))
+	require.NoError(t, err)
+	assert.Nil(t, transientMap)
+
+	transientMap, err = EncodeTransientMap(nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, transientMap)
+
+	_, err = EncodeTransientMap(nil, map[int]string{1: "a"})
+	assert.Error(t, err)
+
+	_, err = EncodeTransientMap(nil, "value")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported transient data type")
+
+	_, err = EncodeTransientMap(nil, map[string]interface{}{"ch": make(chan int)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to encode transient value [ch]")
+}
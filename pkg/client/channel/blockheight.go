@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	reqContext "context"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	fabchannel "github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
+	"github.com/pkg/errors"
+)
+
+// LedgerHeight returns the ledger height which a peer must reach in order to observe the writes of the
+// transaction of an Execute response, i.e. the number of the block of the transaction plus one (0 if the
+// transaction wasn't committed)
+func (r Response) LedgerHeight() uint64 {
+	if r.BlockNumber == 0 {
+		return 0
+	}
+	return r.BlockNumber + 1
+}
+
+// QueryAtHeight queries the chaincode on a peer whose ledger has reached the given height, e.g. the
+// height returned by the LedgerHeight of a previous Execute response (see WithMinBlockHeight)
+//  Parameters:
+//  request holds info about mandatory chaincode ID and function
+//  height is the minimum ledger height of the peer which answers the query
+//  options holds optional request options
+//
+//  Returns:
+//  the proposal responses from peer(s)
+func (cc *Client) QueryAtHeight(request Request, height uint64, options ...RequestOption) (Response, error) {
+	return cc.Query(request, append(options, WithMinBlockHeight(height))...)
+}
+
+// AwaitPeerHeight waits until the ledger of the peer reaches the given height, e.g. the height returned by
+// the LedgerHeight of a previous Execute response. The ledger height of the peer is polled until it reaches
+// the height or the request timeout (see WithTimeout with fab.Execute) or the parent context expires.
+//  Parameters:
+//  peer is the peer to wait for
+//  height is the ledger height to wait for
+//  options holds optional request options
+//
+//  Returns:
+//  an error if the peer didn't reach the height
+func (cc *Client) AwaitPeerHeight(peer fab.Peer, height uint64, options ...RequestOption) error {
+	if peer == nil {
+		return errors.New("peer is required")
+	}
+
+	opts, err := cc.prepareOptsFromOptions(cc.context, options...)
+	if err != nil {
+		return err
+	}
+
+	reqCtx, cancel := cc.createReqContext(&opts)
+	defer cancel()
+
+	requestContext := &invoke.RequestContext{
+		Opts: invoke.Opts{Targets: []fab.Peer{peer}, MinBlockHeight: height},
+		Ctx:  reqCtx,
+	}
+	invoke.NewBlockHeightHandler().Handle(requestContext, &invoke.ClientContext{BlockHeight: cc.blockHeight})
+	return requestContext.Error
+}
+
+// blockHeight queries the ledger height of a peer (used to assert the minimum block height of a query)
+func (cc *Client) blockHeight(reqCtx reqContext.Context, peer fab.Peer) (uint64, error) {
+	ledger, err := fabchannel.NewLedger(cc.context.ChannelID())
+	if err != nil {
+		return 0, err
+	}
+
+	peerReqCtx, cancel := contextImpl.NewRequest(cc.context, contextImpl.WithTimeoutType(fab.PeerResponse), contextImpl.WithParent(reqCtx))
+	defer cancel()
+
+	responses, err := ledger.QueryInfo(peerReqCtx, []fab.ProposalProcessor{peer}, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(responses) == 0 || responses[0].BCI == nil {
+		return 0, errors.New("no blockchain info returned")
+	}
+	return responses[0].BCI.Height, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseLedgerHeight(t *testing.T) {
+	assert.Equal(t, uint64(0), Response{}.LedgerHeight(), "expecting no height for an uncommitted transaction")
+	assert.Equal(t, uint64(8), Response{BlockNumber: 7}.LedgerHeight())
+}
+
+func TestQueryAtHeight(t *testing.T) {
+	peer1 := newHeightPeer("peer1:7051", 5, t)
+	peer2 := newHeightPeer("peer2:7051", 7, t)
+
+	fabCtx := setupCustomTestContext(t, txnmocks.NewMockSelectionService(nil, peer1), txnmocks.NewMockDiscoveryService(nil, peer1, peer2), nil)
+	chClient, err := New(createChannelContext(fabCtx, channelID))
+	require.NoError(t, err)
+
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+
+	response, err := chClient.QueryAtHeight(request, 6)
+	require.NoError(t, err)
+	require.Len(t, response.Responses, 1)
+	assert.Equal(t, peer2.URL(), response.Responses[0].Endorser, "expecting the query to be routed to the peer which reached the height")
+
+	response, err = chClient.Query(request, WithReadYourWrites(Response{BlockNumber: 4}))
+	require.NoError(t, err)
+	require.Len(t, response.Responses, 1)
+	assert.Equal(t, peer2.URL(), response.Responses[0].Endorser, "expecting the most up-to-date peer to be queried")
+
+	_, err = chClient.QueryAtHeight(request, 10, WithTimeout(fab.Execute, 100*time.Millisecond))
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.Timeout.ToInt32(), s.Code, "expecting timeout while waiting for a peer to reach the height")
+}
+
+func TestAwaitPeerHeight(t *testing.T) {
+	peer1 := newHeightPeer("peer1:7051", 5, t)
+	chClient := setupChannelClient([]fab.Peer{peer1}, t)
+
+	require.NoError(t, chClient.AwaitPeerHeight(peer1, 5))
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		peer1.setHeight(6, t)
+	}()
+	require.NoError(t, chClient.AwaitPeerHeight(peer1, 6, WithTimeout(fab.Execute, 5*time.Second)))
+
+	err := chClient.AwaitPeerHeight(peer1, 10, WithTimeout(fab.Execute, 100*time.Millisecond))
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.Timeout.ToInt32(), s.Code)
+
+	assert.Error(t, chClient.AwaitPeerHeight(nil, 1))
+}
+
+// heightPeer is a mock peer which returns the given ledger height as its blockchain info
+type heightPeer struct {
+	*fcmocks.MockPeer
+}
+
+func newHeightPeer(url string, height uint64, t *testing.T) *heightPeer {
+	p := &heightPeer{MockPeer: fcmocks.NewMockPeer(url, url)}
+	p.setHeight(height, t)
+	return p
+}
+
+func (p *heightPeer) setHeight(height uint64, t *testing.T) {
+	payload, err := proto.Marshal(&common.BlockchainInfo{Height: height})
+	require.NoError(t, err)
+
+	p.RWLock.Lock()
+	defer p.RWLock.Unlock()
+	p.Payload = payload
+}
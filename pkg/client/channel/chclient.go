@@ -6,7 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 
 // Package channel enables access to a channel on a Fabric network. A channel client instance provides a handler to interact with peers on specified channel.
 // Channel client can query chaincode, execute chaincode and register/unregister for chaincode events on specific channel.
-// An application that requires interaction with multiple channels should create a separate instance of the channel client for each channel.
+// An application that requires interaction with multiple channels should create a separate instance of the channel client for each channel
+// or use a multi-channel client, which routes requests to the channel client of the given channel.
 //
 //  Basic Flow:
 //  1) Prepare channel client context
@@ -17,6 +18,7 @@ package channel
 
 import (
 	reqContext "context"
+	"strings"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
@@ -27,6 +29,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 	"github.com/pkg/errors"
 )
 
@@ -40,12 +43,23 @@ type Client struct {
 	membership   fab.ChannelMembership
 	eventService fab.EventService
 	greylist     *greylist.Filter
+	defaultOpts  []RequestOption
 	clientTally  // nolint
 }
 
 // ClientOption describes a functional parameter for the New constructor
 type ClientOption func(*Client) error
 
+// WithDefaultOptions sets request options (e.g. retry options, timeouts or a target filter) which are
+// applied to every request of the client before the options of the request. The options of a request
+// therefore override the defaults, except for timeouts, which are only overridden per timeout type.
+func WithDefaultOptions(options ...RequestOption) ClientOption {
+	return func(cc *Client) error {
+		cc.defaultOpts = append(cc.defaultOpts, options...)
+		return nil
+	}
+}
+
 // New returns a Client instance. Channel client can query chaincode, execute chaincode and register/unregister for chaincode events on specific channel.
 func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client, error) {
 
@@ -91,6 +105,7 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 //  the proposal responses from peer(s)
 func (cc *Client) Query(request Request, options ...RequestOption) (Response, error) {
 
+	options = append(options, cc.addTimeoutOverrides(request.ChaincodeID))
 	options = append(options, addDefaultTimeout(fab.Query))
 	options = append(options, addDefaultTargetFilter(cc.context, filter.ChaincodeQuery))
 
@@ -105,22 +120,41 @@ func (cc *Client) Query(request Request, options ...RequestOption) (Response, er
 //  Returns:
 //  the proposal responses from peer(s)
 func (cc *Client) Execute(request Request, options ...RequestOption) (Response, error) {
+	options = append(options, cc.addTimeoutOverrides(request.ChaincodeID))
 	options = append(options, addDefaultTimeout(fab.Execute))
 	options = append(options, addDefaultTargetFilter(cc.context, filter.EndorsingPeer))
 
 	return callExecute(cc, request, options...)
 }
 
-// addDefaultTargetFilter adds default target filter if target filter is not specified
+// addDefaultTargetFilter adds default target filter if target filter is not specified. If a peer role
+// is specified then the target filter (if any) is combined with the filter for the role.
 func addDefaultTargetFilter(chCtx context.Channel, ft filter.EndpointType) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
-		if len(o.Targets) == 0 && o.TargetFilter == nil {
-			return WithTargetFilter(filter.NewEndpointFilter(chCtx, ft))(ctx, o)
+		if len(o.Targets) > 0 {
+			return nil
+		}
+		if o.TargetFilter == nil {
+			return WithTargetFilter(filter.NewEndpointFilter(chCtx, o.peerRole(ft)))(ctx, o)
+		}
+		if o.PeerRole != nil {
+			return WithTargetFilter(&roleTargetFilter{filter: o.TargetFilter, roleFilter: filter.NewEndpointFilter(chCtx, *o.PeerRole)})(ctx, o)
 		}
 		return nil
 	}
 }
 
+// roleTargetFilter accepts the peers which are accepted by both the target filter and the role filter
+type roleTargetFilter struct {
+	filter     fab.TargetFilter
+	roleFilter fab.TargetFilter
+}
+
+// Accept returns true if the peer is accepted by both filters
+func (f *roleTargetFilter) Accept(peer fab.Peer) bool {
+	return f.filter.Accept(peer) && f.roleFilter.Accept(peer)
+}
+
 // addDefaultTimeout adds default timeout if timeout is not specified
 func addDefaultTimeout(tt fab.TimeoutType) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
@@ -131,6 +165,29 @@ func addDefaultTimeout(tt fab.TimeoutType) RequestOption {
 	}
 }
 
+// addTimeoutOverrides adds the timeouts which are overridden in the channel policies for the channel
+// and the given chaincode, unless the timeout is specified in the request options
+func (cc *Client) addTimeoutOverrides(ccID string) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		chConfig, ok := cc.context.EndpointConfig().ChannelConfig(cc.context.ChannelID())
+		if !ok || chConfig == nil {
+			return nil
+		}
+
+		policy := chConfig.Policies.Timeouts
+		for _, timeouts := range []map[fab.TimeoutType]time.Duration{policy.Operations, policy.Chaincodes[strings.ToLower(ccID)]} {
+			for tt := range timeouts {
+				if o.Timeouts[tt] == 0 {
+					if err := WithTimeout(tt, policy.Timeout(ccID, tt))(ctx, o); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}
+}
+
 // InvokeHandler invokes handler using request and optional request options provided
 //  Parameters:
 //  handler to be invoked
@@ -149,6 +206,12 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 	reqCtx, cancel := cc.createReqContext(&txnOpts)
 	defer cancel()
 
+	var artifacts *txn.Artifacts
+	if txnOpts.IncludeWireArtifacts {
+		artifacts = &txn.Artifacts{}
+		reqCtx = txn.WithArtifacts(reqCtx, artifacts)
+	}
+
 	//Prepare context objects for handler
 	requestContext, clientContext, err := cc.prepareHandlerContexts(reqCtx, request, txnOpts)
 	if err != nil {
@@ -157,6 +220,7 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 
 	invoker := retry.NewInvoker(
 		requestContext.RetryHandler,
+		retry.WithContext(reqCtx),
 		retry.WithBeforeRetry(
 			func(err error) {
 				if requestContext.Opts.BeforeRetry != nil {
@@ -169,17 +233,26 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 				requestContext.Opts.Targets = txnOpts.Targets
 				requestContext.Error = nil
 				requestContext.Response = invoke.Response{}
+				if artifacts != nil {
+					artifacts.Reset()
+				}
 			},
 		),
 	)
 
 	complete := make(chan bool, 1)
 	go func() {
-		_, _ = invoker.Invoke( // nolint: gas
+		_, err := invoker.Invoke(
 			func() (interface{}, error) {
 				handler.Handle(requestContext, clientContext)
 				return nil, requestContext.Error
 			})
+		// The invoker may return a different error than the handler (e.g. when the deadline would be exceeded by a retry)
+		requestContext.Error = err
+		if artifacts != nil {
+			requestContext.Response.SignedProposal = artifacts.SignedProposal()
+			requestContext.Response.Envelope = artifacts.Envelope()
+		}
 		complete <- true
 	}()
 	select {
@@ -240,6 +313,9 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 		if o.TargetFilter != nil && !o.TargetFilter.Accept(peer) {
 			return false
 		}
+		if len(o.EndorsingOrgs) > 0 && !containsMSPID(o.EndorsingOrgs, peer.MSPID()) {
+			return false
+		}
 		return true
 	}
 
@@ -249,6 +325,7 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 		Membership:   cc.membership,
 		Transactor:   transactor,
 		EventService: cc.eventService,
+		BlockHeight:  cc.blockHeight,
 	}
 
 	requestContext := &invoke.RequestContext{
@@ -263,10 +340,19 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 	return requestContext, clientContext, nil
 }
 
-//prepareOptsFromOptions Reads apitxn.Opts from Option array
+func containsMSPID(mspIDs []string, mspID string) bool {
+	for _, id := range mspIDs {
+		if id == mspID {
+			return true
+		}
+	}
+	return false
+}
+
+//prepareOptsFromOptions Reads apitxn.Opts from the default options of the client and the Option array
 func (cc *Client) prepareOptsFromOptions(ctx context.Client, options ...RequestOption) (requestOptions, error) {
 	txnOpts := requestOptions{}
-	for _, option := range options {
+	for _, option := range append(cc.defaultOpts[:len(cc.defaultOpts):len(cc.defaultOpts)], options...) {
 		err := option(ctx, &txnOpts)
 		if err != nil {
 			return txnOpts, errors.WithMessage(err, "Failed to read opts")
@@ -158,6 +158,24 @@ func TestQueryWithOptAsync(t *testing.T) {
 	}
 }
 
+func TestExecuteWithEndorsingOrgs(t *testing.T) {
+	org1Peer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	org2Peer := fcmocks.NewMockPeer("Peer2", "http://peer2.com")
+	org2Peer.MockMSP = "Org2MSP"
+
+	chClient := setupChannelClient([]fab.Peer{org1Peer, org2Peer}, t)
+
+	_, err := chClient.Execute(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}},
+		WithEndorsingOrgs("Org2MSP"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, org1Peer.ProcessProposalCalls, "expecting peer of Org1MSP not to be selected")
+	assert.Equal(t, 1, org2Peer.ProcessProposalCalls)
+
+	_, err = chClient.Execute(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}},
+		WithEndorsingOrgs("Org3MSP"))
+	assert.Error(t, err, "expecting error since no peers of Org3MSP are available")
+}
+
 func TestQueryWithOptTarget(t *testing.T) {
 	chClient := setupChannelClient(nil, t)
 
@@ -229,6 +247,22 @@ func TestExecuteTx(t *testing.T) {
 
 }
 
+func TestExecuteTxSimulationOnly(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("test1")
+	testPeer1.SetRwSets(fcmocks.NewRwSet("testCC"))
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+
+	response, err := chClient.Execute(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}, WithSimulationOnly())
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("test1"), response.Payload)
+	assert.Len(t, response.Responses, 1)
+	assert.Equal(t, pb.TxValidationCode_NOT_VALIDATED, response.TxValidationCode)
+	if assert.Len(t, response.RWSets, 1) {
+		assert.Equal(t, "testCC", response.RWSets[0].Namespace)
+	}
+}
+
 type customHandler struct {
 	expectedPayload []byte
 }
@@ -631,3 +665,100 @@ func createClientContext(client context.Client) context.ClientProvider {
 		return client, nil
 	}
 }
+
+func TestTimeoutOverrides(t *testing.T) {
+	peer1 := fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: 200}
+	chClient := setupChannelClient([]fab.Peer{&peer1}, t)
+
+	config, ok := chClient.context.EndpointConfig().(*fcmocks.MockConfig)
+	if !ok {
+		t.Fatal("expecting mock config")
+	}
+	config.ChannelTimeouts = fab.TimeoutPolicy{
+		Operations: map[fab.TimeoutType]time.Duration{fab.Query: 20 * time.Second, fab.PeerResponse: 10 * time.Second},
+		Chaincodes: map[string]map[fab.TimeoutType]time.Duration{"slowcc": {fab.Query: time.Minute}},
+	}
+
+	opts, err := chClient.prepareOptsFromOptions(chClient.context, chClient.addTimeoutOverrides("examplecc"), addDefaultTimeout(fab.Query))
+	assert.NoError(t, err)
+	assert.Equal(t, 20*time.Second, opts.Timeouts[fab.Query], "expecting channel override")
+	assert.Equal(t, 10*time.Second, opts.Timeouts[fab.PeerResponse], "expecting channel override")
+
+	opts, err = chClient.prepareOptsFromOptions(chClient.context, chClient.addTimeoutOverrides("SlowCC"), addDefaultTimeout(fab.Query))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, opts.Timeouts[fab.Query], "expecting chaincode override")
+	assert.Equal(t, 10*time.Second, opts.Timeouts[fab.PeerResponse], "expecting channel override")
+
+	opts, err = chClient.prepareOptsFromOptions(chClient.context, WithTimeout(fab.Query, 5*time.Second), chClient.addTimeoutOverrides("slowcc"), addDefaultTimeout(fab.Query))
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, opts.Timeouts[fab.Query], "expecting request option to take precedence")
+
+	config.ChannelTimeouts = fab.TimeoutPolicy{}
+	opts, err = chClient.prepareOptsFromOptions(chClient.context, chClient.addTimeoutOverrides("slowcc"), addDefaultTimeout(fab.Query))
+	assert.NoError(t, err)
+	assert.Equal(t, config.Timeout(fab.Query), opts.Timeouts[fab.Query], "expecting client timeout")
+	assert.Equal(t, time.Duration(0), opts.Timeouts[fab.PeerResponse])
+}
+
+func TestDefaultOptions(t *testing.T) {
+	peer1 := fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: 500}
+	peer2 := fcmocks.MockPeer{MockName: "Peer2", MockURL: "http://peer2.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: 200}
+
+	fabCtx := setupCustomTestContext(t, txnmocks.NewMockSelectionService(nil, &peer1, &peer2), txnmocks.NewMockDiscoveryService(nil), nil)
+	chClient, err := New(createChannelContext(fabCtx, channelID),
+		WithDefaultOptions(WithTimeout(fab.Query, 3*time.Second), WithTimeout(fab.PeerResponse, 2*time.Second)),
+		WithDefaultOptions(WithRetry(retry.Opts{Attempts: 2}), WithTargetFilter(&urlFilter{url: "http://peer2.com"})),
+	)
+	assert.NoError(t, err)
+
+	opts, err := chClient.prepareOptsFromOptions(chClient.context, addDefaultTimeout(fab.Query))
+	assert.NoError(t, err)
+	assert.Equal(t, 3*time.Second, opts.Timeouts[fab.Query], "expecting default option to take precedence over config")
+	assert.Equal(t, 2*time.Second, opts.Timeouts[fab.PeerResponse])
+	assert.Equal(t, 2, opts.Retry.Attempts)
+
+	opts, err = chClient.prepareOptsFromOptions(chClient.context, WithTimeout(fab.Query, time.Second), WithRetry(retry.Opts{}))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, opts.Timeouts[fab.Query], "expecting request option to take precedence")
+	assert.Equal(t, 2*time.Second, opts.Timeouts[fab.PeerResponse], "expecting default option to be kept")
+	assert.Equal(t, 0, opts.Retry.Attempts)
+
+	_, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}})
+	assert.NoError(t, err, "expecting failing peer to be excluded by default target filter")
+
+	_, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}, WithTargets(&peer1))
+	assert.Error(t, err, "expecting request targets to override default target filter")
+}
+
+func TestQueryWithHedging(t *testing.T) {
+	peer1 := fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: 200, Error: errors.New("peer1 unavailable")}
+	peer2 := fcmocks.MockPeer{MockName: "Peer2", MockURL: "http://peer2.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: 200, Payload: []byte("value")}
+	chClient := setupChannelClient([]fab.Peer{&peer1, &peer2}, t)
+
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+
+	_, err := chClient.Query(request)
+	assert.Error(t, err, "expecting query to fail without hedging")
+
+	response, err := chClient.Query(request, WithHedging(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), response.Payload)
+
+	_, err = chClient.Query(request, WithHedging(0))
+	assert.Error(t, err, "expecting invalid hedge delay to be rejected")
+}
+
+func TestMinBlockHeightOptions(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+
+	opts, err := chClient.prepareOptsFromOptions(chClient.context, WithMinBlockHeight(10))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), opts.MinBlockHeight)
+
+	opts, err = chClient.prepareOptsFromOptions(chClient.context, WithReadYourWrites(Response{BlockNumber: 10}))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), opts.MinBlockHeight, "expecting the height after the block of the transaction")
+
+	_, err = chClient.prepareOptsFromOptions(chClient.context, WithReadYourWrites(Response{}))
+	assert.Error(t, err, "expecting error for a response without block number")
+}
@@ -0,0 +1,351 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package chunk splits large private payloads into chunks that are stored across multiple
+// transactions (one chunk per transaction, passed in the transient map) and reassembles
+// them on read. An integrity manifest, containing the hash of every chunk and of the
+// whole payload, is stored along with the chunks so that the payload can be verified
+// when it is read back.
+//
+// The chaincode must cooperate by implementing the following convention:
+//  - The put function accepts the arguments [collection, key] and stores the value
+//    of the "value" transient field in the given collection under the given key.
+//  - The get function accepts the arguments [collection, key] and returns the value
+//    stored in the given collection under the given key.
+//
+//  Basic Flow:
+//  1) Create a chunk store using a channel client
+//  2) Put a payload
+//  3) Get the payload
+package chunk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultChunkSize is the default maximum size of a chunk in bytes
+	DefaultChunkSize = 512 * 1024
+
+	// DefaultMaxPayloadSize is the default maximum size in bytes of a payload that is reassembled
+	DefaultMaxPayloadSize = 64 * 1024 * 1024
+
+	// ValueField is the transient field containing the value to store
+	ValueField = "value"
+
+	manifestVersion = 1
+)
+
+// Invoker is the subset of the channel client used by the chunk store
+type Invoker interface {
+	Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+}
+
+// Manifest describes a payload that was split into chunks
+type Manifest struct {
+	Version   int      `json:"version"`
+	ID        string   `json:"id"`
+	Size      int      `json:"size"`
+	ChunkSize int      `json:"chunkSize"`
+	Hash      []byte   `json:"hash"`
+	Chunks    [][]byte `json:"chunks"`
+}
+
+// ChunkKey returns the key under which the chunk with the given index is stored
+func (m *Manifest) ChunkKey(index int) string {
+	return ChunkKey(m.ID, index)
+}
+
+// Validate checks that the manifest is consistent and that the size of the payload doesn't exceed
+// maxSize bytes, so that a corrupted or hostile manifest read from the ledger is rejected before
+// any chunk is retrieved or memory is allocated for the payload
+func (m *Manifest) Validate(maxSize int) error {
+	if m.Size < 0 {
+		return errors.Errorf("invalid payload size %d in manifest of [%s]", m.Size, m.ID)
+	}
+	if m.Size > maxSize {
+		return errors.Errorf("payload size %d of [%s] exceeds the maximum of %d bytes", m.Size, m.ID, maxSize)
+	}
+	if m.ChunkSize <= 0 {
+		return errors.Errorf("invalid chunk size %d in manifest of [%s]", m.ChunkSize, m.ID)
+	}
+	if expected := (m.Size + m.ChunkSize - 1) / m.ChunkSize; len(m.Chunks) != expected {
+		return errors.Errorf("manifest of [%s] has %d chunks but %d are expected for payload size %d", m.ID, len(m.Chunks), expected, m.Size)
+	}
+	return nil
+}
+
+// ChunkKey returns the key under which the chunk with the given index of the given payload ID is stored
+func ChunkKey(id string, index int) string {
+	return fmt.Sprintf("%s~chunk~%d", id, index)
+}
+
+// Split splits the given payload into chunks of (at most) chunkSize bytes and
+// returns the chunks along with the integrity manifest
+func Split(id string, payload []byte, chunkSize int) (*Manifest, [][]byte, error) {
+	if id == "" {
+		return nil, nil, errors.New("payload ID is required")
+	}
+	if chunkSize <= 0 {
+		return nil, nil, errors.New("chunk size must be greater than zero")
+	}
+
+	hash := sha256.Sum256(payload)
+	manifest := &Manifest{
+		Version:   manifestVersion,
+		ID:        id,
+		Size:      len(payload),
+		ChunkSize: chunkSize,
+		Hash:      hash[:],
+	}
+
+	var chunks [][]byte
+	for start := 0; start < len(payload); start += chunkSize {
+		end := start + chunkSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		chunk := payload[start:end]
+		chunkHash := sha256.Sum256(chunk)
+		manifest.Chunks = append(manifest.Chunks, chunkHash[:])
+		chunks = append(chunks, chunk)
+	}
+
+	return manifest, chunks, nil
+}
+
+// Assemble verifies the given chunks against the manifest and reassembles the payload
+// (the payload may not exceed DefaultMaxPayloadSize)
+func Assemble(manifest *Manifest, chunks [][]byte) ([]byte, error) {
+	return AssembleWithMaxSize(manifest, chunks, DefaultMaxPayloadSize)
+}
+
+// AssembleWithMaxSize verifies the given chunks against the manifest and reassembles the payload,
+// which may not exceed maxSize bytes
+func AssembleWithMaxSize(manifest *Manifest, chunks [][]byte, maxSize int) ([]byte, error) {
+	if manifest == nil {
+		return nil, errors.New("manifest is required")
+	}
+	if err := manifest.Validate(maxSize); err != nil {
+		return nil, err
+	}
+	if len(chunks) != len(manifest.Chunks) {
+		return nil, errors.Errorf("expecting %d chunks but got %d", len(manifest.Chunks), len(chunks))
+	}
+
+	size := 0
+	for _, chunk := range chunks {
+		size += len(chunk)
+	}
+	if size != manifest.Size {
+		return nil, errors.Errorf("the chunks of [%s] add up to %d bytes but the manifest declares %d", manifest.ID, size, manifest.Size)
+	}
+
+	payload := make([]byte, 0, manifest.Size)
+	for i, chunk := range chunks {
+		chunkHash := sha256.Sum256(chunk)
+		if !bytes.Equal(chunkHash[:], manifest.Chunks[i]) {
+			return nil, errors.Errorf("integrity check failed for chunk %d of [%s]", i, manifest.ID)
+		}
+		payload = append(payload, chunk...)
+	}
+
+	hash := sha256.Sum256(payload)
+	if !bytes.Equal(hash[:], manifest.Hash) {
+		return nil, errors.Errorf("integrity check failed for [%s]", manifest.ID)
+	}
+
+	return payload, nil
+}
+
+// Store puts and gets chunked payloads using a chaincode that implements the chunk convention
+type Store struct {
+	invoker     Invoker
+	chaincodeID string
+	collection  string
+	putFcn      string
+	getFcn      string
+	chunkSize   int
+	maxSize     int
+}
+
+// Option describes a functional parameter for the New constructor
+type Option func(*Store) error
+
+// WithChunkSize sets the maximum size of a chunk in bytes
+func WithChunkSize(size int) Option {
+	return func(s *Store) error {
+		if size <= 0 {
+			return errors.New("chunk size must be greater than zero")
+		}
+		s.chunkSize = size
+		return nil
+	}
+}
+
+// WithMaxPayloadSize sets the maximum size in bytes of a payload that is read back. Manifests
+// declaring a larger payload are rejected. The default is DefaultMaxPayloadSize.
+func WithMaxPayloadSize(size int) Option {
+	return func(s *Store) error {
+		if size <= 0 {
+			return errors.New("maximum payload size must be greater than zero")
+		}
+		s.maxSize = size
+		return nil
+	}
+}
+
+// WithFunctions sets the names of the chaincode functions used to put and get values.
+// The defaults are "put" and "get".
+func WithFunctions(putFcn, getFcn string) Option {
+	return func(s *Store) error {
+		if putFcn == "" || getFcn == "" {
+			return errors.New("put and get functions are required")
+		}
+		s.putFcn = putFcn
+		s.getFcn = getFcn
+		return nil
+	}
+}
+
+// New returns a chunk store that stores payloads in the given private data collection
+// using the given chaincode
+func New(invoker Invoker, chaincodeID, collection string, opts ...Option) (*Store, error) {
+	if invoker == nil {
+		return nil, errors.New("invoker is required")
+	}
+	if chaincodeID == "" {
+		return nil, errors.New("chaincode ID is required")
+	}
+
+	s := &Store{
+		invoker:     invoker,
+		chaincodeID: chaincodeID,
+		collection:  collection,
+		putFcn:      "put",
+		getFcn:      "get",
+		chunkSize:   DefaultChunkSize,
+		maxSize:     DefaultMaxPayloadSize,
+	}
+
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, errors.WithMessage(err, "option failed")
+		}
+	}
+
+	return s, nil
+}
+
+// Put splits the payload into chunks and stores each chunk in a separate transaction.
+// The manifest is stored (under the payload ID) only after all chunks have been committed.
+//  Parameters:
+//  id is the ID of the payload
+//  payload is the payload to store
+//  options holds optional request options that are applied to every transaction
+//
+//  Returns:
+//  the manifest of the stored payload
+func (s *Store) Put(id string, payload []byte, options ...channel.RequestOption) (*Manifest, error) {
+	manifest, chunks, err := Split(id, payload, s.chunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, chunk := range chunks {
+		if err := s.put(manifest.ChunkKey(i), chunk, options...); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("failed to put chunk %d of [%s]", i, id))
+		}
+	}
+
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal manifest")
+	}
+
+	if err := s.put(id, manifestBytes, options...); err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to put manifest of [%s]", id))
+	}
+
+	return manifest, nil
+}
+
+// Get retrieves the manifest and chunks of the given payload and returns the verified payload
+//  Parameters:
+//  id is the ID of the payload
+//  options holds optional request options that are applied to every query
+//
+//  Returns:
+//  the reassembled payload
+func (s *Store) Get(id string, options ...channel.RequestOption) ([]byte, error) {
+	manifest, err := s.Manifest(id, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([][]byte, len(manifest.Chunks))
+	for i := range manifest.Chunks {
+		chunks[i], err = s.get(manifest.ChunkKey(i), options...)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("failed to get chunk %d of [%s]", i, id))
+		}
+	}
+
+	return AssembleWithMaxSize(manifest, chunks, s.maxSize)
+}
+
+// Manifest retrieves the manifest of the given payload
+func (s *Store) Manifest(id string, options ...channel.RequestOption) (*Manifest, error) {
+	manifestBytes, err := s.get(id, options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to get manifest of [%s]", id))
+	}
+	if len(manifestBytes) == 0 {
+		return nil, errors.Errorf("manifest of [%s] not found", id)
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(manifestBytes, manifest); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal manifest")
+	}
+	if manifest.Version != manifestVersion {
+		return nil, errors.Errorf("unsupported manifest version: %d", manifest.Version)
+	}
+	if err := manifest.Validate(s.maxSize); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func (s *Store) put(key string, value []byte, options ...channel.RequestOption) error {
+	_, err := s.invoker.Execute(channel.Request{
+		ChaincodeID:  s.chaincodeID,
+		Fcn:          s.putFcn,
+		Args:         [][]byte{[]byte(s.collection), []byte(key)},
+		TransientMap: map[string][]byte{ValueField: value},
+	}, options...)
+	return err
+}
+
+func (s *Store) get(key string, options ...channel.RequestOption) ([]byte, error) {
+	response, err := s.invoker.Query(channel.Request{
+		ChaincodeID: s.chaincodeID,
+		Fcn:         s.getFcn,
+		Args:        [][]byte{[]byte(s.collection), []byte(key)},
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return response.Payload, nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chunk
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockInvoker struct {
+	values     map[string][]byte
+	executions int
+}
+
+func newMockInvoker() *mockInvoker {
+	return &mockInvoker{values: make(map[string][]byte)}
+}
+
+func (m *mockInvoker) Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	return channel.Response{Payload: m.values[string(request.Args[0])+"/"+string(request.Args[1])]}, nil
+}
+
+func (m *mockInvoker) Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	m.executions++
+	m.values[string(request.Args[0])+"/"+string(request.Args[1])] = request.TransientMap[ValueField]
+	return channel.Response{}, nil
+}
+
+func TestSplitAndAssemble(t *testing.T) {
+	payload := bytes.Repeat([]byte("0123456789"), 25)
+
+	manifest, chunks, err := Split("id1", payload, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(chunks))
+	assert.Equal(t, 3, len(manifest.Chunks))
+	assert.Equal(t, len(payload), manifest.Size)
+	assert.Equal(t, 50, len(chunks[2]))
+
+	assembled, err := Assemble(manifest, chunks)
+	require.NoError(t, err)
+	assert.Equal(t, payload, assembled)
+
+	_, err = Assemble(manifest, chunks[:2])
+	assert.Error(t, err, "expecting error for missing chunk")
+
+	tampered := [][]byte{chunks[0], []byte("tampered"), chunks[2]}
+	_, err = Assemble(manifest, tampered)
+	assert.Error(t, err, "expecting integrity check to fail")
+
+	_, _, err = Split("", payload, 100)
+	assert.Error(t, err, "expecting error for empty ID")
+
+	_, _, err = Split("id1", payload, 0)
+	assert.Error(t, err, "expecting error for invalid chunk size")
+}
+
+func TestStore(t *testing.T) {
+	invoker := newMockInvoker()
+
+	_, err := New(invoker, "", "coll1")
+	assert.Error(t, err, "expecting error for empty chaincode ID")
+
+	_, err = New(invoker, "cc1", "coll1", WithChunkSize(0))
+	assert.Error(t, err, "expecting error for invalid chunk size")
+
+	store, err := New(invoker, "cc1", "coll1", WithChunkSize(64), WithFunctions("putPrivate", "getPrivate"))
+	require.NoError(t, err)
+
+	payload := bytes.Repeat([]byte("abcdefgh"), 100)
+	manifest, err := store.Put("doc1", payload)
+	require.NoError(t, err)
+	assert.Equal(t, 13, len(manifest.Chunks))
+	assert.Equal(t, 14, invoker.executions, "expecting one transaction per chunk plus one for the manifest")
+
+	value, err := store.Get("doc1")
+	require.NoError(t, err)
+	assert.Equal(t, payload, value)
+
+	_, err = store.Get("doc2")
+	assert.Error(t, err, "expecting error for missing manifest")
+
+	invoker.values["coll1/"+ChunkKey("doc1", 3)] = []byte("corrupted")
+	_, err = store.Get("doc1")
+	assert.Error(t, err, "expecting integrity check to fail")
+}
+
+func TestHostileManifest(t *testing.T) {
+	payload := bytes.Repeat([]byte("0123456789"), 25)
+	manifest, chunks, err := Split("id1", payload, 100)
+	require.NoError(t, err)
+
+	corrupt := func(update func(m *Manifest)) *Manifest {
+		m := *manifest
+		update(&m)
+		return &m
+	}
+
+	_, err = Assemble(corrupt(func(m *Manifest) { m.Size = -1 }), chunks)
+	assert.Error(t, err, "expecting error for negative size")
+
+	_, err = Assemble(corrupt(func(m *Manifest) { m.Size = DefaultMaxPayloadSize + 1 }), chunks)
+	assert.Error(t, err, "expecting error for size above the maximum")
+
+	_, err = AssembleWithMaxSize(manifest, chunks, 200)
+	assert.Error(t, err, "expecting error for size above the configured maximum")
+
+	_, err = Assemble(corrupt(func(m *Manifest) { m.Size = 260 }), chunks)
+	assert.Error(t, err, "expecting error for size not matching the chunk lengths")
+
+	_, err = Assemble(corrupt(func(m *Manifest) { m.ChunkSize = 0 }), chunks)
+	assert.Error(t, err, "expecting error for invalid chunk size")
+
+	short := [][]byte{chunks[0], chunks[1], chunks[2][:10]}
+	_, err = Assemble(manifest, short)
+	assert.Error(t, err, "expecting error for truncated chunk")
+
+	invoker := newMockInvoker()
+	store, err := New(invoker, "cc1", "coll1", WithChunkSize(64), WithMaxPayloadSize(1024))
+	require.NoError(t, err)
+
+	_, err = New(invoker, "cc1", "coll1", WithMaxPayloadSize(0))
+	assert.Error(t, err, "expecting error for invalid maximum payload size")
+
+	invoker.values["coll1/doc1"] = []byte(`{"version":1,"id":"doc1","size":1073741824,"chunkSize":64,"chunks":[]}`)
+	_, err = store.Get("doc1")
+	require.Error(t, err, "expecting hostile manifest to be rejected")
+	assert.Contains(t, err.Error(), "exceeds the maximum")
+
+	invoker.values["coll1/doc1"] = []byte(`{"version":1,"id":"doc1","size":100,"chunkSize":64,"chunks":["AA=="]}`)
+	_, err = store.Get("doc1")
+	require.Error(t, err, "expecting manifest with missing chunk hashes to be rejected")
+	assert.Contains(t, err.Error(), "are expected for payload size")
+	assert.Equal(t, 0, invoker.executions)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package contract invokes the transactions of chaincodes implemented with the Fabric contract API,
+// marshalling the arguments and unmarshalling the results of the transactions. It is the runtime of
+// the typed client bindings generated from the contract metadata by fabric-contract-gen:
+//
+//  //go:generate fabric-contract-gen -metadata metadata.json -package assets -out contract.gen.go
+//
+//  Basic Flow:
+//  1) Retrieve the metadata of the chaincode (org.hyperledger.fabric:GetMetadata) and generate the bindings
+//  2) Create a channel client
+//  3) Create the generated client of the contract with the channel client and the chaincode ID
+//  4) Call the transactions of the contract, e.g. client.CreateAsset(ctx, asset)
+package contract
+
+import (
+	reqContext "context"
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/pkg/errors"
+)
+
+// Executor queries and executes chaincode transactions (e.g. channel.Client)
+type Executor interface {
+	Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+}
+
+// Contract invokes the transactions of a contract of a chaincode
+type Contract struct {
+	client      Executor
+	chaincodeID string
+	name        string
+}
+
+// New returns a contract of the given chaincode. The name of the contract may be empty, in which
+// case the transactions are routed to the default contract of the chaincode.
+func New(client Executor, chaincodeID, name string) *Contract {
+	return &Contract{client: client, chaincodeID: chaincodeID, name: name}
+}
+
+// Submit executes a transaction, i.e. endorses it and commits it to the ledger
+//  Parameters:
+//  ctx is the parent context of the request (may be nil)
+//  txName is the name of the transaction in the contract
+//  args are the arguments of the transaction
+//  result is unmarshalled from the payload of the transaction (may be nil if the result isn't needed)
+//  options holds optional request options
+func (c *Contract) Submit(ctx reqContext.Context, txName string, args []interface{}, result interface{}, options ...channel.RequestOption) error {
+	request, err := c.request(txName, args)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.client.Execute(request, withParentContext(ctx, options)...)
+	if err != nil {
+		return errors.WithMessage(err, "failed to submit transaction ["+txName+"]")
+	}
+	return UnmarshalResult(response.Payload, result)
+}
+
+// Evaluate queries a transaction without committing it to the ledger
+//  Parameters:
+//  ctx is the parent context of the request (may be nil)
+//  txName is the name of the transaction in the contract
+//  args are the arguments of the transaction
+//  result is unmarshalled from the payload of the transaction (may be nil if the result isn't needed)
+//  options holds optional request options
+func (c *Contract) Evaluate(ctx reqContext.Context, txName string, args []interface{}, result interface{}, options ...channel.RequestOption) error {
+	request, err := c.request(txName, args)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.client.Query(request, withParentContext(ctx, options)...)
+	if err != nil {
+		return errors.WithMessage(err, "failed to evaluate transaction ["+txName+"]")
+	}
+	return UnmarshalResult(response.Payload, result)
+}
+
+func (c *Contract) request(txName string, args []interface{}) (channel.Request, error) {
+	fcn := txName
+	if c.name != "" {
+		fcn = c.name + ":" + txName
+	}
+
+	request := channel.Request{ChaincodeID: c.chaincodeID, Fcn: fcn}
+	for i, arg := range args {
+		b, err := MarshalArg(arg)
+		if err != nil {
+			return channel.Request{}, errors.WithMessage(err, fmt.Sprintf("failed to marshal argument %d of transaction [%s]", i, txName))
+		}
+		request.Args = append(request.Args, b)
+	}
+	return request, nil
+}
+
+// withParentContext prepends the parent context so that it may be overridden by the options
+func withParentContext(ctx reqContext.Context, options []channel.RequestOption) []channel.RequestOption {
+	if ctx == nil {
+		return options
+	}
+	return append([]channel.RequestOption{channel.WithParentContext(ctx)}, options...)
+}
+
+// MarshalArg marshals a transaction argument the way the contract API expects it: strings and
+// byte slices as is and other values (including booleans and numbers) as JSON
+func MarshalArg(arg interface{}) ([]byte, error) {
+	return channel.JSONEncoder(arg)
+}
+
+// UnmarshalResult unmarshals the payload of a transaction into result (see channel.JSONDecoder)
+func UnmarshalResult(payload []byte, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	return errors.WithMessage(channel.JSONDecoder(payload, result), "failed to unmarshal transaction result")
+}
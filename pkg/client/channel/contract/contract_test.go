@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package contract
+
+import (
+	reqContext "context"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type asset struct {
+	ID   string `json:"ID"`
+	Size int    `json:"size"`
+}
+
+type mockExecutor struct {
+	payload  []byte
+	err      error
+	request  channel.Request
+	options  []channel.RequestOption
+	executed bool
+}
+
+func (m *mockExecutor) Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	m.request, m.options = request, options
+	return channel.Response{Payload: m.payload}, m.err
+}
+
+func (m *mockExecutor) Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	m.executed = true
+	return m.Query(request, options...)
+}
+
+func TestSubmit(t *testing.T) {
+	client := &mockExecutor{}
+	c := New(client, "assetcc", "org.example.assets")
+
+	err := c.Submit(reqContext.Background(), "CreateAsset", []interface{}{asset{ID: "asset1", Size: 5}, "blue", 10, true}, nil)
+	require.NoError(t, err)
+	assert.True(t, client.executed)
+	assert.Equal(t, "assetcc", client.request.ChaincodeID)
+	assert.Equal(t, "org.example.assets:CreateAsset", client.request.Fcn)
+	assert.Equal(t, [][]byte{[]byte(`{"ID":"asset1","size":5}`), []byte("blue"), []byte("10"), []byte("true")}, client.request.Args)
+	assert.Len(t, client.options, 1, "expecting parent context option")
+
+	client.err = errors.New("endorsement failed")
+	err = c.Submit(nil, "CreateAsset", nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to submit transaction [CreateAsset]")
+	assert.Empty(t, client.options)
+}
+
+func TestEvaluate(t *testing.T) {
+	client := &mockExecutor{payload: []byte(`{"ID":"asset1","size":5}`)}
+	c := New(client, "assetcc", "")
+
+	var result asset
+	err := c.Evaluate(nil, "ReadAsset", []interface{}{"asset1"}, &result)
+	require.NoError(t, err)
+	assert.False(t, client.executed)
+	assert.Equal(t, "ReadAsset", client.request.Fcn, "expecting default contract")
+	assert.Equal(t, asset{ID: "asset1", Size: 5}, result)
+
+	var s string
+	require.NoError(t, c.Evaluate(nil, "ReadAsset", nil, &s))
+	assert.Equal(t, `{"ID":"asset1","size":5}`, s)
+
+	client.payload = []byte("42")
+	var n int64
+	require.NoError(t, c.Evaluate(nil, "Count", nil, &n))
+	assert.Equal(t, int64(42), n)
+
+	client.payload = []byte("not json")
+	err = c.Evaluate(nil, "ReadAsset", nil, &result)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to unmarshal transaction result")
+}
+
+func TestMarshalArg(t *testing.T) {
+	for arg, expected := range map[interface{}]string{
+		"value":         "value",
+		int32(-7):       "-7",
+		uint64(7):       "7",
+		float64(1.5):    "1.5",
+		float32(0.25):   "0.25",
+		false:           "false",
+		[2]string{"a"}:  `["a",""]`,
+		struct{}{}:      "{}",
+		(*asset)(nil):   "null",
+		asset{ID: "a1"}: `{"ID":"a1","size":0}`,
+	} {
+		b, err := MarshalArg(arg)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(b))
+	}
+
+	b, err := MarshalArg([]byte("raw"))
+	require.NoError(t, err)
+	assert.Equal(t, "raw", string(b))
+
+	_, err = MarshalArg(make(chan int))
+	assert.Error(t, err)
+}
+
+func TestParseMetadata(t *testing.T) {
+	_, err := ParseMetadata([]byte("{"))
+	assert.Error(t, err)
+
+	_, err = ParseMetadata([]byte("{}"))
+	assert.Error(t, err, "expecting error for metadata without contracts")
+
+	metadata, err := ParseMetadata([]byte(`{
+		"contracts": {"assets": {"transactions": [
+			{"name": "Create", "tag": ["submit"]},
+			{"name": "Read", "tag": ["Evaluate"], "returns": {"$ref": "#/components/schemas/Asset"}},
+			{"name": "List", "returns": [{"name": "success", "schema": {"type": "array", "items": {"type": "string"}}}]}
+		]}},
+		"components": {"schemas": {"Asset": {"type": "object", "required": ["ID"], "properties": {"ID": {"type": "string"}}}}}
+	}`))
+	require.NoError(t, err)
+
+	c := metadata.Contracts["assets"]
+	require.NotNil(t, c)
+	assert.Equal(t, "assets", c.Name)
+	require.Len(t, c.Transactions, 3)
+	assert.True(t, c.Transactions[0].IsSubmit())
+	assert.Nil(t, c.Transactions[0].Returns)
+	assert.False(t, c.Transactions[1].IsSubmit())
+	assert.Equal(t, "Asset", c.Transactions[1].Returns.RefName())
+	assert.Equal(t, "array", c.Transactions[2].Returns.Type)
+	assert.True(t, metadata.Components.Schemas["Asset"].IsRequired("ID"))
+	assert.False(t, metadata.Components.Schemas["Asset"].IsRequired("size"))
+}
@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package contract
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MetadataTxName is the name of the system transaction returning the metadata of a chaincode
+const MetadataTxName = "org.hyperledger.fabric:GetMetadata"
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// Metadata is the metadata of a chaincode implemented with the Fabric contract API
+type Metadata struct {
+	Contracts  map[string]*ContractMetadata `json:"contracts"`
+	Components ComponentMetadata            `json:"components"`
+}
+
+// ContractMetadata is the metadata of a contract
+type ContractMetadata struct {
+	Name         string                 `json:"name"`
+	Default      bool                   `json:"default"`
+	Transactions []*TransactionMetadata `json:"transactions"`
+}
+
+// TransactionMetadata is the metadata of a transaction of a contract
+type TransactionMetadata struct {
+	Name       string               `json:"name"`
+	Tag        []string             `json:"tag"`
+	Parameters []*ParameterMetadata `json:"parameters"`
+	// Returns is the schema of the result of the transaction (nil if the transaction has no result)
+	Returns *Schema `json:"-"`
+}
+
+// ParameterMetadata is the metadata of a parameter of a transaction
+type ParameterMetadata struct {
+	Name   string  `json:"name"`
+	Schema *Schema `json:"schema"`
+}
+
+// ComponentMetadata holds the schemas of the objects used by the transactions
+type ComponentMetadata struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is the JSON schema of a value
+type Schema struct {
+	Type       string             `json:"type"`
+	Format     string             `json:"format"`
+	Ref        string             `json:"$ref"`
+	Items      *Schema            `json:"items"`
+	Properties map[string]*Schema `json:"properties"`
+	Required   []string           `json:"required"`
+}
+
+// RefName returns the name of the component schema referenced by the schema, if any
+func (s *Schema) RefName() string {
+	return strings.TrimPrefix(s.Ref, schemaRefPrefix)
+}
+
+// IsRequired returns true if the property is required
+func (s *Schema) IsRequired(property string) bool {
+	for _, name := range s.Required {
+		if name == property {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSubmit returns true if the transaction is to be submitted rather than evaluated. Transactions
+// are submitted unless they are tagged as evaluate.
+func (t *TransactionMetadata) IsSubmit() bool {
+	for _, tag := range t.Tag {
+		if strings.EqualFold(tag, "evaluate") {
+			return false
+		}
+	}
+	return true
+}
+
+// UnmarshalJSON accepts the returns of both the Go contract API (a schema) and the Node
+// contract API (a list of named schemas)
+func (t *TransactionMetadata) UnmarshalJSON(b []byte) error {
+	type transactionMetadata TransactionMetadata
+	tx := struct {
+		*transactionMetadata
+		Returns json.RawMessage `json:"returns"`
+	}{transactionMetadata: (*transactionMetadata)(t)}
+
+	if err := json.Unmarshal(b, &tx); err != nil {
+		return err
+	}
+
+	returns := strings.TrimSpace(string(tx.Returns))
+	switch {
+	case returns == "" || returns == "null":
+		return nil
+	case strings.HasPrefix(returns, "["):
+		var params []*ParameterMetadata
+		if err := json.Unmarshal(tx.Returns, &params); err != nil {
+			return err
+		}
+		if len(params) > 0 {
+			t.Returns = params[0].Schema
+		}
+		return nil
+	default:
+		t.Returns = &Schema{}
+		return json.Unmarshal(tx.Returns, t.Returns)
+	}
+}
+
+// ParseMetadata parses the metadata returned by the GetMetadata system transaction
+func ParseMetadata(b []byte) (*Metadata, error) {
+	metadata := &Metadata{}
+	if err := json.Unmarshal(b, metadata); err != nil {
+		return nil, errors.Wrap(err, "invalid contract metadata")
+	}
+	if len(metadata.Contracts) == 0 {
+		return nil, errors.New("contract metadata has no contracts")
+	}
+	for name, contract := range metadata.Contracts {
+		if contract.Name == "" {
+			contract.Name = name
+		}
+	}
+	return metadata, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/pkg/errors"
+)
+
+// JSONDecoder is the default response decoder. The payload is unmarshalled from JSON, except
+// into strings and byte slices, which are set to the payload as is. An empty payload leaves
+// the result unchanged.
+func JSONDecoder(payload []byte, result interface{}) error {
+	if len(payload) == 0 {
+		return nil
+	}
+
+	switch v := result.(type) {
+	case *string:
+		*v = string(payload)
+	case *[]byte:
+		*v = payload
+	default:
+		if err := json.Unmarshal(payload, result); err != nil {
+			return errors.Wrap(err, "JSON unmarshal failed")
+		}
+	}
+	return nil
+}
+
+// QueryInto queries chaincode and decodes the payload of the response into result
+//  Parameters:
+//  request holds info about mandatory chaincode ID and function
+//  result is a pointer to the value into which the payload is decoded
+//  options holds optional request options (see WithResponseDecoder)
+//
+//  Returns:
+//  the proposal responses from peer(s)
+func (cc *Client) QueryInto(request Request, result interface{}, options ...RequestOption) (Response, error) {
+	var decoder func([]byte, interface{}) error
+	response, err := cc.Query(request, append(options, withResponseDecoderOf(&decoder))...)
+	if err != nil {
+		return response, err
+	}
+	return response, decodePayload(decoder, response.Payload, result)
+}
+
+// ExecuteInto executes a transaction and decodes the payload of the response into result
+//  Parameters:
+//  request holds info about mandatory chaincode ID and function
+//  result is a pointer to the value into which the payload is decoded
+//  options holds optional request options (see WithResponseDecoder)
+//
+//  Returns:
+//  the proposal responses from peer(s)
+func (cc *Client) ExecuteInto(request Request, result interface{}, options ...RequestOption) (Response, error) {
+	var decoder func([]byte, interface{}) error
+	response, err := cc.Execute(request, append(options, withResponseDecoderOf(&decoder))...)
+	if err != nil {
+		return response, err
+	}
+	return response, decodePayload(decoder, response.Payload, result)
+}
+
+// withResponseDecoderOf sets the given decoder to the decoder of the request options as they are built for the
+// request (by the default options of the client and the request options), so that the options aren't applied again
+func withResponseDecoderOf(decoder *func([]byte, interface{}) error) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		*decoder = o.ResponseDecoder
+		return nil
+	}
+}
+
+func decodePayload(decoder func([]byte, interface{}) error, payload []byte, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	if decoder == nil {
+		decoder = JSONDecoder
+	}
+	if err := decoder(payload, result); err != nil {
+		return errors.WithMessage(err, "failed to decode response payload")
+	}
+	return nil
+}
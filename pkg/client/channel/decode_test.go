@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONDecoder(t *testing.T) {
+	var asset testAsset
+	require.NoError(t, JSONDecoder([]byte(`{"id":"asset1","Size":5}`), &asset))
+	assert.Equal(t, testAsset{ID: "asset1", Size: 5}, asset)
+
+	var s string
+	require.NoError(t, JSONDecoder([]byte("value"), &s))
+	assert.Equal(t, "value", s)
+
+	var b []byte
+	require.NoError(t, JSONDecoder([]byte("value"), &b))
+	assert.Equal(t, []byte("value"), b)
+
+	n := 7
+	require.NoError(t, JSONDecoder(nil, &n))
+	assert.Equal(t, 7, n, "expecting empty payload to leave result unchanged")
+
+	assert.Error(t, JSONDecoder([]byte("{"), &asset))
+}
+
+func TestQueryInto(t *testing.T) {
+	peer := fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: 200, Payload: []byte(`{"id":"asset1","Size":5}`)}
+	chClient := setupChannelClient([]fab.Peer{&peer}, t)
+	request := Request{ChaincodeID: "testCC", Fcn: "read", Args: [][]byte{[]byte("asset1")}}
+
+	var asset testAsset
+	response, err := chClient.QueryInto(request, &asset)
+	require.NoError(t, err)
+	assert.Equal(t, peer.Payload, response.Payload)
+	assert.Equal(t, testAsset{ID: "asset1", Size: 5}, asset)
+
+	var m map[string]string
+	_, err = chClient.QueryInto(request, &m, WithResponseDecoder(func(payload []byte, result interface{}) error {
+		*(result.(*map[string]string)) = map[string]string{"payload": string(payload)}
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, string(peer.Payload), m["payload"])
+
+	_, err = chClient.QueryInto(request, &asset, WithResponseDecoder(func(payload []byte, result interface{}) error {
+		return errors.New("decode error")
+	}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode response payload: decode error")
+
+	response, err = chClient.QueryInto(request, nil)
+	require.NoError(t, err, "expecting payload to be ignored")
+	assert.Equal(t, peer.Payload, response.Payload)
+
+	peer.Payload = []byte("not json")
+	_, err = chClient.QueryInto(request, &asset)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode response payload")
+
+	applied := 0
+	_, err = chClient.QueryInto(request, nil, func(ctx context.Client, o *requestOptions) error {
+		applied++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, applied, "expecting the request options to be applied once")
+}
+
+func TestExecuteInto(t *testing.T) {
+	peer := fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: 200, Payload: []byte("42")}
+	chClient := setupChannelClient([]fab.Peer{&peer}, t)
+
+	var count int
+	_, err := chClient.ExecuteInto(Request{ChaincodeID: "testCC", Fcn: "count"}, &count)
+	require.NoError(t, err)
+	assert.Equal(t, 42, count)
+
+	_, err = chClient.ExecuteInto(Request{ChaincodeID: "testCC"}, &count)
+	assert.Error(t, err)
+}
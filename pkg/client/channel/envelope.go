@@ -0,0 +1,123 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+type envelopeSender interface {
+	SendEnvelope(envelope *fab.SignedEnvelope) (*fab.TransactionResponse, error)
+}
+
+// SubmitEnvelope broadcasts a previously constructed and signed transaction envelope to the orderer and
+// waits for the transaction to be committed. This allows for store-and-forward architectures where the
+// envelope (e.g. as returned with WithWireArtifacts) is created and signed in a secure zone and submitted
+// later by a relay whose identity doesn't need to be authorized for the transaction. The envelope must be
+// an endorser transaction of the client's channel; it is submitted as is.
+//  Parameters:
+//  envelope is the marshalled, signed common.Envelope
+//  options holds optional request options (timeouts, parent context and retry options)
+//
+//  Returns:
+//  the response with the transaction ID and the validation code of the transaction
+func (cc *Client) SubmitEnvelope(envelope []byte, options ...RequestOption) (Response, error) {
+	txnOpts, err := cc.prepareOptsFromOptions(cc.context, options...)
+	if err != nil {
+		return Response{}, err
+	}
+
+	signedEnvelope, txnID, err := cc.parseEnvelope(envelope)
+	if err != nil {
+		return Response{}, err
+	}
+
+	reqCtx, cancel := cc.createReqContext(&txnOpts)
+	defer cancel()
+
+	transactor, err := cc.context.ChannelService().Transactor(reqCtx)
+	if err != nil {
+		return Response{}, errors.WithMessage(err, "failed to create transactor")
+	}
+	sender, ok := transactor.(envelopeSender)
+	if !ok {
+		return Response{}, errors.New("transactor does not support submitting envelopes")
+	}
+
+	response := Response{TransactionID: txnID, Envelope: envelope}
+
+	reg, statusNotifier, err := cc.eventService.RegisterTxStatusEvent(string(txnID))
+	if err != nil {
+		return response, errors.Wrap(err, "error registering for TxStatus event")
+	}
+	defer cc.eventService.Unregister(reg)
+
+	txnResponse, err := retry.NewInvoker(retry.New(txnOpts.Retry), retry.WithContext(reqCtx)).Invoke(
+		func() (interface{}, error) {
+			return sender.SendEnvelope(signedEnvelope)
+		},
+	)
+	if err != nil {
+		return response, errors.WithMessage(err, "SendEnvelope failed")
+	}
+	response.Orderer = txnResponse.(*fab.TransactionResponse).Orderer
+
+	select {
+	case txStatus := <-statusNotifier:
+		response.TxValidationCode = txStatus.TxValidationCode
+		if txStatus.TxValidationCode != pb.TxValidationCode_VALID {
+			return response, status.New(status.EventServerStatus, int32(txStatus.TxValidationCode),
+				"received invalid transaction", nil)
+		}
+		return response, nil
+	case <-reqCtx.Done():
+		return response, status.New(status.ClientStatus, status.Timeout.ToInt32(),
+			"SubmitEnvelope didn't receive block event", nil)
+	}
+}
+
+// parseEnvelope checks that the envelope is a signed endorser transaction of the client's channel
+// and returns its transaction ID
+func (cc *Client) parseEnvelope(envelope []byte) (*fab.SignedEnvelope, fab.TransactionID, error) {
+	env := &common.Envelope{}
+	if err := proto.Unmarshal(envelope, env); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal envelope")
+	}
+	if len(env.Signature) == 0 {
+		return nil, "", errors.New("envelope is not signed")
+	}
+
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(env.Payload, payload); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal envelope payload")
+	}
+	if payload.Header == nil {
+		return nil, "", errors.New("envelope payload has no header")
+	}
+
+	chHeader := &common.ChannelHeader{}
+	if err := proto.Unmarshal(payload.Header.ChannelHeader, chHeader); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal channel header")
+	}
+	if chHeader.Type != int32(common.HeaderType_ENDORSER_TRANSACTION) {
+		return nil, "", errors.Errorf("envelope is not an endorser transaction (header type %d)", chHeader.Type)
+	}
+	if chHeader.ChannelId != cc.context.ChannelID() {
+		return nil, "", errors.Errorf("envelope is for channel [%s] but the client is for channel [%s]", chHeader.ChannelId, cc.context.ChannelID())
+	}
+	if chHeader.TxId == "" {
+		return nil, "", errors.New("envelope has no transaction ID")
+	}
+
+	return &fab.SignedEnvelope{Payload: env.Payload, Signature: env.Signature}, fab.TransactionID(chHeader.TxId), nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitEnvelope(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = fcmocks.NewMockEventService()
+
+	envelope := newTestEnvelope(t, channelID, "txid", []byte("signature"))
+
+	response, err := chClient.SubmitEnvelope(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, fab.TransactionID("txid"), response.TransactionID)
+	assert.Equal(t, envelope, response.Envelope)
+	assert.Equal(t, pb.TxValidationCode_VALID, response.TxValidationCode)
+
+	mockEventService := fcmocks.NewMockEventService()
+	mockEventService.TxValidationCode = pb.TxValidationCode_MVCC_READ_CONFLICT
+	chClient.eventService = mockEventService
+
+	response, err = chClient.SubmitEnvelope(envelope)
+	statusError, ok := status.FromError(err)
+	require.True(t, ok, "Expected status error got %+v", err)
+	assert.EqualValues(t, pb.TxValidationCode_MVCC_READ_CONFLICT, status.ToTransactionValidationCode(statusError.Code))
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, response.TxValidationCode)
+}
+
+func TestSubmitEnvelopeInvalid(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = fcmocks.NewMockEventService()
+
+	_, err := chClient.SubmitEnvelope([]byte("garbage"))
+	assert.Error(t, err)
+
+	_, err = chClient.SubmitEnvelope(newTestEnvelope(t, channelID, "txid", nil))
+	assert.Contains(t, err.Error(), "envelope is not signed")
+
+	_, err = chClient.SubmitEnvelope(newTestEnvelope(t, "otherchannel", "txid", []byte("signature")))
+	assert.Contains(t, err.Error(), "envelope is for channel [otherchannel]")
+
+	_, err = chClient.SubmitEnvelope(newTestEnvelope(t, channelID, "", []byte("signature")))
+	assert.Contains(t, err.Error(), "envelope has no transaction ID")
+}
+
+func newTestEnvelope(t *testing.T, channelID, txID string, signature []byte) []byte {
+	chHeader, err := proto.Marshal(&common.ChannelHeader{
+		Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+		ChannelId: channelID,
+		TxId:      txID,
+	})
+	require.NoError(t, err)
+
+	payload, err := proto.Marshal(&common.Payload{Header: &common.Header{ChannelHeader: chHeader}})
+	require.NoError(t, err)
+
+	envelope, err := proto.Marshal(&common.Envelope{Payload: payload, Signature: signature})
+	require.NoError(t, err)
+	return envelope
+}
@@ -0,0 +1,106 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// ImplicitCollectionPrefix is the prefix of the names of the implicit private data collections.
+// Each organization of a channel has an implicit collection (Fabric 2.0 or later) which
+// is only distributed to the peers of the organization.
+const ImplicitCollectionPrefix = "_implicit_org_"
+
+// ImplicitCollectionName returns the name of the implicit private data collection of the given organization
+func ImplicitCollectionName(mspID string) string {
+	return ImplicitCollectionPrefix + mspID
+}
+
+// ImplicitCollectionName returns the name of the implicit private data collection of the client's organization
+func (cc *Client) ImplicitCollectionName() string {
+	return ImplicitCollectionName(cc.context.Identifier().MSPID)
+}
+
+// QueryImplicitCollection queries chaincode which reads private data from the implicit collection of the
+// client's organization. Unless targets or a target filter are specified in the options, the query is only
+// sent to peers of the client's organization since the other peers don't hold the private data.
+//  Parameters:
+//  request holds info about mandatory chaincode ID and function
+//  options holds optional request options
+//
+//  Returns:
+//  the proposal responses from peer(s)
+func (cc *Client) QueryImplicitCollection(request Request, options ...RequestOption) (Response, error) {
+	request.InvocationChain = withCollection(request.InvocationChain, request.ChaincodeID, cc.ImplicitCollectionName())
+	options = append(options, addMSPTargetFilter(cc.context, filter.ChaincodeQuery, cc.context.Identifier().MSPID))
+
+	return cc.Query(request, options...)
+}
+
+// ExecuteImplicitCollection executes a transaction which writes private data (usually passed in the
+// transient map) to the implicit collection of the client's organization. The collection is added to
+// the invocation chain of the request so that endorsers which have access to it are selected.
+//  Parameters:
+//  request holds info about mandatory chaincode ID and function
+//  options holds optional request options
+//
+//  Returns:
+//  the proposal responses from peer(s)
+func (cc *Client) ExecuteImplicitCollection(request Request, options ...RequestOption) (Response, error) {
+	request.InvocationChain = withCollection(request.InvocationChain, request.ChaincodeID, cc.ImplicitCollectionName())
+
+	return cc.Execute(request, options...)
+}
+
+// withCollection returns a copy of the invocation chain in which the given collection is added to the given chaincode
+func withCollection(invocationChain []*fab.ChaincodeCall, ccID, collection string) []*fab.ChaincodeCall {
+	var chain []*fab.ChaincodeCall
+	found := false
+	for _, call := range invocationChain {
+		if call.ID == ccID {
+			found = true
+			call = &fab.ChaincodeCall{ID: call.ID, Collections: appendIfMissing(call.Collections, collection)}
+		}
+		chain = append(chain, call)
+	}
+	if !found {
+		chain = append(chain, &fab.ChaincodeCall{ID: ccID, Collections: []string{collection}})
+	}
+	return chain
+}
+
+func appendIfMissing(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(append([]string{}, values...), value)
+}
+
+// addMSPTargetFilter restricts the default target filter to the peers of the given MSP
+// if neither targets nor a target filter are specified
+func addMSPTargetFilter(chCtx context.Channel, ft filter.EndpointType, mspID string) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if len(o.Targets) == 0 && o.TargetFilter == nil {
+			return WithTargetFilter(&mspTargetFilter{mspID: mspID, filter: filter.NewEndpointFilter(chCtx, o.peerRole(ft))})(ctx, o)
+		}
+		return nil
+	}
+}
+
+type mspTargetFilter struct {
+	mspID  string
+	filter fab.TargetFilter
+}
+
+// Accept returns true if the peer belongs to the MSP and is accepted by the underlying filter
+func (f *mspTargetFilter) Accept(peer fab.Peer) bool {
+	return peer.MSPID() == f.mspID && f.filter.Accept(peer)
+}
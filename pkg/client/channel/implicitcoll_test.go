@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+func TestImplicitCollectionName(t *testing.T) {
+	assert.Equal(t, "_implicit_org_Org1MSP", ImplicitCollectionName("Org1MSP"))
+
+	chClient := setupChannelClient(nil, t)
+	assert.Equal(t, "_implicit_org_test", chClient.ImplicitCollectionName())
+}
+
+func TestQueryImplicitCollection(t *testing.T) {
+	ownPeer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	ownPeer.MockMSP = "test"
+	ownPeer.Payload = []byte("private")
+	otherPeer := fcmocks.NewMockPeer("Peer2", "http://peer2.com")
+	otherPeer.MockMSP = "Org2MSP"
+
+	chClient := setupChannelClient([]fab.Peer{ownPeer, otherPeer}, t)
+
+	response, err := chClient.QueryImplicitCollection(Request{ChaincodeID: "testCC", Fcn: "readPrivate"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("private"), response.Payload)
+	assert.Equal(t, 1, ownPeer.ProcessProposalCalls)
+	assert.Equal(t, 0, otherPeer.ProcessProposalCalls, "expecting query to be sent to peers of the client's organization only")
+
+	// Explicit targets take precedence
+	_, err = chClient.QueryImplicitCollection(Request{ChaincodeID: "testCC", Fcn: "readPrivate"}, WithTargets(otherPeer))
+	require.NoError(t, err)
+	assert.Equal(t, 1, otherPeer.ProcessProposalCalls)
+}
+
+func TestExecuteImplicitCollection(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+
+	_, err := chClient.ExecuteImplicitCollection(Request{ChaincodeID: "testCC", Fcn: "writePrivate", TransientMap: map[string][]byte{"asset": []byte("value")}})
+	assert.NoError(t, err)
+}
+
+func TestWithCollection(t *testing.T) {
+	chain := withCollection(nil, "cc1", "coll")
+	require.Len(t, chain, 1)
+	assert.Equal(t, &fab.ChaincodeCall{ID: "cc1", Collections: []string{"coll"}}, chain[0])
+
+	existing := []*fab.ChaincodeCall{{ID: "cc1", Collections: []string{"other"}}, {ID: "cc2"}}
+	chain = withCollection(existing, "cc1", "coll")
+	require.Len(t, chain, 2)
+	assert.Equal(t, []string{"other", "coll"}, chain[0].Collections)
+	assert.Equal(t, []string{"other"}, existing[0].Collections, "expecting invocation chain of the request to be unchanged")
+
+	chain = withCollection(chain, "cc1", "coll")
+	assert.Equal(t, []string{"other", "coll"}, chain[0].Collections, "expecting collection to be added only once")
+}
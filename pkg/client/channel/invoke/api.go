@@ -11,6 +11,8 @@ import (
 	reqContext "context"
 	"time"
 
+	peerfilter "github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/inspect"
 	selectopts "github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
@@ -22,6 +24,10 @@ import (
 // in the invocation chain when computing endorsers.
 type CCFilter func(ccID string) bool
 
+// ProposalResponseValidator is invoked with the endorsement responses before the transaction
+// is sent to the orderer. Returning an error vetoes the submission.
+type ProposalResponseValidator func(responses []*fab.TransactionProposalResponse) error
+
 // Opts allows the user to specify more advanced options
 type Opts struct {
 	Targets       []fab.Peer // targets
@@ -31,6 +37,16 @@ type Opts struct {
 	Timeouts      map[fab.TimeoutType]time.Duration
 	ParentContext reqContext.Context //parent grpc context
 	CCFilter      CCFilter
+
+	ProposalResponseValidator ProposalResponseValidator
+	SimulationOnly            bool
+	IncludeRWSets             bool
+	IncludeWireArtifacts      bool
+	PeerRole                  *peerfilter.EndpointType
+	EndorsingOrgs             []string
+	ResponseDecoder           func(payload []byte, result interface{}) error
+	HedgeDelay                time.Duration
+	MinBlockHeight            uint64
 }
 
 // Request contains the parameters to execute transaction
@@ -49,6 +65,10 @@ type Request struct {
 	// The invoked chaincode (specified by ChaincodeID) may optionally be added to the invocation
 	// chain along with any collections, otherwise it may be omitted.
 	InvocationChain []*fab.ChaincodeCall
+
+	// IsInit must be set when invoking the chaincode's initialization function if the chaincode
+	// was committed with the init required flag (Fabric 2.0 chaincode lifecycle)
+	IsInit bool
 }
 
 //Response contains response parameters for query and execute transaction
@@ -59,6 +79,11 @@ type Response struct {
 	TxValidationCode pb.TxValidationCode
 	ChaincodeStatus  int32
 	Payload          []byte
+	RWSets           []inspect.NsRWSet
+	SignedProposal   []byte
+	Envelope         []byte
+	Orderer          string
+	BlockNumber      uint64
 }
 
 //Handler for chaining transaction executions
@@ -74,6 +99,7 @@ type ClientContext struct {
 	Membership   fab.ChannelMembership
 	Transactor   fab.Transactor
 	EventService fab.EventService
+	BlockHeight  BlockHeightProvider
 }
 
 //RequestContext contains request, opts, response parameters for handler execution
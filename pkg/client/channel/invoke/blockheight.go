@@ -0,0 +1,234 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	reqContext "context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// DefaultBlockHeightPollInterval is the interval at which the ledger heights of the peers
+// are queried while waiting for a peer to reach the minimum block height of a query
+const DefaultBlockHeightPollInterval = 500 * time.Millisecond
+
+// DefaultBlockHeightTimeout is the timeout of the ledger height query of a peer, so that an unresponsive
+// peer doesn't hold up the selection of the peers which have reached the minimum block height
+const DefaultBlockHeightTimeout = 3 * time.Second
+
+// BlockHeightProvider returns the current ledger height of a peer
+type BlockHeightProvider func(reqCtx reqContext.Context, peer fab.Peer) (uint64, error)
+
+//BlockHeightHandler restricts the targets to the peers whose ledgers have reached the
+//minimum block height of the request. If none of the targets has reached it, the other peers
+//of their organizations are considered, waiting until a peer reaches it if necessary.
+//The targets are only restricted for the next handler, so a retry of the request starts
+//from the original targets. Heights queried within the poll interval are reused.
+type BlockHeightHandler struct {
+	next         Handler
+	pollInterval time.Duration
+	timeout      time.Duration
+
+	mutex   sync.Mutex
+	heights map[string]cachedHeight
+}
+
+type cachedHeight struct {
+	height  uint64
+	fetched time.Time
+}
+
+//Handle selects the targets which have reached the minimum block height
+func (h *BlockHeightHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	minHeight := requestContext.Opts.MinBlockHeight
+	if minHeight == 0 {
+		h.handleNext(requestContext, clientContext)
+		return
+	}
+
+	pollInterval := h.pollInterval
+	if pollInterval <= 0 {
+		pollInterval = DefaultBlockHeightPollInterval
+	}
+
+	timeout := h.timeout
+	if timeout <= 0 {
+		timeout = DefaultBlockHeightTimeout
+	}
+
+	var backups []fab.Peer
+	backupsLoaded := false
+
+	for {
+		targets, err := h.peersAtHeight(requestContext.Ctx, clientContext.BlockHeight, requestContext.Opts.Targets, minHeight, timeout, pollInterval)
+		if len(targets) == 0 {
+			if !backupsLoaded {
+				backups = backupPeers(requestContext, clientContext)
+				backupsLoaded = true
+			}
+			if len(backups) > 0 {
+				var backupErr error
+				targets, backupErr = h.peersAtHeight(requestContext.Ctx, clientContext.BlockHeight, backups, minHeight, timeout, pollInterval)
+				err = multi.Append(err, backupErr)
+			}
+		}
+		if len(targets) > 0 {
+			if requestContext.Opts.HedgeDelay == 0 {
+				// peers at different heights may return different payloads so only the most up-to-date peer is queried
+				targets = targets[:1]
+			}
+			logger.Debugf("querying %d peer(s) at block height %d or above", len(targets), minHeight)
+			originalTargets := requestContext.Opts.Targets
+			requestContext.Opts.Targets = targets
+			h.handleNext(requestContext, clientContext)
+			requestContext.Opts.Targets = originalTargets
+			return
+		}
+
+		logger.Debugf("no peer has reached block height %d: %s", minHeight, err)
+
+		select {
+		case <-requestContext.Ctx.Done():
+			requestContext.Error = status.New(status.ClientStatus, status.Timeout.ToInt32(),
+				fmt.Sprintf("no peer reached block height %d: %s", minHeight, err), nil)
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (h *BlockHeightHandler) handleNext(requestContext *RequestContext, clientContext *ClientContext) {
+	if h.next != nil {
+		h.next.Handle(requestContext, clientContext)
+	}
+}
+
+//NewBlockHeightHandler returns a handler that selects the targets which have reached the minimum block height
+func NewBlockHeightHandler(next ...Handler) *BlockHeightHandler {
+	return &BlockHeightHandler{next: getNext(next)}
+}
+
+type peerHeight struct {
+	peer   fab.Peer
+	height uint64
+}
+
+// peersAtHeight returns the peers which have reached the given height, the highest first. The heights of the
+// peers are queried concurrently, each within the given timeout, unless they were queried within maxAge. If no
+// provider is given then the heights reported by the peers (see fab.PeerState) are used.
+func (h *BlockHeightHandler) peersAtHeight(reqCtx reqContext.Context, provider BlockHeightProvider, peers []fab.Peer, minHeight uint64, timeout, maxAge time.Duration) ([]fab.Peer, error) {
+	results := make([]peerHeight, len(peers))
+	errs := make([]error, len(peers))
+
+	var wg sync.WaitGroup
+	for i, p := range peers {
+		wg.Add(1)
+		go func(i int, p fab.Peer) {
+			defer wg.Done()
+
+			peerCtx, cancel := reqContext.WithTimeout(reqCtx, timeout)
+			defer cancel()
+
+			height, err := h.blockHeight(peerCtx, provider, p, maxAge)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if height < minHeight {
+				errs[i] = errors.Errorf("peer [%s] is at height %d", p.URL(), height)
+				return
+			}
+			results[i] = peerHeight{peer: p, height: height}
+		}(i, p)
+	}
+	wg.Wait()
+
+	var heights []peerHeight
+	var err error
+	for i, result := range results {
+		if errs[i] != nil {
+			err = multi.Append(err, errs[i])
+			continue
+		}
+		heights = append(heights, result)
+	}
+
+	sort.SliceStable(heights, func(i, j int) bool { return heights[i].height > heights[j].height })
+
+	targets := make([]fab.Peer, len(heights))
+	for i, h := range heights {
+		targets[i] = h.peer
+	}
+	return targets, err
+}
+
+// blockHeight returns the cached height of the peer if it was queried within maxAge; otherwise the height
+// is queried and cached
+func (h *BlockHeightHandler) blockHeight(reqCtx reqContext.Context, provider BlockHeightProvider, peer fab.Peer, maxAge time.Duration) (uint64, error) {
+	h.mutex.Lock()
+	cached, ok := h.heights[peer.URL()]
+	h.mutex.Unlock()
+	if ok && time.Since(cached.fetched) < maxAge {
+		return cached.height, nil
+	}
+
+	height, err := peerBlockHeight(reqCtx, provider, peer)
+	if err != nil {
+		return 0, err
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.heights == nil {
+		h.heights = make(map[string]cachedHeight)
+	}
+	h.heights[peer.URL()] = cachedHeight{height: height, fetched: time.Now()}
+	return height, nil
+}
+
+func peerBlockHeight(reqCtx reqContext.Context, provider BlockHeightProvider, peer fab.Peer) (uint64, error) {
+	if provider != nil {
+		height, err := heightWithContext(reqCtx, provider, peer)
+		if err != nil {
+			return 0, errors.WithMessage(err, "failed to get the block height of peer ["+peer.URL()+"]")
+		}
+		return height, nil
+	}
+	if state, ok := peer.(fab.PeerState); ok {
+		return state.BlockHeight(), nil
+	}
+	return 0, errors.Errorf("peer [%s] doesn't report its block height", peer.URL())
+}
+
+// heightWithContext calls the provider and returns when it returns or the context is done, whichever
+// happens first, so that a provider which doesn't honour the context can't block the caller
+func heightWithContext(reqCtx reqContext.Context, provider BlockHeightProvider, peer fab.Peer) (uint64, error) {
+	type result struct {
+		height uint64
+		err    error
+	}
+
+	resultCh := make(chan result, 1)
+	go func() {
+		height, err := provider(reqCtx, peer)
+		resultCh <- result{height: height, err: err}
+	}()
+
+	select {
+	case r := <-resultCh:
+		return r.height, r.err
+	case <-reqCtx.Done():
+		return 0, errors.Wrap(reqCtx.Err(), "block height query timed out")
+	}
+}
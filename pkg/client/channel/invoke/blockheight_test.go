@@ -0,0 +1,261 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	reqContext "context"
+	"sync"
+	"testing"
+	"time"
+
+	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockHeightHandler(t *testing.T) {
+	peer1 := fcmocks.NewMockPeer("p1", "peer1:7051")
+	peer2 := fcmocks.NewMockPeer("p2", "peer2:7051")
+	peer3 := fcmocks.NewMockPeer("p3", "peer3:7051")
+	heights := &mockHeights{heights: map[string]uint64{"peer1:7051": 5, "peer2:7051": 10, "peer3:7051": 12}}
+
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+	clientContext.BlockHeight = heights.get
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+	targets := []fab.Peer{peer1, peer2, peer3}
+
+	t.Run("No minimum height", func(t *testing.T) {
+		requestContext := prepareRequestContext(request, Opts{Targets: targets}, t)
+		next := &targetsHandler{}
+		NewBlockHeightHandler(next).Handle(requestContext, clientContext)
+		require.NoError(t, requestContext.Error)
+		assert.Equal(t, targets, next.targets)
+	})
+
+	t.Run("Most up-to-date peer", func(t *testing.T) {
+		requestContext := prepareRequestContext(request, Opts{Targets: targets, MinBlockHeight: 8}, t)
+		next := &targetsHandler{}
+		NewBlockHeightHandler(next).Handle(requestContext, clientContext)
+		require.NoError(t, requestContext.Error)
+		assert.Equal(t, []fab.Peer{peer3}, next.targets)
+		assert.Equal(t, targets, requestContext.Opts.Targets, "expecting the targets of the request to be unchanged")
+	})
+
+	t.Run("Hedged", func(t *testing.T) {
+		requestContext := prepareRequestContext(request, Opts{Targets: targets, MinBlockHeight: 8, HedgeDelay: time.Second}, t)
+		next := &targetsHandler{}
+		NewBlockHeightHandler(next).Handle(requestContext, clientContext)
+		require.NoError(t, requestContext.Error)
+		assert.Equal(t, []fab.Peer{peer3, peer2}, next.targets, "expecting the peers at the minimum height, highest first")
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		requestContext := prepareRequestContext(request, Opts{Targets: targets, MinBlockHeight: 20}, t)
+		ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 50*time.Millisecond)
+		defer cancel()
+		requestContext.Ctx = ctx
+
+		handler := &BlockHeightHandler{pollInterval: 10 * time.Millisecond}
+		handler.Handle(requestContext, clientContext)
+		require.Error(t, requestContext.Error)
+		s, ok := status.FromError(requestContext.Error)
+		require.True(t, ok)
+		assert.Equal(t, status.Timeout.ToInt32(), s.Code)
+		assert.Contains(t, requestContext.Error.Error(), "no peer reached block height 20")
+		assert.Contains(t, requestContext.Error.Error(), "peer [peer3:7051] is at height 12")
+	})
+}
+
+func TestBlockHeightHandlerWait(t *testing.T) {
+	peer1 := fcmocks.NewMockPeer("p1", "peer1:7051")
+	heights := &mockHeights{heights: map[string]uint64{"peer1:7051": 5}}
+
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+	clientContext.BlockHeight = heights.get
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+	requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{peer1}, MinBlockHeight: 6}, t)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		heights.set("peer1:7051", 6)
+	}()
+
+	next := &targetsHandler{}
+	handler := &BlockHeightHandler{next: next, pollInterval: 10 * time.Millisecond}
+	handler.Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []fab.Peer{peer1}, next.targets)
+}
+
+func TestBlockHeightHandlerPeerState(t *testing.T) {
+	peer1 := &statePeer{MockPeer: fcmocks.NewMockPeer("p1", "peer1:7051"), height: 5}
+	peer2 := &statePeer{MockPeer: fcmocks.NewMockPeer("p2", "peer2:7051"), height: 7}
+	peer3 := fcmocks.NewMockPeer("p3", "peer3:7051")
+
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+	requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{peer1, peer3, peer2}, MinBlockHeight: 6}, t)
+
+	next := &targetsHandler{}
+	NewBlockHeightHandler(next).Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []fab.Peer{peer2}, next.targets, "expecting the heights reported by the peers to be used without a provider")
+}
+
+func TestBlockHeightHandlerTargetsFallback(t *testing.T) {
+	peer1 := fcmocks.NewMockPeer("p1", "peer1:7051")
+	peer2 := fcmocks.NewMockPeer("p2", "peer2:7051")
+	peer3 := fcmocks.NewMockPeer("p3", "peer3:7051")
+	peer3.MockMSP = "Org2MSP"
+	heights := &mockHeights{heights: map[string]uint64{"peer1:7051": 5, "peer2:7051": 6, "peer3:7051": 9}}
+
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+	clientContext.Discovery = txnmocks.NewMockDiscoveryService(nil, peer1, peer2, peer3)
+	clientContext.BlockHeight = heights.get
+
+	// The explicit target lags behind so the query is sent to another peer of its org
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+	requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{peer1}, MinBlockHeight: 6}, t)
+
+	next := &targetsHandler{}
+	NewBlockHeightHandler(next).Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []fab.Peer{peer2}, next.targets, "expecting a peer of the target's org")
+	assert.Equal(t, []fab.Peer{peer1}, requestContext.Opts.Targets, "expecting the targets of the request to be unchanged")
+}
+
+func TestBlockHeightHandlerUnresponsivePeer(t *testing.T) {
+	peer1 := fcmocks.NewMockPeer("p1", "peer1:7051")
+	peer2 := fcmocks.NewMockPeer("p2", "peer2:7051")
+
+	unresponsive := make(chan struct{})
+	defer close(unresponsive)
+
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+	clientContext.BlockHeight = func(reqCtx reqContext.Context, peer fab.Peer) (uint64, error) {
+		if peer.URL() == peer1.URL() {
+			<-unresponsive
+		}
+		return 8, nil
+	}
+
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+	requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{peer1, peer2}, MinBlockHeight: 8}, t)
+
+	start := time.Now()
+	next := &targetsHandler{}
+	handler := &BlockHeightHandler{next: next, timeout: 50 * time.Millisecond}
+	handler.Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []fab.Peer{peer2}, next.targets)
+	assert.True(t, time.Since(start) < time.Second, "expecting the height query of the unresponsive peer to time out")
+}
+
+func TestBlockHeightHandlerRetry(t *testing.T) {
+	peer1 := fcmocks.NewMockPeer("p1", "peer1:7051")
+	peer2 := fcmocks.NewMockPeer("p2", "peer2:7051")
+	heights := &mockHeights{heights: map[string]uint64{"peer1:7051": 8, "peer2:7051": 5}}
+
+	var queries int
+	var mutex sync.Mutex
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+	clientContext.BlockHeight = func(reqCtx reqContext.Context, peer fab.Peer) (uint64, error) {
+		mutex.Lock()
+		queries++
+		mutex.Unlock()
+		return heights.get(reqCtx, peer)
+	}
+
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+	requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{peer1, peer2}, MinBlockHeight: 6, HedgeDelay: time.Second}, t)
+
+	next := &targetsHandler{}
+	handler := &BlockHeightHandler{next: next, pollInterval: time.Minute}
+	handler.Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []fab.Peer{peer1}, next.targets)
+
+	// A retry (with the same request context) considers all of the original targets again and
+	// reuses the heights which were queried within the poll interval
+	heights.set("peer2:7051", 9)
+	handler.Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []fab.Peer{peer1}, next.targets, "expecting the cached heights to be used")
+	assert.Equal(t, 2, queries)
+
+	handler = &BlockHeightHandler{next: next, pollInterval: time.Minute}
+	handler.Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []fab.Peer{peer2, peer1}, next.targets, "expecting the heights to be queried by a new handler")
+}
+
+func TestQueryHandlerMinBlockHeight(t *testing.T) {
+	peer1 := fcmocks.NewMockPeer("p1", "peer1:7051")
+	peer2 := fcmocks.NewMockPeer("p2", "peer2:7051")
+	peer3 := fcmocks.NewMockPeer("p3", "peer3:7051")
+	peer3.Payload = []byte("latest")
+	heights := &mockHeights{heights: map[string]uint64{"peer1:7051": 5, "peer2:7051": 5, "peer3:7051": 6}}
+
+	// The selected endorsers lag behind so the query is sent to another peer of their org
+	clientContext := setupChannelClientContext(nil, nil, []fab.Peer{peer1, peer2}, t)
+	clientContext.Discovery = txnmocks.NewMockDiscoveryService(nil, peer1, peer2, peer3)
+	clientContext.BlockHeight = heights.get
+
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+	requestContext := prepareRequestContext(request, Opts{MinBlockHeight: 6}, t)
+
+	NewQueryHandler().Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []byte("latest"), requestContext.Response.Payload)
+	assert.Equal(t, 0, peer1.ProcessProposalCalls)
+	assert.Equal(t, 0, peer2.ProcessProposalCalls)
+}
+
+// targetsHandler records the targets passed to it
+type targetsHandler struct {
+	targets []fab.Peer
+}
+
+func (h *targetsHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	h.targets = requestContext.Opts.Targets
+}
+
+type mockHeights struct {
+	mutex   sync.RWMutex
+	heights map[string]uint64
+}
+
+func (m *mockHeights) get(reqCtx reqContext.Context, peer fab.Peer) (uint64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	height, ok := m.heights[peer.URL()]
+	if !ok {
+		return 0, errors.New("peer not found")
+	}
+	return height, nil
+}
+
+func (m *mockHeights) set(url string, height uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.heights[url] = height
+}
+
+// statePeer is a mock peer which reports its ledger height
+type statePeer struct {
+	*fcmocks.MockPeer
+	height uint64
+}
+
+func (p *statePeer) BlockHeight() uint64 {
+	return p.height
+}
@@ -111,6 +111,9 @@ func getEndorsers(requestContext *RequestContext, clientContext *ClientContext,
 	if requestContext.SelectionFilter != nil {
 		selectionOpts = append(selectionOpts, selectopts.WithPeerFilter(requestContext.SelectionFilter))
 	}
+	if len(requestContext.Opts.EndorsingOrgs) > 0 {
+		selectionOpts = append(selectionOpts, selectopts.WithEndorsingOrgs(requestContext.Opts.EndorsingOrgs...))
+	}
 
 	ccCalls := newInvocationChain(requestContext)
 	peers, err := clientContext.Selection.GetEndorsersForChaincode(newInvocationChain(requestContext), selectionOpts...)
@@ -182,13 +185,33 @@ func getInvocationChainFromResponse(response *fab.TransactionProposalResponse) (
 		for j, collRWSet := range rwSet.CollHashedRwSets {
 			collections[j] = collRWSet.CollectionName
 		}
-		logger.Debugf("Found chaincode in RWSet [%s], Collections %v", rwSet.NameSpace, collections)
-		invocationChain[i] = &fab.ChaincodeCall{ID: rwSet.NameSpace, Collections: collections}
+		ccCall := &fab.ChaincodeCall{ID: rwSet.NameSpace, Collections: collections}
+		if len(collections) > 0 {
+			ccCall.NoPrivateReads = !hasPrivateReads(rwSet)
+			ccCall.NoPublicWrites = !hasPublicWrites(rwSet)
+		}
+		logger.Debugf("Found chaincode in RWSet [%s], Collections %v, NoPrivateReads: %t, NoPublicWrites: %t", rwSet.NameSpace, collections, ccCall.NoPrivateReads, ccCall.NoPublicWrites)
+		invocationChain[i] = ccCall
 	}
 
 	return invocationChain, nil
 }
 
+// hasPrivateReads returns true if the chaincode read private data from any of the collections
+func hasPrivateReads(rwSet *rwsetutil.NsRwSet) bool {
+	for _, collRWSet := range rwSet.CollHashedRwSets {
+		if collRWSet.HashedRwSet != nil && len(collRWSet.HashedRwSet.HashedReads) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPublicWrites returns true if the chaincode wrote public state (including key metadata)
+func hasPublicWrites(rwSet *rwsetutil.NsRwSet) bool {
+	return rwSet.KvRwSet != nil && (len(rwSet.KvRwSet.Writes) > 0 || len(rwSet.KvRwSet.MetadataWrites) > 0)
+}
+
 func getRWSetsFromProposalResponse(response *pb.ProposalResponse) ([]*rwsetutil.NsRwSet, error) {
 	if response == nil {
 		return nil, nil
@@ -232,6 +255,14 @@ func mergeInvocationChains(invocChain []*fab.ChaincodeCall, respInvocChain []*fa
 		}
 		mergedInvocChain = append(mergedInvocChain, mergedCCCall)
 	}
+
+	// A chaincode of the original invocation chain may be missing from the RW set (e.g. a nested chaincode
+	// which didn't touch any state). Keep it so that its collections are still taken into account.
+	for _, ccCall := range invocChain {
+		if _, ok := getCCCall(mergedInvocChain, ccCall.ID); !ok {
+			mergedInvocChain = append(mergedInvocChain, ccCall)
+		}
+	}
 	return mergedInvocChain, changed
 }
 
@@ -269,8 +300,10 @@ func getCCCall(invocChain []*fab.ChaincodeCall, ccID string) (*fab.ChaincodeCall
 
 // merge merges the collections from c1 and c2 and returns the resulting ChaincodeCall.
 // true is returned if a merge was necessary; false is returned if the two ChaincodeCalls were the same.
+// The dissemination hints are taken from c2 since they're derived from the RW set. A change of the hints
+// alone doesn't require a merge since the hints only relax the requirements for the endorsers.
 func merge(c1 *fab.ChaincodeCall, c2 *fab.ChaincodeCall) (*fab.ChaincodeCall, bool) {
-	c := &fab.ChaincodeCall{ID: c1.ID, Collections: c1.Collections}
+	c := &fab.ChaincodeCall{ID: c1.ID, Collections: c1.Collections, NoPrivateReads: c2.NoPrivateReads, NoPublicWrites: c2.NoPublicWrites}
 	merged := false
 	for _, coll := range c2.Collections {
 		if !contains(c.Collections, coll) {
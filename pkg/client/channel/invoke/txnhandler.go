@@ -8,11 +8,15 @@ package invoke
 
 import (
 	"bytes"
+	reqContext "context"
+	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/pkg/errors"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/inspect"
 	selectopts "github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
@@ -23,7 +27,8 @@ import (
 
 //EndorsementHandler for handling endorse transactions
 type EndorsementHandler struct {
-	next Handler
+	next  Handler
+	hedge bool
 }
 
 //Handle for endorsing transactions
@@ -35,8 +40,19 @@ func (e *EndorsementHandler) Handle(requestContext *RequestContext, clientContex
 	}
 
 	// Endorse Tx
-	transactionProposalResponses, proposal, err := createAndSendTransactionProposal(clientContext.Transactor, &requestContext.Request, peer.PeersToTxnProcessors(requestContext.Opts.Targets))
+	var transactionProposalResponses []*fab.TransactionProposalResponse
+	var proposal *fab.TransactionProposal
+	var err error
+	if e.hedge && requestContext.Opts.HedgeDelay > 0 && len(requestContext.Opts.Targets) > 1 {
+		transactionProposalResponses, proposal, err = createAndSendHedgedTransactionProposal(requestContext.Ctx, clientContext.Transactor, &requestContext.Request, requestContext.Opts.Targets, requestContext.Opts.HedgeDelay)
+	} else {
+		transactionProposalResponses, proposal, err = createAndSendTransactionProposal(clientContext.Transactor, &requestContext.Request, peer.PeersToTxnProcessors(requestContext.Opts.Targets))
+	}
 
+	if proposal == nil {
+		requestContext.Error = err
+		return
+	}
 	requestContext.Response.Proposal = proposal
 	requestContext.Response.TransactionID = proposal.TxnID // TODO: still needed?
 
@@ -49,6 +65,15 @@ func (e *EndorsementHandler) Handle(requestContext *RequestContext, clientContex
 	if len(transactionProposalResponses) > 0 {
 		requestContext.Response.Payload = transactionProposalResponses[0].ProposalResponse.GetResponse().Payload
 		requestContext.Response.ChaincodeStatus = transactionProposalResponses[0].ChaincodeStatus
+
+		if requestContext.Opts.IncludeRWSets || requestContext.Opts.SimulationOnly {
+			rwSets, err := inspect.DecodeProposalResponseRWSets(transactionProposalResponses[0].ProposalResponse.GetPayload())
+			if err != nil {
+				requestContext.Error = errors.WithMessage(err, "failed to decode read-write sets")
+				return
+			}
+			requestContext.Response.RWSets = rwSets
+		}
 	}
 
 	//Delegate to next step if any
@@ -59,7 +84,8 @@ func (e *EndorsementHandler) Handle(requestContext *RequestContext, clientContex
 
 //ProposalProcessorHandler for selecting proposal processors
 type ProposalProcessorHandler struct {
-	next Handler
+	next  Handler
+	query bool
 }
 
 //Handle selects proposal processors
@@ -77,6 +103,10 @@ func (h *ProposalProcessorHandler) Handle(requestContext *RequestContext, client
 			return
 		}
 		requestContext.Opts.Targets = endorsers
+
+		if h.query && (requestContext.Opts.HedgeDelay > 0 || requestContext.Opts.MinBlockHeight > 0) {
+			requestContext.Opts.Targets = append(requestContext.Opts.Targets, backupPeers(requestContext, clientContext)...)
+		}
 	}
 
 	//Delegate to next step if any
@@ -149,6 +179,18 @@ type CommitTxHandler struct {
 func (c *CommitTxHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
 	txnID := requestContext.Response.TransactionID
 
+	if validator := requestContext.Opts.ProposalResponseValidator; validator != nil {
+		if err := validator(requestContext.Response.Responses); err != nil {
+			requestContext.Error = errors.WithMessage(err, "proposal response validation failed")
+			return
+		}
+	}
+
+	if requestContext.Opts.SimulationOnly {
+		c.simulate(requestContext, clientContext)
+		return
+	}
+
 	//Register Tx event
 	reg, statusNotifier, err := clientContext.EventService.RegisterTxStatusEvent(string(txnID)) // TODO: Change func to use TransactionID instead of string
 	if err != nil {
@@ -157,15 +199,17 @@ func (c *CommitTxHandler) Handle(requestContext *RequestContext, clientContext *
 	}
 	defer clientContext.EventService.Unregister(reg)
 
-	_, err = createAndSendTransaction(clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses)
+	txnResponse, err := createAndSendTransaction(clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses)
 	if err != nil {
 		requestContext.Error = errors.Wrap(err, "CreateAndSendTransaction failed")
 		return
 	}
+	requestContext.Response.Orderer = txnResponse.Orderer
 
 	select {
 	case txStatus := <-statusNotifier:
 		requestContext.Response.TxValidationCode = txStatus.TxValidationCode
+		requestContext.Response.BlockNumber = txStatus.BlockNumber
 
 		if txStatus.TxValidationCode != pb.TxValidationCode_VALID {
 			requestContext.Error = status.New(status.EventServerStatus, int32(txStatus.TxValidationCode),
@@ -184,15 +228,32 @@ func (c *CommitTxHandler) Handle(requestContext *RequestContext, clientContext *
 	}
 }
 
-//NewQueryHandler returns query handler with EndorseTxHandler & EndorsementValidationHandler Chained
+// simulate completes the request without submitting the transaction (the read-write sets
+// of the endorsement are set by the endorsement handler)
+func (c *CommitTxHandler) simulate(requestContext *RequestContext, clientContext *ClientContext) {
+	requestContext.Response.TxValidationCode = pb.TxValidationCode_NOT_VALIDATED
+
+	//Delegate to next step if any
+	if c.next != nil {
+		c.next.Handle(requestContext, clientContext)
+	}
+}
+
+//NewQueryHandler returns query handler with EndorseTxHandler & EndorsementValidationHandler Chained.
+//The query is hedged if a hedge delay is specified in the options and is only sent to
+//peers which have reached the minimum block height, if specified.
 func NewQueryHandler(next ...Handler) Handler {
-	return NewProposalProcessorHandler(
-		NewEndorsementHandler(
-			NewEndorsementValidationHandler(
-				NewSignatureValidationHandler(next...),
-			),
+	return &ProposalProcessorHandler{
+		next: NewBlockHeightHandler(
+			&EndorsementHandler{
+				next: NewEndorsementValidationHandler(
+					NewSignatureValidationHandler(next...),
+				),
+				hedge: true,
+			},
 		),
-	)
+		query: true,
+	}
 }
 
 //NewExecuteHandler returns query handler with EndorseTxHandler, EndorsementValidationHandler & CommitTxHandler Chained
@@ -253,24 +314,129 @@ func createAndSendTransaction(sender fab.Sender, proposal *fab.TransactionPropos
 }
 
 func createAndSendTransactionProposal(transactor fab.ProposalSender, chrequest *Request, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, *fab.TransactionProposal, error) {
+	proposal, err := createTransactionProposal(transactor, chrequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transactionProposalResponses, err := transactor.SendTransactionProposal(proposal, targets)
+
+	return transactionProposalResponses, proposal, err
+}
+
+func createTransactionProposal(transactor fab.ProposalSender, chrequest *Request) (*fab.TransactionProposal, error) {
 	request := fab.ChaincodeInvokeRequest{
 		ChaincodeID:  chrequest.ChaincodeID,
 		Fcn:          chrequest.Fcn,
 		Args:         chrequest.Args,
 		TransientMap: chrequest.TransientMap,
+		IsInit:       chrequest.IsInit,
 	}
 
 	txh, err := transactor.CreateTransactionHeader()
 	if err != nil {
-		return nil, nil, errors.WithMessage(err, "creating transaction header failed")
+		return nil, errors.WithMessage(err, "creating transaction header failed")
 	}
 
 	proposal, err := txn.CreateChaincodeInvokeProposal(txh, request)
 	if err != nil {
-		return nil, nil, errors.WithMessage(err, "creating transaction proposal failed")
+		return nil, errors.WithMessage(err, "creating transaction proposal failed")
 	}
+	return proposal, nil
+}
 
-	transactionProposalResponses, err := transactor.SendTransactionProposal(proposal, targets)
+// backupPeers returns the peers which may be queried if the targets are slow to respond
+// or lag behind, i.e. the other peers of the organizations of the targets
+func backupPeers(requestContext *RequestContext, clientContext *ClientContext) []fab.Peer {
+	if clientContext.Discovery == nil {
+		return nil
+	}
 
-	return transactionProposalResponses, proposal, err
+	peers, err := clientContext.Discovery.GetPeers()
+	if err != nil {
+		logger.Warnf("error getting backup peers for query: %s", err)
+		return nil
+	}
+
+	mspIDs := make(map[string]bool)
+	urls := make(map[string]bool)
+	for _, endorser := range requestContext.Opts.Targets {
+		mspIDs[endorser.MSPID()] = true
+		urls[endorser.URL()] = true
+	}
+
+	var backups []fab.Peer
+	for _, p := range peers {
+		if !mspIDs[p.MSPID()] || urls[p.URL()] {
+			continue
+		}
+		if requestContext.SelectionFilter != nil && !requestContext.SelectionFilter(p) {
+			continue
+		}
+		urls[p.URL()] = true
+		backups = append(backups, p)
+	}
+	return backups
+}
+
+type hedgedResponse struct {
+	target    fab.Peer
+	responses []*fab.TransactionProposalResponse
+	err       error
+}
+
+// createAndSendHedgedTransactionProposal sends the proposal to the first target and, each time the
+// delay elapses (or a target fails) without a response, to the next target. The first response is returned.
+func createAndSendHedgedTransactionProposal(ctx reqContext.Context, transactor fab.ProposalSender, chrequest *Request, targets []fab.Peer, delay time.Duration) ([]*fab.TransactionProposalResponse, *fab.TransactionProposal, error) {
+	proposal, err := createTransactionProposal(transactor, chrequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := make(chan hedgedResponse, len(targets))
+	send := func(target fab.Peer) {
+		logger.Debugf("sending hedged query to [%s]", target.URL())
+		go func() {
+			responses, err := transactor.SendTransactionProposal(proposal, []fab.ProposalProcessor{target})
+			results <- hedgedResponse{target: target, responses: responses, err: err}
+		}()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	send(targets[0])
+	sent, pending := 1, 1
+
+	var errs error
+	for pending > 0 {
+		select {
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				return result.responses, proposal, nil
+			}
+			logger.Debugf("hedged query to [%s] failed: %s", result.target.URL(), result.err)
+			errs = multi.Append(errs, result.err)
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, proposal, errors.Wrap(ctx.Err(), "hedged query cancelled")
+		}
+
+		// Send to the next target if the delay elapsed or if a target failed (even if other targets are
+		// still pending), so that a failed target is replaced immediately rather than after the delay
+		if sent < len(targets) {
+			send(targets[sent])
+			sent++
+			pending++
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(delay)
+		}
+	}
+	return nil, proposal, errs
 }
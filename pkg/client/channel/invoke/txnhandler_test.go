@@ -23,6 +23,8 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -86,7 +88,7 @@ func TestExecuteTxHandlerSuccess(t *testing.T) {
 	go func() {
 		select {
 		case txStatusReg := <-mockEventService.TxStatusRegCh:
-			txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: pb.TxValidationCode_VALID}
+			txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: pb.TxValidationCode_VALID, BlockNumber: 12}
 		case <-time.After(requestContext.Opts.Timeouts[fab.Execute]):
 			panic("Execute handler : time out not expected")
 		}
@@ -97,6 +99,61 @@ func TestExecuteTxHandlerSuccess(t *testing.T) {
 	//Perform action through handler
 	executeHandler.Handle(requestContext, clientContext)
 	assert.Nil(t, requestContext.Error)
+	assert.Equal(t, uint64(12), requestContext.Response.BlockNumber)
+}
+
+func TestExecuteTxHandlerProposalResponseValidator(t *testing.T) {
+	request := Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+
+	mockPeer1 := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: 200, Payload: []byte("value")}
+
+	vetoErr := errors.New("value out of range")
+	var validatedResponses []*fab.TransactionProposalResponse
+	validator := func(responses []*fab.TransactionProposalResponse) error {
+		validatedResponses = responses
+		return vetoErr
+	}
+
+	requestContext := prepareRequestContext(request, Opts{ProposalResponseValidator: validator}, t)
+	clientContext := setupChannelClientContext(nil, nil, []fab.Peer{mockPeer1}, t)
+	mockEventService := fcmocks.NewMockEventService()
+	clientContext.EventService = mockEventService
+
+	NewExecuteHandler().Handle(requestContext, clientContext)
+	require.NotNil(t, requestContext.Error, "expecting submission to be vetoed by validator")
+	assert.Contains(t, requestContext.Error.Error(), vetoErr.Error())
+	assert.Len(t, validatedResponses, 1, "expecting validator to receive endorsement responses")
+	assert.Empty(t, mockEventService.TxStatusRegCh, "expecting no transaction to be submitted")
+
+	requestContext = prepareRequestContext(request, Opts{ProposalResponseValidator: func([]*fab.TransactionProposalResponse) error { return nil }}, t)
+	NewExecuteHandler().Handle(requestContext, clientContext)
+	assert.Nil(t, requestContext.Error)
+}
+
+func TestExecuteTxHandlerSimulationOnly(t *testing.T) {
+	request := Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+
+	mockPeer1 := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: 200, Payload: []byte("value")}
+	rwSet := fcmocks.NewRwSet("test")
+	rwSet.KvRwSet.Writes = []*kvrwset.KVWrite{{Key: "a", Value: []byte("9")}}
+	mockPeer1.SetRwSets(rwSet)
+
+	requestContext := prepareRequestContext(request, Opts{SimulationOnly: true}, t)
+	clientContext := setupChannelClientContext(nil, nil, []fab.Peer{mockPeer1}, t)
+	mockEventService := fcmocks.NewMockEventService()
+	clientContext.EventService = mockEventService
+
+	NewExecuteHandler().Handle(requestContext, clientContext)
+	require.Nil(t, requestContext.Error)
+	assert.Empty(t, mockEventService.TxStatusRegCh, "expecting no transaction to be submitted")
+	assert.Equal(t, pb.TxValidationCode_NOT_VALIDATED, requestContext.Response.TxValidationCode)
+	assert.Len(t, requestContext.Response.Responses, 1)
+	assert.Equal(t, []byte("value"), requestContext.Response.Payload)
+	require.Len(t, requestContext.Response.RWSets, 1)
+	assert.Equal(t, "test", requestContext.Response.RWSets[0].Namespace)
+	require.Len(t, requestContext.Response.RWSets[0].Writes, 1)
+	assert.Equal(t, "a", requestContext.Response.RWSets[0].Writes[0].Key)
+	assert.Equal(t, []byte("9"), requestContext.Response.RWSets[0].Writes[0].Value)
 }
 
 func TestQueryHandlerErrors(t *testing.T) {
@@ -134,6 +191,120 @@ func TestQueryHandlerErrors(t *testing.T) {
 	}
 }
 
+func TestQueryHandlerHedging(t *testing.T) {
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+
+	slowPeer := &delayedPeer{MockPeer: fcmocks.NewMockPeer("p1", "peer1:7051"), delay: 2 * time.Second}
+	backupPeer := fcmocks.NewMockPeer("p2", "peer2:7051")
+	backupPeer.Payload = []byte("value")
+
+	requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{slowPeer, backupPeer}, HedgeDelay: 10 * time.Millisecond}, t)
+
+	start := time.Now()
+	NewQueryHandler().Handle(requestContext, setupChannelClientContext(nil, nil, nil, t))
+	require.NoError(t, requestContext.Error)
+	assert.True(t, time.Since(start) < slowPeer.delay, "expecting the response of the backup peer")
+	require.Len(t, requestContext.Response.Responses, 1)
+	assert.Equal(t, "peer2:7051", requestContext.Response.Responses[0].Endorser)
+	assert.Equal(t, []byte("value"), requestContext.Response.Payload)
+}
+
+func TestQueryHandlerHedgingPrimaryResponds(t *testing.T) {
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+
+	peer1 := fcmocks.NewMockPeer("p1", "peer1:7051")
+	peer2 := fcmocks.NewMockPeer("p2", "peer2:7051")
+
+	requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{peer1, peer2}, HedgeDelay: time.Second}, t)
+	NewQueryHandler().Handle(requestContext, setupChannelClientContext(nil, nil, nil, t))
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, 1, peer1.ProcessProposalCalls)
+	assert.Equal(t, 0, peer2.ProcessProposalCalls, "backup peer shouldn't be queried if the primary peer responds in time")
+}
+
+func TestQueryHandlerHedgingPrimaryFails(t *testing.T) {
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+
+	peer1 := fcmocks.NewMockPeer("p1", "peer1:7051")
+	peer1.Error = errors.New("peer1 unavailable")
+	peer2 := fcmocks.NewMockPeer("p2", "peer2:7051")
+
+	requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{peer1, peer2}, HedgeDelay: time.Minute}, t)
+	NewQueryHandler().Handle(requestContext, setupChannelClientContext(nil, nil, nil, t))
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, 1, peer2.ProcessProposalCalls, "backup peer should be queried as soon as the primary peer fails")
+
+	peer2.Error = errors.New("peer2 unavailable")
+	requestContext = prepareRequestContext(request, Opts{Targets: []fab.Peer{peer1, peer2}, HedgeDelay: time.Minute}, t)
+	NewQueryHandler().Handle(requestContext, setupChannelClientContext(nil, nil, nil, t))
+	require.Error(t, requestContext.Error)
+	assert.Contains(t, requestContext.Error.Error(), "peer1 unavailable")
+	assert.Contains(t, requestContext.Error.Error(), "peer2 unavailable")
+}
+
+func TestQueryHandlerHedgingPendingTargetFails(t *testing.T) {
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+
+	slowPeer := &delayedPeer{MockPeer: fcmocks.NewMockPeer("p1", "peer1:7051"), delay: 2 * time.Second}
+	failedPeer := fcmocks.NewMockPeer("p2", "peer2:7051")
+	failedPeer.Error = errors.New("peer2 unavailable")
+	backupPeer := fcmocks.NewMockPeer("p3", "peer3:7051")
+	backupPeer.Payload = []byte("value")
+
+	hedgeDelay := 200 * time.Millisecond
+	requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{slowPeer, failedPeer, backupPeer}, HedgeDelay: hedgeDelay}, t)
+
+	// The second target fails while the first is still pending, so the third target is sent immediately
+	// rather than after another delay
+	start := time.Now()
+	NewQueryHandler().Handle(requestContext, setupChannelClientContext(nil, nil, nil, t))
+	require.NoError(t, requestContext.Error)
+	assert.True(t, time.Since(start) < 2*hedgeDelay-hedgeDelay/4, "expecting the third target to be sent as soon as the second target failed")
+	require.Len(t, requestContext.Response.Responses, 1)
+	assert.Equal(t, "peer3:7051", requestContext.Response.Responses[0].Endorser)
+	assert.Equal(t, 1, failedPeer.ProcessProposalCalls)
+}
+
+func TestQueryHandlerHedgingCancelled(t *testing.T) {
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+
+	peer1 := &delayedPeer{MockPeer: fcmocks.NewMockPeer("p1", "peer1:7051"), delay: 2 * time.Second}
+	peer2 := &delayedPeer{MockPeer: fcmocks.NewMockPeer("p2", "peer2:7051"), delay: 2 * time.Second}
+
+	requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{peer1, peer2}, HedgeDelay: 10 * time.Millisecond}, t)
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 50*time.Millisecond)
+	defer cancel()
+	requestContext.Ctx = ctx
+
+	NewQueryHandler().Handle(requestContext, setupChannelClientContext(nil, nil, nil, t))
+	require.Error(t, requestContext.Error)
+	assert.Contains(t, requestContext.Error.Error(), "hedged query cancelled")
+}
+
+func TestProposalProcessorHandlerHedgingBackups(t *testing.T) {
+	peer1 := fcmocks.NewMockPeer("p1", "peer1:7051")
+	peer2 := fcmocks.NewMockPeer("p2", "peer2:7051")
+	peer3 := fcmocks.NewMockPeer("p3", "peer3:7051")
+	peer3.MockMSP = "Org2MSP"
+	peer4 := fcmocks.NewMockPeer("p4", "peer4:7051")
+
+	clientContext := setupChannelClientContext(nil, nil, []fab.Peer{peer1}, t)
+	clientContext.Discovery = txnmocks.NewMockDiscoveryService(nil, peer1, peer2, peer3, peer4)
+
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+	requestContext := prepareRequestContext(request, Opts{TargetFilter: &filter{peer: peer4, exclude: true}, HedgeDelay: time.Second}, t)
+
+	handler := &ProposalProcessorHandler{query: true}
+	handler.Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []fab.Peer{peer1, peer2}, requestContext.Opts.Targets, "expecting the other peers of the endorser's org which pass the filter as backups")
+
+	requestContext = prepareRequestContext(request, Opts{HedgeDelay: time.Second}, t)
+	NewProposalProcessorHandler().Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []fab.Peer{peer1}, requestContext.Opts.Targets, "backups should only be added for queries")
+}
+
 func TestExecuteTxHandlerErrors(t *testing.T) {
 
 	//Sample request
@@ -176,13 +347,55 @@ func TestEndorsementHandler(t *testing.T) {
 
 }
 
+func TestEndorsementHandlerRWSets(t *testing.T) {
+	request := Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+
+	peer := fcmocks.NewMockPeer("p1", "")
+	rwSet := fcmocks.NewRwSet("test")
+	rwSet.KvRwSet.Reads = []*kvrwset.KVRead{{Key: "a", Version: &kvrwset.Version{BlockNum: 5, TxNum: 2}}}
+	rwSet.CollHashedRwSets = []*rwsetutil.CollHashedRwSet{
+		{
+			CollectionName: "coll1",
+			HashedRwSet: &kvrwset.HashedRWSet{
+				HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte("keyhash"), ValueHash: []byte("valuehash")}},
+			},
+			PvtRwSetHash: []byte("pvthash"),
+		},
+	}
+	peer.SetRwSets(rwSet)
+
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+
+	requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{peer}}, t)
+	NewEndorsementHandler().Handle(requestContext, clientContext)
+	require.Nil(t, requestContext.Error)
+	assert.Nil(t, requestContext.Response.RWSets, "expecting read-write sets only if requested")
+
+	requestContext = prepareRequestContext(request, Opts{Targets: []fab.Peer{peer}, IncludeRWSets: true}, t)
+	NewEndorsementHandler().Handle(requestContext, clientContext)
+	require.Nil(t, requestContext.Error)
+	require.Len(t, requestContext.Response.RWSets, 1)
+
+	nsRWSet := requestContext.Response.RWSets[0]
+	require.Len(t, nsRWSet.Reads, 1)
+	assert.Equal(t, uint64(5), nsRWSet.Reads[0].BlockNum)
+	assert.Equal(t, uint64(2), nsRWSet.Reads[0].TxNum)
+	require.Len(t, nsRWSet.HashedRWSets, 1)
+	assert.Equal(t, "coll1", nsRWSet.HashedRWSets[0].Collection)
+	assert.Equal(t, []byte("pvthash"), nsRWSet.HashedRWSets[0].PvtRWSetHash)
+	require.Len(t, nsRWSet.HashedRWSets[0].Writes, 1)
+	assert.Equal(t, []byte("keyhash"), nsRWSet.HashedRWSets[0].Writes[0].KeyHash)
+	assert.Equal(t, []byte("valuehash"), nsRWSet.HashedRWSets[0].Writes[0].ValueHash)
+}
+
 // Target filter
 type filter struct {
-	peer fab.Peer
+	peer    fab.Peer
+	exclude bool
 }
 
 func (f *filter) Accept(p fab.Peer) bool {
-	return p.URL() == f.peer.URL()
+	return (p.URL() == f.peer.URL()) != f.exclude
 }
 
 func TestResponseValidation(t *testing.T) {
@@ -379,6 +592,62 @@ func TestMergeInvocationChains(t *testing.T) {
 	})
 }
 
+func TestMergeInvocationChainsKeepsOriginalCalls(t *testing.T) {
+	ccCall1 := &fab.ChaincodeCall{ID: "cc1"}
+	ccCall2 := &fab.ChaincodeCall{ID: "cc2", Collections: []string{"col1"}}
+	respCCCall1 := &fab.ChaincodeCall{ID: "cc1", Collections: []string{"col2"}, NoPrivateReads: true}
+
+	invocChain, changed := mergeInvocationChains([]*fab.ChaincodeCall{ccCall1, ccCall2}, []*fab.ChaincodeCall{respCCCall1}, func(ccID string) bool { return true })
+	assert.True(t, changed)
+	require.Len(t, invocChain, 2)
+	assert.Equal(t, &fab.ChaincodeCall{ID: "cc1", Collections: []string{"col2"}, NoPrivateReads: true}, invocChain[0])
+	assert.Equal(t, ccCall2, invocChain[1], "expecting chaincode which isn't in the RW set to be kept")
+
+	// A change of the hints alone doesn't require additional endorsements
+	_, changed = mergeInvocationChains([]*fab.ChaincodeCall{respCCCall1}, []*fab.ChaincodeCall{{ID: "cc1", Collections: []string{"col2"}}}, func(ccID string) bool { return true })
+	assert.False(t, changed)
+}
+
+func TestInvocationChainFromResponse(t *testing.T) {
+	request := Request{ChaincodeID: "cc1", Fcn: "invoke"}
+
+	rwSet1 := fcmocks.NewRwSet("cc1")
+	rwSet1.KvRwSet.Writes = []*kvrwset.KVWrite{{Key: "a", Value: []byte("value")}}
+	rwSet2 := fcmocks.NewRwSet("cc2")
+	rwSet2.CollHashedRwSets = []*rwsetutil.CollHashedRwSet{
+		{
+			CollectionName: "coll1",
+			HashedRwSet: &kvrwset.HashedRWSet{
+				HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte("keyhash"), ValueHash: []byte("valuehash")}},
+			},
+		},
+	}
+	rwSet3 := fcmocks.NewRwSet("cc3")
+	rwSet3.KvRwSet.Writes = []*kvrwset.KVWrite{{Key: "b", Value: []byte("value")}}
+	rwSet3.CollHashedRwSets = []*rwsetutil.CollHashedRwSet{
+		{
+			CollectionName: "coll2",
+			HashedRwSet: &kvrwset.HashedRWSet{
+				HashedReads: []*kvrwset.KVReadHash{{KeyHash: []byte("keyhash")}},
+			},
+		},
+	}
+
+	peer := fcmocks.NewMockPeer("p1", "")
+	peer.SetRwSets(rwSet1, rwSet2, rwSet3)
+
+	requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{peer}}, t)
+	NewEndorsementHandler().Handle(requestContext, setupChannelClientContext(nil, nil, nil, t))
+	require.Nil(t, requestContext.Error)
+
+	invocChain, err := getInvocationChainFromResponse(requestContext.Response.Responses[0])
+	require.NoError(t, err)
+	require.Len(t, invocChain, 3)
+	assert.Equal(t, &fab.ChaincodeCall{ID: "cc1", Collections: []string{}}, invocChain[0])
+	assert.Equal(t, &fab.ChaincodeCall{ID: "cc2", Collections: []string{"coll1"}, NoPrivateReads: true, NoPublicWrites: true}, invocChain[1])
+	assert.Equal(t, &fab.ChaincodeCall{ID: "cc3", Collections: []string{"coll2"}}, invocChain[2])
+}
+
 //prepareHandlerContexts prepares context objects for handlers
 func prepareRequestContext(request Request, opts Opts, t *testing.T) *RequestContext {
 	requestContext := &RequestContext{Request: request,
@@ -423,3 +692,14 @@ func setupTestContext() context.Client {
 	ctx := fcmocks.NewMockContext(user)
 	return ctx
 }
+
+// delayedPeer is a mock peer which is slow to respond
+type delayedPeer struct {
+	*fcmocks.MockPeer
+	delay time.Duration
+}
+
+func (p *delayedPeer) ProcessTransactionProposal(ctx reqContext.Context, tp fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	time.Sleep(p.delay)
+	return p.MockPeer.ProcessTransactionProposal(ctx, tp)
+}
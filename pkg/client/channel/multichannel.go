@@ -0,0 +1,108 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
+	"github.com/pkg/errors"
+)
+
+// MultiChannelClient enables access to any number of channels on a Fabric network and
+// routes each request to the channel client of the given channel.
+//
+// The channel clients are created on demand and, when the multi-channel context is provided
+// by the SDK (see fabsdk.MultiChannelContext), they share the identity, the connections and
+// the discovery/selection caches.
+type MultiChannelClient struct {
+	clients *lazycache.Cache
+}
+
+type channelKey string
+
+func (k channelKey) String() string {
+	return string(k)
+}
+
+// NewMultiChannel returns a MultiChannelClient instance. The given options are applied to each channel client.
+func NewMultiChannel(channelProvider context.MultiChannelProvider, opts ...ClientOption) (*MultiChannelClient, error) {
+	if channelProvider == nil {
+		return nil, errors.New("multi-channel provider is required")
+	}
+
+	return &MultiChannelClient{
+		clients: lazycache.New(
+			"Channel_Client_Cache",
+			func(key lazycache.Key) (interface{}, error) {
+				channelID := key.String()
+				return New(
+					func() (context.Channel, error) {
+						return channelProvider(channelID)
+					},
+					opts...,
+				)
+			},
+		),
+	}, nil
+}
+
+// Channel returns the channel client for the given channel
+func (mc *MultiChannelClient) Channel(channelID string) (*Client, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+
+	client, err := mc.clients.Get(channelKey(channelID))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel client for channel "+channelID)
+	}
+	return client.(*Client), nil
+}
+
+// Query chaincode on the given channel using request and optional request options
+func (mc *MultiChannelClient) Query(channelID string, request Request, options ...RequestOption) (Response, error) {
+	client, err := mc.Channel(channelID)
+	if err != nil {
+		return Response{}, err
+	}
+	return client.Query(request, options...)
+}
+
+// Execute prepares and executes transaction on the given channel using request and optional request options
+func (mc *MultiChannelClient) Execute(channelID string, request Request, options ...RequestOption) (Response, error) {
+	client, err := mc.Channel(channelID)
+	if err != nil {
+		return Response{}, err
+	}
+	return client.Execute(request, options...)
+}
+
+// RegisterChaincodeEvent registers for chaincode events on the given channel.
+// Unregister must be called when the registration is no longer needed.
+func (mc *MultiChannelClient) RegisterChaincodeEvent(channelID, chainCodeID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	client, err := mc.Channel(channelID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client.RegisterChaincodeEvent(chainCodeID, eventFilter)
+}
+
+// UnregisterChaincodeEvent removes the given registration on the given channel and closes the event channel
+func (mc *MultiChannelClient) UnregisterChaincodeEvent(channelID string, registration fab.Registration) error {
+	client, err := mc.Channel(channelID)
+	if err != nil {
+		return err
+	}
+	client.UnregisterChaincodeEvent(registration)
+	return nil
+}
+
+// Close releases the channel clients. The client may not be used after it is closed.
+func (mc *MultiChannelClient) Close() {
+	mc.clients.Close()
+}
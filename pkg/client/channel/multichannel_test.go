@@ -0,0 +1,63 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+
+	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiChannelClient(t *testing.T) {
+	_, err := NewMultiChannel(nil)
+	assert.Error(t, err)
+
+	testPeer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	fabCtx := setupCustomTestContext(t, txnmocks.NewMockSelectionService(nil, testPeer), txnmocks.NewMockDiscoveryService(nil), nil)
+
+	var requested []string
+	mc, err := NewMultiChannel(func(channelID string) (context.Channel, error) {
+		requested = append(requested, channelID)
+		if channelID == "unknown" {
+			return nil, errors.New("channel not found")
+		}
+		return createChannelContext(fabCtx, channelID)()
+	})
+	require.NoError(t, err)
+	defer mc.Close()
+
+	_, err = mc.Channel("")
+	assert.Error(t, err)
+
+	_, err = mc.Query("unknown", Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}})
+	assert.Error(t, err)
+
+	_, err = mc.Query("channel1", Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}})
+	assert.NoError(t, err)
+	_, err = mc.Execute("channel2", Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}})
+	assert.NoError(t, err)
+	_, err = mc.Query("channel1", Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}})
+	assert.NoError(t, err)
+
+	client1, err := mc.Channel("channel1")
+	require.NoError(t, err)
+	client2, err := mc.Channel("channel2")
+	require.NoError(t, err)
+	assert.Equal(t, "channel1", client1.context.ChannelID())
+	assert.Equal(t, "channel2", client2.context.ChannelID())
+
+	assert.Equal(t, []string{"unknown", "channel1", "channel2"}, requested, "expecting one channel client per channel")
+
+	reg, _, err := mc.RegisterChaincodeEvent("channel1", "testCC", "event")
+	require.NoError(t, err)
+	assert.NoError(t, mc.UnregisterChaincodeEvent("channel1", reg))
+}
@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// PageDecoder extracts the number of records returned in a page along with
+// the bookmark that is to be used for retrieving the next page.
+// The payload is the chaincode response payload for the page.
+type PageDecoder func(payload []byte) (count int, bookmark string, err error)
+
+// PageIterator iterates over the pages of a chaincode query that implements Fabric's
+// pagination pattern, i.e. the chaincode function accepts a page size and a bookmark
+// as its last two arguments and returns the bookmark of the next page in its response.
+//
+// Successive Query calls are issued transparently until the results are exhausted:
+//  it := client.NewPageIterator(request, 10, decoder)
+//  for it.Next() {
+//      resp := it.Response()
+//      ...
+//  }
+//  if err := it.Err(); err != nil {
+//      ...
+//  }
+type PageIterator struct {
+	query    func(request Request, options ...RequestOption) (Response, error)
+	request  Request
+	pageSize int32
+	decoder  PageDecoder
+	options  []RequestOption
+
+	bookmark string
+	response Response
+	err      error
+	done     bool
+}
+
+// NewPageIterator returns an iterator over the pages of the given query request.
+//  Parameters:
+//  request holds info about mandatory chaincode ID and function. The page size and bookmark
+//  are appended to the request arguments for each page.
+//  pageSize is the maximum number of records per page
+//  decoder extracts the record count and next bookmark from each page's payload
+//  options holds optional request options that are applied to every page query
+//
+//  Returns:
+//  the page iterator
+func (cc *Client) NewPageIterator(request Request, pageSize int32, decoder PageDecoder, options ...RequestOption) *PageIterator {
+	return NewQueryPageIterator(cc.Query, request, pageSize, decoder, options...)
+}
+
+// NewQueryPageIterator returns an iterator over the pages of the given query request which are
+// retrieved with the given query function (e.g. the Query function of a fake channel client)
+func NewQueryPageIterator(query func(request Request, options ...RequestOption) (Response, error), request Request, pageSize int32, decoder PageDecoder, options ...RequestOption) *PageIterator {
+	return &PageIterator{
+		query:    query,
+		request:  request,
+		pageSize: pageSize,
+		decoder:  decoder,
+		options:  options,
+	}
+}
+
+// WithBookmark sets the bookmark from which iteration starts. This allows an
+// iteration to be resumed from a previously retrieved bookmark.
+func (it *PageIterator) WithBookmark(bookmark string) *PageIterator {
+	it.bookmark = bookmark
+	return it
+}
+
+// Next retrieves the next page. False is returned when there are no more pages
+// or if an error occurred (in which case Err returns the error).
+func (it *PageIterator) Next() bool {
+	if it.done {
+		return false
+	}
+
+	if it.pageSize <= 0 {
+		return it.fail(errors.New("page size must be greater than zero"))
+	}
+	if it.decoder == nil {
+		return it.fail(errors.New("page decoder is required"))
+	}
+
+	request := it.request
+	request.Args = make([][]byte, 0, len(it.request.Args)+2)
+	request.Args = append(request.Args, it.request.Args...)
+	request.Args = append(request.Args, []byte(strconv.FormatInt(int64(it.pageSize), 10)), []byte(it.bookmark))
+
+	response, err := it.query(request, it.options...)
+	if err != nil {
+		return it.fail(err)
+	}
+
+	count, bookmark, err := it.decoder(response.Payload)
+	if err != nil {
+		return it.fail(errors.WithMessage(err, "failed to decode page"))
+	}
+
+	if count == 0 {
+		it.done = true
+		return false
+	}
+
+	if bookmark == "" || bookmark == it.bookmark || count < int(it.pageSize) {
+		// This is the last page
+		it.done = true
+	}
+
+	it.bookmark = bookmark
+	it.response = response
+
+	return true
+}
+
+// Response returns the query response for the current page
+func (it *PageIterator) Response() Response {
+	return it.response
+}
+
+// Bookmark returns the bookmark of the next page
+func (it *PageIterator) Bookmark() string {
+	return it.bookmark
+}
+
+// Err returns the error, if any, that occurred during iteration
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+func (it *PageIterator) fail(err error) bool {
+	it.err = err
+	it.done = true
+	return false
+}
@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+func TestPageIterator(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("page")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+
+	var pages int
+	decoder := func(payload []byte) (int, string, error) {
+		pages++
+		if pages < 3 {
+			return 10, fmt.Sprintf("bookmark%d", pages), nil
+		}
+		return 4, fmt.Sprintf("bookmark%d", pages), nil
+	}
+
+	it := chClient.NewPageIterator(Request{ChaincodeID: "testCC", Fcn: "query", Args: [][]byte{[]byte("q")}}, 10, decoder)
+
+	var received int
+	for it.Next() {
+		received++
+		assert.Equal(t, []byte("page"), it.Response().Payload)
+		assert.Equal(t, fmt.Sprintf("bookmark%d", received), it.Bookmark())
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 3, received, "expecting iteration to stop after a partial page")
+	assert.Equal(t, 3, testPeer1.ProcessProposalCalls)
+	assert.False(t, it.Next(), "expecting no more pages")
+}
+
+func TestPageIteratorEmptyPage(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+
+	decoder := func(payload []byte) (int, string, error) {
+		return 0, "", nil
+	}
+
+	it := chClient.NewPageIterator(Request{ChaincodeID: "testCC", Fcn: "query"}, 10, decoder).WithBookmark("start")
+	assert.False(t, it.Next(), "expecting no pages")
+	assert.NoError(t, it.Err())
+}
+
+func TestPageIteratorErrors(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+
+	it := chClient.NewPageIterator(Request{ChaincodeID: "testCC", Fcn: "query"}, 0, nil)
+	assert.False(t, it.Next())
+	assert.Error(t, it.Err(), "expecting error for invalid page size")
+
+	it = chClient.NewPageIterator(Request{ChaincodeID: "testCC", Fcn: "query"}, 10, nil)
+	assert.False(t, it.Next())
+	assert.Error(t, it.Err(), "expecting error for nil decoder")
+
+	decodeErr := errors.New("decode error")
+	it = chClient.NewPageIterator(Request{ChaincodeID: "testCC", Fcn: "query"}, 10, func([]byte) (int, string, error) {
+		return 0, "", decodeErr
+	})
+	assert.False(t, it.Next())
+	assert.Error(t, it.Err())
+	assert.Contains(t, it.Err().Error(), decodeErr.Error())
+
+	it = chClient.NewPageIterator(Request{ChaincodeID: "testCC"}, 10, func([]byte) (int, string, error) {
+		return 1, "", nil
+	})
+	assert.False(t, it.Next())
+	assert.Error(t, it.Err(), "expecting query error for missing function")
+}
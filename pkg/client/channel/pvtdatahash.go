@@ -0,0 +1,51 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"github.com/pkg/errors"
+)
+
+// PrivateDataHash returns the hash of a private data value as it's recorded on the ledger, i.e. the
+// value returned by GetPrivateDataHash in the chaincode
+func PrivateDataHash(value []byte) []byte {
+	hash := sha256.Sum256(value)
+	return hash[:]
+}
+
+// VerifyPrivateDataHash returns an error unless the given value matches the given private data hash
+func VerifyPrivateDataHash(hash, value []byte) error {
+	if len(hash) == 0 {
+		return errors.New("private data hash not found")
+	}
+	if !bytes.Equal(hash, PrivateDataHash(value)) {
+		return errors.Errorf("private data value doesn't match the hash [%x] on the ledger", hash)
+	}
+	return nil
+}
+
+// VerifyPrivateData verifies a private data value which was received off-chain against the hash of the
+// private data key on the ledger. The request must invoke a chaincode function which returns the result of
+// GetPrivateDataHash for the key. Since the hash is public, the query may be sent to any peer of the channel,
+// including peers which aren't members of the collection.
+//  Parameters:
+//  request holds info about mandatory chaincode ID and function which returns the private data hash
+//  value is the private data value to be verified
+//  options holds optional request options
+//
+//  Returns:
+//  an error if the query fails or the value doesn't match the hash
+func (cc *Client) VerifyPrivateData(request Request, value []byte, options ...RequestOption) error {
+	response, err := cc.Query(request, options...)
+	if err != nil {
+		return errors.WithMessage(err, "query of private data hash failed")
+	}
+	return VerifyPrivateDataHash(response.Payload, value)
+}
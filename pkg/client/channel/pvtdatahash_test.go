@@ -0,0 +1,39 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+func TestVerifyPrivateDataHash(t *testing.T) {
+	hash := sha256.Sum256([]byte("value"))
+	assert.Equal(t, hash[:], PrivateDataHash([]byte("value")))
+
+	assert.NoError(t, VerifyPrivateDataHash(hash[:], []byte("value")))
+	assert.Error(t, VerifyPrivateDataHash(hash[:], []byte("other value")), "expecting error for value which doesn't match the hash")
+	assert.Error(t, VerifyPrivateDataHash(nil, []byte("value")), "expecting error if the key has no hash")
+}
+
+func TestVerifyPrivateData(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = PrivateDataHash([]byte("value"))
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+
+	request := Request{ChaincodeID: "testCC", Fcn: "getPrivateHash", Args: [][]byte{[]byte("collection"), []byte("key")}}
+	assert.NoError(t, chClient.VerifyPrivateData(request, []byte("value")))
+	assert.Error(t, chClient.VerifyPrivateData(request, []byte("tampered")))
+
+	testPeer1.Status = 500
+	assert.Error(t, chClient.VerifyPrivateData(request, []byte("value")), "expecting error if the query fails")
+}
@@ -0,0 +1,166 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package blockcache provides a cache of blocks keyed by channel and block number. The cache may be
+// shared between the ledger client (which consults the cache before querying the peers for a block)
+// and the event service (whose block events populate the cache), which avoids repeated QueryBlock
+// requests from applications that re-fetch recent blocks.
+//
+//  Basic Flow:
+//  1) Create a cache
+//  2) Populate the cache from block events (optional)
+//  3) Create a ledger client with the cache
+package blockcache
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// Cache is a cache of blocks
+type Cache interface {
+	// Get returns the block with the given number on the given channel or nil if the block is not in the cache
+	Get(channelID string, blockNum uint64) *cb.Block
+
+	// Put adds the block for the given channel to the cache
+	Put(channelID string, block *cb.Block)
+}
+
+type key struct {
+	channelID string
+	blockNum  uint64
+}
+
+type entry struct {
+	key   key
+	block *cb.Block
+}
+
+// LRU is a Cache which holds a bounded number of blocks. When the cache is full,
+// the least recently used block is evicted.
+type LRU struct {
+	mutex    sync.Mutex
+	capacity int
+	entries  *list.List
+	index    map[key]*list.Element
+	hits     uint64
+	misses   uint64
+}
+
+// NewLRU returns a new LRU cache which holds up to the given number of blocks
+func NewLRU(capacity int) *LRU {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &LRU{
+		capacity: capacity,
+		entries:  list.New(),
+		index:    make(map[key]*list.Element),
+	}
+}
+
+// Get returns the block with the given number on the given channel or nil if the block is not in the cache
+func (c *LRU) Get(channelID string, blockNum uint64) *cb.Block {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, ok := c.index[key{channelID: channelID, blockNum: blockNum}]
+	if !ok {
+		c.misses++
+		return nil
+	}
+
+	c.hits++
+	c.entries.MoveToFront(e)
+	return e.Value.(*entry).block
+}
+
+// Put adds the block for the given channel to the cache
+func (c *LRU) Put(channelID string, block *cb.Block) {
+	if block == nil || block.Header == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	k := key{channelID: channelID, blockNum: block.Header.Number}
+	if e, ok := c.index[k]; ok {
+		e.Value.(*entry).block = block
+		c.entries.MoveToFront(e)
+		return
+	}
+
+	c.index[k] = c.entries.PushFront(&entry{key: k, block: block})
+
+	for c.entries.Len() > c.capacity {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.index, oldest.Value.(*entry).key)
+	}
+}
+
+// Len returns the number of blocks in the cache
+func (c *LRU) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.entries.Len()
+}
+
+// Stats returns the number of cache hits and misses
+func (c *LRU) Stats() (hits, misses uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.hits, c.misses
+}
+
+// BlockEventSource is a source of block events, such as the event client or the event service
+type BlockEventSource interface {
+	RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error)
+	Unregister(reg fab.Registration)
+}
+
+// Populate registers for block events with the given event source and adds each block received
+// for the given channel to the cache. Note that the event source must permit block events.
+// The returned function stops populating the cache.
+func Populate(source BlockEventSource, channelID string, cache Cache) (func(), error) {
+	if cache == nil {
+		return nil, errors.New("cache is required")
+	}
+
+	reg, eventch, err := source.RegisterBlockEvent()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to register for block events")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range eventch {
+			if event.Block == nil || event.Block.Header == nil {
+				logger.Warnf("Ignoring invalid block event on channel [%s]", channelID)
+				continue
+			}
+			logger.Debugf("Adding block [%d] on channel [%s] to the block cache", event.Block.Header.Number, channelID)
+			cache.Put(channelID, event.Block)
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			source.Unregister(reg)
+			<-done
+		})
+	}, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const channelID = "mychannel"
+
+func newBlock(blockNum uint64) *cb.Block {
+	return &cb.Block{Header: &cb.BlockHeader{Number: blockNum}}
+}
+
+func TestLRU(t *testing.T) {
+	cache := NewLRU(2)
+
+	cache.Put(channelID, newBlock(1))
+	cache.Put(channelID, newBlock(2))
+	cache.Put("otherchannel", newBlock(1))
+	assert.Equal(t, 2, cache.Len())
+
+	assert.Nil(t, cache.Get(channelID, 1), "expecting least recently used block to be evicted")
+	assert.NotNil(t, cache.Get(channelID, 2))
+	assert.NotNil(t, cache.Get("otherchannel", 1))
+
+	// Block 2 was used more recently than block 1 of the other channel
+	assert.NotNil(t, cache.Get(channelID, 2))
+	cache.Put(channelID, newBlock(3))
+	assert.Nil(t, cache.Get("otherchannel", 1))
+	assert.NotNil(t, cache.Get(channelID, 2))
+	assert.NotNil(t, cache.Get(channelID, 3))
+
+	hits, misses := cache.Stats()
+	assert.Equal(t, uint64(5), hits)
+	assert.Equal(t, uint64(2), misses)
+
+	// Invalid blocks are ignored
+	cache.Put(channelID, nil)
+	cache.Put(channelID, &cb.Block{})
+	assert.Equal(t, 2, cache.Len())
+}
+
+type mockEventSource struct {
+	eventch chan *fab.BlockEvent
+	err     error
+}
+
+func (s *mockEventSource) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+	return "reg", s.eventch, nil
+}
+
+func (s *mockEventSource) Unregister(reg fab.Registration) {
+	close(s.eventch)
+}
+
+func TestPopulate(t *testing.T) {
+	cache := NewLRU(10)
+
+	_, err := Populate(&mockEventSource{err: errors.New("access denied")}, channelID, cache)
+	assert.Error(t, err)
+
+	_, err = Populate(&mockEventSource{}, channelID, nil)
+	assert.Error(t, err)
+
+	source := &mockEventSource{eventch: make(chan *fab.BlockEvent, 10)}
+	stop, err := Populate(source, channelID, cache)
+	require.NoError(t, err)
+
+	source.eventch <- &fab.BlockEvent{Block: newBlock(5)}
+	source.eventch <- &fab.BlockEvent{}
+	source.eventch <- &fab.BlockEvent{Block: newBlock(6)}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for cache.Len() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.NotNil(t, cache.Get(channelID, 5))
+	assert.NotNil(t, cache.Get(channelID, 6))
+
+	stop()
+	stop()
+}
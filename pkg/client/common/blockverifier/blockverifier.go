@@ -0,0 +1,447 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package blockverifier provides client-side verification of blocks and transactions against the
+// channel configuration, for consumers which don't want to trust the peer delivering the blocks.
+//
+// A block is verified by checking its data hash and by checking that it's signed by (at least one
+// member of) the orderer organizations. A transaction is verified by checking the creator's
+// signature and by checking that the endorsements are valid and satisfy the endorsement policy
+// of the chaincode.
+//
+//  Basic Flow:
+//  1) Decode the channel config (e.g. from the latest config block)
+//  2) Create a verifier from the config, supplying the endorsement policies of the chaincodes
+//  3) Verify the blocks (and their transactions) received from the peer
+package blockverifier
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const mspKey = "MSP"
+
+// Verifier verifies blocks and transactions against the MSPs of a channel
+type Verifier struct {
+	mspManager    msp.MSPManager
+	ordererMSPs   map[string]bool
+	policies      map[string]*cb.SignaturePolicyEnvelope
+	defaultPolicy *cb.SignaturePolicyEnvelope
+}
+
+// InvalidTransaction holds the details of a transaction which failed verification
+type InvalidTransaction struct {
+	Index int
+	TxID  string
+	Err   error
+}
+
+// Option is a functional option for the verifier
+type Option func(v *Verifier)
+
+// WithOrdererMSPs sets the IDs of the MSPs whose members may sign blocks.
+// If not set then blocks signed by a member of any of the channel MSPs are accepted.
+func WithOrdererMSPs(mspIDs ...string) Option {
+	return func(v *Verifier) {
+		for _, mspID := range mspIDs {
+			v.ordererMSPs[mspID] = true
+		}
+	}
+}
+
+// WithEndorsementPolicy sets the endorsement policy of the given chaincode
+func WithEndorsementPolicy(ccName string, policy *cb.SignaturePolicyEnvelope) Option {
+	return func(v *Verifier) {
+		v.policies[ccName] = policy
+	}
+}
+
+// WithDefaultEndorsementPolicy sets the endorsement policy of the chaincodes for which no policy was set
+// using WithEndorsementPolicy. If not set then at least one valid endorsement is required.
+func WithDefaultEndorsementPolicy(policy *cb.SignaturePolicyEnvelope) Option {
+	return func(v *Verifier) {
+		v.defaultPolicy = policy
+	}
+}
+
+// New returns a verifier for the given channel MSPs
+func New(mspConfigs []*mb.MSPConfig, cs core.CryptoSuite, opts ...Option) (*Verifier, error) {
+	if len(mspConfigs) == 0 {
+		return nil, errors.New("at least one MSP config is required")
+	}
+	if cs == nil {
+		return nil, errors.New("crypto suite is required")
+	}
+
+	msps, err := loadMSPs(mspConfigs, cs)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load MSPs")
+	}
+
+	mspManager := msp.NewMSPManager()
+	if err := mspManager.Setup(msps); err != nil {
+		return nil, errors.WithMessage(err, "MSPManager setup failed")
+	}
+
+	v := &Verifier{
+		mspManager:  mspManager,
+		ordererMSPs: make(map[string]bool),
+		policies:    make(map[string]*cb.SignaturePolicyEnvelope),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v, nil
+}
+
+// NewFromConfig returns a verifier for the MSPs in the given channel config. The orderer MSPs are
+// taken from the orderer organizations in the config.
+func NewFromConfig(config *configtx.Config, cs core.CryptoSuite, opts ...Option) (*Verifier, error) {
+	if config == nil || config.Raw() == nil || config.Raw().ChannelGroup == nil {
+		return nil, errors.New("channel config is required")
+	}
+
+	var mspConfigs []*mb.MSPConfig
+	var ordererMSPs []string
+	for groupName, group := range config.Raw().ChannelGroup.Groups {
+		for _, orgGroup := range group.Groups {
+			mspConfig, err := orgMSPConfig(orgGroup)
+			if err != nil {
+				return nil, err
+			}
+			if mspConfig == nil {
+				continue
+			}
+			mspConfigs = append(mspConfigs, mspConfig)
+
+			if groupName == configtx.OrdererGroupKey {
+				fabricConfig := &mb.FabricMSPConfig{}
+				if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+					return nil, errors.Wrap(err, "unmarshal FabricMSPConfig from config failed")
+				}
+				ordererMSPs = append(ordererMSPs, fabricConfig.Name)
+			}
+		}
+	}
+
+	return New(mspConfigs, cs, append([]Option{WithOrdererMSPs(ordererMSPs...)}, opts...)...)
+}
+
+// VerifyBlock verifies the data hash of the block and the orderer signature in the block metadata.
+// The transactions in the block are not verified (see VerifyBlockTransactions).
+func (v *Verifier) VerifyBlock(block *cb.Block) error {
+	if block == nil || block.Header == nil || block.Data == nil {
+		return errors.New("invalid block")
+	}
+
+	dataHash := sha256.Sum256(bytes.Join(block.Data.Data, nil))
+	if !bytes.Equal(dataHash[:], block.Header.DataHash) {
+		return errors.Errorf("data hash of block [%d] doesn't match the header", block.Header.Number)
+	}
+
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_SIGNATURES) {
+		return errors.Errorf("block [%d] has no signatures", block.Header.Number)
+	}
+	md := &cb.Metadata{}
+	if err := proto.Unmarshal(block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES], md); err != nil {
+		return errors.Wrap(err, "error unmarshalling signatures metadata")
+	}
+
+	headerBytes, err := blockHeaderBytes(block.Header)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, sig := range md.Signatures {
+		lastErr = v.verifyOrdererSignature(sig, md.Value, headerBytes)
+		if lastErr == nil {
+			return nil
+		}
+		logger.Debugf("Invalid signature in block [%d]: %s", block.Header.Number, lastErr)
+	}
+	if lastErr == nil {
+		return errors.Errorf("block [%d] has no signatures", block.Header.Number)
+	}
+	return errors.WithMessage(lastErr, "block isn't signed by an orderer")
+}
+
+// VerifyBlockTransactions verifies the transactions in the block which were marked as valid by the
+// committing peer and returns the ones which failed verification
+func (v *Verifier) VerifyBlockTransactions(block *cb.Block) ([]*InvalidTransaction, error) {
+	if block == nil || block.Header == nil || block.Data == nil {
+		return nil, errors.New("invalid block")
+	}
+
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = ledgerutil.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	var invalid []*InvalidTransaction
+	for i, data := range block.Data.Data {
+		if i < len(txFilter) && !txFilter.IsValid(i) {
+			continue
+		}
+
+		env, err := utils.GetEnvelopeFromBlock(data)
+		if err != nil {
+			invalid = append(invalid, &InvalidTransaction{Index: i, Err: errors.Wrap(err, "error extracting Envelope from block")})
+			continue
+		}
+		if err := v.VerifyTransaction(env); err != nil {
+			invalid = append(invalid, &InvalidTransaction{Index: i, TxID: txID(env), Err: err})
+		}
+	}
+	return invalid, nil
+}
+
+// VerifyTransaction verifies the creator's signature of the transaction and, for endorser transactions,
+// verifies that the endorsements are valid and satisfy the endorsement policy of the chaincode
+func (v *Verifier) VerifyTransaction(env *cb.Envelope) error {
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return errors.Wrap(err, "error extracting Payload from envelope")
+	}
+	if payload.Header == nil {
+		return errors.New("payload header is nil")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return errors.Wrap(err, "error extracting ChannelHeader from payload")
+	}
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return errors.Wrap(err, "error extracting SignatureHeader from payload")
+	}
+
+	if _, err := v.verifySignature(shdr.Creator, env.Payload, env.Signature); err != nil {
+		return errors.WithMessage(err, "invalid creator signature")
+	}
+
+	if cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+		return nil
+	}
+
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return errors.Wrap(err, "error unmarshalling transaction payload")
+	}
+	for i, action := range tx.Actions {
+		if err := v.verifyAction(action); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("invalid transaction action [%d]", i))
+		}
+	}
+	return nil
+}
+
+func (v *Verifier) verifyAction(action *pb.TransactionAction) error {
+	ccActionPayload, err := utils.GetChaincodeActionPayload(action.Payload)
+	if err != nil {
+		return errors.Wrap(err, "error unmarshalling chaincode action payload")
+	}
+	if ccActionPayload.Action == nil {
+		return errors.New("chaincode endorsed action is nil")
+	}
+
+	prp, err := utils.GetProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+	if err != nil {
+		return errors.Wrap(err, "error unmarshalling response payload")
+	}
+	ccAction, err := utils.GetChaincodeAction(prp.Extension)
+	if err != nil {
+		return errors.Wrap(err, "error unmarshalling chaincode action")
+	}
+	if ccAction.ChaincodeId == nil {
+		return errors.New("chaincode ID is missing from the chaincode action")
+	}
+
+	var identities []msp.Identity
+	seen := make(map[string]bool)
+	for _, endorsement := range ccActionPayload.Action.Endorsements {
+		msg := append(append([]byte(nil), ccActionPayload.Action.ProposalResponsePayload...), endorsement.Endorser...)
+		id, err := v.verifySignature(endorsement.Endorser, msg, endorsement.Signature)
+		if err != nil {
+			logger.Debugf("Ignoring invalid endorsement: %s", err)
+			continue
+		}
+		// An endorser may only be counted once
+		if seen[string(endorsement.Endorser)] {
+			continue
+		}
+		seen[string(endorsement.Endorser)] = true
+		identities = append(identities, id)
+	}
+
+	policy := v.policies[ccAction.ChaincodeId.Name]
+	if policy == nil {
+		policy = v.defaultPolicy
+	}
+	if policy == nil {
+		if len(identities) == 0 {
+			return errors.Errorf("no valid endorsements for chaincode [%s]", ccAction.ChaincodeId.Name)
+		}
+		return nil
+	}
+
+	if !evaluate(policy.Rule, policy.Identities, identities, make([]bool, len(identities))) {
+		return errors.Errorf("endorsement policy of chaincode [%s] isn't satisfied by the %d valid endorsement(s)", ccAction.ChaincodeId.Name, len(identities))
+	}
+	return nil
+}
+
+func (v *Verifier) verifyOrdererSignature(sig *cb.MetadataSignature, value, headerBytes []byte) error {
+	shdr, err := utils.GetSignatureHeader(sig.SignatureHeader)
+	if err != nil {
+		return errors.Wrap(err, "error unmarshalling signature header")
+	}
+
+	id, err := v.mspManager.DeserializeIdentity(shdr.Creator)
+	if err != nil {
+		return errors.WithMessage(err, "failed to deserialize signer")
+	}
+	if len(v.ordererMSPs) > 0 && !v.ordererMSPs[id.GetMSPIdentifier()] {
+		return errors.Errorf("signer's MSP [%s] isn't an orderer MSP", id.GetMSPIdentifier())
+	}
+
+	msg := bytes.Join([][]byte{value, sig.SignatureHeader, headerBytes}, nil)
+	_, err = v.verifySignature(shdr.Creator, msg, sig.Signature)
+	return err
+}
+
+func (v *Verifier) verifySignature(serializedID, msg, sig []byte) (msp.Identity, error) {
+	id, err := v.mspManager.DeserializeIdentity(serializedID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to deserialize identity")
+	}
+	if err := id.Validate(); err != nil {
+		return nil, errors.WithMessage(err, "identity is not valid")
+	}
+	if err := id.Verify(msg, sig); err != nil {
+		return nil, errors.WithMessage(err, "signature is not valid")
+	}
+	return id, nil
+}
+
+// evaluate returns true if the identities satisfy the policy. Each identity may only be used once
+// in order to satisfy a policy, e.g. AND('Org1.member', 'Org1.member') requires two distinct identities.
+func evaluate(policy *cb.SignaturePolicy, principals []*mb.MSPPrincipal, identities []msp.Identity, used []bool) bool {
+	if policy == nil {
+		return false
+	}
+
+	switch t := policy.Type.(type) {
+	case *cb.SignaturePolicy_NOutOf_:
+		verified := int32(0)
+		tmpUsed := make([]bool, len(used))
+		for _, rule := range t.NOutOf.Rules {
+			copy(tmpUsed, used)
+			if evaluate(rule, principals, identities, tmpUsed) {
+				verified++
+				copy(used, tmpUsed)
+			}
+		}
+		return verified >= t.NOutOf.N
+	case *cb.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(principals) {
+			logger.Warnf("Identity index out of range: %d", t.SignedBy)
+			return false
+		}
+		for i, id := range identities {
+			if used[i] {
+				continue
+			}
+			if err := id.SatisfiesPrincipal(principals[t.SignedBy]); err == nil {
+				used[i] = true
+				return true
+			}
+		}
+		return false
+	default:
+		logger.Warnf("Unknown signature policy type: %T", t)
+		return false
+	}
+}
+
+type asn1BlockHeader struct {
+	Number       *big.Int
+	PreviousHash []byte
+	DataHash     []byte
+}
+
+// blockHeaderBytes returns the ASN.1 encoding of the block header which is signed by the orderer
+func blockHeaderBytes(header *cb.BlockHeader) ([]byte, error) {
+	headerBytes, err := asn1.Marshal(asn1BlockHeader{
+		Number:       new(big.Int).SetUint64(header.Number),
+		PreviousHash: header.PreviousHash,
+		DataHash:     header.DataHash,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal block header")
+	}
+	return headerBytes, nil
+}
+
+func orgMSPConfig(orgGroup *cb.ConfigGroup) (*mb.MSPConfig, error) {
+	value, ok := orgGroup.Values[mspKey]
+	if !ok {
+		return nil, nil
+	}
+	mspConfig := &mb.MSPConfig{}
+	if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling MSP config")
+	}
+	return mspConfig, nil
+}
+
+func loadMSPs(mspConfigs []*mb.MSPConfig, cs core.CryptoSuite) ([]msp.MSP, error) {
+	var msps []msp.MSP
+	for _, config := range mspConfigs {
+		if msp.ProviderType(config.Type) != msp.FABRIC {
+			return nil, errors.Errorf("MSP type not supported: %v", config.Type)
+		}
+
+		newMSP, err := msp.NewBccspMsp(msp.MSPv1_0, cs)
+		if err != nil {
+			return nil, errors.Wrap(err, "instantiate MSP failed")
+		}
+		if err := newMSP.Setup(config); err != nil {
+			return nil, errors.Wrap(err, "configure MSP failed")
+		}
+		msps = append(msps, newMSP)
+	}
+	return msps, nil
+}
+
+func txID(env *cb.Envelope) string {
+	payload, err := utils.GetPayload(env)
+	if err != nil || payload.Header == nil {
+		return ""
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return ""
+	}
+	return chdr.TxId
+}
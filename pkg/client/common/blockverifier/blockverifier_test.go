@@ -0,0 +1,308 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockverifier
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ca struct {
+	mspID string
+	key   *ecdsa.PrivateKey
+	cert  *x509.Certificate
+	pem   []byte
+}
+
+type signer struct {
+	key          *ecdsa.PrivateKey
+	serializedID []byte
+}
+
+func newCA(t *testing.T, mspID string) *ca {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca." + mspID},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &ca{mspID: mspID, key: key, cert: cert, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (c *ca) newSigner(t *testing.T, cn string) *signer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(time.Now().UnixNano()),
+		Subject:        pkix.Name{CommonName: cn},
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(time.Hour),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		AuthorityKeyId: c.cert.SubjectKeyId,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, c.cert, &key.PublicKey, c.key)
+	require.NoError(t, err)
+
+	serializedID, err := proto.Marshal(&mb.SerializedIdentity{Mspid: c.mspID, IdBytes: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})})
+	require.NoError(t, err)
+
+	return &signer{key: key, serializedID: serializedID}
+}
+
+func (c *ca) mspConfig(t *testing.T) *mb.MSPConfig {
+	config, err := proto.Marshal(&mb.FabricMSPConfig{Name: c.mspID, RootCerts: [][]byte{c.pem}})
+	require.NoError(t, err)
+	return &mb.MSPConfig{Type: 0, Config: config}
+}
+
+// sign returns a low-S ECDSA signature of the message, as required by the MSP
+func (s *signer) sign(t *testing.T, msg []byte) []byte {
+	digest := sha256.Sum256(msg)
+	r, sv, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	require.NoError(t, err)
+
+	halfOrder := new(big.Int).Rsh(s.key.Params().N, 1)
+	if sv.Cmp(halfOrder) > 0 {
+		sv.Sub(s.key.Params().N, sv)
+	}
+	sig, err := asn1.Marshal(struct{ R, S *big.Int }{r, sv})
+	require.NoError(t, err)
+	return sig
+}
+
+type fixture struct {
+	org1, org2, orderer *ca
+	client, peer1, peer2 *signer
+	ordererSigner        *signer
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := &fixture{org1: newCA(t, "Org1MSP"), org2: newCA(t, "Org2MSP"), orderer: newCA(t, "OrdererMSP")}
+	f.client = f.org1.newSigner(t, "user1")
+	f.peer1 = f.org1.newSigner(t, "peer0.org1")
+	f.peer2 = f.org2.newSigner(t, "peer0.org2")
+	f.ordererSigner = f.orderer.newSigner(t, "orderer")
+	return f
+}
+
+func (f *fixture) newVerifier(t *testing.T, opts ...Option) *Verifier {
+	cs, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	v, err := New([]*mb.MSPConfig{f.org1.mspConfig(t), f.org2.mspConfig(t), f.orderer.mspConfig(t)}, cs, opts...)
+	require.NoError(t, err)
+	return v
+}
+
+func TestVerifyBlock(t *testing.T) {
+	f := newFixture(t)
+	v := f.newVerifier(t, WithOrdererMSPs("OrdererMSP"))
+
+	block := newBlock(t, f.ordererSigner, newTx(t, "tx1", f.client, f.peer1, f.peer2))
+	assert.NoError(t, v.VerifyBlock(block))
+
+	// Signed by a member of an application org
+	assert.Error(t, v.VerifyBlock(newBlock(t, f.peer1, newTx(t, "tx1", f.client, f.peer1))))
+
+	// Invalid signature
+	tampered := proto.Clone(block).(*cb.Block)
+	tampered.Header.PreviousHash = []byte("other")
+	assert.Error(t, v.VerifyBlock(tampered))
+
+	// Data doesn't match the data hash
+	tampered = proto.Clone(block).(*cb.Block)
+	tampered.Data.Data = append(tampered.Data.Data, newTx(t, "tx2", f.client, f.peer1))
+	err := v.VerifyBlock(tampered)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "data hash")
+
+	// No signatures
+	tampered = proto.Clone(block).(*cb.Block)
+	tampered.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = nil
+	assert.Error(t, v.VerifyBlock(tampered))
+
+	assert.Error(t, v.VerifyBlock(&cb.Block{}))
+}
+
+func TestVerifyBlockTransactions(t *testing.T) {
+	f := newFixture(t)
+
+	and, err := cauthdsl.FromString("AND('Org1MSP.member', 'Org2MSP.member')")
+	require.NoError(t, err)
+	twoOfOrg1, err := cauthdsl.FromString("AND('Org1MSP.member', 'Org1MSP.member')")
+	require.NoError(t, err)
+
+	tamperedTx := newTx(t, "tx4", f.client, f.peer1, f.peer2)
+	env, err := utils.GetEnvelopeFromBlock(tamperedTx)
+	require.NoError(t, err)
+	env.Signature = f.peer1.sign(t, env.Payload)
+	tamperedTx = utils.MarshalOrPanic(env)
+
+	block := newBlock(t, f.ordererSigner,
+		newTx(t, "tx1", f.client, f.peer1, f.peer2),
+		newTx(t, "tx2", f.client, f.peer1),
+		newTx(t, "tx3", f.client, f.peer1, f.peer1),
+		tamperedTx,
+	)
+
+	v := f.newVerifier(t, WithEndorsementPolicy("examplecc", and))
+	invalid, err := v.VerifyBlockTransactions(block)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tx2", "tx3", "tx4"}, txIDs(invalid))
+	assert.Contains(t, invalid[0].Err.Error(), "endorsement policy")
+	assert.Contains(t, invalid[2].Err.Error(), "invalid creator signature")
+
+	// The same endorser may only be counted once
+	v = f.newVerifier(t, WithDefaultEndorsementPolicy(twoOfOrg1))
+	invalid, err = v.VerifyBlockTransactions(block)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tx1", "tx2", "tx3", "tx4"}, txIDs(invalid))
+
+	// Without a policy, any valid endorsement is sufficient
+	v = f.newVerifier(t)
+	invalid, err = v.VerifyBlockTransactions(block)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tx4"}, txIDs(invalid))
+
+	// Transactions which were invalidated by the peer are not verified
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER][3] = uint8(pb.TxValidationCode_BAD_CREATOR_SIGNATURE)
+	invalid, err = v.VerifyBlockTransactions(block)
+	require.NoError(t, err)
+	assert.Empty(t, invalid)
+}
+
+func TestNewFromConfig(t *testing.T) {
+	f := newFixture(t)
+
+	config, err := configtx.DecodeConfig(&cb.Config{ChannelGroup: &cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{
+		"Application": {Groups: map[string]*cb.ConfigGroup{
+			"Org1": orgGroup(t, f.org1),
+			"Org2": orgGroup(t, f.org2),
+		}},
+		"Orderer": {Groups: map[string]*cb.ConfigGroup{
+			"OrdererOrg": orgGroup(t, f.orderer),
+		}},
+	}}})
+	require.NoError(t, err)
+
+	cs, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+	v, err := NewFromConfig(config, cs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"OrdererMSP": true}, v.ordererMSPs)
+
+	assert.NoError(t, v.VerifyBlock(newBlock(t, f.ordererSigner, newTx(t, "tx1", f.client, f.peer1))))
+	assert.Error(t, v.VerifyBlock(newBlock(t, f.peer2, newTx(t, "tx1", f.client, f.peer1))))
+
+	_, err = NewFromConfig(nil, cs)
+	assert.Error(t, err)
+	_, err = New(nil, cs)
+	assert.Error(t, err)
+}
+
+func orgGroup(t *testing.T, c *ca) *cb.ConfigGroup {
+	value, err := proto.Marshal(c.mspConfig(t))
+	require.NoError(t, err)
+	return &cb.ConfigGroup{Values: map[string]*cb.ConfigValue{mspKey: {Value: value}}}
+}
+
+func txIDs(invalid []*InvalidTransaction) []string {
+	var ids []string
+	for _, tx := range invalid {
+		ids = append(ids, tx.TxID)
+	}
+	return ids
+}
+
+func newTx(t *testing.T, txID string, creator *signer, endorsers ...*signer) []byte {
+	prp, err := utils.GetBytesProposalResponsePayload([]byte("hash"), &pb.Response{Status: 200}, []byte("results"), nil, &pb.ChaincodeID{Name: "examplecc", Version: "v1"})
+	require.NoError(t, err)
+
+	var endorsements []*pb.Endorsement
+	for _, e := range endorsers {
+		endorsements = append(endorsements, &pb.Endorsement{Endorser: e.serializedID, Signature: e.sign(t, append(append([]byte(nil), prp...), e.serializedID...))})
+	}
+
+	ccActionPayload, err := utils.GetBytesChaincodeActionPayload(&pb.ChaincodeActionPayload{
+		Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: prp, Endorsements: endorsements},
+	})
+	require.NoError(t, err)
+	txBytes, err := utils.GetBytesTransaction(&pb.Transaction{Actions: []*pb.TransactionAction{{Payload: ccActionPayload}}})
+	require.NoError(t, err)
+
+	chdr := &cb.ChannelHeader{Type: int32(cb.HeaderType_ENDORSER_TRANSACTION), ChannelId: "mychannel", TxId: txID}
+	shdr := &cb.SignatureHeader{Creator: creator.serializedID, Nonce: []byte("nonce")}
+	payload, err := utils.GetBytesPayload(&cb.Payload{
+		Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(chdr), SignatureHeader: utils.MarshalOrPanic(shdr)},
+		Data:   txBytes,
+	})
+	require.NoError(t, err)
+
+	return utils.MarshalOrPanic(&cb.Envelope{Payload: payload, Signature: creator.sign(t, payload)})
+}
+
+func newBlock(t *testing.T, orderer *signer, txs ...[]byte) *cb.Block {
+	dataHash := sha256.Sum256(bytes.Join(txs, nil))
+	block := &cb.Block{
+		Header:   &cb.BlockHeader{Number: 5, PreviousHash: []byte("previous"), DataHash: dataHash[:]},
+		Data:     &cb.BlockData{Data: txs},
+		Metadata: &cb.BlockMetadata{Metadata: make([][]byte, len(cb.BlockMetadataIndex_name))},
+	}
+	txFilter := ledgerutil.NewTxValidationFlags(len(txs))
+	for i := range txs {
+		txFilter[i] = uint8(pb.TxValidationCode_VALID)
+	}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = txFilter
+
+	headerBytes, err := blockHeaderBytes(block.Header)
+	require.NoError(t, err)
+	shdr := utils.MarshalOrPanic(&cb.SignatureHeader{Creator: orderer.serializedID, Nonce: []byte("nonce")})
+	value := []byte("last config")
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&cb.Metadata{
+		Value: value,
+		Signatures: []*cb.MetadataSignature{{
+			SignatureHeader: shdr,
+			Signature:       orderer.sign(t, bytes.Join([][]byte{value, shdr, headerBytes}, nil)),
+		}},
+	})
+	return block
+}
@@ -7,7 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package dynamicdiscovery
 
 import (
-	"math/rand"
+	"sync"
+	"time"
 
 	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
 	coptions "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
@@ -15,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	reqContext "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/random"
 	"github.com/pkg/errors"
 )
 
@@ -25,6 +27,22 @@ type ChannelService struct {
 	*service
 	channelID  string
 	membership fab.ChannelMembership
+
+	stateLock   sync.RWMutex
+	lastRefresh time.Time
+	lastErr     error
+	numPeers    int
+}
+
+// RefreshState describes the most recent refresh of the channel's peers from the discovery service
+type RefreshState struct {
+	ChannelID string
+	// LastRefresh is the time of the last refresh attempt (zero if the peers haven't been queried yet)
+	LastRefresh time.Time
+	// LastError is the error of the last refresh attempt (empty if it succeeded)
+	LastError string
+	// NumPeers is the number of peers returned by the last successful refresh
+	NumPeers int
 }
 
 // NewChannelService creates a Discovery Service to query the list of member peers on a given channel.
@@ -34,7 +52,7 @@ func NewChannelService(ctx contextAPI.Client, membership fab.ChannelMembership,
 		channelID:  channelID,
 		membership: membership,
 	}
-	s.service = newService(ctx.EndpointConfig(), s.queryPeers, opts...)
+	s.service = newService(ctx.EndpointConfig(), s.queryPeers, "peers/"+channelID, opts...)
 	err := s.service.initialize(ctx)
 	if err != nil {
 		return nil, err
@@ -49,7 +67,37 @@ func (s *ChannelService) Close() {
 	s.service.Close()
 }
 
+// RefreshState returns the state of the most recent refresh of the channel's peers
+func (s *ChannelService) RefreshState() RefreshState {
+	s.stateLock.RLock()
+	defer s.stateLock.RUnlock()
+
+	state := RefreshState{
+		ChannelID:   s.channelID,
+		LastRefresh: s.lastRefresh,
+		NumPeers:    s.numPeers,
+	}
+	if s.lastErr != nil {
+		state.LastError = s.lastErr.Error()
+	}
+	return state
+}
+
 func (s *ChannelService) queryPeers() ([]fab.Peer, error) {
+	peers, err := s.doQueryPeers()
+
+	s.stateLock.Lock()
+	s.lastRefresh = time.Now()
+	s.lastErr = err
+	if err == nil {
+		s.numPeers = len(peers)
+	}
+	s.stateLock.Unlock()
+
+	return peers, err
+}
+
+func (s *ChannelService) doQueryPeers() ([]fab.Peer, error) {
 	logger.Debugf("Refreshing peers of channel [%s] from discovery service...", s.channelID)
 
 	ctx := s.context()
@@ -146,7 +194,7 @@ func (p *peerEndpoint) BlockHeight() uint64 {
 func pickRandomNPeerConfigs(chPeers []fab.ChannelPeer, n int) []fab.PeerConfig {
 
 	var result []fab.PeerConfig
-	for _, index := range rand.Perm(len(chPeers)) {
+	for _, index := range random.Perm(len(chPeers)) {
 		result = append(result, chPeers[index].PeerConfig)
 		if len(result) == n {
 			break
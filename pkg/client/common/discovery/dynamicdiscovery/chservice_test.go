@@ -116,6 +116,12 @@ func TestDiscoveryService(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equalf(t, 2, len(peers), "Expected 2 peers")
 
+	state := service.RefreshState()
+	assert.Equal(t, ch, state.ChannelID)
+	assert.Equal(t, 2, state.NumPeers)
+	assert.Empty(t, state.LastError)
+	assert.WithinDuration(t, time.Now(), state.LastRefresh, time.Second)
+
 	filteredService := discovery.NewDiscoveryFilterService(service, &blockHeightFilter{minBlockHeight: 10})
 	peers, err = filteredService.GetPeers()
 	require.NoError(t, err)
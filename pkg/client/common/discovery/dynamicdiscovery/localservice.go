@@ -27,7 +27,7 @@ func newLocalService(config fab.EndpointConfig, mspID string, opts ...coptions.O
 	logger.Debug("Creating new local discovery service")
 
 	s := &LocalService{mspID: mspID}
-	s.service = newService(config, s.queryPeers, opts...)
+	s.service = newService(config, s.queryPeers, "", opts...)
 	return s
 }
 
@@ -10,11 +10,13 @@ import (
 	"time"
 
 	coptions "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 )
 
 type options struct {
 	refreshInterval time.Duration
 	responseTimeout time.Duration
+	warmStartStore  core.KVStore
 }
 
 // WithRefreshInterval sets the interval in which the
@@ -38,6 +40,17 @@ func WithResponseTimeout(value time.Duration) coptions.Opt {
 	}
 }
 
+// WithWarmStartStore persists the discovered peers in the given store. When the service
+// is created, the persisted peers are served while the peers are discovered in the background.
+func WithWarmStartStore(store core.KVStore) coptions.Opt {
+	return func(p coptions.Params) {
+		logger.Debug("Checking warmStartStoreSetter")
+		if setter, ok := p.(warmStartStoreSetter); ok {
+			setter.SetWarmStartStore(store)
+		}
+	}
+}
+
 type refreshIntervalSetter interface {
 	SetRefreshInterval(value time.Duration)
 }
@@ -46,6 +59,10 @@ type responseTimeoutSetter interface {
 	SetResponseTimeout(value time.Duration)
 }
 
+type warmStartStoreSetter interface {
+	SetWarmStartStore(store core.KVStore)
+}
+
 func (o *options) SetRefreshInterval(value time.Duration) {
 	logger.Debugf("RefreshInterval: %s", value)
 	o.refreshInterval = value
@@ -55,3 +72,7 @@ func (o *options) SetResponseTimeout(value time.Duration) {
 	logger.Debugf("ResponseTimeout: %s", value)
 	o.responseTimeout = value
 }
+
+func (o *options) SetWarmStartStore(store core.KVStore) {
+	o.warmStartStore = store
+}
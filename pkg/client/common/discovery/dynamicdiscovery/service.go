@@ -8,14 +8,17 @@ package dynamicdiscovery
 
 import (
 	"context"
+	"encoding/json"
 	"sync"
 	"time"
 
 	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
 	coptions "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	contextAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/warmstart"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
 	"github.com/pkg/errors"
 )
@@ -38,11 +41,15 @@ type service struct {
 	ctx             contextAPI.Client
 	discClient      discoveryClient
 	peersRef        *lazyref.Reference
+	store           core.KVStore
+	warmStartKey    string
 }
 
 type queryPeers func() ([]fab.Peer, error)
 
-func newService(config fab.EndpointConfig, query queryPeers, opts ...coptions.Opt) *service {
+// newService creates the service. If a warm-start store is provided in the options and the given
+// key is not empty then the discovered peers are persisted in the store under the given key.
+func newService(config fab.EndpointConfig, query queryPeers, warmStartKey string, opts ...coptions.Opt) *service {
 	options := options{}
 	coptions.Apply(&options, opts)
 
@@ -57,15 +64,37 @@ func newService(config fab.EndpointConfig, query queryPeers, opts ...coptions.Op
 	logger.Debugf("Cache refresh interval: %s", options.refreshInterval)
 	logger.Debugf("Deliver service response timeout: %s", options.responseTimeout)
 
-	return &service{
+	s := &service{
 		responseTimeout: options.responseTimeout,
-		peersRef: lazyref.New(
+	}
+
+	if options.warmStartStore == nil || warmStartKey == "" {
+		s.peersRef = lazyref.New(
 			func() (interface{}, error) {
 				return query()
 			},
 			lazyref.WithRefreshInterval(lazyref.InitOnFirstAccess, options.refreshInterval),
-		),
+		)
+		return s
 	}
+
+	logger.Debugf("Persisting discovered peers under [%s]", warmStartKey)
+
+	s.store = options.warmStartStore
+	s.warmStartKey = warmStartKey
+	s.peersRef = warmstart.NewRef(
+		s.store, warmStartKey, s.loadPeers,
+		func() (interface{}, error) {
+			peers, err := query()
+			if err != nil {
+				return nil, err
+			}
+			s.savePeers(peers)
+			return peers, nil
+		},
+		lazyref.InitOnFirstAccess, options.refreshInterval,
+	)
+	return s
 }
 
 // initialize initializes the service with client context
@@ -121,6 +150,55 @@ func (s *service) discoveryClient() discoveryClient {
 	return s.discClient
 }
 
+type persistedPeer struct {
+	URL         string `json:"url"`
+	MSPID       string `json:"mspid"`
+	BlockHeight uint64 `json:"blockHeight,omitempty"`
+}
+
+func (s *service) savePeers(peers []fab.Peer) {
+	var persisted []persistedPeer
+	for _, peer := range peers {
+		p := persistedPeer{URL: peer.URL(), MSPID: peer.MSPID()}
+		if state, ok := peer.(fab.PeerState); ok {
+			p.BlockHeight = state.BlockHeight()
+		}
+		persisted = append(persisted, p)
+	}
+
+	data, err := json.Marshal(persisted)
+	if err != nil {
+		logger.Warnf("Error marshalling peers for [%s]: %s", s.warmStartKey, err)
+		return
+	}
+	warmstart.Save(s.store, s.warmStartKey, data)
+}
+
+func (s *service) loadPeers(data []byte) (interface{}, error) {
+	ctx := s.context()
+	if ctx == nil {
+		return nil, errors.New("the service has not been initialized")
+	}
+
+	var persisted []persistedPeer
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		return nil, errors.Wrap(err, "unmarshal persisted peers failed")
+	}
+
+	var peers []fab.Peer
+	for _, p := range persisted {
+		peer, ok := newPeer(ctx, p.URL, p.MSPID)
+		if !ok {
+			continue
+		}
+		if p.BlockHeight > 0 {
+			peer = &peerEndpoint{Peer: peer, blockHeight: p.BlockHeight}
+		}
+		peers = append(peers, peer)
+	}
+	return peers, nil
+}
+
 func asPeers(ctx contextAPI.Client, endpoints []*discclient.Peer) []fab.Peer {
 	var peers []fab.Peer
 	for _, endpoint := range endpoints {
@@ -138,13 +216,17 @@ func asPeer(ctx contextAPI.Client, endpoint *discclient.Peer) (fab.Peer, bool) {
 
 	logger.Debugf("Adding endpoint [%s]", url)
 
+	return newPeer(ctx, url, endpoint.MSPID)
+}
+
+func newPeer(ctx contextAPI.Client, url, mspID string) (fab.Peer, bool) {
 	peerConfig, found := ctx.EndpointConfig().PeerConfig(url)
 	if !found {
 		logger.Debugf("Peer config not found for url [%s]", url)
 		return nil, false
 	}
 
-	peer, err := ctx.InfraProvider().CreatePeerFromConfig(&fab.NetworkPeer{PeerConfig: *peerConfig, MSPID: endpoint.MSPID})
+	peer, err := ctx.InfraProvider().CreatePeerFromConfig(&fab.NetworkPeer{PeerConfig: *peerConfig, MSPID: mspID})
 	if err != nil {
 		logger.Warnf("Unable to create peer config for [%s]: %s", url, err)
 		return nil, false
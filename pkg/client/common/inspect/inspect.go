@@ -0,0 +1,589 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package inspect decodes proposals and transactions into a structured view for audit tooling and
+// debugging: the creator (MSP ID and certificate), the invoked chaincode and its arguments (with
+// optional redaction), the read-write sets, the endorsing organizations and whether the signatures
+// of the creator and of the endorsers are valid.
+//
+// Signatures are verified against the certificate embedded in the signer's identity unless a
+// verifier (e.g. the channel membership) is provided, in which case the identity is also validated
+// against the channel's MSPs.
+package inspect
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// Redacted is the value which replaces redacted arguments when RedactAll is used
+var Redacted = []byte("<redacted>")
+
+// Verifier validates identities and verifies signatures. The channel membership (fab.ChannelMembership)
+// implements this interface.
+type Verifier interface {
+	Validate(serializedID []byte) error
+	Verify(serializedID []byte, msg []byte, sig []byte) error
+}
+
+// Redactor returns the value which is shown for the argument with the given index
+// of an invocation of the given chaincode
+type Redactor func(chaincode string, index int, arg []byte) []byte
+
+// RedactAll is a Redactor which redacts all arguments except for the function name (the first argument)
+func RedactAll(chaincode string, index int, arg []byte) []byte {
+	if index == 0 {
+		return arg
+	}
+	return Redacted
+}
+
+// Identity is the identity of a creator or endorser
+type Identity struct {
+	MSPID       string            `json:"mspid"`
+	Subject     string            `json:"subject,omitempty"`
+	Issuer      string            `json:"issuer,omitempty"`
+	Certificate *x509.Certificate `json:"-"`
+	PEM         []byte            `json:"pem,omitempty"`
+}
+
+// Signature contains the result of a signature verification
+type Signature struct {
+	Verified bool   `json:"verified"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Chaincode is a chaincode invocation
+type Chaincode struct {
+	Name          string   `json:"name"`
+	Version       string   `json:"version,omitempty"`
+	Args          [][]byte `json:"args,omitempty"`
+	TransientKeys []string `json:"transientKeys,omitempty"`
+}
+
+// Header contains the fields of the channel and signature headers
+type Header struct {
+	Type      string    `json:"type"`
+	ChannelID string    `json:"channelId"`
+	TxID      string    `json:"txId"`
+	Timestamp time.Time `json:"timestamp"`
+	Creator   Identity  `json:"creator"`
+}
+
+// Proposal is a decoded transaction proposal
+type Proposal struct {
+	Header    Header     `json:"header"`
+	Chaincode Chaincode  `json:"chaincode"`
+	Signature *Signature `json:"signature,omitempty"`
+}
+
+// KVRead is a read of a key along with the version that was read
+type KVRead struct {
+	Key      string `json:"key"`
+	BlockNum uint64 `json:"blockNum"`
+	TxNum    uint64 `json:"txNum"`
+	Exists   bool   `json:"exists"`
+}
+
+// KVWrite is a write (or delete) of a key
+type KVWrite struct {
+	Key      string `json:"key"`
+	Value    []byte `json:"value,omitempty"`
+	IsDelete bool   `json:"isDelete,omitempty"`
+}
+
+// KVReadHash is a read of a private data key (identified by its hash) along with the version that was read
+type KVReadHash struct {
+	KeyHash  []byte `json:"keyHash"`
+	BlockNum uint64 `json:"blockNum"`
+	TxNum    uint64 `json:"txNum"`
+	Exists   bool   `json:"exists"`
+}
+
+// KVWriteHash is a write (or delete) of a private data key, identified by the hashes of the key and value.
+// IsPurge is set if the key was purged (PurgePrivateData, Fabric 2.5 or later), in which case the private
+// data history of the key is removed from the peers.
+type KVWriteHash struct {
+	KeyHash   []byte `json:"keyHash"`
+	ValueHash []byte `json:"valueHash,omitempty"`
+	IsDelete  bool   `json:"isDelete,omitempty"`
+	IsPurge   bool   `json:"isPurge,omitempty"`
+}
+
+// CollHashedRWSet is the hashed read-write set of a private data collection
+type CollHashedRWSet struct {
+	Collection   string        `json:"collection"`
+	Reads        []KVReadHash  `json:"reads,omitempty"`
+	Writes       []KVWriteHash `json:"writes,omitempty"`
+	PvtRWSetHash []byte        `json:"pvtRWSetHash,omitempty"`
+}
+
+// NsRWSet is the read-write set of a namespace (chaincode)
+type NsRWSet struct {
+	Namespace   string    `json:"namespace"`
+	Reads       []KVRead  `json:"reads,omitempty"`
+	Writes      []KVWrite `json:"writes,omitempty"`
+	Collections []string  `json:"collections,omitempty"`
+	// HashedRWSets contains the hashed read-write sets of the private data collections
+	HashedRWSets []CollHashedRWSet `json:"hashedRWSets,omitempty"`
+}
+
+// Endorsement is the endorsement of a transaction action by a peer
+type Endorsement struct {
+	Endorser  Identity  `json:"endorser"`
+	Signature Signature `json:"signature"`
+}
+
+// Action is a transaction action (the endorsed result of a chaincode invocation)
+type Action struct {
+	Chaincode       Chaincode     `json:"chaincode"`
+	ResponseStatus  int32         `json:"responseStatus"`
+	ResponseMessage string        `json:"responseMessage,omitempty"`
+	ResponsePayload []byte        `json:"responsePayload,omitempty"`
+	RWSets          []NsRWSet     `json:"rwsets,omitempty"`
+	Endorsements    []Endorsement `json:"endorsements"`
+	EndorsingOrgs   []string      `json:"endorsingOrgs"`
+}
+
+// Transaction is a decoded transaction envelope
+type Transaction struct {
+	Header    Header    `json:"header"`
+	Actions   []Action  `json:"actions,omitempty"`
+	Signature Signature `json:"signature"`
+}
+
+// Decoder decodes proposals and transactions
+type Decoder struct {
+	verifier Verifier
+	redactor Redactor
+}
+
+// Option is a functional option for the decoder
+type Option func(d *Decoder)
+
+// WithVerifier sets the verifier which validates the identities and verifies the signatures
+func WithVerifier(verifier Verifier) Option {
+	return func(d *Decoder) {
+		d.verifier = verifier
+	}
+}
+
+// WithRedactor sets the redactor which is applied to the chaincode arguments
+func WithRedactor(redactor Redactor) Option {
+	return func(d *Decoder) {
+		d.redactor = redactor
+	}
+}
+
+// NewDecoder returns a new decoder
+func NewDecoder(opts ...Option) *Decoder {
+	d := &Decoder{}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// DecodeSignedProposal decodes a marshalled SignedProposal and verifies the creator's signature
+func (d *Decoder) DecodeSignedProposal(signedProposalBytes []byte) (*Proposal, error) {
+	signedProposal := &pb.SignedProposal{}
+	if err := proto.Unmarshal(signedProposalBytes, signedProposal); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal signed proposal")
+	}
+
+	proposal, creator, err := d.decodeProposal(signedProposal.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signature := d.verify(creator, signedProposal.ProposalBytes, signedProposal.Signature)
+	proposal.Signature = &signature
+	return proposal, nil
+}
+
+// DecodeProposal decodes a marshalled (unsigned) Proposal
+func (d *Decoder) DecodeProposal(proposalBytes []byte) (*Proposal, error) {
+	proposal, _, err := d.decodeProposal(proposalBytes)
+	return proposal, err
+}
+
+func (d *Decoder) decodeProposal(proposalBytes []byte) (*Proposal, []byte, error) {
+	proposal := &pb.Proposal{}
+	if err := proto.Unmarshal(proposalBytes, proposal); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal proposal")
+	}
+
+	hdr, err := utils.GetHeader(proposal.Header)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal proposal header")
+	}
+
+	header, creator, err := decodeHeader(hdr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ccProposalPayload, err := utils.GetChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal chaincode proposal payload")
+	}
+
+	chaincode, err := d.decodeChaincode(ccProposalPayload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The version of the chaincode is in the header extension
+	if ext, err := utils.GetChaincodeHeaderExtension(hdr); err == nil && ext.ChaincodeId != nil {
+		if chaincode.Name == "" {
+			chaincode.Name = ext.ChaincodeId.Name
+		}
+		chaincode.Version = ext.ChaincodeId.Version
+	}
+
+	return &Proposal{Header: *header, Chaincode: *chaincode}, creator, nil
+}
+
+// DecodeTransaction decodes a marshalled transaction Envelope (e.g. from block data) and verifies
+// the signatures of the creator and of the endorsers
+func (d *Decoder) DecodeTransaction(envelopeBytes []byte) (*Transaction, error) {
+	env, err := utils.GetEnvelopeFromBlock(envelopeBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal envelope")
+	}
+	return d.DecodeEnvelope(env)
+}
+
+// DecodeEnvelope decodes a transaction envelope and verifies the signatures of the creator and of the endorsers
+func (d *Decoder) DecodeEnvelope(env *cb.Envelope) (*Transaction, error) {
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal payload")
+	}
+	if payload.Header == nil {
+		return nil, errors.New("payload header is missing")
+	}
+
+	header, creator, err := decodeHeader(payload.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &Transaction{
+		Header:    *header,
+		Signature: d.verify(creator, env.Payload, env.Signature),
+	}
+
+	if header.Type != cb.HeaderType_ENDORSER_TRANSACTION.String() {
+		return tx, nil
+	}
+
+	transaction, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal transaction")
+	}
+
+	for _, txAction := range transaction.Actions {
+		action, err := d.decodeAction(txAction)
+		if err != nil {
+			return nil, err
+		}
+		tx.Actions = append(tx.Actions, *action)
+	}
+
+	return tx, nil
+}
+
+func (d *Decoder) decodeAction(txAction *pb.TransactionAction) (*Action, error) {
+	ccActionPayload, err := utils.GetChaincodeActionPayload(txAction.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal chaincode action payload")
+	}
+	if ccActionPayload.Action == nil {
+		return nil, errors.New("chaincode endorsed action is missing")
+	}
+
+	ccProposalPayload, err := utils.GetChaincodeProposalPayload(ccActionPayload.ChaincodeProposalPayload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal chaincode proposal payload")
+	}
+	chaincode, err := d.decodeChaincode(ccProposalPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	prp, err := utils.GetProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal proposal response payload")
+	}
+	ccAction, err := utils.GetChaincodeAction(prp.Extension)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal chaincode action")
+	}
+	if ccAction.ChaincodeId != nil {
+		chaincode.Version = ccAction.ChaincodeId.Version
+	}
+
+	action := &Action{Chaincode: *chaincode}
+	if ccAction.Response != nil {
+		action.ResponseStatus = ccAction.Response.Status
+		action.ResponseMessage = ccAction.Response.Message
+		action.ResponsePayload = ccAction.Response.Payload
+	}
+
+	action.RWSets, err = decodeRWSets(ccAction.Results)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs := make(map[string]bool)
+	for _, endorsement := range ccActionPayload.Action.Endorsements {
+		endorser, err := decodeIdentity(endorsement.Endorser)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to decode endorser")
+		}
+		signedBytes := append(append([]byte(nil), ccActionPayload.Action.ProposalResponsePayload...), endorsement.Endorser...)
+		action.Endorsements = append(action.Endorsements, Endorsement{
+			Endorser:  *endorser,
+			Signature: d.verify(endorsement.Endorser, signedBytes, endorsement.Signature),
+		})
+		orgs[endorser.MSPID] = true
+	}
+
+	for org := range orgs {
+		action.EndorsingOrgs = append(action.EndorsingOrgs, org)
+	}
+	sort.Strings(action.EndorsingOrgs)
+
+	return action, nil
+}
+
+func (d *Decoder) decodeChaincode(ccProposalPayload *pb.ChaincodeProposalPayload) (*Chaincode, error) {
+	chaincode := &Chaincode{}
+
+	if len(ccProposalPayload.Input) > 0 {
+		cis := &pb.ChaincodeInvocationSpec{}
+		if err := proto.Unmarshal(ccProposalPayload.Input, cis); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal chaincode invocation spec")
+		}
+		if spec := cis.ChaincodeSpec; spec != nil {
+			if spec.ChaincodeId != nil {
+				chaincode.Name = spec.ChaincodeId.Name
+				chaincode.Version = spec.ChaincodeId.Version
+			}
+			if spec.Input != nil {
+				for i, arg := range spec.Input.Args {
+					if d.redactor != nil {
+						arg = d.redactor(chaincode.Name, i, arg)
+					}
+					chaincode.Args = append(chaincode.Args, arg)
+				}
+			}
+		}
+	}
+
+	// Only the keys of the transient data are shown
+	for key := range ccProposalPayload.TransientMap {
+		chaincode.TransientKeys = append(chaincode.TransientKeys, key)
+	}
+	sort.Strings(chaincode.TransientKeys)
+
+	return chaincode, nil
+}
+
+// DecodeProposalResponseRWSets decodes the read-write sets of the given (marshalled) proposal response payload,
+// i.e. the results of the chaincode simulation by an endorser
+func DecodeProposalResponseRWSets(payload []byte) ([]NsRWSet, error) {
+	prp, err := utils.GetProposalResponsePayload(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal proposal response payload")
+	}
+	ccAction, err := utils.GetChaincodeAction(prp.Extension)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal chaincode action")
+	}
+	return decodeRWSets(ccAction.Results)
+}
+
+func decodeRWSets(results []byte) ([]NsRWSet, error) {
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(results); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal read-write set")
+	}
+
+	var nsRWSets []NsRWSet
+	for _, ns := range txRWSet.NsRwSets {
+		nsRWSet := NsRWSet{Namespace: ns.NameSpace}
+		if ns.KvRwSet != nil {
+			for _, r := range ns.KvRwSet.Reads {
+				read := KVRead{Key: r.Key}
+				if r.Version != nil {
+					read.Exists = true
+					read.BlockNum = r.Version.BlockNum
+					read.TxNum = r.Version.TxNum
+				}
+				nsRWSet.Reads = append(nsRWSet.Reads, read)
+			}
+			for _, w := range ns.KvRwSet.Writes {
+				nsRWSet.Writes = append(nsRWSet.Writes, KVWrite{Key: w.Key, Value: w.Value, IsDelete: w.IsDelete})
+			}
+		}
+		for _, coll := range ns.CollHashedRwSets {
+			nsRWSet.Collections = append(nsRWSet.Collections, coll.CollectionName)
+			nsRWSet.HashedRWSets = append(nsRWSet.HashedRWSets, decodeHashedRWSet(coll))
+		}
+		nsRWSets = append(nsRWSets, nsRWSet)
+	}
+	return nsRWSets, nil
+}
+
+func decodeHashedRWSet(coll *rwsetutil.CollHashedRwSet) CollHashedRWSet {
+	hashedRWSet := CollHashedRWSet{Collection: coll.CollectionName, PvtRWSetHash: coll.PvtRwSetHash}
+	if coll.HashedRwSet == nil {
+		return hashedRWSet
+	}
+	for _, r := range coll.HashedRwSet.HashedReads {
+		read := KVReadHash{KeyHash: r.KeyHash}
+		if r.Version != nil {
+			read.Exists = true
+			read.BlockNum = r.Version.BlockNum
+			read.TxNum = r.Version.TxNum
+		}
+		hashedRWSet.Reads = append(hashedRWSet.Reads, read)
+	}
+	for _, w := range coll.HashedRwSet.HashedWrites {
+		hashedRWSet.Writes = append(hashedRWSet.Writes, KVWriteHash{KeyHash: w.KeyHash, ValueHash: w.ValueHash, IsDelete: w.IsDelete, IsPurge: w.IsPurge})
+	}
+	return hashedRWSet
+}
+
+func decodeHeader(hdr *cb.Header) (*Header, []byte, error) {
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal channel header")
+	}
+	shdr, err := utils.GetSignatureHeader(hdr.SignatureHeader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal signature header")
+	}
+
+	header := &Header{
+		Type:      cb.HeaderType(chdr.Type).String(),
+		ChannelID: chdr.ChannelId,
+		TxID:      chdr.TxId,
+	}
+	if chdr.Timestamp != nil {
+		header.Timestamp, err = ptypes.Timestamp(chdr.Timestamp)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid timestamp")
+		}
+	}
+
+	creator, err := decodeIdentity(shdr.Creator)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to decode creator")
+	}
+	header.Creator = *creator
+
+	return header, shdr.Creator, nil
+}
+
+func decodeIdentity(serializedID []byte) (*Identity, error) {
+	sID := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, sID); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal serialized identity")
+	}
+
+	identity := &Identity{MSPID: sID.Mspid, PEM: sID.IdBytes}
+
+	cert, err := parseCertificate(sID.IdBytes)
+	if err != nil {
+		// The identity may not be an X.509 identity (e.g. idemix) so only the MSP ID is shown
+		return identity, nil
+	}
+	identity.Certificate = cert
+	identity.Subject = cert.Subject.String()
+	identity.Issuer = cert.Issuer.String()
+
+	return identity, nil
+}
+
+func parseCertificate(pemBytes []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM data found in identity")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// verify verifies the signature of the given identity over the message
+func (d *Decoder) verify(serializedID, msg, sig []byte) Signature {
+	if err := d.doVerify(serializedID, msg, sig); err != nil {
+		return Signature{Error: err.Error()}
+	}
+	return Signature{Verified: true}
+}
+
+func (d *Decoder) doVerify(serializedID, msg, sig []byte) error {
+	if len(sig) == 0 {
+		return errors.New("signature is missing")
+	}
+
+	if d.verifier != nil {
+		if err := d.verifier.Validate(serializedID); err != nil {
+			return errors.WithMessage(err, "identity is not valid")
+		}
+		return d.verifier.Verify(serializedID, msg, sig)
+	}
+
+	return VerifySignature(serializedID, msg, sig)
+}
+
+// VerifySignature verifies the signature over the message against the certificate in the given serialized
+// identity. The identity itself isn't validated against the MSP.
+func VerifySignature(serializedID, msg, sig []byte) error {
+	sID := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, sID); err != nil {
+		return errors.Wrap(err, "failed to unmarshal serialized identity")
+	}
+
+	cert, err := parseCertificate(sID.IdBytes)
+	if err != nil {
+		return errors.WithMessage(err, "failed to parse certificate of identity")
+	}
+
+	var algorithm x509.SignatureAlgorithm
+	switch cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		algorithm = x509.ECDSAWithSHA256
+	case *rsa.PublicKey:
+		algorithm = x509.SHA256WithRSA
+	default:
+		return errors.Errorf("unsupported public key type %T", cert.PublicKey)
+	}
+
+	if err := cert.CheckSignature(algorithm, msg, sig); err != nil {
+		return errors.Wrap(err, "signature is not valid")
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package inspect
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type signer struct {
+	key          *ecdsa.PrivateKey
+	serializedID []byte
+}
+
+func newSigner(t *testing.T, mspID, cn string) *signer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	serializedID, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})})
+	require.NoError(t, err)
+
+	return &signer{key: key, serializedID: serializedID}
+}
+
+func (s *signer) sign(t *testing.T, msg []byte) []byte {
+	digest := sha256.Sum256(msg)
+	sig, err := s.key.Sign(rand.Reader, digest[:], nil)
+	require.NoError(t, err)
+	return sig
+}
+
+func newProposal(t *testing.T, creator *signer) *pb.Proposal {
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{
+		ChaincodeId: &pb.ChaincodeID{Name: "examplecc"},
+		Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("move"), []byte("a"), []byte("secret")}},
+	}}
+	proposal, _, err := utils.CreateChaincodeProposalWithTxIDNonceAndTransient("txid", cb.HeaderType_ENDORSER_TRANSACTION, "mychannel", cis, []byte("nonce"), creator.serializedID, map[string][]byte{"pvt": []byte("data")})
+	require.NoError(t, err)
+	return proposal
+}
+
+func TestDecodeSignedProposal(t *testing.T) {
+	creator := newSigner(t, "Org1MSP", "user1")
+	proposal := newProposal(t, creator)
+
+	proposalBytes, err := proto.Marshal(proposal)
+	require.NoError(t, err)
+	signedProposalBytes, err := proto.Marshal(&pb.SignedProposal{ProposalBytes: proposalBytes, Signature: creator.sign(t, proposalBytes)})
+	require.NoError(t, err)
+
+	decoded, err := NewDecoder(WithRedactor(RedactAll)).DecodeSignedProposal(signedProposalBytes)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ENDORSER_TRANSACTION", decoded.Header.Type)
+	assert.Equal(t, "mychannel", decoded.Header.ChannelID)
+	assert.Equal(t, "txid", decoded.Header.TxID)
+	assert.Equal(t, "Org1MSP", decoded.Header.Creator.MSPID)
+	assert.Equal(t, "CN=user1", decoded.Header.Creator.Subject)
+	assert.Equal(t, "examplecc", decoded.Chaincode.Name)
+	assert.Equal(t, [][]byte{[]byte("move"), Redacted, Redacted}, decoded.Chaincode.Args)
+	assert.Equal(t, []string{"pvt"}, decoded.Chaincode.TransientKeys)
+	require.NotNil(t, decoded.Signature)
+	assert.True(t, decoded.Signature.Verified)
+
+	// Signed by another identity
+	signedProposalBytes, err = proto.Marshal(&pb.SignedProposal{ProposalBytes: proposalBytes, Signature: newSigner(t, "Org1MSP", "user2").sign(t, proposalBytes)})
+	require.NoError(t, err)
+	decoded, err = NewDecoder().DecodeSignedProposal(signedProposalBytes)
+	require.NoError(t, err)
+	assert.False(t, decoded.Signature.Verified)
+	assert.NotEmpty(t, decoded.Signature.Error)
+	assert.Equal(t, []byte("secret"), decoded.Chaincode.Args[2])
+
+	_, err = NewDecoder().DecodeProposal([]byte("invalid"))
+	assert.Error(t, err)
+}
+
+func TestDecodeTransaction(t *testing.T) {
+	creator := newSigner(t, "Org1MSP", "user1")
+	endorser1 := newSigner(t, "Org1MSP", "peer0.org1")
+	endorser2 := newSigner(t, "Org2MSP", "peer0.org2")
+
+	proposal := newProposal(t, creator)
+
+	txRWSet := &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{{
+		NameSpace: "examplecc",
+		KvRwSet: &kvrwset.KVRWSet{
+			Reads:  []*kvrwset.KVRead{{Key: "a", Version: &kvrwset.Version{BlockNum: 5, TxNum: 1}}, {Key: "b"}},
+			Writes: []*kvrwset.KVWrite{{Key: "a", Value: []byte("10")}, {Key: "c", IsDelete: true}},
+		},
+		CollHashedRwSets: []*rwsetutil.CollHashedRwSet{{
+			CollectionName: "coll1",
+			HashedRwSet: &kvrwset.HashedRWSet{
+				HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte("keyhash1"), ValueHash: []byte("valuehash1")}, {KeyHash: []byte("keyhash2"), IsDelete: true, IsPurge: true}},
+			},
+			PvtRwSetHash: []byte("pvthash"),
+		}},
+	}}}
+	results, err := txRWSet.ToProtoBytes()
+	require.NoError(t, err)
+
+	prp, err := utils.GetBytesProposalResponsePayload([]byte("hash"), &pb.Response{Status: 200, Payload: []byte("ok")}, results, nil, &pb.ChaincodeID{Name: "examplecc", Version: "v1"})
+	require.NoError(t, err)
+
+	var endorsements []*pb.Endorsement
+	for _, e := range []*signer{endorser1, endorser2} {
+		endorsements = append(endorsements, &pb.Endorsement{Endorser: e.serializedID, Signature: e.sign(t, append(append([]byte(nil), prp...), e.serializedID...))})
+	}
+	// Invalid endorsement signature
+	endorsements[1].Signature = endorser1.sign(t, prp)
+
+	ccProposalPayload, err := utils.GetBytesProposalPayloadForTx(mustChaincodeProposalPayload(t, proposal), nil)
+	require.NoError(t, err)
+	ccActionPayload, err := utils.GetBytesChaincodeActionPayload(&pb.ChaincodeActionPayload{
+		ChaincodeProposalPayload: ccProposalPayload,
+		Action:                   &pb.ChaincodeEndorsedAction{ProposalResponsePayload: prp, Endorsements: endorsements},
+	})
+	require.NoError(t, err)
+
+	hdr, err := utils.GetHeader(proposal.Header)
+	require.NoError(t, err)
+	txBytes, err := utils.GetBytesTransaction(&pb.Transaction{Actions: []*pb.TransactionAction{{Header: hdr.SignatureHeader, Payload: ccActionPayload}}})
+	require.NoError(t, err)
+	payload, err := utils.GetBytesPayload(&cb.Payload{Header: hdr, Data: txBytes})
+	require.NoError(t, err)
+	envBytes, err := utils.GetBytesEnvelope(&cb.Envelope{Payload: payload, Signature: creator.sign(t, payload)})
+	require.NoError(t, err)
+
+	tx, err := NewDecoder().DecodeTransaction(envBytes)
+	require.NoError(t, err)
+
+	assert.Equal(t, "txid", tx.Header.TxID)
+	assert.True(t, tx.Signature.Verified)
+	require.Len(t, tx.Actions, 1)
+
+	action := tx.Actions[0]
+	assert.Equal(t, "examplecc", action.Chaincode.Name)
+	assert.Equal(t, "v1", action.Chaincode.Version)
+	assert.Empty(t, action.Chaincode.TransientKeys, "expecting transient data to be removed from the transaction")
+	assert.Equal(t, int32(200), action.ResponseStatus)
+	assert.Equal(t, []byte("ok"), action.ResponsePayload)
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP"}, action.EndorsingOrgs)
+	require.Len(t, action.Endorsements, 2)
+	assert.True(t, action.Endorsements[0].Signature.Verified)
+	assert.False(t, action.Endorsements[1].Signature.Verified)
+
+	require.Len(t, action.RWSets, 1)
+	assert.Equal(t, []KVRead{{Key: "a", BlockNum: 5, TxNum: 1, Exists: true}, {Key: "b"}}, action.RWSets[0].Reads)
+	assert.Equal(t, []KVWrite{{Key: "a", Value: []byte("10")}, {Key: "c", IsDelete: true}}, action.RWSets[0].Writes)
+	require.Len(t, action.RWSets[0].HashedRWSets, 1)
+	assert.Equal(t, []KVWriteHash{{KeyHash: []byte("keyhash1"), ValueHash: []byte("valuehash1")}, {KeyHash: []byte("keyhash2"), IsDelete: true, IsPurge: true}}, action.RWSets[0].HashedRWSets[0].Writes)
+
+	// A verifier that doesn't accept Org2MSP
+	tx, err = NewDecoder(WithVerifier(&mockVerifier{invalidMSP: "Org2MSP"})).DecodeTransaction(envBytes)
+	require.NoError(t, err)
+	assert.True(t, tx.Signature.Verified)
+	assert.True(t, tx.Actions[0].Endorsements[0].Signature.Verified)
+	assert.False(t, tx.Actions[0].Endorsements[1].Signature.Verified)
+	assert.Contains(t, tx.Actions[0].Endorsements[1].Signature.Error, "identity is not valid")
+}
+
+func mustChaincodeProposalPayload(t *testing.T, proposal *pb.Proposal) *pb.ChaincodeProposalPayload {
+	payload, err := utils.GetChaincodeProposalPayload(proposal.Payload)
+	require.NoError(t, err)
+	return payload
+}
+
+type mockVerifier struct {
+	invalidMSP string
+}
+
+func (v *mockVerifier) Validate(serializedID []byte) error {
+	sID := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, sID); err != nil {
+		return err
+	}
+	if sID.Mspid == v.invalidMSP {
+		return errors.Errorf("MSP %s is not a member of the channel", sID.Mspid)
+	}
+	return nil
+}
+
+func (v *mockVerifier) Verify(serializedID []byte, msg []byte, sig []byte) error {
+	return VerifySignature(serializedID, msg, sig)
+}
@@ -51,3 +51,10 @@ func (t *MockTransactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionR
 	defer cancel()
 	return txn.Send(rqtx, tx, t.Orderers)
 }
+
+// SendEnvelope sends a previously signed transaction envelope to the orderers.
+func (t *MockTransactor) SendEnvelope(envelope *fab.SignedEnvelope) (*fab.TransactionResponse, error) {
+	rqtx, cancel := contextImpl.NewRequest(t.Ctx, contextImpl.WithTimeout(10*time.Second))
+	defer cancel()
+	return txn.BroadcastEnvelope(rqtx, envelope, t.Orderers)
+}
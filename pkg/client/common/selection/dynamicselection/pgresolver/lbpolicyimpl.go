@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package pgresolver
 
 import (
-	"math/rand"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peerstats"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/random"
 )
 
 type randomLBP struct {
@@ -27,7 +29,7 @@ func (lbp *randomLBP) Choose(peerGroups []PeerGroup) PeerGroup {
 		return NewPeerGroup()
 	}
 
-	index := rand.Intn(len(peerGroups))
+	index := random.Intn(len(peerGroups))
 
 	logger.Debugf("randomLBP - Choosing index %d\n", index)
 	return peerGroups[index]
@@ -50,7 +52,7 @@ func (lbp *roundRobinLBP) Choose(peerGroups []PeerGroup) PeerGroup {
 	}
 
 	if lbp.index == -1 {
-		lbp.index = rand.Intn(len(peerGroups))
+		lbp.index = random.Intn(len(peerGroups))
 	} else {
 		lbp.index++
 	}
@@ -63,3 +65,55 @@ func (lbp *roundRobinLBP) Choose(peerGroups []PeerGroup) PeerGroup {
 
 	return peerGroups[lbp.index]
 }
+
+type weightedLBP struct {
+	tracker *peerstats.Tracker
+}
+
+// NewWeightedLBP returns a load-balance policy which randomly chooses a peer group with a probability
+// proportional to the weight of the group. The weight of a group is the lowest weight of its peers, where
+// the weight of a peer is determined by its recent latency, error rate and ledger height (see peerstats).
+func NewWeightedLBP(tracker *peerstats.Tracker) LoadBalancePolicy {
+	return &weightedLBP{tracker: tracker}
+}
+
+func (lbp *weightedLBP) Choose(peerGroups []PeerGroup) PeerGroup {
+	if len(peerGroups) == 0 {
+		logger.Warn("No available peer groups\n")
+		// Return an empty PeerGroup
+		return NewPeerGroup()
+	}
+
+	var allPeers []fab.Peer
+	for _, pg := range peerGroups {
+		allPeers = append(allPeers, pg.Peers()...)
+	}
+	maxHeight := peerstats.MaxBlockHeight(allPeers)
+
+	weights := make([]float64, len(peerGroups))
+	total := 0.0
+	for i, pg := range peerGroups {
+		weights[i] = lbp.groupWeight(pg, maxHeight)
+		total += weights[i]
+	}
+
+	r := random.Float64() * total
+	for i, weight := range weights {
+		r -= weight
+		if r < 0 {
+			logger.Debugf("weightedLBP - Choosing index %d with weight %f\n", i, weight)
+			return peerGroups[i]
+		}
+	}
+	return peerGroups[len(peerGroups)-1]
+}
+
+func (lbp *weightedLBP) groupWeight(pg PeerGroup, maxHeight uint64) float64 {
+	weight := 1.0
+	for _, peer := range pg.Peers() {
+		if w := lbp.tracker.Weight(peer, maxHeight); w < weight {
+			weight = w
+		}
+	}
+	return weight
+}
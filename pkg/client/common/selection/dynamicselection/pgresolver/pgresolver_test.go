@@ -12,8 +12,10 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	mocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peerstats"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
 	common "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
 )
 
 const (
@@ -339,6 +341,25 @@ func TestPeerGroupResolverRandomPeers(t *testing.T) {
 	}
 }
 
+func TestWeightedLBP(t *testing.T) {
+	tracker := peerstats.NewTracker()
+	tracker.Record(p1.URL(), 10*time.Millisecond, true)
+
+	lbp := NewWeightedLBP(tracker)
+	assert.Empty(t, lbp.Choose(nil).Peers())
+
+	degraded := pg(p1, p3)
+	healthy := pg(p2, p4)
+
+	chosen := 0
+	for i := 0; i < 100; i++ {
+		if lbp.Choose([]PeerGroup{degraded, healthy}) == healthy {
+			chosen++
+		}
+	}
+	assert.True(t, chosen > 80, "expecting the group without the failing peer to be chosen most of the time but was chosen %d times", chosen)
+}
+
 func testPeerGroupResolver(t *testing.T, sigPolicyEnv *common.SignaturePolicyEnvelope, peers []fab.Peer, expected []PeerGroup, expectedErr error) {
 	pgResolver, err := NewRoundRobinPeerGroupResolver(sigPolicyEnv)
 	if err != nil {
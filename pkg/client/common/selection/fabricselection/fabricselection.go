@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/discovery"
 	soptions "github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
@@ -64,13 +65,15 @@ var clientProvider = func(ctx contextAPI.Client) (discoveryClient, error) {
 // Service chooses endorsing peers for a given set of chaincodes using
 // Fabric's Discovery Service
 type Service struct {
-	channelID       string
-	responseTimeout time.Duration
-	ctx             contextAPI.Client
-	discovery       fab.DiscoveryService
-	discClient      discoveryClient
-	chResponseCache *lazycache.Cache
-	retryOpts       retry.Opts
+	channelID        string
+	responseTimeout  time.Duration
+	ctx              contextAPI.Client
+	discovery        fab.DiscoveryService
+	discClient       discoveryClient
+	chResponseCache  *lazycache.Cache
+	retryOpts        retry.Opts
+	prioritySelector soptions.PrioritySelector
+	localMSPID       string
 }
 
 // New creates a new dynamic selection service using Fabric's Discovery Service
@@ -96,12 +99,14 @@ func New(ctx contextAPI.Client, channelID string, discovery fab.DiscoveryService
 	}
 
 	s := &Service{
-		channelID:       channelID,
-		ctx:             ctx,
-		responseTimeout: options.responseTimeout,
-		discovery:       discovery,
-		discClient:      discoveryClient,
-		retryOpts:       options.retryOpts,
+		channelID:        channelID,
+		ctx:              ctx,
+		responseTimeout:  options.responseTimeout,
+		discovery:        discovery,
+		discClient:       discoveryClient,
+		retryOpts:        options.retryOpts,
+		prioritySelector: options.prioritySelector,
+		localMSPID:       options.localMSPID,
 	}
 
 	s.chResponseCache = lazycache.NewWithData(
@@ -137,7 +142,7 @@ func (s *Service) GetEndorsersForChaincode(chaincodes []*fab.ChaincodeCall, opts
 		return nil, errors.New("no chaincode IDs provided")
 	}
 
-	params := soptions.Params{RetryOpts: s.retryOpts}
+	params := soptions.Params{RetryOpts: s.retryOpts, PrioritySelector: s.prioritySelector}
 	coptions.Apply(&params, opts)
 
 	chResponse, err := s.getChannelResponse(chaincodes, params.RetryOpts)
@@ -145,11 +150,21 @@ func (s *Service) GetEndorsersForChaincode(chaincodes []*fab.ChaincodeCall, opts
 		return nil, errors.Wrapf(err, "error getting channel response for channel [%s]", s.channelID)
 	}
 
+	peerFilter := params.PeerFilter
+	if len(params.EndorsingOrgs) > 0 {
+		peerFilter = orgsPeerFilter(params.EndorsingOrgs, peerFilter)
+	}
+
 	// Execute getEndorsers with retries since the discovered peers may be out of sync with
 	// the peers returned from the endorser query and it may take a while for them to sync.
 	endpoints, err := retry.NewInvoker(retry.New(s.retryOpts)).Invoke(
 		func() (interface{}, error) {
-			return s.getEndorsers(chaincodes, chResponse, newSelector(s.ctx, params.PrioritySelector), params.PeerFilter)
+			endpoints, err := s.getEndorsers(chaincodes, chResponse, newSelector(s.ctx, params.PrioritySelector), peerFilter)
+			if err != nil && len(params.EndorsingOrgs) > 0 {
+				// Fail fast since the requested organizations may not be able to satisfy the policy at all
+				return nil, errors.Errorf("unable to satisfy the endorsement policy with the peers of organizations %v: %s", params.EndorsingOrgs, err)
+			}
+			return endpoints, err
 		},
 	)
 
@@ -172,6 +187,15 @@ func (s *Service) getEndorsers(chaincodes []*fab.ChaincodeCall, chResponse discc
 		return nil, errors.Wrapf(err, "error getting peers from discovery service for channel [%s]", s.channelID)
 	}
 
+	if s.localMSPID != "" {
+		endpoints, err := chResponse.Endorsers(asInvocationChain(chaincodes), prioritySelector, newFilter(s.ctx, localPeerFilter(s.localMSPID, peerFilter), peers))
+		if err == nil && len(endpoints) > 0 {
+			return endpoints, nil
+		}
+		logger.Debugf("Endorsement policy can't be satisfied by available peers of [%s] (%v) - selecting peers of other organizations", s.localMSPID, err)
+		prioritySelector = &localOrgSelector{mspID: s.localMSPID, next: prioritySelector}
+	}
+
 	endpoints, err := chResponse.Endorsers(asInvocationChain(chaincodes), prioritySelector, newFilter(s.ctx, peerFilter, peers))
 	if err != nil && newDiscoveryError(err).isTransient() {
 		return nil, status.New(status.DiscoveryServerStatus, int32(status.QueryEndorsers), fmt.Sprintf("error getting endorsers: %s", err), []interface{}{})
@@ -293,13 +317,33 @@ func asInvocationChain(chaincodes []*fab.ChaincodeCall) discclient.InvocationCha
 	var invocChain discclient.InvocationChain
 	for _, cc := range chaincodes {
 		invocChain = append(invocChain, &discovery.ChaincodeCall{
-			Name:            cc.ID,
-			CollectionNames: cc.Collections,
+			Name:             cc.ID,
+			CollectionNames:  cc.Collections,
+			XXX_unrecognized: disseminationHints(cc),
 		})
 	}
 	return invocChain
 }
 
+// Field numbers of the ChaincodeCall hints which were added to the discovery protocol in Fabric 2.0
+const (
+	noPrivateReadsField = 3
+	noPublicWritesField = 4
+)
+
+// disseminationHints returns the marshalled hints of the chaincode call. The generated discovery
+// protos predate the hints so they're passed as unrecognized fields (older peers ignore them).
+func disseminationHints(cc *fab.ChaincodeCall) []byte {
+	var hints []byte
+	if cc.NoPrivateReads {
+		hints = append(proto.EncodeVarint(noPrivateReadsField<<3), 1)
+	}
+	if cc.NoPublicWrites {
+		hints = append(append(hints, proto.EncodeVarint(noPublicWritesField<<3)...), 1)
+	}
+	return hints
+}
+
 func asPeers(ctx contextAPI.Client, endpoints []*discclient.Peer) []fab.Peer {
 	var peers []fab.Peer
 	for _, endpoint := range endpoints {
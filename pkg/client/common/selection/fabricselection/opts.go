@@ -9,15 +9,18 @@ package fabricselection
 import (
 	"time"
 
+	soptions "github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 
 	coptions "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 )
 
 type params struct {
-	refreshInterval time.Duration
-	responseTimeout time.Duration
-	retryOpts       retry.Opts
+	refreshInterval  time.Duration
+	responseTimeout  time.Duration
+	retryOpts        retry.Opts
+	prioritySelector soptions.PrioritySelector
+	localMSPID       string
 }
 
 // WithRefreshInterval sets the interval in which the
@@ -52,6 +55,28 @@ func WithRetryOpts(value retry.Opts) coptions.Opt {
 	}
 }
 
+// WithPrioritySelector sets the priority selector which is used for requests
+// that don't provide their own priority selector
+func WithPrioritySelector(value soptions.PrioritySelector) coptions.Opt {
+	return func(p coptions.Params) {
+		logger.Debug("Checking prioritySelectorSetter")
+		if setter, ok := p.(prioritySelectorSetter); ok {
+			setter.SetPrioritySelector(value)
+		}
+	}
+}
+
+// WithLocalOrgPreference selects only the peers of the given (local) MSP if they satisfy the endorsement
+// policy. Otherwise the peers of other MSPs are also selected but the local peers are still preferred.
+func WithLocalOrgPreference(mspID string) coptions.Opt {
+	return func(p coptions.Params) {
+		logger.Debug("Checking localMSPIDSetter")
+		if setter, ok := p.(localMSPIDSetter); ok {
+			setter.SetLocalMSPID(mspID)
+		}
+	}
+}
+
 type refreshIntervalSetter interface {
 	SetRefreshInterval(value time.Duration)
 }
@@ -64,6 +89,14 @@ type retryOptsSetter interface {
 	SetRetryOpts(value retry.Opts)
 }
 
+type prioritySelectorSetter interface {
+	SetPrioritySelector(value soptions.PrioritySelector)
+}
+
+type localMSPIDSetter interface {
+	SetLocalMSPID(value string)
+}
+
 func (o *params) SetRefreshInterval(value time.Duration) {
 	logger.Debugf("RefreshInterval: %s", value)
 	o.refreshInterval = value
@@ -78,3 +111,13 @@ func (o *params) SetRetryOpts(value retry.Opts) {
 	logger.Debugf("RetryOpts: %#v", value)
 	o.retryOpts = value
 }
+
+func (o *params) SetPrioritySelector(value soptions.PrioritySelector) {
+	logger.Debugf("PrioritySelector: %#v", value)
+	o.prioritySelector = value
+}
+
+func (o *params) SetLocalMSPID(value string) {
+	logger.Debugf("LocalMSPID: %s", value)
+	o.localMSPID = value
+}
@@ -7,11 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package fabricselection
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/gossip"
 	clientmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
 	contextAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
@@ -275,6 +279,145 @@ func TestWithDiscoveryFilter(t *testing.T) {
 	})
 }
 
+func TestLocalOrgPreference(t *testing.T) {
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("test", mspID1))
+	config := &config{
+		EndpointConfig: mocks.NewMockEndpointConfig(),
+		peers:          channelPeers,
+	}
+	ctx.SetEndpointConfig(config)
+
+	discClient := clientmocks.NewMockDiscoveryClient()
+	clientProvider = func(ctx contextAPI.Client) (discoveryClient, error) {
+		return discClient, nil
+	}
+
+	discClient.SetResponses(
+		&clientmocks.MockDiscoverEndpointResponse{
+			PeerEndpoints: []*discmocks.MockDiscoveryPeerEndpoint{
+				peer2Org1Endpoint, peer2Org3Endpoint, peer2Org2Endpoint,
+				peer1Org1Endpoint, peer1Org2Endpoint, peer1Org3Endpoint,
+			},
+		},
+	)
+
+	t.Run("Local Peers", func(t *testing.T) {
+		service, err := New(
+			ctx, channelID,
+			mocks.NewMockDiscoveryService(nil, peer1Org1, peer2Org1, peer1Org2, peer2Org2, peer1Org3, peer2Org3),
+			WithLocalOrgPreference(mspID1),
+		)
+		require.NoError(t, err)
+		defer service.Close()
+
+		endorsers, err := service.GetEndorsersForChaincode([]*fab.ChaincodeCall{cc1ChaincodeCall})
+		require.NoError(t, err)
+		require.Equalf(t, 2, len(endorsers), "Expecting only the endorsers of the local org")
+		for _, endorser := range endorsers {
+			assert.Equal(t, mspID1, endorser.MSPID())
+		}
+	})
+
+	t.Run("Local Peers Down", func(t *testing.T) {
+		service, err := New(
+			ctx, channelID,
+			mocks.NewMockDiscoveryService(nil, peer2Org2, peer2Org3),
+			WithLocalOrgPreference(mspID1),
+		)
+		require.NoError(t, err)
+		defer service.Close()
+
+		endorsers, err := service.GetEndorsersForChaincode([]*fab.ChaincodeCall{cc1ChaincodeCall})
+		require.NoError(t, err)
+		assert.Equalf(t, 2, len(endorsers), "Expecting fail-over to the endorsers of other orgs")
+	})
+}
+
+func TestEndorsingOrgs(t *testing.T) {
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("test", mspID1))
+	config := &config{
+		EndpointConfig: mocks.NewMockEndpointConfig(),
+		peers:          channelPeers,
+	}
+	ctx.SetEndpointConfig(config)
+
+	discClient := clientmocks.NewMockDiscoveryClient()
+	clientProvider = func(ctx contextAPI.Client) (discoveryClient, error) {
+		return discClient, nil
+	}
+
+	discClient.SetResponses(
+		&clientmocks.MockDiscoverEndpointResponse{
+			PeerEndpoints: []*discmocks.MockDiscoveryPeerEndpoint{
+				peer2Org1Endpoint, peer2Org3Endpoint, peer2Org2Endpoint,
+				peer1Org1Endpoint, peer1Org2Endpoint, peer1Org3Endpoint,
+			},
+		},
+	)
+
+	service, err := New(
+		ctx, channelID,
+		mocks.NewMockDiscoveryService(nil, peer1Org1, peer2Org1, peer1Org2, peer2Org2, peer1Org3, peer2Org3),
+	)
+	require.NoError(t, err)
+	defer service.Close()
+
+	endorsers, err := service.GetEndorsersForChaincode([]*fab.ChaincodeCall{cc1ChaincodeCall}, options.WithEndorsingOrgs(mspID2, mspID3))
+	require.NoError(t, err)
+	assert.Equalf(t, 4, len(endorsers), "Expecting the endorsers of Org2 and Org3")
+	for _, endorser := range endorsers {
+		assert.NotEqual(t, mspID1, endorser.MSPID())
+	}
+
+	endorsers, err = service.GetEndorsersForChaincode([]*fab.ChaincodeCall{cc1ChaincodeCall},
+		options.WithEndorsingOrgs(mspID2),
+		options.WithPeerFilter(func(peer fab.Peer) bool {
+			return peer.(fab.PeerState).BlockHeight() > 1001
+		}),
+	)
+	require.NoError(t, err)
+	require.Equalf(t, 1, len(endorsers), "Expecting the endorsers of Org2 which are accepted by the peer filter")
+	assert.Equal(t, peer2Org2URL, endorsers[0].URL())
+}
+
+func TestLocalOrgSelector(t *testing.T) {
+	selector := &localOrgSelector{mspID: mspID1, next: discclient.PrioritiesByHeight}
+
+	local := discclient.Peer{MSPID: mspID1, StateInfoMessage: stateInfo(1000)}
+	remote := discclient.Peer{MSPID: mspID2, StateInfoMessage: stateInfo(2000)}
+	localHigher := discclient.Peer{MSPID: mspID1, StateInfoMessage: stateInfo(1001)}
+
+	assert.True(t, selector.Compare(local, remote) > 0, "expecting local peer to be preferred over a remote peer with a higher ledger")
+	assert.True(t, selector.Compare(remote, local) < 0, "expecting local peer to be preferred over a remote peer with a higher ledger")
+	assert.True(t, selector.Compare(localHigher, local) > 0, "expecting local peers to be compared with the next selector")
+}
+
+func TestDisseminationHints(t *testing.T) {
+	invocChain := asInvocationChain([]*fab.ChaincodeCall{
+		{ID: cc1},
+		{ID: cc2, Collections: []string{cc2Col1}, NoPrivateReads: true, NoPublicWrites: true},
+	})
+	require.Len(t, invocChain, 2)
+	assert.Empty(t, invocChain[0].XXX_unrecognized)
+
+	msg, err := proto.Marshal(invocChain[1])
+	require.NoError(t, err)
+	assert.True(t, bytes.HasSuffix(msg, []byte{noPrivateReadsField << 3, 1, noPublicWritesField << 3, 1}), "expecting hints to be marshalled as fields 3 and 4")
+
+	assert.Equal(t, []byte{noPublicWritesField << 3, 1}, disseminationHints(&fab.ChaincodeCall{ID: cc1, NoPublicWrites: true}))
+	assert.NotEqual(t, newCacheKey([]*fab.ChaincodeCall{{ID: cc1}}).String(), newCacheKey([]*fab.ChaincodeCall{{ID: cc1, NoPrivateReads: true}}).String())
+}
+
+func stateInfo(ledgerHeight uint64) *gossip.SignedGossipMessage {
+	return &gossip.SignedGossipMessage{
+		GossipMessage: &gossip.GossipMessage{
+			Content: &gossip.GossipMessage_StateInfo{
+				StateInfo: &gossip.StateInfo{Properties: &gossip.Properties{LedgerHeight: ledgerHeight}},
+			},
+		},
+	}
+}
+
 type config struct {
 	fab.EndpointConfig
 	peers []fab.ChannelPeer
@@ -68,6 +68,49 @@ func (s *prioritySelector) Compare(endpoint1, endpoint2 discclient.Peer) disccli
 	return discclient.Priority(s.selector(asPeerValue(s.ctx, &endpoint1), asPeerValue(s.ctx, &endpoint2)))
 }
 
+// localPeerFilter returns a peer filter which only accepts the peers of the given MSP (and which
+// are accepted by the given filter, if any)
+func localPeerFilter(mspID string, filter options.PeerFilter) options.PeerFilter {
+	return func(peer fab.Peer) bool {
+		if peer.MSPID() != mspID {
+			return false
+		}
+		return filter == nil || filter(peer)
+	}
+}
+
+// orgsPeerFilter returns a peer filter which only accepts the peers of the given MSPs (and which
+// are accepted by the given filter, if any)
+func orgsPeerFilter(mspIDs []string, filter options.PeerFilter) options.PeerFilter {
+	return func(peer fab.Peer) bool {
+		for _, mspID := range mspIDs {
+			if peer.MSPID() == mspID {
+				return filter == nil || filter(peer)
+			}
+		}
+		return false
+	}
+}
+
+// localOrgSelector prioritizes the peers of the given MSP over the peers of other MSPs. Peers of the
+// same MSP are compared with the next selector.
+type localOrgSelector struct {
+	mspID string
+	next  discclient.PrioritySelector
+}
+
+func (s *localOrgSelector) Compare(endpoint1, endpoint2 discclient.Peer) discclient.Priority {
+	local1 := endpoint1.MSPID == s.mspID
+	local2 := endpoint2.MSPID == s.mspID
+	if local1 && !local2 {
+		return 1
+	}
+	if local2 && !local1 {
+		return -1
+	}
+	return s.next.Compare(endpoint1, endpoint2)
+}
+
 // asPeerValue converts the discovery endpoint into a light-weight peer value (i.e. without the GRPC config)
 // so that it may used by a peer filter
 func asPeerValue(ctx contextAPI.Client, endpoint *discclient.Peer) fab.Peer {
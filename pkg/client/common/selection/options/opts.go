@@ -30,6 +30,7 @@ type Params struct {
 	PeerFilter       PeerFilter
 	PrioritySelector PrioritySelector
 	RetryOpts        retry.Opts
+	EndorsingOrgs    []string
 }
 
 // NewParams creates new parameters based on the provided options
@@ -68,6 +69,15 @@ func WithRetryOpts(value retry.Opts) copts.Opt {
 	}
 }
 
+// WithEndorsingOrgs restricts the endorsers to the peers of the given organizations (MSP IDs)
+func WithEndorsingOrgs(mspIDs ...string) copts.Opt {
+	return func(p copts.Params) {
+		if setter, ok := p.(endorsingOrgsSetter); ok {
+			setter.SetEndorsingOrgs(mspIDs)
+		}
+	}
+}
+
 type peerFilterSetter interface {
 	SetPeerFilter(value PeerFilter)
 }
@@ -97,3 +107,13 @@ func (p *Params) SetRetryOpts(value retry.Opts) {
 	logger.Debugf("RetryOpts: %#+v", value)
 	p.RetryOpts = value
 }
+
+type endorsingOrgsSetter interface {
+	SetEndorsingOrgs(value []string)
+}
+
+// SetEndorsingOrgs sets the endorsing organizations
+func (p *Params) SetEndorsingOrgs(value []string) {
+	logger.Debugf("EndorsingOrgs: %v", value)
+	p.EndorsingOrgs = value
+}
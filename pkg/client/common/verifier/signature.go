@@ -42,7 +42,12 @@ func (v *Signature) Verify(response *fab.TransactionProposalResponse) error {
 	}
 	creatorID := res.GetEndorsement().Endorser
 
-	err := v.Membership.Validate(creatorID)
+	var err error
+	if ev, ok := v.Membership.(fab.EndorserValidator); ok {
+		err = ev.ValidateEndorser(creatorID, response.EndorserMSPID)
+	} else {
+		err = v.Membership.Validate(creatorID)
+	}
 	if err != nil {
 		return errors.WithStack(status.New(status.EndorserClientStatus, status.SignatureVerificationFailed.ToInt32(), "the creator certificate is not valid", []interface{}{err.Error()}))
 	}
@@ -0,0 +1,514 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package connprofile generates a connection profile (SDK configuration) from a live network. Starting
+// from a single bootstrap peer, the generator queries the channels joined by the peer, their configuration
+// (organizations, orderer addresses and TLS CA certificates) and the peers found by discovery, and returns
+// a profile which references all of them.
+//
+// Certificate authorities aren't part of the channel configuration, so the generated profile has no
+// certificateAuthorities section; neither does it contain the client's crypto or credential store settings.
+//
+//  Basic Flow:
+//  1) Create an SDK with BootstrapConfig and prepare the context of the admin identity
+//  2) Create generator
+//  3) Generate the profile and write it out as YAML
+package connprofile
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const profileVersion = "1.0.0"
+
+// Profile is a connection profile. It is marshalled to YAML in the format read by the SDK's configuration.
+type Profile struct {
+	Version       string                  `yaml:"version"`
+	Client        Client                  `yaml:"client"`
+	Channels      map[string]Channel      `yaml:"channels,omitempty"`
+	Organizations map[string]Organization `yaml:"organizations"`
+	Orderers      map[string]Endpoint     `yaml:"orderers,omitempty"`
+	Peers         map[string]Endpoint     `yaml:"peers"`
+}
+
+// Client contains the client section of the profile
+type Client struct {
+	Organization    string           `yaml:"organization"`
+	CredentialStore *CredentialStore `yaml:"credentialStore,omitempty"`
+	BCCSP           BCCSP            `yaml:"BCCSP"`
+}
+
+// CredentialStore contains the location of the client's state store
+type CredentialStore struct {
+	Path string `yaml:"path"`
+}
+
+// BCCSP contains the crypto provider settings of the client
+type BCCSP struct {
+	Security BCCSPSecurity `yaml:"security"`
+}
+
+// BCCSPSecurity contains the security settings of the crypto provider
+type BCCSPSecurity struct {
+	Enabled       bool              `yaml:"enabled"`
+	Default       map[string]string `yaml:"default"`
+	HashAlgorithm string            `yaml:"hashAlgorithm"`
+	SoftVerify    bool              `yaml:"softVerify"`
+	Level         int               `yaml:"level"`
+}
+
+// newClient returns the client section with the default (software) crypto provider
+func newClient(org string) Client {
+	return Client{
+		Organization: org,
+		BCCSP: BCCSP{Security: BCCSPSecurity{
+			Enabled:       true,
+			Default:       map[string]string{"provider": "SW"},
+			HashAlgorithm: "SHA2",
+			SoftVerify:    true,
+			Level:         256,
+		}},
+	}
+}
+
+// Channel contains the orderers and peers of a channel
+type Channel struct {
+	Orderers []string               `yaml:"orderers,omitempty"`
+	Peers    map[string]ChannelPeer `yaml:"peers"`
+}
+
+// ChannelPeer contains the roles of a peer in a channel
+type ChannelPeer struct {
+	EndorsingPeer  bool `yaml:"endorsingPeer"`
+	ChaincodeQuery bool `yaml:"chaincodeQuery"`
+	LedgerQuery    bool `yaml:"ledgerQuery"`
+	EventSource    bool `yaml:"eventSource"`
+}
+
+// Organization contains the MSP ID and the peers of an organization
+type Organization struct {
+	MSPID string          `yaml:"mspid"`
+	Peers []string        `yaml:"peers,omitempty"`
+	Users map[string]User `yaml:"users,omitempty"`
+}
+
+// User contains the embedded certificate and private key of a user
+type User struct {
+	Cert PEM `yaml:"cert"`
+	Key  PEM `yaml:"key"`
+}
+
+// Endpoint contains the URL and the TLS CA certificate of a peer or an orderer
+type Endpoint struct {
+	URL         string                 `yaml:"url"`
+	GRPCOptions map[string]interface{} `yaml:"grpcOptions,omitempty"`
+	TLSCACerts  *PEM                   `yaml:"tlsCACerts,omitempty"`
+}
+
+// PEM contains an embedded PEM encoded certificate or key
+type PEM struct {
+	Pem string `yaml:"pem"`
+}
+
+// YAML returns the profile in YAML format
+func (p *Profile) YAML() ([]byte, error) {
+	bytes, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal profile")
+	}
+	return bytes, nil
+}
+
+// AdminUser is the name of the admin identity in the bootstrap configuration
+const AdminUser = "Admin"
+
+// Bootstrap contains the peer from which the network is discovered and the admin identity
+// of the peer's organization
+type Bootstrap struct {
+	// PeerURL is the address of the peer (grpcs://host:port or grpc://host:port)
+	PeerURL string
+	// PeerTLSCACert is the PEM encoded TLS CA certificate of the peer (required for grpcs)
+	PeerTLSCACert []byte
+	// MSPID is the MSP ID of the peer's organization
+	MSPID string
+	// AdminCert and AdminKey are the PEM encoded certificate and private key of the admin identity
+	AdminCert []byte
+	AdminKey  []byte
+	// StateStorePath is the directory of the SDK's state store (defaults to a directory in the system's temp directory)
+	StateStorePath string
+}
+
+// BootstrapConfig returns a minimal SDK configuration which contains the bootstrap peer and its organization
+// with the admin identity as user AdminUser. The configuration is used to create the context of the generator, e.g.:
+//
+//  sdk, err := fabsdk.New(connprofile.BootstrapConfig(bootstrap))
+//  generator, err := connprofile.New(sdk.Context(fabsdk.WithUser(connprofile.AdminUser)), bootstrap.PeerURL)
+func BootstrapConfig(bootstrap Bootstrap) core.ConfigProvider {
+	org := orgName(bootstrap.MSPID)
+	peer := hostOf(trimProtocol(bootstrap.PeerURL))
+
+	stateStorePath := bootstrap.StateStorePath
+	if stateStorePath == "" {
+		stateStorePath = filepath.Join(os.TempDir(), "connprofile-state-store")
+	}
+	client := newClient(org)
+	client.CredentialStore = &CredentialStore{Path: stateStorePath}
+
+	profile := &Profile{
+		Version: profileVersion,
+		Client:  client,
+		Organizations: map[string]Organization{
+			org: {
+				MSPID: bootstrap.MSPID,
+				Peers: []string{peer},
+				Users: map[string]User{AdminUser: {Cert: PEM{Pem: string(bootstrap.AdminCert)}, Key: PEM{Pem: string(bootstrap.AdminKey)}}},
+			},
+		},
+		Peers: map[string]Endpoint{peer: newEndpoint(bootstrap.PeerURL, bootstrap.PeerTLSCACert)},
+	}
+
+	bytes, err := profile.YAML()
+	if err != nil {
+		return func() ([]core.ConfigBackend, error) {
+			return nil, err
+		}
+	}
+	return config.FromRaw(bytes, "yaml")
+}
+
+// channelInfo contains the configuration and peers of a channel
+type channelInfo struct {
+	config *configtx.Config
+	peers  []discovery.Peer
+}
+
+// network queries the channels, channel configuration and peers from the bootstrap peer
+type network interface {
+	Channels() ([]string, error)
+	ConfigBlock(channelID string) (*common.Block, error)
+	ChannelPeers(channelID string) ([]discovery.Peer, error)
+}
+
+// Generator generates connection profiles
+type Generator struct {
+	mspID   string
+	network network
+}
+
+// New returns a generator which discovers the network from the given bootstrap peer (name or URL in the
+// SDK configuration). The identity of the context must belong to an organization of the channels and be
+// allowed to query the peer's joined channels (e.g. an admin).
+func New(ctxProvider context.ClientProvider, bootstrapPeer string) (*Generator, error) {
+	ctx, err := ctxProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create client context")
+	}
+
+	resClient, err := resmgmt.New(ctxProvider)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create resource management client")
+	}
+	discClient, err := discovery.New(ctxProvider)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create discovery client")
+	}
+
+	return &Generator{
+		mspID:   ctx.Identifier().MSPID,
+		network: &peerNetwork{ctx: ctx, bootstrap: bootstrapPeer, resClient: resClient, discClient: discClient},
+	}, nil
+}
+
+// Generate returns the profile of the given channels. If no channels are given, the channels joined
+// by the bootstrap peer are used.
+func (g *Generator) Generate(channels ...string) (*Profile, error) {
+	if len(channels) == 0 {
+		var err error
+		channels, err = g.network.Channels()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to query channels of bootstrap peer")
+		}
+		if len(channels) == 0 {
+			return nil, errors.New("bootstrap peer hasn't joined any channel")
+		}
+	}
+
+	infos := make(map[string]*channelInfo)
+	for _, channelID := range channels {
+		block, err := g.network.ConfigBlock(channelID)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("failed to query config block of channel [%s]", channelID))
+		}
+		cfg, err := configtx.DecodeConfigBlock(block)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("failed to decode config block of channel [%s]", channelID))
+		}
+		peers, err := g.network.ChannelPeers(channelID)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("failed to discover peers of channel [%s]", channelID))
+		}
+		infos[channelID] = &channelInfo{config: cfg, peers: peers}
+	}
+
+	return newProfile(g.mspID, infos)
+}
+
+// newProfile merges the organizations, orderers and peers of all channels into a profile
+func newProfile(mspID string, channels map[string]*channelInfo) (*Profile, error) {
+	profile := &Profile{
+		Version:       profileVersion,
+		Channels:      make(map[string]Channel),
+		Organizations: make(map[string]Organization),
+		Orderers:      make(map[string]Endpoint),
+		Peers:         make(map[string]Endpoint),
+	}
+
+	orgPeers := make(map[string]map[string]bool)
+	for channelID, info := range channels {
+		orgs := organizations(info.config)
+		for _, org := range orgs {
+			name := orgName(org.Name)
+			if _, ok := profile.Organizations[name]; !ok {
+				profile.Organizations[name] = Organization{MSPID: org.MSPID}
+				orgPeers[name] = make(map[string]bool)
+			}
+		}
+
+		channel := Channel{Peers: make(map[string]ChannelPeer)}
+		for _, address := range ordererAddresses(info.config) {
+			name := hostOf(address)
+			if _, ok := profile.Orderers[name]; !ok {
+				profile.Orderers[name] = newEndpoint(address, ordererTLSCACert(info.config, name))
+			}
+			channel.Orderers = append(channel.Orderers, name)
+		}
+
+		for _, peer := range info.peers {
+			org, ok := orgs[peer.MSPID]
+			if !ok {
+				logger.Warnf("Ignoring peer [%s] of channel [%s]: MSP [%s] not found in channel configuration", peer.Endpoint, channelID, peer.MSPID)
+				continue
+			}
+			name := hostOf(peer.Endpoint)
+			if _, ok := profile.Peers[name]; !ok {
+				profile.Peers[name] = newEndpoint(peer.Endpoint, firstCert(org.TLSRootCerts))
+			}
+			orgPeers[orgName(org.Name)][name] = true
+			channel.Peers[name] = ChannelPeer{EndorsingPeer: true, ChaincodeQuery: true, LedgerQuery: true, EventSource: true}
+		}
+		profile.Channels[channelID] = channel
+	}
+
+	for name, org := range profile.Organizations {
+		for peer := range orgPeers[name] {
+			org.Peers = append(org.Peers, peer)
+		}
+		sort.Strings(org.Peers)
+		profile.Organizations[name] = org
+
+		if org.MSPID == mspID {
+			profile.Client = newClient(name)
+		}
+	}
+	if profile.Client.Organization == "" {
+		return nil, errors.Errorf("organization of MSP [%s] not found in channel configuration", mspID)
+	}
+
+	return profile, nil
+}
+
+// organizations returns the application and orderer organizations of a channel by MSP ID
+func organizations(cfg *configtx.Config) map[string]*configtx.Organization {
+	orgs := make(map[string]*configtx.Organization)
+	if cfg.Orderer != nil {
+		for _, org := range cfg.Orderer.Organizations {
+			orgs[org.MSPID] = org
+		}
+	}
+	if cfg.Application != nil {
+		for _, org := range cfg.Application.Organizations {
+			orgs[org.MSPID] = org
+		}
+	}
+	return orgs
+}
+
+// ordererAddresses returns the orderer addresses of a channel. If the channel has no global orderer
+// addresses, the addresses of the Raft consenters are used.
+func ordererAddresses(cfg *configtx.Config) []string {
+	if len(cfg.OrdererAddresses) > 0 || cfg.Orderer == nil {
+		return cfg.OrdererAddresses
+	}
+
+	var addresses []string
+	for _, consenter := range cfg.Orderer.Consenters {
+		addresses = append(addresses, net.JoinHostPort(consenter.Host, fmt.Sprint(consenter.Port)))
+	}
+	return addresses
+}
+
+// ordererTLSCACert returns the TLS root certificate of the orderer organization which issued the TLS
+// certificate of the (Raft) consenter with the given host. If the issuer isn't found, the TLS root
+// certificate of the first orderer organization is used.
+func ordererTLSCACert(cfg *configtx.Config, host string) []byte {
+	if cfg.Orderer == nil {
+		return nil
+	}
+
+	var names []string
+	for name := range cfg.Orderer.Organizations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, consenter := range cfg.Orderer.Consenters {
+		if consenter.Host != host {
+			continue
+		}
+		serverCert, err := parseCert(consenter.ServerTLSCert)
+		if err != nil {
+			logger.Debugf("Invalid server TLS certificate of consenter [%s]: %s", host, err)
+			break
+		}
+		for _, name := range names {
+			for _, rootCert := range cfg.Orderer.Organizations[name].TLSRootCerts {
+				if isIssuedBy(serverCert, rootCert) {
+					return rootCert
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		if cert := firstCert(cfg.Orderer.Organizations[name].TLSRootCerts); cert != nil {
+			return cert
+		}
+	}
+	return nil
+}
+
+func isIssuedBy(cert *x509.Certificate, issuerPEM []byte) bool {
+	issuer, err := parseCert(issuerPEM)
+	if err != nil {
+		return false
+	}
+	return cert.CheckSignatureFrom(issuer) == nil
+}
+
+func parseCert(pemBytes []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func firstCert(certs [][]byte) []byte {
+	if len(certs) == 0 {
+		return nil
+	}
+	return certs[0]
+}
+
+// newEndpoint returns the endpoint with the given address. TLS is used if a TLS CA certificate is given.
+func newEndpoint(address string, tlsCACert []byte) Endpoint {
+	address = trimProtocol(address)
+	if len(tlsCACert) == 0 {
+		return Endpoint{URL: "grpc://" + address, GRPCOptions: map[string]interface{}{"allow-insecure": true}}
+	}
+	return Endpoint{
+		URL:         "grpcs://" + address,
+		GRPCOptions: map[string]interface{}{"ssl-target-name-override": hostOf(address)},
+		TLSCACerts:  &PEM{Pem: string(tlsCACert)},
+	}
+}
+
+func trimProtocol(url string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(url, "grpcs://"), "grpc://")
+}
+
+func hostOf(address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return host
+}
+
+// orgName returns the name of an organization in the profile
+func orgName(name string) string {
+	return strings.ToLower(name)
+}
+
+// peerNetwork queries the network through the bootstrap peer
+type peerNetwork struct {
+	ctx        context.Client
+	bootstrap  string
+	resClient  *resmgmt.Client
+	discClient *discovery.Client
+}
+
+func (n *peerNetwork) Channels() ([]string, error) {
+	resp, err := n.resClient.QueryChannels(resmgmt.WithTargetEndpoints(n.bootstrap))
+	if err != nil {
+		return nil, err
+	}
+
+	var channels []string
+	for _, ch := range resp.Channels {
+		channels = append(channels, ch.ChannelId)
+	}
+	return channels, nil
+}
+
+func (n *peerNetwork) ConfigBlock(channelID string) (*common.Block, error) {
+	peerCfg, err := comm.NetworkPeerConfig(n.ctx.EndpointConfig(), n.bootstrap)
+	if err != nil {
+		return nil, err
+	}
+	peer, err := n.ctx.InfraProvider().CreatePeerFromConfig(peerCfg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create bootstrap peer")
+	}
+
+	cfg, err := chconfig.New(channelID, chconfig.WithPeers([]fab.Peer{peer}), chconfig.WithMinResponses(1))
+	if err != nil {
+		return nil, err
+	}
+
+	reqCtx, cancel := contextImpl.NewRequest(n.ctx, contextImpl.WithTimeoutType(fab.PeerResponse))
+	defer cancel()
+
+	return cfg.QueryBlock(reqCtx)
+}
+
+func (n *peerNetwork) ChannelPeers(channelID string) ([]discovery.Peer, error) {
+	return n.discClient.ChannelPeers(channelID, discovery.WithTargetEndpoints(n.bootstrap))
+}
@@ -0,0 +1,246 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connprofile
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNetwork struct {
+	channels []string
+	blocks   map[string]*common.Block
+	peers    map[string][]discovery.Peer
+}
+
+func (n *mockNetwork) Channels() ([]string, error) {
+	return n.channels, nil
+}
+
+func (n *mockNetwork) ConfigBlock(channelID string) (*common.Block, error) {
+	block, ok := n.blocks[channelID]
+	if !ok {
+		return nil, errors.Errorf("channel [%s] not found", channelID)
+	}
+	return block, nil
+}
+
+func (n *mockNetwork) ChannelPeers(channelID string) ([]discovery.Peer, error) {
+	return n.peers[channelID], nil
+}
+
+type testCA struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+}
+
+func newTestCA(t *testing.T, cn string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(raw)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw})}
+}
+
+func (ca *testCA) issue(t *testing.T, cn string) []byte {
+	cert, _ := ca.issueWithKey(t, cn)
+	return cert
+}
+
+func (ca *testCA) issueWithKey(t *testing.T, cn string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyRaw, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw}), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyRaw})
+}
+
+func newTestNetwork(t *testing.T) (*mockNetwork, map[string]*testCA) {
+	cas := map[string]*testCA{
+		"orderer1": newTestCA(t, "tlsca.orderer1.example.com"),
+		"orderer2": newTestCA(t, "tlsca.orderer2.example.com"),
+		"org1":     newTestCA(t, "tlsca.org1.example.com"),
+		"org2":     newTestCA(t, "tlsca.org2.example.com"),
+	}
+
+	newProfile := func(appOrgs ...string) *configtx.GenesisProfile {
+		app := &configtx.Application{Organizations: make(map[string]*configtx.Organization)}
+		for _, name := range appOrgs {
+			app.Organizations[name] = &configtx.Organization{Name: name, MSPID: name + "MSP", RootCerts: [][]byte{cas[name].certPEM}, TLSRootCerts: [][]byte{cas[name].certPEM}}
+		}
+		return &configtx.GenesisProfile{
+			Orderer: &configtx.Orderer{
+				ConsensusType: "etcdraft",
+				Consenters: []configtx.Consenter{
+					{Host: "orderer1.example.com", Port: 7050, ClientTLSCert: cas["orderer1"].issue(t, "orderer1"), ServerTLSCert: cas["orderer1"].issue(t, "orderer1")},
+					{Host: "orderer2.example.com", Port: 7050, ClientTLSCert: cas["orderer2"].issue(t, "orderer2"), ServerTLSCert: cas["orderer2"].issue(t, "orderer2")},
+				},
+				Organizations: map[string]*configtx.Organization{
+					"Orderer1": {Name: "Orderer1", MSPID: "Orderer1MSP", RootCerts: [][]byte{cas["orderer1"].certPEM}, TLSRootCerts: [][]byte{cas["orderer1"].certPEM}},
+					"Orderer2": {Name: "Orderer2", MSPID: "Orderer2MSP", RootCerts: [][]byte{cas["orderer2"].certPEM}, TLSRootCerts: [][]byte{cas["orderer2"].certPEM}},
+				},
+			},
+			OrdererAddresses: []string{"orderer1.example.com:7050", "orderer2.example.com:7050"},
+			Application:      app,
+		}
+	}
+
+	block1, err := configtx.NewGenesisBlock("channel1", newProfile("org1"))
+	require.NoError(t, err)
+	block2, err := configtx.NewGenesisBlock("channel2", newProfile("org1", "org2"))
+	require.NoError(t, err)
+
+	return &mockNetwork{
+		channels: []string{"channel1", "channel2"},
+		blocks:   map[string]*common.Block{"channel1": block1, "channel2": block2},
+		peers: map[string][]discovery.Peer{
+			"channel1": {{Endpoint: "peer0.org1.example.com:7051", MSPID: "org1MSP"}},
+			"channel2": {
+				{Endpoint: "peer0.org1.example.com:7051", MSPID: "org1MSP"},
+				{Endpoint: "peer1.org1.example.com:8051", MSPID: "org1MSP"},
+				{Endpoint: "peer0.org2.example.com:9051", MSPID: "org2MSP"},
+				{Endpoint: "peer0.org3.example.com:7051", MSPID: "org3MSP"},
+			},
+		},
+	}, cas
+}
+
+func TestGenerate(t *testing.T) {
+	network, cas := newTestNetwork(t)
+	g := &Generator{mspID: "org2MSP", network: network}
+
+	profile, err := g.Generate()
+	require.NoError(t, err)
+
+	assert.Equal(t, "org2", profile.Client.Organization)
+	require.Len(t, profile.Channels, 2)
+	assert.Equal(t, []string{"orderer1.example.com", "orderer2.example.com"}, profile.Channels["channel1"].Orderers)
+	assert.Len(t, profile.Channels["channel1"].Peers, 1)
+	assert.Len(t, profile.Channels["channel2"].Peers, 3, "expecting peer of unknown MSP to be ignored")
+	assert.True(t, profile.Channels["channel2"].Peers["peer0.org2.example.com"].EndorsingPeer)
+
+	assert.Equal(t, Organization{MSPID: "org1MSP", Peers: []string{"peer0.org1.example.com", "peer1.org1.example.com"}}, profile.Organizations["org1"])
+	assert.Equal(t, Organization{MSPID: "org2MSP", Peers: []string{"peer0.org2.example.com"}}, profile.Organizations["org2"])
+	assert.Equal(t, Organization{MSPID: "Orderer1MSP"}, profile.Organizations["orderer1"])
+
+	peer := profile.Peers["peer0.org2.example.com"]
+	assert.Equal(t, "grpcs://peer0.org2.example.com:9051", peer.URL)
+	assert.Equal(t, string(cas["org2"].certPEM), peer.TLSCACerts.Pem)
+
+	assert.Equal(t, string(cas["orderer1"].certPEM), profile.Orderers["orderer1.example.com"].TLSCACerts.Pem)
+	assert.Equal(t, string(cas["orderer2"].certPEM), profile.Orderers["orderer2.example.com"].TLSCACerts.Pem, "expecting TLS CA of consenter's issuer")
+
+	// Only the requested channel
+	profile, err = (&Generator{mspID: "org1MSP", network: network}).Generate("channel1")
+	require.NoError(t, err)
+	assert.Len(t, profile.Channels, 1)
+	assert.Len(t, profile.Peers, 1)
+
+	_, err = g.Generate("channel1")
+	assert.Error(t, err, "expecting error since org2 isn't a member of channel1")
+
+	_, err = g.Generate("unknown")
+	assert.Error(t, err)
+
+	_, err = (&Generator{mspID: "org1MSP", network: &mockNetwork{}}).Generate()
+	assert.Error(t, err, "expecting error since no channels are joined")
+}
+
+func TestBootstrapConfig(t *testing.T) {
+	ca := newTestCA(t, "ca.org1.example.com")
+	cert, key := ca.issueWithKey(t, "Admin@org1.example.com")
+
+	stateStorePath, err := ioutil.TempDir("", "connprofile")
+	require.NoError(t, err)
+	defer os.RemoveAll(stateStorePath)
+
+	bootstrap := Bootstrap{PeerURL: "grpcs://peer0.org1.example.com:7051", PeerTLSCACert: ca.certPEM, MSPID: "Org1MSP", AdminCert: cert, AdminKey: key, StateStorePath: stateStorePath}
+	sdk, err := fabsdk.New(BootstrapConfig(bootstrap))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	ctxProvider := sdk.Context(fabsdk.WithUser(AdminUser))
+	ctx, err := ctxProvider()
+	require.NoError(t, err)
+	assert.Equal(t, "Org1MSP", ctx.Identifier().MSPID)
+	assert.Equal(t, cert, ctx.EnrollmentCertificate())
+
+	peerCfg, ok := ctx.EndpointConfig().PeerConfig(bootstrap.PeerURL)
+	require.True(t, ok, "expecting bootstrap peer in bootstrap configuration")
+	assert.NotNil(t, peerCfg.TLSCACert)
+
+	g, err := New(ctxProvider, bootstrap.PeerURL)
+	require.NoError(t, err)
+	assert.Equal(t, "Org1MSP", g.mspID)
+}
+
+func TestProfileConfig(t *testing.T) {
+	network, _ := newTestNetwork(t)
+
+	profile, err := (&Generator{mspID: "org1MSP", network: network}).Generate()
+	require.NoError(t, err)
+	bytes, err := profile.YAML()
+	require.NoError(t, err)
+
+	// The generated profile is read by the SDK's configuration
+	backends, err := config.FromRaw(bytes, "yaml")()
+	require.NoError(t, err)
+	endpointConfig, err := fabImpl.ConfigFromBackend(backends...)
+	require.NoError(t, err)
+
+	peerCfg, ok := endpointConfig.PeerConfig("peer0.org2.example.com")
+	require.True(t, ok)
+	assert.Equal(t, "grpcs://peer0.org2.example.com:9051", peerCfg.URL)
+	assert.NotNil(t, peerCfg.TLSCACert)
+
+	chPeers, ok := endpointConfig.ChannelPeers("channel2")
+	require.True(t, ok)
+	assert.Len(t, chPeers, 3)
+
+	orderers, ok := endpointConfig.ChannelOrderers("channel1")
+	require.True(t, ok)
+	assert.Len(t, orderers, 2)
+}
@@ -0,0 +1,380 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package deploy deploys a chaincode to a channel across multiple organizations using the
+// Fabric 2.0 chaincode lifecycle. Given a chaincode package and the admin contexts of the
+// organizations, the deployer installs the package on the peers of each organization, approves
+// the chaincode definition for each organization, checks the commit readiness, commits the
+// definition and (optionally) initializes the chaincode.
+//
+// Each step is reported to an optional status handler. The progress of a deployment is returned
+// (also on failure) and may be passed to a subsequent deployment in order to resume it; steps which
+// have completed are skipped. Approvals are also checked on the channel so that a deployment may be
+// resumed without the progress of the previous attempt.
+//
+//  Basic Flow:
+//  1) Prepare the admin context of each organization
+//  2) Create deployer
+//  3) Deploy (and resume with the returned progress on failure)
+package deploy
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// Step is a step of the deployment
+type Step string
+
+const (
+	// StepInstall installs the chaincode package on the peers of an organization
+	StepInstall Step = "install"
+	// StepApprove approves the chaincode definition for an organization
+	StepApprove Step = "approve"
+	// StepCheckReadiness checks that the chaincode definition is approved by the organizations
+	StepCheckReadiness Step = "checkreadiness"
+	// StepCommit commits the chaincode definition to the channel
+	StepCommit Step = "commit"
+	// StepInit initializes the chaincode
+	StepInit Step = "init"
+)
+
+// State is the state of a step
+type State string
+
+const (
+	// Started indicates that the step has started
+	Started State = "started"
+	// Completed indicates that the step has completed successfully
+	Completed State = "completed"
+	// Skipped indicates that the step was skipped since it was completed before
+	Skipped State = "skipped"
+	// Failed indicates that the step failed
+	Failed State = "failed"
+)
+
+// StepStatus is reported to the status handler for each step
+type StepStatus struct {
+	Step Step
+	// MSPID is the organization of the step (empty for the steps which apply to the channel)
+	MSPID string
+	State State
+	// Info contains details of the step (such as the package ID or the transaction ID)
+	Info string
+	// Err is set if the step failed
+	Err error
+}
+
+// StatusHandler is invoked for each step of the deployment
+type StatusHandler func(status StepStatus)
+
+// Progress contains the steps of a deployment which have completed. It may be persisted
+// (e.g. as JSON) and passed to Deploy in order to resume a failed deployment.
+type Progress struct {
+	PackageID   string          `json:"packageId,omitempty"`
+	Installed   map[string]bool `json:"installed,omitempty"`
+	Approved    map[string]bool `json:"approved,omitempty"`
+	Committed   bool            `json:"committed,omitempty"`
+	Initialized bool            `json:"initialized,omitempty"`
+}
+
+// Org contains the admin context of an organization which takes part in the deployment
+type Org struct {
+	MSPID string
+	// Context is the context of an admin of the organization
+	Context context.ClientProvider
+	// Peers are the peers of the organization which are used for the deployment. If not
+	// specified then the peers of the organization are discovered.
+	Peers []fab.Peer
+}
+
+// Request contains the parameters of a deployment
+type Request struct {
+	ChannelID string
+	// Package is the chaincode package as created by 'peer lifecycle chaincode package'
+	Package []byte
+	// Definition is the chaincode definition which is approved and committed
+	Definition resmgmt.LifecycleCCDefinition
+	// Init is invoked (optionally) after the definition is committed in order to initialize
+	// the chaincode, e.g. by invoking the chaincode's init function using a channel client.
+	// If the definition requires initialization then the invocation must set IsInit on the channel request.
+	Init func() error
+}
+
+// lifecycleClient is implemented by resmgmt.Client
+type lifecycleClient interface {
+	LifecycleInstallCC(req resmgmt.LifecycleInstallCCRequest, options ...resmgmt.RequestOption) ([]resmgmt.LifecycleInstallCCResponse, error)
+	LifecycleApproveCC(channelID string, req resmgmt.LifecycleApproveCCRequest, options ...resmgmt.RequestOption) (fab.TransactionID, error)
+	LifecycleQueryApprovalMatrix(channelID string, def resmgmt.LifecycleCCDefinition, options ...resmgmt.RequestOption) (resmgmt.LifecycleApprovalMatrix, error)
+	LifecycleCommitCC(channelID string, def resmgmt.LifecycleCCDefinition, options ...resmgmt.RequestOption) (fab.TransactionID, error)
+}
+
+type org struct {
+	mspID  string
+	client lifecycleClient
+	peers  []fab.Peer
+}
+
+// Deployer deploys chaincodes across organizations
+type Deployer struct {
+	orgs          []*org
+	statusHandler StatusHandler
+	reqOpts       []resmgmt.RequestOption
+}
+
+// Option configures the deployer
+type Option func(d *Deployer)
+
+// WithStatusHandler sets the handler which is invoked for each step of the deployment
+func WithStatusHandler(handler StatusHandler) Option {
+	return func(d *Deployer) {
+		d.statusHandler = handler
+	}
+}
+
+// WithRequestOptions sets the request options (such as timeouts and retry options) of the resource
+// management requests. Targets are set by the deployer and must not be specified.
+func WithRequestOptions(opts ...resmgmt.RequestOption) Option {
+	return func(d *Deployer) {
+		d.reqOpts = opts
+	}
+}
+
+// New returns a new deployer for the given organizations. The chaincode definition is committed
+// by the first organization.
+func New(orgs []Org, opts ...Option) (*Deployer, error) {
+	if len(orgs) == 0 {
+		return nil, errors.New("at least one organization is required")
+	}
+
+	var deployerOrgs []*org
+	for _, o := range orgs {
+		if o.MSPID == "" || o.Context == nil {
+			return nil, errors.New("MSP ID and context are required for each organization")
+		}
+
+		client, err := resmgmt.New(o.Context)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to create resource management client for "+o.MSPID)
+		}
+		deployerOrgs = append(deployerOrgs, &org{mspID: o.MSPID, client: client, peers: o.Peers})
+	}
+
+	return newDeployer(deployerOrgs, opts...), nil
+}
+
+func newDeployer(orgs []*org, opts ...Option) *Deployer {
+	d := &Deployer{orgs: orgs}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Deploy deploys the chaincode. The returned progress contains the steps which have completed (also if the
+// deployment failed) and may be passed to a subsequent call in order to resume the deployment.
+func (d *Deployer) Deploy(req Request, progress *Progress) (*Progress, error) {
+	if req.ChannelID == "" || len(req.Package) == 0 {
+		return progress, errors.New("channel ID and chaincode package are required")
+	}
+
+	if progress == nil {
+		progress = &Progress{}
+	}
+	if progress.Installed == nil {
+		progress.Installed = make(map[string]bool)
+	}
+	if progress.Approved == nil {
+		progress.Approved = make(map[string]bool)
+	}
+
+	if err := d.install(req, progress); err != nil {
+		return progress, err
+	}
+	if err := d.approve(req, progress); err != nil {
+		return progress, err
+	}
+	if err := d.checkReadiness(req, progress); err != nil {
+		return progress, err
+	}
+	if err := d.commit(req, progress); err != nil {
+		return progress, err
+	}
+	if err := d.init(req, progress); err != nil {
+		return progress, err
+	}
+
+	return progress, nil
+}
+
+func (d *Deployer) install(req Request, progress *Progress) error {
+	for _, o := range d.orgs {
+		if progress.Installed[o.mspID] {
+			d.report(StepStatus{Step: StepInstall, MSPID: o.mspID, State: Skipped, Info: progress.PackageID})
+			continue
+		}
+
+		d.report(StepStatus{Step: StepInstall, MSPID: o.mspID, State: Started})
+
+		responses, err := o.client.LifecycleInstallCC(resmgmt.LifecycleInstallCCRequest{Package: req.Package}, d.options(o.peers)...)
+		if err != nil {
+			return d.fail(StepInstall, o.mspID, errors.WithMessage(err, "install failed for "+o.mspID))
+		}
+
+		for _, resp := range responses {
+			if progress.PackageID == "" {
+				progress.PackageID = resp.PackageID
+			} else if resp.PackageID != progress.PackageID {
+				return d.fail(StepInstall, o.mspID, errors.Errorf("package ID [%s] of peer [%s] does not match package ID [%s]", resp.PackageID, resp.Target, progress.PackageID))
+			}
+		}
+
+		progress.Installed[o.mspID] = true
+		d.report(StepStatus{Step: StepInstall, MSPID: o.mspID, State: Completed, Info: progress.PackageID})
+	}
+
+	if progress.PackageID == "" {
+		return d.fail(StepInstall, "", errors.New("package ID was not returned by any peer"))
+	}
+	return nil
+}
+
+func (d *Deployer) approve(req Request, progress *Progress) error {
+	// Approvals which were made outside of this deployment (or by a previous attempt) are skipped
+	matrix, err := d.orgs[0].client.LifecycleQueryApprovalMatrix(req.ChannelID, req.Definition, d.options(d.allPeers())...)
+	if err != nil {
+		logger.Debugf("Unable to query approvals for chaincode [%s]: %s", req.Definition.Name, err)
+	}
+
+	for _, o := range d.orgs {
+		if progress.Approved[o.mspID] || matrix[o.mspID].Approved {
+			progress.Approved[o.mspID] = true
+			d.report(StepStatus{Step: StepApprove, MSPID: o.mspID, State: Skipped})
+			continue
+		}
+
+		d.report(StepStatus{Step: StepApprove, MSPID: o.mspID, State: Started})
+
+		approveReq := resmgmt.LifecycleApproveCCRequest{LifecycleCCDefinition: req.Definition, PackageID: progress.PackageID}
+		txID, err := o.client.LifecycleApproveCC(req.ChannelID, approveReq, d.options(o.peers)...)
+		if err != nil {
+			return d.fail(StepApprove, o.mspID, errors.WithMessage(err, "approve failed for "+o.mspID))
+		}
+
+		progress.Approved[o.mspID] = true
+		d.report(StepStatus{Step: StepApprove, MSPID: o.mspID, State: Completed, Info: string(txID)})
+	}
+	return nil
+}
+
+func (d *Deployer) checkReadiness(req Request, progress *Progress) error {
+	if progress.Committed {
+		d.report(StepStatus{Step: StepCheckReadiness, State: Skipped})
+		return nil
+	}
+
+	d.report(StepStatus{Step: StepCheckReadiness, State: Started})
+
+	matrix, err := d.orgs[0].client.LifecycleQueryApprovalMatrix(req.ChannelID, req.Definition, d.options(d.allPeers())...)
+	if err != nil {
+		return d.fail(StepCheckReadiness, "", errors.WithMessage(err, "commit readiness check failed"))
+	}
+
+	// Only the organizations of the deployment must have approved. Whether the approvals are
+	// sufficient (according to the channel's lifecycle endorsement policy) is checked on commit.
+	var blocking []string
+	for _, o := range d.orgs {
+		if !matrix[o.mspID].Approved {
+			blocking = append(blocking, o.mspID)
+		}
+	}
+	if len(blocking) > 0 {
+		return d.fail(StepCheckReadiness, "", errors.Errorf("chaincode definition is not approved by %v", blocking))
+	}
+
+	d.report(StepStatus{Step: StepCheckReadiness, State: Completed})
+	return nil
+}
+
+func (d *Deployer) commit(req Request, progress *Progress) error {
+	if progress.Committed {
+		d.report(StepStatus{Step: StepCommit, State: Skipped})
+		return nil
+	}
+
+	d.report(StepStatus{Step: StepCommit, State: Started})
+
+	txID, err := d.orgs[0].client.LifecycleCommitCC(req.ChannelID, req.Definition, d.options(d.allPeers())...)
+	if err != nil {
+		return d.fail(StepCommit, "", errors.WithMessage(err, "commit failed"))
+	}
+
+	progress.Committed = true
+	d.report(StepStatus{Step: StepCommit, State: Completed, Info: string(txID)})
+	return nil
+}
+
+func (d *Deployer) init(req Request, progress *Progress) error {
+	if req.Init == nil {
+		if req.Definition.InitRequired {
+			logger.Warnf("Chaincode [%s] requires initialization but no init function was provided", req.Definition.Name)
+		}
+		return nil
+	}
+
+	if progress.Initialized {
+		d.report(StepStatus{Step: StepInit, State: Skipped})
+		return nil
+	}
+
+	d.report(StepStatus{Step: StepInit, State: Started})
+
+	if err := req.Init(); err != nil {
+		return d.fail(StepInit, "", errors.WithMessage(err, "init failed"))
+	}
+
+	progress.Initialized = true
+	d.report(StepStatus{Step: StepInit, State: Completed})
+	return nil
+}
+
+// allPeers returns the peers of all organizations or nil if the peers of any organization are not specified
+// (in which case the channel's peers are discovered)
+func (d *Deployer) allPeers() []fab.Peer {
+	var peers []fab.Peer
+	for _, o := range d.orgs {
+		if len(o.peers) == 0 {
+			return nil
+		}
+		peers = append(peers, o.peers...)
+	}
+	return peers
+}
+
+func (d *Deployer) options(peers []fab.Peer) []resmgmt.RequestOption {
+	opts := append([]resmgmt.RequestOption{}, d.reqOpts...)
+	if len(peers) > 0 {
+		opts = append(opts, resmgmt.WithTargets(peers...))
+	}
+	return opts
+}
+
+func (d *Deployer) fail(step Step, mspID string, err error) error {
+	d.report(StepStatus{Step: step, MSPID: mspID, State: Failed, Err: err})
+	return err
+}
+
+func (d *Deployer) report(status StepStatus) {
+	logger.Debugf("Deployment step [%s] for [%s]: %s", status.Step, status.MSPID, status.State)
+	if d.statusHandler != nil {
+		d.statusHandler(status)
+	}
+}
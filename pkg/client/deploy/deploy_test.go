@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	channelID = "mychannel"
+	packageID = "examplecc_1:abcd"
+)
+
+// mockChannel keeps the approvals of all organizations
+type mockChannel struct {
+	approvals map[string]bool
+	commits   int
+}
+
+type mockLifecycleClient struct {
+	mspID      string
+	channel    *mockChannel
+	packageID  string
+	installs   int
+	approveErr error
+	commitErr  error
+}
+
+func (c *mockLifecycleClient) LifecycleInstallCC(req resmgmt.LifecycleInstallCCRequest, options ...resmgmt.RequestOption) ([]resmgmt.LifecycleInstallCCResponse, error) {
+	c.installs++
+	return []resmgmt.LifecycleInstallCCResponse{{Target: c.mspID + "-peer", Status: 200, PackageID: c.packageID}}, nil
+}
+
+func (c *mockLifecycleClient) LifecycleApproveCC(channelID string, req resmgmt.LifecycleApproveCCRequest, options ...resmgmt.RequestOption) (fab.TransactionID, error) {
+	if c.approveErr != nil {
+		return fab.EmptyTransactionID, c.approveErr
+	}
+	c.channel.approvals[c.mspID] = true
+	return "txid", nil
+}
+
+func (c *mockLifecycleClient) LifecycleQueryApprovalMatrix(channelID string, def resmgmt.LifecycleCCDefinition, options ...resmgmt.RequestOption) (resmgmt.LifecycleApprovalMatrix, error) {
+	matrix := make(resmgmt.LifecycleApprovalMatrix)
+	for mspID, approved := range c.channel.approvals {
+		matrix[mspID] = resmgmt.LifecycleOrgApproval{Approved: approved}
+	}
+	return matrix, nil
+}
+
+func (c *mockLifecycleClient) LifecycleCommitCC(channelID string, def resmgmt.LifecycleCCDefinition, options ...resmgmt.RequestOption) (fab.TransactionID, error) {
+	if c.commitErr != nil {
+		return fab.EmptyTransactionID, c.commitErr
+	}
+	c.channel.commits++
+	return "txid", nil
+}
+
+func newTestOrgs(ch *mockChannel, mspIDs ...string) ([]*org, []*mockLifecycleClient) {
+	var orgs []*org
+	var clients []*mockLifecycleClient
+	for _, mspID := range mspIDs {
+		client := &mockLifecycleClient{mspID: mspID, channel: ch, packageID: packageID}
+		ch.approvals[mspID] = false
+		orgs = append(orgs, &org{mspID: mspID, client: client})
+		clients = append(clients, client)
+	}
+	return orgs, clients
+}
+
+func newTestRequest() Request {
+	return Request{
+		ChannelID:  channelID,
+		Package:    []byte("package"),
+		Definition: resmgmt.LifecycleCCDefinition{Name: "examplecc", Version: "v1", Sequence: 1},
+	}
+}
+
+func TestDeploy(t *testing.T) {
+	ch := &mockChannel{approvals: make(map[string]bool)}
+	orgs, _ := newTestOrgs(ch, "Org1MSP", "Org2MSP")
+
+	var statuses []StepStatus
+	deployer := newDeployer(orgs, WithStatusHandler(func(status StepStatus) {
+		statuses = append(statuses, status)
+	}))
+
+	initialized := false
+	req := newTestRequest()
+	req.Init = func() error {
+		initialized = true
+		return nil
+	}
+
+	progress, err := deployer.Deploy(req, nil)
+	require.NoError(t, err)
+	assert.Equal(t, packageID, progress.PackageID)
+	assert.Equal(t, map[string]bool{"Org1MSP": true, "Org2MSP": true}, progress.Installed)
+	assert.Equal(t, map[string]bool{"Org1MSP": true, "Org2MSP": true}, progress.Approved)
+	assert.True(t, progress.Committed)
+	assert.True(t, progress.Initialized)
+	assert.True(t, initialized)
+	assert.Equal(t, 1, ch.commits)
+
+	var completed []Step
+	for _, status := range statuses {
+		if status.State == Completed {
+			completed = append(completed, status.Step)
+		}
+	}
+	assert.Equal(t, []Step{StepInstall, StepInstall, StepApprove, StepApprove, StepCheckReadiness, StepCommit, StepInit}, completed)
+}
+
+func TestDeployResume(t *testing.T) {
+	ch := &mockChannel{approvals: make(map[string]bool)}
+	orgs, clients := newTestOrgs(ch, "Org1MSP", "Org2MSP")
+	deployer := newDeployer(orgs)
+
+	clients[1].approveErr = errors.New("approve failed")
+	progress, err := deployer.Deploy(newTestRequest(), nil)
+	require.Error(t, err)
+	assert.True(t, progress.Approved["Org1MSP"])
+	assert.False(t, progress.Approved["Org2MSP"])
+	assert.False(t, progress.Committed)
+
+	// Resume without the progress: the approval of Org1 is found on the channel
+	clients[1].approveErr = nil
+	clients[0].commitErr = errors.New("commit failed")
+	progress, err = deployer.Deploy(newTestRequest(), &Progress{})
+	require.Error(t, err)
+	assert.True(t, progress.Approved["Org2MSP"])
+
+	// Resume with the progress: install is not repeated
+	clients[0].commitErr = nil
+	installs := clients[0].installs
+	progress, err = deployer.Deploy(newTestRequest(), progress)
+	require.NoError(t, err)
+	assert.True(t, progress.Committed)
+	assert.Equal(t, installs, clients[0].installs)
+	assert.Equal(t, 1, ch.commits)
+}
+
+func TestDeployErrors(t *testing.T) {
+	_, err := New(nil)
+	assert.Error(t, err)
+
+	ch := &mockChannel{approvals: make(map[string]bool)}
+	orgs, clients := newTestOrgs(ch, "Org1MSP", "Org2MSP")
+	deployer := newDeployer(orgs)
+
+	_, err = deployer.Deploy(Request{ChannelID: channelID}, nil)
+	assert.Error(t, err, "expecting error for missing package")
+
+	clients[1].packageID = "examplecc_1:other"
+	var failed []StepStatus
+	deployer = newDeployer(orgs, WithStatusHandler(func(status StepStatus) {
+		if status.State == Failed {
+			failed = append(failed, status)
+		}
+	}))
+	_, err = deployer.Deploy(newTestRequest(), nil)
+	assert.Error(t, err, "expecting error for mismatched package IDs")
+	require.Len(t, failed, 1)
+	assert.Equal(t, StepInstall, failed[0].Step)
+	assert.Equal(t, "Org2MSP", failed[0].MSPID)
+}
@@ -0,0 +1,233 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package discovery enables queries of Fabric's discovery service. The peers of a channel (with their
+// ledger height and installed chaincodes) and the peers of the client's organization are returned
+// as typed results so that applications may implement their own dashboards or routing.
+//
+//  Basic Flow:
+//  1) Prepare client context
+//  2) Create discovery client
+//  3) Query the peers of a channel or the local peers
+package discovery
+
+import (
+	reqContext "context"
+	"time"
+
+	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// Chaincode contains the name and version of a chaincode installed on a peer
+type Chaincode struct {
+	Name    string
+	Version string
+}
+
+// Peer contains the information about a peer returned by the discovery service
+type Peer struct {
+	// Endpoint is the endpoint (host:port) of the peer
+	Endpoint string
+	MSPID    string
+	// Identity is the serialized identity of the peer
+	Identity []byte
+	// LedgerHeight is the ledger height of the peer for the channel (0 for local peers since the
+	// discovery service only returns the channel state for peers of a channel)
+	LedgerHeight uint64
+	// Chaincodes are the chaincodes installed on the peer (only for peers of a channel)
+	Chaincodes []Chaincode
+}
+
+// Client enables queries of the discovery service
+type Client struct {
+	ctx        context.Client
+	discClient *fabdiscovery.Client
+}
+
+type requestOptions struct {
+	Targets    []fab.PeerConfig
+	Timeout    time.Duration
+	PeerFilter PeerFilter
+}
+
+// RequestOption func for each Opts argument
+type RequestOption func(ctx context.Client, opts *requestOptions) error
+
+// WithTargetEndpoints specifies the peers (by URL or name) which are queried. The first successful response is used.
+func WithTargetEndpoints(keys ...string) RequestOption {
+	return func(ctx context.Client, opts *requestOptions) error {
+		for _, key := range keys {
+			peerCfg, err := comm.NetworkPeerConfig(ctx.EndpointConfig(), key)
+			if err != nil {
+				return err
+			}
+			opts.Targets = append(opts.Targets, peerCfg.PeerConfig)
+		}
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of the request (the discovery response timeout is used by default)
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(ctx context.Client, opts *requestOptions) error {
+		opts.Timeout = timeout
+		return nil
+	}
+}
+
+// New returns a discovery client
+func New(ctxProvider context.ClientProvider) (*Client, error) {
+	ctx, err := ctxProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create client context")
+	}
+
+	discClient, err := fabdiscovery.New(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create discovery client")
+	}
+
+	return &Client{ctx: ctx, discClient: discClient}, nil
+}
+
+// ChannelPeers returns the peers of the given channel. By default the peers configured for the channel are queried.
+func (c *Client) ChannelPeers(channelID string, options ...RequestOption) ([]Peer, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+
+	opts, err := c.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Targets) == 0 {
+		opts.Targets, err = c.channelTargets(channelID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req := discclient.NewRequest().OfChannel(channelID).AddPeersQuery()
+	return c.queryPeers(req, opts, func(response fabdiscovery.Response) ([]*discclient.Peer, error) {
+		return response.ForChannel(channelID).Peers()
+	})
+}
+
+// LocalPeers returns the peers of the client's organization. By default the peers configured for the
+// client's organization are queried.
+func (c *Client) LocalPeers(options ...RequestOption) ([]Peer, error) {
+	opts, err := c.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Targets) == 0 {
+		// The local peers query is only allowed on peers of the client's organization
+		mspID := c.ctx.Identifier().MSPID
+		for _, p := range c.ctx.EndpointConfig().NetworkPeers() {
+			if p.MSPID == mspID {
+				opts.Targets = append(opts.Targets, p.PeerConfig)
+			}
+		}
+	}
+
+	req := discclient.NewRequest().AddLocalPeersQuery()
+	return c.queryPeers(req, opts, func(response fabdiscovery.Response) ([]*discclient.Peer, error) {
+		return response.ForLocal().Peers()
+	})
+}
+
+func (c *Client) queryPeers(req *discclient.Request, opts requestOptions, getPeers func(response fabdiscovery.Response) ([]*discclient.Peer, error)) ([]Peer, error) {
+	if len(opts.Targets) == 0 {
+		return nil, errors.New("no targets available for the discovery request")
+	}
+
+	reqCtx, cancel := c.createRequestContext(opts)
+	defer cancel()
+
+	responses, err := c.discClient.Send(reqCtx, req, opts.Targets...)
+	if len(responses) == 0 {
+		return nil, errors.WithMessage(err, "no successful response received from any peer")
+	}
+	if err != nil {
+		logger.Debugf("Received %d response(s) and one or more errors from discovery client: %s", len(responses), err)
+	}
+
+	var lastErr error
+	for _, response := range responses {
+		endpoints, err := getPeers(response)
+		if err != nil {
+			lastErr = errors.Wrapf(err, "error getting peers from discovery response of [%s]", response.Target())
+			logger.Debug(lastErr.Error())
+			continue
+		}
+		return asPeers(endpoints), nil
+	}
+	return nil, lastErr
+}
+
+func (c *Client) channelTargets(channelID string) ([]fab.PeerConfig, error) {
+	chPeers, ok := c.ctx.EndpointConfig().ChannelPeers(channelID)
+	if !ok {
+		return nil, errors.Errorf("failed to get channel peer configs for channel [%s]", channelID)
+	}
+	var targets []fab.PeerConfig
+	for _, p := range chPeers {
+		targets = append(targets, p.PeerConfig)
+	}
+	return targets, nil
+}
+
+func (c *Client) prepareRequestOpts(options ...RequestOption) (requestOptions, error) {
+	opts := requestOptions{}
+	for _, option := range options {
+		if err := option(c.ctx, &opts); err != nil {
+			return opts, errors.WithMessage(err, "failed to read request opts")
+		}
+	}
+	return opts, nil
+}
+
+func (c *Client) createRequestContext(opts requestOptions) (reqContext.Context, reqContext.CancelFunc) {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = c.ctx.EndpointConfig().Timeout(fab.DiscoveryResponse)
+	}
+	return contextImpl.NewRequest(c.ctx, contextImpl.WithTimeout(timeout))
+}
+
+func asPeers(endpoints []*discclient.Peer) []Peer {
+	var peers []Peer
+	for _, endpoint := range endpoints {
+		peer := Peer{
+			Endpoint: endpoint.AliveMessage.GetAliveMsg().GetMembership().GetEndpoint(),
+			MSPID:    endpoint.MSPID,
+			Identity: endpoint.Identity,
+		}
+
+		// The state info is not returned for local peers
+		if endpoint.StateInfoMessage != nil && endpoint.StateInfoMessage.GossipMessage != nil {
+			properties := endpoint.StateInfoMessage.GetStateInfo().GetProperties()
+			peer.LedgerHeight = properties.GetLedgerHeight()
+			for _, cc := range properties.GetChaincodes() {
+				peer.Chaincodes = append(peer.Chaincodes, Chaincode{Name: cc.Name, Version: cc.Version})
+			}
+		}
+
+		peers = append(peers, peer)
+	}
+	return peers
+}
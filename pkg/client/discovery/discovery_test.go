@@ -0,0 +1,199 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	discpb "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	discmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+const (
+	peerAddress  = "localhost:9995"
+	peer2Address = "localhost:9994"
+	channelID    = "mychannel"
+)
+
+func TestChannelPeers(t *testing.T) {
+	client, err := New(newMockContextProvider())
+	require.NoError(t, err)
+
+	peers, err := client.ChannelPeers(channelID, WithTargetEndpoints(peerAddress))
+	require.NoError(t, err)
+	require.Len(t, peers, 2)
+
+	peersByEndpoint := make(map[string]Peer)
+	for _, p := range peers {
+		peersByEndpoint[p.Endpoint] = p
+	}
+
+	peer1 := peersByEndpoint[peerAddress]
+	assert.Equal(t, "Org1MSP", peer1.MSPID)
+	assert.Equal(t, uint64(26), peer1.LedgerHeight)
+	assert.Equal(t, []Chaincode{{Name: "examplecc", Version: "v1"}}, peer1.Chaincodes)
+
+	peer2 := peersByEndpoint[peer2Address]
+	assert.Equal(t, "Org2MSP", peer2.MSPID)
+	assert.Equal(t, uint64(25), peer2.LedgerHeight)
+	assert.Empty(t, peer2.Chaincodes)
+
+	_, err = client.ChannelPeers("")
+	assert.Error(t, err, "expecting error for missing channel ID")
+
+	_, err = client.ChannelPeers(channelID, WithTargetEndpoints("invalid"))
+	assert.Error(t, err, "expecting error for invalid target")
+}
+
+func TestLocalPeers(t *testing.T) {
+	client, err := New(newMockContextProvider())
+	require.NoError(t, err)
+
+	peers, err := client.LocalPeers(WithTimeout(5 * time.Second))
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	assert.Equal(t, peerAddress, peers[0].Endpoint)
+	assert.Equal(t, "Org1MSP", peers[0].MSPID)
+	assert.Equal(t, uint64(0), peers[0].LedgerHeight)
+}
+
+func TestNoTargets(t *testing.T) {
+	ctx := newMockContext()
+	ctx.EndpointConfig().(*mocks.MockConfig).SetCustomNetworkPeerCfg(nil)
+
+	client, err := New(func() (context.Client, error) { return ctx, nil })
+	require.NoError(t, err)
+
+	_, err = client.LocalPeers()
+	assert.Error(t, err, "expecting error since there are no peers in the client's organization")
+}
+
+func TestEndorsementDescriptor(t *testing.T) {
+	client, err := New(newMockContextProvider())
+	require.NoError(t, err)
+
+	chaincodes := []*fab.ChaincodeCall{{ID: "examplecc", Collections: []string{"coll1"}}}
+	descriptor, err := client.EndorsementDescriptor(channelID, chaincodes, WithTargetEndpoints(peerAddress))
+	require.NoError(t, err)
+	assert.Equal(t, "examplecc", descriptor.Chaincode)
+	assert.Equal(t, []EndorsementLayout{{"Org1MSP": 1, "Org2MSP": 1}, {"Org1MSP": 1}}, descriptor.Layouts)
+
+	require.Len(t, descriptor.PeersByGroup, 2)
+	require.Len(t, descriptor.PeersByGroup["Org1MSP"], 1)
+	assert.Equal(t, peerAddress, descriptor.PeersByGroup["Org1MSP"][0].Endpoint)
+	assert.Equal(t, "Org1MSP", descriptor.PeersByGroup["Org1MSP"][0].MSPID)
+	assert.Equal(t, uint64(26), descriptor.PeersByGroup["Org1MSP"][0].LedgerHeight)
+	require.Len(t, descriptor.PeersByGroup["Org2MSP"], 1)
+	assert.Equal(t, "Org2MSP", descriptor.PeersByGroup["Org2MSP"][0].MSPID)
+
+	_, err = client.EndorsementDescriptor(channelID, nil)
+	assert.Error(t, err, "expecting error for missing chaincodes")
+}
+
+func TestMain(m *testing.M) {
+	grpcServer := grpc.NewServer()
+
+	lis, err := net.Listen("tcp", peerAddress)
+	if err != nil {
+		panic(fmt.Sprintf("Error starting discovery listener %s", err))
+	}
+
+	discoveryServer := discmocks.NewServer(
+		discmocks.WithLocalPeers(
+			&discmocks.MockDiscoveryPeerEndpoint{
+				MSPID:    "Org1MSP",
+				Endpoint: peerAddress,
+			},
+		),
+		discmocks.WithPeers(
+			&discmocks.MockDiscoveryPeerEndpoint{
+				MSPID:        "Org1MSP",
+				Endpoint:     peerAddress,
+				LedgerHeight: 26,
+				Chaincodes:   []*gossip.Chaincode{{Name: "examplecc", Version: "v1"}},
+			},
+			&discmocks.MockDiscoveryPeerEndpoint{
+				MSPID:        "Org2MSP",
+				Endpoint:     peer2Address,
+				LedgerHeight: 25,
+			},
+		),
+		discmocks.WithEndorsementLayouts(
+			map[string]uint32{"Org1MSP": 1, "Org2MSP": 1},
+			map[string]uint32{"Org1MSP": 1},
+		),
+	)
+
+	discpb.RegisterDiscoveryServer(grpcServer, discoveryServer)
+
+	go grpcServer.Serve(lis)
+
+	time.Sleep(2 * time.Second)
+	os.Exit(m.Run())
+}
+
+func newMockContext() *mocks.MockContext {
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP"))
+	ctx.SetCustomInfraProvider(comm.NewMockInfraProvider())
+
+	peerCfg := fab.PeerConfig{
+		URL:         peerAddress,
+		GRPCOptions: map[string]interface{}{"allow-insecure": true},
+	}
+	config := ctx.EndpointConfig().(*mocks.MockConfig)
+	config.SetCustomPeerCfg(&peerCfg)
+	config.SetCustomNetworkPeerCfg([]fab.NetworkPeer{{PeerConfig: peerCfg, MSPID: "Org1MSP"}})
+	return ctx
+}
+
+func newMockContextProvider() context.ClientProvider {
+	ctx := newMockContext()
+	return func() (context.Client, error) {
+		return ctx, nil
+	}
+}
+
+func TestCheckEndorsementPolicy(t *testing.T) {
+	client, err := New(newMockContextProvider())
+	require.NoError(t, err)
+
+	chaincodes := []*fab.ChaincodeCall{{ID: "examplecc"}}
+	check, err := client.CheckEndorsementPolicy(channelID, chaincodes, WithTargetEndpoints(peerAddress))
+	require.NoError(t, err)
+	assert.True(t, check.Satisfiable)
+	assert.Empty(t, check.MissingOrgs)
+
+	// The second layout only requires Org1
+	check, err = client.CheckEndorsementPolicy(channelID, chaincodes, WithTargetEndpoints(peerAddress), WithPeerFilter(func(peer Peer) bool {
+		return peer.MSPID != "Org2MSP"
+	}))
+	require.NoError(t, err)
+	assert.True(t, check.Satisfiable)
+	assert.Equal(t, EndorsementLayout{"Org1MSP": 1}, check.Layout)
+
+	// Without Org1 no layout can be satisfied
+	check, err = client.CheckEndorsementPolicy(channelID, chaincodes, WithTargetEndpoints(peerAddress), WithPeerFilter(func(peer Peer) bool {
+		return peer.MSPID != "Org1MSP"
+	}))
+	require.NoError(t, err)
+	assert.False(t, check.Satisfiable)
+	assert.Equal(t, []string{"Org1MSP"}, check.MissingOrgs)
+	assert.Equal(t, []MissingGroup{{Group: "Org1MSP", Required: 1, Available: 0, MSPIDs: []string{"Org1MSP"}}}, check.MissingGroups)
+}
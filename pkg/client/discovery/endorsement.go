@@ -0,0 +1,162 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"github.com/golang/protobuf/proto"
+	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// EndorsementLayout is a combination of peer groups which satisfies the endorsement policy. The layout
+// maps each group to the number of peers of the group from which an endorsement is required.
+type EndorsementLayout map[string]int
+
+// EndorsementDescriptor is the endorsement descriptor returned by the discovery service for an invocation chain.
+// An endorsement policy is satisfied by the endorsements of peers which match any one of the layouts.
+type EndorsementDescriptor struct {
+	// Chaincode is the name of the first chaincode of the invocation chain
+	Chaincode string
+	// Layouts are the alternative combinations of groups which satisfy the endorsement policy
+	Layouts []EndorsementLayout
+	// PeersByGroup contains the peers of each group referenced by the layouts
+	PeersByGroup map[string][]Peer
+}
+
+// EndorsementDescriptor returns the endorsement descriptor for the given invocation chain (the chaincode being invoked
+// along with any chaincodes/collections that it accesses). By default the peers configured for the channel are queried.
+func (c *Client) EndorsementDescriptor(channelID string, chaincodes []*fab.ChaincodeCall, options ...RequestOption) (*EndorsementDescriptor, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+	if len(chaincodes) == 0 {
+		return nil, errors.New("at least one chaincode is required")
+	}
+
+	opts, err := c.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Targets) == 0 {
+		opts.Targets, err = c.channelTargets(channelID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := discclient.NewRequest().OfChannel(channelID).AddEndorsersQuery(asChaincodeInterest(chaincodes))
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating endorsers query")
+	}
+
+	reqCtx, cancel := c.createRequestContext(opts)
+	defer cancel()
+
+	responses, err := c.discClient.SendRaw(reqCtx, req, opts.Targets...)
+	if len(responses) == 0 {
+		return nil, errors.WithMessage(err, "no successful response received from any peer")
+	}
+	if err != nil {
+		logger.Debugf("Received %d response(s) and one or more errors from discovery client: %s", len(responses), err)
+	}
+
+	var lastErr error
+	for _, response := range responses {
+		descriptor, err := endorsementDescriptor(response)
+		if err != nil {
+			lastErr = errors.WithMessage(err, "error getting endorsement descriptor from discovery response of ["+response.Target+"]")
+			logger.Debug(lastErr.Error())
+			continue
+		}
+		return descriptor, nil
+	}
+	return nil, lastErr
+}
+
+func endorsementDescriptor(response *fabdiscovery.RawResponse) (*EndorsementDescriptor, error) {
+	result, respErr := response.EndorsersAt(0)
+	if respErr != nil {
+		return nil, errors.New(respErr.Content)
+	}
+	if result == nil || len(result.Content) == 0 {
+		return nil, errors.New("no endorsement descriptor in response")
+	}
+
+	desc := result.Content[0]
+	descriptor := &EndorsementDescriptor{
+		Chaincode:    desc.Chaincode,
+		PeersByGroup: make(map[string][]Peer),
+	}
+
+	for _, l := range desc.Layouts {
+		layout := make(EndorsementLayout)
+		for grp, quantity := range l.QuantitiesByGroup {
+			if _, exists := desc.EndorsersByGroups[grp]; !exists {
+				return nil, errors.Errorf("group %s isn't mapped to endorsers, but exists in a layout", grp)
+			}
+			layout[grp] = int(quantity)
+		}
+		descriptor.Layouts = append(descriptor.Layouts, layout)
+	}
+
+	for grp, peers := range desc.EndorsersByGroups {
+		endorsers, err := asEndorsers(peers.Peers)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid endorsers of group "+grp)
+		}
+		descriptor.PeersByGroup[grp] = endorsers
+	}
+
+	return descriptor, nil
+}
+
+func asEndorsers(peers []*discovery.Peer) ([]Peer, error) {
+	var endorsers []*discclient.Peer
+	for _, p := range peers {
+		if p.MembershipInfo == nil || p.StateInfo == nil {
+			return nil, errors.New("received empty envelope(s) for endorser")
+		}
+		aliveMsg, err := p.MembershipInfo.ToGossipMessage()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed unmarshaling gossip envelope to alive message")
+		}
+		stateInfoMsg, err := p.StateInfo.ToGossipMessage()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed unmarshaling gossip envelope to state info message")
+		}
+		if aliveMsg.GetAliveMsg() == nil || stateInfoMsg.GetStateInfo() == nil {
+			return nil, errors.New("unexpected gossip message type for endorser")
+		}
+		sID := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(p.Identity, sID); err != nil {
+			return nil, errors.Wrap(err, "failed unmarshaling peer's identity")
+		}
+		endorsers = append(endorsers, &discclient.Peer{
+			MSPID:            sID.Mspid,
+			Identity:         p.Identity,
+			AliveMessage:     aliveMsg,
+			StateInfoMessage: stateInfoMsg,
+		})
+	}
+	return asPeers(endorsers), nil
+}
+
+func asChaincodeInterest(chaincodes []*fab.ChaincodeCall) *discovery.ChaincodeInterest {
+	interest := &discovery.ChaincodeInterest{}
+	for _, cc := range chaincodes {
+		interest.Chaincodes = append(interest.Chaincodes, &discovery.ChaincodeCall{
+			Name:            cc.ID,
+			CollectionNames: cc.Collections,
+		})
+	}
+	return interest
+}
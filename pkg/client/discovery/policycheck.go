@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"sort"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// PeerFilter returns true if the given peer may be used to satisfy the endorsement policy
+type PeerFilter func(peer Peer) bool
+
+// MissingGroup describes a group of a layout for which not enough peers are reachable
+type MissingGroup struct {
+	Group string
+	// Required is the number of endorsements required from the group
+	Required int
+	// Available is the number of reachable peers of the group
+	Available int
+	// MSPIDs are the organizations of the peers in the group
+	MSPIDs []string
+}
+
+// EndorsementPolicyCheck is the result of an endorsement policy check
+type EndorsementPolicyCheck struct {
+	// Satisfiable is true if the reachable peers satisfy the endorsement policy
+	Satisfiable bool
+	// Layout is the layout which is satisfied (if satisfiable) or which is the closest to being satisfied
+	Layout EndorsementLayout
+	// MissingGroups are the groups of the layout for which not enough peers are reachable
+	MissingGroups []MissingGroup
+	// MissingOrgs are the MSP IDs of the organizations of the missing groups
+	MissingOrgs []string
+}
+
+// WithPeerFilter sets a filter which is applied to the peers returned by discovery when checking
+// the endorsement policy. This allows, for example, peers which are known to be unhealthy to be excluded.
+func WithPeerFilter(filter PeerFilter) RequestOption {
+	return func(ctx context.Client, opts *requestOptions) error {
+		opts.PeerFilter = filter
+		return nil
+	}
+}
+
+// CheckEndorsementPolicy checks whether the reachable peers are able to satisfy the endorsement policy of the given
+// invocation chain. A peer is reachable if it is returned by discovery, is resolved by the endpoint config and is
+// accepted by the peer filter (if any). If the policy can't be satisfied then the organizations which are missing
+// are returned. The check may be used, for example, in readiness probes before attempting to invoke the chaincode.
+func (c *Client) CheckEndorsementPolicy(channelID string, chaincodes []*fab.ChaincodeCall, options ...RequestOption) (*EndorsementPolicyCheck, error) {
+	opts, err := c.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor, err := c.EndorsementDescriptor(channelID, chaincodes, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(descriptor.Layouts) == 0 {
+		return nil, errors.Errorf("no endorsement layouts returned for chaincode [%s]", descriptor.Chaincode)
+	}
+
+	available := make(map[string]int)
+	for grp, peers := range descriptor.PeersByGroup {
+		for _, peer := range peers {
+			if c.isReachable(peer, opts.PeerFilter) {
+				available[grp]++
+			}
+		}
+	}
+
+	var closest *EndorsementPolicyCheck
+	for _, layout := range descriptor.Layouts {
+		check := checkLayout(layout, available, descriptor.PeersByGroup)
+		if check.Satisfiable {
+			return check, nil
+		}
+		if closest == nil || shortfall(check.MissingGroups) < shortfall(closest.MissingGroups) {
+			closest = check
+		}
+	}
+
+	return closest, nil
+}
+
+func (c *Client) isReachable(peer Peer, filter PeerFilter) bool {
+	if _, ok := c.ctx.EndpointConfig().PeerConfig(peer.Endpoint); !ok {
+		logger.Debugf("Peer [%s] is not reachable since it isn't resolved by the endpoint config", peer.Endpoint)
+		return false
+	}
+	return filter == nil || filter(peer)
+}
+
+func checkLayout(layout EndorsementLayout, available map[string]int, peersByGroup map[string][]Peer) *EndorsementPolicyCheck {
+	check := &EndorsementPolicyCheck{Layout: layout}
+
+	missingOrgs := make(map[string]bool)
+	for grp, required := range layout {
+		if available[grp] >= required {
+			continue
+		}
+
+		missing := MissingGroup{Group: grp, Required: required, Available: available[grp]}
+		mspIDs := make(map[string]bool)
+		for _, peer := range peersByGroup[grp] {
+			if !mspIDs[peer.MSPID] {
+				mspIDs[peer.MSPID] = true
+				missing.MSPIDs = append(missing.MSPIDs, peer.MSPID)
+				missingOrgs[peer.MSPID] = true
+			}
+		}
+		sort.Strings(missing.MSPIDs)
+		check.MissingGroups = append(check.MissingGroups, missing)
+	}
+
+	sort.Slice(check.MissingGroups, func(i, j int) bool {
+		return check.MissingGroups[i].Group < check.MissingGroups[j].Group
+	})
+	for mspID := range missingOrgs {
+		check.MissingOrgs = append(check.MissingOrgs, mspID)
+	}
+	sort.Strings(check.MissingOrgs)
+
+	check.Satisfiable = len(check.MissingGroups) == 0
+	return check
+}
+
+// shortfall returns the total number of peers which are missing to satisfy a layout
+func shortfall(missingGroups []MissingGroup) int {
+	total := 0
+	for _, missing := range missingGroups {
+		total += missing.Required - missing.Available
+	}
+	return total
+}
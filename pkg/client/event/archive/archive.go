@@ -0,0 +1,427 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package archive consumes the block stream of a channel and writes the blocks to one or more sinks
+// (filesystem, S3, Kafka or custom sinks), e.g. for audit retention. The number of the last archived
+// block is checkpointed so that archival resumes where it left off after a restart. Missing blocks
+// (gaps in the stream) are detected and are either fetched from the ledger or reported.
+//
+// Blocks are archived at least once: a block may be written again to the sinks if the archiver is
+// restarted before the checkpoint was saved, so sinks should be idempotent (e.g. keyed by block number).
+//
+//  Basic Flow:
+//  1) Create a checkpointer
+//  2) Create an event client with block events using ResumeOptions(checkpointer)
+//  3) Create an archiver with the event client, the checkpointer and the sinks
+//  4) Start the archiver
+//  5) Stop the archiver
+package archive
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/event"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// BlockSource is the subset of the event client used by the archiver
+type BlockSource interface {
+	RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error)
+	Unregister(reg fab.Registration)
+}
+
+// BlockFetcher retrieves the block with the given number from the ledger (e.g. using the ledger client's QueryBlock).
+// It is used for filling gaps in the block stream.
+type BlockFetcher func(blockNum uint64) (*cb.Block, error)
+
+// GapHandler is invoked when a range of blocks (from and to inclusive) is missing in the block stream and
+// the blocks couldn't be fetched
+type GapHandler func(from, to uint64)
+
+// ErrorHandler is invoked when the archiver stops due to an error
+type ErrorHandler func(err error)
+
+// Gap is a range of blocks (From and To inclusive) which haven't been archived
+type Gap struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// Checkpoint contains the number of the last archived block along with the unfilled gaps
+type Checkpoint struct {
+	BlockNumber uint64 `json:"blockNumber"`
+	Gaps        []Gap  `json:"gaps,omitempty"`
+}
+
+// Checkpointer saves and loads the archival checkpoint
+type Checkpointer interface {
+	// Load returns the checkpoint or nil if no block has been archived
+	Load() (*Checkpoint, error)
+	// Save saves the checkpoint
+	Save(checkpoint *Checkpoint) error
+}
+
+// ResumeOptions returns the event client options which resume the block stream after the
+// checkpointed block (or start from the oldest block if no block has been archived)
+func ResumeOptions(checkpointer Checkpointer) ([]event.ClientOption, error) {
+	checkpoint, err := checkpointer.Load()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load checkpoint")
+	}
+
+	if checkpoint == nil {
+		return []event.ClientOption{event.WithBlockEvents(), event.WithSeekType(seek.Oldest)}, nil
+	}
+	return []event.ClientOption{event.WithBlockEvents(), event.WithSeekType(seek.FromBlock), event.WithBlockNum(checkpoint.BlockNumber + 1)}, nil
+}
+
+// Archiver writes the blocks of a channel to sinks
+type Archiver struct {
+	channelID    string
+	source       BlockSource
+	sinks        []Sink
+	checkpointer Checkpointer
+	fetcher      BlockFetcher
+	gapHandler   GapHandler
+	errHandler   ErrorHandler
+
+	mutex      sync.RWMutex
+	checkpoint *Checkpoint
+	reg        fab.Registration
+	done       chan struct{}
+	stopped    chan struct{}
+	err        error
+}
+
+// Option is a functional option for the archiver
+type Option func(a *Archiver)
+
+// WithSinks adds sinks to which the blocks are written
+func WithSinks(sinks ...Sink) Option {
+	return func(a *Archiver) {
+		a.sinks = append(a.sinks, sinks...)
+	}
+}
+
+// WithCheckpointer sets the checkpointer. If no checkpointer is set then the checkpoint is kept in memory only.
+func WithCheckpointer(checkpointer Checkpointer) Option {
+	return func(a *Archiver) {
+		a.checkpointer = checkpointer
+	}
+}
+
+// WithBlockFetcher sets the fetcher which is used for filling gaps in the block stream
+func WithBlockFetcher(fetcher BlockFetcher) Option {
+	return func(a *Archiver) {
+		a.fetcher = fetcher
+	}
+}
+
+// WithGapHandler sets the handler which is invoked for gaps which couldn't be filled
+func WithGapHandler(handler GapHandler) Option {
+	return func(a *Archiver) {
+		a.gapHandler = handler
+	}
+}
+
+// WithErrorHandler sets the handler which is invoked when the archiver stops due to an error
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(a *Archiver) {
+		a.errHandler = handler
+	}
+}
+
+// New returns an archiver for the blocks of the given channel which are received from the given source
+func New(channelID string, source BlockSource, opts ...Option) (*Archiver, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+	if source == nil {
+		return nil, errors.New("block source is required")
+	}
+
+	a := &Archiver{
+		channelID: channelID,
+		source:    source,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	if len(a.sinks) == 0 {
+		return nil, errors.New("at least one sink is required")
+	}
+	if a.checkpointer == nil {
+		a.checkpointer = &MemoryCheckpointer{}
+	}
+
+	return a, nil
+}
+
+// Start loads the checkpoint, registers for block events and archives the received blocks in the background
+func (a *Archiver) Start() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.done != nil {
+		return errors.New("archiver already started")
+	}
+
+	checkpoint, err := a.checkpointer.Load()
+	if err != nil {
+		return errors.WithMessage(err, "failed to load checkpoint")
+	}
+
+	reg, eventch, err := a.source.RegisterBlockEvent()
+	if err != nil {
+		return errors.WithMessage(err, "failed to register for block events")
+	}
+
+	a.checkpoint = checkpoint
+	a.reg = reg
+	a.done = make(chan struct{})
+	a.stopped = make(chan struct{})
+
+	go a.run(eventch)
+
+	return nil
+}
+
+// Stop stops archiving and unregisters from block events
+func (a *Archiver) Stop() {
+	a.mutex.Lock()
+	done, stopped := a.done, a.stopped
+	if done != nil {
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+	}
+	a.mutex.Unlock()
+
+	if stopped != nil {
+		<-stopped
+	}
+}
+
+// Checkpoint returns the current checkpoint or nil if no block has been archived
+func (a *Archiver) Checkpoint() *Checkpoint {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	if a.checkpoint == nil {
+		return nil
+	}
+	checkpoint := *a.checkpoint
+	checkpoint.Gaps = append([]Gap(nil), a.checkpoint.Gaps...)
+	return &checkpoint
+}
+
+// Err returns the error which caused the archiver to stop
+func (a *Archiver) Err() error {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.err
+}
+
+func (a *Archiver) run(eventch <-chan *fab.BlockEvent) {
+	defer close(a.stopped)
+	defer a.source.Unregister(a.reg)
+
+	for {
+		select {
+		case <-a.done:
+			logger.Debugf("Stopping block archiver for channel [%s]", a.channelID)
+			return
+		case e, ok := <-eventch:
+			if !ok {
+				logger.Debugf("Block event channel closed - stopping block archiver for channel [%s]", a.channelID)
+				return
+			}
+			if err := a.process(e.Block); err != nil {
+				logger.Errorf("Stopping block archiver for channel [%s]: %s", a.channelID, err)
+				a.mutex.Lock()
+				a.err = err
+				a.mutex.Unlock()
+				if a.errHandler != nil {
+					a.errHandler(err)
+				}
+				return
+			}
+		}
+	}
+}
+
+func (a *Archiver) process(block *cb.Block) error {
+	if block == nil || block.Header == nil {
+		return errors.New("received block without header")
+	}
+	blockNum := block.Header.Number
+
+	checkpoint := a.Checkpoint()
+	if checkpoint != nil {
+		next := checkpoint.BlockNumber + 1
+		if blockNum < next {
+			logger.Debugf("Skipping block [%d] of channel [%s] since it has already been archived", blockNum, a.channelID)
+			return nil
+		}
+		if blockNum > next {
+			checkpoint.Gaps = append(checkpoint.Gaps, a.fillGap(next, blockNum-1)...)
+		}
+	} else {
+		checkpoint = &Checkpoint{}
+	}
+
+	if err := a.archive(block); err != nil {
+		return err
+	}
+
+	checkpoint.BlockNumber = blockNum
+	return a.saveCheckpoint(checkpoint)
+}
+
+// fillGap fetches and archives the missing blocks and returns the gaps which couldn't be filled
+func (a *Archiver) fillGap(from, to uint64) []Gap {
+	logger.Warnf("Detected gap in block stream of channel [%s]: blocks [%d-%d] are missing", a.channelID, from, to)
+
+	if a.fetcher == nil {
+		gap := Gap{From: from, To: to}
+		a.reportGap(gap)
+		return []Gap{gap}
+	}
+
+	var gaps []Gap
+	for blockNum := from; blockNum <= to; blockNum++ {
+		block, err := a.fetcher(blockNum)
+		if err == nil {
+			err = a.archive(block)
+		}
+		if err != nil {
+			logger.Warnf("Failed to fill gap with block [%d] of channel [%s]: %s", blockNum, a.channelID, err)
+			if n := len(gaps); n > 0 && gaps[n-1].To == blockNum-1 {
+				gaps[n-1].To = blockNum
+			} else {
+				gaps = append(gaps, Gap{From: blockNum, To: blockNum})
+			}
+		}
+	}
+
+	for _, gap := range gaps {
+		a.reportGap(gap)
+	}
+	return gaps
+}
+
+func (a *Archiver) reportGap(gap Gap) {
+	if a.gapHandler != nil {
+		a.gapHandler(gap.From, gap.To)
+	}
+}
+
+func (a *Archiver) archive(block *cb.Block) error {
+	for _, sink := range a.sinks {
+		if err := sink.Write(a.channelID, block); err != nil {
+			return errors.WithMessage(err, "failed to archive block")
+		}
+	}
+	return nil
+}
+
+func (a *Archiver) saveCheckpoint(checkpoint *Checkpoint) error {
+	if err := a.checkpointer.Save(checkpoint); err != nil {
+		return errors.WithMessage(err, "failed to save checkpoint")
+	}
+
+	a.mutex.Lock()
+	a.checkpoint = checkpoint
+	a.mutex.Unlock()
+	return nil
+}
+
+// MemoryCheckpointer keeps the checkpoint in memory
+type MemoryCheckpointer struct {
+	mutex      sync.RWMutex
+	checkpoint *Checkpoint
+}
+
+// Load returns the checkpoint
+func (c *MemoryCheckpointer) Load() (*Checkpoint, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.checkpoint, nil
+}
+
+// Save saves the checkpoint
+func (c *MemoryCheckpointer) Save(checkpoint *Checkpoint) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.checkpoint = checkpoint
+	return nil
+}
+
+// FileCheckpointer saves the checkpoint as JSON in a file
+type FileCheckpointer struct {
+	path string
+}
+
+// NewFileCheckpointer returns a checkpointer which saves the checkpoint in the file with the given path
+func NewFileCheckpointer(path string) *FileCheckpointer {
+	return &FileCheckpointer{path: path}
+}
+
+// Load loads the checkpoint from the file. Nil is returned if the file doesn't exist.
+func (c *FileCheckpointer) Load() (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read checkpoint file [%s]", c.path)
+	}
+
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal checkpoint file [%s]", c.path)
+	}
+	return checkpoint, nil
+}
+
+// Save writes the checkpoint to the file
+func (c *FileCheckpointer) Save(checkpoint *Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal checkpoint")
+	}
+	return writeFileAtomic(c.path, data)
+}
+
+// writeFileAtomic writes the file to a temporary file and then renames it so that
+// a partially written file is never observed
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory for [%s]", path)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write [%s]", tmpPath)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return errors.Wrapf(err, "failed to rename [%s] to [%s]", tmpPath, path)
+	}
+	return nil
+}
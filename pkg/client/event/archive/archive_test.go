@@ -0,0 +1,185 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package archive
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const channelID = "mychannel"
+
+type mockSource struct {
+	eventch      chan *fab.BlockEvent
+	unregistered chan struct{}
+}
+
+func newMockSource() *mockSource {
+	return &mockSource{eventch: make(chan *fab.BlockEvent, 10), unregistered: make(chan struct{})}
+}
+
+func (s *mockSource) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	return "reg", s.eventch, nil
+}
+
+func (s *mockSource) Unregister(reg fab.Registration) {
+	close(s.unregistered)
+}
+
+func (s *mockSource) send(blockNums ...uint64) {
+	for _, n := range blockNums {
+		s.eventch <- &fab.BlockEvent{Block: newBlock(n)}
+	}
+}
+
+type mockStore struct {
+	mutex   sync.Mutex
+	objects map[string][]byte
+	err     error
+}
+
+func (s *mockStore) PutObject(key string, data []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.objects[key] = data
+	return nil
+}
+
+func (s *mockStore) keys() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var keys []string
+	for k := range s.objects {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func newBlock(n uint64) *cb.Block {
+	return &cb.Block{Header: &cb.BlockHeader{Number: n}, Data: &cb.BlockData{Data: [][]byte{[]byte("tx")}}}
+}
+
+func waitForCheckpoint(t *testing.T, a *Archiver, blockNum uint64) *Checkpoint {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cp := a.Checkpoint(); cp != nil && cp.BlockNumber == blockNum {
+			return cp
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for checkpoint of block %d", blockNum)
+	return nil
+}
+
+func TestArchiver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := &mockStore{objects: make(map[string][]byte)}
+	var published []string
+	kafka := SinkFunc(func(channelID string, block *cb.Block) error {
+		published = append(published, BlockKey(channelID, block.Header.Number))
+		return nil
+	})
+
+	checkpointer := NewFileCheckpointer(filepath.Join(dir, "checkpoint.json"))
+	var gaps []Gap
+	fetched := map[uint64]bool{3: true}
+
+	source := newMockSource()
+	a, err := New(channelID, source,
+		WithSinks(NewFileSink(dir), NewS3Sink(store, "archive/"), kafka),
+		WithCheckpointer(checkpointer),
+		WithBlockFetcher(func(blockNum uint64) (*cb.Block, error) {
+			if fetched[blockNum] {
+				return newBlock(blockNum), nil
+			}
+			return nil, errors.New("block not found")
+		}),
+		WithGapHandler(func(from, to uint64) { gaps = append(gaps, Gap{From: from, To: to}) }),
+	)
+	require.NoError(t, err)
+	require.NoError(t, a.Start())
+
+	// Block 1 is a duplicate; block 3 is missing and is fetched; blocks 4 and 5 are missing and can't be fetched
+	source.send(0, 1, 2, 1, 6)
+	cp := waitForCheckpoint(t, a, 6)
+	a.Stop()
+	<-source.unregistered
+
+	assert.Equal(t, []Gap{{From: 4, To: 5}}, cp.Gaps)
+	assert.Equal(t, []Gap{{From: 4, To: 5}}, gaps)
+	assert.Equal(t, []string{"mychannel/00000000000000000000.block", "mychannel/00000000000000000001.block",
+		"mychannel/00000000000000000002.block", "mychannel/00000000000000000003.block", "mychannel/00000000000000000006.block"}, published)
+	assert.Len(t, store.keys(), 5)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, channelID, "00000000000000000003.block"))
+	require.NoError(t, err)
+	block := &cb.Block{}
+	require.NoError(t, proto.Unmarshal(data, block))
+	assert.Equal(t, uint64(3), block.Header.Number)
+
+	// The checkpoint is persisted and the block stream resumes after the checkpointed block
+	loaded, err := checkpointer.Load()
+	require.NoError(t, err)
+	assert.Equal(t, cp, loaded)
+
+	opts, err := ResumeOptions(checkpointer)
+	require.NoError(t, err)
+	assert.Len(t, opts, 3)
+}
+
+func TestArchiverSinkError(t *testing.T) {
+	store := &mockStore{objects: make(map[string][]byte), err: errors.New("bucket not found")}
+
+	errch := make(chan error, 1)
+	source := newMockSource()
+	a, err := New(channelID, source, WithSinks(NewS3Sink(store, "")), WithErrorHandler(func(err error) { errch <- err }))
+	require.NoError(t, err)
+	require.NoError(t, a.Start())
+
+	source.send(0)
+
+	select {
+	case err := <-errch:
+		assert.Contains(t, err.Error(), "bucket not found")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for error")
+	}
+	<-source.unregistered
+
+	assert.Error(t, a.Err())
+	assert.Nil(t, a.Checkpoint(), "expecting no checkpoint since the block wasn't archived")
+	a.Stop()
+}
+
+func TestNewArchiver(t *testing.T) {
+	_, err := New("", newMockSource(), WithSinks(NewFileSink("")))
+	assert.Error(t, err, "expecting error for missing channel ID")
+
+	_, err = New(channelID, newMockSource())
+	assert.Error(t, err, "expecting error for missing sinks")
+
+	opts, err := ResumeOptions(&MemoryCheckpointer{})
+	require.NoError(t, err)
+	assert.Len(t, opts, 2, "expecting block events from the oldest block")
+}
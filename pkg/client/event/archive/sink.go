@@ -0,0 +1,117 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package archive
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// Sink writes blocks to an archive. A sink should be idempotent since a block may be written more than once.
+type Sink interface {
+	Write(channelID string, block *cb.Block) error
+}
+
+// SinkFunc is a function that implements Sink
+type SinkFunc func(channelID string, block *cb.Block) error
+
+// Write invokes the function
+func (f SinkFunc) Write(channelID string, block *cb.Block) error {
+	return f(channelID, block)
+}
+
+// BlockKey returns the key under which a block is archived: <channel>/<zero-padded block number>.block.
+// The block number is padded so that the keys sort in block order.
+func BlockKey(channelID string, blockNum uint64) string {
+	return fmt.Sprintf("%s/%020d.block", channelID, blockNum)
+}
+
+// FileSink writes each block (marshalled protobuf) to a file in a directory per channel
+type FileSink struct {
+	dir string
+}
+
+// NewFileSink returns a sink which writes the blocks to files in the given root directory
+func NewFileSink(dir string) *FileSink {
+	return &FileSink{dir: dir}
+}
+
+// Write writes the block to <dir>/<channel>/<block number>.block
+func (s *FileSink) Write(channelID string, block *cb.Block) error {
+	data, err := proto.Marshal(block)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal block")
+	}
+	return writeFileAtomic(filepath.Join(s.dir, filepath.FromSlash(BlockKey(channelID, block.Header.Number))), data)
+}
+
+// ObjectStore puts objects into a bucket. An adapter for the S3 client of the AWS SDK (PutObject)
+// or for any S3 compatible object store may be provided.
+type ObjectStore interface {
+	PutObject(key string, data []byte) error
+}
+
+// S3Sink writes each block (marshalled protobuf) as an object to an S3 bucket
+type S3Sink struct {
+	store  ObjectStore
+	prefix string
+}
+
+// NewS3Sink returns a sink which puts the blocks into the given object store. The keys of the
+// objects are prefixed with the given prefix.
+func NewS3Sink(store ObjectStore, prefix string) *S3Sink {
+	return &S3Sink{store: store, prefix: prefix}
+}
+
+// Write puts the block into the object store under <prefix><channel>/<block number>.block
+func (s *S3Sink) Write(channelID string, block *cb.Block) error {
+	data, err := proto.Marshal(block)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal block")
+	}
+
+	key := s.prefix + BlockKey(channelID, block.Header.Number)
+	if err := s.store.PutObject(key, data); err != nil {
+		return errors.Wrapf(err, "failed to put object [%s]", key)
+	}
+	return nil
+}
+
+// Producer publishes messages to a Kafka topic. An adapter for a Kafka client library
+// (e.g. a synchronous producer) may be provided.
+type Producer interface {
+	SendMessage(topic string, key, value []byte) error
+}
+
+// KafkaSink publishes each block (marshalled protobuf) to a Kafka topic
+type KafkaSink struct {
+	producer Producer
+	topic    string
+}
+
+// NewKafkaSink returns a sink which publishes the blocks to the given topic
+func NewKafkaSink(producer Producer, topic string) *KafkaSink {
+	return &KafkaSink{producer: producer, topic: topic}
+}
+
+// Write publishes the block. The message key is the block key (<channel>/<block number>.block) so that
+// consumers are able to deduplicate blocks which are published more than once.
+func (s *KafkaSink) Write(channelID string, block *cb.Block) error {
+	data, err := proto.Marshal(block)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal block")
+	}
+
+	if err := s.producer.SendMessage(s.topic, []byte(BlockKey(channelID, block.Header.Number)), data); err != nil {
+		return errors.Wrapf(err, "failed to publish block [%d] to topic [%s]", block.Header.Number, s.topic)
+	}
+	return nil
+}
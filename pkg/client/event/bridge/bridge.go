@@ -0,0 +1,332 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package bridge republishes the chaincode events (and optionally the blocks) of a channel to a message
+// broker such as Kafka or NATS. The events are serialized as JSON or protobuf and the message key, which
+// determines the partition, is derived from the chaincode ID, the event name, the transaction ID or a
+// custom function of the event.
+//
+// The bridge implements the archive.Sink interface so the block archiver is used for consuming the block
+// stream, which provides checkpointing (the stream resumes after the last published block on restart) and
+// gap detection. Events are published at least once so consumers should deduplicate them, e.g. by the
+// transaction ID and event name.
+//
+//  Basic Flow:
+//  1) Create a publisher for the broker (e.g. NewKafkaPublisher or NewNATSPublisher)
+//  2) Create a bridge with the publisher
+//  3) Create an event client with archive.ResumeOptions and an archiver with the bridge as a sink and start it
+package bridge
+
+import (
+	"encoding/json"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/event/archive"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const defaultChaincodeEventTopic = "chaincode-events"
+
+// Format is the serialization format of the published messages
+type Format string
+
+const (
+	// JSON serializes a chaincode event as a ChaincodeEvent JSON object and a block as a BlockMessage JSON object
+	JSON Format = "json"
+	// Proto serializes a chaincode event as a marshalled peer.ChaincodeEvent and a block as a marshalled common.Block
+	Proto Format = "proto"
+)
+
+// ChaincodeEvent is a chaincode event along with the position of its transaction on the ledger
+type ChaincodeEvent struct {
+	ChannelID   string `json:"channelId"`
+	BlockNumber uint64 `json:"blockNumber"`
+	TxNumber    uint64 `json:"txNumber"`
+	TxID        string `json:"txId"`
+	ChaincodeID string `json:"chaincodeId"`
+	EventName   string `json:"eventName"`
+	Payload     []byte `json:"payload,omitempty"`
+}
+
+// BlockMessage is the JSON message of a block. Block contains the marshalled common.Block.
+type BlockMessage struct {
+	ChannelID   string `json:"channelId"`
+	BlockNumber uint64 `json:"blockNumber"`
+	Block       []byte `json:"block"`
+}
+
+// PartitionKey returns the message key of a chaincode event
+type PartitionKey func(event *ChaincodeEvent) string
+
+var (
+	// ByChaincode partitions the events by chaincode ID so that the events of a chaincode are kept in order
+	ByChaincode PartitionKey = func(event *ChaincodeEvent) string { return event.ChaincodeID }
+	// ByEventName partitions the events by event name
+	ByEventName PartitionKey = func(event *ChaincodeEvent) string { return event.EventName }
+	// ByTxID partitions the events by transaction ID
+	ByTxID PartitionKey = func(event *ChaincodeEvent) string { return event.TxID }
+)
+
+// Bridge publishes the chaincode events and blocks which it receives to a publisher
+type Bridge struct {
+	publisher  Publisher
+	format     Format
+	ccTopic    string
+	blockTopic string
+	partition  PartitionKey
+	chaincodes map[string]bool
+}
+
+// Option is a functional option for the bridge
+type Option func(b *Bridge)
+
+// WithFormat sets the serialization format of the messages (default JSON)
+func WithFormat(format Format) Option {
+	return func(b *Bridge) {
+		b.format = format
+	}
+}
+
+// WithChaincodeEventTopic sets the topic to which the chaincode events are published (default "chaincode-events").
+// If set to an empty string then chaincode events aren't published.
+func WithChaincodeEventTopic(topic string) Option {
+	return func(b *Bridge) {
+		b.ccTopic = topic
+	}
+}
+
+// WithBlockTopic sets the topic to which the blocks are published. Blocks are only published if a topic is set.
+// The message key of a block is its archive key (<channel>/<block number>.block).
+func WithBlockTopic(topic string) Option {
+	return func(b *Bridge) {
+		b.blockTopic = topic
+	}
+}
+
+// WithPartitionKey sets the function which returns the message key of a chaincode event (default ByChaincode)
+func WithPartitionKey(partition PartitionKey) Option {
+	return func(b *Bridge) {
+		b.partition = partition
+	}
+}
+
+// WithChaincodes restricts the published chaincode events to the given chaincodes
+func WithChaincodes(ccIDs ...string) Option {
+	return func(b *Bridge) {
+		if b.chaincodes == nil {
+			b.chaincodes = make(map[string]bool)
+		}
+		for _, ccID := range ccIDs {
+			b.chaincodes[ccID] = true
+		}
+	}
+}
+
+// New returns a bridge which publishes to the given publisher
+func New(publisher Publisher, opts ...Option) (*Bridge, error) {
+	if publisher == nil {
+		return nil, errors.New("publisher is required")
+	}
+
+	b := &Bridge{
+		publisher: publisher,
+		format:    JSON,
+		ccTopic:   defaultChaincodeEventTopic,
+		partition: ByChaincode,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.format != JSON && b.format != Proto {
+		return nil, errors.Errorf("unsupported format [%s]", b.format)
+	}
+	if b.ccTopic == "" && b.blockTopic == "" {
+		return nil, errors.New("at least one of the chaincode event topic and the block topic is required")
+	}
+	if b.partition == nil {
+		return nil, errors.New("partition key function is required")
+	}
+
+	return b, nil
+}
+
+// Write publishes the block (if a block topic is set) followed by the chaincode events of the valid
+// transactions in the block. It implements the archive.Sink interface.
+func (b *Bridge) Write(channelID string, block *cb.Block) error {
+	if block == nil || block.Header == nil || block.Data == nil {
+		return errors.New("invalid block")
+	}
+
+	if b.blockTopic != "" {
+		if err := b.publishBlock(channelID, block); err != nil {
+			return err
+		}
+	}
+
+	if b.ccTopic == "" {
+		return nil
+	}
+
+	events, err := ChaincodeEvents(channelID, block)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if b.chaincodes != nil && !b.chaincodes[event.ChaincodeID] {
+			continue
+		}
+		if err := b.publishChaincodeEvent(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Bridge) publishBlock(channelID string, block *cb.Block) error {
+	data, err := proto.Marshal(block)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal block")
+	}
+
+	if b.format == JSON {
+		data, err = json.Marshal(&BlockMessage{ChannelID: channelID, BlockNumber: block.Header.Number, Block: data})
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal block message")
+		}
+	}
+
+	key := archive.BlockKey(channelID, block.Header.Number)
+	if err := b.publisher.Publish(b.blockTopic, []byte(key), data); err != nil {
+		return errors.Wrapf(err, "failed to publish block [%d] to topic [%s]", block.Header.Number, b.blockTopic)
+	}
+	return nil
+}
+
+func (b *Bridge) publishChaincodeEvent(event *ChaincodeEvent) error {
+	data, err := b.marshalChaincodeEvent(event)
+	if err != nil {
+		return err
+	}
+
+	if err := b.publisher.Publish(b.ccTopic, []byte(b.partition(event)), data); err != nil {
+		return errors.Wrapf(err, "failed to publish chaincode event [%s] of transaction [%s] to topic [%s]", event.EventName, event.TxID, b.ccTopic)
+	}
+	return nil
+}
+
+func (b *Bridge) marshalChaincodeEvent(event *ChaincodeEvent) ([]byte, error) {
+	if b.format == Proto {
+		data, err := proto.Marshal(&pb.ChaincodeEvent{
+			ChaincodeId: event.ChaincodeID,
+			TxId:        event.TxID,
+			EventName:   event.EventName,
+			Payload:     event.Payload,
+		})
+		return data, errors.Wrap(err, "failed to marshal chaincode event")
+	}
+
+	data, err := json.Marshal(event)
+	return data, errors.Wrap(err, "failed to marshal chaincode event")
+}
+
+// ChaincodeEvents returns the chaincode events of the valid endorser transactions in the given block
+func ChaincodeEvents(channelID string, block *cb.Block) ([]*ChaincodeEvent, error) {
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = ledgerutil.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	var events []*ChaincodeEvent
+	for txNum, data := range block.Data.Data {
+		if txNum < len(txFilter) && !txFilter.IsValid(txNum) {
+			logger.Debugf("Skipping invalid transaction [%d] in block [%d]: %s", txNum, block.Header.Number, txFilter.Flag(txNum))
+			continue
+		}
+
+		event, err := chaincodeEvent(data)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to extract chaincode event of transaction in block")
+		}
+		if event == nil {
+			continue
+		}
+
+		event.ChannelID = channelID
+		event.BlockNumber = block.Header.Number
+		event.TxNumber = uint64(txNum)
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func chaincodeEvent(data []byte) (*ChaincodeEvent, error) {
+	env, err := utils.GetEnvelopeFromBlock(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting Envelope from block")
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting Payload from envelope")
+	}
+	if payload.Header == nil {
+		return nil, errors.New("payload header is nil")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting ChannelHeader from payload")
+	}
+
+	if cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+		return nil, nil
+	}
+
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling transaction payload")
+	}
+	if len(tx.Actions) == 0 {
+		return nil, nil
+	}
+
+	chaincodeActionPayload, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode action payload")
+	}
+	if chaincodeActionPayload.Action == nil {
+		return nil, errors.New("chaincode endorsed action is nil")
+	}
+	propRespPayload, err := utils.GetProposalResponsePayload(chaincodeActionPayload.Action.ProposalResponsePayload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling response payload")
+	}
+	ccAction, err := utils.GetChaincodeAction(propRespPayload.Extension)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode action")
+	}
+	ccEvent, err := utils.GetChaincodeEvents(ccAction.Events)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting chaincode events")
+	}
+	if ccEvent == nil || ccEvent.EventName == "" {
+		return nil, nil
+	}
+
+	return &ChaincodeEvent{
+		TxID:        chdr.TxId,
+		ChaincodeID: ccEvent.ChaincodeId,
+		EventName:   ccEvent.EventName,
+		Payload:     ccEvent.Payload,
+	}, nil
+}
@@ -0,0 +1,199 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bridge
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tx struct {
+	id        string
+	code      pb.TxValidationCode
+	ccID      string
+	eventName string
+}
+
+type message struct {
+	topic string
+	key   string
+	value []byte
+}
+
+type mockPublisher struct {
+	messages []message
+	err      error
+}
+
+func (p *mockPublisher) Publish(topic string, key, value []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.messages = append(p.messages, message{topic: topic, key: string(key), value: value})
+	return nil
+}
+
+type mockNATSConn struct {
+	subjects []string
+}
+
+func (c *mockNATSConn) Publish(subject string, data []byte) error {
+	c.subjects = append(c.subjects, subject)
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil)
+	assert.Error(t, err, "expecting error for missing publisher")
+	_, err = New(&mockPublisher{}, WithFormat("xml"))
+	assert.Error(t, err, "expecting error for unsupported format")
+	_, err = New(&mockPublisher{}, WithChaincodeEventTopic(""))
+	assert.Error(t, err, "expecting error since there is nothing to publish")
+	_, err = New(&mockPublisher{}, WithPartitionKey(nil))
+	assert.Error(t, err, "expecting error for missing partition key function")
+}
+
+func TestPublishChaincodeEventsJSON(t *testing.T) {
+	publisher := &mockPublisher{}
+	b, err := New(publisher)
+	require.NoError(t, err)
+
+	block := newBlock(t, 5,
+		tx{id: "tx1", code: pb.TxValidationCode_VALID, ccID: "cc1", eventName: "created"},
+		tx{id: "tx2", code: pb.TxValidationCode_MVCC_READ_CONFLICT, ccID: "cc1", eventName: "created"},
+		tx{id: "tx3", code: pb.TxValidationCode_VALID, ccID: "cc2"},
+		tx{id: "tx4", code: pb.TxValidationCode_VALID, ccID: "cc2", eventName: "deleted"},
+	)
+	require.NoError(t, b.Write("mychannel", block))
+
+	require.Len(t, publisher.messages, 2, "expecting events of valid transactions only")
+	assert.Equal(t, defaultChaincodeEventTopic, publisher.messages[0].topic)
+	assert.Equal(t, "cc1", publisher.messages[0].key)
+	assert.Equal(t, "cc2", publisher.messages[1].key)
+
+	event := &ChaincodeEvent{}
+	require.NoError(t, json.Unmarshal(publisher.messages[1].value, event))
+	assert.Equal(t, &ChaincodeEvent{
+		ChannelID:   "mychannel",
+		BlockNumber: 5,
+		TxNumber:    3,
+		TxID:        "tx4",
+		ChaincodeID: "cc2",
+		EventName:   "deleted",
+		Payload:     []byte("payload"),
+	}, event)
+}
+
+func TestPublishChaincodeEventsProto(t *testing.T) {
+	publisher := &mockPublisher{}
+	b, err := New(publisher, WithFormat(Proto), WithChaincodeEventTopic("events"), WithPartitionKey(ByTxID), WithChaincodes("cc2"))
+	require.NoError(t, err)
+
+	block := newBlock(t, 1,
+		tx{id: "tx1", code: pb.TxValidationCode_VALID, ccID: "cc1", eventName: "created"},
+		tx{id: "tx2", code: pb.TxValidationCode_VALID, ccID: "cc2", eventName: "created"},
+	)
+	require.NoError(t, b.Write("mychannel", block))
+
+	require.Len(t, publisher.messages, 1, "expecting events of cc2 only")
+	assert.Equal(t, "events", publisher.messages[0].topic)
+	assert.Equal(t, "tx2", publisher.messages[0].key)
+
+	event := &pb.ChaincodeEvent{}
+	require.NoError(t, proto.Unmarshal(publisher.messages[0].value, event))
+	assert.Equal(t, "cc2", event.ChaincodeId)
+	assert.Equal(t, "tx2", event.TxId)
+	assert.Equal(t, "created", event.EventName)
+}
+
+func TestPublishBlocks(t *testing.T) {
+	publisher := &mockPublisher{}
+	b, err := New(publisher, WithChaincodeEventTopic(""), WithBlockTopic("blocks"))
+	require.NoError(t, err)
+
+	block := newBlock(t, 3, tx{id: "tx1", code: pb.TxValidationCode_VALID, ccID: "cc1", eventName: "created"})
+	require.NoError(t, b.Write("mychannel", block))
+
+	require.Len(t, publisher.messages, 1, "expecting block only")
+	assert.Equal(t, "blocks", publisher.messages[0].topic)
+	assert.Equal(t, "mychannel/00000000000000000003.block", publisher.messages[0].key)
+
+	msg := &BlockMessage{}
+	require.NoError(t, json.Unmarshal(publisher.messages[0].value, msg))
+	assert.Equal(t, uint64(3), msg.BlockNumber)
+	published := &cb.Block{}
+	require.NoError(t, proto.Unmarshal(msg.Block, published))
+	assert.True(t, proto.Equal(block, published))
+}
+
+func TestPublishError(t *testing.T) {
+	b, err := New(&mockPublisher{err: errors.New("broker unavailable")})
+	require.NoError(t, err)
+
+	block := newBlock(t, 1, tx{id: "tx1", code: pb.TxValidationCode_VALID, ccID: "cc1", eventName: "created"})
+	assert.Error(t, b.Write("mychannel", block), "expecting error so that the block isn't checkpointed")
+	assert.Error(t, b.Write("mychannel", &cb.Block{}), "expecting error for invalid block")
+}
+
+func TestNATSPublisher(t *testing.T) {
+	conn := &mockNATSConn{}
+	p := NewNATSPublisher(conn)
+
+	require.NoError(t, p.Publish("events", []byte("my.cc>v1"), nil))
+	require.NoError(t, p.Publish("blocks", nil, nil))
+	assert.Equal(t, []string{"events.my_cc_v1", "blocks"}, conn.subjects)
+}
+
+func newBlock(t *testing.T, blockNum uint64, txs ...tx) *cb.Block {
+	block := &cb.Block{
+		Header:   &cb.BlockHeader{Number: blockNum},
+		Data:     &cb.BlockData{},
+		Metadata: &cb.BlockMetadata{Metadata: make([][]byte, len(cb.BlockMetadataIndex_name))},
+	}
+
+	txFilter := ledgerutil.NewTxValidationFlags(len(txs))
+	for i, tx := range txs {
+		block.Data.Data = append(block.Data.Data, newEnvelopeBytes(t, tx))
+		txFilter[i] = uint8(tx.code)
+	}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = txFilter
+
+	return block
+}
+
+func newEnvelopeBytes(t *testing.T, tx tx) []byte {
+	var events []byte
+	if tx.eventName != "" {
+		events = utils.MarshalOrPanic(&pb.ChaincodeEvent{ChaincodeId: tx.ccID, TxId: tx.id, EventName: tx.eventName, Payload: []byte("payload")})
+	}
+
+	prp, err := utils.GetBytesProposalResponsePayload([]byte("hash"), &pb.Response{Status: 200}, nil, events, &pb.ChaincodeID{Name: tx.ccID})
+	require.NoError(t, err)
+
+	ccActionPayload, err := utils.GetBytesChaincodeActionPayload(&pb.ChaincodeActionPayload{Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: prp}})
+	require.NoError(t, err)
+
+	txBytes, err := utils.GetBytesTransaction(&pb.Transaction{Actions: []*pb.TransactionAction{{Payload: ccActionPayload}}})
+	require.NoError(t, err)
+
+	chdr := &cb.ChannelHeader{Type: int32(cb.HeaderType_ENDORSER_TRANSACTION), ChannelId: "mychannel", TxId: tx.id}
+	payload, err := utils.GetBytesPayload(&cb.Payload{Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(chdr)}, Data: txBytes})
+	require.NoError(t, err)
+
+	env, err := utils.GetBytesEnvelope(&cb.Envelope{Payload: payload})
+	require.NoError(t, err)
+	return env
+}
@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bridge
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/event/archive"
+)
+
+// Publisher publishes a message with the given key to a topic of a message broker
+type Publisher interface {
+	Publish(topic string, key, value []byte) error
+}
+
+// PublisherFunc is a function which implements the Publisher interface
+type PublisherFunc func(topic string, key, value []byte) error
+
+// Publish invokes the function
+func (f PublisherFunc) Publish(topic string, key, value []byte) error {
+	return f(topic, key, value)
+}
+
+// KafkaPublisher publishes the messages to Kafka topics. The message key determines the partition.
+type KafkaPublisher struct {
+	producer archive.Producer
+}
+
+// NewKafkaPublisher returns a publisher which sends the messages with the given producer
+// (e.g. an adapter for a synchronous producer of a Kafka client library)
+func NewKafkaPublisher(producer archive.Producer) *KafkaPublisher {
+	return &KafkaPublisher{producer: producer}
+}
+
+// Publish sends the message to the topic
+func (p *KafkaPublisher) Publish(topic string, key, value []byte) error {
+	return p.producer.SendMessage(topic, key, value)
+}
+
+// NATSConn publishes data to a NATS subject. It's satisfied by a connection of the NATS Go client.
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+}
+
+// NATSPublisher publishes the messages to NATS subjects. Since NATS has no message keys, a message with
+// a key is published to the subject <topic>.<key> so that subscribers may filter on the key (e.g. the
+// chaincode ID) with a wildcard subscription.
+type NATSPublisher struct {
+	conn NATSConn
+}
+
+// NewNATSPublisher returns a publisher which publishes the messages on the given connection
+func NewNATSPublisher(conn NATSConn) *NATSPublisher {
+	return &NATSPublisher{conn: conn}
+}
+
+// Publish publishes the message to the subject of the topic and key
+func (p *NATSPublisher) Publish(topic string, key, value []byte) error {
+	return p.conn.Publish(natsSubject(topic, string(key)), value)
+}
+
+// natsSubject replaces the characters of the key which have a special meaning in NATS subjects
+func natsSubject(topic, key string) string {
+	if key == "" {
+		return topic
+	}
+	return topic + "." + strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_").Replace(key)
+}
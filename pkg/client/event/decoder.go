@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// Decoder decodes the payload of a chaincode event into a typed value
+type Decoder func(event *fab.CCEvent) (interface{}, error)
+
+// DecodedCCEvent contains a chaincode event along with its decoded payload. If the payload couldn't
+// be decoded then Value is nil and Err contains the reason.
+type DecodedCCEvent struct {
+	*fab.CCEvent
+	// Value is the decoded payload
+	Value interface{}
+	// Err is the decode error
+	Err error
+}
+
+// JSONDecoder returns a decoder which unmarshals the JSON payload of chaincode events into a new
+// value of the type of the given prototype (e.g. JSONDecoder(&Asset{}) produces *Asset values)
+func JSONDecoder(prototype interface{}) Decoder {
+	newValue := newValueFunc(prototype)
+	return func(event *fab.CCEvent) (interface{}, error) {
+		value := newValue()
+		if err := json.Unmarshal(event.Payload, value); err != nil {
+			return nil, errors.Wrap(err, "unmarshal JSON payload failed")
+		}
+		return value, nil
+	}
+}
+
+// ProtoDecoder returns a decoder which unmarshals the protobuf payload of chaincode events into a new
+// message of the type of the given prototype
+func ProtoDecoder(prototype proto.Message) Decoder {
+	newValue := newValueFunc(prototype)
+	return func(event *fab.CCEvent) (interface{}, error) {
+		msg := newValue().(proto.Message)
+		if err := proto.Unmarshal(event.Payload, msg); err != nil {
+			return nil, errors.Wrap(err, "unmarshal protobuf payload failed")
+		}
+		return msg, nil
+	}
+}
+
+// RegisterDecodedChaincodeEvent registers for chaincode events whose payloads are decoded with the decoder
+// registered for the chaincode (see WithChaincodeEventDecoder). Decode errors are returned with each event so
+// that an invalid payload doesn't affect the other events. Unregister must be called (with the returned
+// registration) when the registration is no longer needed.
+//  Parameters:
+//  ccID is the chaincode ID for which events are to be received
+//  eventFilter is the chaincode event filter (regular expression) for which events are to be received
+//
+//  Returns:
+//  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
+func (c *Client) RegisterDecodedChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *DecodedCCEvent, error) {
+	decoder, ok := c.decoders[ccID]
+	if !ok {
+		return nil, nil, errors.Errorf("no event decoder registered for chaincode [%s]", ccID)
+	}
+
+	reg, events, err := c.eventService.RegisterChaincodeEvent(ccID, eventFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decodedEvents := make(chan *DecodedCCEvent)
+	go func() {
+		defer close(decodedEvents)
+		for event := range events {
+			decodedEvents <- decode(decoder, event)
+		}
+	}()
+
+	return reg, decodedEvents, nil
+}
+
+// decode decodes the event, recovering from a panic in the decoder
+func decode(decoder Decoder, event *fab.CCEvent) (decoded *DecodedCCEvent) {
+	decoded = &DecodedCCEvent{CCEvent: event}
+
+	defer func() {
+		if r := recover(); r != nil {
+			decoded.Value = nil
+			decoded.Err = errors.Errorf("decoder panicked: %v", r)
+		}
+	}()
+
+	decoded.Value, decoded.Err = decoder(event)
+	if decoded.Err != nil {
+		decoded.Err = errors.WithMessage(decoded.Err, "failed to decode payload of event ["+event.EventName+"] in tx ["+event.TxID+"]")
+	}
+	return decoded
+}
+
+func newValueFunc(prototype interface{}) func() interface{} {
+	t := reflect.TypeOf(prototype)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return func() interface{} {
+		if t == nil {
+			var value interface{}
+			return &value
+		}
+		return reflect.New(t).Interface()
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type asset struct {
+	ID    string `json:"id"`
+	Owner string `json:"owner"`
+}
+
+func TestDecodedCCEvents(t *testing.T) {
+	eventService, eventProducer, err := newServiceWithMockProducer(defaultOpts, withBlockLedger(sourceURL))
+	require.NoError(t, err)
+	defer eventProducer.Close()
+	defer eventService.Stop()
+
+	fabCtx := setupCustomTestContext(t, nil)
+	ctx := createChannelContext(fabCtx, channelID)
+
+	ccID := "mycc"
+	panicCCID := "panicky"
+
+	client, err := New(ctx,
+		WithChaincodeEventDecoder(ccID, JSONDecoder(&asset{})),
+		WithChaincodeEventDecoder(panicCCID, func(event *fab.CCEvent) (interface{}, error) {
+			panic("bad decoder")
+		}),
+	)
+	require.NoError(t, err)
+
+	client.eventService = eventService
+
+	_, _, err = client.RegisterDecodedChaincodeEvent("othercc", ".*")
+	assert.Error(t, err, "expecting error since no decoder is registered for the chaincode")
+
+	reg, eventch, err := client.RegisterDecodedChaincodeEvent(ccID, ".*")
+	require.NoError(t, err)
+
+	panicReg, panicEventch, err := client.RegisterDecodedChaincodeEvent(panicCCID, ".*")
+	require.NoError(t, err)
+	defer client.Unregister(panicReg)
+
+	eventProducer.Ledger().NewBlock(channelID,
+		servicemocks.NewTransactionWithCCEvent("txid1", pb.TxValidationCode_VALID, ccID, "created", []byte(`{"id":"asset1","owner":"alice"}`)),
+		servicemocks.NewTransactionWithCCEvent("txid2", pb.TxValidationCode_VALID, ccID, "created", []byte("not json")),
+		servicemocks.NewTransactionWithCCEvent("txid3", pb.TxValidationCode_VALID, panicCCID, "created", []byte("{}")),
+	)
+
+	event := receiveDecodedEvent(t, eventch)
+	require.NoError(t, event.Err)
+	assert.Equal(t, "txid1", event.TxID)
+	assert.Equal(t, &asset{ID: "asset1", Owner: "alice"}, event.Value)
+
+	event = receiveDecodedEvent(t, eventch)
+	assert.Equal(t, "txid2", event.TxID)
+	assert.Nil(t, event.Value)
+	require.Error(t, event.Err)
+	assert.Contains(t, event.Err.Error(), "txid2")
+
+	event = receiveDecodedEvent(t, panicEventch)
+	assert.Equal(t, "txid3", event.TxID)
+	require.Error(t, event.Err)
+	assert.Contains(t, event.Err.Error(), "decoder panicked")
+
+	client.Unregister(reg)
+	select {
+	case _, ok := <-eventch:
+		assert.False(t, ok, "expecting channel to be closed after unregister")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for channel to be closed")
+	}
+}
+
+func TestProtoDecoder(t *testing.T) {
+	payload, err := proto.Marshal(&pb.ChaincodeID{Name: "mycc", Version: "v1"})
+	require.NoError(t, err)
+
+	decoder := ProtoDecoder(&pb.ChaincodeID{})
+
+	value, err := decoder(&fab.CCEvent{Payload: payload})
+	require.NoError(t, err)
+	assert.Equal(t, "mycc", value.(*pb.ChaincodeID).Name)
+
+	_, err = decoder(&fab.CCEvent{Payload: []byte{0xff}})
+	assert.Error(t, err)
+}
+
+func TestWithChaincodeEventDecoderInvalid(t *testing.T) {
+	fabCtx := setupCustomTestContext(t, nil)
+	ctx := createChannelContext(fabCtx, channelID)
+
+	_, err := New(ctx, WithChaincodeEventDecoder("mycc", nil))
+	assert.Error(t, err, "expecting error for nil decoder")
+}
+
+func receiveDecodedEvent(t *testing.T, eventch <-chan *DecodedCCEvent) *DecodedCCEvent {
+	select {
+	case event, ok := <-eventch:
+		require.True(t, ok, "unexpected closed channel")
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for chaincode event")
+	}
+	return nil
+}
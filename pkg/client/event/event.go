@@ -19,8 +19,10 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	clientdisp "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
 	"github.com/pkg/errors"
 )
 
@@ -30,6 +32,8 @@ type Client struct {
 	permitBlockEvents bool
 	fromBlock         uint64
 	seekType          seek.Type
+	exactlyOnce       bool
+	decoders          map[string]Decoder
 }
 
 // New returns a Client instance. Client receives events such as block, filtered block,
@@ -46,7 +50,7 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 	for _, param := range opts {
 		err1 := param(&eventClient)
 		if err1 != nil {
-			return nil, errors.WithMessage(err, "option failed")
+			return nil, errors.WithMessage(err1, "option failed")
 		}
 	}
 
@@ -54,21 +58,21 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 		return nil, errors.New("channel service not initialized")
 	}
 
-	var es fab.EventService
+	var esOpts []options.Opt
 	if eventClient.permitBlockEvents {
-		var opts []options.Opt
-		opts = append(opts, client.WithBlockEvents())
+		esOpts = append(esOpts, client.WithBlockEvents())
 		if eventClient.seekType != "" {
-			opts = append(opts, deliverclient.WithSeekType(eventClient.seekType))
+			esOpts = append(esOpts, deliverclient.WithSeekType(eventClient.seekType))
 			if eventClient.seekType == seek.FromBlock {
-				opts = append(opts, deliverclient.WithBlockNum(eventClient.fromBlock))
+				esOpts = append(esOpts, deliverclient.WithBlockNum(eventClient.fromBlock))
 			}
 		}
-		es, err = channelContext.ChannelService().EventService(opts...)
-	} else {
-		es, err = channelContext.ChannelService().EventService()
+	}
+	if eventClient.exactlyOnce {
+		esOpts = append(esOpts, dispatcher.WithExactlyOnceDispatch(true))
 	}
 
+	es, err := channelContext.ChannelService().EventService(esOpts...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "event service creation failed")
 	}
@@ -123,3 +127,21 @@ func (c *Client) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *f
 func (c *Client) Unregister(reg fab.Registration) {
 	c.eventService.Unregister(reg)
 }
+
+// StreamState returns the current state of the event service's stream so that applications may detect
+// (and alert on) stuck streams.
+//  Returns:
+//  the URL of the peer to which the event service is connected, the number of the last block received,
+//  the time at which the last event was received and the number of blocks by which the stream lags
+//  behind the channel's peers
+func (c *Client) StreamState() (*clientdisp.StreamState, error) {
+	stateProvider, ok := c.eventService.(streamStateProvider)
+	if !ok {
+		return nil, errors.New("stream state is not supported by the event service")
+	}
+	return stateProvider.StreamState()
+}
+
+type streamStateProvider interface {
+	StreamState() (*clientdisp.StreamState, error)
+}
@@ -0,0 +1,387 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fanout delivers the block events of a single event registration to multiple in-process consumers.
+// Each consumer receives the blocks in its own goroutine and tracks its own position, so a slow consumer
+// doesn't block the other consumers (or the event client). Consumers acknowledge the blocks which they
+// have processed and the lag of each consumer (the number of received blocks which it hasn't acknowledged)
+// may be queried.
+//
+// The blocks pending delivery to a consumer are buffered up to a limit. If a consumer falls further behind
+// then its oldest pending blocks are dropped (and counted in its status) rather than blocking the others.
+//
+//  Basic Flow:
+//  1) Create an event client with block events
+//  2) Create a group with the event client and start it
+//  3) Join the consumers to the group
+//  4) Process the events of each consumer and acknowledge them
+//  5) Stop the group
+package fanout
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const defaultBufferSize = 1000
+
+// BlockSource is the subset of the event client used by the group
+type BlockSource interface {
+	RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error)
+	Unregister(reg fab.Registration)
+}
+
+// ConsumerStatus contains the position of a consumer
+type ConsumerStatus struct {
+	Name string
+	// Delivered is the number of the last block delivered to the consumer
+	Delivered uint64
+	// Acked is the number of the last block acknowledged by the consumer
+	Acked uint64
+	// Pending is the number of blocks buffered for delivery to the consumer
+	Pending int
+	// Dropped is the number of blocks which were dropped since the consumer's buffer was full
+	Dropped uint64
+	// Lag is the number of blocks received by the group which haven't been acknowledged by the consumer
+	Lag uint64
+}
+
+// Group delivers block events to multiple consumers
+type Group struct {
+	source     BlockSource
+	bufferSize int
+
+	mutex     sync.RWMutex
+	consumers map[string]*Consumer
+	head      uint64
+	hasHead   bool
+	reg       fab.Registration
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// Option is a functional option for the group
+type Option func(g *Group)
+
+// WithBufferSize sets the maximum number of blocks which are buffered for each consumer (default 1000).
+// If a consumer falls further behind then its oldest pending blocks are dropped.
+func WithBufferSize(size int) Option {
+	return func(g *Group) {
+		g.bufferSize = size
+	}
+}
+
+// New returns a group which delivers the block events received from the given source
+func New(source BlockSource, opts ...Option) (*Group, error) {
+	if source == nil {
+		return nil, errors.New("block source is required")
+	}
+
+	g := &Group{
+		source:     source,
+		bufferSize: defaultBufferSize,
+		consumers:  make(map[string]*Consumer),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if g.bufferSize <= 0 {
+		return nil, errors.New("buffer size must be greater than zero")
+	}
+
+	return g, nil
+}
+
+// Start registers for block events and delivers the received blocks to the consumers in the background
+func (g *Group) Start() error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.done != nil {
+		return errors.New("group already started")
+	}
+
+	reg, eventch, err := g.source.RegisterBlockEvent()
+	if err != nil {
+		return errors.WithMessage(err, "failed to register for block events")
+	}
+
+	g.reg = reg
+	g.done = make(chan struct{})
+	g.stopped = make(chan struct{})
+
+	go g.run(eventch)
+
+	return nil
+}
+
+// Stop unregisters from block events and closes the event channels of all of the consumers
+func (g *Group) Stop() {
+	g.mutex.Lock()
+	done, stopped := g.done, g.stopped
+	if done != nil {
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+	}
+	g.mutex.Unlock()
+
+	if stopped != nil {
+		<-stopped
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	for name, c := range g.consumers {
+		c.close()
+		delete(g.consumers, name)
+	}
+}
+
+// Join adds a consumer with the given name to the group. The consumer receives the blocks which are
+// received by the group after it joined.
+func (g *Group) Join(name string) (*Consumer, error) {
+	if name == "" {
+		return nil, errors.New("consumer name is required")
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if _, ok := g.consumers[name]; ok {
+		return nil, errors.Errorf("consumer [%s] already joined", name)
+	}
+
+	c := newConsumer(name, g.bufferSize)
+	g.consumers[name] = c
+
+	go c.deliver()
+
+	return c, nil
+}
+
+// Leave removes the consumer with the given name from the group and closes its event channel
+func (g *Group) Leave(name string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if c, ok := g.consumers[name]; ok {
+		c.close()
+		delete(g.consumers, name)
+	}
+}
+
+// Lag returns the number of blocks received by the group which haven't been acknowledged by the given consumer
+func (g *Group) Lag(name string) (uint64, error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	c, ok := g.consumers[name]
+	if !ok {
+		return 0, errors.Errorf("consumer [%s] not found", name)
+	}
+	return c.status(g.head, g.hasHead).Lag, nil
+}
+
+// Status returns the status of each of the consumers, sorted by name
+func (g *Group) Status() []ConsumerStatus {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	var status []ConsumerStatus
+	for _, c := range g.consumers {
+		status = append(status, c.status(g.head, g.hasHead))
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Name < status[j].Name })
+	return status
+}
+
+func (g *Group) run(eventch <-chan *fab.BlockEvent) {
+	defer close(g.stopped)
+	defer g.source.Unregister(g.reg)
+
+	for {
+		select {
+		case <-g.done:
+			logger.Debugf("Stopping block event group")
+			return
+		case e, ok := <-eventch:
+			if !ok {
+				logger.Debugf("Block event channel closed - stopping block event group")
+				return
+			}
+			if e.Block == nil || e.Block.Header == nil {
+				logger.Warnf("Ignoring invalid block event")
+				continue
+			}
+			g.dispatch(e)
+		}
+	}
+}
+
+func (g *Group) dispatch(e *fab.BlockEvent) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.head = e.Block.Header.Number
+	g.hasHead = true
+
+	for _, c := range g.consumers {
+		c.enqueue(e)
+	}
+}
+
+// Consumer receives the block events of a group
+type Consumer struct {
+	name       string
+	bufferSize int
+	eventch    chan *fab.BlockEvent
+
+	mutex     sync.Mutex
+	cond      *sync.Cond
+	pending   []*fab.BlockEvent
+	first     uint64
+	received  bool
+	sent      uint64
+	delivered uint64
+	acked     uint64
+	hasAcked  bool
+	dropped   uint64
+	closed    bool
+	closech   chan struct{}
+}
+
+func newConsumer(name string, bufferSize int) *Consumer {
+	c := &Consumer{
+		name:       name,
+		bufferSize: bufferSize,
+		eventch:    make(chan *fab.BlockEvent),
+		closech:    make(chan struct{}),
+	}
+	c.cond = sync.NewCond(&c.mutex)
+	return c
+}
+
+// Name returns the name of the consumer
+func (c *Consumer) Name() string {
+	return c.name
+}
+
+// Events returns the channel on which the consumer receives block events. The channel is closed when
+// the consumer leaves the group or the group is stopped.
+func (c *Consumer) Events() <-chan *fab.BlockEvent {
+	return c.eventch
+}
+
+// Ack acknowledges that the consumer has processed the blocks up to (and including) the given block
+func (c *Consumer) Ack(blockNum uint64) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.received || blockNum > c.sent || blockNum < c.first {
+		return errors.Errorf("block [%d] hasn't been delivered to consumer [%s]", blockNum, c.name)
+	}
+	if !c.hasAcked || blockNum > c.acked {
+		c.acked = blockNum
+		c.hasAcked = true
+	}
+	return nil
+}
+
+func (c *Consumer) enqueue(e *fab.BlockEvent) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.closed {
+		return
+	}
+	if !c.received {
+		c.first = e.Block.Header.Number
+		c.received = true
+	}
+	if len(c.pending) >= c.bufferSize {
+		logger.Warnf("Buffer of consumer [%s] is full - dropping block [%d]", c.name, c.pending[0].Block.Header.Number)
+		c.pending[0] = nil
+		c.pending = c.pending[1:]
+		c.dropped++
+	}
+	c.pending = append(c.pending, e)
+	c.cond.Signal()
+}
+
+func (c *Consumer) deliver() {
+	defer close(c.eventch)
+
+	for {
+		c.mutex.Lock()
+		for len(c.pending) == 0 && !c.closed {
+			c.cond.Wait()
+		}
+		if c.closed {
+			c.mutex.Unlock()
+			return
+		}
+		e := c.pending[0]
+		c.pending[0] = nil
+		c.pending = c.pending[1:]
+		// The consumer may acknowledge the block as soon as it's received
+		c.sent = e.Block.Header.Number
+		c.mutex.Unlock()
+
+		select {
+		case c.eventch <- e:
+			c.mutex.Lock()
+			c.delivered = e.Block.Header.Number
+			c.mutex.Unlock()
+		case <-c.closech:
+			return
+		}
+	}
+}
+
+func (c *Consumer) close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.pending = nil
+	close(c.closech)
+	c.cond.Broadcast()
+}
+
+func (c *Consumer) status(head uint64, hasHead bool) ConsumerStatus {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	status := ConsumerStatus{
+		Name:      c.name,
+		Delivered: c.delivered,
+		Acked:     c.acked,
+		Pending:   len(c.pending),
+		Dropped:   c.dropped,
+	}
+
+	switch {
+	case !c.received || !hasHead:
+	case c.hasAcked:
+		status.Lag = head - c.acked
+	default:
+		status.Lag = head - c.first + 1
+	}
+	return status
+}
@@ -0,0 +1,142 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fanout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSource struct {
+	eventch      chan *fab.BlockEvent
+	unregistered chan struct{}
+}
+
+func newMockSource() *mockSource {
+	return &mockSource{eventch: make(chan *fab.BlockEvent, 10), unregistered: make(chan struct{})}
+}
+
+func (s *mockSource) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	return "reg", s.eventch, nil
+}
+
+func (s *mockSource) Unregister(reg fab.Registration) {
+	close(s.unregistered)
+}
+
+func (s *mockSource) send(blockNums ...uint64) {
+	for _, n := range blockNums {
+		s.eventch <- &fab.BlockEvent{Block: &cb.Block{Header: &cb.BlockHeader{Number: n}}}
+	}
+}
+
+func receive(t *testing.T, c *Consumer) uint64 {
+	select {
+	case e, ok := <-c.Events():
+		require.True(t, ok, "unexpected closed channel")
+		return e.Block.Header.Number
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for block event for consumer [%s]", c.Name())
+	}
+	return 0
+}
+
+func waitForStatus(t *testing.T, g *Group, name string, check func(status ConsumerStatus) bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, status := range g.Status() {
+			if status.Name == name && check(status) {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for status of consumer [%s]: %+v", name, g.Status())
+}
+
+func TestGroup(t *testing.T) {
+	source := newMockSource()
+	g, err := New(source, WithBufferSize(2))
+	require.NoError(t, err)
+	require.NoError(t, g.Start())
+	assert.Error(t, g.Start(), "expecting error since group is already started")
+
+	fast, err := g.Join("fast")
+	require.NoError(t, err)
+	slow, err := g.Join("slow")
+	require.NoError(t, err)
+
+	_, err = g.Join("fast")
+	assert.Error(t, err, "expecting error for duplicate consumer")
+
+	assert.Error(t, fast.Ack(1), "expecting error for block which hasn't been delivered")
+
+	// The fast consumer receives all of the blocks even though the slow consumer isn't receiving
+	for n := uint64(1); n <= 5; n++ {
+		source.send(n)
+		assert.Equal(t, n, receive(t, fast))
+		require.NoError(t, fast.Ack(n))
+		if n == 1 {
+			waitForStatus(t, g, "slow", func(status ConsumerStatus) bool { return status.Pending == 0 })
+		}
+	}
+
+	lag, err := g.Lag("fast")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), lag)
+
+	// The slow consumer has block 1 in flight and has a buffer of two blocks (4 and 5) so blocks 2 and 3 are dropped
+	waitForStatus(t, g, "slow", func(status ConsumerStatus) bool { return status.Dropped == 2 })
+
+	lag, err = g.Lag("slow")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), lag)
+
+	assert.Equal(t, uint64(1), receive(t, slow))
+	require.NoError(t, slow.Ack(1))
+	assert.Equal(t, uint64(4), receive(t, slow))
+	require.NoError(t, slow.Ack(4))
+
+	lag, err = g.Lag("slow")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), lag)
+
+	_, err = g.Lag("unknown")
+	assert.Error(t, err)
+
+	g.Leave("fast")
+	_, ok := <-fast.Events()
+	assert.False(t, ok, "expecting channel to be closed after leaving the group")
+	require.Len(t, g.Status(), 1)
+
+	g.Stop()
+	select {
+	case <-source.unregistered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expecting group to unregister from block events")
+	}
+	for range slow.Events() {
+	}
+}
+
+func TestNewGroupErrors(t *testing.T) {
+	_, err := New(nil)
+	assert.Error(t, err)
+
+	_, err = New(newMockSource(), WithBufferSize(0))
+	assert.Error(t, err)
+
+	g, err := New(newMockSource())
+	require.NoError(t, err)
+	_, err = g.Join("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,290 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package leader ensures that, in a horizontally scaled application, only one replica actively consumes
+// and processes chaincode events while the other replicas stand by. The replicas compete for a lease on
+// a distributed lock (e.g. backed by etcd or a database) which is supplied by the application. The replica
+// which holds the lease registers for the chaincode events and renews the lease periodically. If it fails
+// to renew the lease (or it's stopped) then it unregisters and one of the standby replicas takes over once
+// the lease has expired.
+//
+// Note that the events which are emitted while no replica is active (i.e. until the lease of a failed
+// replica expires) are only received by the new leader if its event client seeks from an earlier block.
+//
+//  Basic Flow:
+//  1) Implement the Lock interface
+//  2) Create an event client in each replica
+//  3) Create a consumer with the event client, the lock and the event handler and start it
+//  4) Stop the consumer when the replica shuts down (the lease is released)
+package leader
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const defaultTTL = 15 * time.Second
+
+// Lock is a distributed lock which is held under a lease
+type Lock interface {
+	// TryAcquire acquires the lock for the given owner, or renews the lease if the owner already holds the
+	// lock, such that the lease expires after the given TTL. It returns false without blocking if the
+	// lock is held by another owner whose lease hasn't expired.
+	TryAcquire(owner string, ttl time.Duration) (bool, error)
+
+	// Release releases the lock if it's held by the given owner
+	Release(owner string) error
+}
+
+// EventSource is the subset of the event client used by the consumer
+type EventSource interface {
+	RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error)
+	Unregister(reg fab.Registration)
+}
+
+// Handler processes a chaincode event. An error is logged and doesn't stop the consumer.
+type Handler func(event *fab.CCEvent) error
+
+// Consumer processes the chaincode events while it holds the lock
+type Consumer struct {
+	source        EventSource
+	lock          Lock
+	id            string
+	ccID          string
+	eventFilter   string
+	handler       Handler
+	ttl           time.Duration
+	renewInterval time.Duration
+	onChange      func(active bool)
+
+	mutex     sync.RWMutex
+	reg       fab.Registration
+	processed chan struct{}
+	active    bool
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// Option is a functional option for the consumer
+type Option func(c *Consumer)
+
+// WithTTL sets the time after which the lease expires if it isn't renewed (default 15s). This is the
+// maximum time for which no replica processes events after the active replica fails.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Consumer) {
+		c.ttl = ttl
+	}
+}
+
+// WithRenewInterval sets the interval at which the lease is renewed (or at which a standby replica attempts
+// to acquire the lock). It must be less than the TTL and defaults to a third of the TTL.
+func WithRenewInterval(interval time.Duration) Option {
+	return func(c *Consumer) {
+		c.renewInterval = interval
+	}
+}
+
+// WithActiveChangeHandler sets a function which is invoked when the replica becomes active or goes on standby
+func WithActiveChangeHandler(onChange func(active bool)) Option {
+	return func(c *Consumer) {
+		c.onChange = onChange
+	}
+}
+
+// New returns a consumer which processes the chaincode events of the given chaincode (matching the event filter)
+// while the replica with the given ID holds the lock. The ID must be unique among the replicas.
+func New(source EventSource, lock Lock, id, ccID, eventFilter string, handler Handler, opts ...Option) (*Consumer, error) {
+	if source == nil {
+		return nil, errors.New("event source is required")
+	}
+	if lock == nil {
+		return nil, errors.New("lock is required")
+	}
+	if id == "" {
+		return nil, errors.New("replica ID is required")
+	}
+	if handler == nil {
+		return nil, errors.New("event handler is required")
+	}
+
+	c := &Consumer{
+		source:      source,
+		lock:        lock,
+		id:          id,
+		ccID:        ccID,
+		eventFilter: eventFilter,
+		handler:     handler,
+		ttl:         defaultTTL,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.renewInterval == 0 {
+		c.renewInterval = c.ttl / 3
+	}
+	if c.renewInterval <= 0 || c.renewInterval >= c.ttl {
+		return nil, errors.Errorf("renew interval [%s] must be greater than zero and less than the TTL [%s]", c.renewInterval, c.ttl)
+	}
+
+	return c, nil
+}
+
+// Start starts competing for the lock in the background
+func (c *Consumer) Start() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.done != nil {
+		return errors.New("consumer already started")
+	}
+
+	c.done = make(chan struct{})
+	c.stopped = make(chan struct{})
+
+	go c.run()
+
+	return nil
+}
+
+// Stop stops processing events (after the event being processed) and releases the lock
+func (c *Consumer) Stop() {
+	c.mutex.Lock()
+	done, stopped := c.done, c.stopped
+	if done != nil {
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+	}
+	c.mutex.Unlock()
+
+	if stopped != nil {
+		<-stopped
+	}
+}
+
+// IsActive returns true if the replica holds the lock and processes the events
+func (c *Consumer) IsActive() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.active
+}
+
+func (c *Consumer) run() {
+	defer close(c.stopped)
+
+	ticker := time.NewTicker(c.renewInterval)
+	defer ticker.Stop()
+
+	for {
+		c.campaign()
+
+		select {
+		case <-c.done:
+			c.deactivate()
+			if err := c.lock.Release(c.id); err != nil {
+				logger.Warnf("Error releasing lock of replica [%s]: %s", c.id, err)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign acquires the lock (or renews the lease) and activates or deactivates the replica accordingly
+func (c *Consumer) campaign() {
+	acquired, err := c.lock.TryAcquire(c.id, c.ttl)
+	if err != nil {
+		// The lease may expire before it can be renewed so the replica mustn't process any more events
+		logger.Warnf("Error acquiring lock for replica [%s]: %s", c.id, err)
+		acquired = false
+	}
+
+	if acquired == c.IsActive() {
+		return
+	}
+
+	if !acquired {
+		logger.Infof("Replica [%s] lost the lock - going on standby", c.id)
+		c.deactivate()
+		return
+	}
+
+	logger.Infof("Replica [%s] acquired the lock - processing chaincode events of [%s]", c.id, c.ccID)
+	if err := c.activate(); err != nil {
+		logger.Errorf("Error activating replica [%s]: %s - releasing the lock", c.id, err)
+		if err := c.lock.Release(c.id); err != nil {
+			logger.Warnf("Error releasing lock of replica [%s]: %s", c.id, err)
+		}
+	}
+}
+
+func (c *Consumer) activate() error {
+	reg, eventch, err := c.source.RegisterChaincodeEvent(c.ccID, c.eventFilter)
+	if err != nil {
+		return errors.WithMessage(err, "failed to register for chaincode events")
+	}
+
+	processed := make(chan struct{})
+
+	c.mutex.Lock()
+	c.reg = reg
+	c.processed = processed
+	c.active = true
+	c.mutex.Unlock()
+
+	go c.process(eventch, processed)
+
+	c.notify(true)
+	return nil
+}
+
+func (c *Consumer) deactivate() {
+	c.mutex.Lock()
+	if !c.active {
+		c.mutex.Unlock()
+		return
+	}
+	reg, processed := c.reg, c.processed
+	c.active = false
+	c.reg = nil
+	c.processed = nil
+	c.mutex.Unlock()
+
+	// Unregistering closes the event channel
+	c.source.Unregister(reg)
+	<-processed
+
+	c.notify(false)
+}
+
+func (c *Consumer) process(eventch <-chan *fab.CCEvent, processed chan<- struct{}) {
+	defer close(processed)
+
+	for event := range eventch {
+		if !c.IsActive() {
+			logger.Debugf("Replica [%s] is on standby - ignoring chaincode event [%s] of transaction [%s]", c.id, event.EventName, event.TxID)
+			continue
+		}
+		if err := c.handler(event); err != nil {
+			logger.Warnf("Error handling chaincode event [%s] of transaction [%s]: %s", event.EventName, event.TxID, err)
+		}
+	}
+}
+
+func (c *Consumer) notify(active bool) {
+	if c.onChange != nil {
+		c.onChange(active)
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package leader
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLock struct {
+	mutex   sync.Mutex
+	owner   string
+	expiry  time.Time
+	failFor string
+}
+
+func (l *mockLock) TryAcquire(owner string, ttl time.Duration) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if owner == l.failFor {
+		return false, errors.New("lock backend unavailable")
+	}
+	if l.owner != "" && l.owner != owner && time.Now().Before(l.expiry) {
+		return false, nil
+	}
+	l.owner = owner
+	l.expiry = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (l *mockLock) Release(owner string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.owner == owner {
+		l.owner = ""
+	}
+	return nil
+}
+
+func (l *mockLock) setFailFor(owner string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.failFor = owner
+}
+
+type mockSource struct {
+	mutex   sync.Mutex
+	eventch chan *fab.CCEvent
+}
+
+func (s *mockSource) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.eventch = make(chan *fab.CCEvent, 10)
+	return s.eventch, s.eventch, nil
+}
+
+func (s *mockSource) Unregister(reg fab.Registration) {
+	close(reg.(chan *fab.CCEvent))
+}
+
+func (s *mockSource) send(txID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.eventch <- &fab.CCEvent{TxID: txID, EventName: "event"}
+}
+
+type recorder struct {
+	mutex  sync.Mutex
+	events []string
+}
+
+func (r *recorder) handle(event *fab.CCEvent) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, event.TxID)
+	return nil
+}
+
+func (r *recorder) received() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string{}, r.events...)
+}
+
+func waitFor(t *testing.T, msg string, check func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if check() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", msg)
+}
+
+func TestNew(t *testing.T) {
+	handler := func(event *fab.CCEvent) error { return nil }
+
+	_, err := New(nil, &mockLock{}, "r1", "cc", ".*", handler)
+	assert.Error(t, err)
+	_, err = New(&mockSource{}, nil, "r1", "cc", ".*", handler)
+	assert.Error(t, err)
+	_, err = New(&mockSource{}, &mockLock{}, "", "cc", ".*", handler)
+	assert.Error(t, err)
+	_, err = New(&mockSource{}, &mockLock{}, "r1", "cc", ".*", nil)
+	assert.Error(t, err)
+	_, err = New(&mockSource{}, &mockLock{}, "r1", "cc", ".*", handler, WithTTL(time.Second), WithRenewInterval(time.Second))
+	assert.Error(t, err, "expecting error since renew interval isn't less than the TTL")
+
+	c, err := New(&mockSource{}, &mockLock{}, "r1", "cc", ".*", handler, WithTTL(3*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, c.renewInterval)
+}
+
+func TestSingleActiveConsumer(t *testing.T) {
+	lock := &mockLock{}
+	source1, source2 := &mockSource{}, &mockSource{}
+	recorder1, recorder2 := &recorder{}, &recorder{}
+	opts := []Option{WithTTL(300 * time.Millisecond), WithRenewInterval(50 * time.Millisecond)}
+
+	var changes []bool
+	var changesMutex sync.Mutex
+	onChange := WithActiveChangeHandler(func(active bool) {
+		changesMutex.Lock()
+		defer changesMutex.Unlock()
+		changes = append(changes, active)
+	})
+
+	c1, err := New(source1, lock, "r1", "cc", ".*", recorder1.handle, append(opts, onChange)...)
+	require.NoError(t, err)
+	require.NoError(t, c1.Start())
+	assert.Error(t, c1.Start(), "expecting error since consumer is already started")
+	waitFor(t, "replica 1 to become active", c1.IsActive)
+
+	c2, err := New(source2, lock, "r2", "cc", ".*", recorder2.handle, opts...)
+	require.NoError(t, err)
+	require.NoError(t, c2.Start())
+	defer c2.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, c2.IsActive(), "expecting replica 2 to stand by")
+
+	source1.send("tx1")
+	waitFor(t, "event to be processed by replica 1", func() bool { return len(recorder1.received()) == 1 })
+
+	// Replica 1 can't renew its lease so it goes on standby and replica 2 takes over once the lease expires
+	lock.setFailFor("r1")
+	waitFor(t, "replica 1 to go on standby", func() bool { return !c1.IsActive() })
+	waitFor(t, "replica 2 to become active", c2.IsActive)
+
+	source2.send("tx2")
+	waitFor(t, "event to be processed by replica 2", func() bool { return len(recorder2.received()) == 1 })
+	assert.Equal(t, []string{"tx1"}, recorder1.received())
+	assert.Equal(t, []string{"tx2"}, recorder2.received())
+
+	c1.Stop()
+	changesMutex.Lock()
+	assert.Equal(t, []bool{true, false}, changes)
+	changesMutex.Unlock()
+}
+
+func TestStopReleasesLock(t *testing.T) {
+	lock := &mockLock{}
+	opts := []Option{WithTTL(time.Minute), WithRenewInterval(50 * time.Millisecond)}
+
+	c1, err := New(&mockSource{}, lock, "r1", "cc", ".*", (&recorder{}).handle, opts...)
+	require.NoError(t, err)
+	require.NoError(t, c1.Start())
+	waitFor(t, "replica 1 to become active", c1.IsActive)
+
+	c2, err := New(&mockSource{}, lock, "r2", "cc", ".*", (&recorder{}).handle, opts...)
+	require.NoError(t, err)
+	require.NoError(t, c2.Start())
+	defer c2.Stop()
+
+	c1.Stop()
+	assert.False(t, c1.IsActive())
+
+	// Replica 2 takes over without waiting for the lease of replica 1 to expire
+	waitFor(t, "replica 2 to become active", c2.IsActive)
+}
@@ -6,7 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 
 package event
 
-import "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/pkg/errors"
+)
 
 // ClientOption describes a functional parameter for the New constructor
 type ClientOption func(*Client) error
@@ -37,3 +40,31 @@ func WithSeekType(seek seek.Type) ClientOption {
 		return nil
 	}
 }
+
+// WithExactlyOnceDispatch indicates that each event is to be delivered exactly once to each registration,
+// including across reconnects of the event client. After reconnecting, the event client resumes from the last
+// block whose events were delivered and suppresses the events which were already delivered.
+// Note that, with this option, the event client blocks until each event is accepted by the consumer, so
+// registrations must be serviced promptly.
+func WithExactlyOnceDispatch() ClientOption {
+	return func(c *Client) error {
+		c.exactlyOnce = true
+		return nil
+	}
+}
+
+// WithChaincodeEventDecoder registers a decoder for the payloads of the events of the given chaincode.
+// Events of the chaincode received with RegisterDecodedChaincodeEvent are decoded into typed values
+// (see JSONDecoder and ProtoDecoder).
+func WithChaincodeEventDecoder(ccID string, decoder Decoder) ClientOption {
+	return func(c *Client) error {
+		if ccID == "" || decoder == nil {
+			return errors.New("chaincode ID and decoder are required")
+		}
+		if c.decoders == nil {
+			c.decoders = make(map[string]Decoder)
+		}
+		c.decoders[ccID] = decoder
+		return nil
+	}
+}
@@ -0,0 +1,341 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package replay reconstructs the world state and the key-value history of a channel by replaying
+// blocks. The writes of valid endorser transactions are extracted from the blocks and are applied,
+// in commit order, to a caller-supplied store (e.g. an off-chain reporting database).
+//
+// Purges of private data keys (PurgePrivateData, Fabric 2.5 or later) are passed to stores implementing Purger,
+// so that private data held off-chain may be removed along with the private data on the peers.
+//
+// The replayer implements the archive.Sink interface so the block archiver may be used for
+// consuming the deliver stream, which also provides checkpointing and gap detection.
+//
+//  Basic Flow:
+//  1) Create a store
+//  2) Create a replayer with the store
+//  3) Create an archiver with the replayer as a sink and start it (or invoke Apply for each block)
+package replay
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// KVWrite is a write (or delete) of a key by a transaction
+type KVWrite struct {
+	Namespace string
+	Key       string
+	Value     []byte
+	IsDelete  bool
+	BlockNum  uint64
+	TxNum     uint64
+	TxID      string
+	Timestamp time.Time
+}
+
+// PrivateDataPurge is the purge of a private data key by a transaction (PurgePrivateData, Fabric 2.5 or later).
+// Only the hash of the key is recorded in the block.
+type PrivateDataPurge struct {
+	Namespace  string
+	Collection string
+	KeyHash    []byte
+	BlockNum   uint64
+	TxNum      uint64
+	TxID       string
+	Timestamp  time.Time
+}
+
+// Store receives the writes of the replayed transactions
+type Store interface {
+	// Put applies the write to the store. The writes are applied in commit order.
+	Put(write *KVWrite) error
+}
+
+// BlockCommitter may be implemented by a store which needs to know when all of the writes of a block
+// have been applied (e.g. in order to commit a database transaction per block)
+type BlockCommitter interface {
+	CommitBlock(blockNum uint64) error
+}
+
+// Purger may be implemented by a store which holds private data (e.g. received off-chain), so that
+// the private data of purged keys is removed from the store as it is from the peers
+type Purger interface {
+	// Purge removes the private data of the key. Purges are applied in commit order along with the writes.
+	Purge(purge *PrivateDataPurge) error
+}
+
+// Replayer applies the writes in blocks to a store
+type Replayer struct {
+	store      Store
+	namespaces map[string]bool
+}
+
+// Option is a functional option for the replayer
+type Option func(r *Replayer)
+
+// WithNamespaces restricts the replayed writes to the given namespaces (chaincodes)
+func WithNamespaces(namespaces ...string) Option {
+	return func(r *Replayer) {
+		if r.namespaces == nil {
+			r.namespaces = make(map[string]bool)
+		}
+		for _, ns := range namespaces {
+			r.namespaces[ns] = true
+		}
+	}
+}
+
+// New returns a replayer which applies the writes to the given store
+func New(store Store, opts ...Option) (*Replayer, error) {
+	if store == nil {
+		return nil, errors.New("store is required")
+	}
+
+	r := &Replayer{store: store}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// Write applies the block. It implements the archive.Sink interface.
+func (r *Replayer) Write(channelID string, block *cb.Block) error {
+	return r.Apply(block)
+}
+
+// Apply applies the writes of the valid endorser transactions in the given block to the store
+func (r *Replayer) Apply(block *cb.Block) error {
+	if block == nil || block.Header == nil || block.Data == nil {
+		return errors.New("invalid block")
+	}
+	blockNum := block.Header.Number
+
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = ledgerutil.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	for txNum, data := range block.Data.Data {
+		if txNum < len(txFilter) && !txFilter.IsValid(txNum) {
+			logger.Debugf("Skipping invalid transaction [%d] in block [%d]: %s", txNum, blockNum, txFilter.Flag(txNum))
+			continue
+		}
+
+		writes, purges, err := r.extractWrites(data, blockNum, uint64(txNum))
+		if err != nil {
+			return errors.WithMessage(err, "failed to extract writes of transaction in block")
+		}
+
+		for _, write := range writes {
+			if err := r.store.Put(write); err != nil {
+				return errors.WithMessage(err, "failed to apply write to store")
+			}
+		}
+
+		if purger, ok := r.store.(Purger); ok {
+			for _, purge := range purges {
+				if err := purger.Purge(purge); err != nil {
+					return errors.WithMessage(err, "failed to apply purge to store")
+				}
+			}
+		}
+	}
+
+	if committer, ok := r.store.(BlockCommitter); ok {
+		if err := committer.CommitBlock(blockNum); err != nil {
+			return errors.WithMessage(err, "failed to commit block to store")
+		}
+	}
+	return nil
+}
+
+// extractWrites returns the public writes and the private data purges of the transaction
+func (r *Replayer) extractWrites(data []byte, blockNum, txNum uint64) ([]*KVWrite, []*PrivateDataPurge, error) {
+	env, err := utils.GetEnvelopeFromBlock(data)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error extracting Envelope from block")
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error extracting Payload from envelope")
+	}
+	if payload.Header == nil {
+		return nil, nil, errors.New("payload header is nil")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error extracting ChannelHeader from payload")
+	}
+
+	if cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+		return nil, nil, nil
+	}
+
+	var timestamp time.Time
+	if chdr.Timestamp != nil {
+		timestamp, err = ptypes.Timestamp(chdr.Timestamp)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid transaction timestamp")
+		}
+	}
+
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error unmarshalling transaction payload")
+	}
+
+	var writes []*KVWrite
+	var purges []*PrivateDataPurge
+	for _, action := range tx.Actions {
+		txRWSet, err := getTxRWSet(action)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			if r.namespaces != nil && !r.namespaces[nsRWSet.NameSpace] {
+				continue
+			}
+			for _, coll := range nsRWSet.CollHashedRwSets {
+				for _, w := range coll.HashedRwSet.GetHashedWrites() {
+					if !w.IsPurge {
+						continue
+					}
+					purges = append(purges, &PrivateDataPurge{
+						Namespace:  nsRWSet.NameSpace,
+						Collection: coll.CollectionName,
+						KeyHash:    w.KeyHash,
+						BlockNum:   blockNum,
+						TxNum:      txNum,
+						TxID:       chdr.TxId,
+						Timestamp:  timestamp,
+					})
+				}
+			}
+			if nsRWSet.KvRwSet == nil {
+				continue
+			}
+			for _, w := range nsRWSet.KvRwSet.Writes {
+				writes = append(writes, &KVWrite{
+					Namespace: nsRWSet.NameSpace,
+					Key:       w.Key,
+					Value:     w.Value,
+					IsDelete:  w.IsDelete,
+					BlockNum:  blockNum,
+					TxNum:     txNum,
+					TxID:      chdr.TxId,
+					Timestamp: timestamp,
+				})
+			}
+		}
+	}
+	return writes, purges, nil
+}
+
+func getTxRWSet(action *pb.TransactionAction) (*rwsetutil.TxRwSet, error) {
+	chaincodeActionPayload, err := utils.GetChaincodeActionPayload(action.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode action payload")
+	}
+	if chaincodeActionPayload.Action == nil {
+		return nil, errors.New("chaincode endorsed action is nil")
+	}
+	propRespPayload, err := utils.GetProposalResponsePayload(chaincodeActionPayload.Action.ProposalResponsePayload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling response payload")
+	}
+	ccAction, err := utils.GetChaincodeAction(propRespPayload.Extension)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode action")
+	}
+
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(ccAction.Results); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling read-write set")
+	}
+	return txRWSet, nil
+}
+
+// MemoryStore is an in-memory store which keeps the current state and the history of every key
+type MemoryStore struct {
+	mutex   sync.RWMutex
+	state   map[string]map[string][]byte
+	history map[string]map[string][]*KVWrite
+}
+
+// NewMemoryStore returns a new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		state:   make(map[string]map[string][]byte),
+		history: make(map[string]map[string][]*KVWrite),
+	}
+}
+
+// Put applies the write
+func (s *MemoryStore) Put(write *KVWrite) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	nsState, ok := s.state[write.Namespace]
+	if !ok {
+		nsState = make(map[string][]byte)
+		s.state[write.Namespace] = nsState
+	}
+	if write.IsDelete {
+		delete(nsState, write.Key)
+	} else {
+		nsState[write.Key] = write.Value
+	}
+
+	nsHistory, ok := s.history[write.Namespace]
+	if !ok {
+		nsHistory = make(map[string][]*KVWrite)
+		s.history[write.Namespace] = nsHistory
+	}
+	nsHistory[write.Key] = append(nsHistory[write.Key], write)
+	return nil
+}
+
+// State returns the current value of the key in the namespace
+func (s *MemoryStore) State(namespace, key string) ([]byte, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	value, ok := s.state[namespace][key]
+	return value, ok
+}
+
+// Keys returns the keys in the namespace which currently have a value
+func (s *MemoryStore) Keys(namespace string) []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var keys []string
+	for key := range s.state[namespace] {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// History returns the writes of the key in the namespace in commit order
+func (s *MemoryStore) History(namespace, key string) []*KVWrite {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return append([]*KVWrite(nil), s.history[namespace][key]...)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replay
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/event/archive"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tx struct {
+	id     string
+	code   pb.TxValidationCode
+	writes map[string][]*kvrwset.KVWrite
+	hashed map[string][]*rwsetutil.CollHashedRwSet
+}
+
+type committingStore struct {
+	*MemoryStore
+	blocks []uint64
+}
+
+func (s *committingStore) CommitBlock(blockNum uint64) error {
+	s.blocks = append(s.blocks, blockNum)
+	return nil
+}
+
+func TestReplay(t *testing.T) {
+	store := &committingStore{MemoryStore: NewMemoryStore()}
+	r, err := New(store, WithNamespaces("cc1"))
+	require.NoError(t, err)
+
+	var _ archive.Sink = r
+
+	block0 := newBlock(t, 0,
+		tx{id: "tx1", code: pb.TxValidationCode_VALID, writes: map[string][]*kvrwset.KVWrite{
+			"cc1": {{Key: "a", Value: []byte("1")}, {Key: "b", Value: []byte("1")}},
+			"cc2": {{Key: "c", Value: []byte("1")}},
+		}},
+		tx{id: "tx2", code: pb.TxValidationCode_MVCC_READ_CONFLICT, writes: map[string][]*kvrwset.KVWrite{
+			"cc1": {{Key: "a", Value: []byte("invalid")}},
+		}},
+	)
+	block1 := newBlock(t, 1,
+		tx{id: "tx3", code: pb.TxValidationCode_VALID, writes: map[string][]*kvrwset.KVWrite{
+			"cc1": {{Key: "a", Value: []byte("2")}, {Key: "b", IsDelete: true}},
+		}},
+	)
+
+	require.NoError(t, r.Apply(block0))
+	require.NoError(t, r.Write("mychannel", block1))
+
+	value, ok := store.State("cc1", "a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("2"), value)
+
+	_, ok = store.State("cc1", "b")
+	assert.False(t, ok, "expecting key to be deleted")
+
+	_, ok = store.State("cc2", "c")
+	assert.False(t, ok, "expecting namespace to be filtered")
+
+	history := store.History("cc1", "a")
+	require.Len(t, history, 2)
+	assert.Equal(t, "tx1", history[0].TxID)
+	assert.Equal(t, uint64(0), history[0].BlockNum)
+	assert.Equal(t, "tx3", history[1].TxID)
+	assert.Equal(t, uint64(1), history[1].BlockNum)
+	assert.Equal(t, int64(1000), history[1].Timestamp.Unix())
+
+	assert.Equal(t, []string{"a"}, store.Keys("cc1"))
+	assert.Equal(t, []uint64{0, 1}, store.blocks)
+
+	assert.Error(t, r.Apply(&cb.Block{}), "expecting error for invalid block")
+
+	_, err = New(nil)
+	assert.Error(t, err, "expecting error for missing store")
+}
+
+type purgingStore struct {
+	*MemoryStore
+	purges []*PrivateDataPurge
+}
+
+func (s *purgingStore) Purge(purge *PrivateDataPurge) error {
+	s.purges = append(s.purges, purge)
+	return nil
+}
+
+func TestReplayPurges(t *testing.T) {
+	store := &purgingStore{MemoryStore: NewMemoryStore()}
+	r, err := New(store, WithNamespaces("cc1"))
+	require.NoError(t, err)
+
+	hashedRWSets := []*rwsetutil.CollHashedRwSet{{
+		CollectionName: "coll1",
+		HashedRwSet: &kvrwset.HashedRWSet{HashedWrites: []*kvrwset.KVWriteHash{
+			{KeyHash: []byte("hash1"), ValueHash: []byte("value")},
+			{KeyHash: []byte("hash2"), IsDelete: true, IsPurge: true},
+		}},
+		PvtRwSetHash: []byte("pvthash"),
+	}}
+
+	block := newBlock(t, 3,
+		tx{id: "tx1", code: pb.TxValidationCode_VALID,
+			writes: map[string][]*kvrwset.KVWrite{"cc1": {{Key: "a", Value: []byte("1")}}, "cc2": {}},
+			hashed: map[string][]*rwsetutil.CollHashedRwSet{"cc1": hashedRWSets, "cc2": hashedRWSets},
+		},
+		tx{id: "tx2", code: pb.TxValidationCode_MVCC_READ_CONFLICT,
+			writes: map[string][]*kvrwset.KVWrite{"cc1": {}},
+			hashed: map[string][]*rwsetutil.CollHashedRwSet{"cc1": hashedRWSets},
+		},
+	)
+	require.NoError(t, r.Apply(block))
+
+	require.Len(t, store.purges, 1, "expecting purges of invalid transactions and filtered namespaces to be skipped")
+	purge := store.purges[0]
+	assert.Equal(t, "cc1", purge.Namespace)
+	assert.Equal(t, "coll1", purge.Collection)
+	assert.Equal(t, []byte("hash2"), purge.KeyHash)
+	assert.Equal(t, uint64(3), purge.BlockNum)
+	assert.Equal(t, uint64(0), purge.TxNum)
+	assert.Equal(t, "tx1", purge.TxID)
+
+	value, ok := store.State("cc1", "a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+}
+
+func newBlock(t *testing.T, blockNum uint64, txs ...tx) *cb.Block {
+	block := &cb.Block{
+		Header:   &cb.BlockHeader{Number: blockNum},
+		Data:     &cb.BlockData{},
+		Metadata: &cb.BlockMetadata{Metadata: make([][]byte, len(cb.BlockMetadataIndex_name))},
+	}
+
+	txFilter := ledgerutil.NewTxValidationFlags(len(txs))
+	for i, tx := range txs {
+		block.Data.Data = append(block.Data.Data, newEnvelopeBytes(t, tx))
+		txFilter[i] = uint8(tx.code)
+	}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = txFilter
+
+	return block
+}
+
+func newEnvelopeBytes(t *testing.T, tx tx) []byte {
+	txRWSet := &rwsetutil.TxRwSet{}
+	for ns, writes := range tx.writes {
+		txRWSet.NsRwSets = append(txRWSet.NsRwSets, &rwsetutil.NsRwSet{NameSpace: ns, KvRwSet: &kvrwset.KVRWSet{Writes: writes}, CollHashedRwSets: tx.hashed[ns]})
+	}
+	results, err := txRWSet.ToProtoBytes()
+	require.NoError(t, err)
+
+	prp, err := utils.GetBytesProposalResponsePayload([]byte("hash"), &pb.Response{Status: 200}, results, nil, nil)
+	require.NoError(t, err)
+
+	ccActionPayload, err := utils.GetBytesChaincodeActionPayload(&pb.ChaincodeActionPayload{Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: prp}})
+	require.NoError(t, err)
+
+	txBytes, err := utils.GetBytesTransaction(&pb.Transaction{Actions: []*pb.TransactionAction{{Payload: ccActionPayload}}})
+	require.NoError(t, err)
+
+	chdr := &cb.ChannelHeader{
+		Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+		ChannelId: "mychannel",
+		TxId:      tx.id,
+		Timestamp: &timestamp.Timestamp{Seconds: 1000},
+	}
+	payload, err := utils.GetBytesPayload(&cb.Payload{Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(chdr)}, Data: txBytes})
+	require.NoError(t, err)
+
+	env, err := utils.GetBytesEnvelope(&cb.Envelope{Payload: payload})
+	require.NoError(t, err)
+	return env
+}
@@ -0,0 +1,370 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package webhook posts the commit status of transactions to webhook URLs. The notifier registers for
+// filtered block events and, for each transaction in a block, posts a JSON notification containing the
+// transaction ID, the validation code and the block number to each of the configured endpoints.
+//
+// If an endpoint has a secret then the notification is signed with HMAC-SHA256 and the hex encoded
+// signature is sent in the X-Fabric-Signature header (as "sha256=<signature>") so that the receiver is
+// able to verify that the notification was sent by the notifier. Failed deliveries (connection errors,
+// status 429 and 5xx) are retried with exponential backoff.
+//
+//  Basic Flow:
+//  1) Create an event client for the channel
+//  2) Create a notifier with the event client and the endpoints and start it
+//  3) Verify the signature of the notifications at the receiver with VerifySignature
+package webhook
+
+import (
+	"bytes"
+	reqContext "context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const (
+	// SignatureHeader is the HTTP header which contains the signature of a notification
+	SignatureHeader = "X-Fabric-Signature"
+
+	signaturePrefix = "sha256="
+	defaultTimeout  = 10 * time.Second
+)
+
+// DefaultRetryOpts are the retry options used for delivering notifications unless WithRetry is specified
+var DefaultRetryOpts = retry.Opts{
+	Attempts:       5,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+	BackoffFactor:  2.0,
+	RetryableCodes: RetryableCodes,
+}
+
+// RetryableCodes are the delivery failures which are retried
+var RetryableCodes = map[status.Group][]status.Code{
+	status.ClientStatus: {status.ConnectionFailed},
+	status.HTTPTransportStatus: {
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// FilteredBlockSource is the subset of the event client used by the notifier
+type FilteredBlockSource interface {
+	RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error)
+	Unregister(reg fab.Registration)
+}
+
+// Notification is the commit status of a transaction which is posted to the webhooks
+type Notification struct {
+	ChannelID      string `json:"channelId"`
+	TxID           string `json:"txId"`
+	ValidationCode string `json:"validationCode"`
+	Valid          bool   `json:"valid"`
+	BlockNumber    uint64 `json:"blockNumber"`
+}
+
+// Endpoint is a webhook URL along with the secret used for signing the notifications (optional)
+type Endpoint struct {
+	URL    string
+	Secret []byte
+}
+
+// ErrorHandler is invoked when a notification couldn't be delivered to an endpoint after all retries
+type ErrorHandler func(n *Notification, url string, err error)
+
+// Notifier posts the commit status of transactions to webhooks
+type Notifier struct {
+	source     FilteredBlockSource
+	endpoints  []Endpoint
+	client     *http.Client
+	retryOpts  retry.Opts
+	txFilter   func(n *Notification) bool
+	errHandler ErrorHandler
+
+	mutex   sync.Mutex
+	reg     fab.Registration
+	ctx     reqContext.Context
+	cancel  reqContext.CancelFunc
+	stopped chan struct{}
+}
+
+// Option is a functional option for the notifier
+type Option func(n *Notifier)
+
+// WithEndpoint adds a webhook URL to which the notifications are posted. If the secret isn't empty then
+// the notifications are signed with it.
+func WithEndpoint(url string, secret []byte) Option {
+	return func(n *Notifier) {
+		n.endpoints = append(n.endpoints, Endpoint{URL: url, Secret: secret})
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for posting the notifications (default: a client with a 10s timeout)
+func WithHTTPClient(client *http.Client) Option {
+	return func(n *Notifier) {
+		n.client = client
+	}
+}
+
+// WithRetry sets the retry options for delivering notifications (default DefaultRetryOpts). If no
+// retryable codes are specified then RetryableCodes are used.
+func WithRetry(opts retry.Opts) Option {
+	return func(n *Notifier) {
+		n.retryOpts = opts
+	}
+}
+
+// WithTxFilter sets a filter which selects the transactions for which notifications are posted
+// (e.g. only invalid transactions)
+func WithTxFilter(filter func(n *Notification) bool) Option {
+	return func(n *Notifier) {
+		n.txFilter = filter
+	}
+}
+
+// WithErrorHandler sets the handler which is invoked for notifications which couldn't be delivered
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(n *Notifier) {
+		n.errHandler = handler
+	}
+}
+
+// New returns a notifier which posts the commit status of the transactions received from the given source
+func New(source FilteredBlockSource, opts ...Option) (*Notifier, error) {
+	if source == nil {
+		return nil, errors.New("filtered block source is required")
+	}
+
+	n := &Notifier{
+		source:    source,
+		client:    &http.Client{Timeout: defaultTimeout},
+		retryOpts: DefaultRetryOpts,
+		ctx:       reqContext.Background(),
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	if len(n.endpoints) == 0 {
+		return nil, errors.New("at least one endpoint is required")
+	}
+	for _, endpoint := range n.endpoints {
+		if !strings.HasPrefix(endpoint.URL, "http://") && !strings.HasPrefix(endpoint.URL, "https://") {
+			return nil, errors.Errorf("invalid webhook URL [%s]", endpoint.URL)
+		}
+	}
+	if len(n.retryOpts.RetryableCodes) == 0 {
+		n.retryOpts.RetryableCodes = RetryableCodes
+	}
+
+	return n, nil
+}
+
+// Start registers for filtered block events and posts the notifications in the background
+func (n *Notifier) Start() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if n.stopped != nil {
+		return errors.New("notifier already started")
+	}
+
+	reg, eventch, err := n.source.RegisterFilteredBlockEvent()
+	if err != nil {
+		return errors.WithMessage(err, "failed to register for filtered block events")
+	}
+
+	n.reg = reg
+	n.ctx, n.cancel = reqContext.WithCancel(reqContext.Background())
+	n.stopped = make(chan struct{})
+
+	go n.run(n.ctx, eventch)
+
+	return nil
+}
+
+// Stop stops posting notifications (pending retries are aborted) and unregisters from block events
+func (n *Notifier) Stop() {
+	n.mutex.Lock()
+	cancel, stopped := n.cancel, n.stopped
+	n.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	if stopped != nil {
+		<-stopped
+	}
+}
+
+// Notify posts the notification to all endpoints. It may be used for posting the commit status of a
+// transaction which is known to the application (e.g. from the response of the channel client).
+func (n *Notifier) Notify(notification *Notification) error {
+	n.mutex.Lock()
+	ctx := n.ctx
+	n.mutex.Unlock()
+
+	return n.notify(ctx, notification)
+}
+
+func (n *Notifier) run(ctx reqContext.Context, eventch <-chan *fab.FilteredBlockEvent) {
+	defer close(n.stopped)
+	defer n.source.Unregister(n.reg)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Debugf("Stopping webhook notifier")
+			return
+		case e, ok := <-eventch:
+			if !ok {
+				logger.Debugf("Filtered block event channel closed - stopping webhook notifier")
+				return
+			}
+			n.process(ctx, e.FilteredBlock)
+		}
+	}
+}
+
+func (n *Notifier) process(ctx reqContext.Context, block *pb.FilteredBlock) {
+	if block == nil {
+		return
+	}
+
+	for _, tx := range block.FilteredTransactions {
+		notification := &Notification{
+			ChannelID:      block.ChannelId,
+			TxID:           tx.Txid,
+			ValidationCode: tx.TxValidationCode.String(),
+			Valid:          tx.TxValidationCode == pb.TxValidationCode_VALID,
+			BlockNumber:    block.Number,
+		}
+		if n.txFilter != nil && !n.txFilter(notification) {
+			continue
+		}
+		if err := n.notify(ctx, notification); err != nil {
+			logger.Warnf("Error posting commit status of transaction [%s]: %s", tx.Txid, err)
+		}
+	}
+}
+
+func (n *Notifier) notify(ctx reqContext.Context, notification *Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal notification")
+	}
+
+	var failed []string
+	for _, endpoint := range n.endpoints {
+		if err := n.deliver(ctx, endpoint, body); err != nil {
+			if n.errHandler != nil {
+				n.errHandler(notification, endpoint.URL, err)
+			}
+			failed = append(failed, fmt.Sprintf("%s: %s", endpoint.URL, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("failed to deliver notification to endpoints: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+func (n *Notifier) deliver(ctx reqContext.Context, endpoint Endpoint, body []byte) error {
+	retryHandler := retry.New(n.retryOpts).(retry.ContextHandler)
+
+	for {
+		err := n.post(ctx, endpoint, body)
+		if err == nil {
+			return nil
+		}
+
+		required, retryErr := retryHandler.RequiredWithContext(ctx, err)
+		if !required {
+			if retryErr != nil {
+				return retryErr
+			}
+			return err
+		}
+		logger.Debugf("Retrying delivery to [%s]: %s", endpoint.URL, err)
+	}
+}
+
+func (n *Notifier) post(ctx reqContext.Context, endpoint Endpoint, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if len(endpoint.Secret) > 0 {
+		req.Header.Set(SignatureHeader, signaturePrefix+Sign(endpoint.Secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return status.New(status.ClientStatus, status.ConnectionFailed.ToInt32(), err.Error(), nil)
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so that the connection may be reused
+	if _, err := io.Copy(ioutil.Discard, resp.Body); err != nil {
+		logger.Debugf("Error reading response from [%s]: %s", endpoint.URL, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return status.New(status.HTTPTransportStatus, int32(resp.StatusCode), resp.Status, nil)
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the body
+func Sign(secret, body []byte) string {
+	return hex.EncodeToString(mac(secret, body))
+}
+
+func mac(secret, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write(body) // nolint: errcheck, gas
+	return h.Sum(nil)
+}
+
+// VerifySignature verifies the value of the signature header of a notification against the body
+func VerifySignature(secret, body []byte, signature string) error {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return errors.New("unsupported signature")
+	}
+	actual, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil {
+		return errors.Wrap(err, "invalid signature encoding")
+	}
+	if !hmac.Equal(mac(secret, body), actual) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
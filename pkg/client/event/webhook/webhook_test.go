@@ -0,0 +1,167 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryOpts = retry.Opts{
+	Attempts:       3,
+	InitialBackoff: 10 * time.Millisecond,
+	MaxBackoff:     50 * time.Millisecond,
+	BackoffFactor:  2.0,
+}
+
+type mockSource struct {
+	eventch chan *fab.FilteredBlockEvent
+}
+
+func newMockSource() *mockSource {
+	return &mockSource{eventch: make(chan *fab.FilteredBlockEvent, 10)}
+}
+
+func (s *mockSource) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	return s.eventch, s.eventch, nil
+}
+
+func (s *mockSource) Unregister(reg fab.Registration) {}
+
+type receiver struct {
+	mutex         sync.Mutex
+	secret        []byte
+	failures      int
+	notifications []*Notification
+	sigErrs       []error
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.secret != nil {
+		r.sigErrs = append(r.sigErrs, VerifySignature(r.secret, body, req.Header.Get(SignatureHeader)))
+	}
+
+	n := &Notification{}
+	if err := json.Unmarshal(body, n); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.notifications = append(r.notifications, n)
+}
+
+func (r *receiver) received() []*Notification {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]*Notification{}, r.notifications...)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil, WithEndpoint("http://localhost/hook", nil))
+	assert.Error(t, err, "expecting error for missing source")
+	_, err = New(newMockSource())
+	assert.Error(t, err, "expecting error for missing endpoint")
+	_, err = New(newMockSource(), WithEndpoint("localhost/hook", nil))
+	assert.Error(t, err, "expecting error for invalid URL")
+
+	n, err := New(newMockSource(), WithEndpoint("https://localhost/hook", nil), WithRetry(retry.Opts{Attempts: 1}))
+	require.NoError(t, err)
+	assert.Equal(t, RetryableCodes, n.retryOpts.RetryableCodes)
+}
+
+func TestNotifier(t *testing.T) {
+	r := &receiver{secret: []byte("secret"), failures: 2}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	source := newMockSource()
+	n, err := New(source, WithEndpoint(server.URL, []byte("secret")), WithRetry(testRetryOpts),
+		WithTxFilter(func(n *Notification) bool { return n.TxID != "tx3" }))
+	require.NoError(t, err)
+	require.NoError(t, n.Start())
+	defer n.Stop()
+	assert.Error(t, n.Start(), "expecting error since notifier is already started")
+
+	source.eventch <- &fab.FilteredBlockEvent{FilteredBlock: &pb.FilteredBlock{
+		ChannelId: "mychannel",
+		Number:    7,
+		FilteredTransactions: []*pb.FilteredTransaction{
+			{Txid: "tx1", TxValidationCode: pb.TxValidationCode_VALID},
+			{Txid: "tx2", TxValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT},
+			{Txid: "tx3", TxValidationCode: pb.TxValidationCode_VALID},
+		},
+	}}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(r.received()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	received := r.received()
+	require.Len(t, received, 2, "expecting notifications to be delivered after retries")
+	assert.Equal(t, &Notification{ChannelID: "mychannel", TxID: "tx1", ValidationCode: "VALID", Valid: true, BlockNumber: 7}, received[0])
+	assert.Equal(t, &Notification{ChannelID: "mychannel", TxID: "tx2", ValidationCode: "MVCC_READ_CONFLICT", BlockNumber: 7}, received[1])
+
+	r.mutex.Lock()
+	for _, err := range r.sigErrs {
+		assert.NoError(t, err)
+	}
+	r.mutex.Unlock()
+}
+
+func TestNotifyFailure(t *testing.T) {
+	r := &receiver{failures: 10}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	var failed []string
+	n, err := New(newMockSource(), WithEndpoint(server.URL, nil), WithRetry(testRetryOpts),
+		WithErrorHandler(func(n *Notification, url string, err error) { failed = append(failed, n.TxID) }))
+	require.NoError(t, err)
+
+	assert.Error(t, n.Notify(&Notification{TxID: "tx1"}))
+	assert.Equal(t, []string{"tx1"}, failed)
+	assert.Equal(t, 6, r.failures, "expecting initial attempt plus 3 retries")
+
+	server.Close()
+	assert.Error(t, n.Notify(&Notification{TxID: "tx2"}), "expecting error for unreachable endpoint")
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"txId":"tx1"}`)
+	signature := signaturePrefix + Sign([]byte("secret"), body)
+
+	assert.NoError(t, VerifySignature([]byte("secret"), body, signature))
+	assert.Error(t, VerifySignature([]byte("other"), body, signature))
+	assert.Error(t, VerifySignature([]byte("secret"), []byte(`{"txId":"tx2"}`), signature))
+	assert.Error(t, VerifySignature([]byte("secret"), body, Sign([]byte("secret"), body)), "expecting error for missing prefix")
+	assert.Error(t, VerifySignature([]byte("secret"), body, signaturePrefix+"zz"))
+}
@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identitymapper
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
+)
+
+// DefaultCacheTimeout is the default time a mapping is cached
+const DefaultCacheTimeout = 5 * time.Minute
+
+// CachedMapper caches the mappings of principals
+type CachedMapper struct {
+	cache *lazycache.Cache
+}
+
+// Cached returns a mapper that caches the mappings of the given mapper by issuer and subject.
+// A mapping expires after the timeout (DefaultCacheTimeout if zero) and is then mapped again with
+// the claims of the latest principal; failed mappings aren't cached.
+func Cached(mapper Mapper, timeout time.Duration) *CachedMapper {
+	if timeout == 0 {
+		timeout = DefaultCacheTimeout
+	}
+
+	return &CachedMapper{
+		cache: lazycache.NewWithData("Identity_Mapping_Cache",
+			func(key lazycache.Key, data interface{}) (interface{}, error) {
+				return mapper.Map(data.(*Principal))
+			},
+			lazyref.WithAbsoluteExpiration(timeout),
+		),
+	}
+}
+
+// Map returns the cached mapping of the principal
+func (m *CachedMapper) Map(principal *Principal) (*Mapping, error) {
+	value, err := m.cache.Get(lazycache.NewStringKey(principal.key()), principal)
+	if err != nil {
+		return nil, err
+	}
+	return value.(*Mapping), nil
+}
+
+// Invalidate removes the mapping of the principal from the cache, e.g. when the user logs out
+func (m *CachedMapper) Invalidate(principal *Principal) {
+	m.cache.Delete(lazycache.NewStringKey(principal.key()))
+}
+
+// Close removes all mappings from the cache
+func (m *CachedMapper) Close() {
+	m.cache.Close()
+}
@@ -0,0 +1,174 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package identitymapper maps application-level principals (e.g. the subject of a validated JWT or
+// OAuth token) to Fabric signing identities, so that API gateways serving many users or tenants can
+// submit requests with the right identity. A principal is mapped either to the identity of the user
+// of the same name (per-user) or to a shared org identity, in which case the principal is identified
+// by attributes to be passed to the chaincode (e.g. as transient data).
+//
+//  Basic Flow:
+//  1) Create a mapper (PerUser, Shared or a custom MapperFunc), optionally combined with Chain and Cached
+//  2) Map the principal of each request, e.g. with the HTTP middleware
+//  3) Create the SDK context with the identity of the mapping (fabsdk.WithIdentity)
+package identitymapper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+)
+
+// AttributePrefix prefixes the names of the attributes tagging a mapping to a shared identity
+const AttributePrefix = "principal."
+
+// Principal is an authenticated application-level user
+type Principal struct {
+	// Subject identifies the user at the issuer (JWT "sub" claim)
+	Subject string
+	// Issuer is the identity provider (JWT "iss" claim)
+	Issuer string
+	// Claims are the validated claims of the token
+	Claims map[string]interface{}
+}
+
+// NewPrincipal returns the principal of validated JWT claims, which must include the "sub" claim
+func NewPrincipal(claims map[string]interface{}) (*Principal, error) {
+	subject, ok := claims["sub"].(string)
+	if !ok || subject == "" {
+		return nil, errors.New("subject claim is required")
+	}
+	issuer, _ := claims["iss"].(string)
+	return &Principal{Subject: subject, Issuer: issuer, Claims: claims}, nil
+}
+
+// key identifies the principal across issuers
+func (p *Principal) key() string {
+	return p.Issuer + "|" + p.Subject
+}
+
+// Mapping is the Fabric identity of a principal
+type Mapping struct {
+	Identity msp.SigningIdentity
+	// Attributes identify the principal when the identity is shared
+	Attributes map[string]string
+}
+
+// TransientMap returns the attributes as transient data of a chaincode request
+func (m *Mapping) TransientMap() map[string][]byte {
+	if len(m.Attributes) == 0 {
+		return nil
+	}
+	transient := make(map[string][]byte)
+	for name, value := range m.Attributes {
+		transient[name] = []byte(value)
+	}
+	return transient
+}
+
+// Mapper maps a principal to a Fabric identity. It returns msp.ErrUserNotFound if
+// the principal has no identity.
+type Mapper interface {
+	Map(principal *Principal) (*Mapping, error)
+}
+
+// MapperFunc is a function implementing Mapper
+type MapperFunc func(principal *Principal) (*Mapping, error)
+
+// Map maps the principal
+func (f MapperFunc) Map(principal *Principal) (*Mapping, error) {
+	return f(principal)
+}
+
+// IdentityProvider returns the signing identity of a user (e.g. the identity manager of an org or the msp client)
+type IdentityProvider interface {
+	GetSigningIdentity(id string) (msp.SigningIdentity, error)
+}
+
+// UserNameFunc returns the Fabric user name of a principal
+type UserNameFunc func(principal *Principal) (string, error)
+
+// PerUser maps each principal to the identity of the Fabric user named by the user name function
+// (by default the subject of the principal)
+func PerUser(provider IdentityProvider, userName UserNameFunc) Mapper {
+	if userName == nil {
+		userName = func(principal *Principal) (string, error) {
+			return principal.Subject, nil
+		}
+	}
+
+	return MapperFunc(func(principal *Principal) (*Mapping, error) {
+		name, err := userName(principal)
+		if err != nil {
+			return nil, err
+		}
+		identity, err := provider.GetSigningIdentity(name)
+		if err != nil {
+			if err == msp.ErrUserNotFound {
+				return nil, err
+			}
+			return nil, errors.WithMessage(err, "failed to get signing identity of user ["+name+"]")
+		}
+		return &Mapping{Identity: identity}, nil
+	})
+}
+
+// Shared maps all principals to the shared identity. The mapping is tagged with the attributes
+// principal.sub, principal.iss and principal.<claim> for each of the given claims of the principal.
+func Shared(identity msp.SigningIdentity, claims ...string) Mapper {
+	return MapperFunc(func(principal *Principal) (*Mapping, error) {
+		attributes := map[string]string{AttributePrefix + "sub": principal.Subject}
+		if principal.Issuer != "" {
+			attributes[AttributePrefix+"iss"] = principal.Issuer
+		}
+		for _, claim := range claims {
+			if value, ok := claimValue(principal, claim); ok {
+				attributes[AttributePrefix+claim] = value
+			}
+		}
+		return &Mapping{Identity: identity, Attributes: attributes}, nil
+	})
+}
+
+// Chain returns a mapper that tries the mappers in order until one of them finds an identity,
+// e.g. a per-user mapper falling back to a shared identity
+func Chain(mappers ...Mapper) Mapper {
+	return MapperFunc(func(principal *Principal) (*Mapping, error) {
+		for _, mapper := range mappers {
+			mapping, err := mapper.Map(principal)
+			if err != msp.ErrUserNotFound {
+				return mapping, err
+			}
+		}
+		return nil, msp.ErrUserNotFound
+	})
+}
+
+// claimValue returns string, boolean and numeric claims, and lists of strings joined with commas
+func claimValue(principal *Principal, claim string) (string, bool) {
+	switch v := principal.Claims[claim].(type) {
+	case string:
+		return v, true
+	case bool, float64, int, int64:
+		return fmt.Sprint(v), true
+	case []string:
+		return strings.Join(v, ","), true
+	case []interface{}:
+		var values []string
+		for _, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				return "", false
+			}
+			values = append(values, s)
+		}
+		return strings.Join(values, ","), true
+	default:
+		return "", false
+	}
+}
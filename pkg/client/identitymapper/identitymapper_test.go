@@ -0,0 +1,196 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identitymapper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPrincipal(t *testing.T, subject string) *Principal {
+	principal, err := NewPrincipal(map[string]interface{}{
+		"sub":    subject,
+		"iss":    "https://idp.example.com",
+		"tenant": "acme",
+		"roles":  []interface{}{"reader", "writer"},
+		"admin":  false,
+	})
+	require.NoError(t, err)
+	return principal
+}
+
+func TestNewPrincipal(t *testing.T) {
+	principal := newTestPrincipal(t, "user1")
+	assert.Equal(t, "user1", principal.Subject)
+	assert.Equal(t, "https://idp.example.com", principal.Issuer)
+
+	_, err := NewPrincipal(map[string]interface{}{"iss": "https://idp.example.com"})
+	assert.Error(t, err, "expecting error for missing subject")
+}
+
+func TestPerUser(t *testing.T) {
+	mgr := fcmocks.NewMockIdentityManager(fcmocks.WithUser("user1", "Org1MSP"))
+
+	mapping, err := PerUser(mgr, nil).Map(newTestPrincipal(t, "user1"))
+	require.NoError(t, err)
+	assert.Equal(t, "user1", mapping.Identity.Identifier().ID)
+	assert.Nil(t, mapping.TransientMap())
+
+	_, err = PerUser(mgr, nil).Map(newTestPrincipal(t, "user2"))
+	assert.Equal(t, msp.ErrUserNotFound, err)
+
+	byTenant := PerUser(mgr, func(principal *Principal) (string, error) {
+		return strings.Replace(principal.Subject, "@acme", "", 1), nil
+	})
+	mapping, err = byTenant.Map(newTestPrincipal(t, "user1@acme"))
+	require.NoError(t, err)
+	assert.Equal(t, "user1", mapping.Identity.Identifier().ID)
+}
+
+func TestShared(t *testing.T) {
+	identity := mspmocks.NewMockSigningIdentity("gateway", "Org1MSP")
+
+	mapping, err := Shared(identity, "tenant", "roles", "admin", "missing").Map(newTestPrincipal(t, "user1"))
+	require.NoError(t, err)
+	assert.Equal(t, identity, mapping.Identity)
+	assert.Equal(t, map[string]string{
+		"principal.sub":    "user1",
+		"principal.iss":    "https://idp.example.com",
+		"principal.tenant": "acme",
+		"principal.roles":  "reader,writer",
+		"principal.admin":  "false",
+	}, mapping.Attributes)
+	assert.Equal(t, []byte("acme"), mapping.TransientMap()["principal.tenant"])
+}
+
+func TestChain(t *testing.T) {
+	mgr := fcmocks.NewMockIdentityManager(fcmocks.WithUser("user1", "Org1MSP"))
+	mapper := Chain(PerUser(mgr, nil), Shared(mspmocks.NewMockSigningIdentity("gateway", "Org1MSP")))
+
+	mapping, err := mapper.Map(newTestPrincipal(t, "user1"))
+	require.NoError(t, err)
+	assert.Equal(t, "user1", mapping.Identity.Identifier().ID)
+
+	mapping, err = mapper.Map(newTestPrincipal(t, "user2"))
+	require.NoError(t, err)
+	assert.Equal(t, "gateway", mapping.Identity.Identifier().ID)
+	assert.Equal(t, "user2", mapping.Attributes["principal.sub"])
+
+	_, err = Chain(PerUser(mgr, nil)).Map(newTestPrincipal(t, "user2"))
+	assert.Equal(t, msp.ErrUserNotFound, err)
+
+	failing := MapperFunc(func(principal *Principal) (*Mapping, error) {
+		return nil, errors.New("directory unavailable")
+	})
+	_, err = Chain(failing, PerUser(mgr, nil)).Map(newTestPrincipal(t, "user1"))
+	assert.EqualError(t, err, "directory unavailable", "expecting errors other than not found to stop the chain")
+}
+
+func TestCached(t *testing.T) {
+	var calls int32
+	mapper := Cached(MapperFunc(func(principal *Principal) (*Mapping, error) {
+		atomic.AddInt32(&calls, 1)
+		if principal.Subject == "unknown" {
+			return nil, msp.ErrUserNotFound
+		}
+		return &Mapping{Identity: mspmocks.NewMockSigningIdentity(principal.Subject, "Org1MSP")}, nil
+	}), 100*time.Millisecond)
+	defer mapper.Close()
+
+	for i := 0; i < 3; i++ {
+		mapping, err := mapper.Map(newTestPrincipal(t, "user1"))
+		require.NoError(t, err)
+		assert.Equal(t, "user1", mapping.Identity.Identifier().ID)
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+
+	_, err := mapper.Map(newTestPrincipal(t, "user2"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+
+	other := newTestPrincipal(t, "user1")
+	other.Issuer = "https://other.example.com"
+	_, err = mapper.Map(other)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls), "expecting principals to be cached by issuer")
+
+	for i := 0; i < 2; i++ {
+		_, err = mapper.Map(newTestPrincipal(t, "unknown"))
+		assert.Equal(t, msp.ErrUserNotFound, err)
+	}
+	assert.EqualValues(t, 5, atomic.LoadInt32(&calls), "expecting failed mappings not to be cached")
+
+	mapper.Invalidate(newTestPrincipal(t, "user1"))
+	_, err = mapper.Map(newTestPrincipal(t, "user1"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 6, atomic.LoadInt32(&calls))
+
+	time.Sleep(200 * time.Millisecond)
+	_, err = mapper.Map(newTestPrincipal(t, "user1"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, atomic.LoadInt32(&calls), "expecting mapping to expire")
+}
+
+func TestMiddleware(t *testing.T) {
+	mgr := fcmocks.NewMockIdentityManager(fcmocks.WithUser("user1", "Org1MSP"))
+	authenticate := func(r *http.Request) (*Principal, error) {
+		subject := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subject == "" {
+			return nil, errors.New("missing bearer token")
+		}
+		return &Principal{Subject: subject}, nil
+	}
+	mapper := Chain(PerUser(mgr, nil), MapperFunc(func(principal *Principal) (*Mapping, error) {
+		if principal.Subject == "broken" {
+			return nil, errors.New("directory unavailable")
+		}
+		return nil, msp.ErrUserNotFound
+	}))
+
+	handler := Middleware(authenticate, mapper)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mapping, ok := FromContext(r.Context())
+		require.True(t, ok)
+		w.Write([]byte(mapping.Identity.Identifier().ID)) // nolint: errcheck
+	}))
+
+	tests := []struct {
+		token  string
+		status int
+		body   string
+	}{
+		{"user1", http.StatusOK, "user1"},
+		{"", http.StatusUnauthorized, ""},
+		{"user2", http.StatusForbidden, ""},
+		{"broken", http.StatusInternalServerError, ""},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if test.token != "" {
+			r.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		assert.Equal(t, test.status, w.Code, test.token)
+		if test.status == http.StatusOK {
+			assert.Equal(t, test.body, w.Body.String())
+		}
+	}
+
+	_, ok := FromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context())
+	assert.False(t, ok)
+}
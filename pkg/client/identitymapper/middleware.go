@@ -0,0 +1,64 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identitymapper
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+type contextKey struct{}
+
+// Authenticator returns the principal of an HTTP request, e.g. the subject and claims of a
+// bearer token validated by the application's JWT library
+type Authenticator func(r *http.Request) (*Principal, error)
+
+// NewContext returns a context holding the mapping
+func NewContext(ctx context.Context, mapping *Mapping) context.Context {
+	return context.WithValue(ctx, contextKey{}, mapping)
+}
+
+// FromContext returns the mapping held by the context
+func FromContext(ctx context.Context) (*Mapping, bool) {
+	mapping, ok := ctx.Value(contextKey{}).(*Mapping)
+	return mapping, ok
+}
+
+// Middleware returns HTTP middleware that authenticates each request and adds the mapping of its
+// principal to the request context (see FromContext). Requests failing authentication are rejected
+// with 401 Unauthorized, and requests of principals without an identity with 403 Forbidden.
+func Middleware(authenticate Authenticator, mapper Mapper) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := authenticate(r)
+			if err != nil {
+				logger.Debugf("Authentication failed: %s", err)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			mapping, err := mapper.Map(principal)
+			if err != nil {
+				if err == msp.ErrUserNotFound {
+					logger.Debugf("No identity for principal [%s] of issuer [%s]", principal.Subject, principal.Issuer)
+					http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+					return
+				}
+				logger.Errorf("Failed to map principal [%s] of issuer [%s]: %s", principal.Subject, principal.Issuer, err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), mapping)))
+		})
+	}
+}
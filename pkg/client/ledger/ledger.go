@@ -7,7 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 // Package ledger enables ledger queries on specified channel on a Fabric network.
 // An application that requires ledger queries from multiple channels should create a separate
 // instance of the ledger client for each channel. Ledger client supports the following queries:
-// QueryInfo, QueryBlock, QueryBlockByHash,  QueryBlockByTxID, QueryTransaction and QueryConfig.
+// QueryInfo, QueryBlock, QueryBlockByHash,  QueryBlockByTxID, QueryTransaction, QueryConfig and QueryMissingPrivateData.
 //
 //  Basic Flow:
 //  1) Prepare channel context
@@ -17,11 +17,11 @@ package ledger
 
 import (
 	reqContext "context"
-	"math/rand"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/blockcache"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
@@ -30,11 +30,13 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/pvtdata"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/random"
 	"github.com/pkg/errors"
 )
 
@@ -45,6 +47,7 @@ type Client struct {
 	ledger    *channel.Ledger
 	verifier  channel.ResponseVerifier
 	discovery fab.DiscoveryService
+	cache     blockcache.Cache
 }
 
 // mspFilter is default filter
@@ -57,6 +60,11 @@ func (f *mspFilter) Accept(peer fab.Peer) bool {
 	return peer.MSPID() == f.mspID
 }
 
+func isObserver(ctx context.Channel) bool {
+	chSdkCfg, ok := ctx.EndpointConfig().ChannelConfig(ctx.ChannelID())
+	return ok && chSdkCfg.Policies.Observer.Enabled
+}
+
 // New returns a ledger client instance. A ledger client instance provides a handler to query various info on specified channel.
 // An application that requires interaction with multiple channels should create a separate
 // instance of the ledger client for each channel. Ledger client supports specific queries only.
@@ -105,8 +113,9 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 		}
 	}
 
-	// check if target filter was set - if not set the default
-	if ledgerClient.filter == nil {
+	// check if target filter was set - if not set the default (an observer's organization has
+	// no peers in the channel so all of the ledger query peers are used)
+	if ledgerClient.filter == nil && !isObserver(channelContext) {
 		// Default target filter is based on user msp
 		if channelContext.Identifier().MSPID == "" {
 			return nil, errors.New("mspID not available in user context")
@@ -181,7 +190,7 @@ func (c *Client) QueryBlockByHash(blockHash []byte, options ...RequestOption) (*
 		return nil, errors.WithMessage(err, "QueryBlockByHash failed")
 	}
 
-	return matchBlockData(responses, opts.MinTargets)
+	return c.cacheBlock(matchBlockData(responses, opts.MinTargets))
 }
 
 // QueryBlockByTxID queries for block which contains a transaction.
@@ -205,10 +214,11 @@ func (c *Client) QueryBlockByTxID(txID fab.TransactionID, options ...RequestOpti
 		return nil, errors.WithMessage(err, "QueryBlockByTxID failed")
 	}
 
-	return matchBlockData(responses, opts.MinTargets)
+	return c.cacheBlock(matchBlockData(responses, opts.MinTargets))
 }
 
-// QueryBlock queries the ledger for Block by block number.
+// QueryBlock queries the ledger for Block by block number. If the client was created with a block cache
+// then the block is returned from the cache, if present, without querying the peers.
 //  Parameters:
 //  blockNumber is required block number(ID)
 //  options hold optional request options
@@ -217,6 +227,12 @@ func (c *Client) QueryBlockByTxID(txID fab.TransactionID, options ...RequestOpti
 //  block information
 func (c *Client) QueryBlock(blockNumber uint64, options ...RequestOption) (*common.Block, error) {
 
+	if c.cache != nil {
+		if block := c.cache.Get(c.ctx.ChannelID(), blockNumber); block != nil {
+			return block, nil
+		}
+	}
+
 	targets, opts, err := c.prepareRequestParams(options...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "QueryBlock failed to prepare request parameters")
@@ -229,7 +245,15 @@ func (c *Client) QueryBlock(blockNumber uint64, options ...RequestOption) (*comm
 		return nil, errors.WithMessage(err, "QueryBlock failed")
 	}
 
-	return matchBlockData(responses, opts.MinTargets)
+	return c.cacheBlock(matchBlockData(responses, opts.MinTargets))
+}
+
+// cacheBlock adds the block to the block cache (if the client has one)
+func (c *Client) cacheBlock(block *common.Block, err error) (*common.Block, error) {
+	if err == nil && c.cache != nil {
+		c.cache.Put(c.ctx.ChannelID(), block)
+	}
+	return block, err
 }
 
 func (c *Client) prepareRequestParams(options ...RequestOption) ([]fab.Peer, *requestOptions, error) {
@@ -330,6 +354,60 @@ func (c *Client) QueryConfig(options ...RequestOption) (fab.ChannelCfg, error) {
 	return channelConfig.Query(reqCtx)
 }
 
+// MissingPrivateDataRequest holds the block range (inclusive) and the collections to check for missing private data.
+// If no collections are given then all collections are checked.
+type MissingPrivateDataRequest struct {
+	FromBlock   uint64
+	ToBlock     uint64
+	Collections []pvtdata.Collection
+}
+
+// MissingPrivateData identifies private data which isn't available on a peer
+type MissingPrivateData struct {
+	Target string
+	pvtdata.MissingPrivateData
+}
+
+// QueryMissingPrivateData queries the target peers for private data of the given blocks which was written by valid
+// transactions but was never received by the peer (the peers must support the DeliverWithPrivateData service and
+// the client's organization must be eligible for the collections). Note that private data which was purged
+// (see BlockToLive) is also reported as missing, unless the keys were purged explicitly (PurgePrivateData)
+// within the requested block range.
+//  Parameters:
+//  req holds the block range and the collections to check
+//  options hold optional request options
+//
+//  Returns:
+//  the private data missing on each of the targets
+func (c *Client) QueryMissingPrivateData(req MissingPrivateDataRequest, options ...RequestOption) ([]MissingPrivateData, error) {
+	targets, opts, err := c.prepareRequestParams(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "QueryMissingPrivateData failed to prepare request parameters")
+	}
+	reqCtx, cancel := c.createRequestContext(opts)
+	defer cancel()
+
+	client := pvtdata.New(c.ctx)
+
+	var missing []MissingPrivateData
+	for _, target := range targets {
+		peerConfig, ok := c.ctx.EndpointConfig().PeerConfig(target.URL())
+		if !ok {
+			peerConfig = &fab.PeerConfig{URL: target.URL()}
+		}
+
+		m, err := client.QueryMissing(reqCtx, *peerConfig, c.ctx.ChannelID(), req.FromBlock, req.ToBlock, req.Collections...)
+		if err != nil {
+			return nil, errors.WithMessage(err, "QueryMissingPrivateData failed")
+		}
+		for _, pd := range m {
+			missing = append(missing, MissingPrivateData{Target: target.URL(), MissingPrivateData: pd})
+		}
+	}
+
+	return missing, nil
+}
+
 //prepareRequestOpts Reads Opts from Option array
 func (c *Client) prepareRequestOpts(options ...RequestOption) (requestOptions, error) {
 	opts := requestOptions{}
@@ -447,7 +525,7 @@ func peersToTxnProcessors(peers []fab.Peer) []fab.ProposalProcessor {
 
 func shuffle(a []fab.Peer) {
 	for i := range a {
-		j := rand.Intn(i + 1)
+		j := random.Intn(i + 1)
 		a[i], a[j] = a[j], a[i]
 	}
 }
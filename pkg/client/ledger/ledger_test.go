@@ -9,6 +9,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/blockcache"
 	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -16,8 +18,10 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -366,3 +370,65 @@ func (tv *TestVerifier) Verify(response *fab.TransactionProposalResponse) error
 func (tv *TestVerifier) Match(response []*fab.TransactionProposalResponse) error {
 	return tv.matchErr
 }
+
+func TestQueryBlockWithCache(t *testing.T) {
+	blockBytes, err := proto.Marshal(&common.Block{Header: &common.BlockHeader{Number: 1, DataHash: []byte("hash1")}})
+	if err != nil {
+		t.Fatalf("Failed to marshal block: %s", err)
+	}
+
+	peer1 := &mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200, MockMSP: "test", Payload: blockBytes}
+
+	lc := setupLedgerClient([]fab.Peer{peer1}, t)
+
+	cache := blockcache.NewLRU(10)
+	lc.cache = cache
+
+	// Block 2 is only in the cache
+	cache.Put(channelID, &common.Block{Header: &common.BlockHeader{Number: 2, DataHash: []byte("hash2")}})
+
+	block, err := lc.QueryBlock(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hash2"), block.Header.DataHash)
+
+	// Block 1 is retrieved from the peer and added to the cache
+	block, err = lc.QueryBlock(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hash1"), block.Header.DataHash)
+	assert.Equal(t, 2, cache.Len())
+
+	hits, misses := cache.Stats()
+	assert.Equal(t, uint64(1), hits)
+	assert.Equal(t, uint64(1), misses)
+
+	// Block 1 is now served from the cache
+	_, err = lc.QueryBlock(1)
+	assert.NoError(t, err)
+	hits, _ = cache.Stats()
+	assert.Equal(t, uint64(2), hits)
+}
+
+func TestObserver(t *testing.T) {
+	// The peer belongs to an organization other than the client's
+	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200, MockMSP: "Org1MSP"}
+
+	fabCtx := setupCustomTestContext(t, txnmocks.NewMockDiscoveryService(nil, &peer), nil)
+
+	lc, err := New(createChannelContext(fabCtx, channelID))
+	require.NoError(t, err)
+	lc.verifier = &TestVerifier{}
+
+	_, err = lc.QueryInfo()
+	assert.Error(t, err, "expecting error since the client's organization has no peers")
+
+	ctx, err := fabCtx()
+	require.NoError(t, err)
+	ctx.EndpointConfig().(*fcmocks.MockConfig).ChannelObserver = fab.ObserverPolicy{Enabled: true}
+
+	lc, err = New(createChannelContext(fabCtx, channelID))
+	require.NoError(t, err)
+	lc.verifier = &TestVerifier{}
+
+	_, err = lc.QueryInfo()
+	assert.NoError(t, err, "expecting the peers of other organizations to be used by an observer")
+}
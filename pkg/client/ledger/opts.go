@@ -10,6 +10,7 @@ import (
 	reqContext "context"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/blockcache"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
@@ -32,6 +33,16 @@ func WithDefaultTargetFilter(filter fab.TargetFilter) ClientOption {
 	}
 }
 
+// WithBlockCache sets the cache which is consulted by QueryBlock before querying the peers. Blocks retrieved
+// from the peers are added to the cache. The cache may be shared with other clients on the same channel and
+// may be populated from block events (see blockcache.Populate).
+func WithBlockCache(cache blockcache.Cache) ClientOption {
+	return func(rmc *Client) error {
+		rmc.cache = cache
+		return nil
+	}
+}
+
 //RequestOption func for each requestOptions argument
 type RequestOption func(ctx context.Client, opts *requestOptions) error
 
@@ -0,0 +1,327 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package loadgen drives a configurable mix of read (query) and write (execute) transactions through a
+// channel client and reports the throughput and the latency percentiles of each stage of the load.
+// It's intended for quick performance checks of a network; see test/performance/cmd/loadgen for
+// the command line tool.
+//
+//  Basic Flow:
+//  1) Create a channel client
+//  2) Create a generator with the transaction mix and the concurrency stages
+//  3) Run the generator and print the report
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/random"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const (
+	defaultReadFcn     = "get"
+	defaultWriteFcn    = "put"
+	defaultKeySpace    = 1000
+	defaultPayloadSize = 100
+)
+
+// Invoker executes and queries chaincode (implemented by channel.Client)
+type Invoker interface {
+	Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+}
+
+// Stage runs the given number of concurrent clients for the given duration
+type Stage struct {
+	Concurrency int
+	Duration    time.Duration
+}
+
+// Config holds the transaction mix and the stages of the load
+type Config struct {
+	ChaincodeID string
+	// ReadPercent is the percentage (0-100) of transactions which are reads (queries); the rest are writes
+	ReadPercent int
+	// ReadFcn is invoked with a key for reads (default "get")
+	ReadFcn string
+	// WriteFcn is invoked with a key and a value for writes (default "put")
+	WriteFcn string
+	// KeySpace is the number of distinct keys which are read and written (default 1000)
+	KeySpace int
+	// PayloadSizes are the sizes of the values which are written, chosen randomly for each write (default 100 bytes)
+	PayloadSizes []int
+	// Stages are run in sequence, e.g. in order to ramp up the concurrency
+	Stages []Stage
+	// RequestOptions are passed to each query and execute
+	RequestOptions []channel.RequestOption
+}
+
+// Generator generates load on a channel
+type Generator struct {
+	invoker Invoker
+	cfg     Config
+}
+
+// New returns a load generator which uses the given invoker (e.g. a channel client)
+func New(invoker Invoker, cfg Config) (*Generator, error) {
+	if cfg.ChaincodeID == "" {
+		return nil, errors.New("chaincode ID is required")
+	}
+	if cfg.ReadPercent < 0 || cfg.ReadPercent > 100 {
+		return nil, errors.Errorf("invalid read percentage: %d", cfg.ReadPercent)
+	}
+	if len(cfg.Stages) == 0 {
+		return nil, errors.New("at least one stage is required")
+	}
+	for _, s := range cfg.Stages {
+		if s.Concurrency <= 0 || s.Duration <= 0 {
+			return nil, errors.Errorf("invalid stage: %d clients for %s", s.Concurrency, s.Duration)
+		}
+	}
+
+	if cfg.ReadFcn == "" {
+		cfg.ReadFcn = defaultReadFcn
+	}
+	if cfg.WriteFcn == "" {
+		cfg.WriteFcn = defaultWriteFcn
+	}
+	if cfg.KeySpace <= 0 {
+		cfg.KeySpace = defaultKeySpace
+	}
+	if len(cfg.PayloadSizes) == 0 {
+		cfg.PayloadSizes = []int{defaultPayloadSize}
+	}
+
+	return &Generator{invoker: invoker, cfg: cfg}, nil
+}
+
+// Run runs the stages in sequence. The run is stopped if the given context is done, in which case
+// the report contains the stages which were run (along with the interrupted stage).
+func (g *Generator) Run(ctx context.Context) (*Report, error) {
+	report := &Report{}
+	for i, stage := range g.cfg.Stages {
+		logger.Infof("Running stage %d: %d clients for %s", i+1, stage.Concurrency, stage.Duration)
+		report.Stages = append(report.Stages, g.runStage(ctx, stage))
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+	}
+	return report, nil
+}
+
+func (g *Generator) runStage(ctx context.Context, stage Stage) StageReport {
+	stageCtx, cancel := context.WithTimeout(ctx, stage.Duration)
+	defer cancel()
+
+	reads := &recorder{}
+	writes := &recorder{}
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < stage.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for stageCtx.Err() == nil {
+				if random.Intn(100) < g.cfg.ReadPercent {
+					g.invoke(stageCtx, reads, g.invoker.Query, g.readRequest())
+				} else {
+					g.invoke(stageCtx, writes, g.invoker.Execute, g.writeRequest())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	return StageReport{
+		Stage:    stage,
+		Elapsed:  elapsed,
+		Reads:    reads.stats(elapsed),
+		Writes:   writes.stats(elapsed),
+		FirstErr: firstErr(reads, writes),
+	}
+}
+
+type invokeFunc func(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+
+func (g *Generator) invoke(ctx context.Context, r *recorder, invoke invokeFunc, request channel.Request) {
+	opts := append([]channel.RequestOption{channel.WithParentContext(ctx)}, g.cfg.RequestOptions...)
+
+	start := time.Now()
+	_, err := invoke(request, opts...)
+	if err != nil && ctx.Err() != nil {
+		// the request was interrupted by the end of the stage
+		return
+	}
+	r.record(time.Since(start), err)
+}
+
+func (g *Generator) readRequest() channel.Request {
+	return channel.Request{
+		ChaincodeID: g.cfg.ChaincodeID,
+		Fcn:         g.cfg.ReadFcn,
+		Args:        [][]byte{g.key()},
+	}
+}
+
+func (g *Generator) writeRequest() channel.Request {
+	value := make([]byte, g.cfg.PayloadSizes[random.Intn(len(g.cfg.PayloadSizes))])
+	for i := range value {
+		value[i] = byte('a' + random.Intn(26))
+	}
+	return channel.Request{
+		ChaincodeID: g.cfg.ChaincodeID,
+		Fcn:         g.cfg.WriteFcn,
+		Args:        [][]byte{g.key(), value},
+	}
+}
+
+func (g *Generator) key() []byte {
+	return []byte(fmt.Sprintf("key%d", random.Intn(g.cfg.KeySpace)))
+}
+
+// recorder records the latencies and errors of requests
+type recorder struct {
+	mutex     sync.Mutex
+	latencies []time.Duration
+	errors    int
+	firstErr  error
+}
+
+func (r *recorder) record(latency time.Duration, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err != nil {
+		r.errors++
+		if r.firstErr == nil {
+			r.firstErr = err
+		}
+		return
+	}
+	r.latencies = append(r.latencies, latency)
+}
+
+func (r *recorder) stats(elapsed time.Duration) Stats {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	s := Stats{Count: len(r.latencies), Errors: r.errors}
+	if len(r.latencies) == 0 {
+		return s
+	}
+
+	sort.Slice(r.latencies, func(i, j int) bool { return r.latencies[i] < r.latencies[j] })
+
+	var total time.Duration
+	for _, l := range r.latencies {
+		total += l
+	}
+	s.TPS = float64(len(r.latencies)) / elapsed.Seconds()
+	s.Mean = total / time.Duration(len(r.latencies))
+	s.P50 = percentile(r.latencies, 50)
+	s.P90 = percentile(r.latencies, 90)
+	s.P99 = percentile(r.latencies, 99)
+	s.Max = r.latencies[len(r.latencies)-1]
+	return s
+}
+
+func firstErr(recorders ...*recorder) error {
+	for _, r := range recorders {
+		if r.firstErr != nil {
+			return r.firstErr
+		}
+	}
+	return nil
+}
+
+// percentile returns the p-th percentile (nearest rank) of the given sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Stats contains the throughput and the latencies of the successful requests of one kind
+type Stats struct {
+	Count  int
+	Errors int
+	TPS    float64
+	Mean   time.Duration
+	P50    time.Duration
+	P90    time.Duration
+	P99    time.Duration
+	Max    time.Duration
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("count=%d errors=%d tps=%.1f mean=%s p50=%s p90=%s p99=%s max=%s",
+		s.Count, s.Errors, s.TPS, s.Mean, s.P50, s.P90, s.P99, s.Max)
+}
+
+// StageReport contains the results of a stage
+type StageReport struct {
+	Stage   Stage
+	Elapsed time.Duration
+	Reads   Stats
+	Writes  Stats
+	// FirstErr is the first error returned by a request (if any)
+	FirstErr error
+}
+
+// Report contains the results of each stage
+type Report struct {
+	Stages []StageReport
+}
+
+func (r *Report) String() string {
+	var b bytes.Buffer
+	for i, s := range r.Stages {
+		fmt.Fprintf(&b, "Stage %d (%d clients, %s):\n", i+1, s.Stage.Concurrency, s.Elapsed.Round(time.Millisecond))
+		fmt.Fprintf(&b, "  reads:  %s\n", s.Reads)
+		fmt.Fprintf(&b, "  writes: %s\n", s.Writes)
+		if s.FirstErr != nil {
+			fmt.Fprintf(&b, "  first error: %s\n", s.FirstErr)
+		}
+	}
+	return b.String()
+}
+
+// ParseStages parses stages from a comma separated list of <concurrency>:<duration>, e.g. "10:30s,50:1m"
+func ParseStages(s string) ([]Stage, error) {
+	var stages []Stage
+	for _, v := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(v), ":")
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid stage [%s]: expecting <concurrency>:<duration>", v)
+		}
+		concurrency, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid concurrency in stage [%s]", v)
+		}
+		duration, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid duration in stage [%s]", v)
+		}
+		stages = append(stages, Stage{Concurrency: concurrency, Duration: duration})
+	}
+	return stages, nil
+}
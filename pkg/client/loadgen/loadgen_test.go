@@ -0,0 +1,151 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package loadgen
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	stages := []Stage{{Concurrency: 1, Duration: time.Second}}
+
+	_, err := New(&mockInvoker{}, Config{Stages: stages})
+	assert.Error(t, err, "expecting error for missing chaincode ID")
+
+	_, err = New(&mockInvoker{}, Config{ChaincodeID: "cc", ReadPercent: 101, Stages: stages})
+	assert.Error(t, err, "expecting error for invalid read percentage")
+
+	_, err = New(&mockInvoker{}, Config{ChaincodeID: "cc"})
+	assert.Error(t, err, "expecting error for missing stages")
+
+	_, err = New(&mockInvoker{}, Config{ChaincodeID: "cc", Stages: []Stage{{Concurrency: 0, Duration: time.Second}}})
+	assert.Error(t, err, "expecting error for invalid stage")
+
+	g, err := New(&mockInvoker{}, Config{ChaincodeID: "cc", Stages: stages})
+	require.NoError(t, err)
+	assert.Equal(t, defaultReadFcn, g.cfg.ReadFcn)
+	assert.Equal(t, defaultWriteFcn, g.cfg.WriteFcn)
+	assert.Equal(t, defaultKeySpace, g.cfg.KeySpace)
+	assert.Equal(t, []int{defaultPayloadSize}, g.cfg.PayloadSizes)
+}
+
+func TestRun(t *testing.T) {
+	invoker := &mockInvoker{latency: time.Millisecond}
+	g, err := New(invoker, Config{
+		ChaincodeID:  "cc",
+		ReadPercent:  50,
+		KeySpace:     10,
+		PayloadSizes: []int{10, 20},
+		Stages: []Stage{
+			{Concurrency: 1, Duration: 100 * time.Millisecond},
+			{Concurrency: 4, Duration: 100 * time.Millisecond},
+		},
+	})
+	require.NoError(t, err)
+
+	report, err := g.Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Stages, 2)
+
+	for _, s := range report.Stages {
+		assert.True(t, s.Reads.Count > 0, "expecting reads")
+		assert.True(t, s.Writes.Count > 0, "expecting writes")
+		assert.True(t, s.Reads.P50 >= time.Millisecond)
+		assert.True(t, s.Writes.P99 <= s.Writes.Max)
+		assert.NoError(t, s.FirstErr)
+	}
+	assert.True(t, report.Stages[1].Reads.TPS > report.Stages[0].Reads.TPS, "expecting higher throughput with more clients")
+
+	for _, r := range invoker.writes {
+		assert.Equal(t, defaultWriteFcn, r.Fcn)
+		require.Len(t, r.Args, 2)
+		assert.Contains(t, []int{10, 20}, len(r.Args[1]))
+	}
+	assert.Contains(t, report.String(), "Stage 2 (4 clients")
+}
+
+func TestRunErrors(t *testing.T) {
+	g, err := New(&mockInvoker{err: errors.New("endorsement failed")}, Config{
+		ChaincodeID: "cc",
+		Stages:      []Stage{{Concurrency: 2, Duration: 50 * time.Millisecond}},
+	})
+	require.NoError(t, err)
+
+	report, err := g.Run(context.Background())
+	require.NoError(t, err)
+	s := report.Stages[0]
+	assert.Equal(t, 0, s.Writes.Count)
+	assert.True(t, s.Writes.Errors > 0)
+	assert.Equal(t, 0, s.Reads.Count+s.Reads.Errors, "expecting writes only")
+	assert.EqualError(t, s.FirstErr, "endorsement failed")
+}
+
+func TestRunCancel(t *testing.T) {
+	g, err := New(&mockInvoker{latency: time.Millisecond}, Config{
+		ChaincodeID: "cc",
+		Stages:      []Stage{{Concurrency: 1, Duration: time.Minute}, {Concurrency: 1, Duration: time.Minute}},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	report, err := g.Run(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Len(t, report.Stages, 1)
+}
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i))
+	}
+	assert.Equal(t, time.Duration(50), percentile(latencies, 50))
+	assert.Equal(t, time.Duration(99), percentile(latencies, 99))
+	assert.Equal(t, time.Duration(1), percentile(latencies[:1], 99))
+}
+
+func TestParseStages(t *testing.T) {
+	stages, err := ParseStages("10:30s, 50:1m")
+	require.NoError(t, err)
+	assert.Equal(t, []Stage{{Concurrency: 10, Duration: 30 * time.Second}, {Concurrency: 50, Duration: time.Minute}}, stages)
+
+	_, err = ParseStages("10")
+	assert.Error(t, err)
+	_, err = ParseStages("x:30s")
+	assert.Error(t, err)
+	_, err = ParseStages("10:x")
+	assert.Error(t, err)
+}
+
+type mockInvoker struct {
+	latency time.Duration
+	err     error
+	mutex   sync.Mutex
+	writes  []channel.Request
+}
+
+func (m *mockInvoker) Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	time.Sleep(m.latency)
+	return channel.Response{}, m.err
+}
+
+func (m *mockInvoker) Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	time.Sleep(m.latency)
+	m.mutex.Lock()
+	m.writes = append(m.writes, request)
+	m.mutex.Unlock()
+	return channel.Response{}, m.err
+}
@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"sort"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/attrmgr"
+	mspctx "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+)
+
+// CertAttributes holds the attributes that the CA added to the enrollment certificate of an
+// identity. These are the attributes that chaincode reads with the client identity library (cid).
+type CertAttributes struct {
+	attrs map[string]string
+}
+
+// GetCertAttributes returns the attributes in the enrollment certificate of the identity
+//  Parameters:
+//  identity is the identity, e.g. the signing identity returned by GetSigningIdentity
+//
+//  Returns:
+//  the attributes, which are empty if the certificate has none
+func GetCertAttributes(identity mspctx.Identity) (*CertAttributes, error) {
+	block, _ := pem.Decode(identity.EnrollmentCertificate())
+	if block == nil {
+		return nil, errors.New("failed to decode enrollment certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse enrollment certificate")
+	}
+
+	attrs, err := attrmgr.New().GetAttributesFromCert(cert)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get attributes from enrollment certificate")
+	}
+	if attrs.Attrs == nil {
+		attrs.Attrs = make(map[string]string)
+	}
+	return &CertAttributes{attrs: attrs.Attrs}, nil
+}
+
+// Names returns the sorted names of the attributes
+func (a *CertAttributes) Names() []string {
+	var names []string
+	for name := range a.attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Value returns the value of the attribute and whether the certificate holds the attribute
+func (a *CertAttributes) Value(name string) (string, bool) {
+	value, ok := a.attrs[name]
+	return value, ok
+}
+
+// AssertValue returns an error unless the attribute has the given value, as cid.AssertAttributeValue does in chaincode
+func (a *CertAttributes) AssertValue(name, value string) error {
+	v, ok := a.attrs[name]
+	if !ok {
+		return errors.Errorf("attribute '%s' was not found", name)
+	}
+	if v != value {
+		return errors.Errorf("attribute '%s' equals '%s', not '%s'", name, v, value)
+	}
+	return nil
+}
+
+// AssertAttributes checks that the enrollment certificate of the identity holds the required attributes
+// before a transaction is submitted, so that requests are rejected locally rather than by the chaincode.
+//  Parameters:
+//  identity is the identity submitting the transaction
+//  required maps the name of each required attribute to its required value (empty if any value is accepted)
+//
+//  Returns:
+//  an error naming the first missing or mismatched attribute
+func AssertAttributes(identity mspctx.Identity, required map[string]string) error {
+	attrs, err := GetCertAttributes(identity)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for name := range required {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if required[name] == "" {
+			if _, ok := attrs.Value(name); !ok {
+				return errors.Errorf("attribute '%s' was not found", name)
+			}
+			continue
+		}
+		if err := attrs.AssertValue(name, required[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/attrmgr"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdentityWithAttributes(t *testing.T, attrs map[string]string) *mspmocks.MockSigningIdentity {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "user1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if attrs != nil {
+		require.NoError(t, attrmgr.New().AddAttributesToCert(&attrmgr.Attributes{Attrs: attrs}, template))
+		template.ExtraExtensions = template.Extensions
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	identity := mspmocks.NewMockSigningIdentity("user1", "Org1MSP")
+	identity.SetEnrollmentCertificate(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	return identity
+}
+
+func TestGetCertAttributes(t *testing.T) {
+	identity := newIdentityWithAttributes(t, map[string]string{"hf.EnrollmentID": "user1", "app.role": "auditor"})
+
+	attrs, err := GetCertAttributes(identity)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app.role", "hf.EnrollmentID"}, attrs.Names())
+
+	value, ok := attrs.Value("app.role")
+	assert.True(t, ok)
+	assert.Equal(t, "auditor", value)
+	_, ok = attrs.Value("app.region")
+	assert.False(t, ok)
+
+	assert.NoError(t, attrs.AssertValue("app.role", "auditor"))
+	assert.EqualError(t, attrs.AssertValue("app.role", "admin"), "attribute 'app.role' equals 'auditor', not 'admin'")
+	assert.EqualError(t, attrs.AssertValue("app.region", "eu"), "attribute 'app.region' was not found")
+
+	attrs, err = GetCertAttributes(newIdentityWithAttributes(t, nil))
+	require.NoError(t, err)
+	assert.Empty(t, attrs.Names())
+
+	invalid := mspmocks.NewMockSigningIdentity("user1", "Org1MSP")
+	invalid.SetEnrollmentCertificate([]byte("invalid"))
+	_, err = GetCertAttributes(invalid)
+	assert.Error(t, err)
+}
+
+func TestAssertAttributes(t *testing.T) {
+	identity := newIdentityWithAttributes(t, map[string]string{"app.role": "auditor", "app.region": "eu"})
+
+	assert.NoError(t, AssertAttributes(identity, nil))
+	assert.NoError(t, AssertAttributes(identity, map[string]string{"app.role": "auditor", "app.region": ""}))
+	assert.EqualError(t, AssertAttributes(identity, map[string]string{"app.role": "admin"}), "attribute 'app.role' equals 'auditor', not 'admin'")
+	assert.EqualError(t, AssertAttributes(identity, map[string]string{"app.tenant": ""}), "attribute 'app.tenant' was not found")
+}
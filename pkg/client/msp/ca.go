@@ -55,6 +55,8 @@ type RevocationRequest struct {
 	Reason string
 	// CAName is the name of the CA to connect to
 	CAName string
+	// GenCRL requests the CA to return a CRL with all unexpired revoked certificates in the response
+	GenCRL bool
 }
 
 // RevocationResponse represents response from the server for a revocation request
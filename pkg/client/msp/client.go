@@ -105,7 +105,8 @@ func newCAClient(ctx context.Client, orgName string) (mspapi.CAClient, error) {
 
 // enrollmentOptions represent enrollment options
 type enrollmentOptions struct {
-	secret string
+	secret   string
+	attrReqs []*AttributeRequest
 }
 
 // EnrollmentOption describes a functional parameter for Enroll
@@ -119,6 +120,16 @@ func WithSecret(secret string) EnrollmentOption {
 	}
 }
 
+// WithAttributeRequests enrollment option requests attributes of the registered
+// user to be added to the enrollment certificate. The CA fails the enrollment if
+// the user doesn't own a requested attribute, unless the request is optional.
+func WithAttributeRequests(attrReqs []*AttributeRequest) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		o.attrReqs = attrReqs
+		return nil
+	}
+}
+
 // CreateIdentity creates a new identity with the Fabric CA server. An enrollment secret is returned which can then be used,
 // along with the enrollment ID, to enroll a new identity.
 //  Parameters:
@@ -328,7 +339,14 @@ func (c *Client) Enroll(enrollmentID string, opts ...EnrollmentOption) error {
 	if err != nil {
 		return err
 	}
-	return ca.Enroll(enrollmentID, eo.secret)
+	req := &mspapi.EnrollmentRequest{
+		Name:   enrollmentID,
+		Secret: eo.secret,
+	}
+	for _, attrReq := range eo.attrReqs {
+		req.AttrReqs = append(req.AttrReqs, &mspapi.AttributeRequest{Name: attrReq.Name, Optional: attrReq.Optional})
+	}
+	return ca.Enroll(req)
 }
 
 // Reenroll reenrolls an enrolled user in order to obtain a new signed X509 certificate
@@ -424,6 +442,22 @@ func (c *Client) GetSigningIdentity(id string) (mspctx.SigningIdentity, error) {
 	return si, nil
 }
 
+// CreateSigningIdentity creates a signing identity for the client's org from the given certificate and
+// private key. The credentials are neither loaded from nor saved to the credential store or the MSP
+// directories. The identity may be used to create a context for any client (see fabsdk.WithIdentity).
+//  Parameters:
+//  opts provide the PEM-encoded certificate and private key (see msp.WithCert and msp.WithPrivateKey)
+//
+//  Returns:
+//  signing identity
+func (c *Client) CreateSigningIdentity(opts ...mspctx.SigningIdentityOption) (mspctx.SigningIdentity, error) {
+	im, ok := c.ctx.IdentityManager(c.orgName)
+	if !ok {
+		return nil, errors.Errorf("identity manager not found for organization [%s]", c.orgName)
+	}
+	return im.CreateSigningIdentity(opts...)
+}
+
 //prepareOptsFromOptions reads request options from Option array
 func (c *Client) prepareOptsFromOptions(ctx context.Client, options ...RequestOption) (requestOptions, error) {
 	opts := requestOptions{}
@@ -0,0 +1,142 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	reqContext "context"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/participation"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// BootstrapChannelRequest holds parameters for bootstrapping an application channel
+type BootstrapChannelRequest struct {
+	ChannelID string
+	// Profile is used to generate the genesis block of the channel. The anchor peers of the
+	// application organizations are set in the genesis block.
+	Profile *configtx.GenesisProfile
+	// GenesisBlock may be provided instead of a profile (e.g. a block generated by configtxgen)
+	GenesisBlock *common.Block
+}
+
+// BootstrapChannelResponse contains response parameters for bootstrap channel
+type BootstrapChannelResponse struct {
+	GenesisBlock *common.Block
+	// Orderers contains the channel status of each of the orderers
+	Orderers map[string]*participation.ChannelInfo
+}
+
+// BootstrapChannel creates an application channel on an ordering service which uses the channel participation
+// API (Fabric 2.3+, i.e. without a system channel) and joins the peers to the channel.
+//
+//  Basic Flow:
+//  1) Generate the genesis block of the channel from the profile (including the anchor peers of the organizations)
+//  2) Join the orderers to the channel through their admin endpoints (orderers that are already members are skipped)
+//  3) Join the peers to the channel with the genesis block
+//
+// The admin URL (adminUrl) must be configured for each of the orderers and the client's TLS certificate
+// must be authorized by the admin endpoints.
+//  Parameters:
+//  req holds info about mandatory channel ID and the profile (or genesis block) of the channel
+//  options holds optional request options (WithOrdererEndpoint or WithOrderer selects a single orderer, otherwise
+//  all of the configured orderers are joined; target peers default to the peers of the client's organization)
+//
+//  Returns:
+//  bootstrap channel response with the genesis block and the channel status of the orderers
+func (rc *Client) BootstrapChannel(req BootstrapChannelRequest, options ...RequestOption) (BootstrapChannelResponse, error) {
+	if req.ChannelID == "" {
+		return BootstrapChannelResponse{}, errors.New("must provide channel ID")
+	}
+	if req.Profile == nil && req.GenesisBlock == nil {
+		return BootstrapChannelResponse{}, errors.New("must provide channel profile or genesis block")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return BootstrapChannelResponse{}, errors.WithMessage(err, "failed to get opts for BootstrapChannel")
+	}
+
+	ordererTargets, err := rc.ordererTargets(opts)
+	if err != nil {
+		return BootstrapChannelResponse{}, err
+	}
+
+	peerTargets, err := rc.calculateTargets(opts.Targets, opts.TargetFilter)
+	if err != nil {
+		return BootstrapChannelResponse{}, errors.WithMessage(err, "failed to determine target peers for BootstrapChannel")
+	}
+	if len(peerTargets) == 0 {
+		return BootstrapChannelResponse{}, errors.WithStack(status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "no targets available", nil))
+	}
+
+	genesisBlock := req.GenesisBlock
+	if genesisBlock == nil {
+		if genesisBlock, err = configtx.NewGenesisBlock(req.ChannelID, req.Profile); err != nil {
+			return BootstrapChannelResponse{}, errors.WithMessage(err, "creating genesis block failed")
+		}
+	}
+
+	rc.resolveTimeouts(&opts)
+
+	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.ParentContext))
+	parentReqCtx = reqContext.WithValue(parentReqCtx, contextImpl.ReqContextTimeoutOverrides, opts.Timeouts)
+	defer parentReqCancel()
+
+	orderers, err := rc.joinOrderers(parentReqCtx, req.ChannelID, genesisBlock, ordererTargets)
+	if err != nil {
+		return BootstrapChannelResponse{}, err
+	}
+
+	if err := rc.joinPeers(parentReqCtx, genesisBlock, peerTargets, opts); err != nil {
+		return BootstrapChannelResponse{}, err
+	}
+
+	return BootstrapChannelResponse{GenesisBlock: genesisBlock, Orderers: orderers}, nil
+}
+
+// joinOrderers joins the given orderers to the channel through the channel participation API
+func (rc *Client) joinOrderers(parentReqCtx reqContext.Context, channelID string, genesisBlock *common.Block, targets []fab.OrdererConfig) (map[string]*participation.ChannelInfo, error) {
+	reqCtx, cancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeoutType(fab.OrdererResponse), contextImpl.WithParent(parentReqCtx))
+	defer cancel()
+
+	orderers := make(map[string]*participation.ChannelInfo)
+	var errs multi.Errors
+	for i := range targets {
+		target := targets[i]
+		info, err := rc.joinOrderer(reqCtx, channelID, genesisBlock, &target)
+		if err != nil {
+			errs = append(errs, errors.WithMessage(err, "join channel failed on orderer ["+target.URL+"]"))
+			continue
+		}
+		orderers[target.URL] = info
+	}
+	return orderers, errs.ToError()
+}
+
+func (rc *Client) joinOrderer(reqCtx reqContext.Context, channelID string, genesisBlock *common.Block, ordererCfg *fab.OrdererConfig) (*participation.ChannelInfo, error) {
+	client, err := participation.NewForOrderer(rc.ctx.EndpointConfig(), ordererCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := client.ChannelInfo(reqCtx, channelID)
+	if err == nil {
+		logger.Debugf("Orderer [%s] is already a member of channel [%s]", ordererCfg.URL, channelID)
+		return info, nil
+	}
+	if err != participation.ErrChannelNotFound {
+		return nil, err
+	}
+
+	return client.Join(reqCtx, genesisBlock)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/participation"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockParticipationServer emulates the channel participation API of an orderer
+type mockParticipationServer struct {
+	mutex  sync.Mutex
+	joined bool
+	joins  int
+}
+
+func (s *mockParticipationServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	info := &participation.ChannelInfo{Name: "mychannel", ConsensusRelation: "consenter", Status: "active", Height: 1}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/participation/v1/channels":
+		if _, _, err := r.FormFile("config-block"); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.joined = true
+		s.joins++
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(info) // nolint: errcheck
+	case r.Method == http.MethodGet && r.URL.Path == "/participation/v1/channels/mychannel" && s.joined:
+		json.NewEncoder(w).Encode(info) // nolint: errcheck
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestGenesisProfile() *configtx.GenesisProfile {
+	return &configtx.GenesisProfile{
+		Orderer: &configtx.Orderer{
+			Consenters: []configtx.Consenter{
+				{Host: "orderer.example.com", Port: 7050, ClientTLSCert: []byte("client"), ServerTLSCert: []byte("server")},
+			},
+			Organizations: map[string]*configtx.Organization{
+				"OrdererOrg": {Name: "OrdererOrg", MSPID: "OrdererMSP", RootCerts: [][]byte{[]byte("orderer-ca")}},
+			},
+		},
+		Application: &configtx.Application{
+			Organizations: map[string]*configtx.Organization{
+				"Org1MSP": {
+					Name:        "Org1MSP",
+					MSPID:       "Org1MSP",
+					RootCerts:   [][]byte{[]byte("org1-ca")},
+					AnchorPeers: []configtx.AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}},
+				},
+			},
+		},
+	}
+}
+
+func TestBootstrapChannel(t *testing.T) {
+	participationServer := &mockParticipationServer{}
+	server := httptest.NewServer(participationServer)
+	defer server.Close()
+
+	srv := &fcmocks.MockEndorserServer{}
+	addr := srv.Start(testAddress)
+	defer srv.Stop()
+
+	ctx := setupTestContext("test", "Org1MSP")
+	config := fcmocks.NewMockEndpointConfig()
+	config.(*fcmocks.MockConfig).SetCustomOrdererCfg(&fab.OrdererConfig{URL: "orderer.example.com:7050", AdminURL: server.URL})
+	ctx.SetEndpointConfig(config)
+
+	rc := setupResMgmtClient(t, ctx)
+
+	peer1, err := peer.New(fcmocks.NewMockEndpointConfig(), peer.WithURL("grpc://"+addr))
+	require.NoError(t, err)
+
+	req := BootstrapChannelRequest{ChannelID: "mychannel", Profile: newTestGenesisProfile()}
+
+	resp, err := rc.BootstrapChannel(req, WithTargets(peer1))
+	require.NoError(t, err)
+	require.NotNil(t, resp.GenesisBlock)
+	assert.Equal(t, uint64(0), resp.GenesisBlock.Header.Number)
+	require.Contains(t, resp.Orderers, "orderer.example.com:7050")
+	assert.Equal(t, "consenter", resp.Orderers["orderer.example.com:7050"].ConsensusRelation)
+	assert.Equal(t, 1, participationServer.joins)
+
+	// The orderer is already a member of the channel
+	_, err = rc.BootstrapChannel(BootstrapChannelRequest{ChannelID: "mychannel", GenesisBlock: resp.GenesisBlock}, WithTargets(peer1))
+	require.NoError(t, err)
+	assert.Equal(t, 1, participationServer.joins, "expecting orderer which is already a member to be skipped")
+}
+
+func TestBootstrapChannelErrors(t *testing.T) {
+	ctx := setupTestContext("test", "Org1MSP")
+	config := fcmocks.NewMockEndpointConfig()
+	config.(*fcmocks.MockConfig).SetCustomOrdererCfg(&fab.OrdererConfig{URL: "orderer.example.com:7050"})
+	ctx.SetEndpointConfig(config)
+
+	rc := setupResMgmtClient(t, ctx)
+	peer1 := fcmocks.NewMockPeer("peer1", "peer1.example.com:7051")
+
+	_, err := rc.BootstrapChannel(BootstrapChannelRequest{Profile: newTestGenesisProfile()})
+	assert.Error(t, err, "expecting error for missing channel ID")
+
+	_, err = rc.BootstrapChannel(BootstrapChannelRequest{ChannelID: "mychannel"})
+	assert.Error(t, err, "expecting error for missing profile")
+
+	_, err = rc.BootstrapChannel(BootstrapChannelRequest{ChannelID: "mychannel", Profile: &configtx.GenesisProfile{}}, WithTargets(peer1))
+	assert.Error(t, err, "expecting error for invalid profile")
+
+	_, err = rc.BootstrapChannel(BootstrapChannelRequest{ChannelID: "mychannel", Profile: newTestGenesisProfile()}, WithTargets(peer1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "admin URL is not configured")
+}
@@ -0,0 +1,213 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	reqContext "context"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/operations"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/pkg/errors"
+)
+
+// Fabric doesn't expose the ledger administration operations of a peer remotely. They're performed with the peer
+// CLI while the peer is stopped:
+//
+//  peer node reset                               resets the ledgers of all channels to the genesis block
+//  peer node rollback -c <channel> -b <block>    rolls back the ledger of a channel to the given block
+//  peer node rebuild-dbs                         drops the state, history and other databases (rebuilt on start)
+//
+// After the peer is restarted it pulls the missing blocks from the orderer or from other peers (and reconciles
+// missing private data in the background). WaitForPeerCatchUp verifies that the peer has recovered.
+
+const defaultCatchUpPollInterval = 2 * time.Second
+
+// PeerCatchUpRequest holds the parameters for waiting for peers to catch up with the channel
+type PeerCatchUpRequest struct {
+	ChannelID string
+	// TargetHeight is the ledger height which the peers must reach. If it's 0 then the height of the channel
+	// is determined from the newest block of the orderer.
+	TargetHeight uint64
+	// PollInterval is the interval at which the peers are queried (the default is 2s)
+	PollInterval time.Duration
+}
+
+// PeerCatchUpStatus contains the recovery status of a peer
+type PeerCatchUpStatus struct {
+	Target string
+	// Height is the height of the peer's ledger (0 if it couldn't be queried)
+	Height uint64
+	// Healthy is true if the peer's health checks succeeded (or the operations URL of the peer isn't configured)
+	Healthy bool
+	// Error is the error of the last query of the peer (if any)
+	Error error
+}
+
+// caughtUp returns true if the peer is healthy and its ledger has reached the given height
+func (s *PeerCatchUpStatus) caughtUp(height uint64) bool {
+	return s.Error == nil && s.Healthy && s.Height >= height
+}
+
+// WaitForPeerCatchUp waits until the peers are healthy and their ledgers have reached the height of the channel,
+// e.g. after a ledger was reset or rolled back. The health of a peer is checked through its operations endpoint if
+// the operations URL (operationsUrl) of the peer is configured. The wait is bounded by the resource management
+// timeout (see WithTimeout) and the parent context.
+//  Parameters:
+//  req holds the channel and the target height
+//  options holds optional request options (targets default to the peers of the client's organization)
+//
+//  Returns:
+//  the status of each peer and an error if not all of the peers caught up
+func (rc *Client) WaitForPeerCatchUp(req PeerCatchUpRequest, options ...RequestOption) ([]PeerCatchUpStatus, error) {
+	if req.ChannelID == "" {
+		return nil, errors.New("must provide channel ID")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := rc.calculateTargets(opts.Targets, opts.TargetFilter)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to determine target peers for WaitForPeerCatchUp")
+	}
+	if len(targets) == 0 {
+		return nil, errors.WithStack(status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "no targets available", nil))
+	}
+
+	pollInterval := req.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultCatchUpPollInterval
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.ResMgmt)
+	defer cancel()
+
+	height := req.TargetHeight
+	if height == 0 {
+		height, err = rc.channelHeight(reqCtx, req.ChannelID, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugf("Waiting for %d peer(s) to reach height %d on channel [%s]", len(targets), height, req.ChannelID)
+
+	ledger, err := channel.NewLedger(req.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []PeerCatchUpStatus
+	var lastErr error
+	for {
+		current, err := rc.peerCatchUpStatus(reqCtx, ledger, targets, height)
+		if err == nil {
+			return current, nil
+		}
+		if reqCtx.Err() != nil && statuses != nil {
+			// the queries were interrupted by the timeout so the previous status is reported
+			break
+		}
+		statuses, lastErr = current, err
+
+		if !sleep(reqCtx, pollInterval) {
+			break
+		}
+	}
+
+	return statuses, errors.WithStack(status.New(status.ClientStatus, status.Timeout.ToInt32(),
+		"peers didn't catch up before the timeout: "+lastErr.Error(), nil))
+}
+
+// sleep waits for the given duration and returns false if the context is done before
+func sleep(reqCtx reqContext.Context, d time.Duration) bool {
+	select {
+	case <-reqCtx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// channelHeight returns the height of the channel from the newest block of the orderer
+func (rc *Client) channelHeight(reqCtx reqContext.Context, channelID string, opts requestOptions) (uint64, error) {
+	orderer, err := rc.requestOrderer(&opts, channelID)
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to find orderer for request")
+	}
+
+	ordrReqCtx, cancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeoutType(fab.OrdererResponse), contextImpl.WithParent(reqCtx))
+	defer cancel()
+
+	block, err := resource.NewestBlockFromOrderer(ordrReqCtx, channelID, orderer, resource.WithRetry(opts.Retry))
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to retrieve newest block from orderer")
+	}
+	return block.Header.Number + 1, nil
+}
+
+// peerCatchUpStatus queries the health and the ledger height of the peers. An error is returned
+// if any of the peers hasn't caught up.
+func (rc *Client) peerCatchUpStatus(reqCtx reqContext.Context, ledger *channel.Ledger, targets []fab.Peer, height uint64) ([]PeerCatchUpStatus, error) {
+	var statuses []PeerCatchUpStatus
+	var errs multi.Errors
+	for _, target := range targets {
+		s := rc.queryCatchUpStatus(reqCtx, ledger, target)
+		if !s.caughtUp(height) {
+			if s.Error != nil {
+				errs = append(errs, errors.WithMessage(s.Error, "query of peer ["+s.Target+"] failed"))
+			} else if !s.Healthy {
+				errs = append(errs, errors.Errorf("peer [%s] isn't healthy", s.Target))
+			} else {
+				errs = append(errs, errors.Errorf("peer [%s] is at height %d of %d", s.Target, s.Height, height))
+			}
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, errs.ToError()
+}
+
+func (rc *Client) queryCatchUpStatus(reqCtx reqContext.Context, ledger *channel.Ledger, target fab.Peer) PeerCatchUpStatus {
+	s := PeerCatchUpStatus{Target: target.URL(), Healthy: true}
+
+	peerCfg := rc.targetPeerConfig(target)
+	if peerCfg.OperationsURL != "" {
+		client, err := operations.NewForPeer(rc.ctx.EndpointConfig(), &peerCfg)
+		if err != nil {
+			s.Error = err
+			return s
+		}
+		health, err := client.Health(reqCtx)
+		if err != nil {
+			s.Error = err
+			return s
+		}
+		s.Healthy = health.Healthy()
+	}
+
+	peerReqCtx, cancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeoutType(fab.PeerResponse), contextImpl.WithParent(reqCtx))
+	defer cancel()
+
+	responses, err := ledger.QueryInfo(peerReqCtx, []fab.ProposalProcessor{target}, nil)
+	if err != nil {
+		s.Error = err
+		return s
+	}
+	if len(responses) == 0 || responses[0].BCI == nil {
+		s.Error = errors.New("no blockchain info returned")
+		return s
+	}
+	s.Height = responses[0].BCI.Height
+	return s
+}
@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForPeerCatchUp(t *testing.T) {
+	var unhealthy int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&unhealthy) == 0 {
+			json.NewEncoder(w).Encode(map[string]string{"status": "OK"}) // nolint: errcheck
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]interface{}{ // nolint: errcheck
+			"status":        "Service Unavailable",
+			"failed_checks": []map[string]string{{"component": "couchdb", "reason": "down"}},
+		})
+	}))
+	defer server.Close()
+
+	ctx := setupTestContext("test", "Org1MSP")
+	config := fcmocks.NewMockEndpointConfig()
+	config.(*fcmocks.MockConfig).SetCustomPeerCfg(&fab.PeerConfig{URL: "peer0.org1.example.com:7051", OperationsURL: server.URL})
+	ctx.SetEndpointConfig(config)
+
+	bci, err := proto.Marshal(&common.BlockchainInfo{Height: 10})
+	require.NoError(t, err)
+	peer := &fcmocks.MockPeer{MockName: "peer0", MockURL: "peer0.org1.example.com:7051", MockMSP: "Org1MSP", Status: http.StatusOK, Payload: bci}
+
+	rc := setupResMgmtClient(t, ctx)
+
+	statuses, err := rc.WaitForPeerCatchUp(PeerCatchUpRequest{ChannelID: "mychannel", TargetHeight: 10}, WithTargets(peer))
+	require.NoError(t, err)
+	assert.Equal(t, []PeerCatchUpStatus{{Target: "peer0.org1.example.com:7051", Height: 10, Healthy: true}}, statuses)
+
+	// The target height is determined from the orderer
+	_, err = rc.WaitForPeerCatchUp(PeerCatchUpRequest{ChannelID: "mychannel"}, WithTargets(peer), WithOrderer(newMockConfigOrderer()))
+	require.NoError(t, err)
+
+	// The peer hasn't caught up
+	opts := []RequestOption{WithTargets(peer), WithTimeout(fab.ResMgmt, 100*time.Millisecond)}
+	req := PeerCatchUpRequest{ChannelID: "mychannel", TargetHeight: 11, PollInterval: 10 * time.Millisecond}
+	statuses, err = rc.WaitForPeerCatchUp(req, opts...)
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.EqualValues(t, status.Timeout, s.Code)
+	assert.Contains(t, err.Error(), "is at height 10 of 11")
+	assert.Equal(t, uint64(10), statuses[0].Height)
+
+	// The peer isn't healthy
+	atomic.StoreInt32(&unhealthy, 1)
+	req.TargetHeight = 10
+	statuses, err = rc.WaitForPeerCatchUp(req, opts...)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "isn't healthy")
+	assert.False(t, statuses[0].Healthy)
+
+	_, err = rc.WaitForPeerCatchUp(PeerCatchUpRequest{}, opts...)
+	assert.Error(t, err, "expecting error for missing channel ID")
+}
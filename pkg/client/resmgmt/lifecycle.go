@@ -0,0 +1,689 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	reqContext "context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// alreadyInstalledRegex matches the error returned by peers for a package which is already installed
+var alreadyInstalledRegex = regexp.MustCompile(`chaincode already successfully installed \(package ID '([^']+)'\)`)
+
+// LifecycleCCDefinition contains the parameters of a chaincode definition (Fabric 2.0 chaincode lifecycle)
+type LifecycleCCDefinition struct {
+	Name                string
+	Version             string
+	Sequence            int64
+	EndorsementPlugin   string
+	ValidationPlugin    string
+	SignaturePolicy     *common.SignaturePolicyEnvelope
+	ChannelConfigPolicy string
+	CollConfig          []*common.CollectionConfig
+	// InitRequired indicates that the chaincode must be initialized before it may be invoked, i.e. the first
+	// invocation must be an Execute with IsInit set on the channel request (peer chaincode invoke --isInit)
+	InitRequired bool
+	// ValidationParameter is the parameter of a custom ValidationPlugin. It replaces the SignaturePolicy
+	// or ChannelConfigPolicy, which only apply to the default validation plugin.
+	ValidationParameter []byte
+}
+
+// LifecycleInstallCCRequest contains the parameters for installing a chaincode package
+type LifecycleInstallCCRequest struct {
+	// Package is the chaincode package (tar.gz) as created by 'peer lifecycle chaincode package'
+	Package []byte
+}
+
+// LifecycleInstallCCResponse contains the response of a peer for the install request
+type LifecycleInstallCCResponse struct {
+	Target    string
+	Status    int32
+	PackageID string
+	Info      string
+}
+
+// LifecycleInstalledCC contains the ID and label of a chaincode package installed on a peer
+type LifecycleInstalledCC struct {
+	PackageID string
+	Label     string
+}
+
+// LifecycleApproveCCRequest contains the parameters for approving a chaincode definition for an organization
+type LifecycleApproveCCRequest struct {
+	LifecycleCCDefinition
+	// PackageID is the ID of the installed package (may be empty if the organization's peers
+	// do not need to endorse transactions for the chaincode)
+	PackageID string
+}
+
+// LifecycleQueryApprovedCCRequest contains the parameters for querying an approved chaincode definition
+type LifecycleQueryApprovedCCRequest struct {
+	Name string
+	// Sequence of the approved definition (the latest approved definition is returned if 0)
+	Sequence int64
+}
+
+// LifecycleApprovedCC contains the chaincode definition approved by an organization
+type LifecycleApprovedCC struct {
+	LifecycleCCDefinition
+	PackageID string
+}
+
+// LifecycleQueryCommittedCCRequest contains the parameters for querying committed chaincode definitions
+type LifecycleQueryCommittedCCRequest struct {
+	// Name is the name of the chaincode. The definitions of all chaincodes committed to the channel
+	// are returned if the name is empty.
+	Name string
+}
+
+// LifecycleCommittedCC contains a chaincode definition which is committed to a channel
+type LifecycleCommittedCC struct {
+	LifecycleCCDefinition
+
+	// Approvals contains the approval status (per MSP ID) of the definition. It is only set
+	// if the definition of a single chaincode was queried.
+	Approvals map[string]bool
+}
+
+// LifecycleOrgApproval contains the approval status of a chaincode definition for an organization
+type LifecycleOrgApproval struct {
+	// Approved is true if the organization approved the chaincode definition
+	Approved bool
+
+	// Definition is the definition approved by the organization for the sequence of the requested
+	// definition. It is nil if none of the organization's peers was queried or if the query failed.
+	Definition *LifecycleApprovedCC
+
+	// Error is the error returned by the organization's peer for the approved definition query
+	Error error
+}
+
+// LifecycleApprovalMatrix contains the approval status of a chaincode definition per organization (MSP ID)
+type LifecycleApprovalMatrix map[string]LifecycleOrgApproval
+
+// Blocking returns the MSP IDs of the organizations which have not approved the chaincode definition
+func (m LifecycleApprovalMatrix) Blocking() []string {
+	var mspIDs []string
+	for mspID, approval := range m {
+		if !approval.Approved {
+			mspIDs = append(mspIDs, mspID)
+		}
+	}
+	sort.Strings(mspIDs)
+	return mspIDs
+}
+
+// LifecycleInstallCC installs a chaincode package on the peers of the client's organization. A peer on which the
+// package is already installed is reported with the info 'already installed'. Requires Fabric 2.0 (or later) peers.
+//  Parameters:
+//  req holds the chaincode package
+//  options holds optional request options
+//
+//  Returns:
+//  install chaincode responses from peer(s)
+func (rc *Client) LifecycleInstallCC(req LifecycleInstallCCRequest, options ...RequestOption) ([]LifecycleInstallCCResponse, error) {
+	if len(req.Package) == 0 {
+		return nil, errors.New("chaincode package is required")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get opts for LifecycleInstallCC")
+	}
+
+	defaultTargets, err := rc.resolveDefaultTargets(&opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get default targets for LifecycleInstallCC")
+	}
+
+	targets, err := rc.calculateTargets(defaultTargets, opts.TargetFilter)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to determine target peers for install cc")
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.WithStack(status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "no targets available", nil))
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.ResMgmt)
+	defer cancel()
+
+	var responses []LifecycleInstallCCResponse
+	var errs multi.Errors
+	for _, target := range targets {
+		packageID, err := resource.LifecycleInstallChaincode(reqCtx, req.Package, target, resource.WithRetry(opts.Retry))
+		if err != nil {
+			if matches := alreadyInstalledRegex.FindStringSubmatch(err.Error()); matches != nil {
+				responses = append(responses, LifecycleInstallCCResponse{Target: target.URL(), PackageID: matches[1], Info: "already installed"})
+				continue
+			}
+			errs = append(errs, errors.WithMessage(err, fmt.Sprintf("install on %s failed", target.URL())))
+			continue
+		}
+
+		logger.Debugf("Installed chaincode package [%s] on [%s]", packageID, target.URL())
+		responses = append(responses, LifecycleInstallCCResponse{Target: target.URL(), Status: http.StatusOK, PackageID: packageID})
+	}
+
+	return responses, errs.ToError()
+}
+
+// LifecycleQueryInstalledCC queries the chaincode packages installed on a peer. Requires Fabric 2.0 (or later) peers.
+//  Parameters:
+//  options hold optional request options
+//  Note: One target(peer) has to be specified using either WithTargetURLs or WithTargets request option
+//
+//  Returns:
+//  the installed chaincode packages
+func (rc *Client) LifecycleQueryInstalledCC(options ...RequestOption) ([]LifecycleInstalledCC, error) {
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Targets) != 1 {
+		return nil, errors.New("only one target is supported")
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	installed, err := resource.LifecycleQueryInstalledChaincodes(reqCtx, opts.Targets[0], resource.WithRetry(opts.Retry))
+	if err != nil {
+		return nil, err
+	}
+
+	var ccs []LifecycleInstalledCC
+	for _, cc := range installed {
+		ccs = append(ccs, LifecycleInstalledCC{PackageID: cc.PackageID, Label: cc.Label})
+	}
+	return ccs, nil
+}
+
+// LifecycleVerifyInstalledCC verifies that the chaincode package with the given ID (which may be computed offline using
+// lcpackager.PackageID) is installed on the peers of the client's organization. An error is returned for each peer on
+// which the package is not installed, including the ID of a package with the same label which is installed instead.
+// Requires Fabric 2.0 (or later) peers.
+//  Parameters:
+//  packageID is the expected package ID (<label>:<hash>)
+//  options holds optional request options
+//
+//  Returns:
+//  an error if the package is not installed on all peers
+func (rc *Client) LifecycleVerifyInstalledCC(packageID string, options ...RequestOption) error {
+	if packageID == "" {
+		return errors.New("package ID is required")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return err
+	}
+
+	defaultTargets, err := rc.resolveDefaultTargets(&opts)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get default targets for LifecycleVerifyInstalledCC")
+	}
+
+	targets, err := rc.calculateTargets(defaultTargets, opts.TargetFilter)
+	if err != nil {
+		return errors.WithMessage(err, "failed to determine target peers")
+	}
+
+	if len(targets) == 0 {
+		return errors.WithStack(status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "no targets available", nil))
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	var errs multi.Errors
+	for _, target := range targets {
+		installed, err := resource.LifecycleQueryInstalledChaincodes(reqCtx, target, resource.WithRetry(opts.Retry))
+		if err != nil {
+			errs = append(errs, errors.WithMessage(err, fmt.Sprintf("unable to query installed chaincodes on %s", target.URL())))
+			continue
+		}
+		if err := verifyInstalledPackage(target.URL(), packageID, installed); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs.ToError()
+}
+
+func verifyInstalledPackage(target, packageID string, installed []resource.LifecycleInstalledChaincode) error {
+	label := packageLabel(packageID)
+
+	var mismatched []string
+	for _, cc := range installed {
+		if cc.PackageID == packageID {
+			return nil
+		}
+		if cc.Label == label {
+			mismatched = append(mismatched, cc.PackageID)
+		}
+	}
+
+	if len(mismatched) > 0 {
+		return errors.Errorf("package [%s] is not installed on %s but package(s) %v with the same label are installed", packageID, target, mismatched)
+	}
+	return errors.Errorf("package [%s] is not installed on %s", packageID, target)
+}
+
+// packageLabel returns the label part of a package ID (<label>:<hash>)
+func packageLabel(packageID string) string {
+	if i := strings.LastIndex(packageID, ":"); i >= 0 {
+		return packageID[:i]
+	}
+	return packageID
+}
+
+// LifecycleApproveCC approves a chaincode definition for the client's organization. If peer(s) are not
+// specified in options it will default to the peers of the client's organization. Requires Fabric 2.0 (or later) peers.
+//  Parameters:
+//  channelID is mandatory channel name
+//  req holds the chaincode definition and the ID of the installed package
+//  options holds optional request options
+//
+//  Returns:
+//  the ID of the approve transaction
+func (rc *Client) LifecycleApproveCC(channelID string, req LifecycleApproveCCRequest, options ...RequestOption) (fab.TransactionID, error) {
+	if err := validateLifecycleCCDefinition(channelID, req.LifecycleCCDefinition); err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	targets, err := rc.calculateTargets(opts.Targets, opts.TargetFilter)
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "failed to determine target peers for approve cc")
+	}
+
+	if len(targets) == 0 {
+		return fab.EmptyTransactionID, errors.WithStack(status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "no targets available", nil))
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.ResMgmt)
+	defer cancel()
+
+	return rc.sendLifecycleTransaction(reqCtx, channelID, targets, func(txh *txn.TransactionHeader) (*fab.TransactionProposal, error) {
+		return resource.CreateLifecycleApproveProposal(txh, toResourceCCDefinition(req.LifecycleCCDefinition), req.PackageID)
+	})
+}
+
+// LifecycleCommitCC commits a chaincode definition to the channel once it has been approved by enough organizations.
+// If peer(s) are not specified in options it will default to all channel peers. Requires Fabric 2.0 (or later) peers.
+//  Parameters:
+//  channelID is mandatory channel name
+//  def holds the chaincode definition
+//  options holds optional request options
+//
+//  Returns:
+//  the ID of the commit transaction
+func (rc *Client) LifecycleCommitCC(channelID string, def LifecycleCCDefinition, options ...RequestOption) (fab.TransactionID, error) {
+	if err := validateLifecycleCCDefinition(channelID, def); err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	targets, err := rc.getCCProposalTargets(channelID, opts)
+	if err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.ResMgmt)
+	defer cancel()
+
+	return rc.sendLifecycleTransaction(reqCtx, channelID, targets, func(txh *txn.TransactionHeader) (*fab.TransactionProposal, error) {
+		return resource.CreateLifecycleCommitProposal(txh, toResourceCCDefinition(def))
+	})
+}
+
+// LifecycleQueryApprovedCC queries the chaincode definition approved by the organization of the target peer.
+// Requires Fabric 2.0 (or later) peers.
+//  Parameters:
+//  channelID is mandatory channel name
+//  req holds the chaincode name and the (optional) sequence of the approved definition
+//  options hold optional request options
+//  Note: One target(peer) has to be specified using either WithTargetURLs or WithTargets request option
+//
+//  Returns:
+//  the approved chaincode definition
+func (rc *Client) LifecycleQueryApprovedCC(channelID string, req LifecycleQueryApprovedCCRequest, options ...RequestOption) (LifecycleApprovedCC, error) {
+	if channelID == "" || req.Name == "" {
+		return LifecycleApprovedCC{}, errors.New("channel ID and chaincode name are required")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return LifecycleApprovedCC{}, err
+	}
+
+	if len(opts.Targets) != 1 {
+		return LifecycleApprovedCC{}, errors.New("only one target is supported")
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	approved, err := resource.QueryApprovedChaincodeDefinition(reqCtx, channelID, req.Name, req.Sequence, opts.Targets[0], resource.WithRetry(opts.Retry))
+	if err != nil {
+		return LifecycleApprovedCC{}, err
+	}
+
+	return newLifecycleApprovedCC(approved), nil
+}
+
+// LifecycleQueryCommittedCC queries the chaincode definitions which are committed to the channel, including
+// the sequence, the endorsement and validation plugins, the collections and the init-required flag.
+// Requires Fabric 2.0 (or later) peers.
+//  Parameters:
+//  channelID is mandatory channel name
+//  req holds the (optional) name of the chaincode
+//  options hold optional request options
+//  Note: One target(peer) has to be specified using either WithTargetURLs or WithTargets request option
+//
+//  Returns:
+//  the committed chaincode definitions
+func (rc *Client) LifecycleQueryCommittedCC(channelID string, req LifecycleQueryCommittedCCRequest, options ...RequestOption) ([]LifecycleCommittedCC, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Targets) != 1 {
+		return nil, errors.New("only one target is supported")
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	if req.Name != "" {
+		committed, err := resource.QueryCommittedChaincodeDefinition(reqCtx, channelID, req.Name, opts.Targets[0], resource.WithRetry(opts.Retry))
+		if err != nil {
+			return nil, err
+		}
+		return []LifecycleCommittedCC{newLifecycleCommittedCC(committed)}, nil
+	}
+
+	committed, err := resource.QueryCommittedChaincodeDefinitions(reqCtx, channelID, opts.Targets[0], resource.WithRetry(opts.Retry))
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []LifecycleCommittedCC
+	for i := range committed {
+		definitions = append(definitions, newLifecycleCommittedCC(&committed[i]))
+	}
+	return definitions, nil
+}
+
+// LifecycleCheckCCCommitReadiness returns the approval status (per MSP ID) of a chaincode definition
+// by the organizations of the channel. Requires Fabric 2.0 (or later) peers.
+//  Parameters:
+//  channelID is mandatory channel name
+//  def holds the chaincode definition
+//  options hold optional request options
+//  Note: One target(peer) has to be specified using either WithTargetURLs or WithTargets request option
+//
+//  Returns:
+//  the approval status per MSP ID
+func (rc *Client) LifecycleCheckCCCommitReadiness(channelID string, def LifecycleCCDefinition, options ...RequestOption) (map[string]bool, error) {
+	if err := validateLifecycleCCDefinition(channelID, def); err != nil {
+		return nil, err
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Targets) != 1 {
+		return nil, errors.New("only one target is supported")
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	return resource.CheckCommitReadiness(reqCtx, channelID, toResourceCCDefinition(def), opts.Targets[0], resource.WithRetry(opts.Retry))
+}
+
+// LifecycleQueryApprovalMatrix returns the approval status of a chaincode definition for each organization of
+// the channel together with the definition that each organization approved for the definition's sequence, so
+// that the organizations which block the commit of the definition (and why) may be determined.
+// Requires Fabric 2.0 (or later) peers.
+//  Parameters:
+//  channelID is mandatory channel name
+//  def holds the chaincode definition
+//  options hold optional request options (targets default to the peers of the channel; one peer
+//  per organization is queried for the approved definition)
+//
+//  Returns:
+//  the approval matrix keyed by MSP ID
+func (rc *Client) LifecycleQueryApprovalMatrix(channelID string, def LifecycleCCDefinition, options ...RequestOption) (LifecycleApprovalMatrix, error) {
+	if err := validateLifecycleCCDefinition(channelID, def); err != nil {
+		return nil, err
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := opts.Targets
+	if len(targets) == 0 {
+		targets, err = rc.channelTargets(channelID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(targets) == 0 {
+		return nil, errors.Errorf("no targets available for channel [%s]", channelID)
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	// The commit readiness is the same on all peers of the channel so the first successful response is used
+	var approvals map[string]bool
+	var errs multi.Errors
+	for _, target := range targets {
+		approvals, err = resource.CheckCommitReadiness(reqCtx, channelID, toResourceCCDefinition(def), target, resource.WithRetry(opts.Retry))
+		if err == nil {
+			break
+		}
+		errs = append(errs, err)
+	}
+	if approvals == nil {
+		return nil, errors.WithMessage(errs.ToError(), "failed to check commit readiness")
+	}
+
+	approved := make(map[string]*LifecycleApprovedCC)
+	queryErrs := make(map[string]error)
+	for mspID, target := range targetsByMSP(targets) {
+		definition, err := resource.QueryApprovedChaincodeDefinition(reqCtx, channelID, def.Name, def.Sequence, target, resource.WithRetry(opts.Retry))
+		if err != nil {
+			logger.Debugf("Failed to query approved chaincode definition from [%s]: %s", target.URL(), err)
+			queryErrs[mspID] = err
+			continue
+		}
+		approvedCC := newLifecycleApprovedCC(definition)
+		approved[mspID] = &approvedCC
+	}
+
+	return newLifecycleApprovalMatrix(approvals, approved, queryErrs), nil
+}
+
+// sendLifecycleTransaction endorses the _lifecycle proposal on the given targets and sends the
+// transaction to the orderer, waiting for the transaction to be committed
+func (rc *Client) sendLifecycleTransaction(reqCtx reqContext.Context, channelID string, targets []fab.Peer, createProposal func(txh *txn.TransactionHeader) (*fab.TransactionProposal, error)) (fab.TransactionID, error) {
+	channelService, err := rc.ctx.ChannelProvider().ChannelService(rc.ctx, channelID)
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "Unable to get channel service")
+	}
+
+	transactor, err := channelService.Transactor(reqCtx)
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "get channel transactor failed")
+	}
+
+	txh, err := txn.NewHeader(rc.ctx, channelID)
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "create transaction ID failed")
+	}
+
+	tp, err := createProposal(txh)
+	if err != nil {
+		return txh.TransactionID(), errors.WithMessage(err, "creating _lifecycle transaction proposal failed")
+	}
+
+	txProposalResponse, err := transactor.SendTransactionProposal(tp, peersToTxnProcessors(targets))
+	if err != nil {
+		return tp.TxnID, errors.WithMessage(err, "sending _lifecycle transaction proposal failed")
+	}
+
+	err = rc.verifyTPSignature(channelService, txProposalResponse)
+	if err != nil {
+		return tp.TxnID, errors.WithMessage(err, "sending _lifecycle transaction proposal failed to verify signature")
+	}
+
+	eventService, err := channelService.EventService()
+	if err != nil {
+		return tp.TxnID, errors.WithMessage(err, "unable to get event service")
+	}
+
+	return rc.sendTransactionAndCheckEvent(eventService, tp, txProposalResponse, transactor, reqCtx)
+}
+
+// channelTargets returns the peers of the channel using the channel's discovery service
+func (rc *Client) channelTargets(channelID string) ([]fab.Peer, error) {
+	chCtx, err := contextImpl.NewChannel(
+		func() (context.Client, error) {
+			return rc.ctx, nil
+		},
+		channelID,
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel context")
+	}
+
+	discovery, err := chCtx.ChannelService().Discovery()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get discovery service")
+	}
+
+	// default filter will be applied (if any)
+	return rc.getDefaultTargets(discovery)
+}
+
+// targetsByMSP returns the first peer of each organization
+func targetsByMSP(targets []fab.Peer) map[string]fab.Peer {
+	peers := make(map[string]fab.Peer)
+	for _, target := range targets {
+		if _, ok := peers[target.MSPID()]; !ok {
+			peers[target.MSPID()] = target
+		}
+	}
+	return peers
+}
+
+func newLifecycleApprovalMatrix(approvals map[string]bool, approved map[string]*LifecycleApprovedCC, queryErrs map[string]error) LifecycleApprovalMatrix {
+	matrix := make(LifecycleApprovalMatrix)
+	for mspID, ok := range approvals {
+		matrix[mspID] = LifecycleOrgApproval{Approved: ok}
+	}
+	for mspID, definition := range approved {
+		approval := matrix[mspID]
+		approval.Definition = definition
+		matrix[mspID] = approval
+	}
+	for mspID, err := range queryErrs {
+		approval := matrix[mspID]
+		approval.Error = err
+		matrix[mspID] = approval
+	}
+	return matrix
+}
+
+func validateLifecycleCCDefinition(channelID string, def LifecycleCCDefinition) error {
+	if channelID == "" || def.Name == "" || def.Version == "" {
+		return errors.New("channel ID, chaincode name and version are required")
+	}
+	if def.Sequence < 1 {
+		return errors.New("chaincode sequence must be greater than 0")
+	}
+	return nil
+}
+
+func toResourceCCDefinition(def LifecycleCCDefinition) resource.LifecycleChaincodeDefinition {
+	return resource.LifecycleChaincodeDefinition{
+		Name:                def.Name,
+		Version:             def.Version,
+		Sequence:            def.Sequence,
+		EndorsementPlugin:   def.EndorsementPlugin,
+		ValidationPlugin:    def.ValidationPlugin,
+		SignaturePolicy:     def.SignaturePolicy,
+		ChannelConfigPolicy: def.ChannelConfigPolicy,
+		CollectionConfig:    def.CollConfig,
+		InitRequired:        def.InitRequired,
+		ValidationParameter: def.ValidationParameter,
+	}
+}
+
+func fromResourceCCDefinition(def resource.LifecycleChaincodeDefinition) LifecycleCCDefinition {
+	return LifecycleCCDefinition{
+		Name:                def.Name,
+		Version:             def.Version,
+		Sequence:            def.Sequence,
+		EndorsementPlugin:   def.EndorsementPlugin,
+		ValidationPlugin:    def.ValidationPlugin,
+		SignaturePolicy:     def.SignaturePolicy,
+		ChannelConfigPolicy: def.ChannelConfigPolicy,
+		CollConfig:          def.CollectionConfig,
+		InitRequired:        def.InitRequired,
+		ValidationParameter: def.ValidationParameter,
+	}
+}
+
+func newLifecycleApprovedCC(approved *resource.LifecycleApprovedChaincodeDefinition) LifecycleApprovedCC {
+	return LifecycleApprovedCC{
+		LifecycleCCDefinition: fromResourceCCDefinition(approved.LifecycleChaincodeDefinition),
+		PackageID:             approved.PackageID,
+	}
+}
+
+func newLifecycleCommittedCC(committed *resource.LifecycleCommittedChaincodeDefinition) LifecycleCommittedCC {
+	return LifecycleCommittedCC{
+		LifecycleCCDefinition: fromResourceCCDefinition(committed.LifecycleChaincodeDefinition),
+		Approvals:             committed.Approvals,
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	reqContext "context"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/operations"
+	"github.com/pkg/errors"
+)
+
+// LogSpecResponse contains the logging specification of a peer or orderer
+type LogSpecResponse struct {
+	Target string
+	Spec   string
+}
+
+// QueryLogSpec queries the logging specification (e.g. info:gossip=debug) of peers through their operations
+// endpoints. The operations URL (operationsUrl) must be configured for each of the peers.
+//  Parameters:
+//  options holds optional request options (targets default to the peers of the client's organization)
+//
+//  Returns:
+//  the logging specification of each peer that responded and an error if the query failed on any of the peers
+func (rc *Client) QueryLogSpec(options ...RequestOption) ([]LogSpecResponse, error) {
+	var responses []LogSpecResponse
+	err := rc.sendPeerOperationsRequest(options, func(reqCtx reqContext.Context, client *operations.Client, target string) error {
+		spec, err := client.LogSpec(reqCtx)
+		if err != nil {
+			return err
+		}
+		responses = append(responses, LogSpecResponse{Target: target, Spec: spec})
+		return nil
+	})
+	return responses, err
+}
+
+// SetLogSpec sets the logging specification (e.g. info:gossip=debug) of peers through their operations endpoints.
+// The operations URL (operationsUrl) must be configured for each of the peers.
+//  Parameters:
+//  spec is the logging specification
+//  options holds optional request options (targets default to the peers of the client's organization)
+//
+//  Returns:
+//  an error if the request failed on any of the peers
+func (rc *Client) SetLogSpec(spec string, options ...RequestOption) error {
+	if spec == "" {
+		return errors.New("must provide log spec")
+	}
+	return rc.sendPeerOperationsRequest(options, func(reqCtx reqContext.Context, client *operations.Client, target string) error {
+		return client.SetLogSpec(reqCtx, spec)
+	})
+}
+
+// QueryOrdererLogSpec queries the logging specification of orderers through their operations endpoints.
+// The operations URL (operationsUrl) must be configured for each of the orderers.
+//  Parameters:
+//  options holds optional request options (WithOrdererEndpoint or WithOrderer selects a single orderer,
+//  otherwise all of the configured orderers are queried)
+//
+//  Returns:
+//  the logging specification of each orderer that responded and an error if the query failed on any of the orderers
+func (rc *Client) QueryOrdererLogSpec(options ...RequestOption) ([]LogSpecResponse, error) {
+	var responses []LogSpecResponse
+	err := rc.sendOrdererOperationsRequest(options, func(reqCtx reqContext.Context, client *operations.Client, target string) error {
+		spec, err := client.LogSpec(reqCtx)
+		if err != nil {
+			return err
+		}
+		responses = append(responses, LogSpecResponse{Target: target, Spec: spec})
+		return nil
+	})
+	return responses, err
+}
+
+// SetOrdererLogSpec sets the logging specification of orderers through their operations endpoints.
+// The operations URL (operationsUrl) must be configured for each of the orderers.
+//  Parameters:
+//  spec is the logging specification
+//  options holds optional request options (WithOrdererEndpoint or WithOrderer selects a single orderer,
+//  otherwise the logging specification of all of the configured orderers is set)
+//
+//  Returns:
+//  an error if the request failed on any of the orderers
+func (rc *Client) SetOrdererLogSpec(spec string, options ...RequestOption) error {
+	if spec == "" {
+		return errors.New("must provide log spec")
+	}
+	return rc.sendOrdererOperationsRequest(options, func(reqCtx reqContext.Context, client *operations.Client, target string) error {
+		return client.SetLogSpec(reqCtx, spec)
+	})
+}
+
+type operationsRequestFunc func(reqCtx reqContext.Context, client *operations.Client, target string) error
+
+func (rc *Client) sendPeerOperationsRequest(options []RequestOption, send operationsRequestFunc) error {
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return err
+	}
+
+	targets, err := rc.calculateTargets(opts.Targets, opts.TargetFilter)
+	if err != nil {
+		return errors.WithMessage(err, "failed to determine target peers for operations request")
+	}
+
+	if len(targets) == 0 {
+		return errors.WithStack(status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "no targets available", nil))
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	var errs multi.Errors
+	for _, target := range targets {
+		peerCfg := rc.targetPeerConfig(target)
+		client, err := operations.NewForPeer(rc.ctx.EndpointConfig(), &peerCfg)
+		if err == nil {
+			err = send(reqCtx, client, target.URL())
+		}
+		if err != nil {
+			errs = append(errs, errors.WithMessage(err, "operations request failed on peer ["+target.URL()+"]"))
+		}
+	}
+	return errs.ToError()
+}
+
+func (rc *Client) sendOrdererOperationsRequest(options []RequestOption, send operationsRequestFunc) error {
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return err
+	}
+
+	targets, err := rc.ordererTargets(opts)
+	if err != nil {
+		return err
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.OrdererResponse)
+	defer cancel()
+
+	var errs multi.Errors
+	for i := range targets {
+		target := targets[i]
+		client, err := operations.NewForOrderer(rc.ctx.EndpointConfig(), &target)
+		if err == nil {
+			err = send(reqCtx, client, target.URL)
+		}
+		if err != nil {
+			errs = append(errs, errors.WithMessage(err, "operations request failed on orderer ["+target.URL+"]"))
+		}
+	}
+	return errs.ToError()
+}
+
+// ordererTargets returns the configuration of the requested orderer or of all
+// of the configured orderers if no orderer was requested
+func (rc *Client) ordererTargets(opts requestOptions) ([]fab.OrdererConfig, error) {
+	if opts.Orderer != nil {
+		ordererCfg, ok := rc.ctx.EndpointConfig().OrdererConfig(opts.Orderer.URL())
+		if !ok {
+			return nil, errors.Errorf("orderer not found for url : %s", opts.Orderer.URL())
+		}
+		return []fab.OrdererConfig{*ordererCfg}, nil
+	}
+
+	orderers := rc.ctx.EndpointConfig().OrderersConfig()
+	if len(orderers) == 0 {
+		return nil, errors.New("no orderers found")
+	}
+	return orderers, nil
+}
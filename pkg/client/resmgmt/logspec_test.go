@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockLogSpecServer emulates the logspec resource of the operations endpoint
+type mockLogSpecServer struct {
+	mutex sync.Mutex
+	spec  string
+}
+
+func (s *mockLogSpecServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if r.URL.Path != "/logspec" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		req := struct {
+			Spec string `json:"spec"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.spec = req.Spec
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"spec": s.spec}) // nolint: errcheck
+}
+
+func TestPeerLogSpec(t *testing.T) {
+	logSpecServer := &mockLogSpecServer{spec: "info"}
+	server := httptest.NewServer(logSpecServer)
+	defer server.Close()
+
+	ctx := setupTestContext("test", "Org1MSP")
+	config := fcmocks.NewMockEndpointConfig()
+	config.(*fcmocks.MockConfig).SetCustomPeerCfg(&fab.PeerConfig{URL: "peer0.org1.example.com:7051", OperationsURL: server.URL})
+	ctx.SetEndpointConfig(config)
+
+	peer := fcmocks.NewMockPeer("peer0", "peer0.org1.example.com:7051")
+	rc := setupResMgmtClientWithLocalPeers(t, ctx, []fab.Peer{peer})
+
+	require.NoError(t, rc.SetLogSpec("info:gossip=debug"))
+	assert.Equal(t, "info:gossip=debug", logSpecServer.spec)
+
+	responses, err := rc.QueryLogSpec(WithTargets(peer))
+	require.NoError(t, err)
+	assert.Equal(t, []LogSpecResponse{{Target: "peer0.org1.example.com:7051", Spec: "info:gossip=debug"}}, responses)
+
+	err = rc.SetLogSpec("")
+	assert.Error(t, err, "expecting error for empty log spec")
+
+	// The operations URL of the peer isn't configured
+	config.(*fcmocks.MockConfig).SetCustomPeerCfg(&fab.PeerConfig{URL: "peer0.org1.example.com:7051"})
+	_, err = rc.QueryLogSpec()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "operations URL is not configured")
+
+	// No targets
+	rc = setupResMgmtClient(t, ctx)
+	err = rc.SetLogSpec("debug")
+	assert.Error(t, err, "expecting error since there are no targets")
+}
+
+func TestOrdererLogSpec(t *testing.T) {
+	logSpecServer := &mockLogSpecServer{spec: "info"}
+	server := httptest.NewServer(logSpecServer)
+	defer server.Close()
+
+	ctx := setupTestContext("test", "Org1MSP")
+	config := fcmocks.NewMockEndpointConfig()
+	config.(*fcmocks.MockConfig).SetCustomOrdererCfg(&fab.OrdererConfig{URL: "orderer.example.com:7050", OperationsURL: server.URL})
+	ctx.SetEndpointConfig(config)
+
+	rc := setupResMgmtClient(t, ctx)
+
+	require.NoError(t, rc.SetOrdererLogSpec("warning"))
+	assert.Equal(t, "warning", logSpecServer.spec)
+
+	responses, err := rc.QueryOrdererLogSpec(WithOrdererEndpoint("orderer.example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, []LogSpecResponse{{Target: "orderer.example.com:7050", Spec: "warning"}}, responses)
+
+	_, err = rc.QueryOrdererLogSpec(WithOrdererEndpoint("Invalid"))
+	assert.Error(t, err, "expecting error for unknown orderer")
+}
@@ -36,12 +36,15 @@ const (
 
 // chaincodeDeployRequest holds parameters for creating an instantiate or upgrade chaincode proposal.
 type chaincodeDeployRequest struct {
-	Name       string
-	Path       string
-	Version    string
-	Args       [][]byte
-	Policy     *common.SignaturePolicyEnvelope
-	CollConfig []*common.CollectionConfig
+	Name                string
+	Path                string
+	Version             string
+	Args                [][]byte
+	Policy              *common.SignaturePolicyEnvelope
+	CollConfig          []*common.CollectionConfig
+	EndorsementPlugin   string
+	ValidationPlugin    string
+	ValidationParameter []byte
 }
 
 // createChaincodeDeployProposal creates an instantiate or upgrade chaincode proposal.
@@ -60,14 +63,14 @@ func createChaincodeDeployProposal(txh fab.TransactionHeader, deploy chaincodePr
 	}
 	args = append(args, ccdsBytes)
 
-	chaincodePolicyBytes, err := protos_utils.Marshal(chaincode.Policy)
+	chaincodePolicyBytes, err := validationParameter(chaincode)
 	if err != nil {
-		return nil, errors.WithMessage(err, "marshal of chaincode policy failed")
+		return nil, err
 	}
 	args = append(args, chaincodePolicyBytes)
 
-	args = append(args, []byte(escc))
-	args = append(args, []byte(vscc))
+	args = append(args, []byte(pluginName(chaincode.EndorsementPlugin, escc)))
+	args = append(args, []byte(pluginName(chaincode.ValidationPlugin, vscc)))
 
 	if chaincode.CollConfig != nil {
 		collConfigBytes, err := proto.Marshal(&common.CollectionConfigPackage{Config: chaincode.CollConfig})
@@ -95,3 +98,27 @@ func createChaincodeDeployProposal(txh fab.TransactionHeader, deploy chaincodePr
 	}
 	return txn.CreateChaincodeInvokeProposal(txh, cir)
 }
+
+// validationParameter returns the argument of the validation plugin, i.e. either the
+// custom validation parameter or the marshaled chaincode policy
+func validationParameter(chaincode chaincodeDeployRequest) ([]byte, error) {
+	if chaincode.ValidationParameter != nil {
+		if chaincode.Policy != nil {
+			return nil, errors.New("only one of chaincode policy and validation parameter may be specified")
+		}
+		return chaincode.ValidationParameter, nil
+	}
+
+	chaincodePolicyBytes, err := protos_utils.Marshal(chaincode.Policy)
+	if err != nil {
+		return nil, errors.WithMessage(err, "marshal of chaincode policy failed")
+	}
+	return chaincodePolicyBytes, nil
+}
+
+func pluginName(name, defaultName string) string {
+	if name == "" {
+		return defaultName
+	}
+	return name
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/pkg/errors"
 )
 
@@ -117,6 +118,18 @@ func WithParentContext(parentContext reqContext.Context) RequestOption {
 	}
 }
 
+// WithGenesisBlock sets the genesis block with which the peers are joined to the channel (see JoinChannel).
+// By default the genesis block is fetched from the orderer.
+func WithGenesisBlock(block *common.Block) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if block == nil {
+			return errors.New("genesis block is nil")
+		}
+		o.GenesisBlock = block
+		return nil
+	}
+}
+
 // WithRetry sets retry options.
 func WithRetry(retryOpt retry.Opts) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
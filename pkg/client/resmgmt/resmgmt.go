@@ -20,13 +20,14 @@ SPDX-License-Identifier: Apache-2.0
 package resmgmt
 
 import (
+	"bytes"
 	reqContext "context"
 	"io"
 	"io/ioutil"
-	"math/rand"
 	"os"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
@@ -34,6 +35,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
@@ -44,6 +46,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/random"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
 )
@@ -71,6 +74,12 @@ type InstantiateCCRequest struct {
 	Args       [][]byte
 	Policy     *common.SignaturePolicyEnvelope
 	CollConfig []*common.CollectionConfig
+	// EndorsementPlugin and ValidationPlugin are the names of the endorsement and validation plugins
+	// of the chaincode (escc and vscc by default)
+	EndorsementPlugin string
+	ValidationPlugin  string
+	// ValidationParameter is passed to a custom validation plugin instead of the marshaled Policy
+	ValidationParameter []byte
 }
 
 // InstantiateCCResponse contains response parameters for instantiate chaincode
@@ -86,6 +95,12 @@ type UpgradeCCRequest struct {
 	Args       [][]byte
 	Policy     *common.SignaturePolicyEnvelope
 	CollConfig []*common.CollectionConfig
+	// EndorsementPlugin and ValidationPlugin are the names of the endorsement and validation plugins
+	// of the chaincode (escc and vscc by default)
+	EndorsementPlugin string
+	ValidationPlugin  string
+	// ValidationParameter is passed to a custom validation plugin instead of the marshaled Policy
+	ValidationParameter []byte
 }
 
 // UpgradeCCResponse contains response parameters for upgrade chaincode
@@ -101,6 +116,7 @@ type requestOptions struct {
 	Timeouts      map[fab.TimeoutType]time.Duration //timeout options for resmgmt operations
 	ParentContext reqContext.Context                //parent grpc context for resmgmt operations
 	Retry         retry.Opts
+	GenesisBlock  *common.Block // genesis block used for joining peers (fetched from the orderer if not set)
 }
 
 //SaveChannelRequest holds parameters for save channel request
@@ -109,7 +125,8 @@ type SaveChannelRequest struct {
 	ChannelConfig     io.Reader             // ChannelConfig data source
 	ChannelConfigPath string                // Convenience option to use the named file as ChannelConfig reader
 	SigningIdentities []msp.SigningIdentity // Users that sign channel configuration
-	// TODO: support pre-signed signature blocks
+	// The signatures already contained in the channel configuration (e.g. collected with
+	// configtx.Editor.ConfigUpdateEnvelope) are submitted along with those of the signing identities
 }
 
 // SaveChannelResponse contains response parameters for save channel
@@ -117,6 +134,28 @@ type SaveChannelResponse struct {
 	TransactionID fab.TransactionID
 }
 
+// UpdateChannelConfigRequest holds parameters for update channel config request
+type UpdateChannelConfigRequest struct {
+	ChannelID         string
+	Update            func(editor *configtx.Editor) error // Applies changes to the channel config
+	SigningIdentities []msp.SigningIdentity               // Users that sign the config update
+}
+
+// ConsenterRequest holds parameters for add/remove orderer consenter requests
+type ConsenterRequest struct {
+	ChannelID         string
+	Consenter         configtx.Consenter
+	SigningIdentities []msp.SigningIdentity // Orderer admins that sign the config update
+}
+
+// RevocationListRequest holds parameters for installing a CRL into the MSP of an organization
+type RevocationListRequest struct {
+	ChannelID         string
+	Org               string                // Name or MSP ID of the organization
+	CRL               []byte                // PEM or DER encoded CRL signed by a CA of the organization
+	SigningIdentities []msp.SigningIdentity // Admins that sign the config update
+}
+
 //RequestOption func for each Opts argument
 type RequestOption func(ctx context.Client, opts *requestOptions) error
 
@@ -187,6 +226,7 @@ func New(ctxProvider context.ClientProvider, opts ...ClientOption) (*Client, err
 }
 
 // JoinChannel allows for peers to join existing channel with optional custom options (specific peers, filtered peers). If peer(s) are not specified in options it will default to all peers that belong to client's MSP.
+// The genesis block is fetched from the orderer unless it's provided with WithGenesisBlock.
 //  Parameters:
 //  channel is manadatory channel name
 //  options holds optional request options
@@ -221,9 +261,21 @@ func (rc *Client) JoinChannel(channelID string, options ...RequestOption) error
 		return errors.WithStack(status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "no targets available", nil))
 	}
 
+	genesisBlock := opts.GenesisBlock
+	if genesisBlock == nil {
+		genesisBlock, err = rc.genesisBlockFromOrderer(parentReqCtx, channelID, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rc.joinPeers(parentReqCtx, genesisBlock, targets, opts)
+}
+
+func (rc *Client) genesisBlockFromOrderer(parentReqCtx reqContext.Context, channelID string, opts requestOptions) (*common.Block, error) {
 	orderer, err := rc.requestOrderer(&opts, channelID)
 	if err != nil {
-		return errors.WithMessage(err, "failed to find orderer for request")
+		return nil, errors.WithMessage(err, "failed to find orderer for request")
 	}
 
 	ordrReqCtx, ordrReqCtxCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeoutType(fab.OrdererResponse), contextImpl.WithParent(parentReqCtx))
@@ -231,16 +283,20 @@ func (rc *Client) JoinChannel(channelID string, options ...RequestOption) error
 
 	genesisBlock, err := resource.GenesisBlockFromOrderer(ordrReqCtx, channelID, orderer, resource.WithRetry(opts.Retry))
 	if err != nil {
-		return errors.WithMessage(err, "genesis block retrieval failed")
+		return nil, errors.WithMessage(err, "genesis block retrieval failed")
 	}
+	return genesisBlock, nil
+}
 
+// joinPeers joins the target peers to the channel of the given genesis block
+func (rc *Client) joinPeers(parentReqCtx reqContext.Context, genesisBlock *common.Block, targets []fab.Peer, opts requestOptions) error {
 	joinChannelRequest := resource.JoinChannelRequest{
 		GenesisBlock: genesisBlock,
 	}
 
 	peerReqCtx, peerReqCtxCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeoutType(fab.ResMgmt), contextImpl.WithParent(parentReqCtx))
 	defer peerReqCtxCancel()
-	err = resource.JoinChannel(peerReqCtx, joinChannelRequest, peersToTxnProcessors(targets), resource.WithRetry(opts.Retry))
+	err := resource.JoinChannel(peerReqCtx, joinChannelRequest, peersToTxnProcessors(targets), resource.WithRetry(opts.Retry))
 	if err != nil {
 		return errors.WithMessage(err, "join channel failed")
 	}
@@ -449,7 +505,7 @@ func (rc *Client) adjustTargets(targets []fab.Peer, req InstallCCRequest, retry
 		installed, err1 := rc.isChaincodeInstalled(reqCtx, req, target, retry)
 		if err1 != nil {
 			// Add to errors with unable to verify error message
-			errs = append(errs, errors.Errorf("unable to verify if cc is installed on %s. Got error: %s", target.URL(), err1))
+			errs = append(errs, multi.NewTargetError(target.URL(), target.MSPID(), errors.Errorf("unable to verify if cc is installed on %s. Got error: %s", target.URL(), err1)))
 			continue
 		}
 		if installed {
@@ -590,7 +646,7 @@ func (rc *Client) QueryInstantiatedChaincodes(channelID string, options ...Reque
 		}
 
 		// select random channel peer
-		randomNumber := rand.Intn(len(targets))
+		randomNumber := random.Intn(len(targets))
 		target = targets[randomNumber]
 	}
 
@@ -644,7 +700,7 @@ func (rc *Client) QueryChannels(options ...RequestOption) (*pb.ChannelQueryRespo
 }
 
 // validateSendCCProposal
-func (rc *Client) getCCProposalTargets(channelID string, req InstantiateCCRequest, opts requestOptions) ([]fab.Peer, error) {
+func (rc *Client) getCCProposalTargets(channelID string, opts requestOptions) ([]fab.Peer, error) {
 
 	chCtx, err := contextImpl.NewChannel(
 		func() (context.Client, error) {
@@ -719,7 +775,7 @@ func (rc *Client) sendCCProposal(reqCtx reqContext.Context, ccProposalType chain
 		return fab.EmptyTransactionID, err
 	}
 
-	targets, err := rc.getCCProposalTargets(channelID, req, opts)
+	targets, err := rc.getCCProposalTargets(channelID, opts)
 	if err != nil {
 		return fab.EmptyTransactionID, err
 	}
@@ -873,10 +929,16 @@ func (rc *Client) SaveChannel(req SaveChannelRequest, options ...RequestOption)
 		return SaveChannelResponse{}, errors.WithMessage(err, "failed to find orderer for request")
 	}
 
+	existingSignatures, err := resource.ExtractConfigSignatures(configTx)
+	if err != nil {
+		return SaveChannelResponse{}, errors.WithMessage(err, "extracting channel config signatures failed")
+	}
+
 	configSignatures, err := rc.getConfigSignatures(req, chConfig)
 	if err != nil {
 		return SaveChannelResponse{}, err
 	}
+	configSignatures = mergeConfigSignatures(existingSignatures, configSignatures)
 
 	request := resource.CreateChannelRequest{
 		Name:       req.ChannelID,
@@ -941,6 +1003,39 @@ func (rc *Client) getConfigSignatures(req SaveChannelRequest, chConfig []byte) (
 
 }
 
+// mergeConfigSignatures appends the new signatures to the existing ones, skipping a new signature
+// if the channel config has already been signed by the same identity
+func mergeConfigSignatures(existing, signatures []*common.ConfigSignature) []*common.ConfigSignature {
+	merged := append([]*common.ConfigSignature{}, existing...)
+	for _, signature := range signatures {
+		if !signedBy(existing, configSignatureCreator(signature)) {
+			merged = append(merged, signature)
+		}
+	}
+	return merged
+}
+
+func signedBy(signatures []*common.ConfigSignature, creator []byte) bool {
+	if len(creator) == 0 {
+		return false
+	}
+	for _, signature := range signatures {
+		if bytes.Equal(configSignatureCreator(signature), creator) {
+			return true
+		}
+	}
+	return false
+}
+
+func configSignatureCreator(signature *common.ConfigSignature) []byte {
+	header := &common.SignatureHeader{}
+	if err := proto.Unmarshal(signature.SignatureHeader, header); err != nil {
+		logger.Debugf("unmarshal config signature header failed: %s", err)
+		return nil
+	}
+	return header.Creator
+}
+
 func loggedClose(c io.Closer) {
 	err := c.Close()
 	if err != nil {
@@ -979,6 +1074,277 @@ func (rc *Client) QueryConfigFromOrderer(channelID string, options ...RequestOpt
 
 }
 
+// QueryConfigBlockFromOrderer returns the latest channel config block from orderer. If orderer is not provided using options it will be defaulted to channel orderer (if configured) or random orderer from configuration.
+//  Parameters:
+//  channelID is mandatory channel ID
+//  options holds optional request options
+//
+//  Returns:
+//  channel config block
+func (rc *Client) QueryConfigBlockFromOrderer(channelID string, options ...RequestOption) (*common.Block, error) {
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	orderer, err := rc.requestOrderer(&opts, channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to find orderer for request")
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.OrdererResponse)
+	defer cancel()
+
+	return resource.LastConfigFromOrderer(reqCtx, channelID, orderer, resource.WithRetry(opts.Retry))
+}
+
+// QueryBlockFromOrderer returns the block with the given number from the orderer's deliver service. The orderer
+// waits for the block if it hasn't been cut yet (until the orderer response timeout expires). If orderer is not
+// provided using options it will be defaulted to channel orderer (if configured) or random orderer from configuration.
+//  Parameters:
+//  channelID is mandatory channel ID
+//  blockNumber is the number of the block
+//  options holds optional request options
+//
+//  Returns:
+//  the block
+func (rc *Client) QueryBlockFromOrderer(channelID string, blockNumber uint64, options ...RequestOption) (*common.Block, error) {
+	return rc.queryBlockFromOrderer(channelID, options, func(reqCtx reqContext.Context, orderer fab.Orderer, opts ...resource.Opt) (*common.Block, error) {
+		return resource.BlockFromOrderer(reqCtx, channelID, orderer, blockNumber, opts...)
+	})
+}
+
+// QueryNewestBlockFromOrderer returns the newest block of the channel from the orderer's deliver service. If orderer
+// is not provided using options it will be defaulted to channel orderer (if configured) or random orderer from configuration.
+//  Parameters:
+//  channelID is mandatory channel ID
+//  options holds optional request options
+//
+//  Returns:
+//  the newest block
+func (rc *Client) QueryNewestBlockFromOrderer(channelID string, options ...RequestOption) (*common.Block, error) {
+	return rc.queryBlockFromOrderer(channelID, options, func(reqCtx reqContext.Context, orderer fab.Orderer, opts ...resource.Opt) (*common.Block, error) {
+		return resource.NewestBlockFromOrderer(reqCtx, channelID, orderer, opts...)
+	})
+}
+
+// QueryGenesisBlockFromOrderer returns the genesis (oldest) block of the channel from the orderer's deliver service.
+// The block may be passed to JoinChannel with WithGenesisBlock so that it's fetched only once when joining the peers
+// of several organizations. If orderer is not provided using options it will be defaulted to channel orderer
+// (if configured) or random orderer from configuration.
+//  Parameters:
+//  channelID is mandatory channel ID
+//  options holds optional request options
+//
+//  Returns:
+//  the genesis block
+func (rc *Client) QueryGenesisBlockFromOrderer(channelID string, options ...RequestOption) (*common.Block, error) {
+	return rc.queryBlockFromOrderer(channelID, options, func(reqCtx reqContext.Context, orderer fab.Orderer, opts ...resource.Opt) (*common.Block, error) {
+		return resource.GenesisBlockFromOrderer(reqCtx, channelID, orderer, opts...)
+	})
+}
+
+type blockRetriever func(reqCtx reqContext.Context, orderer fab.Orderer, opts ...resource.Opt) (*common.Block, error)
+
+func (rc *Client) queryBlockFromOrderer(channelID string, options []RequestOption, retrieve blockRetriever) (*common.Block, error) {
+	if channelID == "" {
+		return nil, errors.New("must provide channel ID")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	orderer, err := rc.requestOrderer(&opts, channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to find orderer for request")
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.OrdererResponse)
+	defer cancel()
+
+	block, err := retrieve(reqCtx, orderer, resource.WithRetry(opts.Retry))
+	if err != nil {
+		return nil, errors.WithMessage(err, "block retrieval failed")
+	}
+	return block, nil
+}
+
+// QueryDecodedConfigFromOrderer returns the latest channel configuration from orderer decoded into Go structs
+// (application organizations, policies, ACLs, orderer settings, etc.). If orderer is not provided using options
+// it will be defaulted to channel orderer (if configured) or random orderer from configuration.
+//  Parameters:
+//  channelID is mandatory channel ID
+//  options holds optional request options
+//
+//  Returns:
+//  decoded channel configuration
+func (rc *Client) QueryDecodedConfigFromOrderer(channelID string, options ...RequestOption) (*configtx.Config, error) {
+
+	block, err := rc.QueryConfigBlockFromOrderer(channelID, options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "QueryConfigBlock failed")
+	}
+
+	return configtx.DecodeConfigBlock(block)
+}
+
+// UpdateChannelConfig queries the latest channel configuration from orderer, applies the changes
+// of the given update and saves the resulting config update (signed by the signing identities).
+//  Parameters:
+//  req holds info about mandatory channel ID, update function and optional signing identities
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID
+func (rc *Client) UpdateChannelConfig(req UpdateChannelConfigRequest, options ...RequestOption) (SaveChannelResponse, error) {
+
+	if req.ChannelID == "" || req.Update == nil {
+		return SaveChannelResponse{}, errors.New("must provide channel ID and config update")
+	}
+
+	config, err := rc.QueryDecodedConfigFromOrderer(req.ChannelID, options...)
+	if err != nil {
+		return SaveChannelResponse{}, err
+	}
+	config.ChannelID = req.ChannelID
+
+	editor, err := configtx.NewEditor(config)
+	if err != nil {
+		return SaveChannelResponse{}, errors.WithMessage(err, "creating config editor failed")
+	}
+
+	if err := req.Update(editor); err != nil {
+		return SaveChannelResponse{}, errors.WithMessage(err, "updating channel config failed")
+	}
+
+	configUpdate, err := editor.ComputeUpdate()
+	if err != nil {
+		return SaveChannelResponse{}, errors.WithMessage(err, "computing config update failed")
+	}
+
+	configTx, err := configtx.CreateConfigUpdateEnvelope(req.ChannelID, configUpdate)
+	if err != nil {
+		return SaveChannelResponse{}, err
+	}
+
+	return rc.SaveChannel(SaveChannelRequest{
+		ChannelID:         req.ChannelID,
+		ChannelConfig:     bytes.NewReader(configTx),
+		SigningIdentities: req.SigningIdentities,
+	}, options...)
+}
+
+// AddConsenter adds a consenter to the Raft (etcdraft) or BFT ordering service of the channel.
+// A BFT consenter also requires the MSP ID and identity of the ordering node.
+//  Parameters:
+//  req holds info about mandatory channel ID, consenter and optional signing identities
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID
+func (rc *Client) AddConsenter(req ConsenterRequest, options ...RequestOption) (SaveChannelResponse, error) {
+	return rc.UpdateChannelConfig(UpdateChannelConfigRequest{
+		ChannelID: req.ChannelID,
+		Update: func(editor *configtx.Editor) error {
+			return editor.AddConsenter(req.Consenter)
+		},
+		SigningIdentities: req.SigningIdentities,
+	}, options...)
+}
+
+// RemoveConsenter removes a consenter (identified by host and port) from the Raft (etcdraft) or BFT ordering service of the channel.
+//  Parameters:
+//  req holds info about mandatory channel ID, consenter and optional signing identities
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID
+func (rc *Client) RemoveConsenter(req ConsenterRequest, options ...RequestOption) (SaveChannelResponse, error) {
+	return rc.UpdateChannelConfig(UpdateChannelConfigRequest{
+		ChannelID: req.ChannelID,
+		Update: func(editor *configtx.Editor) error {
+			return editor.RemoveConsenter(req.Consenter.Host, req.Consenter.Port)
+		},
+		SigningIdentities: req.SigningIdentities,
+	}, options...)
+}
+
+// UpdateAnchorPeers replaces the anchor peers of an application organization of the channel.
+// The config update is computed from the latest channel configuration, signed by the client
+// identity (which must be an admin of the organization) and submitted to the orderer.
+//  Parameters:
+//  channelID is mandatory channel ID
+//  org is the name or MSP ID of the organization
+//  anchorPeers are the new anchor peers of the organization (an empty list removes the anchor peers)
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID
+func (rc *Client) UpdateAnchorPeers(channelID, org string, anchorPeers []configtx.AnchorPeer, options ...RequestOption) (SaveChannelResponse, error) {
+	if org == "" {
+		return SaveChannelResponse{}, errors.New("must provide organization")
+	}
+
+	return rc.UpdateChannelConfig(UpdateChannelConfigRequest{
+		ChannelID: channelID,
+		Update: func(editor *configtx.Editor) error {
+			orgName, err := resolveOrgName(editor, org)
+			if err != nil {
+				return err
+			}
+			return editor.SetAnchorPeers(orgName, anchorPeers)
+		},
+	}, options...)
+}
+
+// UpdateRevocationList installs a CRL into the MSP of an organization of the channel (in both the
+// application and orderer organizations if the organization belongs to both), replacing the
+// previous CRL of the same CA. Peers and orderers reject the revoked certificates once the
+// config update is committed.
+//  Parameters:
+//  req holds info about mandatory channel ID, organization and CRL, and optional signing identities
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID
+func (rc *Client) UpdateRevocationList(req RevocationListRequest, options ...RequestOption) (SaveChannelResponse, error) {
+	if req.Org == "" || len(req.CRL) == 0 {
+		return SaveChannelResponse{}, errors.New("must provide organization and CRL")
+	}
+
+	return rc.UpdateChannelConfig(UpdateChannelConfigRequest{
+		ChannelID: req.ChannelID,
+		Update: func(editor *configtx.Editor) error {
+			return editor.AddRevocationList(req.Org, req.CRL)
+		},
+		SigningIdentities: req.SigningIdentities,
+	}, options...)
+}
+
+// resolveOrgName returns the name of the application organization with the given name or MSP ID
+func resolveOrgName(editor *configtx.Editor, org string) (string, error) {
+	config, err := editor.Config()
+	if err != nil {
+		return "", err
+	}
+	if config.Application == nil {
+		return "", errors.New("channel config doesn't contain an application group")
+	}
+
+	if _, ok := config.Application.Organizations[org]; ok {
+		return org, nil
+	}
+	for name, o := range config.Application.Organizations {
+		if o.MSPID == org {
+			return name, nil
+		}
+	}
+	return "", errors.Errorf("organization [%s] not found in channel config", org)
+}
+
 func (rc *Client) requestOrderer(opts *requestOptions, channelID string) (fab.Orderer, error) {
 	if opts.Orderer != nil {
 		return opts.Orderer, nil
@@ -1014,7 +1380,7 @@ func (rc *Client) ordererConfig(channelID string) (*fab.OrdererConfig, error) {
 	}
 
 	// random channel orderer
-	randomNumber := rand.Intn(len(orderers))
+	randomNumber := random.Intn(len(orderers))
 	return &orderers[randomNumber], nil
 }
 
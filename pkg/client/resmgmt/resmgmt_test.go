@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package resmgmt
 
 import (
+	reqContext "context"
 	"fmt"
 	"net/http"
 	"os"
@@ -29,6 +30,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks"
 	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
@@ -158,6 +160,24 @@ func TestJoinChannelWithFilter(t *testing.T) {
 	}
 }
 
+func TestJoinChannelWithGenesisBlock(t *testing.T) {
+	srv := &fcmocks.MockEndorserServer{}
+	addr := srv.Start(testAddress)
+	defer srv.Stop()
+
+	ctx := setupTestContext("test", "Org1MSP")
+	rc := setupResMgmtClient(t, ctx)
+
+	peer1, _ := peer.New(fcmocks.NewMockEndpointConfig(), peer.WithURL("grpc://"+addr))
+
+	// The genesis block isn't fetched from the orderer
+	err := rc.JoinChannel("mychannel", WithTargets(peer1), WithGenesisBlock(fcmocks.NewSimpleMockBlock()))
+	assert.NoError(t, err)
+
+	err = rc.JoinChannel("mychannel", WithTargets(peer1), WithGenesisBlock(nil))
+	assert.Error(t, err)
+}
+
 func TestNoSigningUserFailure(t *testing.T) {
 
 	// Setup client without MSP
@@ -812,6 +832,42 @@ func TestInstantiateCCWithOpts(t *testing.T) {
 	}
 }
 
+func TestInstantiateCCPlugins(t *testing.T) {
+
+	rc := setupDefaultResMgmtClient(t)
+
+	deployArgs := func(req InstantiateCCRequest) [][]byte {
+		tp, _, err := rc.createTP(req, "mychannel", InstantiateChaincode)
+		if err != nil {
+			t.Fatalf("Failed to create deploy proposal: %s", err)
+		}
+		cpp := &pb.ChaincodeProposalPayload{}
+		if err := proto.Unmarshal(tp.Proposal.Payload, cpp); err != nil {
+			t.Fatalf("Failed to unmarshal chaincode proposal payload: %s", err)
+		}
+		cis := &pb.ChaincodeInvocationSpec{}
+		if err := proto.Unmarshal(cpp.Input, cis); err != nil {
+			t.Fatalf("Failed to unmarshal chaincode invocation spec: %s", err)
+		}
+		// Args are: fcn, channel, CDS, validation parameter, escc, vscc
+		return cis.ChaincodeSpec.Input.Args
+	}
+
+	// Default plugins
+	args := deployArgs(InstantiateCCRequest{Name: "name", Version: "version", Path: "path", Policy: cauthdsl.SignedByMspMember("Org1MSP")})
+	assert.Equal(t, "escc", string(args[4]))
+	assert.Equal(t, "vscc", string(args[5]))
+
+	// Custom plugins with custom validation parameter
+	args = deployArgs(InstantiateCCRequest{Name: "name", Version: "version", Path: "path", EndorsementPlugin: "tokenescc", ValidationPlugin: "tokenvscc", ValidationParameter: []byte("params")})
+	assert.Equal(t, "params", string(args[3]))
+	assert.Equal(t, "tokenescc", string(args[4]))
+	assert.Equal(t, "tokenvscc", string(args[5]))
+
+	_, _, err := rc.createTP(InstantiateCCRequest{Name: "name", Version: "version", Path: "path", Policy: cauthdsl.SignedByMspMember("Org1MSP"), ValidationParameter: []byte("params")}, "mychannel", InstantiateChaincode)
+	assert.Error(t, err, "expecting error since both policy and validation parameter are specified")
+}
+
 func TestUpgradeCCRequiredParameters(t *testing.T) {
 
 	rc := setupDefaultResMgmtClient(t)
@@ -1239,6 +1295,24 @@ func TestSaveChannelWithMultipleSigningIdenities(t *testing.T) {
 	assert.NotEmpty(t, resp.TransactionID, "transaction ID should be populated")
 }
 
+func TestMergeConfigSignatures(t *testing.T) {
+	signature1 := newTestConfigSignature(t, "user1")
+	signature2 := newTestConfigSignature(t, "user2")
+	signature1Again := newTestConfigSignature(t, "user1")
+
+	merged := mergeConfigSignatures([]*common.ConfigSignature{signature1}, []*common.ConfigSignature{signature2, signature1Again})
+	assert.Equal(t, []*common.ConfigSignature{signature1, signature2}, merged, "expecting existing signatures to be kept and duplicate signers to be skipped")
+
+	merged = mergeConfigSignatures(nil, []*common.ConfigSignature{signature1})
+	assert.Equal(t, []*common.ConfigSignature{signature1}, merged)
+}
+
+func newTestConfigSignature(t *testing.T, creator string) *common.ConfigSignature {
+	header, err := proto.Marshal(&common.SignatureHeader{Creator: []byte(creator), Nonce: []byte(creator)})
+	assert.Nil(t, err)
+	return &common.ConfigSignature{SignatureHeader: header, Signature: []byte("signature of " + creator)}
+}
+
 func createClientContext(fabCtx context.Client) context.ClientProvider {
 	return func() (context.Client, error) {
 		return fabCtx, nil
@@ -1350,3 +1424,169 @@ type endpointConfigEntity struct {
 	Orderers map[string]fabImpl.OrdererConfig
 	Channels map[string]fabImpl.ChannelEndpointConfig
 }
+
+// mockConfigOrderer delivers the same config block for every deliver request
+type mockConfigOrderer struct {
+	*fcmocks.MockOrderer
+	block *common.Block
+}
+
+func (o *mockConfigOrderer) SendDeliver(ctx reqContext.Context, envelope *fab.SignedEnvelope) (chan *common.Block, chan error) {
+	blocks := make(chan *common.Block, 1)
+	blocks <- o.block
+	close(blocks)
+	return blocks, make(chan error)
+}
+
+func newMockConfigOrderer() *mockConfigOrderer {
+	builder := &fcmocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: fcmocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org1MSP", "Org2MSP"},
+			OrdererAddress: "localhost:7050",
+			RootCA:         validRootCA,
+		},
+	}
+
+	return &mockConfigOrderer{
+		MockOrderer: fcmocks.NewMockOrderer("", nil),
+		block:       builder.Build(),
+	}
+}
+
+func TestQueryDecodedConfigFromOrderer(t *testing.T) {
+	ctx := setupTestContext("test", "Org1MSP")
+	rc := setupResMgmtClient(t, ctx)
+
+	config, err := rc.QueryDecodedConfigFromOrderer("mychannel", WithOrderer(newMockConfigOrderer()))
+	if err != nil {
+		t.Fatalf("QueryDecodedConfigFromOrderer failed: %s", err)
+	}
+	if !assert.NotNil(t, config.Application) {
+		return
+	}
+	assert.Len(t, config.Application.Organizations, 2)
+	assert.Equal(t, []string{"localhost:7050"}, config.OrdererAddresses)
+}
+
+func TestQueryBlockFromOrderer(t *testing.T) {
+	ctx := setupTestContext("test", "Org1MSP")
+	rc := setupResMgmtClient(t, ctx)
+	orderer := newMockConfigOrderer()
+
+	block, err := rc.QueryBlockFromOrderer("mychannel", 5, WithOrderer(orderer))
+	assert.NoError(t, err)
+	assert.Equal(t, orderer.block, block)
+
+	block, err = rc.QueryNewestBlockFromOrderer("mychannel", WithOrderer(orderer))
+	assert.NoError(t, err)
+	assert.Equal(t, orderer.block, block)
+
+	block, err = rc.QueryGenesisBlockFromOrderer("mychannel", WithOrderer(orderer))
+	assert.NoError(t, err)
+	assert.Equal(t, orderer.block, block)
+
+	_, err = rc.QueryGenesisBlockFromOrderer("", WithOrderer(orderer))
+	assert.Error(t, err, "expecting error for missing channel ID")
+}
+
+func TestUpdateChannelConfigErrors(t *testing.T) {
+	ctx := setupTestContext("test", "Org1MSP")
+	rc := setupResMgmtClient(t, ctx)
+
+	_, err := rc.UpdateChannelConfig(UpdateChannelConfigRequest{ChannelID: "mychannel"})
+	assert.Error(t, err, "expecting error for missing update")
+
+	_, err = rc.UpdateChannelConfig(UpdateChannelConfigRequest{
+		ChannelID: "mychannel",
+		Update:    func(editor *configtx.Editor) error { return nil },
+	}, WithOrderer(newMockConfigOrderer()))
+	assert.Error(t, err, "expecting error since nothing was changed")
+	assert.Contains(t, err.Error(), "no differences detected")
+
+	_, err = rc.AddConsenter(ConsenterRequest{
+		ChannelID: "mychannel",
+		Consenter: configtx.Consenter{Host: "orderer2.example.com", Port: 7050, ClientTLSCert: []byte("c"), ServerTLSCert: []byte("s")},
+	}, WithOrderer(newMockConfigOrderer()))
+	assert.Error(t, err, "expecting error since the mock consensus type is neither etcdraft nor BFT")
+	assert.Contains(t, err.Error(), "consenters are not supported")
+}
+
+func TestUpdateAnchorPeers(t *testing.T) {
+	ctx := setupTestContext("test", "Org1MSP")
+	rc := setupResMgmtClient(t, ctx)
+
+	anchorPeers := []configtx.AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}}
+
+	_, err := rc.UpdateAnchorPeers("mychannel", "", anchorPeers)
+	assert.Error(t, err, "expecting error for missing organization")
+
+	_, err = rc.UpdateAnchorPeers("", "Org1MSP", anchorPeers)
+	assert.Error(t, err, "expecting error for missing channel ID")
+
+	_, err = rc.UpdateAnchorPeers("mychannel", "Org3MSP", anchorPeers, WithOrderer(newMockConfigOrderer()))
+	assert.Error(t, err, "expecting error for unknown organization")
+	assert.Contains(t, err.Error(), "organization [Org3MSP] not found")
+
+	_, err = rc.UpdateAnchorPeers("mychannel", "Org1MSP", anchorPeers, WithOrderer(newMockConfigOrderer()))
+	assert.NoError(t, err)
+}
+
+func TestUpdateRevocationList(t *testing.T) {
+	ctx := setupTestContext("test", "Org1MSP")
+	rc := setupResMgmtClient(t, ctx)
+
+	_, err := rc.UpdateRevocationList(RevocationListRequest{ChannelID: "mychannel", Org: "Org1MSP"})
+	assert.Error(t, err, "expecting error for missing CRL")
+
+	_, err = rc.UpdateRevocationList(RevocationListRequest{ChannelID: "mychannel", CRL: []byte("crl")})
+	assert.Error(t, err, "expecting error for missing organization")
+
+	_, err = rc.UpdateRevocationList(RevocationListRequest{ChannelID: "mychannel", Org: "Org1MSP", CRL: []byte("crl")}, WithOrderer(newMockConfigOrderer()))
+	assert.Error(t, err, "expecting error for invalid CRL")
+	assert.Contains(t, err.Error(), "invalid CRL")
+}
+
+func TestLifecycleApprovalMatrix(t *testing.T) {
+	rc := setupResMgmtClient(t, setupTestContext("test", "Org1MSP"))
+
+	_, err := rc.LifecycleQueryApprovalMatrix("mychannel", LifecycleCCDefinition{Name: "examplecc", Version: "v1"})
+	assert.Error(t, err, "expecting error for missing sequence")
+
+	_, err = rc.LifecycleCheckCCCommitReadiness("mychannel", LifecycleCCDefinition{Name: "examplecc", Version: "v1", Sequence: 1})
+	assert.Error(t, err, "expecting error for missing target")
+
+	approved := &LifecycleApprovedCC{LifecycleCCDefinition: LifecycleCCDefinition{Name: "examplecc", Version: "v0", Sequence: 1}}
+	matrix := newLifecycleApprovalMatrix(
+		map[string]bool{"Org1MSP": true, "Org2MSP": false, "Org3MSP": false},
+		map[string]*LifecycleApprovedCC{"Org2MSP": approved},
+		map[string]error{"Org3MSP": errors.New("not approved")},
+	)
+
+	assert.Equal(t, []string{"Org2MSP", "Org3MSP"}, matrix.Blocking())
+	assert.True(t, matrix["Org1MSP"].Approved)
+	assert.Equal(t, approved, matrix["Org2MSP"].Definition)
+	assert.Error(t, matrix["Org3MSP"].Error)
+	assert.Nil(t, matrix["Org3MSP"].Definition)
+}
+
+func TestVerifyInstalledPackage(t *testing.T) {
+	installed := []resource.LifecycleInstalledChaincode{
+		{PackageID: "examplecc_1:abcd", Label: "examplecc_1"},
+		{PackageID: "othercc_1:1234", Label: "othercc_1"},
+	}
+
+	assert.NoError(t, verifyInstalledPackage("peer1", "examplecc_1:abcd", installed))
+
+	err := verifyInstalledPackage("peer1", "examplecc_1:ef01", installed)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "examplecc_1:abcd", "expecting the mismatched package ID to be reported")
+	}
+
+	err = verifyInstalledPackage("peer1", "newcc_1:ef01", installed)
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "same label")
+	}
+
+	assert.Equal(t, "examplecc_1", packageLabel("examplecc_1:abcd"))
+}
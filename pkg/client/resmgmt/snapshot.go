@@ -0,0 +1,124 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	reqContext "context"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/snapshot"
+	"github.com/pkg/errors"
+)
+
+// SubmitSnapshotRequest requests the peers to generate a snapshot of the channel's ledger at the given block number.
+// If the block number is 0 then the snapshot is generated at the last committed block. Requires Fabric 2.4 (or later) peers.
+//  Parameters:
+//  channelID is mandatory channel name
+//  blockNumber is the block number at which the snapshot is generated (0 for the last committed block)
+//  options holds optional request options (targets default to the peers of the client's organization)
+//
+//  Returns:
+//  an error if the request failed on any of the peers
+func (rc *Client) SubmitSnapshotRequest(channelID string, blockNumber uint64, options ...RequestOption) error {
+	return rc.sendSnapshotRequest(channelID, options, func(reqCtx reqContext.Context, client *snapshot.Client, target fab.PeerConfig) error {
+		return client.Generate(reqCtx, target, channelID, blockNumber)
+	})
+}
+
+// CancelSnapshotRequest cancels a pending snapshot request of the channel for the given block number.
+// Requires Fabric 2.4 (or later) peers.
+//  Parameters:
+//  channelID is mandatory channel name
+//  blockNumber is the block number of the pending snapshot request
+//  options holds optional request options (targets default to the peers of the client's organization)
+//
+//  Returns:
+//  an error if the request failed on any of the peers
+func (rc *Client) CancelSnapshotRequest(channelID string, blockNumber uint64, options ...RequestOption) error {
+	return rc.sendSnapshotRequest(channelID, options, func(reqCtx reqContext.Context, client *snapshot.Client, target fab.PeerConfig) error {
+		return client.Cancel(reqCtx, target, channelID, blockNumber)
+	})
+}
+
+// QueryPendingSnapshotRequests queries the block numbers of the pending snapshot requests of the channel on a peer.
+// Requires Fabric 2.4 (or later) peers.
+//  Parameters:
+//  channelID is mandatory channel name
+//  options hold optional request options
+//  Note: One target(peer) has to be specified using either WithTargetURLs or WithTargets request option
+//
+//  Returns:
+//  block numbers of the pending snapshot requests
+func (rc *Client) QueryPendingSnapshotRequests(channelID string, options ...RequestOption) ([]uint64, error) {
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Targets) != 1 {
+		return nil, errors.New("only one target is supported")
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	return snapshot.New(rc.ctx).QueryPendings(reqCtx, rc.targetPeerConfig(opts.Targets[0]), channelID)
+}
+
+// ListCompletedSnapshots returns the block numbers of the snapshots of the channel which have been generated by a peer.
+// The generated snapshots are not available through the peer's API so the peer's snapshot root directory
+// (ledger.snapshots.rootDir) must be accessible.
+func ListCompletedSnapshots(snapshotsRootDir, channelID string) ([]uint64, error) {
+	return snapshot.ListCompleted(snapshotsRootDir, channelID)
+}
+
+type snapshotRequestFunc func(reqCtx reqContext.Context, client *snapshot.Client, target fab.PeerConfig) error
+
+func (rc *Client) sendSnapshotRequest(channelID string, options []RequestOption, send snapshotRequestFunc) error {
+	if channelID == "" {
+		return errors.New("must provide channel ID")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return err
+	}
+
+	targets, err := rc.calculateTargets(opts.Targets, opts.TargetFilter)
+	if err != nil {
+		return errors.WithMessage(err, "failed to determine target peers for snapshot request")
+	}
+
+	if len(targets) == 0 {
+		return errors.WithStack(status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "no targets available", nil))
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	client := snapshot.New(rc.ctx)
+
+	var errs multi.Errors
+	for _, target := range targets {
+		if err := send(reqCtx, client, rc.targetPeerConfig(target)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs.ToError()
+}
+
+// targetPeerConfig returns the configuration of the given peer, which is required for
+// connecting to the peer's snapshot service and operations endpoint
+func (rc *Client) targetPeerConfig(peer fab.Peer) fab.PeerConfig {
+	peerConfig, ok := rc.ctx.EndpointConfig().PeerConfig(peer.URL())
+	if !ok {
+		return fab.PeerConfig{URL: peer.URL()}
+	}
+	return *peerConfig
+}
@@ -0,0 +1,126 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package revocation revokes certificates at the Fabric CA and propagates the resulting CRL to the
+// MSP of the organization in each of its channels, so that peers and orderers reject the revoked
+// certificates. Without this, the CRL has to be fetched from the CA and added to the channel config
+// of every channel by hand.
+//
+//  Basic Flow:
+//  1) Create an msp client (with the registrar of the CA) and a resmgmt client (with an org admin)
+//  2) Create a revocation client
+//  3) Revoke the identity or certificate, listing the channels of the organization
+//  4) Retry the channels that failed using resmgmt.UpdateRevocationList with the returned CRL
+package revocation
+
+import (
+	mspclient "github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// Revoker revokes certificates at the CA (implemented by the msp client)
+type Revoker interface {
+	Revoke(request *mspclient.RevocationRequest) (*mspclient.RevocationResponse, error)
+}
+
+// ConfigUpdater installs CRLs into channel configs (implemented by the resmgmt client)
+type ConfigUpdater interface {
+	UpdateRevocationList(req resmgmt.RevocationListRequest, options ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error)
+}
+
+// Client revokes certificates and propagates the CRL to channels
+type Client struct {
+	revoker Revoker
+	updater ConfigUpdater
+}
+
+// Request holds the parameters of a revocation
+type Request struct {
+	// Revocation identifies the identity or certificate to revoke (a CRL is always requested from the CA)
+	Revocation mspclient.RevocationRequest
+	// Org is the name or MSP ID of the organization of the CA
+	Org string
+	// Channels are the channels whose config is updated with the CRL
+	Channels []string
+	// SigningIdentities are the admins that sign the config updates (default: the resmgmt client identity)
+	SigningIdentities []msp.SigningIdentity
+}
+
+// Response holds the result of a revocation
+type Response struct {
+	RevokedCerts []mspclient.RevokedCert
+	// CRL is the PEM encoded CRL returned by the CA
+	CRL []byte
+	// TransactionIDs holds the ID of the config update of each updated channel
+	TransactionIDs map[string]fab.TransactionID
+}
+
+// New returns a revocation client
+//  Parameters:
+//  revoker revokes the certificates, e.g. an msp client whose CA registrar has the hf.Revoker attribute
+//  updater submits the config updates, e.g. a resmgmt client of an admin of the organization
+//
+//  Returns:
+//  the revocation client
+func New(revoker Revoker, updater ConfigUpdater) *Client {
+	return &Client{revoker: revoker, updater: updater}
+}
+
+// Revoke revokes the certificates at the CA and installs the resulting CRL in the channels. The
+// certificates stay revoked at the CA if a channel fails to be updated: the response then holds the
+// CRL and the channels that were updated, along with an error listing the channels that failed.
+//  Parameters:
+//  req holds the revocation request, the organization and the channels
+//  options holds optional request options of the config updates
+//
+//  Returns:
+//  the revoked certificates, the CRL and the transaction IDs of the config updates
+func (c *Client) Revoke(req Request, options ...resmgmt.RequestOption) (*Response, error) {
+	if req.Org == "" {
+		return nil, errors.New("organization is required")
+	}
+
+	revocation := req.Revocation
+	revocation.GenCRL = true
+	resp, err := c.revoker.Revoke(&revocation)
+	if err != nil {
+		return nil, errors.WithMessage(err, "revocation failed")
+	}
+	if len(resp.CRL) == 0 {
+		return nil, errors.New("CA didn't return a CRL")
+	}
+
+	response := &Response{
+		RevokedCerts:   resp.RevokedCerts,
+		CRL:            resp.CRL,
+		TransactionIDs: make(map[string]fab.TransactionID),
+	}
+
+	var errs error
+	for _, channelID := range req.Channels {
+		saveResp, err := c.updater.UpdateRevocationList(resmgmt.RevocationListRequest{
+			ChannelID:         channelID,
+			Org:               req.Org,
+			CRL:               resp.CRL,
+			SigningIdentities: req.SigningIdentities,
+		}, options...)
+		if err != nil {
+			logger.Warnf("Failed to install CRL of [%s] in channel [%s]: %s", req.Org, channelID, err)
+			errs = multi.Append(errs, errors.WithMessage(err, "failed to update channel ["+channelID+"]"))
+			continue
+		}
+		response.TransactionIDs[channelID] = saveResp.TransactionID
+	}
+
+	return response, errs
+}
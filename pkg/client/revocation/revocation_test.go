@@ -0,0 +1,113 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package revocation
+
+import (
+	"testing"
+
+	mspclient "github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCRL = "-----BEGIN X509 CRL-----\nY3Js\n-----END X509 CRL-----\n"
+
+type mockRevoker struct {
+	request  *mspclient.RevocationRequest
+	response *mspclient.RevocationResponse
+	err      error
+}
+
+func (r *mockRevoker) Revoke(request *mspclient.RevocationRequest) (*mspclient.RevocationResponse, error) {
+	r.request = request
+	return r.response, r.err
+}
+
+type mockUpdater struct {
+	requests []resmgmt.RevocationListRequest
+	failures map[string]error
+}
+
+func (u *mockUpdater) UpdateRevocationList(req resmgmt.RevocationListRequest, options ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error) {
+	u.requests = append(u.requests, req)
+	if err := u.failures[req.ChannelID]; err != nil {
+		return resmgmt.SaveChannelResponse{}, err
+	}
+	return resmgmt.SaveChannelResponse{TransactionID: fab.TransactionID("tx-" + req.ChannelID)}, nil
+}
+
+func newMockRevoker() *mockRevoker {
+	return &mockRevoker{response: &mspclient.RevocationResponse{
+		RevokedCerts: []mspclient.RevokedCert{{Serial: "1234", AKI: "abcd"}},
+		CRL:          []byte(testCRL),
+	}}
+}
+
+func TestRevoke(t *testing.T) {
+	revoker := newMockRevoker()
+	updater := &mockUpdater{}
+	c := New(revoker, updater)
+
+	resp, err := c.Revoke(Request{
+		Revocation: mspclient.RevocationRequest{Name: "user1", Reason: "keycompromise"},
+		Org:        "Org1MSP",
+		Channels:   []string{"channel1", "channel2"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "user1", revoker.request.Name)
+	assert.True(t, revoker.request.GenCRL, "expecting CRL to be requested")
+
+	assert.Equal(t, []byte(testCRL), resp.CRL)
+	assert.Equal(t, []mspclient.RevokedCert{{Serial: "1234", AKI: "abcd"}}, resp.RevokedCerts)
+	assert.Equal(t, map[string]fab.TransactionID{"channel1": "tx-channel1", "channel2": "tx-channel2"}, resp.TransactionIDs)
+
+	require.Len(t, updater.requests, 2)
+	for _, req := range updater.requests {
+		assert.Equal(t, "Org1MSP", req.Org)
+		assert.Equal(t, []byte(testCRL), req.CRL)
+	}
+}
+
+func TestRevokePartialFailure(t *testing.T) {
+	updater := &mockUpdater{failures: map[string]error{"channel1": errors.New("orderer unavailable")}}
+	c := New(newMockRevoker(), updater)
+
+	resp, err := c.Revoke(Request{
+		Revocation: mspclient.RevocationRequest{Name: "user1"},
+		Org:        "Org1MSP",
+		Channels:   []string{"channel1", "channel2"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to update channel [channel1]")
+	require.NotNil(t, resp, "expecting response with the CRL so that failed channels can be retried")
+	assert.Equal(t, []byte(testCRL), resp.CRL)
+	assert.Equal(t, map[string]fab.TransactionID{"channel2": "tx-channel2"}, resp.TransactionIDs)
+}
+
+func TestRevokeErrors(t *testing.T) {
+	updater := &mockUpdater{}
+
+	_, err := New(newMockRevoker(), updater).Revoke(Request{Revocation: mspclient.RevocationRequest{Name: "user1"}})
+	assert.Error(t, err, "expecting error for missing organization")
+
+	revoker := newMockRevoker()
+	revoker.err = errors.New("authorization failure")
+	_, err = New(revoker, updater).Revoke(Request{Org: "Org1MSP", Channels: []string{"channel1"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "revocation failed")
+
+	revoker = newMockRevoker()
+	revoker.response.CRL = nil
+	_, err = New(revoker, updater).Revoke(Request{Org: "Org1MSP", Channels: []string{"channel1"}})
+	assert.Error(t, err, "expecting error if the CA doesn't return a CRL")
+
+	assert.Empty(t, updater.requests, "expecting no config update if revocation failed")
+}
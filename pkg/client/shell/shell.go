@@ -0,0 +1,476 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package shell provides an interactive debug shell for a channel. The shell reads commands line by line
+// and lets developers query chaincode, inspect the peers returned by discovery and the endorsers chosen by
+// the selection service, decode blocks and watch block and chaincode events while troubleshooting a network.
+// The shell is available on the command line with 'fabric-sdk-cli shell'.
+//
+//  Basic Flow:
+//  1) Prepare channel context
+//  2) Create shell
+//  3) Run the shell on an input (e.g. os.Stdin) or execute single commands
+package shell
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/inspect"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/event"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// errExit is returned by the exit command
+var errExit = errors.New("exit")
+
+type querier interface {
+	Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+}
+
+type ledgerQuerier interface {
+	QueryInfo(options ...ledger.RequestOption) (*fab.BlockchainInfoResponse, error)
+	QueryBlock(blockNumber uint64, options ...ledger.RequestOption) (*common.Block, error)
+}
+
+type eventService interface {
+	RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error)
+	RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error)
+	Unregister(reg fab.Registration)
+}
+
+// handler executes a command with the given arguments
+type handler func(args []string) error
+
+type command struct {
+	usage   string
+	help    string
+	handler handler
+}
+
+// Shell is an interactive debug shell for a channel
+type Shell struct {
+	channelID string
+	querier   querier
+	ledger    ledgerQuerier
+	events    eventService
+	discovery fab.DiscoveryService
+	selection fab.SelectionService
+	decoder   *inspect.Decoder
+	commands  map[string]*command
+
+	mutex   sync.Mutex // protects the output and the watches
+	out     io.Writer
+	watches map[int]fab.Registration
+	nextID  int
+}
+
+// Option configures the shell
+type Option func(s *Shell)
+
+// WithOutput sets the writer to which the output of the commands (and events) is written (default os.Stdout)
+func WithOutput(out io.Writer) Option {
+	return func(s *Shell) {
+		s.out = out
+	}
+}
+
+// New returns a shell for the given channel. Block events are requested from the peers' deliver
+// service, which requires the user to have access to full blocks.
+func New(channelProvider context.ChannelProvider, opts ...Option) (*Shell, error) {
+	channelContext, err := channelProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel context")
+	}
+
+	chClient, err := channel.New(channelProvider)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel client")
+	}
+	ledgerClient, err := ledger.New(channelProvider)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create ledger client")
+	}
+	eventClient, err := event.New(channelProvider, event.WithBlockEvents())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create event client")
+	}
+	discovery, err := channelContext.ChannelService().Discovery()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get discovery service")
+	}
+	selection, err := channelContext.ChannelService().Selection()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get selection service")
+	}
+
+	return newShell(channelContext.ChannelID(), chClient, ledgerClient, eventClient, discovery, selection, opts...), nil
+}
+
+func newShell(channelID string, querier querier, ledger ledgerQuerier, events eventService, discovery fab.DiscoveryService, selection fab.SelectionService, opts ...Option) *Shell {
+	s := &Shell{
+		channelID: channelID,
+		querier:   querier,
+		ledger:    ledger,
+		events:    events,
+		discovery: discovery,
+		selection: selection,
+		decoder:   inspect.NewDecoder(),
+		out:       os.Stdout,
+		watches:   make(map[int]fab.Registration),
+		nextID:    1,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.commands = map[string]*command{
+		"help":      {usage: "help", help: "lists the commands", handler: s.help},
+		"info":      {usage: "info", help: "shows the height and current block hash of the ledger", handler: s.info},
+		"query":     {usage: "query <cc> <fcn> [args...]", help: "queries a chaincode", handler: s.query},
+		"peers":     {usage: "peers", help: "lists the peers returned by discovery", handler: s.peers},
+		"endorsers": {usage: "endorsers <cc>[,<cc>...]", help: "lists the endorsers chosen by the selection service", handler: s.endorsers},
+		"block":     {usage: "block <number|newest> [json]", help: "decodes a block (json shows the full decoded transactions)", handler: s.block},
+		"watch":     {usage: "watch blocks | watch cc <cc> [event filter]", help: "prints block or chaincode events as they arrive", handler: s.watch},
+		"unwatch":   {usage: "unwatch <id|all>", help: "stops watching events", handler: s.unwatch},
+		"exit":      {usage: "exit", help: "exits the shell", handler: func([]string) error { return errExit }},
+	}
+	return s
+}
+
+// Run reads commands from the given input and executes them until the input is exhausted or the exit
+// command is entered. Errors of commands are printed and don't stop the shell.
+func (s *Shell) Run(in io.Reader) error {
+	defer s.Close()
+
+	scanner := bufio.NewScanner(in)
+	s.printf("%s> ", s.channelID)
+	for scanner.Scan() {
+		err := s.Exec(scanner.Text())
+		if err == errExit {
+			return nil
+		}
+		if err != nil {
+			s.printf("Error: %s\n", err)
+		}
+		s.printf("%s> ", s.channelID)
+	}
+	return scanner.Err()
+}
+
+// Exec executes a single command line
+func (s *Shell) Exec(line string) error {
+	args, err := splitArgs(line)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	cmd, ok := s.commands[args[0]]
+	if !ok {
+		return errors.Errorf("unknown command [%s] (enter 'help' for the list of commands)", args[0])
+	}
+	return cmd.handler(args[1:])
+}
+
+// Close stops watching events
+func (s *Shell) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for id, reg := range s.watches {
+		s.events.Unregister(reg)
+		delete(s.watches, id)
+	}
+}
+
+func (s *Shell) printf(format string, args ...interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	fmt.Fprintf(s.out, format, args...)
+}
+
+func (s *Shell) help(args []string) error {
+	var names []string
+	for name := range s.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cmd := s.commands[name]
+		s.printf("  %-45s %s\n", cmd.usage, cmd.help)
+	}
+	return nil
+}
+
+func (s *Shell) info(args []string) error {
+	resp, err := s.ledger.QueryInfo()
+	if err != nil {
+		return err
+	}
+	s.printf("Height: %d\nCurrent block hash: %x\nPrevious block hash: %x\nEndorser: %s\n",
+		resp.BCI.Height, resp.BCI.CurrentBlockHash, resp.BCI.PreviousBlockHash, resp.Endorser)
+	return nil
+}
+
+func (s *Shell) query(args []string) error {
+	if len(args) < 2 {
+		return errors.New("usage: " + s.commands["query"].usage)
+	}
+
+	var ccArgs [][]byte
+	for _, a := range args[2:] {
+		ccArgs = append(ccArgs, []byte(a))
+	}
+
+	resp, err := s.querier.Query(channel.Request{ChaincodeID: args[0], Fcn: args[1], Args: ccArgs})
+	if err != nil {
+		return err
+	}
+
+	var endorsers []string
+	for _, r := range resp.Responses {
+		endorsers = append(endorsers, r.Endorser)
+	}
+	s.printf("%s\n(endorsed by %s)\n", resp.Payload, strings.Join(endorsers, ", "))
+	return nil
+}
+
+func (s *Shell) peers(args []string) error {
+	peers, err := s.discovery.GetPeers()
+	if err != nil {
+		return err
+	}
+	s.printPeers(peers)
+	return nil
+}
+
+func (s *Shell) endorsers(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: " + s.commands["endorsers"].usage)
+	}
+
+	var calls []*fab.ChaincodeCall
+	for _, cc := range strings.Split(args[0], ",") {
+		calls = append(calls, &fab.ChaincodeCall{ID: cc})
+	}
+
+	peers, err := s.selection.GetEndorsersForChaincode(calls)
+	if err != nil {
+		return err
+	}
+	s.printPeers(peers)
+	return nil
+}
+
+func (s *Shell) printPeers(peers []fab.Peer) {
+	sort.Slice(peers, func(i, j int) bool { return peers[i].URL() < peers[j].URL() })
+	for _, p := range peers {
+		height := "-"
+		if ps, ok := p.(fab.PeerState); ok {
+			height = strconv.FormatUint(ps.BlockHeight(), 10)
+		}
+		s.printf("  %-40s %-15s height: %s\n", p.URL(), p.MSPID(), height)
+	}
+	s.printf("%d peer(s)\n", len(peers))
+}
+
+func (s *Shell) block(args []string) error {
+	if len(args) < 1 || len(args) > 2 || (len(args) == 2 && args[1] != "json") {
+		return errors.New("usage: " + s.commands["block"].usage)
+	}
+
+	var number uint64
+	if args[0] == "newest" {
+		resp, err := s.ledger.QueryInfo()
+		if err != nil {
+			return err
+		}
+		number = resp.BCI.Height - 1
+	} else {
+		var err error
+		number, err = strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return errors.Errorf("invalid block number [%s]", args[0])
+		}
+	}
+
+	block, err := s.ledger.QueryBlock(number)
+	if err != nil {
+		return err
+	}
+	return s.printBlock(block, len(args) == 2)
+}
+
+func (s *Shell) printBlock(block *common.Block, asJSON bool) error {
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = ledgerutil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	var txs []*inspect.Transaction
+	for _, data := range block.Data.Data {
+		tx, err := s.decoder.DecodeTransaction(data)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("failed to decode transaction %d of block %d", len(txs), block.Header.Number))
+		}
+		txs = append(txs, tx)
+	}
+
+	if asJSON {
+		bytes, err := json.MarshalIndent(txs, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal transactions")
+		}
+		s.printf("%s\n", bytes)
+		return nil
+	}
+
+	s.printf("Block %d: %d transaction(s), data hash %x\n", block.Header.Number, len(txs), block.Header.DataHash)
+	for i, tx := range txs {
+		validation := "-"
+		if i < len(txFilter) {
+			validation = txFilter.Flag(i).String()
+		}
+		s.printf("  [%d] %s %s %s creator: %s\n", i, tx.Header.TxID, tx.Header.Type, validation, tx.Header.Creator.MSPID)
+		for _, action := range tx.Actions {
+			s.printf("      %s %s endorsed by %v\n", action.Chaincode.Name, formatArgs(action.Chaincode.Args), action.EndorsingOrgs)
+		}
+	}
+	return nil
+}
+
+func (s *Shell) watch(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: " + s.commands["watch"].usage)
+	}
+
+	switch {
+	case args[0] == "blocks" && len(args) == 1:
+		reg, eventch, err := s.events.RegisterBlockEvent()
+		if err != nil {
+			return err
+		}
+		id := s.addWatch(reg)
+		go func() {
+			for e := range eventch {
+				s.printf("\n[watch %d] block %d with %d transaction(s) from %s\n", id, e.Block.Header.Number, len(e.Block.Data.Data), e.SourceURL)
+			}
+		}()
+		return nil
+	case args[0] == "cc" && (len(args) == 2 || len(args) == 3):
+		filter := ".*"
+		if len(args) == 3 {
+			filter = args[2]
+		}
+		reg, eventch, err := s.events.RegisterChaincodeEvent(args[1], filter)
+		if err != nil {
+			return err
+		}
+		id := s.addWatch(reg)
+		go func() {
+			for e := range eventch {
+				s.printf("\n[watch %d] event %s of %s in tx %s (block %d): %s\n", id, e.EventName, e.ChaincodeID, e.TxID, e.BlockNumber, e.Payload)
+			}
+		}()
+		return nil
+	default:
+		return errors.New("usage: " + s.commands["watch"].usage)
+	}
+}
+
+func (s *Shell) addWatch(reg fab.Registration) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	s.watches[id] = reg
+	fmt.Fprintf(s.out, "Watch %d started\n", id)
+	return id
+}
+
+func (s *Shell) unwatch(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: " + s.commands["unwatch"].usage)
+	}
+	if args[0] == "all" {
+		s.Close()
+		return nil
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return errors.Errorf("invalid watch ID [%s]", args[0])
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	reg, ok := s.watches[id]
+	if !ok {
+		return errors.Errorf("watch %d not found", id)
+	}
+	s.events.Unregister(reg)
+	delete(s.watches, id)
+	return nil
+}
+
+func formatArgs(args [][]byte) string {
+	var strArgs []string
+	for _, a := range args {
+		strArgs = append(strArgs, strconv.Quote(string(a)))
+	}
+	return "[" + strings.Join(strArgs, " ") + "]"
+}
+
+// splitArgs splits a command line into arguments separated by white space. Arguments
+// containing white space may be enclosed in double quotes.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var current []rune
+	inQuotes, inArg := false, false
+
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			inArg = true
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if inArg {
+				args = append(args, string(current))
+				current, inArg = nil, false
+			}
+		default:
+			current = append(current, r)
+			inArg = true
+		}
+	}
+
+	if inQuotes {
+		return nil, errors.New("unterminated quote")
+	}
+	if inArg {
+		args = append(args, string(current))
+	}
+	return args, nil
+}
@@ -0,0 +1,255 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shell
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
+	eventmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const channelID = "mychannel"
+
+type mockQuerier struct {
+	request channel.Request
+}
+
+func (m *mockQuerier) Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	m.request = request
+	if request.ChaincodeID == "invalid" {
+		return channel.Response{}, errors.New("chaincode not found")
+	}
+	return channel.Response{
+		Payload:   []byte("value"),
+		Responses: []*fab.TransactionProposalResponse{{Endorser: "peer1:7051"}},
+	}, nil
+}
+
+type mockLedger struct {
+	blocks []*common.Block
+}
+
+func (m *mockLedger) QueryInfo(options ...ledger.RequestOption) (*fab.BlockchainInfoResponse, error) {
+	return &fab.BlockchainInfoResponse{
+		BCI:      &common.BlockchainInfo{Height: uint64(len(m.blocks)), CurrentBlockHash: []byte{0xab}},
+		Endorser: "peer1:7051",
+	}, nil
+}
+
+func (m *mockLedger) QueryBlock(blockNumber uint64, options ...ledger.RequestOption) (*common.Block, error) {
+	if blockNumber >= uint64(len(m.blocks)) {
+		return nil, errors.Errorf("block %d not found", blockNumber)
+	}
+	return m.blocks[blockNumber], nil
+}
+
+type mockEvents struct {
+	mutex    sync.Mutex
+	blockCh  chan *fab.BlockEvent
+	ccCh     chan *fab.CCEvent
+	unregged int
+}
+
+func (m *mockEvents) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	m.blockCh = make(chan *fab.BlockEvent)
+	return &dispatcher.BlockReg{Eventch: m.blockCh}, m.blockCh, nil
+}
+
+func (m *mockEvents) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	if eventFilter == "(" {
+		return nil, nil, errors.New("invalid event filter")
+	}
+	m.ccCh = make(chan *fab.CCEvent)
+	return &dispatcher.ChaincodeReg{Eventch: m.ccCh, ChaincodeID: ccID, EventFilter: eventFilter}, m.ccCh, nil
+}
+
+func (m *mockEvents) Unregister(reg fab.Registration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.unregged++
+	switch r := reg.(type) {
+	case *dispatcher.BlockReg:
+		close(r.Eventch)
+	case *dispatcher.ChaincodeReg:
+		close(r.Eventch)
+	}
+}
+
+func (m *mockEvents) unregistered() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.unregged
+}
+
+// syncBuffer is a buffer which may be written by the event goroutines while the test reads it
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+func newTestShell(t *testing.T) (*Shell, *syncBuffer, *mockQuerier, *mockEvents) {
+	block0 := eventmocks.NewBlock(channelID, eventmocks.NewTransaction("txid0", pb.TxValidationCode_VALID, common.HeaderType_CONFIG))
+	block1 := eventmocks.NewBlock(channelID,
+		eventmocks.NewTransaction("txid1", pb.TxValidationCode_VALID, common.HeaderType_CONFIG),
+		eventmocks.NewTransaction("txid2", pb.TxValidationCode_MVCC_READ_CONFLICT, common.HeaderType_CONFIG),
+	)
+	block1.Header.Number = 1
+
+	peer1 := fcmocks.NewMockPeer("peer1", "grpcs://peer1:7051")
+	peer1.MockMSP = "Org1MSP"
+	peer2 := fcmocks.NewMockPeer("peer2", "grpcs://peer2:7051")
+	peer2.MockMSP = "Org2MSP"
+
+	out := &syncBuffer{}
+	querier := &mockQuerier{}
+	events := &mockEvents{}
+	s := newShell(channelID, querier, &mockLedger{blocks: []*common.Block{block0, block1}}, events,
+		fcmocks.NewMockDiscoveryService(nil, peer2, peer1), fcmocks.NewMockSelectionService(nil, peer1), WithOutput(out))
+	return s, out, querier, events
+}
+
+func TestQuery(t *testing.T) {
+	s, out, querier, _ := newTestShell(t)
+
+	require.NoError(t, s.Exec(`query examplecc get "key 1" b`))
+	assert.Equal(t, "examplecc", querier.request.ChaincodeID)
+	assert.Equal(t, "get", querier.request.Fcn)
+	assert.Equal(t, [][]byte{[]byte("key 1"), []byte("b")}, querier.request.Args)
+	assert.Contains(t, out.String(), "value\n(endorsed by peer1:7051)")
+
+	assert.Error(t, s.Exec("query examplecc"), "expecting usage error")
+	assert.Error(t, s.Exec("query invalid get"))
+}
+
+func TestPeersAndEndorsers(t *testing.T) {
+	s, out, _, _ := newTestShell(t)
+
+	require.NoError(t, s.Exec("peers"))
+	output := out.String()
+	assert.Contains(t, output, "2 peer(s)")
+	assert.True(t, strings.Index(output, "peer1:7051") < strings.Index(output, "peer2:7051"), "expecting peers sorted by URL")
+	assert.Contains(t, output, "Org2MSP")
+
+	require.NoError(t, s.Exec("endorsers examplecc"))
+	assert.Contains(t, out.String(), "1 peer(s)")
+
+	assert.Error(t, s.Exec("endorsers"), "expecting usage error")
+}
+
+func TestInfoAndBlock(t *testing.T) {
+	s, out, _, _ := newTestShell(t)
+
+	require.NoError(t, s.Exec("info"))
+	assert.Contains(t, out.String(), "Height: 2\nCurrent block hash: ab")
+
+	require.NoError(t, s.Exec("block newest"))
+	output := out.String()
+	assert.Contains(t, output, "Block 1: 2 transaction(s)")
+	assert.Contains(t, output, "txid1 CONFIG VALID")
+	assert.Contains(t, output, "txid2 CONFIG MVCC_READ_CONFLICT")
+
+	require.NoError(t, s.Exec("block 0 json"))
+	assert.Contains(t, out.String(), `"txId": "txid0"`)
+
+	assert.Error(t, s.Exec("block 5"))
+	assert.Error(t, s.Exec("block x"), "expecting error for invalid block number")
+	assert.Error(t, s.Exec("block 0 xml"), "expecting usage error")
+}
+
+func TestWatch(t *testing.T) {
+	s, out, _, events := newTestShell(t)
+
+	require.NoError(t, s.Exec("watch blocks"))
+	require.NoError(t, s.Exec("watch cc examplecc"))
+	assert.Contains(t, out.String(), "Watch 1 started")
+	assert.Contains(t, out.String(), "Watch 2 started")
+
+	block := eventmocks.NewBlock(channelID)
+	block.Header.Number = 7
+	events.blockCh <- &fab.BlockEvent{Block: block, SourceURL: "peer1:7051"}
+	events.ccCh <- &fab.CCEvent{TxID: "txid", ChaincodeID: "examplecc", EventName: "moved", BlockNumber: 7, Payload: []byte("payload")}
+
+	assert.True(t, waitFor(func() bool {
+		output := out.String()
+		return strings.Contains(output, "[watch 1] block 7") && strings.Contains(output, "[watch 2] event moved of examplecc in tx txid (block 7): payload")
+	}), "expecting events to be printed")
+
+	assert.Error(t, s.Exec("watch cc examplecc ("))
+	assert.Error(t, s.Exec("watch txs"), "expecting usage error")
+
+	require.NoError(t, s.Exec("unwatch 1"))
+	assert.Error(t, s.Exec("unwatch 1"), "expecting error for watch that was removed")
+	assert.Error(t, s.Exec("unwatch x"))
+	assert.Equal(t, 1, events.unregistered())
+
+	require.NoError(t, s.Exec("unwatch all"))
+	assert.Equal(t, 2, events.unregistered())
+}
+
+func TestRun(t *testing.T) {
+	s, out, _, events := newTestShell(t)
+
+	in := strings.NewReader("help\n\nwatch blocks\nunknown\nquery \"examplecc\nexit\ninfo\n")
+	require.NoError(t, s.Run(in))
+
+	output := out.String()
+	assert.True(t, strings.HasPrefix(output, channelID+"> "))
+	assert.Contains(t, output, "endorsers <cc>[,<cc>...]")
+	assert.Contains(t, output, "Error: unknown command [unknown]")
+	assert.Contains(t, output, "Error: unterminated quote")
+	assert.NotContains(t, output, "Height", "expecting commands after exit to be ignored")
+	assert.Equal(t, 1, events.unregistered(), "expecting watches to be stopped on exit")
+}
+
+func TestSplitArgs(t *testing.T) {
+	args, err := splitArgs(`  query cc  put "a b" "" c`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"query", "cc", "put", "a b", "", "c"}, args)
+
+	args, err = splitArgs("   ")
+	require.NoError(t, err)
+	assert.Empty(t, args)
+
+	_, err = splitArgs(`query "cc`)
+	assert.Error(t, err)
+}
+
+func waitFor(cond func() bool) bool {
+	for i := 0; i < 100; i++ {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
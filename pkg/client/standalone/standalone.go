@@ -0,0 +1,186 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package standalone creates channel, ledger and event clients from explicitly supplied parts (endpoint config,
+// crypto suite and signing identity) without creating an SDK instance. Only the providers needed by these
+// clients are created: the identity configuration, the user store and the identity managers of the
+// organizations aren't loaded, which reduces the start-up cost (e.g. for serverless functions).
+//
+//  Basic Flow:
+//  1) Create the endpoint config (e.g. fab.ConfigFromBackend) and the crypto suite
+//  2) Create the signing identity (e.g. msp.NewSigningIdentity)
+//  3) Create the context with the parts
+//  4) Create the channel, ledger or event clients from the context
+//  5) Close the context
+package standalone
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/staticdiscovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/event"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/signingmgr"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/chpvdr"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/fabpvdr"
+	"github.com/pkg/errors"
+)
+
+// Context supplies the providers and the signing identity to the clients
+type Context struct {
+	provider *context.Provider
+	identity msp.SigningIdentity
+}
+
+type options struct {
+	infraProvider          fab.InfraProvider
+	channelProvider        fab.ChannelProvider
+	localDiscoveryProvider fab.LocalDiscoveryProvider
+}
+
+// Option configures the context
+type Option func(opts *options) error
+
+// WithInfraProvider overrides the default infra provider
+func WithInfraProvider(infraProvider fab.InfraProvider) Option {
+	return func(opts *options) error {
+		opts.infraProvider = infraProvider
+		return nil
+	}
+}
+
+// WithChannelProvider overrides the default channel provider
+func WithChannelProvider(channelProvider fab.ChannelProvider) Option {
+	return func(opts *options) error {
+		opts.channelProvider = channelProvider
+		return nil
+	}
+}
+
+// WithLocalDiscoveryProvider overrides the default (static) local discovery provider
+func WithLocalDiscoveryProvider(discoveryProvider fab.LocalDiscoveryProvider) Option {
+	return func(opts *options) error {
+		opts.localDiscoveryProvider = discoveryProvider
+		return nil
+	}
+}
+
+type providerInit interface {
+	Initialize(providers contextApi.Providers) error
+}
+
+type closeable interface {
+	Close()
+}
+
+// New creates a context from the given endpoint config, crypto suite and signing identity
+func New(endpointConfig fab.EndpointConfig, cryptoSuite core.CryptoSuite, identity msp.SigningIdentity, opts ...Option) (*Context, error) {
+	if endpointConfig == nil {
+		return nil, errors.New("endpoint config is required")
+	}
+	if cryptoSuite == nil {
+		return nil, errors.New("crypto suite is required")
+	}
+	if identity == nil {
+		return nil, errors.New("signing identity is required")
+	}
+
+	o := options{}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, errors.WithMessage(err, "error in option passed to New")
+		}
+	}
+
+	signingManager, err := signingmgr.New(cryptoSuite)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create signing manager")
+	}
+
+	if o.infraProvider == nil {
+		o.infraProvider = fabpvdr.New(endpointConfig)
+	}
+	if o.localDiscoveryProvider == nil {
+		if o.localDiscoveryProvider, err = staticdiscovery.NewLocalProvider(endpointConfig); err != nil {
+			return nil, errors.WithMessage(err, "failed to create local discovery provider")
+		}
+	}
+	if o.channelProvider == nil {
+		if o.channelProvider, err = chpvdr.New(endpointConfig); err != nil {
+			return nil, errors.WithMessage(err, "failed to create channel provider")
+		}
+	}
+
+	provider := context.NewProvider(
+		context.WithEndpointConfig(endpointConfig),
+		context.WithCryptoSuite(cryptoSuite),
+		context.WithSigningManager(signingManager),
+		context.WithIdentityManagerProvider(&noIdentityManagerProvider{}),
+		context.WithLocalDiscoveryProvider(o.localDiscoveryProvider),
+		context.WithInfraProvider(o.infraProvider),
+		context.WithChannelProvider(o.channelProvider))
+
+	for _, p := range []interface{}{o.infraProvider, o.localDiscoveryProvider, o.channelProvider} {
+		if pi, ok := p.(providerInit); ok {
+			if err := pi.Initialize(provider); err != nil {
+				return nil, errors.WithMessage(err, "failed to initialize provider")
+			}
+		}
+	}
+
+	return &Context{provider: provider, identity: identity}, nil
+}
+
+// ClientContext returns the client context provider
+func (c *Context) ClientContext() contextApi.ClientProvider {
+	return func() (contextApi.Client, error) {
+		return &context.Client{Providers: c.provider, SigningIdentity: c.identity}, nil
+	}
+}
+
+// ChannelContext returns the channel context provider for the given channel
+func (c *Context) ChannelContext(channelID string) contextApi.ChannelProvider {
+	return func() (contextApi.Channel, error) {
+		return context.NewChannel(c.ClientContext(), channelID)
+	}
+}
+
+// ChannelClient creates a channel client for the given channel
+func (c *Context) ChannelClient(channelID string, opts ...channel.ClientOption) (*channel.Client, error) {
+	return channel.New(c.ChannelContext(channelID), opts...)
+}
+
+// LedgerClient creates a ledger client for the given channel
+func (c *Context) LedgerClient(channelID string, opts ...ledger.ClientOption) (*ledger.Client, error) {
+	return ledger.New(c.ChannelContext(channelID), opts...)
+}
+
+// EventClient creates an event client for the given channel
+func (c *Context) EventClient(channelID string, opts ...event.ClientOption) (*event.Client, error) {
+	return event.New(c.ChannelContext(channelID), opts...)
+}
+
+// Close frees up the caches and connections maintained by the providers
+func (c *Context) Close() {
+	if pvdr, ok := c.provider.LocalDiscoveryProvider().(closeable); ok {
+		pvdr.Close()
+	}
+	if pvdr, ok := c.provider.ChannelProvider().(closeable); ok {
+		pvdr.Close()
+	}
+	c.provider.InfraProvider().Close()
+}
+
+// noIdentityManagerProvider is used since identity managers aren't available without the identity configuration
+type noIdentityManagerProvider struct{}
+
+func (p *noIdentityManagerProvider) IdentityManager(orgName string) (msp.IdentityManager, bool) {
+	return nil, false
+}
@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package standalone
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	config := mocks.NewMockEndpointConfig()
+	cryptoSuite, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+	identity := mspmocks.NewMockSigningIdentity("user1", "Org1MSP")
+
+	_, err = New(nil, cryptoSuite, identity)
+	assert.Error(t, err, "expecting error for missing endpoint config")
+	_, err = New(config, nil, identity)
+	assert.Error(t, err, "expecting error for missing crypto suite")
+	_, err = New(config, cryptoSuite, nil)
+	assert.Error(t, err, "expecting error for missing identity")
+
+	ctx, err := New(config, cryptoSuite, identity)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	client, err := ctx.ClientContext()()
+	require.NoError(t, err)
+	assert.Equal(t, identity.Identifier(), client.Identifier())
+	assert.Equal(t, config, client.EndpointConfig())
+	assert.NotNil(t, client.SigningManager())
+	assert.NotNil(t, client.InfraProvider())
+
+	_, ok := client.IdentityManager("Org1")
+	assert.False(t, ok, "identity managers are not available")
+}
+
+func TestClients(t *testing.T) {
+	config := mocks.NewMockEndpointConfig()
+	cryptoSuite, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+	identity := mspmocks.NewMockSigningIdentity("user1", "Org1MSP")
+	channelProvider, err := mocks.NewMockChannelProvider(nil)
+	require.NoError(t, err)
+
+	ctx, err := New(config, cryptoSuite, identity, WithChannelProvider(channelProvider))
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	channelCtx, err := ctx.ChannelContext("mychannel")()
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", channelCtx.ChannelID())
+
+	_, err = ctx.ChannelClient("mychannel")
+	assert.NoError(t, err)
+
+	_, err = ctx.EventClient("mychannel")
+	assert.NoError(t, err)
+}
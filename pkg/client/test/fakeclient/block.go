@@ -0,0 +1,149 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fakeclient
+
+import (
+	"crypto/sha256"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// txInfo holds the data of a committed transaction from which the block and the events are created
+type txInfo struct {
+	txID        string
+	code        pb.TxValidationCode
+	chaincodeID string
+	eventName   string
+	payload     []byte
+}
+
+// newBlock returns a block with the given number which contains the given transaction envelope
+func newBlock(blockNum uint64, previousHash []byte, envelope *cb.Envelope, code pb.TxValidationCode) (*cb.Block, error) {
+	envelopeBytes, err := proto.Marshal(envelope)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal envelope failed")
+	}
+
+	metadata := make([][]byte, len(cb.BlockMetadataIndex_name))
+	metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{uint8(code)}
+
+	dataHash := sha256.Sum256(envelopeBytes)
+
+	return &cb.Block{
+		Header: &cb.BlockHeader{
+			Number:       blockNum,
+			PreviousHash: previousHash,
+			DataHash:     dataHash[:],
+		},
+		Data:     &cb.BlockData{Data: [][]byte{envelopeBytes}},
+		Metadata: &cb.BlockMetadata{Metadata: metadata},
+	}, nil
+}
+
+// newEnvelope returns an endorser transaction envelope with a single chaincode action
+// (which includes the chaincode event, if any)
+func newEnvelope(channelID string, tx *txInfo) (*cb.Envelope, error) {
+	eventBytes, err := proto.Marshal(&pb.ChaincodeEvent{
+		TxId:        tx.txID,
+		ChaincodeId: tx.chaincodeID,
+		EventName:   tx.eventName,
+		Payload:     tx.payload,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal chaincode event failed")
+	}
+
+	actionBytes, err := proto.Marshal(&pb.ChaincodeAction{
+		ChaincodeId: &pb.ChaincodeID{Name: tx.chaincodeID},
+		Events:      eventBytes,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal chaincode action failed")
+	}
+
+	prpBytes, err := proto.Marshal(&pb.ProposalResponsePayload{Extension: actionBytes})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal proposal response payload failed")
+	}
+
+	capBytes, err := proto.Marshal(&pb.ChaincodeActionPayload{
+		Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: prpBytes},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal chaincode action payload failed")
+	}
+
+	txBytes, err := proto.Marshal(&pb.Transaction{
+		Actions: []*pb.TransactionAction{{Payload: capBytes}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal transaction failed")
+	}
+
+	channelHeaderBytes, err := proto.Marshal(&cb.ChannelHeader{
+		Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+		ChannelId: channelID,
+		TxId:      tx.txID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal channel header failed")
+	}
+
+	payloadBytes, err := proto.Marshal(&cb.Payload{
+		Header: &cb.Header{ChannelHeader: channelHeaderBytes},
+		Data:   txBytes,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal payload failed")
+	}
+
+	return &cb.Envelope{Payload: payloadBytes}, nil
+}
+
+// newFilteredBlock returns the filtered block of the block with the given number
+func newFilteredBlock(channelID string, blockNum uint64, tx *txInfo) *pb.FilteredBlock {
+	ftx := &pb.FilteredTransaction{
+		Txid:             tx.txID,
+		Type:             cb.HeaderType_ENDORSER_TRANSACTION,
+		TxValidationCode: tx.code,
+	}
+	if tx.eventName != "" {
+		ftx.Data = &pb.FilteredTransaction_TransactionActions{
+			TransactionActions: &pb.FilteredTransactionActions{
+				ChaincodeActions: []*pb.FilteredChaincodeAction{
+					{
+						ChaincodeEvent: &pb.ChaincodeEvent{
+							ChaincodeId: tx.chaincodeID,
+							EventName:   tx.eventName,
+							TxId:        tx.txID,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	return &pb.FilteredBlock{
+		ChannelId:            channelID,
+		Number:               blockNum,
+		FilteredTransactions: []*pb.FilteredTransaction{ftx},
+	}
+}
+
+// blockHash returns a hash of the block header. Note that this is not the ASN.1 header
+// hash computed by Fabric but is sufficient to chain the blocks of the fake ledger.
+func blockHash(block *cb.Block) ([]byte, error) {
+	headerBytes, err := proto.Marshal(block.Header)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal block header failed")
+	}
+	hash := sha256.Sum256(headerBytes)
+	return hash[:], nil
+}
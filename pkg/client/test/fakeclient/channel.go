@@ -0,0 +1,195 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fakeclient
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	chaincodeOKStatus    = 200
+	chaincodeErrorStatus = 500
+)
+
+// ChannelClient is an in-memory implementation of the channel client. Request options are
+// ignored since the requests are not sent to any peers.
+type ChannelClient struct {
+	ledger *Ledger
+	events *EventClient
+}
+
+// NewChannelClient returns a new channel client for the given ledger
+func NewChannelClient(ledger *Ledger) *ChannelClient {
+	return &ChannelClient{
+		ledger: ledger,
+		events: NewEventClient(ledger),
+	}
+}
+
+// Query simulates the chaincode invocation without committing a transaction
+func (c *ChannelClient) Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	if err := validateRequest(request); err != nil {
+		return channel.Response{}, err
+	}
+
+	txID := c.ledger.newTxID()
+	_, payload, err := c.ledger.simulate(txID, request)
+	if err != nil {
+		return channel.Response{}, status.NewFromExtractedChaincodeError(chaincodeErrorStatus, err.Error())
+	}
+
+	return channel.Response{
+		TransactionID:   fab.TransactionID(txID),
+		ChaincodeStatus: chaincodeOKStatus,
+		Payload:         payload,
+	}, nil
+}
+
+// Execute simulates the chaincode invocation and commits the transaction to the ledger. If the
+// transaction is invalidated then the response is returned along with an error containing the
+// validation code (as with the SDK channel client).
+func (c *ChannelClient) Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	if err := validateRequest(request); err != nil {
+		return channel.Response{}, err
+	}
+
+	txID := c.ledger.newTxID()
+	stub, payload, err := c.ledger.simulate(txID, request)
+	if err != nil {
+		return channel.Response{}, status.NewFromExtractedChaincodeError(chaincodeErrorStatus, err.Error())
+	}
+
+	code, err := c.ledger.commit(stub, request)
+	if err != nil {
+		return channel.Response{}, errors.WithMessage(err, "commit of transaction failed")
+	}
+
+	resp := channel.Response{
+		TransactionID:    fab.TransactionID(txID),
+		TxValidationCode: code,
+		ChaincodeStatus:  chaincodeOKStatus,
+		Payload:          payload,
+	}
+
+	if code != pb.TxValidationCode_VALID {
+		return resp, status.New(status.EventServerStatus, int32(code), "received invalid transaction", nil)
+	}
+	return resp, nil
+}
+
+// QueryInto queries the chaincode and decodes the payload of the response into result with the
+// default JSON decoder (the decoder of the request options is ignored)
+func (c *ChannelClient) QueryInto(request channel.Request, result interface{}, options ...channel.RequestOption) (channel.Response, error) {
+	response, err := c.Query(request, options...)
+	if err != nil {
+		return response, err
+	}
+	return response, decodePayload(response.Payload, result)
+}
+
+// ExecuteInto executes the transaction and decodes the payload of the response into result with the
+// default JSON decoder (the decoder of the request options is ignored)
+func (c *ChannelClient) ExecuteInto(request channel.Request, result interface{}, options ...channel.RequestOption) (channel.Response, error) {
+	response, err := c.Execute(request, options...)
+	if err != nil {
+		return response, err
+	}
+	return response, decodePayload(response.Payload, result)
+}
+
+// QueryAtHeight queries the chaincode if the ledger has reached the given height. Since transactions
+// are committed synchronously, an error is returned if the ledger is below the height.
+func (c *ChannelClient) QueryAtHeight(request channel.Request, height uint64, options ...channel.RequestOption) (channel.Response, error) {
+	if err := c.checkHeight(height); err != nil {
+		return channel.Response{}, err
+	}
+	return c.Query(request, options...)
+}
+
+// AwaitPeerHeight returns immediately if the ledger has reached the given height. Since transactions
+// are committed synchronously, an error is returned if the ledger is below the height.
+func (c *ChannelClient) AwaitPeerHeight(peer fab.Peer, height uint64, options ...channel.RequestOption) error {
+	return c.checkHeight(height)
+}
+
+// InvokeHandler is not supported since handlers require a connection to the network
+func (c *ChannelClient) InvokeHandler(handler invoke.Handler, request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	return channel.Response{}, errors.New("InvokeHandler is not supported by the fake channel client")
+}
+
+// SubmitEnvelope is not supported since the envelope can't be simulated
+func (c *ChannelClient) SubmitEnvelope(envelope []byte, options ...channel.RequestOption) (channel.Response, error) {
+	return channel.Response{}, errors.New("SubmitEnvelope is not supported by the fake channel client")
+}
+
+// ImplicitCollectionName returns the name of the implicit private data collection of the fake organization (see MSPID)
+func (c *ChannelClient) ImplicitCollectionName() string {
+	return channel.ImplicitCollectionName(MSPID)
+}
+
+// QueryImplicitCollection queries the chaincode. (Private data is held in the chaincode state of the fake ledger.)
+func (c *ChannelClient) QueryImplicitCollection(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	return c.Query(request, options...)
+}
+
+// ExecuteImplicitCollection executes the transaction. (Private data is held in the chaincode state of the fake ledger.)
+func (c *ChannelClient) ExecuteImplicitCollection(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	return c.Execute(request, options...)
+}
+
+// NewPageIterator returns an iterator over the pages of the given query request
+func (c *ChannelClient) NewPageIterator(request channel.Request, pageSize int32, decoder channel.PageDecoder, options ...channel.RequestOption) *channel.PageIterator {
+	return channel.NewQueryPageIterator(c.Query, request, pageSize, decoder, options...)
+}
+
+// VerifyPrivateData verifies the given private data value against the hash returned by the query
+func (c *ChannelClient) VerifyPrivateData(request channel.Request, value []byte, options ...channel.RequestOption) error {
+	response, err := c.Query(request, options...)
+	if err != nil {
+		return errors.WithMessage(err, "query of private data hash failed")
+	}
+	return channel.VerifyPrivateDataHash(response.Payload, value)
+}
+
+// RegisterChaincodeEvent registers for chaincode events
+func (c *ChannelClient) RegisterChaincodeEvent(chainCodeID string, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	return c.events.RegisterChaincodeEvent(chainCodeID, eventFilter)
+}
+
+// UnregisterChaincodeEvent removes the given chaincode event registration
+func (c *ChannelClient) UnregisterChaincodeEvent(registration fab.Registration) {
+	c.events.Unregister(registration)
+}
+
+func (c *ChannelClient) checkHeight(height uint64) error {
+	if ledgerHeight := c.ledger.Height(); ledgerHeight < height {
+		return errors.Errorf("ledger height [%d] is below the requested height [%d]", ledgerHeight, height)
+	}
+	return nil
+}
+
+func decodePayload(payload []byte, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	if err := channel.JSONDecoder(payload, result); err != nil {
+		return errors.WithMessage(err, "failed to decode response payload")
+	}
+	return nil
+}
+
+func validateRequest(request channel.Request) error {
+	if request.ChaincodeID == "" || request.Fcn == "" {
+		return errors.New("ChaincodeID and Fcn are required")
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fakeclient
+
+import (
+	"math"
+	"regexp"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/event"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	clientdisp "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SourceURL is the source URL set in the events emitted by the fake ledger
+	SourceURL = "fakepeer:7051"
+
+	// MSPID is the MSP ID of the organization of the fake clients (e.g. of the implicit collection)
+	MSPID = "FakeOrgMSP"
+
+	eventBufferSize = 100
+)
+
+type blockRegistration struct {
+	filter  fab.BlockFilter
+	eventch chan *fab.BlockEvent
+}
+
+type filteredBlockRegistration struct {
+	eventch chan *fab.FilteredBlockEvent
+}
+
+type ccRegistration struct {
+	ccID        string
+	eventFilter *regexp.Regexp
+	eventch     chan *fab.CCEvent
+}
+
+type txRegistration struct {
+	txID    string
+	eventch chan *fab.TxStatusEvent
+}
+
+// eventHub dispatches the events of committed blocks to the registrations
+type eventHub struct {
+	mutex      sync.RWMutex
+	blockRegs  map[*blockRegistration]struct{}
+	fblockRegs map[*filteredBlockRegistration]struct{}
+	ccRegs     map[*ccRegistration]struct{}
+	txRegs     map[string]*txRegistration
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		blockRegs:  make(map[*blockRegistration]struct{}),
+		fblockRegs: make(map[*filteredBlockRegistration]struct{}),
+		ccRegs:     make(map[*ccRegistration]struct{}),
+		txRegs:     make(map[string]*txRegistration),
+	}
+}
+
+// EventClient is an in-memory implementation of the event client
+type EventClient struct {
+	ledger   *Ledger
+	mutex    sync.RWMutex
+	decoders map[string]event.Decoder
+}
+
+// NewEventClient returns a new event client for the given ledger
+func NewEventClient(ledger *Ledger) *EventClient {
+	return &EventClient{ledger: ledger, decoders: make(map[string]event.Decoder)}
+}
+
+// SetChaincodeEventDecoder sets the decoder of the payloads of the events of the given chaincode
+// (the equivalent of the event.WithChaincodeEventDecoder option of the SDK event client)
+func (c *EventClient) SetChaincodeEventDecoder(ccID string, decoder event.Decoder) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.decoders[ccID] = decoder
+}
+
+// RegisterBlockEvent registers for block events
+func (c *EventClient) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	reg := &blockRegistration{eventch: make(chan *fab.BlockEvent, eventBufferSize)}
+	if len(filter) > 0 {
+		reg.filter = filter[0]
+	}
+
+	h := c.ledger.events
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.blockRegs[reg] = struct{}{}
+
+	return reg, reg.eventch, nil
+}
+
+// RegisterFilteredBlockEvent registers for filtered block events
+func (c *EventClient) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	reg := &filteredBlockRegistration{eventch: make(chan *fab.FilteredBlockEvent, eventBufferSize)}
+
+	h := c.ledger.events
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.fblockRegs[reg] = struct{}{}
+
+	return reg, reg.eventch, nil
+}
+
+// RegisterChaincodeEvent registers for chaincode events
+func (c *EventClient) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	if ccID == "" {
+		return nil, nil, errors.New("chaincode ID is required")
+	}
+	if eventFilter == "" {
+		return nil, nil, errors.New("event filter is required")
+	}
+
+	regExp, err := regexp.Compile(eventFilter)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid event filter [%s] for chaincode [%s]", eventFilter, ccID)
+	}
+
+	reg := &ccRegistration{ccID: ccID, eventFilter: regExp, eventch: make(chan *fab.CCEvent, eventBufferSize)}
+
+	h := c.ledger.events
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.ccRegs[reg] = struct{}{}
+
+	return reg, reg.eventch, nil
+}
+
+// RegisterTxStatusEvent registers for transaction status events
+func (c *EventClient) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	if txID == "" {
+		return nil, nil, errors.New("txID must be provided")
+	}
+
+	h := c.ledger.events
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if _, ok := h.txRegs[txID]; ok {
+		return nil, nil, errors.Errorf("registration already exists for TX ID [%s]", txID)
+	}
+
+	reg := &txRegistration{txID: txID, eventch: make(chan *fab.TxStatusEvent, eventBufferSize)}
+	h.txRegs[txID] = reg
+
+	return reg, reg.eventch, nil
+}
+
+// RegisterDecodedChaincodeEvent registers for chaincode events whose payloads are decoded with the
+// decoder set for the chaincode (see SetChaincodeEventDecoder)
+func (c *EventClient) RegisterDecodedChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *event.DecodedCCEvent, error) {
+	c.mutex.RLock()
+	decoder, ok := c.decoders[ccID]
+	c.mutex.RUnlock()
+	if !ok {
+		return nil, nil, errors.Errorf("no event decoder registered for chaincode [%s]", ccID)
+	}
+
+	reg, events, err := c.RegisterChaincodeEvent(ccID, eventFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decodedEvents := make(chan *event.DecodedCCEvent, eventBufferSize)
+	go func() {
+		defer close(decodedEvents)
+		for e := range events {
+			decoded := &event.DecodedCCEvent{CCEvent: e}
+			decoded.Value, decoded.Err = decoder(e)
+			decodedEvents <- decoded
+		}
+	}()
+
+	return reg, decodedEvents, nil
+}
+
+// Unregister removes the given registration and closes the event channel
+func (c *EventClient) Unregister(reg fab.Registration) {
+	c.ledger.events.unregister(reg)
+}
+
+// StreamState returns the state of the event stream, which is always connected to the fake peer
+// and up to date with the ledger
+func (c *EventClient) StreamState() (*clientdisp.StreamState, error) {
+	c.ledger.mutex.RLock()
+	defer c.ledger.mutex.RUnlock()
+
+	state := &clientdisp.StreamState{
+		PeerURL:       SourceURL,
+		LastBlockNum:  math.MaxUint64,
+		LastEventTime: c.ledger.lastCommit,
+	}
+	if n := len(c.ledger.blocks); n > 0 {
+		state.LastBlockNum = uint64(n - 1)
+	}
+	return state, nil
+}
+
+func (h *eventHub) unregister(reg fab.Registration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	switch r := reg.(type) {
+	case *blockRegistration:
+		if _, ok := h.blockRegs[r]; ok {
+			delete(h.blockRegs, r)
+			close(r.eventch)
+		}
+	case *filteredBlockRegistration:
+		if _, ok := h.fblockRegs[r]; ok {
+			delete(h.fblockRegs, r)
+			close(r.eventch)
+		}
+	case *ccRegistration:
+		if _, ok := h.ccRegs[r]; ok {
+			delete(h.ccRegs, r)
+			close(r.eventch)
+		}
+	case *txRegistration:
+		if existing, ok := h.txRegs[r.txID]; ok && existing == r {
+			delete(h.txRegs, r.txID)
+			close(r.eventch)
+		}
+	default:
+		logger.Warnf("Unsupported registration type: %T", reg)
+	}
+}
+
+// publish emits the events for the given block, which contains the given transaction
+func (h *eventHub) publish(channelID string, block *cb.Block, tx *txInfo) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for reg := range h.blockRegs {
+		if reg.filter != nil && !reg.filter(block) {
+			continue
+		}
+		select {
+		case reg.eventch <- &fab.BlockEvent{Block: block, SourceURL: SourceURL}:
+		default:
+			logger.Warnf("Block event buffer is full. Dropping block [%d].", block.Header.Number)
+		}
+	}
+
+	fblock := newFilteredBlock(channelID, block.Header.Number, tx)
+	for reg := range h.fblockRegs {
+		select {
+		case reg.eventch <- &fab.FilteredBlockEvent{FilteredBlock: fblock, SourceURL: SourceURL}:
+		default:
+			logger.Warnf("Filtered block event buffer is full. Dropping block [%d].", block.Header.Number)
+		}
+	}
+
+	if reg, ok := h.txRegs[tx.txID]; ok {
+		select {
+		case reg.eventch <- &fab.TxStatusEvent{TxID: tx.txID, TxValidationCode: tx.code, BlockNumber: block.Header.Number, SourceURL: SourceURL}:
+		default:
+			logger.Warnf("Tx status event buffer is full. Dropping event for TX [%s].", tx.txID)
+		}
+	}
+
+	// As with Fabric, chaincode events are only emitted for valid transactions
+	if tx.eventName == "" || tx.code != pb.TxValidationCode_VALID {
+		return
+	}
+
+	for reg := range h.ccRegs {
+		if reg.ccID != tx.chaincodeID || !reg.eventFilter.MatchString(tx.eventName) {
+			continue
+		}
+		ccEvent := &fab.CCEvent{
+			TxID:        tx.txID,
+			ChaincodeID: tx.chaincodeID,
+			EventName:   tx.eventName,
+			Payload:     tx.payload,
+			BlockNumber: block.Header.Number,
+			SourceURL:   SourceURL,
+		}
+		select {
+		case reg.eventch <- ccEvent:
+		default:
+			logger.Warnf("Chaincode event buffer is full. Dropping event [%s] for TX [%s].", tx.eventName, tx.txID)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fakeclient
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ api.ChannelClient = (*ChannelClient)(nil)
+var _ api.LedgerClient = (*LedgerClient)(nil)
+var _ api.EventClient = (*EventClient)(nil)
+
+const (
+	channelID = "mychannel"
+	ccID      = "examplecc"
+)
+
+// exampleCC sets the value of a key ("set") and returns the value of a key ("get")
+func exampleCC(stub *Stub, fcn string, args [][]byte) ([]byte, error) {
+	switch fcn {
+	case "set":
+		stub.PutState(string(args[0]), args[1])
+		stub.SetEvent("set", args[0])
+		return nil, nil
+	case "get":
+		return stub.GetState(string(args[0])), nil
+	case "del":
+		stub.DelState(string(args[0]))
+		return nil, nil
+	default:
+		return nil, errors.Errorf("unknown function [%s]", fcn)
+	}
+}
+
+func newTestLedger() *Ledger {
+	ledger := NewLedger(channelID)
+	ledger.RegisterChaincode(ccID, exampleCC)
+	return ledger
+}
+
+func TestExecuteAndQuery(t *testing.T) {
+	ledger := newTestLedger()
+	chClient := NewChannelClient(ledger)
+
+	resp, err := chClient.Execute(channel.Request{ChaincodeID: ccID, Fcn: "set", Args: [][]byte{[]byte("a"), []byte("1")}})
+	require.NoError(t, err)
+	assert.Equal(t, pb.TxValidationCode_VALID, resp.TxValidationCode)
+	assert.NotEmpty(t, resp.TransactionID)
+	assert.Equal(t, []byte("1"), ledger.GetState(ccID, "a"))
+
+	resp, err = chClient.Query(channel.Request{ChaincodeID: ccID, Fcn: "get", Args: [][]byte{[]byte("a")}})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), resp.Payload)
+	assert.Equal(t, int32(200), resp.ChaincodeStatus)
+	assert.Equal(t, uint64(1), ledger.Height(), "query should not commit a block")
+
+	_, err = chClient.Execute(channel.Request{ChaincodeID: ccID, Fcn: "del", Args: [][]byte{[]byte("a")}})
+	require.NoError(t, err)
+	assert.Nil(t, ledger.GetState(ccID, "a"))
+
+	_, err = chClient.Query(channel.Request{ChaincodeID: ccID, Fcn: "unknown"})
+	s, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.ChaincodeStatus, s.Group)
+	assert.Equal(t, int32(500), s.Code)
+
+	_, err = chClient.Query(channel.Request{ChaincodeID: "othercc", Fcn: "get"})
+	assert.Error(t, err)
+
+	_, err = chClient.Execute(channel.Request{ChaincodeID: ccID})
+	assert.Error(t, err)
+}
+
+func TestQueryIntoAndHeight(t *testing.T) {
+	ledger := newTestLedger()
+	chClient := NewChannelClient(ledger)
+
+	var value map[string]int
+	_, err := chClient.ExecuteInto(channel.Request{ChaincodeID: ccID, Fcn: "set", Args: [][]byte{[]byte("a"), []byte(`{"x":1}`)}}, nil)
+	require.NoError(t, err)
+
+	_, err = chClient.QueryAtHeight(channel.Request{ChaincodeID: ccID, Fcn: "get", Args: [][]byte{[]byte("a")}}, 2)
+	assert.Error(t, err, "expecting error since the ledger is below the height")
+	assert.Error(t, chClient.AwaitPeerHeight(nil, 2))
+	require.NoError(t, chClient.AwaitPeerHeight(nil, 1))
+
+	_, err = chClient.QueryInto(channel.Request{ChaincodeID: ccID, Fcn: "get", Args: [][]byte{[]byte("a")}}, &value)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"x": 1}, value)
+
+	_, err = chClient.SubmitEnvelope([]byte("envelope"))
+	assert.Error(t, err)
+	assert.Equal(t, channel.ImplicitCollectionName(MSPID), chClient.ImplicitCollectionName())
+
+	it := chClient.NewPageIterator(channel.Request{ChaincodeID: ccID, Fcn: "get"}, 10, func(payload []byte) (int, string, error) {
+		return 1, "", nil
+	})
+	pages := 0
+	for it.Next() {
+		pages++
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, 1, pages)
+}
+
+func TestValidationCodes(t *testing.T) {
+	ledger := newTestLedger()
+	chClient := NewChannelClient(ledger)
+
+	ledger.SetValidationFunc(func(txID string, request channel.Request) pb.TxValidationCode {
+		return pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+	})
+
+	resp, err := chClient.Execute(channel.Request{ChaincodeID: ccID, Fcn: "set", Args: [][]byte{[]byte("a"), []byte("1")}})
+	require.Error(t, err)
+	assert.Equal(t, pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE, resp.TxValidationCode)
+	s, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.EventServerStatus, s.Group)
+	assert.Equal(t, int32(pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE), s.Code)
+	assert.Nil(t, ledger.GetState(ccID, "a"), "invalid transaction should not update the state")
+	assert.Equal(t, uint64(1), ledger.Height(), "invalid transaction should be committed to a block")
+
+	ledger.SetValidationFunc(nil)
+
+	// Simulate a concurrent update between simulation and commit
+	request := channel.Request{ChaincodeID: ccID, Fcn: "get", Args: [][]byte{[]byte("b")}}
+	stub, _, err := ledger.simulate(ledger.newTxID(), request)
+	require.NoError(t, err)
+	stub.PutState("c", []byte("1"))
+
+	_, err = chClient.Execute(channel.Request{ChaincodeID: ccID, Fcn: "set", Args: [][]byte{[]byte("b"), []byte("2")}})
+	require.NoError(t, err)
+
+	code, err := ledger.commit(stub, request)
+	require.NoError(t, err)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, code)
+	assert.Nil(t, ledger.GetState(ccID, "c"))
+}
+
+func TestEvents(t *testing.T) {
+	ledger := newTestLedger()
+	chClient := NewChannelClient(ledger)
+	eventClient := NewEventClient(ledger)
+
+	breg, blockch, err := eventClient.RegisterBlockEvent()
+	require.NoError(t, err)
+	defer eventClient.Unregister(breg)
+
+	freg, fblockch, err := eventClient.RegisterFilteredBlockEvent()
+	require.NoError(t, err)
+	defer eventClient.Unregister(freg)
+
+	ccreg, ccch, err := chClient.RegisterChaincodeEvent(ccID, "se.*")
+	require.NoError(t, err)
+	defer chClient.UnregisterChaincodeEvent(ccreg)
+
+	_, _, err = eventClient.RegisterChaincodeEvent(ccID, "[")
+	assert.Error(t, err)
+
+	resp, err := chClient.Execute(channel.Request{ChaincodeID: ccID, Fcn: "set", Args: [][]byte{[]byte("a"), []byte("1")}})
+	require.NoError(t, err)
+
+	select {
+	case e := <-blockch:
+		assert.Equal(t, uint64(0), e.Block.Header.Number)
+		assert.Equal(t, SourceURL, e.SourceURL)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for block event")
+	}
+
+	select {
+	case e := <-fblockch:
+		require.Len(t, e.FilteredBlock.FilteredTransactions, 1)
+		assert.Equal(t, string(resp.TransactionID), e.FilteredBlock.FilteredTransactions[0].Txid)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for filtered block event")
+	}
+
+	select {
+	case e := <-ccch:
+		assert.Equal(t, "set", e.EventName)
+		assert.Equal(t, []byte("a"), e.Payload)
+		assert.Equal(t, string(resp.TransactionID), e.TxID)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for chaincode event")
+	}
+
+	txID := "tx1"
+	treg, txch, err := eventClient.RegisterTxStatusEvent(txID)
+	require.NoError(t, err)
+	_, _, err = eventClient.RegisterTxStatusEvent(txID)
+	assert.Error(t, err, "expecting error for duplicate registration")
+
+	stub, _, err := ledger.simulate(txID, channel.Request{ChaincodeID: ccID, Fcn: "get", Args: [][]byte{[]byte("a")}})
+	require.NoError(t, err)
+	_, err = ledger.commit(stub, channel.Request{})
+	require.NoError(t, err)
+
+	select {
+	case e := <-txch:
+		assert.Equal(t, pb.TxValidationCode_VALID, e.TxValidationCode)
+		assert.Equal(t, uint64(1), e.BlockNumber)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for tx status event")
+	}
+
+	eventClient.Unregister(treg)
+	_, ok := <-txch
+	assert.False(t, ok, "expecting event channel to be closed")
+}
+
+func TestDecodedEventsAndStreamState(t *testing.T) {
+	ledger := newTestLedger()
+	chClient := NewChannelClient(ledger)
+	eventClient := NewEventClient(ledger)
+
+	state, err := eventClient.StreamState()
+	require.NoError(t, err)
+	assert.Equal(t, SourceURL, state.PeerURL)
+	assert.Equal(t, uint64(math.MaxUint64), state.LastBlockNum)
+
+	_, _, err = eventClient.RegisterDecodedChaincodeEvent(ccID, "set")
+	assert.Error(t, err, "expecting error since no decoder is set")
+
+	eventClient.SetChaincodeEventDecoder(ccID, func(e *fab.CCEvent) (interface{}, error) {
+		return string(e.Payload), nil
+	})
+	reg, eventch, err := eventClient.RegisterDecodedChaincodeEvent(ccID, "set")
+	require.NoError(t, err)
+	defer eventClient.Unregister(reg)
+
+	_, err = chClient.Execute(channel.Request{ChaincodeID: ccID, Fcn: "set", Args: [][]byte{[]byte("a"), []byte("1")}})
+	require.NoError(t, err)
+
+	select {
+	case e := <-eventch:
+		require.NoError(t, e.Err)
+		assert.Equal(t, "a", e.Value)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for decoded chaincode event")
+	}
+
+	state, err = eventClient.StreamState()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), state.LastBlockNum)
+	assert.False(t, state.LastEventTime.IsZero())
+}
+
+func TestLedgerClient(t *testing.T) {
+	ledger := newTestLedger()
+	chClient := NewChannelClient(ledger)
+	ledgerClient := NewLedgerClient(ledger)
+
+	var txIDs []fab.TransactionID
+	for _, v := range []string{"1", "2"} {
+		resp, err := chClient.Execute(channel.Request{ChaincodeID: ccID, Fcn: "set", Args: [][]byte{[]byte("a"), []byte(v)}})
+		require.NoError(t, err)
+		txIDs = append(txIDs, resp.TransactionID)
+	}
+
+	info, err := ledgerClient.QueryInfo()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), info.BCI.Height)
+
+	block, err := ledgerClient.QueryBlock(1)
+	require.NoError(t, err)
+	assert.Equal(t, info.BCI.PreviousBlockHash, block.Header.PreviousHash)
+
+	block, err = ledgerClient.QueryBlockByHash(info.BCI.CurrentBlockHash)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), block.Header.Number)
+
+	block, err = ledgerClient.QueryBlockByTxID(txIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), block.Header.Number)
+
+	tx, err := ledgerClient.QueryTransaction(txIDs[1])
+	require.NoError(t, err)
+	assert.Equal(t, int32(pb.TxValidationCode_VALID), tx.ValidationCode)
+	assert.NotNil(t, tx.TransactionEnvelope)
+
+	_, err = ledgerClient.QueryBlock(2)
+	assert.Error(t, err)
+	_, err = ledgerClient.QueryTransaction("unknown")
+	assert.Error(t, err)
+
+	cfg, err := ledgerClient.QueryConfig()
+	require.NoError(t, err)
+	assert.Equal(t, channelID, cfg.ID())
+}
@@ -0,0 +1,268 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fakeclient provides in-memory fake implementations of the SDK client interfaces
+// (see pkg/client/api) so that applications can be unit tested without a live network.
+//
+// The fakes share an in-memory Ledger per channel. Chaincodes are simulated by Go functions which
+// read and write the ledger state through a Stub. Executed transactions are validated (including MVCC
+// read conflict detection and optional validation code simulation), committed to a new block and the
+// corresponding block, filtered block, chaincode and transaction status events are emitted.
+//
+//  Basic Flow:
+//  1) Create a ledger using NewLedger and register chaincodes using Ledger.RegisterChaincode
+//  2) Create the fake clients using NewChannelClient, NewEventClient and NewLedgerClient
+//  3) Pass the fake clients to the code under test in place of the SDK clients
+package fakeclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// ValidationFunc returns the validation code of a transaction. It may be used
+// to simulate transactions that are invalidated by the committing peers (for example
+// an endorsement policy failure).
+type ValidationFunc func(txID string, request channel.Request) pb.TxValidationCode
+
+type versionedValue struct {
+	value   []byte
+	version uint64
+}
+
+type txEntry struct {
+	blockNum uint64
+	code     pb.TxValidationCode
+	envelope *cb.Envelope
+}
+
+// Ledger is an in-memory channel ledger which is shared by the fake clients
+type Ledger struct {
+	mutex      sync.RWMutex
+	channelID  string
+	chaincodes map[string]Chaincode
+	state      map[string]map[string]*versionedValue
+	blocks     []*cb.Block
+	txs        map[string]*txEntry
+	txNum      uint64
+	validate   ValidationFunc
+	events     *eventHub
+	lastCommit time.Time
+}
+
+// NewLedger returns a new ledger for the given channel
+func NewLedger(channelID string) *Ledger {
+	return &Ledger{
+		channelID:  channelID,
+		chaincodes: make(map[string]Chaincode),
+		state:      make(map[string]map[string]*versionedValue),
+		txs:        make(map[string]*txEntry),
+		events:     newEventHub(),
+	}
+}
+
+// ChannelID returns the ID of the channel
+func (l *Ledger) ChannelID() string {
+	return l.channelID
+}
+
+// RegisterChaincode registers the given chaincode simulation
+func (l *Ledger) RegisterChaincode(ccID string, cc Chaincode) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.chaincodes[ccID] = cc
+}
+
+// SetValidationFunc sets the function that determines the validation code of committed transactions.
+// Transactions are valid by default (unless an MVCC read conflict is detected).
+func (l *Ledger) SetValidationFunc(validate ValidationFunc) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.validate = validate
+}
+
+// GetState returns the committed value of the given key of the given chaincode
+func (l *Ledger) GetState(ccID, key string) []byte {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if v, ok := l.state[ccID][key]; ok {
+		return v.value
+	}
+	return nil
+}
+
+// PutState sets the committed value of the given key of the given chaincode
+// (used to initialize the state of a test)
+func (l *Ledger) PutState(ccID, key string, value []byte) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.ccState(ccID)[key] = &versionedValue{value: value, version: uint64(len(l.blocks)) + 1}
+}
+
+// Height returns the number of blocks in the ledger
+func (l *Ledger) Height() uint64 {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return uint64(len(l.blocks))
+}
+
+func (l *Ledger) ccState(ccID string) map[string]*versionedValue {
+	state, ok := l.state[ccID]
+	if !ok {
+		state = make(map[string]*versionedValue)
+		l.state[ccID] = state
+	}
+	return state
+}
+
+func (l *Ledger) newTxID() string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.txNum++
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s-%d", l.channelID, l.txNum)))
+	return hex.EncodeToString(hash[:])
+}
+
+// simulate invokes the chaincode against a snapshot of the committed state
+func (l *Ledger) simulate(txID string, request channel.Request) (*Stub, []byte, error) {
+	l.mutex.RLock()
+	cc, ok := l.chaincodes[request.ChaincodeID]
+	snapshot := make(map[string]*versionedValue)
+	for k, v := range l.state[request.ChaincodeID] {
+		snapshot[k] = v
+	}
+	l.mutex.RUnlock()
+
+	if !ok {
+		return nil, nil, errors.Errorf("chaincode [%s] is not registered on channel [%s]", request.ChaincodeID, l.channelID)
+	}
+
+	stub := newStub(l.channelID, request.ChaincodeID, txID, request.TransientMap, snapshot)
+	payload, err := cc(stub, request.Fcn, request.Args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stub, payload, nil
+}
+
+// commit validates the simulated transaction, commits it to a new block and emits the events
+func (l *Ledger) commit(stub *Stub, request channel.Request) (pb.TxValidationCode, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	code := pb.TxValidationCode_VALID
+	if l.validate != nil {
+		code = l.validate(stub.txID, request)
+	}
+
+	state := l.ccState(stub.ccID)
+	if code == pb.TxValidationCode_VALID {
+		for key, version := range stub.reads {
+			current := uint64(0)
+			if v, ok := state[key]; ok {
+				current = v.version
+			}
+			if current != version {
+				logger.Debugf("MVCC read conflict on key [%s] of chaincode [%s] in transaction [%s]", key, stub.ccID, stub.txID)
+				code = pb.TxValidationCode_MVCC_READ_CONFLICT
+				break
+			}
+		}
+	}
+
+	tx := &txInfo{txID: stub.txID, code: code, chaincodeID: stub.ccID}
+	if stub.event != nil {
+		tx.eventName = stub.event.name
+		tx.payload = stub.event.payload
+	}
+
+	blockNum := uint64(len(l.blocks))
+	var previousHash []byte
+	if blockNum > 0 {
+		var err error
+		if previousHash, err = blockHash(l.blocks[blockNum-1]); err != nil {
+			return code, err
+		}
+	}
+
+	envelope, err := newEnvelope(l.channelID, tx)
+	if err != nil {
+		return code, err
+	}
+	block, err := newBlock(blockNum, previousHash, envelope, code)
+	if err != nil {
+		return code, err
+	}
+
+	if code == pb.TxValidationCode_VALID {
+		// Versions start at one so that a key which was read as missing conflicts with a later write
+		for key, value := range stub.writes {
+			state[key] = &versionedValue{value: value, version: blockNum + 1}
+		}
+		for key := range stub.deletes {
+			delete(state, key)
+		}
+	}
+
+	l.blocks = append(l.blocks, block)
+	l.txs[stub.txID] = &txEntry{blockNum: blockNum, code: code, envelope: envelope}
+	l.lastCommit = time.Now()
+
+	// Events are published while holding the lock so that they are emitted in block order
+	l.events.publish(l.channelID, block, tx)
+
+	return code, nil
+}
+
+func (l *Ledger) block(blockNum uint64) (*cb.Block, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if blockNum >= uint64(len(l.blocks)) {
+		return nil, errors.Errorf("block [%d] not found", blockNum)
+	}
+	return l.blocks[blockNum], nil
+}
+
+func (l *Ledger) blockByHash(hash []byte) (*cb.Block, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	for _, block := range l.blocks {
+		h, err := blockHash(block)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(h, hash) {
+			return block, nil
+		}
+	}
+	return nil, errors.New("block not found")
+}
+
+func (l *Ledger) transaction(txID string) (*txEntry, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	tx, ok := l.txs[txID]
+	if !ok {
+		return nil, errors.Errorf("transaction [%s] not found", txID)
+	}
+	return tx, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fakeclient
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// LedgerClient is an in-memory implementation of the ledger client. Request options are
+// ignored since the requests are not sent to any peers.
+type LedgerClient struct {
+	ledger *Ledger
+}
+
+// NewLedgerClient returns a new ledger client for the given ledger
+func NewLedgerClient(ledger *Ledger) *LedgerClient {
+	return &LedgerClient{ledger: ledger}
+}
+
+// QueryInfo returns the height and the hashes of the last blocks of the ledger
+func (c *LedgerClient) QueryInfo(options ...ledger.RequestOption) (*fab.BlockchainInfoResponse, error) {
+	c.ledger.mutex.RLock()
+	defer c.ledger.mutex.RUnlock()
+
+	bci := &common.BlockchainInfo{Height: uint64(len(c.ledger.blocks))}
+	if n := len(c.ledger.blocks); n > 0 {
+		hash, err := blockHash(c.ledger.blocks[n-1])
+		if err != nil {
+			return nil, err
+		}
+		bci.CurrentBlockHash = hash
+		bci.PreviousBlockHash = c.ledger.blocks[n-1].Header.PreviousHash
+	}
+
+	return &fab.BlockchainInfoResponse{BCI: bci, Endorser: SourceURL, Status: chaincodeOKStatus}, nil
+}
+
+// QueryBlockByHash returns the block with the given hash
+func (c *LedgerClient) QueryBlockByHash(blockHash []byte, options ...ledger.RequestOption) (*common.Block, error) {
+	return c.ledger.blockByHash(blockHash)
+}
+
+// QueryBlockByTxID returns the block containing the given transaction
+func (c *LedgerClient) QueryBlockByTxID(txID fab.TransactionID, options ...ledger.RequestOption) (*common.Block, error) {
+	tx, err := c.ledger.transaction(string(txID))
+	if err != nil {
+		return nil, err
+	}
+	return c.ledger.block(tx.blockNum)
+}
+
+// QueryBlock returns the block with the given number
+func (c *LedgerClient) QueryBlock(blockNumber uint64, options ...ledger.RequestOption) (*common.Block, error) {
+	return c.ledger.block(blockNumber)
+}
+
+// QueryTransaction returns the processed transaction with the given ID
+func (c *LedgerClient) QueryTransaction(transactionID fab.TransactionID, options ...ledger.RequestOption) (*pb.ProcessedTransaction, error) {
+	tx, err := c.ledger.transaction(string(transactionID))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ProcessedTransaction{TransactionEnvelope: tx.envelope, ValidationCode: int32(tx.code)}, nil
+}
+
+// QueryConfig returns a mock channel configuration for the channel of the ledger
+func (c *LedgerClient) QueryConfig(options ...ledger.RequestOption) (fab.ChannelCfg, error) {
+	return mocks.NewMockChannelCfg(c.ledger.channelID), nil
+}
+
+// QueryMissingPrivateData returns no missing private data since private data is held
+// in the chaincode state of the fake ledger
+func (c *LedgerClient) QueryMissingPrivateData(req ledger.MissingPrivateDataRequest, options ...ledger.RequestOption) ([]ledger.MissingPrivateData, error) {
+	return nil, nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fakeclient
+
+// Chaincode simulates a chaincode. The function is invoked with a stub that provides
+// access to the chaincode's state. The returned payload is set in the channel response.
+// If an error is returned then the invocation fails with a chaincode status error.
+type Chaincode func(stub *Stub, fcn string, args [][]byte) ([]byte, error)
+
+// Stub provides a chaincode with access to the ledger state during simulation. Writes
+// are only applied to the ledger if the transaction is committed as valid.
+type Stub struct {
+	channelID string
+	ccID      string
+	txID      string
+	transient map[string][]byte
+	state     map[string]*versionedValue
+	reads     map[string]uint64
+	writes    map[string][]byte
+	deletes   map[string]bool
+	event     *ccEvent
+}
+
+type ccEvent struct {
+	name    string
+	payload []byte
+}
+
+func newStub(channelID, ccID, txID string, transient map[string][]byte, state map[string]*versionedValue) *Stub {
+	return &Stub{
+		channelID: channelID,
+		ccID:      ccID,
+		txID:      txID,
+		transient: transient,
+		state:     state,
+		reads:     make(map[string]uint64),
+		writes:    make(map[string][]byte),
+		deletes:   make(map[string]bool),
+	}
+}
+
+// GetChannelID returns the ID of the channel
+func (s *Stub) GetChannelID() string {
+	return s.channelID
+}
+
+// GetTxID returns the ID of the transaction
+func (s *Stub) GetTxID() string {
+	return s.txID
+}
+
+// GetTransient returns the transient data of the request
+func (s *Stub) GetTransient() map[string][]byte {
+	return s.transient
+}
+
+// GetState returns the value of the given key. Keys written during the simulation
+// return the written value.
+func (s *Stub) GetState(key string) []byte {
+	if s.deletes[key] {
+		return nil
+	}
+	if value, ok := s.writes[key]; ok {
+		return value
+	}
+
+	v, ok := s.state[key]
+	if !ok {
+		s.reads[key] = 0
+		return nil
+	}
+	s.reads[key] = v.version
+	return v.value
+}
+
+// PutState writes the given value
+func (s *Stub) PutState(key string, value []byte) {
+	delete(s.deletes, key)
+	s.writes[key] = value
+}
+
+// DelState deletes the given key
+func (s *Stub) DelState(key string) {
+	delete(s.writes, key)
+	s.deletes[key] = true
+}
+
+// SetEvent sets the chaincode event which is emitted when the transaction is committed
+func (s *Stub) SetEvent(name string, payload []byte) {
+	s.event = &ccEvent{name: name, payload: payload}
+}
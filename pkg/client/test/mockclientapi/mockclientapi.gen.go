@@ -0,0 +1,1238 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/hyperledger/fabric-sdk-go/pkg/client/api (interfaces: ChannelClient,ResMgmtClient,LedgerClient,EventClient)
+
+// Package mockclientapi is a generated GoMock package.
+package mockclientapi
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	channel "github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	invoke "github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	event "github.com/hyperledger/fabric-sdk-go/pkg/client/event"
+	ledger "github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	resmgmt "github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	fab "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	configtx "github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	dispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
+	common "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	peer "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// MockChannelClient is a mock of ChannelClient interface
+type MockChannelClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockChannelClientMockRecorder
+}
+
+// MockChannelClientMockRecorder is the mock recorder for MockChannelClient
+type MockChannelClientMockRecorder struct {
+	mock *MockChannelClient
+}
+
+// NewMockChannelClient creates a new mock instance
+func NewMockChannelClient(ctrl *gomock.Controller) *MockChannelClient {
+	mock := &MockChannelClient{ctrl: ctrl}
+	mock.recorder = &MockChannelClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockChannelClient) EXPECT() *MockChannelClientMockRecorder {
+	return m.recorder
+}
+
+// AwaitPeerHeight mocks base method
+func (m *MockChannelClient) AwaitPeerHeight(arg0 fab.Peer, arg1 uint64, arg2 ...channel.RequestOption) error {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AwaitPeerHeight", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AwaitPeerHeight indicates an expected call of AwaitPeerHeight
+func (mr *MockChannelClientMockRecorder) AwaitPeerHeight(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AwaitPeerHeight", reflect.TypeOf((*MockChannelClient)(nil).AwaitPeerHeight), varargs...)
+}
+
+// Execute mocks base method
+func (m *MockChannelClient) Execute(arg0 channel.Request, arg1 ...channel.RequestOption) (channel.Response, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Execute", varargs...)
+	ret0, _ := ret[0].(channel.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute
+func (mr *MockChannelClientMockRecorder) Execute(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockChannelClient)(nil).Execute), varargs...)
+}
+
+// ExecuteImplicitCollection mocks base method
+func (m *MockChannelClient) ExecuteImplicitCollection(arg0 channel.Request, arg1 ...channel.RequestOption) (channel.Response, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExecuteImplicitCollection", varargs...)
+	ret0, _ := ret[0].(channel.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteImplicitCollection indicates an expected call of ExecuteImplicitCollection
+func (mr *MockChannelClientMockRecorder) ExecuteImplicitCollection(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteImplicitCollection", reflect.TypeOf((*MockChannelClient)(nil).ExecuteImplicitCollection), varargs...)
+}
+
+// ExecuteInto mocks base method
+func (m *MockChannelClient) ExecuteInto(arg0 channel.Request, arg1 interface{}, arg2 ...channel.RequestOption) (channel.Response, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExecuteInto", varargs...)
+	ret0, _ := ret[0].(channel.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteInto indicates an expected call of ExecuteInto
+func (mr *MockChannelClientMockRecorder) ExecuteInto(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteInto", reflect.TypeOf((*MockChannelClient)(nil).ExecuteInto), varargs...)
+}
+
+// ImplicitCollectionName mocks base method
+func (m *MockChannelClient) ImplicitCollectionName() string {
+	ret := m.ctrl.Call(m, "ImplicitCollectionName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ImplicitCollectionName indicates an expected call of ImplicitCollectionName
+func (mr *MockChannelClientMockRecorder) ImplicitCollectionName() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImplicitCollectionName", reflect.TypeOf((*MockChannelClient)(nil).ImplicitCollectionName))
+}
+
+// InvokeHandler mocks base method
+func (m *MockChannelClient) InvokeHandler(arg0 invoke.Handler, arg1 channel.Request, arg2 ...channel.RequestOption) (channel.Response, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InvokeHandler", varargs...)
+	ret0, _ := ret[0].(channel.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InvokeHandler indicates an expected call of InvokeHandler
+func (mr *MockChannelClientMockRecorder) InvokeHandler(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvokeHandler", reflect.TypeOf((*MockChannelClient)(nil).InvokeHandler), varargs...)
+}
+
+// NewPageIterator mocks base method
+func (m *MockChannelClient) NewPageIterator(arg0 channel.Request, arg1 int32, arg2 channel.PageDecoder, arg3 ...channel.RequestOption) *channel.PageIterator {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewPageIterator", varargs...)
+	ret0, _ := ret[0].(*channel.PageIterator)
+	return ret0
+}
+
+// NewPageIterator indicates an expected call of NewPageIterator
+func (mr *MockChannelClientMockRecorder) NewPageIterator(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewPageIterator", reflect.TypeOf((*MockChannelClient)(nil).NewPageIterator), varargs...)
+}
+
+// Query mocks base method
+func (m *MockChannelClient) Query(arg0 channel.Request, arg1 ...channel.RequestOption) (channel.Response, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Query", varargs...)
+	ret0, _ := ret[0].(channel.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Query indicates an expected call of Query
+func (mr *MockChannelClientMockRecorder) Query(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockChannelClient)(nil).Query), varargs...)
+}
+
+// QueryAtHeight mocks base method
+func (m *MockChannelClient) QueryAtHeight(arg0 channel.Request, arg1 uint64, arg2 ...channel.RequestOption) (channel.Response, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryAtHeight", varargs...)
+	ret0, _ := ret[0].(channel.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryAtHeight indicates an expected call of QueryAtHeight
+func (mr *MockChannelClientMockRecorder) QueryAtHeight(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryAtHeight", reflect.TypeOf((*MockChannelClient)(nil).QueryAtHeight), varargs...)
+}
+
+// QueryImplicitCollection mocks base method
+func (m *MockChannelClient) QueryImplicitCollection(arg0 channel.Request, arg1 ...channel.RequestOption) (channel.Response, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryImplicitCollection", varargs...)
+	ret0, _ := ret[0].(channel.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryImplicitCollection indicates an expected call of QueryImplicitCollection
+func (mr *MockChannelClientMockRecorder) QueryImplicitCollection(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryImplicitCollection", reflect.TypeOf((*MockChannelClient)(nil).QueryImplicitCollection), varargs...)
+}
+
+// QueryInto mocks base method
+func (m *MockChannelClient) QueryInto(arg0 channel.Request, arg1 interface{}, arg2 ...channel.RequestOption) (channel.Response, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryInto", varargs...)
+	ret0, _ := ret[0].(channel.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryInto indicates an expected call of QueryInto
+func (mr *MockChannelClientMockRecorder) QueryInto(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryInto", reflect.TypeOf((*MockChannelClient)(nil).QueryInto), varargs...)
+}
+
+// RegisterChaincodeEvent mocks base method
+func (m *MockChannelClient) RegisterChaincodeEvent(arg0, arg1 string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	ret := m.ctrl.Call(m, "RegisterChaincodeEvent", arg0, arg1)
+	ret0, _ := ret[0].(fab.Registration)
+	ret1, _ := ret[1].(<-chan *fab.CCEvent)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegisterChaincodeEvent indicates an expected call of RegisterChaincodeEvent
+func (mr *MockChannelClientMockRecorder) RegisterChaincodeEvent(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterChaincodeEvent", reflect.TypeOf((*MockChannelClient)(nil).RegisterChaincodeEvent), arg0, arg1)
+}
+
+// SubmitEnvelope mocks base method
+func (m *MockChannelClient) SubmitEnvelope(arg0 []byte, arg1 ...channel.RequestOption) (channel.Response, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SubmitEnvelope", varargs...)
+	ret0, _ := ret[0].(channel.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitEnvelope indicates an expected call of SubmitEnvelope
+func (mr *MockChannelClientMockRecorder) SubmitEnvelope(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitEnvelope", reflect.TypeOf((*MockChannelClient)(nil).SubmitEnvelope), varargs...)
+}
+
+// UnregisterChaincodeEvent mocks base method
+func (m *MockChannelClient) UnregisterChaincodeEvent(arg0 fab.Registration) {
+	m.ctrl.Call(m, "UnregisterChaincodeEvent", arg0)
+}
+
+// UnregisterChaincodeEvent indicates an expected call of UnregisterChaincodeEvent
+func (mr *MockChannelClientMockRecorder) UnregisterChaincodeEvent(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnregisterChaincodeEvent", reflect.TypeOf((*MockChannelClient)(nil).UnregisterChaincodeEvent), arg0)
+}
+
+// VerifyPrivateData mocks base method
+func (m *MockChannelClient) VerifyPrivateData(arg0 channel.Request, arg1 []byte, arg2 ...channel.RequestOption) error {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "VerifyPrivateData", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyPrivateData indicates an expected call of VerifyPrivateData
+func (mr *MockChannelClientMockRecorder) VerifyPrivateData(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPrivateData", reflect.TypeOf((*MockChannelClient)(nil).VerifyPrivateData), varargs...)
+}
+
+// MockResMgmtClient is a mock of ResMgmtClient interface
+type MockResMgmtClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockResMgmtClientMockRecorder
+}
+
+// MockResMgmtClientMockRecorder is the mock recorder for MockResMgmtClient
+type MockResMgmtClientMockRecorder struct {
+	mock *MockResMgmtClient
+}
+
+// NewMockResMgmtClient creates a new mock instance
+func NewMockResMgmtClient(ctrl *gomock.Controller) *MockResMgmtClient {
+	mock := &MockResMgmtClient{ctrl: ctrl}
+	mock.recorder = &MockResMgmtClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockResMgmtClient) EXPECT() *MockResMgmtClientMockRecorder {
+	return m.recorder
+}
+
+// AddConsenter mocks base method
+func (m *MockResMgmtClient) AddConsenter(arg0 resmgmt.ConsenterRequest, arg1 ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddConsenter", varargs...)
+	ret0, _ := ret[0].(resmgmt.SaveChannelResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddConsenter indicates an expected call of AddConsenter
+func (mr *MockResMgmtClientMockRecorder) AddConsenter(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddConsenter", reflect.TypeOf((*MockResMgmtClient)(nil).AddConsenter), varargs...)
+}
+
+// BootstrapChannel mocks base method
+func (m *MockResMgmtClient) BootstrapChannel(arg0 resmgmt.BootstrapChannelRequest, arg1 ...resmgmt.RequestOption) (resmgmt.BootstrapChannelResponse, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BootstrapChannel", varargs...)
+	ret0, _ := ret[0].(resmgmt.BootstrapChannelResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BootstrapChannel indicates an expected call of BootstrapChannel
+func (mr *MockResMgmtClientMockRecorder) BootstrapChannel(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapChannel", reflect.TypeOf((*MockResMgmtClient)(nil).BootstrapChannel), varargs...)
+}
+
+// CancelSnapshotRequest mocks base method
+func (m *MockResMgmtClient) CancelSnapshotRequest(arg0 string, arg1 uint64, arg2 ...resmgmt.RequestOption) error {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CancelSnapshotRequest", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelSnapshotRequest indicates an expected call of CancelSnapshotRequest
+func (mr *MockResMgmtClientMockRecorder) CancelSnapshotRequest(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSnapshotRequest", reflect.TypeOf((*MockResMgmtClient)(nil).CancelSnapshotRequest), varargs...)
+}
+
+// InstallCC mocks base method
+func (m *MockResMgmtClient) InstallCC(arg0 resmgmt.InstallCCRequest, arg1 ...resmgmt.RequestOption) ([]resmgmt.InstallCCResponse, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InstallCC", varargs...)
+	ret0, _ := ret[0].([]resmgmt.InstallCCResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstallCC indicates an expected call of InstallCC
+func (mr *MockResMgmtClientMockRecorder) InstallCC(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallCC", reflect.TypeOf((*MockResMgmtClient)(nil).InstallCC), varargs...)
+}
+
+// InstantiateCC mocks base method
+func (m *MockResMgmtClient) InstantiateCC(arg0 string, arg1 resmgmt.InstantiateCCRequest, arg2 ...resmgmt.RequestOption) (resmgmt.InstantiateCCResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InstantiateCC", varargs...)
+	ret0, _ := ret[0].(resmgmt.InstantiateCCResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstantiateCC indicates an expected call of InstantiateCC
+func (mr *MockResMgmtClientMockRecorder) InstantiateCC(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstantiateCC", reflect.TypeOf((*MockResMgmtClient)(nil).InstantiateCC), varargs...)
+}
+
+// JoinChannel mocks base method
+func (m *MockResMgmtClient) JoinChannel(arg0 string, arg1 ...resmgmt.RequestOption) error {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "JoinChannel", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// JoinChannel indicates an expected call of JoinChannel
+func (mr *MockResMgmtClientMockRecorder) JoinChannel(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinChannel", reflect.TypeOf((*MockResMgmtClient)(nil).JoinChannel), varargs...)
+}
+
+// LifecycleApproveCC mocks base method
+func (m *MockResMgmtClient) LifecycleApproveCC(arg0 string, arg1 resmgmt.LifecycleApproveCCRequest, arg2 ...resmgmt.RequestOption) (fab.TransactionID, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LifecycleApproveCC", varargs...)
+	ret0, _ := ret[0].(fab.TransactionID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LifecycleApproveCC indicates an expected call of LifecycleApproveCC
+func (mr *MockResMgmtClientMockRecorder) LifecycleApproveCC(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LifecycleApproveCC", reflect.TypeOf((*MockResMgmtClient)(nil).LifecycleApproveCC), varargs...)
+}
+
+// LifecycleCheckCCCommitReadiness mocks base method
+func (m *MockResMgmtClient) LifecycleCheckCCCommitReadiness(arg0 string, arg1 resmgmt.LifecycleCCDefinition, arg2 ...resmgmt.RequestOption) (map[string]bool, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LifecycleCheckCCCommitReadiness", varargs...)
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LifecycleCheckCCCommitReadiness indicates an expected call of LifecycleCheckCCCommitReadiness
+func (mr *MockResMgmtClientMockRecorder) LifecycleCheckCCCommitReadiness(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LifecycleCheckCCCommitReadiness", reflect.TypeOf((*MockResMgmtClient)(nil).LifecycleCheckCCCommitReadiness), varargs...)
+}
+
+// LifecycleCommitCC mocks base method
+func (m *MockResMgmtClient) LifecycleCommitCC(arg0 string, arg1 resmgmt.LifecycleCCDefinition, arg2 ...resmgmt.RequestOption) (fab.TransactionID, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LifecycleCommitCC", varargs...)
+	ret0, _ := ret[0].(fab.TransactionID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LifecycleCommitCC indicates an expected call of LifecycleCommitCC
+func (mr *MockResMgmtClientMockRecorder) LifecycleCommitCC(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LifecycleCommitCC", reflect.TypeOf((*MockResMgmtClient)(nil).LifecycleCommitCC), varargs...)
+}
+
+// LifecycleInstallCC mocks base method
+func (m *MockResMgmtClient) LifecycleInstallCC(arg0 resmgmt.LifecycleInstallCCRequest, arg1 ...resmgmt.RequestOption) ([]resmgmt.LifecycleInstallCCResponse, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LifecycleInstallCC", varargs...)
+	ret0, _ := ret[0].([]resmgmt.LifecycleInstallCCResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LifecycleInstallCC indicates an expected call of LifecycleInstallCC
+func (mr *MockResMgmtClientMockRecorder) LifecycleInstallCC(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LifecycleInstallCC", reflect.TypeOf((*MockResMgmtClient)(nil).LifecycleInstallCC), varargs...)
+}
+
+// LifecycleQueryApprovalMatrix mocks base method
+func (m *MockResMgmtClient) LifecycleQueryApprovalMatrix(arg0 string, arg1 resmgmt.LifecycleCCDefinition, arg2 ...resmgmt.RequestOption) (resmgmt.LifecycleApprovalMatrix, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LifecycleQueryApprovalMatrix", varargs...)
+	ret0, _ := ret[0].(resmgmt.LifecycleApprovalMatrix)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LifecycleQueryApprovalMatrix indicates an expected call of LifecycleQueryApprovalMatrix
+func (mr *MockResMgmtClientMockRecorder) LifecycleQueryApprovalMatrix(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LifecycleQueryApprovalMatrix", reflect.TypeOf((*MockResMgmtClient)(nil).LifecycleQueryApprovalMatrix), varargs...)
+}
+
+// LifecycleQueryApprovedCC mocks base method
+func (m *MockResMgmtClient) LifecycleQueryApprovedCC(arg0 string, arg1 resmgmt.LifecycleQueryApprovedCCRequest, arg2 ...resmgmt.RequestOption) (resmgmt.LifecycleApprovedCC, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LifecycleQueryApprovedCC", varargs...)
+	ret0, _ := ret[0].(resmgmt.LifecycleApprovedCC)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LifecycleQueryApprovedCC indicates an expected call of LifecycleQueryApprovedCC
+func (mr *MockResMgmtClientMockRecorder) LifecycleQueryApprovedCC(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LifecycleQueryApprovedCC", reflect.TypeOf((*MockResMgmtClient)(nil).LifecycleQueryApprovedCC), varargs...)
+}
+
+// LifecycleQueryCommittedCC mocks base method
+func (m *MockResMgmtClient) LifecycleQueryCommittedCC(arg0 string, arg1 resmgmt.LifecycleQueryCommittedCCRequest, arg2 ...resmgmt.RequestOption) ([]resmgmt.LifecycleCommittedCC, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LifecycleQueryCommittedCC", varargs...)
+	ret0, _ := ret[0].([]resmgmt.LifecycleCommittedCC)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LifecycleQueryCommittedCC indicates an expected call of LifecycleQueryCommittedCC
+func (mr *MockResMgmtClientMockRecorder) LifecycleQueryCommittedCC(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LifecycleQueryCommittedCC", reflect.TypeOf((*MockResMgmtClient)(nil).LifecycleQueryCommittedCC), varargs...)
+}
+
+// LifecycleQueryInstalledCC mocks base method
+func (m *MockResMgmtClient) LifecycleQueryInstalledCC(arg0 ...resmgmt.RequestOption) ([]resmgmt.LifecycleInstalledCC, error) {
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LifecycleQueryInstalledCC", varargs...)
+	ret0, _ := ret[0].([]resmgmt.LifecycleInstalledCC)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LifecycleQueryInstalledCC indicates an expected call of LifecycleQueryInstalledCC
+func (mr *MockResMgmtClientMockRecorder) LifecycleQueryInstalledCC(arg0 ...interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LifecycleQueryInstalledCC", reflect.TypeOf((*MockResMgmtClient)(nil).LifecycleQueryInstalledCC), arg0...)
+}
+
+// LifecycleVerifyInstalledCC mocks base method
+func (m *MockResMgmtClient) LifecycleVerifyInstalledCC(arg0 string, arg1 ...resmgmt.RequestOption) error {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LifecycleVerifyInstalledCC", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LifecycleVerifyInstalledCC indicates an expected call of LifecycleVerifyInstalledCC
+func (mr *MockResMgmtClientMockRecorder) LifecycleVerifyInstalledCC(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LifecycleVerifyInstalledCC", reflect.TypeOf((*MockResMgmtClient)(nil).LifecycleVerifyInstalledCC), varargs...)
+}
+
+// QueryBlockFromOrderer mocks base method
+func (m *MockResMgmtClient) QueryBlockFromOrderer(arg0 string, arg1 uint64, arg2 ...resmgmt.RequestOption) (*common.Block, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryBlockFromOrderer", varargs...)
+	ret0, _ := ret[0].(*common.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryBlockFromOrderer indicates an expected call of QueryBlockFromOrderer
+func (mr *MockResMgmtClientMockRecorder) QueryBlockFromOrderer(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBlockFromOrderer", reflect.TypeOf((*MockResMgmtClient)(nil).QueryBlockFromOrderer), varargs...)
+}
+
+// QueryChannels mocks base method
+func (m *MockResMgmtClient) QueryChannels(arg0 ...resmgmt.RequestOption) (*peer.ChannelQueryResponse, error) {
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryChannels", varargs...)
+	ret0, _ := ret[0].(*peer.ChannelQueryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryChannels indicates an expected call of QueryChannels
+func (mr *MockResMgmtClientMockRecorder) QueryChannels(arg0 ...interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryChannels", reflect.TypeOf((*MockResMgmtClient)(nil).QueryChannels), arg0...)
+}
+
+// QueryConfigBlockFromOrderer mocks base method
+func (m *MockResMgmtClient) QueryConfigBlockFromOrderer(arg0 string, arg1 ...resmgmt.RequestOption) (*common.Block, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryConfigBlockFromOrderer", varargs...)
+	ret0, _ := ret[0].(*common.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryConfigBlockFromOrderer indicates an expected call of QueryConfigBlockFromOrderer
+func (mr *MockResMgmtClientMockRecorder) QueryConfigBlockFromOrderer(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConfigBlockFromOrderer", reflect.TypeOf((*MockResMgmtClient)(nil).QueryConfigBlockFromOrderer), varargs...)
+}
+
+// QueryConfigFromOrderer mocks base method
+func (m *MockResMgmtClient) QueryConfigFromOrderer(arg0 string, arg1 ...resmgmt.RequestOption) (fab.ChannelCfg, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryConfigFromOrderer", varargs...)
+	ret0, _ := ret[0].(fab.ChannelCfg)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryConfigFromOrderer indicates an expected call of QueryConfigFromOrderer
+func (mr *MockResMgmtClientMockRecorder) QueryConfigFromOrderer(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConfigFromOrderer", reflect.TypeOf((*MockResMgmtClient)(nil).QueryConfigFromOrderer), varargs...)
+}
+
+// QueryDecodedConfigFromOrderer mocks base method
+func (m *MockResMgmtClient) QueryDecodedConfigFromOrderer(arg0 string, arg1 ...resmgmt.RequestOption) (*configtx.Config, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryDecodedConfigFromOrderer", varargs...)
+	ret0, _ := ret[0].(*configtx.Config)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryDecodedConfigFromOrderer indicates an expected call of QueryDecodedConfigFromOrderer
+func (mr *MockResMgmtClientMockRecorder) QueryDecodedConfigFromOrderer(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryDecodedConfigFromOrderer", reflect.TypeOf((*MockResMgmtClient)(nil).QueryDecodedConfigFromOrderer), varargs...)
+}
+
+// QueryGenesisBlockFromOrderer mocks base method
+func (m *MockResMgmtClient) QueryGenesisBlockFromOrderer(arg0 string, arg1 ...resmgmt.RequestOption) (*common.Block, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryGenesisBlockFromOrderer", varargs...)
+	ret0, _ := ret[0].(*common.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryGenesisBlockFromOrderer indicates an expected call of QueryGenesisBlockFromOrderer
+func (mr *MockResMgmtClientMockRecorder) QueryGenesisBlockFromOrderer(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryGenesisBlockFromOrderer", reflect.TypeOf((*MockResMgmtClient)(nil).QueryGenesisBlockFromOrderer), varargs...)
+}
+
+// QueryInstalledChaincodes mocks base method
+func (m *MockResMgmtClient) QueryInstalledChaincodes(arg0 ...resmgmt.RequestOption) (*peer.ChaincodeQueryResponse, error) {
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryInstalledChaincodes", varargs...)
+	ret0, _ := ret[0].(*peer.ChaincodeQueryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryInstalledChaincodes indicates an expected call of QueryInstalledChaincodes
+func (mr *MockResMgmtClientMockRecorder) QueryInstalledChaincodes(arg0 ...interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryInstalledChaincodes", reflect.TypeOf((*MockResMgmtClient)(nil).QueryInstalledChaincodes), arg0...)
+}
+
+// QueryInstantiatedChaincodes mocks base method
+func (m *MockResMgmtClient) QueryInstantiatedChaincodes(arg0 string, arg1 ...resmgmt.RequestOption) (*peer.ChaincodeQueryResponse, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryInstantiatedChaincodes", varargs...)
+	ret0, _ := ret[0].(*peer.ChaincodeQueryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryInstantiatedChaincodes indicates an expected call of QueryInstantiatedChaincodes
+func (mr *MockResMgmtClientMockRecorder) QueryInstantiatedChaincodes(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryInstantiatedChaincodes", reflect.TypeOf((*MockResMgmtClient)(nil).QueryInstantiatedChaincodes), varargs...)
+}
+
+// QueryLogSpec mocks base method
+func (m *MockResMgmtClient) QueryLogSpec(arg0 ...resmgmt.RequestOption) ([]resmgmt.LogSpecResponse, error) {
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryLogSpec", varargs...)
+	ret0, _ := ret[0].([]resmgmt.LogSpecResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryLogSpec indicates an expected call of QueryLogSpec
+func (mr *MockResMgmtClientMockRecorder) QueryLogSpec(arg0 ...interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryLogSpec", reflect.TypeOf((*MockResMgmtClient)(nil).QueryLogSpec), arg0...)
+}
+
+// QueryNewestBlockFromOrderer mocks base method
+func (m *MockResMgmtClient) QueryNewestBlockFromOrderer(arg0 string, arg1 ...resmgmt.RequestOption) (*common.Block, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryNewestBlockFromOrderer", varargs...)
+	ret0, _ := ret[0].(*common.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryNewestBlockFromOrderer indicates an expected call of QueryNewestBlockFromOrderer
+func (mr *MockResMgmtClientMockRecorder) QueryNewestBlockFromOrderer(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryNewestBlockFromOrderer", reflect.TypeOf((*MockResMgmtClient)(nil).QueryNewestBlockFromOrderer), varargs...)
+}
+
+// QueryOrdererLogSpec mocks base method
+func (m *MockResMgmtClient) QueryOrdererLogSpec(arg0 ...resmgmt.RequestOption) ([]resmgmt.LogSpecResponse, error) {
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryOrdererLogSpec", varargs...)
+	ret0, _ := ret[0].([]resmgmt.LogSpecResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryOrdererLogSpec indicates an expected call of QueryOrdererLogSpec
+func (mr *MockResMgmtClientMockRecorder) QueryOrdererLogSpec(arg0 ...interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryOrdererLogSpec", reflect.TypeOf((*MockResMgmtClient)(nil).QueryOrdererLogSpec), arg0...)
+}
+
+// QueryPendingSnapshotRequests mocks base method
+func (m *MockResMgmtClient) QueryPendingSnapshotRequests(arg0 string, arg1 ...resmgmt.RequestOption) ([]uint64, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryPendingSnapshotRequests", varargs...)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryPendingSnapshotRequests indicates an expected call of QueryPendingSnapshotRequests
+func (mr *MockResMgmtClientMockRecorder) QueryPendingSnapshotRequests(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPendingSnapshotRequests", reflect.TypeOf((*MockResMgmtClient)(nil).QueryPendingSnapshotRequests), varargs...)
+}
+
+// RemoveConsenter mocks base method
+func (m *MockResMgmtClient) RemoveConsenter(arg0 resmgmt.ConsenterRequest, arg1 ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RemoveConsenter", varargs...)
+	ret0, _ := ret[0].(resmgmt.SaveChannelResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveConsenter indicates an expected call of RemoveConsenter
+func (mr *MockResMgmtClientMockRecorder) RemoveConsenter(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveConsenter", reflect.TypeOf((*MockResMgmtClient)(nil).RemoveConsenter), varargs...)
+}
+
+// SaveChannel mocks base method
+func (m *MockResMgmtClient) SaveChannel(arg0 resmgmt.SaveChannelRequest, arg1 ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SaveChannel", varargs...)
+	ret0, _ := ret[0].(resmgmt.SaveChannelResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveChannel indicates an expected call of SaveChannel
+func (mr *MockResMgmtClientMockRecorder) SaveChannel(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveChannel", reflect.TypeOf((*MockResMgmtClient)(nil).SaveChannel), varargs...)
+}
+
+// SetLogSpec mocks base method
+func (m *MockResMgmtClient) SetLogSpec(arg0 string, arg1 ...resmgmt.RequestOption) error {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetLogSpec", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLogSpec indicates an expected call of SetLogSpec
+func (mr *MockResMgmtClientMockRecorder) SetLogSpec(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLogSpec", reflect.TypeOf((*MockResMgmtClient)(nil).SetLogSpec), varargs...)
+}
+
+// SetOrdererLogSpec mocks base method
+func (m *MockResMgmtClient) SetOrdererLogSpec(arg0 string, arg1 ...resmgmt.RequestOption) error {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetOrdererLogSpec", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOrdererLogSpec indicates an expected call of SetOrdererLogSpec
+func (mr *MockResMgmtClientMockRecorder) SetOrdererLogSpec(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrdererLogSpec", reflect.TypeOf((*MockResMgmtClient)(nil).SetOrdererLogSpec), varargs...)
+}
+
+// SubmitSnapshotRequest mocks base method
+func (m *MockResMgmtClient) SubmitSnapshotRequest(arg0 string, arg1 uint64, arg2 ...resmgmt.RequestOption) error {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SubmitSnapshotRequest", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubmitSnapshotRequest indicates an expected call of SubmitSnapshotRequest
+func (mr *MockResMgmtClientMockRecorder) SubmitSnapshotRequest(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitSnapshotRequest", reflect.TypeOf((*MockResMgmtClient)(nil).SubmitSnapshotRequest), varargs...)
+}
+
+// UpdateAnchorPeers mocks base method
+func (m *MockResMgmtClient) UpdateAnchorPeers(arg0, arg1 string, arg2 []configtx.AnchorPeer, arg3 ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error) {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateAnchorPeers", varargs...)
+	ret0, _ := ret[0].(resmgmt.SaveChannelResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAnchorPeers indicates an expected call of UpdateAnchorPeers
+func (mr *MockResMgmtClientMockRecorder) UpdateAnchorPeers(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAnchorPeers", reflect.TypeOf((*MockResMgmtClient)(nil).UpdateAnchorPeers), varargs...)
+}
+
+// UpdateChannelConfig mocks base method
+func (m *MockResMgmtClient) UpdateChannelConfig(arg0 resmgmt.UpdateChannelConfigRequest, arg1 ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateChannelConfig", varargs...)
+	ret0, _ := ret[0].(resmgmt.SaveChannelResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateChannelConfig indicates an expected call of UpdateChannelConfig
+func (mr *MockResMgmtClientMockRecorder) UpdateChannelConfig(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChannelConfig", reflect.TypeOf((*MockResMgmtClient)(nil).UpdateChannelConfig), varargs...)
+}
+
+// UpdateRevocationList mocks base method
+func (m *MockResMgmtClient) UpdateRevocationList(arg0 resmgmt.RevocationListRequest, arg1 ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateRevocationList", varargs...)
+	ret0, _ := ret[0].(resmgmt.SaveChannelResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRevocationList indicates an expected call of UpdateRevocationList
+func (mr *MockResMgmtClientMockRecorder) UpdateRevocationList(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRevocationList", reflect.TypeOf((*MockResMgmtClient)(nil).UpdateRevocationList), varargs...)
+}
+
+// UpgradeCC mocks base method
+func (m *MockResMgmtClient) UpgradeCC(arg0 string, arg1 resmgmt.UpgradeCCRequest, arg2 ...resmgmt.RequestOption) (resmgmt.UpgradeCCResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpgradeCC", varargs...)
+	ret0, _ := ret[0].(resmgmt.UpgradeCCResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpgradeCC indicates an expected call of UpgradeCC
+func (mr *MockResMgmtClientMockRecorder) UpgradeCC(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeCC", reflect.TypeOf((*MockResMgmtClient)(nil).UpgradeCC), varargs...)
+}
+
+// WaitForPeerCatchUp mocks base method
+func (m *MockResMgmtClient) WaitForPeerCatchUp(arg0 resmgmt.PeerCatchUpRequest, arg1 ...resmgmt.RequestOption) ([]resmgmt.PeerCatchUpStatus, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WaitForPeerCatchUp", varargs...)
+	ret0, _ := ret[0].([]resmgmt.PeerCatchUpStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForPeerCatchUp indicates an expected call of WaitForPeerCatchUp
+func (mr *MockResMgmtClientMockRecorder) WaitForPeerCatchUp(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForPeerCatchUp", reflect.TypeOf((*MockResMgmtClient)(nil).WaitForPeerCatchUp), varargs...)
+}
+
+// MockLedgerClient is a mock of LedgerClient interface
+type MockLedgerClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerClientMockRecorder
+}
+
+// MockLedgerClientMockRecorder is the mock recorder for MockLedgerClient
+type MockLedgerClientMockRecorder struct {
+	mock *MockLedgerClient
+}
+
+// NewMockLedgerClient creates a new mock instance
+func NewMockLedgerClient(ctrl *gomock.Controller) *MockLedgerClient {
+	mock := &MockLedgerClient{ctrl: ctrl}
+	mock.recorder = &MockLedgerClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLedgerClient) EXPECT() *MockLedgerClientMockRecorder {
+	return m.recorder
+}
+
+// QueryBlock mocks base method
+func (m *MockLedgerClient) QueryBlock(arg0 uint64, arg1 ...ledger.RequestOption) (*common.Block, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryBlock", varargs...)
+	ret0, _ := ret[0].(*common.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryBlock indicates an expected call of QueryBlock
+func (mr *MockLedgerClientMockRecorder) QueryBlock(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBlock", reflect.TypeOf((*MockLedgerClient)(nil).QueryBlock), varargs...)
+}
+
+// QueryBlockByHash mocks base method
+func (m *MockLedgerClient) QueryBlockByHash(arg0 []byte, arg1 ...ledger.RequestOption) (*common.Block, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryBlockByHash", varargs...)
+	ret0, _ := ret[0].(*common.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryBlockByHash indicates an expected call of QueryBlockByHash
+func (mr *MockLedgerClientMockRecorder) QueryBlockByHash(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBlockByHash", reflect.TypeOf((*MockLedgerClient)(nil).QueryBlockByHash), varargs...)
+}
+
+// QueryBlockByTxID mocks base method
+func (m *MockLedgerClient) QueryBlockByTxID(arg0 fab.TransactionID, arg1 ...ledger.RequestOption) (*common.Block, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryBlockByTxID", varargs...)
+	ret0, _ := ret[0].(*common.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryBlockByTxID indicates an expected call of QueryBlockByTxID
+func (mr *MockLedgerClientMockRecorder) QueryBlockByTxID(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBlockByTxID", reflect.TypeOf((*MockLedgerClient)(nil).QueryBlockByTxID), varargs...)
+}
+
+// QueryConfig mocks base method
+func (m *MockLedgerClient) QueryConfig(arg0 ...ledger.RequestOption) (fab.ChannelCfg, error) {
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryConfig", varargs...)
+	ret0, _ := ret[0].(fab.ChannelCfg)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryConfig indicates an expected call of QueryConfig
+func (mr *MockLedgerClientMockRecorder) QueryConfig(arg0 ...interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConfig", reflect.TypeOf((*MockLedgerClient)(nil).QueryConfig), arg0...)
+}
+
+// QueryInfo mocks base method
+func (m *MockLedgerClient) QueryInfo(arg0 ...ledger.RequestOption) (*fab.BlockchainInfoResponse, error) {
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryInfo", varargs...)
+	ret0, _ := ret[0].(*fab.BlockchainInfoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryInfo indicates an expected call of QueryInfo
+func (mr *MockLedgerClientMockRecorder) QueryInfo(arg0 ...interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryInfo", reflect.TypeOf((*MockLedgerClient)(nil).QueryInfo), arg0...)
+}
+
+// QueryMissingPrivateData mocks base method
+func (m *MockLedgerClient) QueryMissingPrivateData(arg0 ledger.MissingPrivateDataRequest, arg1 ...ledger.RequestOption) ([]ledger.MissingPrivateData, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryMissingPrivateData", varargs...)
+	ret0, _ := ret[0].([]ledger.MissingPrivateData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryMissingPrivateData indicates an expected call of QueryMissingPrivateData
+func (mr *MockLedgerClientMockRecorder) QueryMissingPrivateData(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryMissingPrivateData", reflect.TypeOf((*MockLedgerClient)(nil).QueryMissingPrivateData), varargs...)
+}
+
+// QueryTransaction mocks base method
+func (m *MockLedgerClient) QueryTransaction(arg0 fab.TransactionID, arg1 ...ledger.RequestOption) (*peer.ProcessedTransaction, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryTransaction", varargs...)
+	ret0, _ := ret[0].(*peer.ProcessedTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryTransaction indicates an expected call of QueryTransaction
+func (mr *MockLedgerClientMockRecorder) QueryTransaction(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryTransaction", reflect.TypeOf((*MockLedgerClient)(nil).QueryTransaction), varargs...)
+}
+
+// MockEventClient is a mock of EventClient interface
+type MockEventClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventClientMockRecorder
+}
+
+// MockEventClientMockRecorder is the mock recorder for MockEventClient
+type MockEventClientMockRecorder struct {
+	mock *MockEventClient
+}
+
+// NewMockEventClient creates a new mock instance
+func NewMockEventClient(ctrl *gomock.Controller) *MockEventClient {
+	mock := &MockEventClient{ctrl: ctrl}
+	mock.recorder = &MockEventClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockEventClient) EXPECT() *MockEventClientMockRecorder {
+	return m.recorder
+}
+
+// RegisterBlockEvent mocks base method
+func (m *MockEventClient) RegisterBlockEvent(arg0 ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RegisterBlockEvent", varargs...)
+	ret0, _ := ret[0].(fab.Registration)
+	ret1, _ := ret[1].(<-chan *fab.BlockEvent)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegisterBlockEvent indicates an expected call of RegisterBlockEvent
+func (mr *MockEventClientMockRecorder) RegisterBlockEvent(arg0 ...interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterBlockEvent", reflect.TypeOf((*MockEventClient)(nil).RegisterBlockEvent), arg0...)
+}
+
+// RegisterChaincodeEvent mocks base method
+func (m *MockEventClient) RegisterChaincodeEvent(arg0, arg1 string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	ret := m.ctrl.Call(m, "RegisterChaincodeEvent", arg0, arg1)
+	ret0, _ := ret[0].(fab.Registration)
+	ret1, _ := ret[1].(<-chan *fab.CCEvent)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegisterChaincodeEvent indicates an expected call of RegisterChaincodeEvent
+func (mr *MockEventClientMockRecorder) RegisterChaincodeEvent(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterChaincodeEvent", reflect.TypeOf((*MockEventClient)(nil).RegisterChaincodeEvent), arg0, arg1)
+}
+
+// RegisterDecodedChaincodeEvent mocks base method
+func (m *MockEventClient) RegisterDecodedChaincodeEvent(arg0, arg1 string) (fab.Registration, <-chan *event.DecodedCCEvent, error) {
+	ret := m.ctrl.Call(m, "RegisterDecodedChaincodeEvent", arg0, arg1)
+	ret0, _ := ret[0].(fab.Registration)
+	ret1, _ := ret[1].(<-chan *event.DecodedCCEvent)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegisterDecodedChaincodeEvent indicates an expected call of RegisterDecodedChaincodeEvent
+func (mr *MockEventClientMockRecorder) RegisterDecodedChaincodeEvent(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterDecodedChaincodeEvent", reflect.TypeOf((*MockEventClient)(nil).RegisterDecodedChaincodeEvent), arg0, arg1)
+}
+
+// RegisterFilteredBlockEvent mocks base method
+func (m *MockEventClient) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	ret := m.ctrl.Call(m, "RegisterFilteredBlockEvent")
+	ret0, _ := ret[0].(fab.Registration)
+	ret1, _ := ret[1].(<-chan *fab.FilteredBlockEvent)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegisterFilteredBlockEvent indicates an expected call of RegisterFilteredBlockEvent
+func (mr *MockEventClientMockRecorder) RegisterFilteredBlockEvent() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterFilteredBlockEvent", reflect.TypeOf((*MockEventClient)(nil).RegisterFilteredBlockEvent))
+}
+
+// RegisterTxStatusEvent mocks base method
+func (m *MockEventClient) RegisterTxStatusEvent(arg0 string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	ret := m.ctrl.Call(m, "RegisterTxStatusEvent", arg0)
+	ret0, _ := ret[0].(fab.Registration)
+	ret1, _ := ret[1].(<-chan *fab.TxStatusEvent)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegisterTxStatusEvent indicates an expected call of RegisterTxStatusEvent
+func (mr *MockEventClientMockRecorder) RegisterTxStatusEvent(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterTxStatusEvent", reflect.TypeOf((*MockEventClient)(nil).RegisterTxStatusEvent), arg0)
+}
+
+// StreamState mocks base method
+func (m *MockEventClient) StreamState() (*dispatcher.StreamState, error) {
+	ret := m.ctrl.Call(m, "StreamState")
+	ret0, _ := ret[0].(*dispatcher.StreamState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamState indicates an expected call of StreamState
+func (mr *MockEventClientMockRecorder) StreamState() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamState", reflect.TypeOf((*MockEventClient)(nil).StreamState))
+}
+
+// Unregister mocks base method
+func (m *MockEventClient) Unregister(arg0 fab.Registration) {
+	m.ctrl.Call(m, "Unregister", arg0)
+}
+
+// Unregister indicates an expected call of Unregister
+func (mr *MockEventClientMockRecorder) Unregister(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unregister", reflect.TypeOf((*MockEventClient)(nil).Unregister), arg0)
+}
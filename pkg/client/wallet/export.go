@@ -0,0 +1,200 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pkcs12"
+)
+
+// ExportPKCS12 returns a PKCS#12 bundle (.p12/.pfx file) containing the certificate and private key of
+// the identity, protected by the password. The bundle may be read by e.g. openssl, Java keystores and browsers.
+//  Parameters:
+//  id is the identity
+//  password protects the private key and the integrity of the bundle
+//
+//  Returns:
+//  DER encoded PKCS#12 bundle
+func ExportPKCS12(id *X509Identity, password string) ([]byte, error) {
+	cert, err := id.certificate()
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := id.pkcs8Key()
+	if err != nil {
+		return nil, err
+	}
+	return encodePKCS12(cert.Raw, keyDER, password)
+}
+
+// ImportPKCS12 returns the identity contained in a PKCS#12 bundle with one certificate and its private key.
+// The bundle doesn't contain the MSP ID, so it has to be provided.
+//  Parameters:
+//  mspID is the MSP ID of the identity
+//  data is the DER encoded PKCS#12 bundle
+//  password of the bundle
+//
+//  Returns:
+//  identity
+func ImportPKCS12(mspID string, data []byte, password string) (*X509Identity, error) {
+	key, cert, err := pkcs12.Decode(data, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode PKCS#12 bundle")
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal private key")
+	}
+
+	id := NewX509Identity(mspID,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+	if err := id.verifyKeyPair(); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// ExportJSON returns the identity in the JSON format of the wallets (see X509Identity). If a passphrase is
+// given, the private key is stored as an encrypted PKCS#8 key ("ENCRYPTED PRIVATE KEY" PEM block), which
+// keeps the key protected at rest; such an identity has to be imported with ImportJSON before it can be used.
+//  Parameters:
+//  id is the identity
+//  passphrase encrypts the private key (optional)
+//
+//  Returns:
+//  JSON encoded identity
+func ExportJSON(id *X509Identity, passphrase string) ([]byte, error) {
+	exported := *id
+	if passphrase != "" {
+		keyDER, err := id.pkcs8Key()
+		if err != nil {
+			return nil, err
+		}
+		encrypted, err := encryptPKCS8(keyDER, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		exported.Credentials.PrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: encryptedPrivateKeyType, Bytes: encrypted}))
+	}
+
+	data, err := json.Marshal(&exported)
+	return data, errors.Wrap(err, "failed to marshal identity")
+}
+
+// ImportJSON returns the identity from its JSON format. An encrypted private key is decrypted with the passphrase.
+//  Parameters:
+//  data is the JSON encoded identity
+//  passphrase decrypts the private key (required if the key is encrypted)
+//
+//  Returns:
+//  identity
+func ImportJSON(data []byte, passphrase string) (*X509Identity, error) {
+	id := &X509Identity{}
+	if err := json.Unmarshal(data, id); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal identity")
+	}
+	if id.Type != X509Type {
+		return nil, errors.Errorf("unsupported identity type [%s]", id.Type)
+	}
+
+	block, _ := pem.Decode([]byte(id.Credentials.PrivateKey))
+	if block != nil && block.Type == encryptedPrivateKeyType {
+		if passphrase == "" {
+			return nil, errors.New("private key is encrypted: passphrase is required")
+		}
+		keyDER, err := decryptPKCS8(block.Bytes, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		id.Credentials.PrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+	}
+
+	if err := id.verifyKeyPair(); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+func (id *X509Identity) certificate() (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(id.Credentials.Certificate))
+	if block == nil {
+		return nil, errors.New("no PEM data found in certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	return cert, errors.Wrap(err, "failed to parse certificate")
+}
+
+// pkcs8Key returns the private key as DER encoded PKCS#8 (keys in SEC 1 or PKCS#1 format are converted)
+func (id *X509Identity) pkcs8Key() ([]byte, error) {
+	block, _ := pem.Decode([]byte(id.Credentials.PrivateKey))
+	if block == nil {
+		return nil, errors.New("no PEM data found in private key")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		return block.Bytes, nil
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, errors.Errorf("unsupported private key type [%s]", block.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse private key")
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	return keyDER, errors.Wrap(err, "failed to marshal private key")
+}
+
+// verifyKeyPair checks that the private key belongs to the certificate
+func (id *X509Identity) verifyKeyPair() error {
+	cert, err := id.certificate()
+	if err != nil {
+		return err
+	}
+	keyDER, err := id.pkcs8Key()
+	if err != nil {
+		return err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyDER)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse private key")
+	}
+
+	var public crypto.PublicKey
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		public = &k.PublicKey
+	case *rsa.PrivateKey:
+		public = &k.PublicKey
+	default:
+		return errors.Errorf("unsupported private key type %T", key)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal public key")
+	}
+	if !bytes.Equal(publicDER, cert.RawSubjectPublicKeyInfo) {
+		return errors.New("private key doesn't match the certificate")
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyPair(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "user1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+}
+
+func newTestIdentity(t *testing.T) *X509Identity {
+	key, cert := newTestKeyPair(t)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return NewX509Identity("Org1MSP", cert, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestPKCS12(t *testing.T) {
+	id := newTestIdentity(t)
+
+	data, err := ExportPKCS12(id, "secret")
+	require.NoError(t, err)
+
+	imported, err := ImportPKCS12("Org1MSP", data, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, id, imported)
+
+	_, err = ImportPKCS12("Org1MSP", data, "wrong")
+	assert.Error(t, err, "expecting error for wrong password")
+
+	_, err = ImportPKCS12("Org1MSP", []byte("invalid"), "secret")
+	assert.Error(t, err)
+
+	// Private key of another certificate
+	_, otherCert := newTestKeyPair(t)
+	mismatched := NewX509Identity("Org1MSP", otherCert, []byte(id.Credentials.PrivateKey))
+	data, err = ExportPKCS12(mismatched, "secret")
+	require.NoError(t, err)
+	_, err = ImportPKCS12("Org1MSP", data, "secret")
+	assert.Error(t, err, "expecting error for private key that doesn't match the certificate")
+
+	_, err = ExportPKCS12(NewX509Identity("Org1MSP", []byte(testCert), []byte(testKey)), "secret")
+	assert.Error(t, err, "expecting error for invalid certificate")
+}
+
+func TestJSON(t *testing.T) {
+	id := newTestIdentity(t)
+
+	data, err := ExportJSON(id, "")
+	require.NoError(t, err)
+	imported, err := ImportJSON(data, "")
+	require.NoError(t, err)
+	assert.Equal(t, id, imported)
+
+	data, err = ExportJSON(id, "secret")
+	require.NoError(t, err)
+
+	exported := &X509Identity{}
+	require.NoError(t, json.Unmarshal(data, exported))
+	assert.Equal(t, id.Credentials.Certificate, exported.Credentials.Certificate)
+	block, _ := pem.Decode([]byte(exported.Credentials.PrivateKey))
+	require.NotNil(t, block)
+	assert.Equal(t, encryptedPrivateKeyType, block.Type)
+
+	imported, err = ImportJSON(data, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, id, imported)
+
+	_, err = ImportJSON(data, "wrong")
+	assert.Error(t, err, "expecting error for wrong passphrase")
+
+	_, err = ImportJSON(data, "")
+	assert.Error(t, err, "expecting error for missing passphrase")
+
+	_, err = ImportJSON([]byte(`{"credentials":{},"mspId":"Org1MSP","type":"HSM-X.509","version":1}`), "")
+	assert.Error(t, err, "expecting error for unsupported identity type")
+}
+
+func TestJSONECPrivateKey(t *testing.T) {
+	key, cert := newTestKeyPair(t)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	id := NewX509Identity("Org1MSP", cert, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+
+	data, err := ExportJSON(id, "secret")
+	require.NoError(t, err)
+
+	imported, err := ImportJSON(data, "secret")
+	require.NoError(t, err)
+	block, _ := pem.Decode([]byte(imported.Credentials.PrivateKey))
+	require.NotNil(t, block)
+	assert.Equal(t, "PRIVATE KEY", block.Type, "expecting key to be converted to PKCS#8")
+
+	decoded, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, key.D, decoded.(*ecdsa.PrivateKey).D)
+}
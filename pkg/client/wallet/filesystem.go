@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// idExtension is the extension of identity files used by the wallets of all SDKs
+const idExtension = ".id"
+
+// FileSystemStore stores each identity in a <label>.id file in a directory
+type FileSystemStore struct {
+	path string
+}
+
+// NewFileSystemStore returns a store in the given directory, which is created if it doesn't exist
+func NewFileSystemStore(path string) (*FileSystemStore, error) {
+	if path == "" {
+		return nil, errors.New("wallet path is required")
+	}
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create wallet directory")
+	}
+	return &FileSystemStore{path: path}, nil
+}
+
+// Put writes the identity file
+func (s *FileSystemStore) Put(label string, content []byte) error {
+	file, err := s.file(label)
+	if err != nil {
+		return err
+	}
+
+	// The content is written to a temporary file first so that readers never see a partial identity
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write identity [%s]", label)
+	}
+	return errors.Wrapf(os.Rename(tmp, file), "failed to write identity [%s]", label)
+}
+
+// Get reads the identity file
+func (s *FileSystemStore) Get(label string) ([]byte, error) {
+	file, err := s.file(label)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return content, errors.Wrapf(err, "failed to read identity [%s]", label)
+}
+
+// List returns the labels of the identity files
+func (s *FileSystemStore) List() ([]string, error) {
+	files, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read wallet directory")
+	}
+
+	var labels []string
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), idExtension) {
+			labels = append(labels, strings.TrimSuffix(f.Name(), idExtension))
+		}
+	}
+	return labels, nil
+}
+
+// Remove deletes the identity file
+func (s *FileSystemStore) Remove(label string) error {
+	file, err := s.file(label)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove identity [%s]", label)
+	}
+	return nil
+}
+
+func (s *FileSystemStore) file(label string) (string, error) {
+	if label == "" || strings.ContainsAny(label, `/\`) || label == "." || label == ".." {
+		return "", errors.Errorf("invalid label [%s]", label)
+	}
+	return filepath.Join(s.path, label+idExtension), nil
+}
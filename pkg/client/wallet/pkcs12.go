@@ -0,0 +1,283 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" // nolint: gas
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// The PKCS#12 (RFC 7292) encoder writes the structure produced by 'openssl pkcs12 -export' with
+// the default legacy algorithms, which is understood by all PKCS#12 readers: the certificate is
+// stored unencrypted, the private key in a PKCS#8 shrouded key bag encrypted with
+// pbeWithSHAAnd3-KeyTripleDES-CBC and the content is protected by an HMAC-SHA1 MAC.
+
+var (
+	oidDataContentType               = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidPBEWithSHAAnd3KeyTripleDESCBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 3}
+	oidPKCS8ShroudedKeyBag           = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag                       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidCertTypeX509Certificate       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+	oidLocalKeyID                    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+	oidSHA1                          = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+)
+
+const (
+	pkcs12Iterations = 2048
+	pkcs12SaltLength = 8
+)
+
+type pfxPdu struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit"`
+}
+
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int
+}
+
+type digestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue     `asn1:"tag:0,explicit"`
+	Attributes []pkcs12Attribute `asn1:"set"`
+}
+
+type pkcs12Attribute struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue `asn1:"set"`
+}
+
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+type pbeParams struct {
+	Salt       []byte
+	Iterations int
+}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// encodePKCS12 returns a PKCS#12 bundle of the DER encoded certificate and PKCS#8 private key
+func encodePKCS12(certDER, keyDER []byte, password string) ([]byte, error) {
+	pwd := bmpString(password)
+
+	// The local key ID links the key to its certificate
+	localKeyID := sha1.Sum(certDER) // nolint: gas
+	attributes, err := localKeyIDAttributes(localKeyID[:])
+	if err != nil {
+		return nil, err
+	}
+
+	certBagBytes, err := asn1.Marshal(certBag{ID: oidCertTypeX509Certificate, Data: certDER})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal certificate bag")
+	}
+
+	shroudedKey, err := encryptPKCS8ShroudedKey(keyDER, pwd)
+	if err != nil {
+		return nil, err
+	}
+
+	certContent, err := dataContentInfo(safeBag{ID: oidCertBag, Value: explicitValue(certBagBytes), Attributes: attributes})
+	if err != nil {
+		return nil, err
+	}
+	keyContent, err := dataContentInfo(safeBag{ID: oidPKCS8ShroudedKeyBag, Value: explicitValue(shroudedKey), Attributes: attributes})
+	if err != nil {
+		return nil, err
+	}
+
+	authenticatedSafe, err := asn1.Marshal([]contentInfo{certContent, keyContent})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal authenticated safe")
+	}
+
+	mac, err := newMacData(authenticatedSafe, pwd)
+	if err != nil {
+		return nil, err
+	}
+
+	authSafe, err := octetString(authenticatedSafe)
+	if err != nil {
+		return nil, err
+	}
+
+	pfx, err := asn1.Marshal(pfxPdu{
+		Version:  3,
+		AuthSafe: contentInfo{ContentType: oidDataContentType, Content: explicitValue(authSafe)},
+		MacData:  mac,
+	})
+	return pfx, errors.Wrap(err, "failed to marshal PFX")
+}
+
+func encryptPKCS8ShroudedKey(keyDER, password []byte) ([]byte, error) {
+	salt := make([]byte, pkcs12SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt")
+	}
+
+	params, err := asn1.Marshal(pbeParams{Salt: salt, Iterations: pkcs12Iterations})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal PBE parameters")
+	}
+
+	block, err := des.NewTripleDESCipher(pkcs12KDF(salt, password, pkcs12Iterations, 1, 24))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	encrypted := pad(keyDER, block.BlockSize())
+	cipher.NewCBCEncrypter(block, pkcs12KDF(salt, password, pkcs12Iterations, 2, 8)).CryptBlocks(encrypted, encrypted)
+
+	info, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidPBEWithSHAAnd3KeyTripleDESCBC, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData: encrypted,
+	})
+	return info, errors.Wrap(err, "failed to marshal encrypted private key")
+}
+
+func newMacData(content, password []byte) (macData, error) {
+	salt := make([]byte, pkcs12SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return macData{}, errors.Wrap(err, "failed to generate salt")
+	}
+
+	mac := hmac.New(sha1.New, pkcs12KDF(salt, password, pkcs12Iterations, 3, 20))
+	mac.Write(content) // nolint: errcheck
+
+	return macData{
+		Mac:        digestInfo{Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA1, Parameters: asn1.NullRawValue}, Digest: mac.Sum(nil)},
+		MacSalt:    salt,
+		Iterations: pkcs12Iterations,
+	}, nil
+}
+
+func localKeyIDAttributes(id []byte) ([]pkcs12Attribute, error) {
+	value, err := asn1.Marshal(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal local key ID")
+	}
+	return []pkcs12Attribute{{ID: oidLocalKeyID, Value: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: value}}}, nil
+}
+
+func dataContentInfo(bag safeBag) (contentInfo, error) {
+	safeContents, err := asn1.Marshal([]safeBag{bag})
+	if err != nil {
+		return contentInfo{}, errors.Wrap(err, "failed to marshal safe contents")
+	}
+	data, err := octetString(safeContents)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	return contentInfo{ContentType: oidDataContentType, Content: explicitValue(data)}, nil
+}
+
+func octetString(data []byte) ([]byte, error) {
+	bytes, err := asn1.Marshal(data)
+	return bytes, errors.Wrap(err, "failed to marshal octet string")
+}
+
+func explicitValue(der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der}
+}
+
+// bmpString returns the password as a null terminated UTF-16 big endian string (RFC 7292, appendix B.1)
+func bmpString(s string) []byte {
+	var bytes []byte
+	for _, r := range utf16.Encode([]rune(s)) {
+		bytes = append(bytes, byte(r>>8), byte(r))
+	}
+	return append(bytes, 0, 0)
+}
+
+// pkcs12KDF derives key material from the password with the SHA-1 based PKCS#12 key derivation
+// function (RFC 7292, appendix B.2). ID 1 is used for keys, 2 for IVs and 3 for MAC keys.
+func pkcs12KDF(salt, password []byte, iterations int, id byte, size int) []byte {
+	const u, v = sha1.Size, 64
+
+	d := make([]byte, v)
+	for i := range d {
+		d[i] = id
+	}
+	i := append(fill(salt, v), fill(password, v)...)
+
+	var result []byte
+	for len(result) < size {
+		h := sha1.New() // nolint: gas
+		h.Write(d)      // nolint: errcheck
+		h.Write(i)      // nolint: errcheck
+		a := h.Sum(nil)
+		for j := 1; j < iterations; j++ {
+			sum := sha1.Sum(a) // nolint: gas
+			a = sum[:]
+		}
+		result = append(result, a[:u]...)
+
+		// I_j = (I_j + B + 1) mod 2^(v*8) for each v-byte block of I
+		b := new(big.Int).SetBytes(fill(a, v))
+		b.Add(b, big.NewInt(1))
+		mod := new(big.Int).Lsh(big.NewInt(1), v*8)
+		for j := 0; j < len(i); j += v {
+			block := new(big.Int).SetBytes(i[j : j+v])
+			block.Add(block, b).Mod(block, mod)
+			blockBytes := block.Bytes()
+			copy(i[j:j+v], make([]byte, v-len(blockBytes)))
+			copy(i[j+v-len(blockBytes):j+v], blockBytes)
+		}
+	}
+	return result[:size]
+}
+
+// fill repeats the data to a multiple of v bytes
+func fill(data []byte, v int) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	n := v * ((len(data) + v - 1) / v)
+	filled := make([]byte, n)
+	for i := range filled {
+		filled[i] = data[i%len(data)]
+	}
+	return filled
+}
+
+// pad applies PKCS#7 padding
+func pad(data []byte, blockSize int) []byte {
+	n := blockSize - len(data)%blockSize
+	padded := make([]byte, len(data), len(data)+n)
+	copy(padded, data)
+	for i := 0; i < n; i++ {
+		padded = append(padded, byte(n))
+	}
+	return padded
+}
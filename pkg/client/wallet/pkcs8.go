@@ -0,0 +1,188 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha1" // nolint: gas
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"hash"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Encrypted private keys are PKCS#8 EncryptedPrivateKeyInfo structures (PEM type "ENCRYPTED PRIVATE KEY")
+// using PBES2 with PBKDF2 and AES-CBC (RFC 8018), as written by 'openssl pkcs8 -topk8 -v2 aes-256-cbc'.
+
+const encryptedPrivateKeyType = "ENCRYPTED PRIVATE KEY"
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+const (
+	pbkdf2Iterations = 100000
+	pbkdf2SaltLength = 16
+)
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// encryptPKCS8 encrypts a DER encoded PKCS#8 private key with the passphrase
+func encryptPKCS8(keyDER []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, pbkdf2SaltLength)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt")
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return nil, errors.Wrap(err, "failed to generate IV")
+	}
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: pbkdf2Iterations,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal PBKDF2 parameters")
+	}
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal IV")
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal PBES2 parameters")
+	}
+
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, pbkdf2Iterations, 32, sha256.New))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	encrypted := pad(keyDER, block.BlockSize())
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, encrypted)
+
+	info, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData: encrypted,
+	})
+	return info, errors.Wrap(err, "failed to marshal encrypted private key")
+}
+
+// decryptPKCS8 decrypts a DER encoded PKCS#8 EncryptedPrivateKeyInfo. PBES2 with PBKDF2
+// (HMAC-SHA1 or HMAC-SHA256) and AES-128-CBC or AES-256-CBC is supported.
+func decryptPKCS8(der []byte, passphrase string) ([]byte, error) {
+	info := encryptedPrivateKeyInfo{}
+	if err := unmarshalDER(der, &info); err != nil {
+		return nil, errors.WithMessage(err, "invalid encrypted private key")
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, errors.Errorf("unsupported private key encryption algorithm [%s]", info.Algorithm.Algorithm)
+	}
+
+	params := pbes2Params{}
+	if err := unmarshalDER(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, errors.WithMessage(err, "invalid PBES2 parameters")
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, errors.Errorf("unsupported key derivation function [%s]", params.KeyDerivationFunc.Algorithm)
+	}
+
+	kdfParams := pbkdf2Params{}
+	if err := unmarshalDER(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, errors.WithMessage(err, "invalid PBKDF2 parameters")
+	}
+
+	var prf func() hash.Hash
+	switch {
+	case len(kdfParams.PRF.Algorithm) == 0 || kdfParams.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case kdfParams.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	default:
+		return nil, errors.Errorf("unsupported PBKDF2 pseudorandom function [%s]", kdfParams.PRF.Algorithm)
+	}
+
+	var keyLength int
+	switch {
+	case params.EncryptionScheme.Algorithm.Equal(oidAES128CBC):
+		keyLength = 16
+	case params.EncryptionScheme.Algorithm.Equal(oidAES256CBC):
+		keyLength = 32
+	default:
+		return nil, errors.Errorf("unsupported encryption scheme [%s]", params.EncryptionScheme.Algorithm)
+	}
+
+	var iv []byte
+	if err := unmarshalDER(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil || len(iv) != aes.BlockSize {
+		return nil, errors.New("invalid IV")
+	}
+
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), kdfParams.Salt, kdfParams.IterationCount, keyLength, prf))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	if len(info.EncryptedData) == 0 || len(info.EncryptedData)%block.BlockSize() != 0 {
+		return nil, errors.New("invalid encrypted data length")
+	}
+
+	decrypted := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, info.EncryptedData)
+	return unpad(decrypted, block.BlockSize())
+}
+
+func unmarshalDER(der []byte, v interface{}) error {
+	rest, err := asn1.Unmarshal(der, v)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal")
+	}
+	if len(rest) > 0 {
+		return errors.New("trailing data")
+	}
+	return nil
+}
+
+// unpad removes PKCS#7 padding. An invalid padding usually means that the passphrase is wrong.
+func unpad(data []byte, blockSize int) ([]byte, error) {
+	errIncorrect := errors.New("decryption failed: incorrect passphrase")
+	if len(data) == 0 {
+		return nil, errIncorrect
+	}
+	n := int(data[len(data)-1])
+	if n == 0 || n > blockSize || n > len(data) {
+		return nil, errIncorrect
+	}
+	for _, b := range data[len(data)-n:] {
+		if int(b) != n {
+			return nil, errIncorrect
+		}
+	}
+	return data[:len(data)-n], nil
+}
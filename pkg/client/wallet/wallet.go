@@ -0,0 +1,218 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package wallet stores X.509 identities (certificate and private key) by label. The file system wallet
+// uses the same on-disk format as the wallets of the Node and Java SDKs (one <label>.id JSON file per
+// identity), so a wallet directory may be shared by applications written with any of the SDKs.
+//
+//  Basic Flow:
+//  1) Create a file system or in-memory wallet
+//  2) Put identities into the wallet
+//  3) Get an identity and create a signing identity from it (see fabsdk.WithIdentity)
+package wallet
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+)
+
+const (
+	// X509Type is the type of X.509 identities
+	X509Type = "X.509"
+
+	identityVersion = 1
+)
+
+// Store persists the serialized identities of a wallet
+type Store interface {
+	Put(label string, content []byte) error
+	Get(label string) ([]byte, error)
+	List() ([]string, error)
+	Remove(label string) error
+}
+
+// ErrNotFound is returned by Store.Get if the wallet has no identity with the label
+var ErrNotFound = errors.New("identity not found")
+
+// X509Identity is an identity consisting of an X.509 certificate and its private key
+type X509Identity struct {
+	Credentials Credentials `json:"credentials"`
+	MSPID       string      `json:"mspId"`
+	Type        string      `json:"type"`
+	Version     int         `json:"version"`
+}
+
+// Credentials contains the PEM encoded certificate and private key of an identity
+type Credentials struct {
+	Certificate string `json:"certificate"`
+	PrivateKey  string `json:"privateKey"`
+}
+
+// NewX509Identity returns an identity with the given MSP ID and PEM encoded certificate and private key
+func NewX509Identity(mspID string, cert []byte, key []byte) *X509Identity {
+	return &X509Identity{
+		Credentials: Credentials{Certificate: string(cert), PrivateKey: string(key)},
+		MSPID:       mspID,
+		Type:        X509Type,
+		Version:     identityVersion,
+	}
+}
+
+// SigningIdentity creates a signing identity from the identity. The identity manager must be the one
+// of the identity's organization.
+//  Parameters:
+//  mgr is the identity manager of the identity's organization
+//
+//  Returns:
+//  signing identity
+func (id *X509Identity) SigningIdentity(mgr msp.IdentityManager) (msp.SigningIdentity, error) {
+	signingIdentity, err := mgr.CreateSigningIdentity(msp.WithCert([]byte(id.Credentials.Certificate)), msp.WithPrivateKey([]byte(id.Credentials.PrivateKey)))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create signing identity")
+	}
+	if signingIdentity.Identifier().MSPID != id.MSPID {
+		return nil, errors.Errorf("identity manager of MSP [%s] doesn't match identity of MSP [%s]", signingIdentity.Identifier().MSPID, id.MSPID)
+	}
+	return signingIdentity, nil
+}
+
+// Wallet stores identities by label
+type Wallet struct {
+	store Store
+}
+
+// New returns a wallet which persists identities in the given store
+func New(store Store) *Wallet {
+	return &Wallet{store: store}
+}
+
+// NewInMemoryWallet returns a wallet which keeps the identities in memory
+func NewInMemoryWallet() *Wallet {
+	return New(newMemoryStore())
+}
+
+// NewFileSystemWallet returns a wallet which stores identities in the given directory. The directory
+// is created if it doesn't exist.
+func NewFileSystemWallet(path string) (*Wallet, error) {
+	store, err := NewFileSystemStore(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(store), nil
+}
+
+// Put stores an identity with the given label, replacing an existing identity with the label
+//  Parameters:
+//  label of the identity
+//  id is the identity
+//
+//  Returns:
+//  error if the identity is invalid or can't be stored
+func (w *Wallet) Put(label string, id *X509Identity) error {
+	if label == "" {
+		return errors.New("label is required")
+	}
+	if id.MSPID == "" || id.Credentials.Certificate == "" || id.Credentials.PrivateKey == "" {
+		return errors.New("identity requires an MSP ID, certificate and private key")
+	}
+
+	content, err := json.Marshal(id)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal identity")
+	}
+	return w.store.Put(label, content)
+}
+
+// Get returns the identity with the given label
+//  Parameters:
+//  label of the identity
+//
+//  Returns:
+//  identity (ErrNotFound is returned if the wallet has no identity with the label)
+func (w *Wallet) Get(label string) (*X509Identity, error) {
+	content, err := w.store.Get(label)
+	if err != nil {
+		return nil, err
+	}
+
+	id := &X509Identity{}
+	if err := json.Unmarshal(content, id); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal identity [%s]", label)
+	}
+	if id.Type != X509Type {
+		return nil, errors.Errorf("unsupported type [%s] of identity [%s]", id.Type, label)
+	}
+	return id, nil
+}
+
+// Exists returns true if the wallet has an identity with the given label
+func (w *Wallet) Exists(label string) bool {
+	_, err := w.store.Get(label)
+	return err == nil
+}
+
+// List returns the labels of the identities in the wallet
+func (w *Wallet) List() ([]string, error) {
+	labels, err := w.store.List()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
+
+// Remove removes the identity with the given label. Removing an identity which doesn't exist isn't an error.
+func (w *Wallet) Remove(label string) error {
+	return w.store.Remove(label)
+}
+
+// memoryStore keeps the identities in memory
+type memoryStore struct {
+	mutex      sync.RWMutex
+	identities map[string][]byte
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{identities: make(map[string][]byte)}
+}
+
+func (s *memoryStore) Put(label string, content []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.identities[label] = content
+	return nil
+}
+
+func (s *memoryStore) Get(label string) ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	content, ok := s.identities[label]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return content, nil
+}
+
+func (s *memoryStore) List() ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var labels []string
+	for label := range s.identities {
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+func (s *memoryStore) Remove(label string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.identities, label)
+	return nil
+}
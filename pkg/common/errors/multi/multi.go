@@ -74,3 +74,8 @@ func (errs Errors) Error() string {
 	}
 	return strings.Join(errors, "\n")
 }
+
+// Unwrap returns the errors so that errors.Is and errors.As of the standard library examine each of them
+func (errs Errors) Unwrap() []error {
+	return errs
+}
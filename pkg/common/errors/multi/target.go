@@ -0,0 +1,123 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multi
+
+import (
+	"sort"
+)
+
+// TargetError is the error returned by one of the targets (e.g. peers) of an operation that is
+// sent to multiple targets. The message of the error is the message of the target's error, which
+// is returned by Cause so that the typed error (e.g. a status) may be extracted.
+type TargetError struct {
+	// Target is the URL of the target
+	Target string
+	// MSPID is the MSP ID of the organization of the target (empty if unknown)
+	MSPID string
+	// Err is the error returned by the target
+	Err error
+}
+
+// NewTargetError returns the error of the given target, or nil if err is nil
+func NewTargetError(target, mspID string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &TargetError{Target: target, MSPID: mspID, Err: err}
+}
+
+func (e *TargetError) Error() string {
+	return e.Err.Error()
+}
+
+// Cause returns the error returned by the target
+func (e *TargetError) Cause() error {
+	return e.Err
+}
+
+// Unwrap returns the error returned by the target
+func (e *TargetError) Unwrap() error {
+	return e.Err
+}
+
+// TargetErrors holds the errors of the targets of an operation keyed by target URL
+type TargetErrors map[string]*TargetError
+
+// ByTarget returns the target errors contained in err, which may be a TargetError, an Errors
+// or an error wrapping them. Errors that aren't associated with a target are ignored.
+func ByTarget(err error) TargetErrors {
+	errs := make(TargetErrors)
+	collectTargetErrors(err, errs)
+	return errs
+}
+
+// FailedOrgs returns the sorted MSP IDs of the organizations with at least one failed target
+func (errs TargetErrors) FailedOrgs() []string {
+	orgs := make(map[string]bool)
+	for _, e := range errs {
+		if e.MSPID != "" {
+			orgs[e.MSPID] = true
+		}
+	}
+	return sortedKeys(orgs)
+}
+
+// SucceededOrgs returns the sorted MSP IDs of the organizations with at least one target that didn't fail
+//  Parameters:
+//  targets maps each target URL of the operation to the MSP ID of its organization
+func (errs TargetErrors) SucceededOrgs(targets map[string]string) []string {
+	orgs := make(map[string]bool)
+	for target, mspID := range targets {
+		if _, failed := errs[target]; !failed {
+			orgs[mspID] = true
+		}
+	}
+	return sortedKeys(orgs)
+}
+
+// SucceededOnOrgs returns true if the operation that returned err succeeded on at least n organizations
+//  Parameters:
+//  err is the error returned by the operation (nil if it succeeded on all targets)
+//  targets maps each target URL of the operation to the MSP ID of its organization
+//  n is the required number of organizations
+func SucceededOnOrgs(err error, targets map[string]string, n int) bool {
+	return len(ByTarget(err).SucceededOrgs(targets)) >= n
+}
+
+type causer interface {
+	Cause() error
+}
+
+func collectTargetErrors(err error, errs TargetErrors) {
+	for err != nil {
+		switch e := err.(type) {
+		case *TargetError:
+			errs[e.Target] = e
+			return
+		case Errors:
+			for _, me := range e {
+				collectTargetErrors(me, errs)
+			}
+			return
+		}
+
+		c, ok := err.(causer)
+		if !ok {
+			return
+		}
+		err = c.Cause()
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
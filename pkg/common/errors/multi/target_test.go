@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetError(t *testing.T) {
+	assert.Nil(t, NewTargetError("peer0.org1.example.com", "Org1MSP", nil))
+
+	testErr := fmt.Errorf("test")
+	err := NewTargetError("peer0.org1.example.com", "Org1MSP", testErr)
+	assert.Equal(t, "test", err.Error())
+	assert.Equal(t, testErr, errors.Cause(err))
+
+	targetErr, ok := err.(*TargetError)
+	require.True(t, ok)
+	assert.Equal(t, testErr, targetErr.Unwrap())
+}
+
+func TestByTarget(t *testing.T) {
+	testErr := fmt.Errorf("test")
+
+	assert.Empty(t, ByTarget(nil))
+	assert.Empty(t, ByTarget(testErr))
+
+	err := errors.WithMessage(New(
+		NewTargetError("peer0.org1.example.com", "Org1MSP", testErr),
+		testErr,
+		Errors{NewTargetError("peer0.org2.example.com", "Org2MSP", testErr), NewTargetError("peer0.org3.example.com", "", testErr)},
+	), "operation failed")
+
+	errs := ByTarget(err)
+	assert.Len(t, errs, 3)
+	assert.Equal(t, "Org1MSP", errs["peer0.org1.example.com"].MSPID)
+	assert.Equal(t, testErr, errs["peer0.org2.example.com"].Err)
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP"}, errs.FailedOrgs())
+
+	targets := map[string]string{
+		"peer0.org1.example.com": "Org1MSP",
+		"peer1.org1.example.com": "Org1MSP",
+		"peer0.org2.example.com": "Org2MSP",
+		"peer0.org4.example.com": "Org4MSP",
+	}
+	assert.Equal(t, []string{"Org1MSP", "Org4MSP"}, errs.SucceededOrgs(targets))
+	assert.True(t, SucceededOnOrgs(err, targets, 2))
+	assert.False(t, SucceededOnOrgs(err, targets, 3))
+	assert.True(t, SucceededOnOrgs(nil, targets, 3), "expecting all organizations to succeed without error")
+}
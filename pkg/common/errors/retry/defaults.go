@@ -66,34 +66,8 @@ var DefaultResMgmtOpts = Opts{
 }
 
 // DefaultRetryableCodes these are the error codes, grouped by source of error,
-// that are considered to be transient error conditions by default
-var DefaultRetryableCodes = map[status.Group][]status.Code{
-	status.EndorserClientStatus: {
-		status.EndorsementMismatch,
-		status.PrematureChaincodeExecution,
-		status.ChaincodeAlreadyLaunching,
-		status.ChaincodeNameNotFound,
-	},
-	status.EndorserServerStatus: {
-		status.Code(common.Status_SERVICE_UNAVAILABLE),
-		status.Code(common.Status_INTERNAL_SERVER_ERROR),
-	},
-	status.OrdererServerStatus: {
-		status.Code(common.Status_SERVICE_UNAVAILABLE),
-		status.Code(common.Status_INTERNAL_SERVER_ERROR),
-	},
-	status.EventServerStatus: {
-		status.Code(pb.TxValidationCode_DUPLICATE_TXID),
-		status.Code(pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE),
-		status.Code(pb.TxValidationCode_MVCC_READ_CONFLICT),
-		status.Code(pb.TxValidationCode_PHANTOM_READ_CONFLICT),
-	},
-	// TODO: gRPC introduced retries in v1.8.0. This can be replaced with the
-	// gRPC fail fast option, once available
-	status.GRPCTransportStatus: {
-		status.Code(grpcCodes.Unavailable),
-	},
-}
+// that are considered to be transient error conditions by default (see status.DefaultRetryableCodes)
+var DefaultRetryableCodes = status.DefaultRetryableCodes
 
 // ResMgmtDefaultRetryableCodes are the suggested codes that should be treated as
 // transient by fabric-sdk-go/pkg/client/resmgmt.Client
@@ -0,0 +1,39 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package retry
+
+import (
+	"fmt"
+	"time"
+)
+
+// DeadlineWouldExceedError is returned when a retry is warranted but the backoff
+// before the retry would exceed the deadline of the caller's context
+type DeadlineWouldExceedError struct {
+	// Attempts the number of attempts made
+	Attempts int
+	// Backoff the backoff before the next attempt
+	Backoff time.Duration
+	// Remaining the time remaining before the deadline
+	Remaining time.Duration
+	// Err the error of the last attempt
+	Err error
+}
+
+func (e *DeadlineWouldExceedError) Error() string {
+	return fmt.Sprintf("retry backoff of %s would exceed the deadline (remaining %s) after %d attempt(s): %s", e.Backoff, e.Remaining, e.Attempts, e.Err)
+}
+
+// Cause returns the error of the last attempt
+func (e *DeadlineWouldExceedError) Cause() error {
+	return e.Err
+}
+
+// Unwrap returns the error of the last attempt
+func (e *DeadlineWouldExceedError) Unwrap() error {
+	return e.Err
+}
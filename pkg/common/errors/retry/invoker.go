@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package retry
 
 import (
+	reqContext "context"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 )
@@ -25,6 +27,7 @@ type BeforeRetryHandler func(error)
 type RetryableInvoker struct {
 	handler     Handler
 	beforeRetry BeforeRetryHandler
+	ctx         reqContext.Context
 }
 
 // InvokerOpt is an invoker option
@@ -37,6 +40,15 @@ func WithBeforeRetry(beforeRetry BeforeRetryHandler) InvokerOpt {
 	}
 }
 
+// WithContext specifies the caller's context. If the handler is a ContextHandler then a retry is
+// not attempted if its backoff would exceed the deadline of the context; instead, the invocation
+// returns a DeadlineWouldExceedError.
+func WithContext(ctx reqContext.Context) InvokerOpt {
+	return func(invoker *RetryableInvoker) {
+		invoker.ctx = ctx
+	}
+}
+
 // NewInvoker creates a new RetryableInvoker
 func NewInvoker(handler Handler, opts ...InvokerOpt) *RetryableInvoker {
 	invoker := &RetryableInvoker{
@@ -69,7 +81,12 @@ func (ri *RetryableInvoker) Invoke(invocation Invocation) (interface{}, error) {
 		}
 
 		logger.Debugf("Failed with err [%s] on attempt #%d. Checking if retry is warranted...", err, attemptNum)
-		if !ri.resolveRetry(err) {
+		retry, retryErr := ri.resolveRetry(err)
+		if retryErr != nil {
+			logger.Debugf("... retry for err [%s] is NOT possible after %d attempt(s): %s", err, attemptNum, retryErr)
+			return nil, retryErr
+		}
+		if !retry {
 			if lastErr != nil && lastErr.Error() != err.Error() {
 				logger.Debugf("... retry for err [%s] is NOT warranted after %d attempt(s). Previous error [%s]", err, attemptNum, lastErr)
 			} else {
@@ -82,19 +99,30 @@ func (ri *RetryableInvoker) Invoke(invocation Invocation) (interface{}, error) {
 	}
 }
 
-func (ri *RetryableInvoker) resolveRetry(err error) bool {
+func (ri *RetryableInvoker) resolveRetry(err error) (bool, error) {
 	errs, ok := err.(multi.Errors)
 	if !ok {
 		errs = append(errs, err)
 	}
 	for _, e := range errs {
-		if ri.handler.Required(e) {
+		required, retryErr := ri.required(e)
+		if retryErr != nil {
+			return false, retryErr
+		}
+		if required {
 			logger.Debugf("Retrying on error %s", e)
 			if ri.beforeRetry != nil {
 				ri.beforeRetry(err)
 			}
-			return true
+			return true, nil
 		}
 	}
-	return false
+	return false, nil
+}
+
+func (ri *RetryableInvoker) required(err error) (bool, error) {
+	if ctxHandler, ok := ri.handler.(ContextHandler); ok && ri.ctx != nil {
+		return ctxHandler.RequiredWithContext(ri.ctx, err)
+	}
+	return ri.handler.Required(err), nil
 }
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package retry
 
 import (
+	reqContext "context"
 	"testing"
 	"time"
 
@@ -102,3 +103,50 @@ func TestInvokeWithBeforeRetry(t *testing.T) {
 	assert.Equal(t, 2, attempt)
 	assert.Equal(t, 1, beforeRetryHandlerCalled)
 }
+
+func TestInvokeDeadlineWouldExceed(t *testing.T) {
+	r := New(Opts{
+		Attempts:       5,
+		BackoffFactor:  10,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+	})
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	attempt := 0
+	retryErr := status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "", nil)
+	start := time.Now()
+	_, err := NewInvoker(r, WithContext(ctx)).Invoke(
+		func() (interface{}, error) {
+			attempt++
+			return nil, retryErr
+		},
+	)
+
+	// Backoffs are 10ms, 100ms and 1s so the third retry would exceed the deadline
+	assert.Equal(t, 3, attempt)
+	assert.True(t, time.Since(start) < 500*time.Millisecond, "expecting invocation to return before the deadline")
+
+	deadlineErr, ok := err.(*DeadlineWouldExceedError)
+	if !ok {
+		t.Fatalf("expecting DeadlineWouldExceedError but got %v", err)
+	}
+	assert.Equal(t, 3, deadlineErr.Attempts)
+	assert.Equal(t, time.Second, deadlineErr.Backoff)
+	assert.True(t, deadlineErr.Remaining < 500*time.Millisecond)
+	assert.True(t, status.Is(err, retryErr))
+
+	// Without a context the deadline is not considered
+	r = New(Opts{Attempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	attempt = 0
+	_, err = NewInvoker(r).Invoke(
+		func() (interface{}, error) {
+			attempt++
+			return nil, retryErr
+		},
+	)
+	assert.Equal(t, 3, attempt)
+	assert.Equal(t, retryErr, err)
+}
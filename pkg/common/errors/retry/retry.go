@@ -14,9 +14,11 @@ SPDX-License-Identifier: Apache-2.0
 package retry
 
 import (
+	reqContext "context"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/pkg/errors"
 )
 
 // Opts defines the retry parameters
@@ -43,6 +45,17 @@ type Handler interface {
 	Required(err error) bool
 }
 
+// ContextHandler is a retry Handler which respects the deadline of the caller's context.
+// A retry is never scheduled if its backoff would exceed the time remaining before the deadline.
+type ContextHandler interface {
+	Handler
+
+	// RequiredWithContext determines if retry is required for the given error. If a retry is warranted
+	// but the backoff would exceed the deadline of the given context then false is returned along with
+	// a DeadlineWouldExceedError.
+	RequiredWithContext(ctx reqContext.Context, err error) (bool, error)
+}
+
 // impl retry Handler implementation
 type impl struct {
 	opts    Opts
@@ -72,18 +85,37 @@ func WithAttempts(attempts int) Handler {
 // Required determines if retry is required for the given error
 // Note: backoffs are implemented behind this interface
 func (i *impl) Required(err error) bool {
+	required, _ := i.RequiredWithContext(reqContext.Background(), err)
+	return required
+}
+
+// RequiredWithContext determines if retry is required for the given error, taking into
+// account the deadline of the given context
+func (i *impl) RequiredWithContext(ctx reqContext.Context, err error) (bool, error) {
 	if i.retries == i.opts.Attempts {
-		return false
+		return false, nil
 	}
 
 	s, ok := status.FromError(err)
-	if ok && i.isRetryable(s.Group, s.Code) {
-		time.Sleep(i.backoffPeriod())
-		i.retries++
-		return true
+	if !ok || !i.isRetryable(s.Group, s.Code) {
+		return false, nil
 	}
 
-	return false
+	backoff := i.backoffPeriod()
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < backoff {
+			return false, &DeadlineWouldExceedError{Attempts: i.retries + 1, Backoff: backoff, Remaining: remaining, Err: err}
+		}
+	}
+
+	select {
+	case <-time.After(backoff):
+	case <-ctx.Done():
+		return false, errors.Wrapf(ctx.Err(), "retry aborted after %d attempt(s)", i.retries+1)
+	}
+
+	i.retries++
+	return true, nil
 }
 
 // backoffPeriod calculates the backoff duration based on the provided opts
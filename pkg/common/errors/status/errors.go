@@ -0,0 +1,270 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	grpcCodes "google.golang.org/grpc/codes"
+)
+
+// Errors for conditions which are commonly checked by applications. These may be used as the target
+// of Is in order to check for a condition without matching the
+// error string. A status matches one of these errors if it has the same SDK status code, regardless of
+// which client group produced it, or if its code from the server or transport maps to that SDK code
+// (for example, a FORBIDDEN status from the ordering service matches ErrAccessDenied).
+var (
+	ErrConnectionFailed    = New(ClientStatus, ConnectionFailed.ToInt32(), "connection failed", nil)
+	ErrEndorsementMismatch = New(ClientStatus, EndorsementMismatch.ToInt32(), "endorsement mismatch", nil)
+	ErrTimeout             = New(ClientStatus, Timeout.ToInt32(), "operation timed out", nil)
+	ErrNoPeersFound        = New(ClientStatus, NoPeersFound.ToInt32(), "no peers found", nil)
+	ErrChaincodeNotFound   = New(ClientStatus, ChaincodeNameNotFound.ToInt32(), "chaincode not found", nil)
+	ErrAccessDenied        = New(ClientStatus, AccessDenied.ToInt32(), "access denied", nil)
+)
+
+// DefaultRetryableCodes these are the error codes, grouped by source of error,
+// that are considered to be transient error conditions by default. Status.Retryable
+// reports whether a status is in this set.
+var DefaultRetryableCodes = map[Group][]Code{
+	EndorserClientStatus: {
+		EndorsementMismatch,
+		PrematureChaincodeExecution,
+		ChaincodeAlreadyLaunching,
+		ChaincodeNameNotFound,
+	},
+	EndorserServerStatus: {
+		Code(common.Status_SERVICE_UNAVAILABLE),
+		Code(common.Status_INTERNAL_SERVER_ERROR),
+	},
+	OrdererServerStatus: {
+		Code(common.Status_SERVICE_UNAVAILABLE),
+		Code(common.Status_INTERNAL_SERVER_ERROR),
+	},
+	EventServerStatus: {
+		Code(pb.TxValidationCode_DUPLICATE_TXID),
+		Code(pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE),
+		Code(pb.TxValidationCode_MVCC_READ_CONFLICT),
+		Code(pb.TxValidationCode_PHANTOM_READ_CONFLICT),
+	},
+	// TODO: gRPC introduced retries in v1.8.0. This can be replaced with the
+	// gRPC fail fast option, once available
+	GRPCTransportStatus: {
+		Code(grpcCodes.Unavailable),
+	},
+}
+
+// Retryable returns true if the status is considered to be a transient error condition by default
+// (see DefaultRetryableCodes)
+func (s *Status) Retryable() bool {
+	for _, code := range DefaultRetryableCodes[s.Group] {
+		if code.ToInt32() == s.Code {
+			return true
+		}
+	}
+	return false
+}
+
+// Is returns true if the target is a status with the same group and code or if both statuses
+// correspond to the same SDK status code (see ErrAccessDenied, ErrChaincodeNotFound, etc.).
+// It allows a status to be matched with Is.
+func (s *Status) Is(target error) bool {
+	t, ok := target.(*Status)
+	if !ok {
+		return false
+	}
+	if s.Group == t.Group && s.Code == t.Code {
+		return true
+	}
+	code, ok := s.sdkCode()
+	if !ok {
+		return false
+	}
+	targetCode, ok := t.sdkCode()
+	return ok && code == targetCode
+}
+
+// sdkCode returns the SDK status code which corresponds to the status
+func (s *Status) sdkCode() (Code, bool) {
+	switch s.Group {
+	case EndorserClientStatus, OrdererClientStatus, ClientStatus:
+		return ToSDKStatusCode(s.Code), true
+	case EndorserServerStatus, OrdererServerStatus:
+		if ToFabricCommonStatusCode(s.Code) == common.Status_FORBIDDEN {
+			return AccessDenied, true
+		}
+	case GRPCTransportStatus:
+		switch ToGRPCStatusCode(s.Code) {
+		case grpcCodes.PermissionDenied:
+			return AccessDenied, true
+		case grpcCodes.DeadlineExceeded:
+			return Timeout, true
+		case grpcCodes.Unavailable:
+			return ConnectionFailed, true
+		}
+	}
+	return Unknown, false
+}
+
+type causer interface {
+	Cause() error
+}
+
+// Is reports whether any error in the chain of err matches the target. The chain consists of err followed by
+// the causes of errors wrapped with github.com/pkg/errors (see errors.Cause) and each of the errors contained
+// in a multi.Errors. An error matches the target if it is equal to it or if it has an Is(error) bool method
+// which returns true.
+func Is(err, target error) bool {
+	if target == nil {
+		return err == target
+	}
+	comparable := reflect.TypeOf(target).Comparable()
+	for err != nil {
+		if isMatch(err, target, comparable) {
+			return true
+		}
+		if m, ok := err.(multi.Errors); ok {
+			for _, e := range m {
+				if Is(e, target) {
+					return true
+				}
+			}
+			return false
+		}
+		c, ok := err.(causer)
+		if !ok {
+			return false
+		}
+		err = c.Cause()
+	}
+	return false
+}
+
+// As finds the first error in the chain of err that matches the target and, if so, sets the target to
+// that error and returns true. The chain is formed as for Is. For example:
+//
+//  var s *status.Status
+//  if status.As(err, &s) && s.Retryable() {
+//  	...
+//  }
+func As(err error, target interface{}) bool {
+	val := reflect.ValueOf(target)
+	if target == nil || val.Kind() != reflect.Ptr || val.IsNil() {
+		return false
+	}
+	for err != nil {
+		if asMatch(err, target, val) {
+			return true
+		}
+		if m, ok := err.(multi.Errors); ok {
+			for _, e := range m {
+				if As(e, target) {
+					return true
+				}
+			}
+			return false
+		}
+		c, ok := err.(causer)
+		if !ok {
+			return false
+		}
+		err = c.Cause()
+	}
+	return false
+}
+
+func isMatch(err, target error, comparable bool) bool {
+	if comparable && err == target {
+		return true
+	}
+	if x, ok := err.(interface{ Is(error) bool }); ok && x.Is(target) {
+		return true
+	}
+	return false
+}
+
+func asMatch(err error, target interface{}, val reflect.Value) bool {
+	if reflect.TypeOf(err).AssignableTo(val.Type().Elem()) {
+		val.Elem().Set(reflect.ValueOf(err))
+		return true
+	}
+	if x, ok := err.(interface{ As(interface{}) bool }); ok && x.As(target) {
+		return true
+	}
+	return false
+}
+
+// WithEndpoint returns err with the endpoint set on the statuses in its chain which don't already have
+// an endpoint. The statuses are copied rather than modified, so err (which may be one of the shared
+// errors such as ErrTimeout) is left unchanged. Errors in the chain which wrap a copied status are
+// replaced with errors which have the same message and whose cause is the copy.
+func WithEndpoint(err error, endpoint string) error {
+	if newErr := withEndpoint(err, endpoint); newErr != nil {
+		return newErr
+	}
+	return err
+}
+
+// withEndpoint returns a copy of err with the endpoint set as described for WithEndpoint or nil if none of
+// the statuses in the chain of err require the endpoint
+func withEndpoint(err error, endpoint string) error {
+	switch e := err.(type) {
+	case *Status:
+		if e.Endpoint != "" {
+			return nil
+		}
+		s := *e
+		s.Endpoint = endpoint
+		return &s
+	case multi.Errors:
+		var errs multi.Errors
+		for i, me := range e {
+			newErr := withEndpoint(me, endpoint)
+			if newErr == nil {
+				continue
+			}
+			if errs == nil {
+				errs = append(multi.Errors{}, e...)
+			}
+			errs[i] = newErr
+		}
+		if errs == nil {
+			return nil
+		}
+		return errs
+	case causer:
+		cause := withEndpoint(e.Cause(), endpoint)
+		if cause == nil {
+			return nil
+		}
+		return &withCause{error: err, cause: cause}
+	default:
+		return nil
+	}
+}
+
+// withCause replaces the cause of a wrapped error while retaining its message
+type withCause struct {
+	error
+	cause error
+}
+
+func (w *withCause) Cause() error {
+	return w.cause
+}
+
+// Format formats the wrapped error (e.g. with its stack trace for %+v)
+func (w *withCause) Format(f fmt.State, verb rune) {
+	if formatter, ok := w.error.(fmt.Formatter); ok {
+		formatter.Format(f, verb)
+		return
+	}
+	fmt.Fprintf(f, "%"+string(verb), w.error.Error())
+}
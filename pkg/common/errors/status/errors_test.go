@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+func TestIs(t *testing.T) {
+	ccNotFound := New(EndorserClientStatus, ChaincodeNameNotFound.ToInt32(), "could not find chaincode with name 'examplecc'", nil)
+
+	assert.True(t, Is(ccNotFound, ErrChaincodeNotFound))
+	assert.True(t, ccNotFound.Is(ErrChaincodeNotFound))
+	assert.False(t, Is(ccNotFound, ErrAccessDenied))
+
+	// Wrapped with github.com/pkg/errors
+	err := errors.WithMessage(errors.Wrap(ccNotFound, "endorsement failed"), "invoke failed")
+	assert.True(t, Is(err, ErrChaincodeNotFound))
+
+	// Wrapped in a target error
+	assert.True(t, Is(multi.NewTargetError("peer0", "Org1MSP", ccNotFound), ErrChaincodeNotFound))
+
+	// Contained in multiple errors
+	err = multi.New(errors.New("some error"), errors.Wrap(ccNotFound, "endorsement failed"))
+	assert.True(t, Is(err, ErrChaincodeNotFound))
+	assert.False(t, Is(err, ErrTimeout))
+
+	// Server and transport codes which map to SDK codes
+	assert.True(t, Is(New(OrdererServerStatus, int32(common.Status_FORBIDDEN), "forbidden", nil), ErrAccessDenied))
+	assert.True(t, Is(NewFromGRPCStatus(grpcstatus.New(grpccodes.PermissionDenied, "denied")), ErrAccessDenied))
+	assert.True(t, Is(NewFromGRPCStatus(grpcstatus.New(grpccodes.DeadlineExceeded, "deadline")), ErrTimeout))
+	assert.False(t, Is(New(ChaincodeStatus, 500, "error", nil), ErrTimeout))
+
+	assert.False(t, Is(nil, ErrTimeout))
+	assert.False(t, Is(errors.New("access denied"), ErrAccessDenied))
+}
+
+func TestAs(t *testing.T) {
+	s := New(OrdererServerStatus, int32(common.Status_SERVICE_UNAVAILABLE), "unavailable", nil)
+
+	var target *Status
+	assert.True(t, As(errors.WithMessage(s, "broadcast failed"), &target))
+	assert.Equal(t, s, target)
+	assert.True(t, target.Retryable())
+
+	target = nil
+	assert.True(t, As(multi.New(errors.New("error"), errors.Wrap(s, "wrapped")), &target))
+	assert.Equal(t, s, target)
+
+	assert.False(t, As(errors.New("error"), &target))
+
+	// FromError also handles wrapped statuses
+	derived, ok := FromError(errors.Wrap(s, "wrapped"))
+	assert.True(t, ok)
+	assert.Equal(t, s, derived)
+}
+
+func TestRetryable(t *testing.T) {
+	assert.True(t, New(EndorserClientStatus, PrematureChaincodeExecution.ToInt32(), "", nil).Retryable())
+	assert.True(t, NewFromGRPCStatus(grpcstatus.New(grpccodes.Unavailable, "")).Retryable())
+	assert.False(t, New(EndorserClientStatus, AccessDenied.ToInt32(), "", nil).Retryable())
+	assert.False(t, New(ChaincodeStatus, 500, "", nil).Retryable())
+}
+
+func TestWithEndpoint(t *testing.T) {
+	s1 := New(EndorserClientStatus, ConnectionFailed.ToInt32(), "", nil)
+	s2 := New(EndorserClientStatus, ConnectionFailed.ToInt32(), "", nil)
+	s2.Endpoint = "peer1:7051"
+
+	wrapped := errors.Wrap(multi.New(s1, s2), "failed")
+	err := WithEndpoint(wrapped, "peer0:7051")
+	assert.Error(t, err)
+	assert.Equal(t, wrapped.Error(), err.Error())
+	assert.Empty(t, s1.Endpoint, "expecting original status to be unchanged")
+
+	m, ok := errors.Cause(err).(multi.Errors)
+	assert.True(t, ok)
+	assert.Equal(t, "peer0:7051", m[0].(*Status).Endpoint)
+	assert.Equal(t, "peer1:7051", m[1].(*Status).Endpoint, "expecting existing endpoint to be retained")
+
+	err = WithEndpoint(ErrTimeout, "peer0:7051")
+	assert.True(t, Is(err, ErrTimeout))
+	assert.Empty(t, ErrTimeout.Endpoint, "expecting shared error to be unchanged")
+
+	var s *Status
+	assert.True(t, As(errors.WithMessage(WithEndpoint(errors.Wrap(ErrTimeout, "wrapped"), "peer0:7051"), "msg"), &s))
+	assert.Equal(t, "peer0:7051", s.Endpoint)
+
+	assert.NoError(t, WithEndpoint(nil, "peer0:7051"))
+}
@@ -10,6 +10,8 @@ SPDX-License-Identifier: Apache-2.0
 // Status codes are divided by group, where each group represents a particular
 // component and the codes correspond to those returned by the component.
 // These are defined in detail below.
+// Common conditions may be checked with Is (for example, status.Is(err, status.ErrChaincodeNotFound))
+// and the status may be retrieved from a wrapped error with As or FromError.
 package status
 
 import (
@@ -34,6 +36,8 @@ type Status struct {
 	Message string
 	// Details any additional status details
 	Details []interface{}
+	// Endpoint the URL of the endpoint (peer, orderer, etc.) that produced the status, if known
+	Endpoint string
 }
 
 // Group of status to help users infer status codes from various components
@@ -131,6 +135,9 @@ func FromError(err error) (s *Status, ok bool) {
 		}
 		return New(ClientStatus, MultipleErrors.ToInt32(), m.Error(), errors), true
 	}
+	if As(err, &s) {
+		return s, true
+	}
 
 	return nil, false
 }
@@ -166,7 +173,9 @@ func NewFromProposalResponse(res *pb.ProposalResponse, endorser string) *Status
 	}
 	details := []interface{}{endorser, res.Response.Payload}
 
-	return New(EndorserServerStatus, res.Response.Status, res.Response.Message, details)
+	s := New(EndorserServerStatus, res.Response.Status, res.Response.Message, details)
+	s.Endpoint = endorser
+	return s
 }
 
 // NewFromGRPCStatus new Status from gRPC status response
@@ -0,0 +1,76 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logging
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/modlog"
+)
+
+// Binding binds loggers to a logger provider which may be swapped at any time. Unlike the loggers created
+// with NewLogger, which use the process-wide provider set with Initialize, the loggers of a binding use the
+// provider of the binding so that, for example, two SDK instances in the same process may log to different
+// outputs at different levels (see modlog.NewProvider).
+type Binding struct {
+	mutex      sync.RWMutex
+	provider   api.LoggerProvider
+	generation uint64
+}
+
+// NewBinding returns a binding to the given logger provider (or to the default provider if nil)
+func NewBinding(provider api.LoggerProvider) *Binding {
+	if provider == nil {
+		provider = modlog.LoggerProvider()
+	}
+	return &Binding{provider: provider, generation: 1}
+}
+
+// NewLogger creates and returns a Logger for the given module which is bound to the binding
+func (b *Binding) NewLogger(module string) *Logger {
+	return &Logger{module: module, binding: b}
+}
+
+// Provider returns the logger provider of the binding
+func (b *Binding) Provider() api.LoggerProvider {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.provider
+}
+
+// SetProvider swaps the logger provider. The loggers of the binding use the new provider for subsequent log calls.
+func (b *Binding) SetProvider(provider api.LoggerProvider) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.provider = provider
+	atomic.AddUint64(&b.generation, 1)
+}
+
+// boundLogger returns the logger of the binding's provider, which is (re-)created if the provider has been swapped
+func (l *Logger) boundLogger() api.Logger {
+	generation := atomic.LoadUint64(&l.binding.generation)
+
+	l.mutex.RLock()
+	instance, current := l.instance, l.generation == generation
+	l.mutex.RUnlock()
+	if current {
+		return instance
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.binding.mutex.RLock()
+	l.instance = l.binding.provider.GetLogger(l.module)
+	l.generation = atomic.LoadUint64(&l.binding.generation)
+	l.binding.mutex.RUnlock()
+
+	return l.instance
+}
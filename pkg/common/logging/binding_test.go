@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/modlog"
+)
+
+func TestBindingIndependentProviders(t *testing.T) {
+	var buf1, buf2 bytes.Buffer
+
+	provider1 := modlog.NewProvider(&buf1)
+	provider1.SetLevel(moduleName, api.DEBUG)
+	provider2 := modlog.NewProvider(&buf2)
+	provider2.SetLevel(moduleName, api.ERROR)
+
+	logger1 := NewBinding(provider1).NewLogger(moduleName)
+	logger2 := NewBinding(provider2).NewLogger(moduleName)
+
+	logger1.Debug("debug from instance 1")
+	logger2.Debug("debug from instance 2")
+	logger2.Error("error from instance 2")
+
+	if !strings.Contains(buf1.String(), "debug from instance 1") {
+		t.Fatalf("expected debug message in output of instance 1: %s", buf1.String())
+	}
+	if strings.Contains(buf1.String(), "instance 2") {
+		t.Fatalf("unexpected message of instance 2 in output of instance 1: %s", buf1.String())
+	}
+	if strings.Contains(buf2.String(), "debug from instance 2") {
+		t.Fatalf("debug message shouldn't be logged at level ERROR: %s", buf2.String())
+	}
+	if !strings.Contains(buf2.String(), "error from instance 2") {
+		t.Fatalf("expected error message in output of instance 2: %s", buf2.String())
+	}
+}
+
+func TestBindingSetProvider(t *testing.T) {
+	var buf1, buf2 bytes.Buffer
+
+	provider1 := modlog.NewProvider(&buf1)
+	provider2 := modlog.NewProvider(&buf2)
+
+	binding := NewBinding(provider1)
+	logger := binding.NewLogger(moduleName)
+
+	logger.Info("before swap")
+	binding.SetProvider(provider2)
+	if binding.Provider() != provider2 {
+		t.Fatal("expected swapped provider")
+	}
+	logger.Info("after swap")
+
+	if !strings.Contains(buf1.String(), "before swap") || strings.Contains(buf1.String(), "after swap") {
+		t.Fatalf("unexpected output of first provider: %s", buf1.String())
+	}
+	if !strings.Contains(buf2.String(), "after swap") || strings.Contains(buf2.String(), "before swap") {
+		t.Fatalf("unexpected output of second provider: %s", buf2.String())
+	}
+}
@@ -25,6 +25,11 @@ type Logger struct {
 	instance api.Logger // access only via Logger.logger()
 	module   string
 	once     sync.Once
+
+	// binding is set for loggers created with Binding.NewLogger
+	binding    *Binding
+	mutex      sync.RWMutex
+	generation uint64
 }
 
 // logger factory singleton - access only via loggerProvider()
@@ -231,6 +236,9 @@ func (l *Logger) Errorln(args ...interface{}) {
 }
 
 func (l *Logger) logger() api.Logger {
+	if l.binding != nil {
+		return l.boundLogger()
+	}
 	l.once.Do(func() {
 		l.instance = loggerProvider().GetLogger(l.module)
 	})
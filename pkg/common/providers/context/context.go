@@ -43,3 +43,6 @@ type LocalProvider func() (Local, error)
 
 // ChannelProvider returns channel client context
 type ChannelProvider func() (Channel, error)
+
+// MultiChannelProvider returns the channel client context for the given channel
+type MultiChannelProvider func(channelID string) (Channel, error)
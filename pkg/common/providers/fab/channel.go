@@ -55,6 +55,7 @@ type ChannelCfg interface {
 	Orderers() []string
 	Versions() *Versions
 	HasCapability(group ConfigGroupKey, capability string) bool
+	HashingAlgorithm() string
 }
 
 // ChannelMembership helps identify a channel's members
@@ -67,6 +68,14 @@ type ChannelMembership interface {
 	ContainsMSP(msp string) bool
 }
 
+// EndorserValidator is optionally implemented by a ChannelMembership in order to validate the identities of
+// endorsers more strictly than Validate
+type EndorserValidator interface {
+	// ValidateEndorser validates that the given ID is a valid member of the given MSP (of any of the channel's
+	// MSPs if the MSP ID is empty) and, if the MSP distinguishes node roles (node OUs), that it has the peer role
+	ValidateEndorser(serializedID []byte, mspID string) error
+}
+
 // Versions ...
 type Versions struct {
 	ReadSet  *common.ConfigGroup
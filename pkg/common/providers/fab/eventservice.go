@@ -7,11 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package fab
 
 import (
+	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
-// BlockEvent contains the data for the block event
+// BlockEvent contains the data for the block event. The event (and its block) is shared by all of the
+// registrations which receive it so consumers must not modify it; use Copy to get a copy which may be modified.
 type BlockEvent struct {
 	// Block is the block that was committed
 	Block *cb.Block
@@ -19,7 +21,8 @@ type BlockEvent struct {
 	SourceURL string
 }
 
-// FilteredBlockEvent contains the data for a filtered block event
+// FilteredBlockEvent contains the data for a filtered block event. The event is shared by all of the
+// registrations which receive it so consumers must not modify it; use Copy to get a copy which may be modified.
 type FilteredBlockEvent struct {
 	// FilteredBlock contains a filtered version of the block that was committed
 	FilteredBlock *pb.FilteredBlock
@@ -40,7 +43,8 @@ type TxStatusEvent struct {
 	SourceURL string
 }
 
-// CCEvent contains the data for a chaincode event
+// CCEvent contains the data for a chaincode event. The payload is shared by all of the registrations
+// which receive the event so consumers must not modify it; use Copy to get a copy which may be modified.
 type CCEvent struct {
 	// TxID is the ID of the transaction in which the event was set
 	TxID string
@@ -58,6 +62,34 @@ type CCEvent struct {
 	SourceURL string
 }
 
+// Copy returns a deep copy of the block event (including the block) which the caller owns and may modify
+func (e *BlockEvent) Copy() *BlockEvent {
+	c := *e
+	if e.Block != nil {
+		c.Block = proto.Clone(e.Block).(*cb.Block)
+	}
+	return &c
+}
+
+// Copy returns a deep copy of the filtered block event (including the filtered block) which the caller
+// owns and may modify
+func (e *FilteredBlockEvent) Copy() *FilteredBlockEvent {
+	c := *e
+	if e.FilteredBlock != nil {
+		c.FilteredBlock = proto.Clone(e.FilteredBlock).(*pb.FilteredBlock)
+	}
+	return &c
+}
+
+// Copy returns a deep copy of the chaincode event (including the payload) which the caller owns and may modify
+func (e *CCEvent) Copy() *CCEvent {
+	c := *e
+	if e.Payload != nil {
+		c.Payload = append([]byte(nil), e.Payload...)
+	}
+	return &c
+}
+
 // Registration is a handle that is returned from a successful RegisterXXXEvent.
 // This handle should be used in Unregister in order to unregister the event.
 type Registration interface{}
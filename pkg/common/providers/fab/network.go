@@ -8,6 +8,8 @@ package fab
 
 import (
 	"crypto/x509"
+	"strings"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 )
@@ -35,6 +37,63 @@ type ChannelEndpointConfig struct {
 type ChannelPolicies struct {
 	//Policy for querying channel block
 	QueryChannelConfig QueryChannelConfigPolicy
+	//Timeouts overrides the client timeouts for the channel
+	Timeouts TimeoutPolicy
+	//Selection policy for choosing endorsing peers
+	Selection SelectionPolicy
+	//Observer policy for clients whose organization isn't a member of the channel
+	Observer ObserverPolicy
+}
+
+//ObserverPolicy defines the policy for read-only clients whose organization isn't a member of the
+//channel (e.g. auditors which are granted read access through the channel ACLs)
+type ObserverPolicy struct {
+	//Enabled disables dynamic discovery and Fabric selection (only the peers configured for the channel
+	//are used) and allows the peers of any organization to be used for channel config and ledger queries
+	Enabled bool
+}
+
+//SelectionPolicy defines the policy for choosing endorsing peers
+type SelectionPolicy struct {
+	//Balancer is the load-balancing strategy used to choose among the peers
+	Balancer BalancerType
+	//PreferLocalOrg selects only peers of the client's organization if they satisfy the endorsement policy.
+	//Peers of other organizations are only selected if the policy requires them or if the local peers are
+	//unavailable, in which case the local peers are still preferred (Fabric selection only).
+	PreferLocalOrg bool
+}
+
+// BalancerType is the load-balancing strategy used to choose among peers
+type BalancerType string
+
+const (
+	// Random chooses peers randomly (the default)
+	Random BalancerType = "Random"
+	// RoundRobin chooses peers in a round-robin fashion
+	RoundRobin BalancerType = "RoundRobin"
+	// Weighted favours peers with a low latency, a low error rate and an up-to-date ledger
+	// (the latency and error rate are tracked by the SDK)
+	Weighted BalancerType = "Weighted"
+)
+
+//TimeoutPolicy defines timeout overrides for a channel by operation type and by chaincode
+type TimeoutPolicy struct {
+	//Operations timeouts, by operation type, which override the client timeouts
+	Operations map[TimeoutType]time.Duration
+	//Chaincodes timeouts, by operation type, which override the channel timeouts for the chaincode
+	//(the chaincode IDs are lower case)
+	Chaincodes map[string]map[TimeoutType]time.Duration
+}
+
+// Timeout returns the timeout override for the given chaincode and operation type. The chaincode
+// override takes precedence over the channel override. Zero is returned if there is no override.
+func (p TimeoutPolicy) Timeout(ccID string, tType TimeoutType) time.Duration {
+	if ccID != "" {
+		if timeout := p.Chaincodes[strings.ToLower(ccID)][tType]; timeout > 0 {
+			return timeout
+		}
+	}
+	return p.Operations[tType]
 }
 
 //QueryChannelConfigPolicy defines opts for channelConfigBlock
@@ -76,16 +135,19 @@ type OrganizationConfig struct {
 
 // OrdererConfig defines an orderer configuration
 type OrdererConfig struct {
-	URL         string
-	GRPCOptions map[string]interface{}
-	TLSCACert   *x509.Certificate
+	URL           string
+	GRPCOptions   map[string]interface{}
+	TLSCACert     *x509.Certificate
+	OperationsURL string
+	AdminURL      string
 }
 
 // PeerConfig defines a peer configuration
 type PeerConfig struct {
-	URL         string
-	GRPCOptions map[string]interface{}
-	TLSCACert   *x509.Certificate
+	URL           string
+	GRPCOptions   map[string]interface{}
+	TLSCACert     *x509.Certificate
+	OperationsURL string
 }
 
 // CertKeyPair contains the private key and certificate
@@ -46,6 +46,9 @@ type ChaincodeInvokeRequest struct {
 	TransientMap map[string][]byte
 	Fcn          string
 	Args         [][]byte
+	// IsInit indicates that the invocation initializes a chaincode which was committed with the
+	// init required flag (Fabric 2.0 chaincode lifecycle)
+	IsInit bool
 }
 
 // TransactionProposal contains a marashalled transaction proposal.
@@ -62,6 +65,8 @@ type ProcessProposalRequest struct {
 // TransactionProposalResponse respresents the result of transaction proposal processing.
 type TransactionProposalResponse struct {
 	Endorser string
+	// EndorserMSPID is the MSP ID of the endorsing peer (if known)
+	EndorserMSPID string
 	// Status is the EndorserStatus
 	Status int32
 	// ChaincodeStatus is the status returned by Chaincode
@@ -42,6 +42,12 @@ type InfraProvider interface {
 type ChaincodeCall struct {
 	ID          string
 	Collections []string
+	// NoPrivateReads indicates that the chaincode only writes to the collections (i.e. it doesn't read
+	// private data) so the endorsers needn't be members of the collections. NoPublicWrites indicates that
+	// the chaincode doesn't write public state. These hints are passed to the discovery service which
+	// takes them into account in Fabric 2.0 or later.
+	NoPrivateReads bool
+	NoPublicWrites bool
 }
 
 // SelectionService selects peers for endorsement and commit events
@@ -112,6 +118,21 @@ type EventServiceConfig interface {
 	// BlockHeightMonitorPeriod is the period in which the connected peer's block height is monitored. Note that this
 	// value is only relevant if reconnectBlockHeightLagThreshold >0.
 	BlockHeightMonitorPeriod() time.Duration
+
+	// ShareConnections - if true then a single event service connection is shared by all of the clients of an
+	// organization (i.e. identities with the same MSP ID) which listen on the same channel with the same options.
+	// The connection is established using the identity of the first client.
+	ShareConnections() bool
+
+	// KeepAliveTime and KeepAliveTimeout override the gRPC keep-alive parameters of the peers' event (deliver)
+	// connections. If set to 0 then the keep-alive parameters from the peer's gRPC options are used.
+	KeepAliveTime() time.Duration
+	KeepAliveTimeout() time.Duration
+
+	// StreamIdleTimeout - if >0 then the event client will disconnect from the peer (and reconnect) if no event
+	// was received on the deliver stream within the given time. The value should be greater than the maximum
+	// expected time between blocks on the channel.
+	StreamIdleTimeout() time.Duration
 }
 
 // TimeoutType enumerates the different types of outgoing connections
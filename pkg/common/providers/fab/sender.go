@@ -32,5 +32,9 @@ type Transaction struct {
 
 // TransactionResponse contains information returned by the orderer.
 type TransactionResponse struct {
+	// Orderer is the URL of the orderer which accepted the envelope
 	Orderer string
+	// FailedOrderers are the URLs of the orderers to which the envelope was broadcast before
+	// it was accepted (e.g. because they were unavailable)
+	FailedOrderers []string
 }
@@ -19,6 +19,33 @@ var (
 // IdentityManager provides management of identities in Fabric network
 type IdentityManager interface {
 	GetSigningIdentity(name string) (SigningIdentity, error)
+	CreateSigningIdentity(opts ...SigningIdentityOption) (SigningIdentity, error)
+}
+
+// IdentityOption captures options used for creating a new SigningIdentity instance
+type IdentityOption struct {
+	Cert       []byte
+	PrivateKey []byte
+}
+
+// SigningIdentityOption describes a functional parameter for creating a new SigningIdentity instance
+type SigningIdentityOption func(*IdentityOption) error
+
+// WithCert sets the PEM-encoded certificate of the signing identity
+func WithCert(cert []byte) SigningIdentityOption {
+	return func(o *IdentityOption) error {
+		o.Cert = cert
+		return nil
+	}
+}
+
+// WithPrivateKey sets the PEM-encoded private key of the signing identity. If the private key
+// is not provided then it must be available in the key store of the crypto suite.
+func WithPrivateKey(key []byte) SigningIdentityOption {
+	return func(o *IdentityOption) error {
+		o.PrivateKey = key
+		return nil
+	}
 }
 
 // Identity represents a Fabric client identity
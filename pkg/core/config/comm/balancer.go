@@ -0,0 +1,56 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc"
+)
+
+// LoadBalancingPolicyOption is the gRPC option of a peer or orderer which enables DNS re-resolution
+// and load balancing across all of the addresses that the host name of the endpoint resolves to
+// (for example, multiple A records or a headless Kubernetes service)
+const LoadBalancingPolicyOption = "load-balancing-policy"
+
+const (
+	// RoundRobin balances the requests across all of the resolved addresses
+	RoundRobin = "round_robin"
+	// PickFirst sends all requests to the first resolved address which is reachable
+	PickFirst = "pick_first"
+
+	dnsScheme = "dns:///"
+)
+
+// LoadBalancingPolicy returns the load balancing policy from the given gRPC options or an empty string if none is configured
+func LoadBalancingPolicy(grpcOptions map[string]interface{}) string {
+	if policy, ok := grpcOptions[LoadBalancingPolicyOption]; ok {
+		return cast.ToString(policy)
+	}
+	return ""
+}
+
+// DialTarget returns the gRPC target and additional dial options for the given address and load balancing policy.
+// If a policy is specified then the address is resolved with gRPC's DNS resolver (which re-resolves the address
+// whenever a connection fails) and requests are balanced according to the policy. Otherwise the address is
+// returned as is and gRPC connects to a single resolved address for the lifetime of the connection.
+func DialTarget(address string, policy string) (string, []grpc.DialOption, error) {
+	switch policy {
+	case "":
+		return address, nil, nil
+	case RoundRobin, PickFirst:
+		target := address
+		if !strings.Contains(address, ":///") {
+			target = dnsScheme + address
+		}
+		return target, []grpc.DialOption{grpc.WithBalancerName(policy)}, nil
+	default:
+		return "", nil, errors.Errorf("unsupported load balancing policy [%s]", policy)
+	}
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package comm
 
 import (
+	"bytes"
 	"crypto/tls"
 
 	"crypto/x509"
@@ -18,6 +19,9 @@ import (
 
 // TLSConfig returns the appropriate config for TLS including the root CAs,
 // certs for mutual TLS, and server host override. Works with certs loaded either from a path or embedded pem.
+// The client certificate is resolved from the endpoint config on each handshake so that connections which are
+// (re-)established after the client certificate has been rotated (e.g. by a SPIFFE workload API source) use the
+// new certificate.
 func TLSConfig(cert *x509.Certificate, serverName string, config fab.EndpointConfig) (*tls.Config, error) {
 
 	if cert != nil {
@@ -28,7 +32,47 @@ func TLSConfig(cert *x509.Certificate, serverName string, config fab.EndpointCon
 	if err != nil {
 		return nil, err
 	}
-	return &tls.Config{RootCAs: certPool, Certificates: config.TLSClientCerts(), ServerName: serverName}, nil
+	return &tls.Config{
+		RootCAs:              certPool,
+		Certificates:         config.TLSClientCerts(),
+		GetClientCertificate: clientCertificate(config),
+		ServerName:           serverName,
+	}, nil
+}
+
+// clientCertificate returns a function which selects the client certificate for a handshake. Like the
+// default selection of the TLS package, the first certificate issued by one of the CAs accepted by the
+// server is selected or, if there's no such certificate, the first certificate.
+func clientCertificate(config fab.EndpointConfig) func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return func(cri *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		certs := config.TLSClientCerts()
+		if len(certs) == 0 {
+			// No certificate is sent
+			return &tls.Certificate{}, nil
+		}
+
+		for i := range certs {
+			if issuedByAcceptableCA(&certs[i], cri.AcceptableCAs) {
+				return &certs[i], nil
+			}
+		}
+		return &certs[0], nil
+	}
+}
+
+func issuedByAcceptableCA(cert *tls.Certificate, acceptableCAs [][]byte) bool {
+	for _, certBytes := range cert.Certificate {
+		x509Cert, err := x509.ParseCertificate(certBytes)
+		if err != nil {
+			continue
+		}
+		for _, ca := range acceptableCAs {
+			if bytes.Equal(x509Cert.RawIssuer, ca) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // TLSCertHash is a utility method to calculate the SHA256 hash of the configured certificate (for usage in channel headers)
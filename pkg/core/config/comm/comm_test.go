@@ -90,6 +90,14 @@ func TestTLSConfigHappyPath(t *testing.T) {
 	if !reflect.DeepEqual(tlsConfig.Certificates[0], mockfab.TLSCert) {
 		t.Fatal("Certs do not match")
 	}
+
+	clientCert, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(*clientCert, mockfab.TLSCert) {
+		t.Fatal("Client cert resolved for handshake does not match")
+	}
 }
 
 func createNCerts(n int) []*x509.Certificate {
@@ -153,3 +161,23 @@ func TestTlsCertHash(t *testing.T) {
 		t.Fatal("Cert hash calculated incorrectly")
 	}
 }
+
+func TestDialTarget(t *testing.T) {
+	target, opts, err := DialTarget("peer0.org1.example.com:7051", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "peer0.org1.example.com:7051", target)
+	assert.Empty(t, opts)
+
+	policy := LoadBalancingPolicy(map[string]interface{}{LoadBalancingPolicyOption: RoundRobin})
+	assert.Equal(t, RoundRobin, policy)
+
+	target, opts, err = DialTarget("peer0.org1.example.com:7051", policy)
+	assert.NoError(t, err)
+	assert.Equal(t, "dns:///peer0.org1.example.com:7051", target)
+	assert.Len(t, opts, 1)
+
+	_, _, err = DialTarget("peer0.org1.example.com:7051", "random")
+	assert.Error(t, err, "expecting error for unsupported policy")
+
+	assert.Empty(t, LoadBalancingPolicy(map[string]interface{}{}))
+}
@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// DialerOption is the gRPC option of a peer or orderer which specifies a custom dialer for establishing the
+// connections to the endpoint, e.g. through a bastion host, over a Unix socket, through a service mesh or
+// over a user-space network. The value is either the name of a dialer registered with RegisterDialer (which
+// allows the dialer to be referenced from a config file) or, if the endpoint config is provided
+// programmatically, a Dialer, a DialContext function or a *net.Dialer. The option may not be combined with
+// the proxy-url option.
+const DialerOption = "dialer"
+
+// DialContext establishes a network connection to the given address (it has the signature of net.Dialer.DialContext)
+type DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+
+var dialers = struct {
+	sync.RWMutex
+	registered map[string]Dialer
+}{
+	registered: make(map[string]Dialer),
+}
+
+// RegisterDialer registers a dialer which is used for the peers and orderers whose dialer option is the given name.
+// An existing dialer with the same name is replaced.
+func RegisterDialer(name string, dialer Dialer) {
+	dialers.Lock()
+	defer dialers.Unlock()
+	dialers.registered[name] = dialer
+}
+
+// FromDialContext returns a Dialer which establishes TCP connections with the given DialContext function
+func FromDialContext(dialContext DialContext) Dialer {
+	return func(addr string, timeout time.Duration) (net.Conn, error) {
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return dialContext(ctx, "tcp", addr)
+	}
+}
+
+// DialerFromGRPC returns the custom dialer specified by the dialer option in the given gRPC options
+// or nil if none is configured
+func DialerFromGRPC(grpcOptions map[string]interface{}) (Dialer, error) {
+	value, ok := grpcOptions[DialerOption]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	switch dialer := value.(type) {
+	case string:
+		if dialer == "" {
+			return nil, nil
+		}
+		dialers.RLock()
+		registered, ok := dialers.registered[dialer]
+		dialers.RUnlock()
+		if !ok {
+			return nil, errors.Errorf("dialer [%s] is not registered", dialer)
+		}
+		return registered, nil
+	case Dialer:
+		return dialer, nil
+	case func(addr string, timeout time.Duration) (net.Conn, error):
+		return dialer, nil
+	case DialContext:
+		return FromDialContext(dialer), nil
+	case func(ctx context.Context, network, addr string) (net.Conn, error):
+		return FromDialContext(dialer), nil
+	case *net.Dialer:
+		return FromDialContext(dialer.DialContext), nil
+	default:
+		return nil, errors.Errorf("unsupported dialer type [%T]", value)
+	}
+}
+
+// DialOptions returns the dial options which establish connections with the given custom dialer or
+// through the proxy with the given URL. No options are returned if neither is set.
+func DialOptions(proxyURL string, dialer Dialer) ([]grpc.DialOption, error) {
+	if dialer == nil {
+		return ProxyDialOptions(proxyURL)
+	}
+	if proxyURL != "" {
+		return nil, errors.New("a custom dialer can't be combined with a proxy URL")
+	}
+	return []grpc.DialOption{grpc.WithDialer(dialer)}, nil
+}
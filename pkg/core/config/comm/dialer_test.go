@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialerFromGRPC(t *testing.T) {
+	echoAddr := startEchoServer(t)
+
+	dialer, err := DialerFromGRPC(map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Nil(t, dialer)
+
+	// The dialer ignores the target address, e.g. like a dialer which connects over a Unix socket
+	RegisterDialer("echo", func(addr string, timeout time.Duration) (net.Conn, error) {
+		return net.DialTimeout("tcp", echoAddr, timeout)
+	})
+	dialer, err = DialerFromGRPC(map[string]interface{}{DialerOption: "echo"})
+	require.NoError(t, err)
+	checkEcho(t, dialer, "peer0.org1.example.com:7051")
+
+	_, err = DialerFromGRPC(map[string]interface{}{DialerOption: "unknown"})
+	assert.Error(t, err, "expecting error for dialer which isn't registered")
+
+	dialer, err = DialerFromGRPC(map[string]interface{}{DialerOption: &net.Dialer{}})
+	require.NoError(t, err)
+	checkEcho(t, dialer, echoAddr)
+
+	dialContext := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, echoAddr)
+	}
+	dialer, err = DialerFromGRPC(map[string]interface{}{DialerOption: dialContext})
+	require.NoError(t, err)
+	checkEcho(t, dialer, "peer0.org1.example.com:7051")
+
+	_, err = DialerFromGRPC(map[string]interface{}{DialerOption: 10})
+	assert.Error(t, err, "expecting error for unsupported dialer type")
+}
+
+func TestDialOptions(t *testing.T) {
+	dialer := FromDialContext((&net.Dialer{}).DialContext)
+
+	opts, err := DialOptions("", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, opts)
+
+	opts, err = DialOptions("", dialer)
+	assert.NoError(t, err)
+	assert.Len(t, opts, 1)
+
+	opts, err = DialOptions("socks5://proxy.example.com:1080", nil)
+	assert.NoError(t, err)
+	assert.Len(t, opts, 1)
+
+	_, err = DialOptions("socks5://proxy.example.com:1080", dialer)
+	assert.Error(t, err, "expecting error since a dialer can't be combined with a proxy")
+}
@@ -0,0 +1,325 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc"
+)
+
+// ProxyURLOption is the gRPC option of a peer or orderer which specifies the proxy through which connections
+// to the endpoint are established. The URL has the form socks5://[user:password@]host:port (SOCKS5 proxy),
+// http://[user:password@]host:port (HTTP CONNECT proxy) or ws[s]://[user:password@]host:port/path (WebSocket
+// gateway). Additional schemes may be supported with RegisterTransport. The option may be set for all peers
+// or orderers in the '_default' entry.
+const ProxyURLOption = "proxy-url"
+
+const (
+	socks5Version        = 0x05
+	socks5AuthNone       = 0x00
+	socks5AuthPassword   = 0x02
+	socks5AuthNoMethods  = 0xff
+	socks5PasswordVer    = 0x01
+	socks5CmdConnect     = 0x01
+	socks5AddrTypeIPv4   = 0x01
+	socks5AddrTypeDomain = 0x03
+	socks5AddrTypeIPv6   = 0x04
+)
+
+// ProxyURL returns the proxy URL from the given gRPC options or an empty string if none is configured
+func ProxyURL(grpcOptions map[string]interface{}) string {
+	if proxyURL, ok := grpcOptions[ProxyURLOption]; ok {
+		return cast.ToString(proxyURL)
+	}
+	return ""
+}
+
+// ProxyDialOptions returns the dial options which establish connections through the proxy with the given URL.
+// No options are returned if the proxy URL is empty.
+func ProxyDialOptions(proxyURL string) ([]grpc.DialOption, error) {
+	if proxyURL == "" {
+		return nil, nil
+	}
+
+	dial, err := newProxyDialer(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	return []grpc.DialOption{grpc.WithDialer(dial)}, nil
+}
+
+// Dialer establishes a network connection to the given address (host:port) of a peer or orderer
+type Dialer func(addr string, timeout time.Duration) (net.Conn, error)
+
+// TransportProvider returns a Dialer which connects through the proxy or gateway with the given URL
+type TransportProvider func(transportURL *url.URL) (Dialer, error)
+
+var transports = struct {
+	sync.RWMutex
+	providers map[string]TransportProvider
+}{
+	providers: map[string]TransportProvider{
+		"socks5":  newTunnelTransport(socks5Connect),
+		"socks5h": newTunnelTransport(socks5Connect),
+		"http":    newTunnelTransport(httpConnect),
+		"ws":      newTunnelTransport(webSocketConnect),
+		"wss":     newTunnelTransport(webSocketConnect),
+	},
+}
+
+// RegisterTransport registers a transport for the given proxy URL scheme. This allows connections to peers
+// and orderers to be established through alternative transports which aren't supported out of the box.
+// An existing transport with the same scheme is replaced.
+func RegisterTransport(scheme string, provider TransportProvider) {
+	transports.Lock()
+	defer transports.Unlock()
+	transports.providers[scheme] = provider
+}
+
+func newProxyDialer(proxyURL string) (Dialer, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid proxy URL [%s]", proxyURL)
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("host is missing in proxy URL [%s]", proxyURL)
+	}
+
+	transports.RLock()
+	provider, ok := transports.providers[u.Scheme]
+	transports.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("unsupported proxy scheme [%s]", u.Scheme)
+	}
+	return provider(u)
+}
+
+type handshake func(conn net.Conn, addr string, proxyURL *url.URL) (net.Conn, error)
+
+// newTunnelTransport returns a transport which connects to the proxy and then performs the given handshake
+// in order to tunnel the connection to the target address
+func newTunnelTransport(handshake handshake) TransportProvider {
+	return func(u *url.URL) (Dialer, error) {
+		return func(addr string, timeout time.Duration) (net.Conn, error) {
+			conn, err := net.DialTimeout("tcp", proxyHostPort(u), timeout)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to connect to proxy [%s]", u.Host)
+			}
+
+			if timeout > 0 {
+				if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+					conn.Close()
+					return nil, err
+				}
+			}
+
+			proxiedConn, err := handshake(conn, addr, u)
+			if err != nil {
+				conn.Close()
+				return nil, errors.WithMessage(err, "failed to connect to ["+addr+"] through proxy ["+u.Host+"]")
+			}
+
+			if err := proxiedConn.SetDeadline(time.Time{}); err != nil {
+				proxiedConn.Close()
+				return nil, err
+			}
+			return proxiedConn, nil
+		}, nil
+	}
+}
+
+// proxyHostPort returns the host and port of the proxy, using the default port of the scheme if none is specified
+func proxyHostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	switch u.Scheme {
+	case "http", "ws":
+		return net.JoinHostPort(u.Hostname(), "80")
+	case "wss":
+		return net.JoinHostPort(u.Hostname(), "443")
+	default:
+		return net.JoinHostPort(u.Hostname(), "1080")
+	}
+}
+
+// httpConnect establishes a tunnel to the given address using the HTTP CONNECT method
+func httpConnect(conn net.Conn, addr string, proxyURL *url.URL) (net.Conn, error) {
+	user := proxyURL.User
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Host: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := req.Write(conn); err != nil {
+		return nil, errors.Wrap(err, "failed to write CONNECT request")
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read CONNECT response")
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("proxy refused CONNECT request: %s", resp.Status)
+	}
+
+	// The reader may have buffered data which was sent by the server after the response
+	return &bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+// socks5Connect establishes a connection to the given address using the SOCKS5 protocol (RFC 1928)
+// with optional username/password authentication (RFC 1929)
+func socks5Connect(conn net.Conn, addr string, proxyURL *url.URL) (net.Conn, error) {
+	user := proxyURL.User
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid address [%s]", addr)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid port in address [%s]", addr)
+	}
+
+	methods := []byte{socks5AuthNone}
+	if user != nil {
+		methods = append(methods, socks5AuthPassword)
+	}
+	if _, err := conn.Write(append([]byte{socks5Version, byte(len(methods))}, methods...)); err != nil {
+		return nil, errors.Wrap(err, "failed to write SOCKS5 greeting")
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return nil, errors.Wrap(err, "failed to read SOCKS5 greeting reply")
+	}
+	if reply[0] != socks5Version {
+		return nil, errors.Errorf("unexpected SOCKS version [%d]", reply[0])
+	}
+
+	switch reply[1] {
+	case socks5AuthNone:
+	case socks5AuthPassword:
+		if user == nil {
+			return nil, errors.New("SOCKS5 proxy requires authentication")
+		}
+		if err := socks5Authenticate(conn, user); err != nil {
+			return nil, err
+		}
+	case socks5AuthNoMethods:
+		return nil, errors.New("no acceptable SOCKS5 authentication method")
+	default:
+		return nil, errors.Errorf("unsupported SOCKS5 authentication method [%d]", reply[1])
+	}
+
+	req := []byte{socks5Version, socks5CmdConnect, 0x00}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			req = append(append(req, socks5AddrTypeIPv4), ip4...)
+		} else {
+			req = append(append(req, socks5AddrTypeIPv6), ip.To16()...)
+		}
+	} else {
+		if len(host) > 255 {
+			return nil, errors.Errorf("host name is too long [%s]", host)
+		}
+		req = append(append(req, socks5AddrTypeDomain, byte(len(host))), host...)
+	}
+	portBytes := make([]byte, 2)
+	binary.BigEndian.PutUint16(portBytes, uint16(port))
+	req = append(req, portBytes...)
+
+	if _, err := conn.Write(req); err != nil {
+		return nil, errors.Wrap(err, "failed to write SOCKS5 connect request")
+	}
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, errors.Wrap(err, "failed to read SOCKS5 connect reply")
+	}
+	if header[1] != 0x00 {
+		return nil, errors.Errorf("SOCKS5 proxy failed to connect, reply code [%d]", header[1])
+	}
+
+	// Skip the bound address and port
+	var addrLen int
+	switch header[3] {
+	case socks5AddrTypeIPv4:
+		addrLen = net.IPv4len
+	case socks5AddrTypeIPv6:
+		addrLen = net.IPv6len
+	case socks5AddrTypeDomain:
+		lenByte := make([]byte, 1)
+		if _, err := io.ReadFull(conn, lenByte); err != nil {
+			return nil, errors.Wrap(err, "failed to read SOCKS5 bound address")
+		}
+		addrLen = int(lenByte[0])
+	default:
+		return nil, errors.Errorf("unexpected SOCKS5 address type [%d]", header[3])
+	}
+	if _, err := io.ReadFull(conn, make([]byte, addrLen+2)); err != nil {
+		return nil, errors.Wrap(err, "failed to read SOCKS5 bound address")
+	}
+
+	return conn, nil
+}
+
+func socks5Authenticate(conn net.Conn, user *url.Userinfo) error {
+	username := user.Username()
+	password, _ := user.Password()
+	if len(username) > 255 || len(password) > 255 {
+		return errors.New("SOCKS5 username or password is too long")
+	}
+
+	req := []byte{socks5PasswordVer, byte(len(username))}
+	req = append(req, username...)
+	req = append(req, byte(len(password)))
+	req = append(req, password...)
+	if _, err := conn.Write(req); err != nil {
+		return errors.Wrap(err, "failed to write SOCKS5 authentication request")
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return errors.Wrap(err, "failed to read SOCKS5 authentication reply")
+	}
+	if reply[1] != 0x00 {
+		return errors.New("SOCKS5 authentication failed")
+	}
+	return nil
+}
+
+// bufferedConn is a connection whose reads are served by the given reader
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
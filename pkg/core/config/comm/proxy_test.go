@@ -0,0 +1,206 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyDialOptions(t *testing.T) {
+	opts, err := ProxyDialOptions("")
+	assert.NoError(t, err)
+	assert.Empty(t, opts)
+
+	opts, err = ProxyDialOptions(ProxyURL(map[string]interface{}{ProxyURLOption: "socks5://proxy.example.com:1080"}))
+	assert.NoError(t, err)
+	assert.Len(t, opts, 1)
+
+	_, err = ProxyDialOptions("ftp://proxy.example.com:21")
+	assert.Error(t, err, "expecting error for unsupported scheme")
+
+	_, err = ProxyDialOptions("http://")
+	assert.Error(t, err, "expecting error for missing host")
+}
+
+func TestHTTPConnectProxy(t *testing.T) {
+	echoAddr := startEchoServer(t)
+	proxyAddr := startProxy(t, serveHTTPConnect)
+
+	dial, err := newProxyDialer("http://user:secret@" + proxyAddr)
+	require.NoError(t, err)
+	checkEcho(t, dial, echoAddr)
+
+	dial, err = newProxyDialer("http://user:wrong@" + proxyAddr)
+	require.NoError(t, err)
+	_, err = dial(echoAddr, time.Second)
+	assert.Error(t, err, "expecting error for invalid credentials")
+}
+
+func TestSOCKS5Proxy(t *testing.T) {
+	echoAddr := startEchoServer(t)
+	proxyAddr := startProxy(t, serveSOCKS5)
+
+	dial, err := newProxyDialer("socks5://user:secret@" + proxyAddr)
+	require.NoError(t, err)
+	checkEcho(t, dial, echoAddr)
+
+	dial, err = newProxyDialer("socks5://user:wrong@" + proxyAddr)
+	require.NoError(t, err)
+	_, err = dial(echoAddr, time.Second)
+	assert.Error(t, err, "expecting error for invalid credentials")
+
+	dial, err = newProxyDialer("socks5://" + proxyAddr)
+	require.NoError(t, err)
+	_, err = dial(echoAddr, time.Second)
+	assert.Error(t, err, "expecting error since the proxy requires authentication")
+}
+
+func checkEcho(t *testing.T, dial Dialer, addr string) {
+	conn, err := dial(addr, time.Second)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("hello"))
+	require.NoError(t, err)
+	reply := make([]byte, 5)
+	_, err = io.ReadFull(conn, reply)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(reply))
+}
+
+func startEchoServer(t *testing.T) string {
+	return startProxy(t, func(conn net.Conn) {
+		io.Copy(conn, conn) // nolint: errcheck
+	})
+}
+
+func startProxy(t *testing.T, serve func(conn net.Conn)) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				serve(conn)
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+func serveHTTPConnect(conn net.Conn) {
+	req, err := http.ReadRequest(bufio.NewReader(conn))
+	if err != nil {
+		return
+	}
+	if req.Method != http.MethodConnect || req.Header.Get("Proxy-Authorization") != "Basic dXNlcjpzZWNyZXQ=" {
+		io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n\r\n") // nolint: errcheck
+		return
+	}
+	target, err := net.Dial("tcp", req.Host)
+	if err != nil {
+		io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n") // nolint: errcheck
+		return
+	}
+	defer target.Close()
+	io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n") // nolint: errcheck
+	pipe(conn, target)
+}
+
+func serveSOCKS5(conn net.Conn) {
+	greeting := make([]byte, 2)
+	if _, err := io.ReadFull(conn, greeting); err != nil {
+		return
+	}
+	methods := make([]byte, greeting[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return
+	}
+	if !containsByte(methods, socks5AuthPassword) {
+		conn.Write([]byte{socks5Version, socks5AuthNoMethods}) // nolint: errcheck
+		return
+	}
+	conn.Write([]byte{socks5Version, socks5AuthPassword}) // nolint: errcheck
+
+	reader := bufio.NewReader(conn)
+	ver, _ := reader.ReadByte()
+	username := readString(reader)
+	password := readString(reader)
+	if ver != socks5PasswordVer || username != "user" || password != "secret" {
+		conn.Write([]byte{socks5PasswordVer, 0x01}) // nolint: errcheck
+		return
+	}
+	conn.Write([]byte{socks5PasswordVer, 0x00}) // nolint: errcheck
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return
+	}
+	var host string
+	switch header[3] {
+	case socks5AddrTypeIPv4:
+		ip := make([]byte, net.IPv4len)
+		io.ReadFull(reader, ip) // nolint: errcheck
+		host = net.IP(ip).String()
+	case socks5AddrTypeDomain:
+		host = readString(reader)
+	default:
+		return
+	}
+	portBytes := make([]byte, 2)
+	if _, err := io.ReadFull(reader, portBytes); err != nil {
+		return
+	}
+	port := binary.BigEndian.Uint16(portBytes)
+
+	target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+	if err != nil {
+		conn.Write([]byte{socks5Version, 0x05, 0x00, socks5AddrTypeIPv4, 0, 0, 0, 0, 0, 0}) // nolint: errcheck
+		return
+	}
+	defer target.Close()
+	conn.Write([]byte{socks5Version, 0x00, 0x00, socks5AddrTypeIPv4, 127, 0, 0, 1, 0, 0}) // nolint: errcheck
+	pipe(conn, target)
+}
+
+func pipe(conn net.Conn, target net.Conn) {
+	go io.Copy(target, conn) // nolint: errcheck
+	io.Copy(conn, target)    // nolint: errcheck
+}
+
+func readString(reader *bufio.Reader) string {
+	length, err := reader.ReadByte()
+	if err != nil {
+		return ""
+	}
+	b := make([]byte, length)
+	io.ReadFull(reader, b) // nolint: errcheck
+	return string(b)
+}
+
+func containsByte(b []byte, v byte) bool {
+	for _, e := range b {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,281 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"golang.org/x/crypto/ocsp"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+const (
+	// TLSRevocationCheckOption is the gRPC option of a peer or orderer which enables the revocation check of the
+	// endpoint's TLS certificate: none (default), soft-fail or hard-fail. In soft-fail mode the connection is
+	// only refused if the certificate is known to be revoked; in hard-fail mode it's also refused if the
+	// revocation status can't be determined (e.g. if the OCSP responders and CRL distribution points are unreachable).
+	TLSRevocationCheckOption = "tls-revocation-check"
+
+	// TLSOCSPServersOption is the gRPC option of a peer or orderer which specifies the OCSP responders queried for
+	// the revocation status of the endpoint's certificate (list or comma-separated string of URLs). The responders
+	// listed in the certificate are used if none are configured.
+	TLSOCSPServersOption = "tls-ocsp-servers"
+
+	// TLSCRLURLsOption is the gRPC option of a peer or orderer which specifies the CRLs checked for the revocation
+	// of the endpoint's certificate (list or comma-separated string of URLs). The CRL distribution points listed
+	// in the certificate are used if none are configured.
+	TLSCRLURLsOption = "tls-crl-urls"
+)
+
+// RevocationCheckMode specifies how the revocation check of an endpoint's certificate is performed
+type RevocationCheckMode string
+
+const (
+	// RevocationCheckNone disables the revocation check
+	RevocationCheckNone RevocationCheckMode = "none"
+	// RevocationCheckSoftFail refuses the connection if the certificate is revoked but allows it
+	// if the revocation status can't be determined
+	RevocationCheckSoftFail RevocationCheckMode = "soft-fail"
+	// RevocationCheckHardFail refuses the connection unless the certificate is known not to be revoked
+	RevocationCheckHardFail RevocationCheckMode = "hard-fail"
+)
+
+const defaultRevocationTimeout = 5 * time.Second
+
+// RevocationOptions contains the options of the revocation check of an endpoint's certificate
+type RevocationOptions struct {
+	Mode RevocationCheckMode
+	// OCSPServers overrides the OCSP responders listed in the certificate
+	OCSPServers []string
+	// CRLURLs overrides the CRL distribution points listed in the certificate
+	CRLURLs []string
+	// Timeout is the timeout of each OCSP or CRL request (the default is 5s)
+	Timeout time.Duration
+}
+
+func revocationOptionsFromGRPC(grpcOptions map[string]interface{}) (*RevocationOptions, error) {
+	value, ok := grpcOptions[TLSRevocationCheckOption]
+	if !ok {
+		return nil, nil
+	}
+
+	opts := &RevocationOptions{
+		Mode:        RevocationCheckMode(cast.ToString(value)),
+		OCSPServers: nonEmpty(listValues(grpcOptions[TLSOCSPServersOption])),
+		CRLURLs:     nonEmpty(listValues(grpcOptions[TLSCRLURLsOption])),
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// Validate returns an error if the mode isn't supported
+func (o *RevocationOptions) Validate() error {
+	switch o.Mode {
+	case RevocationCheckNone, RevocationCheckSoftFail, RevocationCheckHardFail:
+		return nil
+	default:
+		return errors.Errorf("unsupported %s [%s]", TLSRevocationCheckOption, o.Mode)
+	}
+}
+
+// check returns an error if the certificate presented by the endpoint is revoked or, in hard-fail mode,
+// if its revocation status can't be determined
+func (o *RevocationOptions) check(verifiedChains [][]*x509.Certificate) error {
+	if o.Mode == RevocationCheckNone || o.Mode == "" {
+		return nil
+	}
+	if len(verifiedChains) == 0 || len(verifiedChains[0]) < 2 {
+		// The certificate is a (self-signed) root CA so there's no issuer to check with
+		return nil
+	}
+
+	cert, issuer := verifiedChains[0][0], verifiedChains[0][1]
+	revoked, err := o.revoked(cert, issuer)
+	if revoked {
+		return errors.Errorf("TLS certificate [serial: %s, subject: %s] is revoked", cert.SerialNumber, cert.Subject)
+	}
+	if err == nil {
+		return nil
+	}
+	if o.Mode == RevocationCheckHardFail {
+		return errors.WithMessage(err, "failed to determine the revocation status of the TLS certificate")
+	}
+	logger.Warnf("Ignoring unknown revocation status of TLS certificate [serial: %s, subject: %s]: %s", cert.SerialNumber, cert.Subject, err)
+	return nil
+}
+
+// revoked returns true if the certificate is revoked. An error is returned if the revocation status
+// couldn't be determined by any of the OCSP responders or CRLs.
+func (o *RevocationOptions) revoked(cert, issuer *x509.Certificate) (bool, error) {
+	client := &http.Client{Timeout: o.timeout()}
+
+	ocspServers := o.OCSPServers
+	if len(ocspServers) == 0 {
+		ocspServers = cert.OCSPServer
+	}
+	crlURLs := o.CRLURLs
+	if len(crlURLs) == 0 {
+		crlURLs = cert.CRLDistributionPoints
+	}
+	if len(ocspServers) == 0 && len(crlURLs) == 0 {
+		return false, errors.New("no OCSP responders or CRL distribution points")
+	}
+
+	var lastErr error
+	for _, server := range ocspServers {
+		revoked, err := ocspRevoked(client, server, cert, issuer)
+		if err == nil {
+			return revoked, nil
+		}
+		logger.Debugf("OCSP request to [%s] failed: %s", server, err)
+		lastErr = err
+	}
+	for _, url := range crlURLs {
+		revoked, err := crlRevoked(client, url, cert, issuer)
+		if err == nil {
+			return revoked, nil
+		}
+		logger.Debugf("Checking CRL [%s] failed: %s", url, err)
+		lastErr = err
+	}
+	return false, lastErr
+}
+
+func (o *RevocationOptions) timeout() time.Duration {
+	if o.Timeout > 0 {
+		return o.Timeout
+	}
+	return defaultRevocationTimeout
+}
+
+func ocspRevoked(client *http.Client, server string, cert, issuer *x509.Certificate) (bool, error) {
+	req, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create OCSP request")
+	}
+
+	body, err := post(client, server, "application/ocsp-request", req)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := ocsp.ParseResponseForCert(body, cert, issuer)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid OCSP response")
+	}
+
+	switch resp.Status {
+	case ocsp.Good:
+		return false, nil
+	case ocsp.Revoked:
+		return true, nil
+	default:
+		return false, errors.Errorf("OCSP responder [%s] returned unknown status", server)
+	}
+}
+
+// crlCache caches the CRLs until their next update
+var crlCache = struct {
+	sync.Mutex
+	crls map[string]*pkix.CertificateList
+}{crls: make(map[string]*pkix.CertificateList)}
+
+func crlRevoked(client *http.Client, url string, cert, issuer *x509.Certificate) (bool, error) {
+	crl, err := fetchCRL(client, url, issuer)
+	if err != nil {
+		return false, err
+	}
+	for _, revoked := range crl.TBSCertList.RevokedCertificates {
+		if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func fetchCRL(client *http.Client, url string, issuer *x509.Certificate) (*pkix.CertificateList, error) {
+	now := time.Now()
+
+	crlCache.Lock()
+	crl, ok := crlCache.crls[url]
+	crlCache.Unlock()
+	if ok && !crl.HasExpired(now) && issuer.CheckCRLSignature(crl) == nil {
+		return crl, nil
+	}
+
+	body, err := get(client, url)
+	if err != nil {
+		return nil, err
+	}
+	crl, err = x509.ParseCRL(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid CRL")
+	}
+	if err := issuer.CheckCRLSignature(crl); err != nil {
+		return nil, errors.Wrap(err, "invalid CRL signature")
+	}
+	if crl.HasExpired(now) {
+		return nil, errors.Errorf("CRL [%s] has expired", url)
+	}
+
+	crlCache.Lock()
+	crlCache.crls[url] = crl
+	crlCache.Unlock()
+
+	return crl, nil
+}
+
+func get(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "request to [%s] failed", url)
+	}
+	return readBody(url, resp)
+}
+
+func post(client *http.Client, url, contentType string, body []byte) ([]byte, error) {
+	resp, err := client.Post(url, contentType, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "request to [%s] failed", url)
+	}
+	return readBody(url, resp)
+}
+
+func readBody(url string, resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("request to [%s] failed: %s", url, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read response from [%s]", url)
+	}
+	return body, nil
+}
+
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,181 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "tlsca.example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) issue(t *testing.T, serial int64) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "peer0.org1.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func (ca *testCA) crl(t *testing.T, revoked ...*x509.Certificate) []byte {
+	var revokedCerts []pkix.RevokedCertificate
+	for _, cert := range revoked {
+		revokedCerts = append(revokedCerts, pkix.RevokedCertificate{SerialNumber: cert.SerialNumber, RevocationTime: time.Now()})
+	}
+	crl, err := ca.cert.CreateCRL(rand.Reader, ca.key, revokedCerts, time.Now(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	return crl
+}
+
+func (ca *testCA) ocspHandler(t *testing.T, revoked *x509.Certificate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		req, err := ocsp.ParseRequest(body)
+		require.NoError(t, err)
+
+		template := ocsp.Response{
+			SerialNumber: req.SerialNumber,
+			Status:       ocsp.Good,
+			ThisUpdate:   time.Now(),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}
+		if req.SerialNumber.Cmp(revoked.SerialNumber) == 0 {
+			template.Status = ocsp.Revoked
+			template.RevokedAt = time.Now()
+		}
+		resp, err := ocsp.CreateResponse(ca.cert, ca.cert, template, ca.key)
+		require.NoError(t, err)
+		w.Write(resp)
+	}
+}
+
+func TestRevocationCheckCRL(t *testing.T) {
+	ca := newTestCA(t)
+	good := ca.issue(t, 100)
+	revoked := ca.issue(t, 101)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(ca.crl(t, revoked))
+	}))
+	defer server.Close()
+
+	opts := &RevocationOptions{Mode: RevocationCheckHardFail, CRLURLs: []string{server.URL + "/crl1"}}
+	assert.NoError(t, opts.check([][]*x509.Certificate{{good, ca.cert}}))
+	assert.Error(t, opts.check([][]*x509.Certificate{{revoked, ca.cert}}), "expecting error for revoked certificate")
+
+	opts.Mode = RevocationCheckSoftFail
+	assert.Error(t, opts.check([][]*x509.Certificate{{revoked, ca.cert}}), "expecting error for revoked certificate in soft-fail mode")
+
+	// A CRL which isn't signed by the issuer is rejected
+	otherCA := newTestCA(t)
+	otherServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(otherCA.crl(t))
+	}))
+	defer otherServer.Close()
+
+	opts = &RevocationOptions{Mode: RevocationCheckHardFail, CRLURLs: []string{otherServer.URL + "/crl2"}}
+	assert.Error(t, opts.check([][]*x509.Certificate{{good, ca.cert}}), "expecting error for CRL with invalid signature")
+}
+
+func TestRevocationCheckOCSP(t *testing.T) {
+	ca := newTestCA(t)
+	good := ca.issue(t, 200)
+	revoked := ca.issue(t, 201)
+
+	server := httptest.NewServer(ca.ocspHandler(t, revoked))
+	defer server.Close()
+
+	opts := &RevocationOptions{Mode: RevocationCheckHardFail, OCSPServers: []string{server.URL}}
+	assert.NoError(t, opts.check([][]*x509.Certificate{{good, ca.cert}}))
+	assert.Error(t, opts.check([][]*x509.Certificate{{revoked, ca.cert}}), "expecting error for revoked certificate")
+}
+
+func TestRevocationCheckUnavailable(t *testing.T) {
+	ca := newTestCA(t)
+	cert := ca.issue(t, 300)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	opts := &RevocationOptions{Mode: RevocationCheckSoftFail, OCSPServers: []string{server.URL}, CRLURLs: []string{server.URL + "/crl"}}
+	assert.NoError(t, opts.check([][]*x509.Certificate{{cert, ca.cert}}), "expecting soft-fail to allow unknown status")
+
+	opts.Mode = RevocationCheckHardFail
+	assert.Error(t, opts.check([][]*x509.Certificate{{cert, ca.cert}}), "expecting hard-fail to refuse unknown status")
+
+	opts = &RevocationOptions{Mode: RevocationCheckHardFail}
+	assert.Error(t, opts.check([][]*x509.Certificate{{cert, ca.cert}}), "expecting hard-fail to refuse certificate without revocation sources")
+
+	opts.Mode = RevocationCheckNone
+	assert.NoError(t, opts.check([][]*x509.Certificate{{cert, ca.cert}}))
+}
+
+func TestRevocationOptionsFromGRPC(t *testing.T) {
+	opts, err := TLSOptionsFromGRPC(map[string]interface{}{
+		TLSRevocationCheckOption: "hard-fail",
+		TLSOCSPServersOption:     "http://ocsp.example.com, http://ocsp2.example.com",
+		TLSCRLURLsOption:         []interface{}{"http://crl.example.com/ca.crl"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, opts.Revocation)
+	assert.Equal(t, RevocationCheckHardFail, opts.Revocation.Mode)
+	assert.Equal(t, []string{"http://ocsp.example.com", "http://ocsp2.example.com"}, opts.Revocation.OCSPServers)
+	assert.Equal(t, []string{"http://crl.example.com/ca.crl"}, opts.Revocation.CRLURLs)
+
+	_, err = TLSOptionsFromGRPC(map[string]interface{}{TLSRevocationCheckOption: "sometimes"})
+	assert.Error(t, err, "expecting error for unsupported mode")
+}
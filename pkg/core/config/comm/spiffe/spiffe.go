@@ -0,0 +1,297 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package spiffe sources the client's mutual TLS certificate from a SPIFFE workload API (e.g. the SPIRE
+// agent or an SDS server of a service mesh) instead of the static client certificate in the config. The
+// X.509 SVID (SPIFFE Verifiable Identity Document) is refreshed in the background before it expires and the
+// connections which are established after a rotation use the new certificate.
+//
+// The source overrides the TLSClientCerts function of the endpoint config so the certificate of the
+// client.tlsCerts.client section of the config is ignored. The root CAs of the peers and orderers are still
+// taken from the config.
+//
+//  Basic Flow:
+//  1) Implement the Fetcher interface (e.g. with an adapter for a workload API client)
+//  2) Create a source with the fetcher and start it
+//  3) Create the SDK with fabsdk.WithEndpointConfig(source)
+//  4) Stop the source when the SDK is closed
+package spiffe
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+const (
+	spiffeScheme         = "spiffe"
+	defaultRetryInterval = 5 * time.Second
+	defaultFetchTimeout  = 10 * time.Second
+)
+
+// X509SVID is an X.509 SPIFFE Verifiable Identity Document
+type X509SVID struct {
+	// Certificates is the certificate chain. The first certificate is the leaf certificate
+	// whose URI SAN contains the SPIFFE ID.
+	Certificates []*x509.Certificate
+	// PrivateKey is the private key of the leaf certificate
+	PrivateKey crypto.Signer
+}
+
+// Fetcher fetches the current X.509 SVID of the workload from the workload API
+type Fetcher interface {
+	FetchX509SVID(ctx context.Context) (*X509SVID, error)
+}
+
+// FetcherFunc is a function which implements the Fetcher interface
+type FetcherFunc func(ctx context.Context) (*X509SVID, error)
+
+// FetchX509SVID invokes the function
+func (f FetcherFunc) FetchX509SVID(ctx context.Context) (*X509SVID, error) {
+	return f(ctx)
+}
+
+// Source provides the client TLS certificate from the workload API. It implements the TLSClientCerts
+// function of the endpoint config.
+type Source struct {
+	fetcher       Fetcher
+	trustDomain   string
+	retryInterval time.Duration
+	fetchTimeout  time.Duration
+	onRotate      func(id string, cert *x509.Certificate)
+
+	mutex   sync.RWMutex
+	cert    tls.Certificate
+	id      string
+	expiry  time.Time
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// Option is a functional option for the source
+type Option func(s *Source)
+
+// WithTrustDomain restricts the SVIDs to the given trust domain (e.g. example.org)
+func WithTrustDomain(trustDomain string) Option {
+	return func(s *Source) {
+		s.trustDomain = trustDomain
+	}
+}
+
+// WithRetryInterval sets the interval at which the SVID is fetched after a failure (default 5s)
+func WithRetryInterval(interval time.Duration) Option {
+	return func(s *Source) {
+		s.retryInterval = interval
+	}
+}
+
+// WithFetchTimeout sets the timeout for fetching the SVID (default 10s)
+func WithFetchTimeout(timeout time.Duration) Option {
+	return func(s *Source) {
+		s.fetchTimeout = timeout
+	}
+}
+
+// WithRotationHandler sets a function which is invoked with the SPIFFE ID and the leaf certificate
+// when the SVID has been rotated
+func WithRotationHandler(onRotate func(id string, cert *x509.Certificate)) Option {
+	return func(s *Source) {
+		s.onRotate = onRotate
+	}
+}
+
+// New returns a source which fetches the SVID with the given fetcher. The initial SVID is fetched before
+// returning so that an error is returned if the workload API isn't available.
+func New(fetcher Fetcher, opts ...Option) (*Source, error) {
+	if fetcher == nil {
+		return nil, errors.New("fetcher is required")
+	}
+
+	s := &Source{
+		fetcher:       fetcher,
+		retryInterval: defaultRetryInterval,
+		fetchTimeout:  defaultFetchTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.retryInterval <= 0 {
+		return nil, errors.New("retry interval must be greater than zero")
+	}
+
+	if err := s.refresh(); err != nil {
+		return nil, errors.WithMessage(err, "failed to fetch initial SVID")
+	}
+
+	return s, nil
+}
+
+// TLSClientCerts returns the certificate of the current SVID
+func (s *Source) TLSClientCerts() []tls.Certificate {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return []tls.Certificate{s.cert}
+}
+
+// ID returns the SPIFFE ID of the current SVID
+func (s *Source) ID() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.id
+}
+
+// Start refreshes the SVID in the background. The SVID is refreshed when half of its remaining
+// lifetime has elapsed.
+func (s *Source) Start() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.done != nil {
+		return errors.New("source already started")
+	}
+
+	s.done = make(chan struct{})
+	s.stopped = make(chan struct{})
+
+	go s.run()
+
+	return nil
+}
+
+// Stop stops refreshing the SVID. The current SVID continues to be provided.
+func (s *Source) Stop() {
+	s.mutex.Lock()
+	done, stopped := s.done, s.stopped
+	if done != nil {
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+	}
+	s.mutex.Unlock()
+
+	if stopped != nil {
+		<-stopped
+	}
+}
+
+func (s *Source) run() {
+	defer close(s.stopped)
+
+	timer := time.NewTimer(s.refreshDelay())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-timer.C:
+			delay := s.retryInterval
+			if err := s.refresh(); err != nil {
+				logger.Warnf("Error refreshing SVID (current SVID expires at %s): %s", s.expiryTime(), err)
+			} else {
+				delay = s.refreshDelay()
+			}
+			timer.Reset(delay)
+		}
+	}
+}
+
+// refreshDelay returns half of the remaining lifetime of the SVID (but at least the retry interval)
+func (s *Source) refreshDelay() time.Duration {
+	delay := time.Until(s.expiryTime()) / 2
+	if delay < s.retryInterval {
+		return s.retryInterval
+	}
+	return delay
+}
+
+func (s *Source) expiryTime() time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.expiry
+}
+
+func (s *Source) refresh() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.fetchTimeout)
+	defer cancel()
+
+	svid, err := s.fetcher.FetchX509SVID(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to fetch SVID")
+	}
+
+	id, err := s.validate(svid)
+	if err != nil {
+		return err
+	}
+
+	cert := tls.Certificate{PrivateKey: svid.PrivateKey, Leaf: svid.Certificates[0]}
+	for _, c := range svid.Certificates {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+
+	s.mutex.Lock()
+	rotated := s.id != "" && !svid.Certificates[0].Equal(s.cert.Leaf)
+	s.cert = cert
+	s.id = id
+	s.expiry = svid.Certificates[0].NotAfter
+	s.mutex.Unlock()
+
+	logger.Debugf("Fetched SVID [%s] which expires at %s", id, svid.Certificates[0].NotAfter)
+
+	if rotated && s.onRotate != nil {
+		s.onRotate(id, svid.Certificates[0])
+	}
+	return nil
+}
+
+// validate checks the SVID and returns its SPIFFE ID
+func (s *Source) validate(svid *X509SVID) (string, error) {
+	if svid == nil || len(svid.Certificates) == 0 {
+		return "", errors.New("SVID has no certificates")
+	}
+	if svid.PrivateKey == nil {
+		return "", errors.New("SVID has no private key")
+	}
+
+	leaf := svid.Certificates[0]
+	if time.Now().After(leaf.NotAfter) {
+		return "", errors.Errorf("SVID expired at %s", leaf.NotAfter)
+	}
+
+	id, err := spiffeID(leaf)
+	if err != nil {
+		return "", err
+	}
+	if s.trustDomain != "" && id.Host != s.trustDomain {
+		return "", errors.Errorf("SVID [%s] doesn't belong to trust domain [%s]", id, s.trustDomain)
+	}
+	return id.String(), nil
+}
+
+// spiffeID returns the SPIFFE ID in the URI SAN of the certificate (which must contain exactly one URI)
+func spiffeID(cert *x509.Certificate) (*url.URL, error) {
+	if len(cert.URIs) != 1 {
+		return nil, errors.Errorf("SVID must have exactly one URI SAN but has %d", len(cert.URIs))
+	}
+	id := cert.URIs[0]
+	if id.Scheme != spiffeScheme || id.Host == "" {
+		return nil, errors.Errorf("invalid SPIFFE ID [%s]", id)
+	}
+	return id, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package spiffe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockFetcher struct {
+	mutex sync.Mutex
+	svid  *X509SVID
+	err   error
+	calls int
+}
+
+func (f *mockFetcher) FetchX509SVID(ctx context.Context) (*X509SVID, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.calls++
+	return f.svid, f.err
+}
+
+func (f *mockFetcher) set(svid *X509SVID, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.svid = svid
+	f.err = err
+}
+
+func newSVID(t *testing.T, id string, ttl time.Duration) *X509SVID {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{Organization: []string{"SPIRE"}},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(ttl),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if id != "" {
+		u, err := url.Parse(id)
+		require.NoError(t, err)
+		template.URIs = []*url.URL{u}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &X509SVID{Certificates: []*x509.Certificate{cert}, PrivateKey: key}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil)
+	assert.Error(t, err, "expecting error for missing fetcher")
+
+	_, err = New(&mockFetcher{err: errors.New("workload API unavailable")})
+	assert.Error(t, err, "expecting error if the initial SVID can't be fetched")
+
+	_, err = New(&mockFetcher{svid: newSVID(t, "", time.Hour)})
+	assert.Error(t, err, "expecting error for SVID without SPIFFE ID")
+
+	_, err = New(&mockFetcher{svid: newSVID(t, "https://example.org/workload", time.Hour)})
+	assert.Error(t, err, "expecting error for invalid SPIFFE ID")
+
+	_, err = New(&mockFetcher{svid: newSVID(t, "spiffe://example.org/workload", -time.Second)})
+	assert.Error(t, err, "expecting error for expired SVID")
+
+	_, err = New(&mockFetcher{svid: newSVID(t, "spiffe://other.org/workload", time.Hour)}, WithTrustDomain("example.org"))
+	assert.Error(t, err, "expecting error for SVID of other trust domain")
+
+	svid := newSVID(t, "spiffe://example.org/workload", time.Hour)
+	s, err := New(&mockFetcher{svid: svid}, WithTrustDomain("example.org"))
+	require.NoError(t, err)
+	assert.Equal(t, "spiffe://example.org/workload", s.ID())
+
+	certs := s.TLSClientCerts()
+	require.Len(t, certs, 1)
+	assert.Equal(t, [][]byte{svid.Certificates[0].Raw}, certs[0].Certificate)
+	assert.Equal(t, svid.PrivateKey, certs[0].PrivateKey)
+}
+
+func TestRotation(t *testing.T) {
+	// The SVID is refreshed at half of its lifetime
+	fetcher := &mockFetcher{svid: newSVID(t, "spiffe://example.org/workload", 2*time.Second)}
+
+	rotated := make(chan string, 1)
+	s, err := New(fetcher, WithRetryInterval(20*time.Millisecond), WithRotationHandler(func(id string, cert *x509.Certificate) {
+		rotated <- id
+	}))
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	defer s.Stop()
+	assert.Error(t, s.Start(), "expecting error since source is already started")
+
+	// The current SVID is kept while the workload API is unavailable
+	initial := s.TLSClientCerts()[0]
+	fetcher.set(nil, errors.New("workload API unavailable"))
+	time.Sleep(1200 * time.Millisecond)
+	assert.Equal(t, initial.Certificate, s.TLSClientCerts()[0].Certificate)
+
+	next := newSVID(t, "spiffe://example.org/workload2", time.Hour)
+	fetcher.set(next, nil)
+
+	select {
+	case id := <-rotated:
+		assert.Equal(t, "spiffe://example.org/workload2", id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for SVID to be rotated")
+	}
+	assert.Equal(t, [][]byte{next.Certificates[0].Raw}, s.TLSClientCerts()[0].Certificate)
+	assert.Equal(t, "spiffe://example.org/workload2", s.ID())
+}
@@ -0,0 +1,325 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+const (
+	// TLSMinVersionOption is the gRPC option of a peer or orderer which specifies the minimum TLS version
+	// of connections to the endpoint (1.0, 1.1, 1.2 or, if built with Go 1.12 or later, 1.3). The default is TLS 1.2.
+	TLSMinVersionOption = "tls-min-version"
+
+	// TLSMaxVersionOption is the gRPC option of a peer or orderer which specifies the maximum TLS version
+	// of connections to the endpoint (1.0, 1.1, 1.2 or, if built with Go 1.12 or later, 1.3). The default is the highest
+	// supported version.
+	TLSMaxVersionOption = "tls-max-version"
+
+	// TLSCipherSuitesOption is the gRPC option of a peer or orderer which specifies the cipher suites allowed for
+	// connections to the endpoint, either as a list or as a comma-separated string of names
+	// (e.g. TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256). The cipher suites only apply to TLS 1.2 and below
+	// since the TLS 1.3 cipher suites aren't configurable.
+	TLSCipherSuitesOption = "tls-cipher-suites"
+
+	// TLSPinnedCertHashesOption is the gRPC option of a peer or orderer which pins the certificates that the endpoint
+	// may present, either as a list or as a comma-separated string of hex encoded SHA-256 hashes of the (DER encoded)
+	// certificates. Colons between the bytes are allowed (e.g. as printed by openssl x509 -fingerprint -sha256).
+	// The connection fails unless the endpoint's certificate matches one of the hashes, in addition to the CA validation.
+	TLSPinnedCertHashesOption = "tls-pinned-cert-hashes"
+
+	// TLSVerifyPeerCertificateOption is the gRPC option of a peer or orderer which sets a VerifyPeerCertificate
+	// callback for the endpoint. It can only be set programmatically (e.g. by overriding the endpoint config).
+	TLSVerifyPeerCertificateOption = "tls-verify-peer-certificate"
+)
+
+// tlsVersions are the supported TLS versions by name. TLS 1.3 is added when built with Go 1.12 or later (see tlsopts_go112.go).
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1":   tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+}
+
+// cipherSuites are the cipher suites implemented by crypto/tls by name. The names ending with _SHA256 of the
+// ChaCha20-Poly1305 suites are the names used by later versions of crypto/tls.
+var cipherSuites = map[string]uint16{
+	"TLS_RSA_WITH_RC4_128_SHA":                      tls.TLS_RSA_WITH_RC4_128_SHA,
+	"TLS_RSA_WITH_3DES_EDE_CBC_SHA":                 tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA,
+	"TLS_RSA_WITH_AES_128_CBC_SHA":                  tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	"TLS_RSA_WITH_AES_256_CBC_SHA":                  tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+	"TLS_RSA_WITH_AES_128_CBC_SHA256":               tls.TLS_RSA_WITH_AES_128_CBC_SHA256,
+	"TLS_RSA_WITH_AES_128_GCM_SHA256":               tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_RSA_WITH_AES_256_GCM_SHA384":               tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_ECDSA_WITH_RC4_128_SHA":              tls.TLS_ECDHE_ECDSA_WITH_RC4_128_SHA,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA":          tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA":          tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_RC4_128_SHA":                tls.TLS_ECDHE_RSA_WITH_RC4_128_SHA,
+	"TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA":           tls.TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA":            tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA":            tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256":       tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256,
+	"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256":         tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256,
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":         tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256":       tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":         tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384":       tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305":          tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305":        tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256":   tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256": tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+}
+
+// VerifyPeerCertificate verifies the certificate presented by an endpoint. It is called after the certificate
+// chain has been validated against the TLS CA certificates. The connection fails if an error is returned.
+type VerifyPeerCertificate func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error
+
+// TLSOptions contains the TLS versions and cipher suites allowed for connections to an endpoint, as well as
+// the additional verifications of the endpoint's certificate. Zero values leave the defaults in place.
+type TLSOptions struct {
+	MinVersion   uint16
+	MaxVersion   uint16
+	CipherSuites []uint16
+	// PinnedCertHashes are the SHA-256 hashes of the certificates which the endpoint may present
+	PinnedCertHashes [][]byte
+	// Revocation enables the revocation check of the endpoint's certificate
+	Revocation *RevocationOptions
+	// VerifyPeerCertificate is called after the CA validation, the pinning check and the revocation check
+	VerifyPeerCertificate VerifyPeerCertificate
+}
+
+// TLSOptionsFromGRPC returns the TLS options from the given gRPC options or nil if none are configured
+func TLSOptionsFromGRPC(grpcOptions map[string]interface{}) (*TLSOptions, error) {
+	var opts TLSOptions
+	configured := false
+
+	if value, ok := grpcOptions[TLSMinVersionOption]; ok {
+		version, err := ParseTLSVersion(cast.ToString(value))
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid "+TLSMinVersionOption)
+		}
+		opts.MinVersion = version
+		configured = true
+	}
+
+	if value, ok := grpcOptions[TLSMaxVersionOption]; ok {
+		version, err := ParseTLSVersion(cast.ToString(value))
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid "+TLSMaxVersionOption)
+		}
+		opts.MaxVersion = version
+		configured = true
+	}
+
+	if value, ok := grpcOptions[TLSCipherSuitesOption]; ok {
+		suites, err := ParseCipherSuites(listValues(value))
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid "+TLSCipherSuitesOption)
+		}
+		opts.CipherSuites = suites
+		configured = true
+	}
+
+	if value, ok := grpcOptions[TLSPinnedCertHashesOption]; ok {
+		hashes, err := ParseCertHashes(listValues(value))
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid "+TLSPinnedCertHashesOption)
+		}
+		opts.PinnedCertHashes = hashes
+		configured = true
+	}
+
+	revocation, err := revocationOptionsFromGRPC(grpcOptions)
+	if err != nil {
+		return nil, err
+	}
+	if revocation != nil {
+		opts.Revocation = revocation
+		configured = true
+	}
+
+	if value, ok := grpcOptions[TLSVerifyPeerCertificateOption]; ok {
+		verify, err := verifyPeerCertificateFunc(value)
+		if err != nil {
+			return nil, err
+		}
+		opts.VerifyPeerCertificate = verify
+		configured = true
+	}
+
+	if !configured {
+		return nil, nil
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &opts, nil
+}
+
+// Validate returns an error if the minimum version is greater than the maximum version, if a pinned hash
+// isn't a SHA-256 hash or if the revocation check mode isn't supported
+func (o *TLSOptions) Validate() error {
+	if o.MinVersion != 0 && o.MaxVersion != 0 && o.MinVersion > o.MaxVersion {
+		return errors.Errorf("TLS min version [%s] is greater than max version [%s]", tlsVersionName(o.MinVersion), tlsVersionName(o.MaxVersion))
+	}
+	for _, hash := range o.PinnedCertHashes {
+		if len(hash) != sha256.Size {
+			return errors.Errorf("pinned certificate hash [%x] isn't a SHA-256 hash", hash)
+		}
+	}
+	if o.Revocation != nil {
+		return o.Revocation.Validate()
+	}
+	return nil
+}
+
+// Apply sets the versions and cipher suites of the options on the given TLS config. The pinning check, the
+// revocation check and the VerifyPeerCertificate callback of the options are chained after the config's existing
+// VerifyPeerCertificate callback (if any). Nothing is changed if the options are nil.
+func (o *TLSOptions) Apply(config *tls.Config) {
+	if o == nil {
+		return
+	}
+	if o.MinVersion != 0 {
+		config.MinVersion = o.MinVersion
+	}
+	if o.MaxVersion != 0 {
+		config.MaxVersion = o.MaxVersion
+	}
+	if len(o.CipherSuites) > 0 {
+		config.CipherSuites = o.CipherSuites
+	}
+	if len(o.PinnedCertHashes) > 0 || o.Revocation != nil || o.VerifyPeerCertificate != nil {
+		config.VerifyPeerCertificate = o.verifier(config.VerifyPeerCertificate)
+	}
+}
+
+func (o *TLSOptions) verifier(next VerifyPeerCertificate) VerifyPeerCertificate {
+	pinnedHashes := o.PinnedCertHashes
+	revocation := o.Revocation
+	verify := o.VerifyPeerCertificate
+
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if next != nil {
+			if err := next(rawCerts, verifiedChains); err != nil {
+				return err
+			}
+		}
+		if len(pinnedHashes) > 0 {
+			if err := verifyPinnedCert(rawCerts, pinnedHashes); err != nil {
+				return err
+			}
+		}
+		if revocation != nil {
+			if err := revocation.check(verifiedChains); err != nil {
+				return err
+			}
+		}
+		if verify != nil {
+			return verify(rawCerts, verifiedChains)
+		}
+		return nil
+	}
+}
+
+func verifyPinnedCert(rawCerts [][]byte, pinnedHashes [][]byte) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no certificate presented by the endpoint")
+	}
+	hash := sha256.Sum256(rawCerts[0])
+	for _, pinned := range pinnedHashes {
+		if bytes.Equal(hash[:], pinned) {
+			return nil
+		}
+	}
+	return errors.Errorf("certificate of the endpoint [%x] doesn't match a pinned certificate hash", hash)
+}
+
+// ParseTLSVersion returns the TLS version with the given name (1.0, 1.1, 1.2 or, if built with Go 1.12
+// or later, 1.3, optionally prefixed with TLS or TLSv)
+func ParseTLSVersion(name string) (uint16, error) {
+	v := strings.ToLower(strings.TrimSpace(name))
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "tls"), "v")
+	version, ok := tlsVersions[v]
+	if !ok {
+		return 0, errors.Errorf("unsupported TLS version [%s]", name)
+	}
+	return version, nil
+}
+
+// ParseCipherSuites returns the IDs of the cipher suites with the given names
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	var suites []uint16
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		id, ok := cipherSuites[name]
+		if !ok {
+			return nil, errors.Errorf("unsupported cipher suite [%s]", name)
+		}
+		suites = append(suites, id)
+	}
+	return suites, nil
+}
+
+// ParseCertHashes decodes the given hex encoded SHA-256 certificate hashes (colons between the bytes are allowed)
+func ParseCertHashes(values []string) ([][]byte, error) {
+	var hashes [][]byte
+	for _, value := range values {
+		value = strings.Replace(strings.TrimSpace(value), ":", "", -1)
+		if value == "" {
+			continue
+		}
+		hash, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid certificate hash [%s]", value)
+		}
+		if len(hash) != sha256.Size {
+			return nil, errors.Errorf("certificate hash [%s] isn't a SHA-256 hash", value)
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
+
+func verifyPeerCertificateFunc(value interface{}) (VerifyPeerCertificate, error) {
+	switch verify := value.(type) {
+	case VerifyPeerCertificate:
+		return verify, nil
+	case func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error:
+		return verify, nil
+	default:
+		return nil, errors.Errorf("invalid %s: unexpected type %T", TLSVerifyPeerCertificateOption, value)
+	}
+}
+
+func listValues(value interface{}) []string {
+	if s, ok := value.(string); ok {
+		return strings.Split(s, ",")
+	}
+	return cast.ToStringSlice(value)
+}
+
+func tlsVersionName(version uint16) string {
+	for name, v := range tlsVersions {
+		if v == version && name != "1" {
+			return name
+		}
+	}
+	return cast.ToString(version)
+}
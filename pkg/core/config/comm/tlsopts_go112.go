@@ -0,0 +1,15 @@
+// +build go1.12
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import "crypto/tls"
+
+func init() {
+	tlsVersions["1.3"] = tls.VersionTLS13
+}
@@ -0,0 +1,30 @@
+// +build go1.12
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSOptionsFromGRPCTLS13(t *testing.T) {
+	opts, err := TLSOptionsFromGRPC(map[string]interface{}{
+		TLSMinVersionOption: 1.2,
+		TLSMaxVersionOption: "TLSv1.3",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), opts.MinVersion)
+	assert.Equal(t, uint16(tls.VersionTLS13), opts.MaxVersion)
+
+	_, err = TLSOptionsFromGRPC(map[string]interface{}{TLSMinVersionOption: "1.3", TLSMaxVersionOption: "1.2"})
+	assert.Error(t, err, "expecting error since min version is greater than max version")
+}
@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSOptionsFromGRPC(t *testing.T) {
+	opts, err := TLSOptionsFromGRPC(map[string]interface{}{"fail-fast": true})
+	require.NoError(t, err)
+	assert.Nil(t, opts, "expecting nil options if none are configured")
+
+	opts, err = TLSOptionsFromGRPC(map[string]interface{}{
+		TLSMinVersionOption:   1.1,
+		TLSMaxVersionOption:   "TLSv1.2",
+		TLSCipherSuitesOption: []interface{}{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, opts)
+	assert.Equal(t, uint16(tls.VersionTLS11), opts.MinVersion)
+	assert.Equal(t, uint16(tls.VersionTLS12), opts.MaxVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, opts.CipherSuites)
+
+	opts, err = TLSOptionsFromGRPC(map[string]interface{}{
+		TLSCipherSuitesOption: "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305}, opts.CipherSuites)
+}
+
+func TestTLSOptionsFromGRPCErrors(t *testing.T) {
+	_, err := TLSOptionsFromGRPC(map[string]interface{}{TLSMinVersionOption: "2.0"})
+	assert.Error(t, err, "expecting error for unsupported version")
+
+	_, err = TLSOptionsFromGRPC(map[string]interface{}{TLSMaxVersionOption: "SSLv3"})
+	assert.Error(t, err, "expecting error for unsupported version")
+
+	_, err = TLSOptionsFromGRPC(map[string]interface{}{TLSCipherSuitesOption: []string{"TLS_UNKNOWN"}})
+	assert.Error(t, err, "expecting error for unsupported cipher suite")
+
+	_, err = TLSOptionsFromGRPC(map[string]interface{}{TLSMinVersionOption: "1.2", TLSMaxVersionOption: "1.1"})
+	assert.Error(t, err, "expecting error since min version is greater than max version")
+}
+
+func TestTLSOptionsApply(t *testing.T) {
+	config := &tls.Config{ServerName: "peer0.org1.example.com"}
+
+	var opts *TLSOptions
+	opts.Apply(config)
+	assert.Equal(t, &tls.Config{ServerName: "peer0.org1.example.com"}, config, "expecting nil options to leave config unchanged")
+
+	opts = &TLSOptions{MinVersion: tls.VersionTLS12}
+	opts.Apply(config)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	assert.Equal(t, uint16(0), config.MaxVersion)
+	assert.Nil(t, config.CipherSuites)
+}
+
+func TestTLSOptionsPinnedCertHashes(t *testing.T) {
+	cert := []byte("certificate")
+	hash := sha256.Sum256(cert)
+	fingerprint := strings.ToUpper(hex.EncodeToString(hash[:]))
+
+	opts, err := TLSOptionsFromGRPC(map[string]interface{}{
+		TLSPinnedCertHashesOption: []interface{}{colonSeparated(fingerprint), hex.EncodeToString(make([]byte, sha256.Size))},
+	})
+	require.NoError(t, err)
+	require.Len(t, opts.PinnedCertHashes, 2)
+	assert.Equal(t, hash[:], opts.PinnedCertHashes[0])
+
+	baseCalled := false
+	config := &tls.Config{
+		VerifyPeerCertificate: func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+			baseCalled = true
+			return nil
+		},
+	}
+	opts.Apply(config)
+
+	assert.NoError(t, config.VerifyPeerCertificate([][]byte{cert}, nil))
+	assert.True(t, baseCalled, "expecting existing callback to be called")
+
+	assert.Error(t, config.VerifyPeerCertificate([][]byte{[]byte("other certificate")}, nil), "expecting error for certificate which isn't pinned")
+	assert.Error(t, config.VerifyPeerCertificate(nil, nil), "expecting error if no certificate is presented")
+
+	_, err = TLSOptionsFromGRPC(map[string]interface{}{TLSPinnedCertHashesOption: "not hex"})
+	assert.Error(t, err)
+
+	_, err = TLSOptionsFromGRPC(map[string]interface{}{TLSPinnedCertHashesOption: "abcd"})
+	assert.Error(t, err, "expecting error for hash which isn't a SHA-256 hash")
+}
+
+func TestTLSOptionsVerifyPeerCertificate(t *testing.T) {
+	var verified [][]byte
+	verify := func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		verified = rawCerts
+		if string(rawCerts[0]) == "revoked" {
+			return errors.New("certificate is revoked")
+		}
+		return nil
+	}
+
+	opts, err := TLSOptionsFromGRPC(map[string]interface{}{TLSVerifyPeerCertificateOption: verify})
+	require.NoError(t, err)
+
+	config := &tls.Config{}
+	opts.Apply(config)
+
+	assert.NoError(t, config.VerifyPeerCertificate([][]byte{[]byte("valid")}, nil))
+	assert.Equal(t, [][]byte{[]byte("valid")}, verified)
+	assert.Error(t, config.VerifyPeerCertificate([][]byte{[]byte("revoked")}, nil))
+
+	_, err = TLSOptionsFromGRPC(map[string]interface{}{TLSVerifyPeerCertificateOption: "verify"})
+	assert.Error(t, err, "expecting error for value which isn't a function")
+}
+
+func colonSeparated(s string) string {
+	var parts []string
+	for i := 0; i < len(s); i += 2 {
+		parts = append(parts, s[i:i+2])
+	}
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,257 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1" // nolint: gas
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// webSocketGUID is the GUID used to compute the Sec-WebSocket-Accept header (RFC 6455)
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xa
+
+	wsFinBit  = 0x80
+	wsMaskBit = 0x80
+)
+
+// webSocketConnect tunnels the connection to the given address over a WebSocket established with the gateway.
+// The gRPC (HTTP/2) stream is carried in binary messages. The target address is passed to the gateway in the
+// 'target' query parameter unless the gateway URL already contains the parameter.
+func webSocketConnect(conn net.Conn, addr string, gatewayURL *url.URL) (net.Conn, error) {
+	if gatewayURL.Scheme == "wss" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: gatewayURL.Hostname()})
+		if err := tlsConn.Handshake(); err != nil {
+			return nil, errors.Wrap(err, "TLS handshake with WebSocket gateway failed")
+		}
+		conn = tlsConn
+	}
+
+	u := *gatewayURL
+	query := u.Query()
+	if query.Get("target") == "" {
+		query.Set("target", addr)
+	}
+	u.RawQuery = query.Encode()
+	u.User = nil
+	u.Scheme = "http"
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return nil, errors.Wrap(err, "failed to generate WebSocket key")
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	req := &http.Request{
+		Method: http.MethodGet,
+		URL:    &u,
+		Host:   gatewayURL.Host,
+		Header: make(http.Header),
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if user := gatewayURL.User; user != nil {
+		password, _ := user.Password()
+		req.SetBasicAuth(user.Username(), password)
+	}
+
+	if err := req.Write(conn); err != nil {
+		return nil, errors.Wrap(err, "failed to write WebSocket upgrade request")
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read WebSocket upgrade response")
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, errors.Errorf("WebSocket gateway refused upgrade request: %s", resp.Status)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != webSocketAccept(key) {
+		return nil, errors.New("invalid Sec-WebSocket-Accept header in WebSocket upgrade response")
+	}
+
+	return &webSocketConn{Conn: conn, reader: reader}, nil
+}
+
+func webSocketAccept(key string) string {
+	h := sha1.New()                      // nolint: gas
+	h.Write([]byte(key + webSocketGUID)) // nolint: errcheck
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// webSocketConn is a client-side WebSocket connection which reads and writes binary messages
+type webSocketConn struct {
+	net.Conn
+	reader    *bufio.Reader
+	writeLock sync.Mutex
+	remaining uint64
+	masked    bool
+	mask      [4]byte
+	maskPos   int
+}
+
+// Read reads the payload of the data frames, handling control frames transparently
+func (c *webSocketConn) Read(b []byte) (int, error) {
+	for c.remaining == 0 {
+		if err := c.nextFrame(); err != nil {
+			return 0, err
+		}
+	}
+
+	if uint64(len(b)) > c.remaining {
+		b = b[:c.remaining]
+	}
+	n, err := c.reader.Read(b)
+	if c.masked {
+		for i := 0; i < n; i++ {
+			b[i] ^= c.mask[c.maskPos%4]
+			c.maskPos++
+		}
+	}
+	c.remaining -= uint64(n)
+	return n, err
+}
+
+func (c *webSocketConn) nextFrame() error {
+	for {
+		opcode, length, err := c.readHeader()
+		if err != nil {
+			return err
+		}
+
+		switch opcode {
+		case wsOpContinuation, wsOpText, wsOpBinary:
+			c.remaining = length
+			return nil
+		case wsOpClose:
+			return io.EOF
+		case wsOpPing, wsOpPong:
+			payload := make([]byte, length)
+			if _, err := io.ReadFull(c.reader, payload); err != nil {
+				return err
+			}
+			if c.masked {
+				for i := range payload {
+					payload[i] ^= c.mask[i%4]
+				}
+			}
+			if opcode == wsOpPing {
+				if err := c.writeFrame(wsOpPong, payload); err != nil {
+					return err
+				}
+			}
+		default:
+			return errors.Errorf("unexpected WebSocket opcode [%d]", opcode)
+		}
+	}
+}
+
+func (c *webSocketConn) readHeader() (byte, uint64, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return 0, 0, err
+	}
+
+	opcode := header[0] & 0x0f
+	c.masked = header[1]&wsMaskBit != 0
+	length := uint64(header[1] & 0x7f)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(c.reader, ext); err != nil {
+			return 0, 0, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(c.reader, ext); err != nil {
+			return 0, 0, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	c.maskPos = 0
+	if c.masked {
+		if _, err := io.ReadFull(c.reader, c.mask[:]); err != nil {
+			return 0, 0, err
+		}
+	}
+	return opcode, length, nil
+}
+
+// Write writes the given bytes in a binary frame
+func (c *webSocketConn) Write(b []byte) (int, error) {
+	if err := c.writeFrame(wsOpBinary, b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close sends a close frame and closes the underlying connection
+func (c *webSocketConn) Close() error {
+	c.writeFrame(wsOpClose, nil) // nolint: errcheck
+	return c.Conn.Close()
+}
+
+// writeFrame writes a single masked frame (frames sent by a client must be masked)
+func (c *webSocketConn) writeFrame(opcode byte, payload []byte) error {
+	frame := []byte{wsFinBit | opcode}
+
+	length := len(payload)
+	switch {
+	case length < 126:
+		frame = append(frame, wsMaskBit|byte(length))
+	case length <= 0xffff:
+		frame = append(frame, wsMaskBit|126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(length))
+	default:
+		frame = append(frame, wsMaskBit|127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[2:], uint64(length))
+	}
+
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return errors.Wrap(err, "failed to generate WebSocket mask")
+	}
+	frame = append(frame, mask[:]...)
+
+	offset := len(frame)
+	frame = append(frame, payload...)
+	for i := range payload {
+		frame[offset+i] ^= mask[i%4]
+	}
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	_, err := c.Conn.Write(frame)
+	return err
+}
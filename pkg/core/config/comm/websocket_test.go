@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketTransport(t *testing.T) {
+	echoAddr := startEchoServer(t)
+	gatewayAddr := startProxy(t, serveWebSocketGateway)
+
+	dial, err := newProxyDialer("ws://user:secret@" + gatewayAddr + "/tunnel")
+	require.NoError(t, err)
+	checkEcho(t, dial, echoAddr)
+
+	dial, err = newProxyDialer("ws://" + gatewayAddr + "/tunnel")
+	require.NoError(t, err)
+	_, err = dial(echoAddr, time.Second)
+	assert.Error(t, err, "expecting error since the gateway requires authentication")
+}
+
+func TestRegisterTransport(t *testing.T) {
+	echoAddr := startEchoServer(t)
+
+	var dialed string
+	RegisterTransport("direct", func(u *url.URL) (Dialer, error) {
+		return func(addr string, timeout time.Duration) (net.Conn, error) {
+			dialed = addr
+			return net.DialTimeout("tcp", addr, timeout)
+		}, nil
+	})
+
+	dial, err := newProxyDialer("direct://gateway")
+	require.NoError(t, err)
+	checkEcho(t, dial, echoAddr)
+	assert.Equal(t, echoAddr, dialed)
+}
+
+func serveWebSocketGateway(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	req, err := http.ReadRequest(reader)
+	if err != nil {
+		return
+	}
+	if user, password, ok := req.BasicAuth(); !ok || user != "user" || password != "secret" {
+		io.WriteString(conn, "HTTP/1.1 401 Unauthorized\r\n\r\n") // nolint: errcheck
+		return
+	}
+
+	target, err := net.Dial("tcp", req.URL.Query().Get("target"))
+	if err != nil {
+		io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n") // nolint: errcheck
+		return
+	}
+	defer target.Close()
+
+	accept := webSocketAccept(req.Header.Get("Sec-WebSocket-Key"))
+	io.WriteString(conn, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: "+accept+"\r\n\r\n") // nolint: errcheck
+
+	// A ping is sent first in order to check that the client handles control frames
+	conn.Write([]byte{wsFinBit | wsOpPing, 0}) // nolint: errcheck
+
+	go func() {
+		// The frames from the client are masked (and the pong is skipped) so use a webSocketConn to read them
+		io.Copy(target, &webSocketConn{Conn: conn, reader: reader}) // nolint: errcheck
+	}()
+
+	buf := make([]byte, 1024)
+	for {
+		n, err := target.Read(buf)
+		if err != nil {
+			return
+		}
+		frame := []byte{wsFinBit | wsOpBinary, byte(n)}
+		if n >= 126 {
+			frame = []byte{wsFinBit | wsOpBinary, 126, 0, 0}
+			binary.BigEndian.PutUint16(frame[2:], uint16(n))
+		}
+		if _, err := conn.Write(append(frame, buf[:n]...)); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+// PassphraseProvider returns the passphrase that protects the keys of the key store.
+// It is called once, when the passphrase is first needed.
+type PassphraseProvider func() ([]byte, error)
+
+// KeyStoreOption describes a functional parameter for the encrypted key store
+type KeyStoreOption func(opts *sw.EncryptedFileKeystoreOpts)
+
+// WithScrypt derives the encryption key with scrypt (default) using the given cost parameters
+func WithScrypt(n, r, p int) KeyStoreOption {
+	return func(opts *sw.EncryptedFileKeystoreOpts) {
+		opts.KDF = sw.KDFScrypt
+		opts.ScryptN, opts.ScryptR, opts.ScryptP = n, r, p
+	}
+}
+
+// WithArgon2id derives the encryption key with Argon2id using the given number of passes,
+// memory in KiB and number of threads
+func WithArgon2id(time, memory uint32, threads uint8) KeyStoreOption {
+	return func(opts *sw.EncryptedFileKeystoreOpts) {
+		opts.KDF = sw.KDFArgon2id
+		opts.Argon2Time, opts.Argon2Memory, opts.Argon2Threads = time, memory, threads
+	}
+}
+
+// GetSuiteByConfigWithPassphrase returns cryptosuite adaptor for bccsp loaded according to given config,
+// whose key store encrypts the private keys at rest (AES-256-GCM) with a key derived from the passphrase.
+// Keys of an existing cleartext key store are encrypted when the key store is opened.
+// To be used by the SDK, the cryptosuite has to be returned by a custom CreateCryptoSuiteProvider of the core factory.
+func GetSuiteByConfigWithPassphrase(config core.CryptoSuiteConfig, passphrase PassphraseProvider, opts ...KeyStoreOption) (core.CryptoSuite, error) {
+	if config.SecurityProvider() != "sw" {
+		return nil, errors.Errorf("Unsupported BCCSP Provider: %s", config.SecurityProvider())
+	}
+
+	ks, err := NewEncryptedKeyStore(config.KeyStorePath(), passphrase, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return GetSuite(config.SecurityLevel(), config.SecurityAlgorithm(), ks)
+}
+
+// NewEncryptedKeyStore returns a file based key store in the given directory whose private keys are
+// encrypted with a key derived from the passphrase
+func NewEncryptedKeyStore(path string, passphrase PassphraseProvider, opts ...KeyStoreOption) (bccsp.KeyStore, error) {
+	if passphrase == nil {
+		return nil, errors.New("passphrase provider is required")
+	}
+
+	ksOpts := &sw.EncryptedFileKeystoreOpts{}
+	for _, opt := range opts {
+		opt(ksOpts)
+	}
+
+	ks, err := sw.NewEncryptedFileKeyStore(path, sw.PassphraseProvider(passphrase), ksOpts, false)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to initialize encrypted key store")
+	}
+	return ks, nil
+}
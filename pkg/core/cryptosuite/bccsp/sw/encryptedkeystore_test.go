@@ -0,0 +1,180 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockcore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encryptedKeyType is the PEM type of encrypted key files
+const encryptedKeyType = "SDK ENCRYPTED KEY"
+
+// Cheap KDF parameters to keep the tests fast
+var testScrypt = WithScrypt(1<<10, 8, 1)
+
+func passphrase(value string, calls *int) PassphraseProvider {
+	return func() ([]byte, error) {
+		*calls++
+		return []byte(value), nil
+	}
+}
+
+func pemType(t *testing.T, path string) string {
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	block, _ := pem.Decode(raw)
+	require.NotNil(t, block)
+	return block.Type
+}
+
+func verifySigning(t *testing.T, c core.CryptoSuite, k core.Key) {
+	digest := sha256.Sum256([]byte("Hello"))
+	signature, err := c.Sign(k, digest[:], nil)
+	require.NoError(t, err)
+
+	pk, err := k.PublicKey()
+	require.NoError(t, err)
+	valid, err := c.Verify(pk, signature, digest[:], nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestEncryptedKeyStore(t *testing.T) {
+	path, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	calls := 0
+	ks, err := NewEncryptedKeyStore(path, passphrase("secret", &calls), testScrypt)
+	require.NoError(t, err)
+	assert.Equal(t, 0, calls, "passphrase isn't needed for an empty key store")
+
+	c, err := GetSuite(256, "SHA2", ks)
+	require.NoError(t, err)
+
+	k, err := c.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+	assert.Equal(t, encryptedKeyType, pemType(t, filepath.Join(path, hex.EncodeToString(k.SKI())+"_sk")))
+
+	aesKey, err := c.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+	assert.Equal(t, encryptedKeyType, pemType(t, filepath.Join(path, hex.EncodeToString(aesKey.SKI())+"_key")))
+
+	// Reopen the key store
+	ks, err = NewEncryptedKeyStore(path, passphrase("secret", &calls), testScrypt)
+	require.NoError(t, err)
+	c, err = GetSuite(256, "SHA2", ks)
+	require.NoError(t, err)
+
+	loaded, err := c.GetKey(k.SKI())
+	require.NoError(t, err)
+	assert.True(t, loaded.Private())
+	verifySigning(t, c, loaded)
+
+	loaded, err = c.GetKey(aesKey.SKI())
+	require.NoError(t, err)
+	assert.True(t, loaded.Symmetric())
+	assert.Equal(t, 2, calls, "expecting the passphrase to be requested once per key store")
+
+	ks, err = NewEncryptedKeyStore(path, passphrase("wrong", &calls), testScrypt)
+	require.NoError(t, err)
+	_, err = ks.GetKey(k.SKI())
+	assert.Error(t, err, "expecting error for wrong passphrase")
+}
+
+func TestEncryptedKeyStoreMigration(t *testing.T) {
+	path, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	// Cleartext key store
+	cleartextKS, err := sw.NewFileBasedKeyStore(nil, path, false)
+	require.NoError(t, err)
+	c, err := GetSuite(256, "SHA2", cleartextKS)
+	require.NoError(t, err)
+	k, err := c.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+	keyFile := filepath.Join(path, hex.EncodeToString(k.SKI())+"_sk")
+	assert.Equal(t, "PRIVATE KEY", pemType(t, keyFile))
+
+	// Key named by another tool
+	require.NoError(t, os.Rename(keyFile, filepath.Join(path, "priv_sk")))
+
+	calls := 0
+	ks, err := NewEncryptedKeyStore(path, passphrase("secret", &calls), testScrypt)
+	require.NoError(t, err)
+	assert.Equal(t, encryptedKeyType, pemType(t, filepath.Join(path, "priv_sk")), "expecting cleartext key to be encrypted")
+
+	c, err = GetSuite(256, "SHA2", ks)
+	require.NoError(t, err)
+	loaded, err := c.GetKey(k.SKI())
+	require.NoError(t, err)
+	verifySigning(t, c, loaded)
+}
+
+func TestEncryptedKeyStoreArgon2id(t *testing.T) {
+	path, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	calls := 0
+	ks, err := NewEncryptedKeyStore(path, passphrase("secret", &calls), WithArgon2id(1, 1024, 1))
+	require.NoError(t, err)
+	c, err := GetSuite(256, "SHA2", ks)
+	require.NoError(t, err)
+
+	k, err := c.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+	raw, err := ioutil.ReadFile(filepath.Join(path, hex.EncodeToString(k.SKI())+"_sk"))
+	require.NoError(t, err)
+	block, _ := pem.Decode(raw)
+	require.NotNil(t, block)
+	assert.Equal(t, "argon2id", block.Headers["KDF"])
+
+	// The KDF is read from the key file, so a key store with other options can read the key
+	ks, err = NewEncryptedKeyStore(path, passphrase("secret", &calls), testScrypt)
+	require.NoError(t, err)
+	_, err = ks.GetKey(k.SKI())
+	assert.NoError(t, err)
+}
+
+func TestEncryptedKeyStoreByConfig(t *testing.T) {
+	path, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("sw").AnyTimes()
+	mockConfig.EXPECT().SecurityAlgorithm().Return("SHA2")
+	mockConfig.EXPECT().SecurityLevel().Return(256)
+	mockConfig.EXPECT().KeyStorePath().Return(path)
+
+	calls := 0
+	c, err := GetSuiteByConfigWithPassphrase(mockConfig, passphrase("secret", &calls), testScrypt)
+	require.NoError(t, err)
+	verifyHashFn(t, c)
+
+	_, err = NewEncryptedKeyStore(path, nil)
+	assert.Error(t, err, "expecting error for missing passphrase provider")
+}
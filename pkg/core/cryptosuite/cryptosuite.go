@@ -78,6 +78,15 @@ func GetSHA256Opts() core.HashOpts {
 	return &bccsp.SHA256Opts{}
 }
 
+//GetHashOpts returns options for computing the hash with the given algorithm (e.g. SHA256 or SHA3_256).
+//SHA-256 is used if no algorithm is given.
+func GetHashOpts(algorithm string) (core.HashOpts, error) {
+	if algorithm == "" {
+		return GetSHA256Opts(), nil
+	}
+	return bccsp.GetHashOpt(algorithm)
+}
+
 //GetSHAOpts returns options for computing SHA.
 func GetSHAOpts() core.HashOpts {
 	return &bccsp.SHAOpts{}
@@ -35,12 +35,20 @@ var loggerProviderOnce sync.Once
 
 // Provider is the default logger implementation
 type Provider struct {
+	output      io.Writer
+	mutex       sync.RWMutex
+	levels      *metadata.ModuleLevels
+	callerInfos *metadata.CallerInfo
 }
 
 //GetLogger returns SDK logger implementation
 func (p *Provider) GetLogger(module string) api.Logger {
-	newDefLogger := log.New(os.Stdout, fmt.Sprintf(logPrefixFormatter, module), log.Ldate|log.Ltime|log.LUTC)
-	return &Log{deflogger: newDefLogger, module: module}
+	var output io.Writer = os.Stdout
+	if p.output != nil {
+		output = p.output
+	}
+	newDefLogger := log.New(output, fmt.Sprintf(logPrefixFormatter, module), log.Ldate|log.Ltime|log.LUTC)
+	return &Log{deflogger: newDefLogger, module: module, provider: p}
 }
 
 //LoggerProvider returns logging provider for SDK logger
@@ -48,6 +56,87 @@ func LoggerProvider() api.LoggerProvider {
 	return &Provider{}
 }
 
+//NewProvider returns a logging provider whose loggers write to the given output (stdout if nil). The log levels
+//and caller info settings of the provider are independent of the package-level settings so that, for example,
+//each SDK instance in a process may log at different levels to a different output.
+func NewProvider(output io.Writer) *Provider {
+	return &Provider{
+		output:      output,
+		levels:      &metadata.ModuleLevels{},
+		callerInfos: &metadata.CallerInfo{},
+	}
+}
+
+//SetLevel - setting log level for given module of the provider
+func (p *Provider) SetLevel(module string, level api.Level) {
+	if !p.scoped() {
+		SetLevel(module, level)
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.levels.SetLevel(module, level)
+}
+
+//GetLevel - getting log level for given module of the provider
+func (p *Provider) GetLevel(module string) api.Level {
+	if !p.scoped() {
+		return GetLevel(module)
+	}
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.levels.GetLevel(module)
+}
+
+//IsEnabledFor - Check if given log level is enabled for given module of the provider
+func (p *Provider) IsEnabledFor(module string, level api.Level) bool {
+	if !p.scoped() {
+		return IsEnabledFor(module, level)
+	}
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.levels.IsEnabledFor(module, level)
+}
+
+//ShowCallerInfo - Show caller info in log lines of the provider for given log level
+func (p *Provider) ShowCallerInfo(module string, level api.Level) {
+	if !p.scoped() {
+		ShowCallerInfo(module, level)
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.callerInfos.ShowCallerInfo(module, level)
+}
+
+//HideCallerInfo - Do not show caller info in log lines of the provider for given log level
+func (p *Provider) HideCallerInfo(module string, level api.Level) {
+	if !p.scoped() {
+		HideCallerInfo(module, level)
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.callerInfos.HideCallerInfo(module, level)
+}
+
+// scoped returns true if the provider has its own settings (i.e. it was created with NewProvider)
+func (p *Provider) scoped() bool {
+	return p != nil && p.levels != nil
+}
+
+func (p *Provider) loggerOpts(module string, level api.Level) *loggerOpts {
+	if !p.scoped() {
+		return getLoggerOpts(module, level)
+	}
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return &loggerOpts{
+		levelEnabled:      p.levels.IsEnabledFor(module, level),
+		callerInfoEnabled: p.callerInfos.IsCallerInfoEnabled(module, level),
+	}
+}
+
 //InitLogger sets custom logger which will be used over deflogger.
 //It is required to call this function before making any loggings.
 func InitLogger(l api.LoggerProvider) {
@@ -62,6 +151,7 @@ type Log struct {
 	deflogger    *log.Logger
 	customLogger api.Logger
 	module       string
+	provider     *Provider
 	custom       bool
 	once         sync.Once
 }
@@ -125,7 +215,7 @@ func getLoggerOpts(module string, level api.Level) *loggerOpts {
 
 // Fatal is CRITICAL log followed by a call to os.Exit(1).
 func (l *Log) Fatal(args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.CRITICAL)
+	opts := l.provider.loggerOpts(l.module, api.CRITICAL)
 	if l.loadCustomLogger() {
 		l.customLogger.Fatal(args...)
 		return
@@ -136,7 +226,7 @@ func (l *Log) Fatal(args ...interface{}) {
 
 // Fatalf is CRITICAL log formatted followed by a call to os.Exit(1).
 func (l *Log) Fatalf(format string, args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.CRITICAL)
+	opts := l.provider.loggerOpts(l.module, api.CRITICAL)
 	if l.loadCustomLogger() {
 		l.customLogger.Fatalf(format, args...)
 		return
@@ -147,7 +237,7 @@ func (l *Log) Fatalf(format string, args ...interface{}) {
 
 // Fatalln is CRITICAL log ln followed by a call to os.Exit(1).
 func (l *Log) Fatalln(args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.CRITICAL)
+	opts := l.provider.loggerOpts(l.module, api.CRITICAL)
 	if l.loadCustomLogger() {
 		l.customLogger.Fatalln(args...)
 		return
@@ -158,7 +248,7 @@ func (l *Log) Fatalln(args ...interface{}) {
 
 // Panic is CRITICAL log followed by a call to panic()
 func (l *Log) Panic(args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.CRITICAL)
+	opts := l.provider.loggerOpts(l.module, api.CRITICAL)
 	if l.loadCustomLogger() {
 		l.customLogger.Panic(args...)
 		return
@@ -169,7 +259,7 @@ func (l *Log) Panic(args ...interface{}) {
 
 // Panicf is CRITICAL log formatted followed by a call to panic()
 func (l *Log) Panicf(format string, args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.CRITICAL)
+	opts := l.provider.loggerOpts(l.module, api.CRITICAL)
 	if l.loadCustomLogger() {
 		l.customLogger.Panicf(format, args...)
 		return
@@ -180,7 +270,7 @@ func (l *Log) Panicf(format string, args ...interface{}) {
 
 // Panicln is CRITICAL log ln followed by a call to panic()
 func (l *Log) Panicln(args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.CRITICAL)
+	opts := l.provider.loggerOpts(l.module, api.CRITICAL)
 	if l.loadCustomLogger() {
 		l.customLogger.Panicln(args...)
 		return
@@ -222,7 +312,7 @@ func (l *Log) Println(args ...interface{}) {
 // Debug calls go log.Output.
 // Arguments are handled in the manner of fmt.Print.
 func (l *Log) Debug(args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.DEBUG)
+	opts := l.provider.loggerOpts(l.module, api.DEBUG)
 	if !opts.levelEnabled {
 		return
 	}
@@ -236,7 +326,7 @@ func (l *Log) Debug(args ...interface{}) {
 // Debugf calls go log.Output.
 // Arguments are handled in the manner of fmt.Printf.
 func (l *Log) Debugf(format string, args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.DEBUG)
+	opts := l.provider.loggerOpts(l.module, api.DEBUG)
 	if !opts.levelEnabled {
 		return
 	}
@@ -250,7 +340,7 @@ func (l *Log) Debugf(format string, args ...interface{}) {
 // Debugln calls go log.Output.
 // Arguments are handled in the manner of fmt.Println.
 func (l *Log) Debugln(args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.DEBUG)
+	opts := l.provider.loggerOpts(l.module, api.DEBUG)
 	if !opts.levelEnabled {
 		return
 	}
@@ -264,7 +354,7 @@ func (l *Log) Debugln(args ...interface{}) {
 // Info calls go log.Output.
 // Arguments are handled in the manner of fmt.Print.
 func (l *Log) Info(args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.INFO)
+	opts := l.provider.loggerOpts(l.module, api.INFO)
 	if !opts.levelEnabled {
 		return
 	}
@@ -278,7 +368,7 @@ func (l *Log) Info(args ...interface{}) {
 // Infof calls go log.Output.
 // Arguments are handled in the manner of fmt.Printf.
 func (l *Log) Infof(format string, args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.INFO)
+	opts := l.provider.loggerOpts(l.module, api.INFO)
 	if !opts.levelEnabled {
 		return
 	}
@@ -292,7 +382,7 @@ func (l *Log) Infof(format string, args ...interface{}) {
 // Infoln calls go log.Output.
 // Arguments are handled in the manner of fmt.Println.
 func (l *Log) Infoln(args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.INFO)
+	opts := l.provider.loggerOpts(l.module, api.INFO)
 	if !opts.levelEnabled {
 		return
 	}
@@ -306,7 +396,7 @@ func (l *Log) Infoln(args ...interface{}) {
 // Warn calls go log.Output.
 // Arguments are handled in the manner of fmt.Print.
 func (l *Log) Warn(args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.WARNING)
+	opts := l.provider.loggerOpts(l.module, api.WARNING)
 	if !opts.levelEnabled {
 		return
 	}
@@ -320,7 +410,7 @@ func (l *Log) Warn(args ...interface{}) {
 // Warnf calls go log.Output.
 // Arguments are handled in the manner of fmt.Printf.
 func (l *Log) Warnf(format string, args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.WARNING)
+	opts := l.provider.loggerOpts(l.module, api.WARNING)
 	if !opts.levelEnabled {
 		return
 	}
@@ -334,7 +424,7 @@ func (l *Log) Warnf(format string, args ...interface{}) {
 // Warnln calls go log.Output.
 // Arguments are handled in the manner of fmt.Println.
 func (l *Log) Warnln(args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.WARNING)
+	opts := l.provider.loggerOpts(l.module, api.WARNING)
 	if !opts.levelEnabled {
 		return
 	}
@@ -348,7 +438,7 @@ func (l *Log) Warnln(args ...interface{}) {
 // Error calls go log.Output.
 // Arguments are handled in the manner of fmt.Print.
 func (l *Log) Error(args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.ERROR)
+	opts := l.provider.loggerOpts(l.module, api.ERROR)
 	if !opts.levelEnabled {
 		return
 	}
@@ -362,7 +452,7 @@ func (l *Log) Error(args ...interface{}) {
 // Errorf calls go log.Output.
 // Arguments are handled in the manner of fmt.Printf.
 func (l *Log) Errorf(format string, args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.ERROR)
+	opts := l.provider.loggerOpts(l.module, api.ERROR)
 	if !opts.levelEnabled {
 		return
 	}
@@ -376,7 +466,7 @@ func (l *Log) Errorf(format string, args ...interface{}) {
 // Errorln calls go log.Output.
 // Arguments are handled in the manner of fmt.Println.
 func (l *Log) Errorln(args ...interface{}) {
-	opts := getLoggerOpts(l.module, api.ERROR)
+	opts := l.provider.loggerOpts(l.module, api.ERROR)
 	if !opts.levelEnabled {
 		return
 	}
@@ -421,7 +511,8 @@ func (l *Log) logln(opts *loggerOpts, level api.Level, args ...interface{}) {
 
 func (l *Log) loadCustomLogger() bool {
 	l.once.Do(func() {
-		if atomic.LoadInt32(&useCustomLogger) > 0 {
+		// The custom logger only replaces the loggers of the package-level provider
+		if !l.provider.scoped() && atomic.LoadInt32(&useCustomLogger) > 0 {
 			l.customLogger = loggerProviderInstance.GetLogger(l.module)
 			l.custom = true
 		}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package fab
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 )
@@ -25,16 +27,19 @@ type ClientTLSConfig struct {
 
 // OrdererConfig defines an orderer configuration
 type OrdererConfig struct {
-	URL         string
-	GRPCOptions map[string]interface{}
-	TLSCACerts  endpoint.TLSConfig
+	URL           string
+	GRPCOptions   map[string]interface{}
+	TLSCACerts    endpoint.TLSConfig
+	OperationsURL string
+	AdminURL      string
 }
 
 // PeerConfig defines a peer configuration
 type PeerConfig struct {
-	URL         string
-	GRPCOptions map[string]interface{}
-	TLSCACerts  endpoint.TLSConfig
+	URL           string
+	GRPCOptions   map[string]interface{}
+	TLSCACerts    endpoint.TLSConfig
+	OperationsURL string
 }
 
 // OrganizationConfig provides the definition of an organization in the network
@@ -61,6 +66,32 @@ type ChannelEndpointConfig struct {
 type ChannelPolicies struct {
 	//Policy for querying channel block
 	QueryChannelConfig QueryChannelConfigPolicy
+	//Timeouts overrides the client timeouts for the channel
+	Timeouts TimeoutPolicy
+	//Selection policy for choosing endorsing peers
+	Selection SelectionPolicy
+	//Observer policy for clients whose organization isn't a member of the channel
+	Observer ObserverPolicy
+}
+
+//ObserverPolicy defines the policy for read-only clients whose organization isn't a member of the channel
+type ObserverPolicy struct {
+	Enabled bool
+}
+
+//SelectionPolicy defines the policy for choosing endorsing peers
+type SelectionPolicy struct {
+	//Balancer is one of Random (default), RoundRobin or Weighted
+	Balancer string
+	//PreferLocalOrg prefers the peers of the client's organization over the peers of other organizations
+	PreferLocalOrg bool
+}
+
+//TimeoutPolicy defines timeout overrides for a channel by operation type (e.g. execute, query,
+//peerResponse) and by chaincode ID
+type TimeoutPolicy struct {
+	Operations map[string]time.Duration
+	Chaincodes map[string]map[string]time.Duration
 }
 
 //QueryChannelConfigPolicy defines opts for channelConfigBlock
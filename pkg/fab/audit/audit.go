@@ -0,0 +1,129 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package audit provides an optional sink which receives a record of every signature produced by the SDK
+// and of every envelope submitted to an orderer, so that applications may keep a compliance trail
+// without wrapping each client call.
+//
+//  Basic Flow:
+//  1) Implement the Sink interface (e.g. writing the records to an append-only log)
+//  2) Create the SDK with the sink using the fabsdk.WithAuditSink option
+//  3) Use the SDK clients as usual; a record is passed to the sink for each signed artifact
+package audit
+
+import (
+	"crypto/sha256"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+)
+
+// Action is the action which is recorded
+type Action string
+
+const (
+	// Signed indicates that the SDK signed an artifact
+	Signed Action = "signed"
+	// Submitted indicates that the SDK submitted a signed envelope to an orderer
+	Submitted Action = "submitted"
+)
+
+// Purpose is the purpose of the signed artifact
+type Purpose string
+
+const (
+	// PurposeProposal is a transaction proposal sent to endorsers
+	PurposeProposal Purpose = "proposal"
+	// PurposeEnvelope is a transaction envelope (including channel config updates) sent to orderers
+	PurposeEnvelope Purpose = "envelope"
+	// PurposeConfigSignature is a signature over a channel config update
+	PurposeConfigSignature Purpose = "config-signature"
+	// PurposeDeliverRequest is a request for blocks sent to a peer's or orderer's deliver service
+	PurposeDeliverRequest Purpose = "deliver-request"
+	// PurposeDiscoveryRequest is a request sent to a peer's discovery service
+	PurposeDiscoveryRequest Purpose = "discovery-request"
+	// PurposeSnapshotRequest is a request sent to a peer's snapshot service
+	PurposeSnapshotRequest Purpose = "snapshot-request"
+)
+
+// Record describes a signed artifact
+type Record struct {
+	Action  Action
+	Purpose Purpose
+	// MSPID and ID identify the signer
+	MSPID string
+	ID    string
+	// Digest is the SHA-256 hash of the signed bytes
+	Digest []byte
+	// Endpoint is the URL of the peer or orderer to which the artifact is sent
+	// (empty if the artifact isn't sent to a single endpoint, e.g. proposals and config signatures)
+	Endpoint  string
+	Timestamp time.Time
+	// Err is the error returned by the endpoint (submissions only)
+	Err error
+}
+
+// Sink receives the audit records. Record is called synchronously by the goroutine which signs or submits
+// the artifact so implementations should return quickly (e.g. by buffering the records).
+type Sink interface {
+	Record(record *Record)
+}
+
+// SinkProvider supplies the sink of an SDK instance. It is implemented by the infra provider of the SDK
+// (see fabsdk.WithAuditSink).
+type SinkProvider interface {
+	AuditSink() Sink
+}
+
+// Context is the client context of the signer. The records are passed to the sink of the context's
+// infra provider, if the provider implements SinkProvider and has a sink.
+type Context interface {
+	msp.Identity
+	InfraProvider() fab.InfraProvider
+}
+
+// RecordSigned records the signature of the given bytes by the signer of the given context
+func RecordSigned(ctx Context, purpose Purpose, signed []byte, endpoint string) {
+	record(Signed, ctx, purpose, signed, endpoint, nil)
+}
+
+// RecordSubmitted records the submission of the given (signed) envelope payload to the given endpoint
+func RecordSubmitted(ctx Context, payload []byte, endpoint string, err error) {
+	record(Submitted, ctx, PurposeEnvelope, payload, endpoint, err)
+}
+
+func sinkOf(ctx Context) Sink {
+	if ctx == nil {
+		return nil
+	}
+	if p, ok := ctx.InfraProvider().(SinkProvider); ok {
+		return p.AuditSink()
+	}
+	return nil
+}
+
+func record(action Action, ctx Context, purpose Purpose, msg []byte, endpoint string, err error) {
+	s := sinkOf(ctx)
+	if s == nil {
+		return
+	}
+
+	digest := sha256.Sum256(msg)
+	r := &Record{
+		Action:    action,
+		Purpose:   purpose,
+		Digest:    digest[:],
+		Endpoint:  endpoint,
+		Timestamp: time.Now(),
+		Err:       err,
+	}
+	if id := ctx.Identifier(); id != nil {
+		r.MSPID = id.MSPID
+		r.ID = id.ID
+	}
+	s.Record(r)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"crypto/sha256"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+)
+
+// memorySink keeps the audit records in memory
+type memorySink struct {
+	mutex   sync.Mutex
+	records []*Record
+}
+
+func (s *memorySink) Record(record *Record) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.records = append(s.records, record)
+}
+
+// sinkProvider is an infra provider with an audit sink
+type sinkProvider struct {
+	fab.InfraProvider
+	sink Sink
+}
+
+func (p *sinkProvider) AuditSink() Sink {
+	return p.sink
+}
+
+// signerContext is the context of a signer whose infra provider is the given provider
+type signerContext struct {
+	msp.Identity
+	infraProvider fab.InfraProvider
+}
+
+func (c *signerContext) InfraProvider() fab.InfraProvider {
+	return c.infraProvider
+}
+
+func TestRecord(t *testing.T) {
+	identity := mspmocks.NewMockSigningIdentity("user1", "Org1MSP")
+
+	// Nothing is recorded without a sink
+	RecordSigned(&signerContext{Identity: identity}, PurposeProposal, []byte("proposal"), "")
+	RecordSigned(&signerContext{Identity: identity, infraProvider: &sinkProvider{}}, PurposeProposal, []byte("proposal"), "")
+
+	sink := &memorySink{}
+	signer := &signerContext{Identity: identity, infraProvider: &sinkProvider{sink: sink}}
+
+	RecordSigned(signer, PurposeProposal, []byte("proposal"), "")
+	RecordSubmitted(signer, []byte("payload"), "orderer.example.com:7050", errors.New("service unavailable"))
+
+	require.Len(t, sink.records, 2)
+
+	r := sink.records[0]
+	assert.Equal(t, Signed, r.Action)
+	assert.Equal(t, PurposeProposal, r.Purpose)
+	assert.Equal(t, "Org1MSP", r.MSPID)
+	assert.Equal(t, "user1", r.ID)
+	digest := sha256.Sum256([]byte("proposal"))
+	assert.Equal(t, digest[:], r.Digest)
+	assert.Empty(t, r.Endpoint)
+	assert.False(t, r.Timestamp.IsZero())
+	assert.NoError(t, r.Err)
+
+	r = sink.records[1]
+	assert.Equal(t, Submitted, r.Action)
+	assert.Equal(t, PurposeEnvelope, r.Purpose)
+	assert.Equal(t, "orderer.example.com:7050", r.Endpoint)
+	assert.EqualError(t, r.Err, "service unavailable")
+
+	// The records of another SDK instance (i.e. with a different infra provider) are passed to its own sink
+	otherSink := &memorySink{}
+	RecordSigned(&signerContext{Identity: identity, infraProvider: &sinkProvider{sink: otherSink}}, PurposeProposal, []byte("proposal"), "")
+	assert.Len(t, otherSink.records, 1)
+	assert.Len(t, sink.records, 2)
+}
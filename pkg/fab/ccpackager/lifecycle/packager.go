@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package lcpackager provides utilities for chaincode packages of the Fabric 2.0 chaincode lifecycle
+// (as created by 'peer lifecycle chaincode package'). The package ID and the type of a package may be
+// determined offline, before the package is installed on the peers.
+package lcpackager
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+const metadataFile = "metadata.json"
+
+// builtinTypes are the chaincode types which are built by the peer without an external builder
+var builtinTypes = map[string]bool{
+	"golang": true,
+	"java":   true,
+	"node":   true,
+}
+
+// Metadata is the content of the metadata.json file of a chaincode package
+type Metadata struct {
+	Type  string `json:"type"`
+	Path  string `json:"path"`
+	Label string `json:"label"`
+}
+
+// RequiresExternalBuilder returns true if the chaincode type is not built by the peer itself,
+// i.e. an external builder (e.g. for chaincode as a service) must be configured on the peers
+func (m *Metadata) RequiresExternalBuilder() bool {
+	return !builtinTypes[m.Type]
+}
+
+// ComputePackageID returns the package ID of a package with the given label. The peer computes the
+// same ID (<label>:<hex encoded SHA256 hash of the package>) when the package is installed.
+func ComputePackageID(label string, pkg []byte) string {
+	hash := sha256.Sum256(pkg)
+	return label + ":" + hex.EncodeToString(hash[:])
+}
+
+// PackageID returns the package ID of the given package using the label of the package's metadata
+func PackageID(pkg []byte) (string, error) {
+	metadata, err := ReadMetadata(pkg)
+	if err != nil {
+		return "", err
+	}
+	return ComputePackageID(metadata.Label, pkg), nil
+}
+
+// ReadMetadata reads the metadata of the given package
+func ReadMetadata(pkg []byte) (*Metadata, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(pkg))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read chaincode package")
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, errors.Errorf("%s not found in chaincode package", metadataFile)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read chaincode package")
+		}
+
+		if header.Name != metadataFile {
+			continue
+		}
+
+		metadataBytes, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", metadataFile)
+		}
+
+		metadata := &Metadata{}
+		if err := json.Unmarshal(metadataBytes, metadata); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", metadataFile)
+		}
+		if metadata.Label == "" {
+			return nil, errors.Errorf("label is missing in %s", metadataFile)
+		}
+		return metadata, nil
+	}
+}
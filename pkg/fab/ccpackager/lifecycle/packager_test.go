@@ -0,0 +1,71 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lcpackager
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPackage(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func TestPackageID(t *testing.T) {
+	pkg := newTestPackage(t, map[string]string{
+		"metadata.json": `{"type":"golang","path":"github.com/example_cc","label":"example_cc_1"}`,
+		"code.tar.gz":   "code",
+	})
+
+	packageID, err := PackageID(pkg)
+	require.NoError(t, err)
+	assert.Equal(t, ComputePackageID("example_cc_1", pkg), packageID)
+	assert.Regexp(t, "^example_cc_1:[0-9a-f]{64}$", packageID)
+
+	metadata, err := ReadMetadata(pkg)
+	require.NoError(t, err)
+	assert.Equal(t, "golang", metadata.Type)
+	assert.Equal(t, "github.com/example_cc", metadata.Path)
+	assert.False(t, metadata.RequiresExternalBuilder())
+}
+
+func TestExternalBuilder(t *testing.T) {
+	pkg := newTestPackage(t, map[string]string{
+		"metadata.json": `{"type":"ccaas","label":"example_cc_1"}`,
+		"code.tar.gz":   "connection.json",
+	})
+
+	metadata, err := ReadMetadata(pkg)
+	require.NoError(t, err)
+	assert.True(t, metadata.RequiresExternalBuilder())
+}
+
+func TestInvalidPackage(t *testing.T) {
+	_, err := PackageID([]byte("invalid"))
+	assert.Error(t, err)
+
+	_, err = PackageID(newTestPackage(t, map[string]string{"code.tar.gz": "code"}))
+	assert.Error(t, err, "expecting error for missing metadata")
+
+	_, err = PackageID(newTestPackage(t, map[string]string{"metadata.json": `{"type":"golang"}`}))
+	assert.Error(t, err, "expecting error for missing label")
+}
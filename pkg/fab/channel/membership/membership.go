@@ -27,6 +27,11 @@ var logger = logging.NewLogger("fabsdk/fab")
 type identityImpl struct {
 	mspManager msp.MSPManager
 	msps       []string
+	// endorserMSPManager is used to validate endorsers. Unlike mspManager, whose MSPs are always MSP 1.0,
+	// its MSPs have the version supported by the channel's capabilities (see createEndorserMSPManager).
+	endorserMSPManager msp.MSPManager
+	// nodeOUs contains the IDs of the MSPs which distinguish node roles (clients and peers)
+	nodeOUs map[string]bool
 }
 
 // Context holds the providers
@@ -41,7 +46,11 @@ func New(ctx Context, cfg fab.ChannelCfg) (fab.ChannelMembership, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &identityImpl{mspManager: mspManager, msps: mspNames}, nil
+	endorserMSPManager, nodeOUs, err := createEndorserMSPManager(ctx, cfg, mspManager)
+	if err != nil {
+		return nil, err
+	}
+	return &identityImpl{mspManager: mspManager, msps: mspNames, endorserMSPManager: endorserMSPManager, nodeOUs: nodeOUs}, nil
 }
 
 func (i *identityImpl) Validate(serializedID []byte) error {
@@ -59,6 +68,44 @@ func (i *identityImpl) Validate(serializedID []byte) error {
 	return id.Validate()
 }
 
+// ValidateEndorser validates the identity and checks that it belongs to the given MSP. If the MSP
+// has node OUs enabled then the identity must also have the peer role.
+func (i *identityImpl) ValidateEndorser(serializedID []byte, mspID string) error {
+	err := areCertDatesValid(serializedID)
+	if err != nil {
+		logger.Errorf("Cert error %s", err)
+		return err
+	}
+
+	id, err := i.endorserMSPManager.DeserializeIdentity(serializedID)
+	if err != nil {
+		logger.Errorf("failed to deserialize identity: %s", err)
+		return err
+	}
+	if err := id.Validate(); err != nil {
+		return err
+	}
+
+	idMSPID := id.GetMSPIdentifier()
+	if mspID != "" && idMSPID != mspID {
+		return errors.Errorf("endorser identity belongs to MSP [%s] rather than the MSP of the endorsing peer [%s]", idMSPID, mspID)
+	}
+
+	if !i.nodeOUs[idMSPID] {
+		return nil
+	}
+
+	principal, err := proto.Marshal(&mb.MSPRole{MspIdentifier: idMSPID, Role: mb.MSPRole_PEER})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal peer role")
+	}
+	err = id.SatisfiesPrincipal(&mb.MSPPrincipal{PrincipalClassification: mb.MSPPrincipal_ROLE, Principal: principal})
+	if err != nil {
+		return errors.WithMessage(err, "endorser identity doesn't have the peer role")
+	}
+	return nil
+}
+
 func (i *identityImpl) Verify(serializedID []byte, msg []byte, sig []byte) error {
 	id, err := i.mspManager.DeserializeIdentity(serializedID)
 	if err != nil {
@@ -105,7 +152,7 @@ func createMSPManager(ctx Context, cfg fab.ChannelCfg) (msp.MSPManager, []string
 	mspManager := msp.NewMSPManager()
 	var mspNames []string
 	if len(cfg.MSPs()) > 0 {
-		msps, err := loadMSPs(cfg.MSPs(), ctx.CryptoSuite())
+		msps, err := loadMSPs(cfg.MSPs(), ctx.CryptoSuite(), msp.MSPv1_0, nil)
 		if err != nil {
 			return nil, nil, errors.WithMessage(err, "load MSPs from config failed")
 		}
@@ -139,7 +186,30 @@ func createMSPManager(ctx Context, cfg fab.ChannelCfg) (msp.MSPManager, []string
 	return mspManager, mspNames, nil
 }
 
-func loadMSPs(mspConfigs []*mb.MSPConfig, cs core.CryptoSuite) ([]msp.MSP, error) {
+// createEndorserMSPManager returns the MSP manager used to validate endorsers along with the IDs of the MSPs
+// which have node OUs enabled. Node OUs require MSP 1.1, so if the channel has the V1_1 capability then a
+// separate manager is created with MSP 1.1 MSPs; otherwise the given (MSP 1.0) manager is returned. The
+// version is limited to endorser validation so that the validation of other identities is unchanged.
+func createEndorserMSPManager(ctx Context, cfg fab.ChannelCfg, mspManager msp.MSPManager) (msp.MSPManager, map[string]bool, error) {
+	nodeOUs := make(map[string]bool)
+	if len(cfg.MSPs()) == 0 || !cfg.HasCapability(fab.ChannelGroupKey, fab.V1_1Capability) {
+		return mspManager, nodeOUs, nil
+	}
+
+	msps, err := loadMSPs(cfg.MSPs(), ctx.CryptoSuite(), msp.MSPv1_1, nodeOUs)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "load endorser MSPs from config failed")
+	}
+
+	endorserMSPManager := msp.NewMSPManager()
+	if err := endorserMSPManager.Setup(msps); err != nil {
+		return nil, nil, errors.WithMessage(err, "endorser MSPManager Setup failed")
+	}
+
+	return endorserMSPManager, nodeOUs, nil
+}
+
+func loadMSPs(mspConfigs []*mb.MSPConfig, cs core.CryptoSuite, version msp.MSPVersion, nodeOUs map[string]bool) ([]msp.MSP, error) {
 	logger.Debugf("loadMSPs - start number of msps=%d", len(mspConfigs))
 
 	msps := []msp.MSP{}
@@ -164,8 +234,7 @@ func loadMSPs(mspConfigs []*mb.MSPConfig, cs core.CryptoSuite) ([]msp.MSP, error
 		}
 
 		// TODO: Do something with orgs
-		// TODO: Configure MSP version (rather than MSP 1.0)
-		newMSP, err := msp.NewBccspMsp(msp.MSPv1_0, cs)
+		newMSP, err := msp.NewBccspMsp(version, cs)
 		if err != nil {
 			return nil, errors.Wrap(err, "instantiate MSP failed")
 		}
@@ -180,6 +249,10 @@ func loadMSPs(mspConfigs []*mb.MSPConfig, cs core.CryptoSuite) ([]msp.MSP, error
 		}
 		logger.Debugf("loadMSPs - adding msp=%s", mspID)
 
+		if version != msp.MSPv1_0 && fabricConfig.FabricNodeOus != nil && fabricConfig.FabricNodeOus.Enable {
+			nodeOUs[mspID] = true
+		}
+
 		msps = append(msps, newMSP)
 	}
 
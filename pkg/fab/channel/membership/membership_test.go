@@ -24,12 +24,16 @@ import (
 	"encoding/pem"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm/tls"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
 	"github.com/stretchr/testify/assert"
 )
 
+var _ fab.EndorserValidator = (*identityImpl)(nil)
+var _ fab.EndorserValidator = (*Ref)(nil)
+
 //TestCertSignedWithUnknownAuthority
 func TestCertSignedWithUnknownAuthority(t *testing.T) {
 	var err error
@@ -167,6 +171,94 @@ func TestNewMembership(t *testing.T) {
 	assert.NotNil(t, m.Verify(badEndorser, []byte("test"), []byte("test1")))
 }
 
+func TestValidateEndorser(t *testing.T) {
+	goodMSPID := "GoodMSP"
+
+	ctx := mocks.NewMockProviderContext()
+	cfg := mocks.NewMockChannelCfg("")
+	cfg.MockMSPs = []*mb.MSPConfig{buildMSPConfig(goodMSPID, []byte(validRootCA))}
+
+	fabCertPool, err := tls.NewCertPool(false)
+	assert.Nil(t, err)
+	endpointConfig := &mocks.MockConfig{CustomTLSCACertPool: fabCertPool}
+
+	m, err := New(Context{Providers: ctx, EndpointConfig: endpointConfig}, cfg)
+	assert.Nil(t, err)
+
+	endorser, err := proto.Marshal(&mb.SerializedIdentity{Mspid: goodMSPID, IdBytes: []byte(certPem)})
+	assert.Nil(t, err)
+
+	assert.Nil(t, m.(fab.EndorserValidator).ValidateEndorser(endorser, goodMSPID))
+	assert.Nil(t, m.(fab.EndorserValidator).ValidateEndorser(endorser, ""), "expecting any MSP to be accepted if the peer's MSP is unknown")
+	assert.NotNil(t, m.(fab.EndorserValidator).ValidateEndorser(endorser, "OtherMSP"), "expecting error since the endorser doesn't belong to the peer's MSP")
+}
+
+func TestValidateEndorserNodeOUs(t *testing.T) {
+	mspID := "Org1MSP"
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca.org1.example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caRaw, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	ca, err := x509.ParseCertificate(caRaw)
+	assert.NoError(t, err)
+
+	issue := func(serial int64, ou string) []byte {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: ou + ".org1.example.com", OrganizationalUnit: []string{ou}},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+		}
+		raw, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		assert.NoError(t, err)
+		id, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw})})
+		assert.NoError(t, err)
+		return id
+	}
+
+	mspConfig := &mb.FabricMSPConfig{
+		Name:      mspID,
+		RootCerts: [][]byte{pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caRaw})},
+		FabricNodeOus: &mb.FabricNodeOUs{
+			Enable:             true,
+			ClientOuIdentifier: &mb.FabricOUIdentifier{OrganizationalUnitIdentifier: "client"},
+			PeerOuIdentifier:   &mb.FabricOUIdentifier{OrganizationalUnitIdentifier: "peer"},
+		},
+	}
+
+	ctx := mocks.NewMockProviderContext()
+	cfg := mocks.NewMockChannelCfg("")
+	cfg.MockCapabilities[fab.ChannelGroupKey][fab.V1_1Capability] = true
+	cfg.MockMSPs = []*mb.MSPConfig{{Config: marshalOrPanic(mspConfig)}}
+
+	fabCertPool, err := tls.NewCertPool(false)
+	assert.Nil(t, err)
+	endpointConfig := &mocks.MockConfig{CustomTLSCACertPool: fabCertPool}
+
+	m, err := New(Context{Providers: ctx, EndpointConfig: endpointConfig}, cfg)
+	assert.Nil(t, err)
+
+	peer := issue(2, "peer")
+	client := issue(3, "client")
+
+	assert.Nil(t, m.(fab.EndorserValidator).ValidateEndorser(peer, mspID))
+	assert.Nil(t, m.Validate(client))
+	assert.NotNil(t, m.(fab.EndorserValidator).ValidateEndorser(client, mspID), "expecting error since the endorser doesn't have the peer role")
+}
+
 func buildMSPConfig(name string, root []byte) *mb.MSPConfig {
 	return &mb.MSPConfig{
 		Type:   0,
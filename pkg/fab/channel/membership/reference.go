@@ -57,6 +57,19 @@ func (ref *Ref) Verify(serializedID []byte, msg []byte, sig []byte) error {
 	return membership.Verify(serializedID, msg, sig)
 }
 
+// ValidateEndorser calls ValidateEndorser on the underlying reference (or Validate if the underlying
+// reference doesn't validate endorsers)
+func (ref *Ref) ValidateEndorser(serializedID []byte, mspID string) error {
+	membership, err := ref.get()
+	if err != nil {
+		return err
+	}
+	if ev, ok := membership.(fab.EndorserValidator); ok {
+		return ev.ValidateEndorser(serializedID, mspID)
+	}
+	return membership.Validate(serializedID)
+}
+
 // ContainsMSP checks if given MSP is available in the underlying reference
 func (ref *Ref) ContainsMSP(msp string) bool {
 	membership, err := ref.get()
@@ -21,9 +21,10 @@ import (
 
 // Transactor enables sending transactions and transaction proposals on the channel.
 type Transactor struct {
-	reqCtx    reqContext.Context
-	ChannelID string
-	orderers  []fab.Orderer
+	reqCtx     reqContext.Context
+	ChannelID  string
+	orderers   []fab.Orderer
+	hashingAlg string
 }
 
 // NewTransactor returns a Transactor for the current context and channel config.
@@ -44,9 +45,10 @@ func NewTransactor(reqCtx reqContext.Context, cfg fab.ChannelCfg) (*Transactor,
 	//}
 
 	t := Transactor{
-		reqCtx:    reqCtx,
-		ChannelID: cfg.ID(),
-		orderers:  orderers,
+		reqCtx:     reqCtx,
+		ChannelID:  cfg.ID(),
+		orderers:   orderers,
+		hashingAlg: cfg.HashingAlgorithm(),
 	}
 	return &t, nil
 }
@@ -154,7 +156,7 @@ func (t *Transactor) CreateTransactionHeader() (fab.TransactionHeader, error) {
 		return nil, errors.New("failed get client context from reqContext for txn Header")
 	}
 
-	txh, err := txn.NewHeader(ctx, t.ChannelID)
+	txh, err := txn.NewHeader(ctx, t.ChannelID, txn.WithHashingAlgorithm(t.hashingAlg))
 	if err != nil {
 		return nil, errors.WithMessage(err, "new transaction ID failed")
 	}
@@ -193,3 +195,16 @@ func (t *Transactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionRespo
 
 	return txn.Send(reqCtx, tx, t.orderers)
 }
+
+// SendEnvelope sends a previously constructed and signed transaction envelope to the orderer service
+func (t *Transactor) SendEnvelope(envelope *fab.SignedEnvelope) (*fab.TransactionResponse, error) {
+	ctx, ok := contextImpl.RequestClientContext(t.reqCtx)
+	if !ok {
+		return nil, errors.New("failed get client context from reqContext for SendEnvelope")
+	}
+
+	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeoutType(fab.OrdererResponse), contextImpl.WithParent(t.reqCtx))
+	defer cancel()
+
+	return txn.BroadcastEnvelope(reqCtx, envelope, t.orderers)
+}
@@ -46,13 +46,13 @@ func NewCacheKey(ctx fab.ClientContext, pvdr Provider, channelID string) (CacheK
 
 // NewRefCache a cache of channel config references that refreshed with the
 // given interval
-func NewRefCache(refresh time.Duration) *lazycache.Cache {
+func NewRefCache(refresh time.Duration, opts ...RefOption) *lazycache.Cache {
 	initializer := func(key lazycache.Key) (interface{}, error) {
 		ck, ok := key.(CacheKey)
 		if !ok {
 			return nil, errors.New("unexpected cache key")
 		}
-		return NewRef(refresh, ck.Provider(), ck.ChannelID(), ck.Context()), nil
+		return NewRef(refresh, ck.Provider(), ck.ChannelID(), ck.Context(), opts...), nil
 	}
 
 	return lazycache.New("Channel_Cfg_Cache", initializer)
@@ -7,13 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package chconfig
 
 import (
+	reqContext "context"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 )
@@ -67,6 +73,75 @@ func TestChannelConfigCacheBad(t *testing.T) {
 	assert.Contains(t, err.Error(), badProviderErrMessage)
 }
 
+func TestChannelConfigCacheWarmStart(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("user", "user")
+	clientCtx := mocks.NewMockContext(user)
+
+	path, err := ioutil.TempDir("", "chconfig")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	store, err := keyvaluestore.New(&keyvaluestore.FileKeyValueStoreOptions{Path: path})
+	require.NoError(t, err)
+
+	block := (&mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:               "Admins",
+			MSPNames:                []string{"Org1MSP"},
+			OrdererAddress:          "localhost:9999",
+			RootCA:                  validRootCA,
+			ChannelCapabilities:     []string{fab.V1_1Capability},
+			ApplicationCapabilities: []string{fab.V1_2Capability},
+		},
+	}).Build()
+
+	// The channel config is persisted when it is queried
+	cache := NewRefCache(time.Minute, WithWarmStartStore(store))
+	defer cache.Close()
+
+	key, err := NewCacheKey(clientCtx, func(string) (fab.ChannelConfig, error) { return &mockBlockQuerier{block: block}, nil }, "mychannel")
+	require.NoError(t, err)
+
+	r, err := cache.Get(key)
+	require.NoError(t, err)
+	c, err := r.(*Ref).Get()
+	require.NoError(t, err)
+	assert.True(t, c.(fab.ChannelCfg).HasCapability(fab.ApplicationGroupKey, fab.V1_2Capability))
+
+	// After a restart the persisted channel config is served even though the peers are unavailable
+	cache2 := NewRefCache(time.Minute, WithWarmStartStore(store))
+	defer cache2.Close()
+
+	key, err = NewCacheKey(clientCtx, func(string) (fab.ChannelConfig, error) { return &mockBlockQuerier{}, nil }, "mychannel")
+	require.NoError(t, err)
+
+	r, err = cache2.Get(key)
+	require.NoError(t, err)
+	c, err = r.(*Ref).Get()
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", c.(fab.ChannelCfg).ID())
+	assert.True(t, c.(fab.ChannelCfg).HasCapability(fab.ApplicationGroupKey, fab.V1_2Capability))
+}
+
+type mockBlockQuerier struct {
+	block *common.Block
+}
+
+func (q *mockBlockQuerier) Query(reqCtx reqContext.Context) (fab.ChannelCfg, error) {
+	block, err := q.QueryBlock(reqCtx)
+	if err != nil {
+		return nil, err
+	}
+	return extractConfig("mychannel", block)
+}
+
+func (q *mockBlockQuerier) QueryBlock(reqCtx reqContext.Context) (*common.Block, error) {
+	if q.block == nil {
+		return nil, fmt.Errorf("peers are unavailable")
+	}
+	return q.block, nil
+}
+
 type badKey struct {
 	s string
 }
@@ -8,7 +8,6 @@ package chconfig
 
 import (
 	reqContext "context"
-	"math/rand"
 	"regexp"
 
 	"github.com/golang/protobuf/proto"
@@ -24,6 +23,7 @@ import (
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/random"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
@@ -73,6 +73,7 @@ type ChannelCfg struct {
 	orderers     []string
 	versions     *fab.Versions
 	capabilities map[fab.ConfigGroupKey]map[string]bool
+	hashingAlg   string
 }
 
 // NewChannelCfg creates channel cfg
@@ -111,6 +112,11 @@ func (cfg *ChannelCfg) Versions() *fab.Versions {
 	return cfg.versions
 }
 
+// HashingAlgorithm returns the name of the hashing algorithm of the channel (e.g. SHA256 or SHA3_256)
+func (cfg *ChannelCfg) HashingAlgorithm() string {
+	return cfg.hashingAlg
+}
+
 // HasCapability indicates whether or not the given group has the given capability
 func (cfg *ChannelCfg) HasCapability(group fab.ConfigGroupKey, capability string) bool {
 	groupCapabilities, ok := cfg.capabilities[group]
@@ -146,6 +152,16 @@ func New(channelID string, options ...Option) (*ChannelConfig, error) {
 
 // Query returns channel configuration
 func (c *ChannelConfig) Query(reqCtx reqContext.Context) (fab.ChannelCfg, error) {
+	block, err := c.QueryBlock(reqCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return extractConfig(c.channelID, block)
+}
+
+// QueryBlock returns the latest channel configuration block
+func (c *ChannelConfig) QueryBlock(reqCtx reqContext.Context) (*common.Block, error) {
 
 	if c.opts.Orderer != nil {
 		return c.queryOrderer(reqCtx)
@@ -154,7 +170,7 @@ func (c *ChannelConfig) Query(reqCtx reqContext.Context) (fab.ChannelCfg, error)
 	return c.queryPeers(reqCtx)
 }
 
-func (c *ChannelConfig) queryPeers(reqCtx reqContext.Context) (*ChannelCfg, error) {
+func (c *ChannelConfig) queryPeers(reqCtx reqContext.Context) (*common.Block, error) {
 	ctx, ok := contextImpl.RequestClientContext(reqCtx)
 	if !ok {
 		return nil, errors.New("failed get client context from reqContext for signPayload")
@@ -196,7 +212,7 @@ func (c *ChannelConfig) queryPeers(reqCtx reqContext.Context) (*ChannelCfg, erro
 	if err != nil {
 		return nil, errors.WithMessage(err, "QueryBlockConfig failed")
 	}
-	return extractConfig(c.channelID, block.(*common.Block))
+	return block.(*common.Block), nil
 
 }
 
@@ -207,6 +223,11 @@ func (c *ChannelConfig) calculateTargetsFromConfig(ctx context.Client) ([]fab.Pr
 		return nil, errors.New("read configuration for channel peers failed")
 	}
 
+	// An observer's organization has no peers in the channel so the peers of
+	// any organization (which grant it read access) are used
+	chSdkCfg, ok := ctx.EndpointConfig().ChannelConfig(c.channelID)
+	observer := ok && chSdkCfg.Policies.Observer.Enabled
+
 	for _, p := range chPeers {
 		newPeer, err := ctx.InfraProvider().CreatePeerFromConfig((&p.NetworkPeer))
 		if err != nil || newPeer == nil {
@@ -214,7 +235,7 @@ func (c *ChannelConfig) calculateTargetsFromConfig(ctx context.Client) ([]fab.Pr
 		}
 
 		// Pick peers in the same MSP as the context since only they can query system chaincode
-		if newPeer.MSPID() == ctx.Identifier().MSPID {
+		if observer || newPeer.MSPID() == ctx.Identifier().MSPID {
 			targets = append(targets, newPeer)
 		}
 	}
@@ -223,14 +244,14 @@ func (c *ChannelConfig) calculateTargetsFromConfig(ctx context.Client) ([]fab.Pr
 	return targets, nil
 }
 
-func (c *ChannelConfig) queryOrderer(reqCtx reqContext.Context) (*ChannelCfg, error) {
+func (c *ChannelConfig) queryOrderer(reqCtx reqContext.Context) (*common.Block, error) {
 
 	block, err := resource.LastConfigFromOrderer(reqCtx, c.channelID, c.opts.Orderer, resource.WithRetry(c.opts.RetryOpts))
 	if err != nil {
 		return nil, errors.WithMessage(err, "LastConfigFromOrderer failed")
 	}
 
-	return extractConfig(c.channelID, block)
+	return block, nil
 }
 
 //resolveOptsFromConfig loads opts from config if not loaded/initialized
@@ -530,6 +551,17 @@ func loadOrdererAddressesKey(configValue *common.ConfigValue, configItems *Chann
 
 }
 
+func loadHashingAlgorithm(configValue *common.ConfigValue, configItems *ChannelCfg, groupName string) error {
+	hashingAlgorithm := &common.HashingAlgorithm{}
+	err := proto.Unmarshal(configValue.Value, hashingAlgorithm)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal hashing algorithm from config failed")
+	}
+	logger.Debugf("loadConfigValue - %s - Hashing algorithm: %s", groupName, hashingAlgorithm.Name)
+	configItems.hashingAlg = hashingAlgorithm.Name
+	return nil
+}
+
 func loadCapabilities(configValue *common.ConfigValue, configItems *ChannelCfg, groupName string) error {
 	capabilities := &common.Capabilities{}
 	err := proto.Unmarshal(configValue.Value, capabilities)
@@ -594,14 +626,6 @@ func loadConfigValue(configItems *ChannelCfg, key string, versionsValue *common.
 	//	}
 	//	// TODO: Do something with this value
 
-	//case channelConfig.HashingAlgorithmKey:
-	//	hashingAlgorithm := &common.HashingAlgorithm{}
-	//	err := proto.Unmarshal(configValue.Value, hashingAlgorithm)
-	//	if err != nil {
-	//		return errors.Wrap(err, "unmarshal hashing algorithm from config failed")
-	//	}
-	//	// TODO: Do something with this value
-
 	//case channelConfig.ConsortiumKey:
 	//	consortium := &common.Consortium{}
 	//	err := proto.Unmarshal(configValue.Value, consortium)
@@ -622,6 +646,10 @@ func loadConfigValue(configItems *ChannelCfg, key string, versionsValue *common.
 		if err := loadOrdererAddressesKey(configValue, configItems, groupName); err != nil {
 			return err
 		}
+	case channelConfig.HashingAlgorithmKey:
+		if err := loadHashingAlgorithm(configValue, configItems, groupName); err != nil {
+			return err
+		}
 
 	default:
 	}
@@ -644,7 +672,7 @@ func randomMaxTargets(targets []fab.ProposalProcessor, max int) []fab.ProposalPr
 		return targets
 	}
 	for i := range targets {
-		j := rand.Intn(i + 1)
+		j := random.Intn(i + 1)
 		targets[i], targets[j] = targets[j], targets[i]
 	}
 	return targets[:max]
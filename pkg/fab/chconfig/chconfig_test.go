@@ -270,6 +270,7 @@ func TestCapabilities(t *testing.T) {
 	assert.Truef(t, chConfig.HasCapability(fab.ApplicationGroupKey, fab.V1_1Capability), "expecting application capability [%s] since [%s] is supported", fab.V1_1Capability, fab.V1_2Capability)
 	assert.Truef(t, chConfig.HasCapability(fab.ApplicationGroupKey, capability1), "expecting application capability [%s]", capability1)
 	assert.Falsef(t, chConfig.HasCapability(fab.ApplicationGroupKey, capability2), "not expecting application capability [%s]", capability2)
+	assert.Equal(t, "SHA256", chConfig.HashingAlgorithm())
 }
 
 func testResolveOptsDefaultValues(t *testing.T, channelID string) {
@@ -7,11 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package chconfig
 
 import (
+	reqContext "context"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/warmstart"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/pkg/errors"
 )
 
@@ -21,20 +26,47 @@ type Ref struct {
 	pvdr      Provider
 	ctx       fab.ClientContext
 	channelID string
+	store     core.KVStore
+}
+
+// RefOption is an option for the channel config reference
+type RefOption func(ref *Ref)
+
+// WithWarmStartStore persists the channel configuration block in the given store. When the reference
+// is created, the persisted configuration is served while the configuration is queried in the background.
+func WithWarmStartStore(store core.KVStore) RefOption {
+	return func(ref *Ref) {
+		ref.store = store
+	}
+}
+
+type blockQuerier interface {
+	QueryBlock(reqCtx reqContext.Context) (*common.Block, error)
 }
 
 // NewRef returns a new channel config reference
-func NewRef(refresh time.Duration, pvdr Provider, channel string, ctx fab.ClientContext) *Ref {
+func NewRef(refresh time.Duration, pvdr Provider, channel string, ctx fab.ClientContext, opts ...RefOption) *Ref {
 	cfgRef := &Ref{
 		pvdr:      pvdr,
 		ctx:       ctx,
 		channelID: channel,
 	}
 
-	cfgRef.Reference = lazyref.New(
-		cfgRef.initializer(),
-		lazyref.WithRefreshInterval(lazyref.InitImmediately, refresh),
-	)
+	for _, opt := range opts {
+		opt(cfgRef)
+	}
+
+	if cfgRef.store != nil {
+		cfgRef.Reference = warmstart.NewRef(
+			cfgRef.store, warmStartKey(channel), cfgRef.load,
+			cfgRef.initializer(), lazyref.InitImmediately, refresh,
+		)
+	} else {
+		cfgRef.Reference = lazyref.New(
+			cfgRef.initializer(),
+			lazyref.WithRefreshInterval(lazyref.InitImmediately, refresh),
+		)
+	}
 
 	return cfgRef
 }
@@ -49,11 +81,44 @@ func (ref *Ref) initializer() lazyref.Initializer {
 		reqCtx, cancel := contextImpl.NewRequest(ref.ctx, contextImpl.WithTimeoutType(fab.PeerResponse))
 		defer cancel()
 
-		chConfig, err := chConfigProvider.Query(reqCtx)
+		querier, ok := chConfigProvider.(blockQuerier)
+		if ref.store == nil || !ok {
+			chConfig, err := chConfigProvider.Query(reqCtx)
+			if err != nil {
+				return nil, err
+			}
+			return chConfig, nil
+		}
+
+		block, err := querier.QueryBlock(reqCtx)
 		if err != nil {
 			return nil, err
 		}
 
+		chConfig, err := extractConfig(ref.channelID, block)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := proto.Marshal(block)
+		if err != nil {
+			logger.Warnf("Error marshalling config block for channel [%s]: %s", ref.channelID, err)
+		} else {
+			warmstart.Save(ref.store, warmStartKey(ref.channelID), data)
+		}
+
 		return chConfig, nil
 	}
 }
+
+func (ref *Ref) load(data []byte) (interface{}, error) {
+	block := &common.Block{}
+	if err := proto.Unmarshal(data, block); err != nil {
+		return nil, errors.Wrap(err, "unmarshal config block failed")
+	}
+	return extractConfig(ref.channelID, block)
+}
+
+func warmStartKey(channelID string) string {
+	return "chconfig/" + channelID
+}
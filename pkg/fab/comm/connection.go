@@ -37,7 +37,6 @@ type GRPCConnection struct {
 	context     fabcontext.Client
 	conn        *grpc.ClientConn
 	commManager fab.CommManager
-	tlsCertHash []byte
 	done        int32
 }
 
@@ -55,6 +54,12 @@ func NewConnection(ctx fabcontext.Client, url string, opts ...options.Opt) (*GRP
 		return nil, err
 	}
 
+	target, lbOpts, err := comm.DialTarget(endpoint.ToAddress(url), params.lbPolicy)
+	if err != nil {
+		return nil, err
+	}
+	dialOpts = append(dialOpts, lbOpts...)
+
 	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(params.connectTimeout))
 	defer cancel()
 
@@ -63,13 +68,13 @@ func NewConnection(ctx fabcontext.Client, url string, opts ...options.Opt) (*GRP
 		return nil, errors.New("unable to get comm manager")
 	}
 
-	grpcconn, err := commManager.DialContext(reqCtx, endpoint.ToAddress(url), dialOpts...)
+	grpcconn, err := commManager.DialContext(reqCtx, target, dialOpts...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not connect to %s", url)
 	}
 
-	hash, err := comm.TLSCertHash(ctx.EndpointConfig())
-	if err != nil {
+	if _, err := comm.TLSCertHash(ctx.EndpointConfig()); err != nil {
+		commManager.ReleaseConn(grpcconn)
 		return nil, errors.Wrapf(err, "failed to get tls cert hash")
 	}
 
@@ -77,7 +82,6 @@ func NewConnection(ctx fabcontext.Client, url string, opts ...options.Opt) (*GRP
 		context:     ctx,
 		commManager: commManager,
 		conn:        grpcconn,
-		tlsCertHash: hash,
 	}, nil
 }
 
@@ -108,9 +112,15 @@ func (c *GRPCConnection) setClosed() bool {
 	return atomic.CompareAndSwapInt32(&c.done, 0, 1)
 }
 
-// TLSCertHash returns the hash of the TLS cert
+// TLSCertHash returns the hash of the current client TLS cert. The hash is computed on each call
+// so that it reflects a client certificate which has been rotated since the connection was created.
 func (c *GRPCConnection) TLSCertHash() []byte {
-	return c.tlsCertHash
+	hash, err := comm.TLSCertHash(c.context.EndpointConfig())
+	if err != nil {
+		logger.Warnf("Failed to get tls cert hash: %s", err)
+		return nil
+	}
+	return hash
 }
 
 // Context returns the context of the client establishing the connection
@@ -136,6 +146,7 @@ func newDialOpts(config fab.EndpointConfig, url string, params *params) ([]grpc.
 		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
 			return verifier.VerifyPeerCertificate(rawCerts, verifiedChains)
 		}
+		params.tlsOptions.Apply(tlsConfig)
 
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 		logger.Debugf("Creating a secure connection to [%s] with TLS HostOverride [%s]", url, params.hostOverride)
@@ -147,5 +158,11 @@ func newDialOpts(config fab.EndpointConfig, url string, params *params) ([]grpc.
 	dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize),
 		grpc.MaxCallSendMsgSize(maxCallSendMsgSize)))
 
+	proxyOpts, err := comm.DialOptions(params.proxyURL, params.dialer)
+	if err != nil {
+		return nil, err
+	}
+	dialOpts = append(dialOpts, proxyOpts...)
+
 	return dialOpts, nil
 }
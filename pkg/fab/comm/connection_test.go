@@ -7,9 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package comm
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	eventmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/mocks"
 	fabmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
@@ -44,6 +49,59 @@ func TestConnection(t *testing.T) {
 	conn.Close()
 }
 
+func TestLoadBalancedConnection(t *testing.T) {
+	context := newMockContext()
+
+	conn, err := NewConnection(context, peerURL, WithLoadBalancingPolicy("round_robin"))
+	if err != nil {
+		t.Fatalf("error creating new load balanced connection: %s", err)
+	}
+	conn.Close()
+
+	_, err = NewConnection(context, peerURL, WithLoadBalancingPolicy("invalid"))
+	if err == nil {
+		t.Fatal("expected error creating new connection with invalid load balancing policy")
+	}
+}
+
+func TestConnectionTLSCertHashRotation(t *testing.T) {
+	context := newMockContext()
+	config := &rotatingCertConfig{EndpointConfig: context.EndpointConfig()}
+	config.setCert([]byte("certificate 1"))
+	context.SetEndpointConfig(config)
+
+	conn, err := NewConnection(context, peerURL)
+	if err != nil {
+		t.Fatalf("error creating new connection: %s", err)
+	}
+	defer conn.Close()
+
+	hash := sha256.Sum256([]byte("certificate 1"))
+	if !bytes.Equal(conn.TLSCertHash(), hash[:]) {
+		t.Fatal("expected hash of the initial client certificate")
+	}
+
+	config.setCert([]byte("certificate 2"))
+
+	hash = sha256.Sum256([]byte("certificate 2"))
+	if !bytes.Equal(conn.TLSCertHash(), hash[:]) {
+		t.Fatal("expected hash of the rotated client certificate")
+	}
+}
+
+type rotatingCertConfig struct {
+	fab.EndpointConfig
+	certs atomic.Value
+}
+
+func (c *rotatingCertConfig) setCert(cert []byte) {
+	c.certs.Store([]tls.Certificate{{Certificate: [][]byte{cert}}})
+}
+
+func (c *rotatingCertConfig) TLSClientCerts() []tls.Certificate {
+	return c.certs.Load().([]tls.Certificate)
+}
+
 // Use the mock deliver server for testing
 var testServer *eventmocks.MockDeliverServer
 var endorserAddr []string
@@ -12,6 +12,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/spf13/cast"
 	"google.golang.org/grpc/keepalive"
 )
@@ -23,6 +24,10 @@ type params struct {
 	failFast        bool
 	insecure        bool
 	connectTimeout  time.Duration
+	lbPolicy        string
+	proxyURL        string
+	dialer          comm.Dialer
+	tlsOptions      *comm.TLSOptions
 }
 
 func defaultParams() *params {
@@ -87,6 +92,44 @@ func WithInsecure() options.Opt {
 	}
 }
 
+// WithLoadBalancingPolicy sets the gRPC load balancing policy (e.g. round_robin). If set, the host name of the
+// URL is resolved with DNS and requests are balanced across all of the resolved addresses.
+func WithLoadBalancingPolicy(value string) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(loadBalancingPolicySetter); ok {
+			setter.SetLoadBalancingPolicy(value)
+		}
+	}
+}
+
+// WithProxyURL sets the URL of the SOCKS5 or HTTP CONNECT proxy through which the connection is established
+func WithProxyURL(value string) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(proxyURLSetter); ok {
+			setter.SetProxyURL(value)
+		}
+	}
+}
+
+// WithDialer sets the custom dialer with which the connection is established
+func WithDialer(value comm.Dialer) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(dialerSetter); ok {
+			setter.SetDialer(value)
+		}
+	}
+}
+
+// WithTLSOptions sets the TLS versions and cipher suites allowed for the connection as well as the additional
+// verifications (pinning, custom callback) of the server certificate
+func WithTLSOptions(value *comm.TLSOptions) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(tlsOptionsSetter); ok {
+			setter.SetTLSOptions(value)
+		}
+	}
+}
+
 func (p *params) SetHostOverride(value string) {
 	logger.Debugf("HostOverride: %s", value)
 	p.hostOverride = value
@@ -121,6 +164,26 @@ func (p *params) SetInsecure(value bool) {
 	p.insecure = value
 }
 
+func (p *params) SetLoadBalancingPolicy(value string) {
+	logger.Debugf("LoadBalancingPolicy: %s", value)
+	p.lbPolicy = value
+}
+
+func (p *params) SetProxyURL(value string) {
+	logger.Debugf("ProxyURL: %s", value)
+	p.proxyURL = value
+}
+
+func (p *params) SetDialer(value comm.Dialer) {
+	logger.Debugf("Dialer set: %t", value != nil)
+	p.dialer = value
+}
+
+func (p *params) SetTLSOptions(value *comm.TLSOptions) {
+	logger.Debugf("TLSOptions: %+v", value)
+	p.tlsOptions = value
+}
+
 type hostOverrideSetter interface {
 	SetHostOverride(value string)
 }
@@ -145,6 +208,22 @@ type connectTimeoutSetter interface {
 	SetConnectTimeout(value time.Duration)
 }
 
+type loadBalancingPolicySetter interface {
+	SetLoadBalancingPolicy(value string)
+}
+
+type proxyURLSetter interface {
+	SetProxyURL(value string)
+}
+
+type dialerSetter interface {
+	SetDialer(value comm.Dialer)
+}
+
+type tlsOptionsSetter interface {
+	SetTLSOptions(value *comm.TLSOptions)
+}
+
 // OptsFromPeerConfig returns a set of connection options from the given peer config
 func OptsFromPeerConfig(peerCfg *fab.PeerConfig) []options.Opt {
 
@@ -153,11 +232,27 @@ func OptsFromPeerConfig(peerCfg *fab.PeerConfig) []options.Opt {
 		WithFailFast(getFailFast(peerCfg)),
 		WithKeepAliveParams(getKeepAliveOptions(peerCfg)),
 		WithCertificate(peerCfg.TLSCACert),
+		WithLoadBalancingPolicy(comm.LoadBalancingPolicy(peerCfg.GRPCOptions)),
+		WithProxyURL(comm.ProxyURL(peerCfg.GRPCOptions)),
 	}
 	if isInsecureAllowed(peerCfg) {
 		opts = append(opts, WithInsecure())
 	}
 
+	tlsOptions, err := comm.TLSOptionsFromGRPC(peerCfg.GRPCOptions)
+	if err != nil {
+		logger.Warnf("Ignoring invalid TLS options of peer [%s]: %s", peerCfg.URL, err)
+	} else if tlsOptions != nil {
+		opts = append(opts, WithTLSOptions(tlsOptions))
+	}
+
+	dialer, err := comm.DialerFromGRPC(peerCfg.GRPCOptions)
+	if err != nil {
+		logger.Warnf("Ignoring invalid dialer of peer [%s]: %s", peerCfg.URL, err)
+	} else if dialer != nil {
+		opts = append(opts, WithDialer(dialer))
+	}
+
 	return opts
 }
 
@@ -8,6 +8,7 @@ package comm
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -51,6 +52,18 @@ type cachedConn struct {
 	lastClose time.Time
 }
 
+// ConnectionInfo describes a cached connection
+type ConnectionInfo struct {
+	// Target is the address of the endpoint
+	Target string
+	// State is the connectivity state of the connection (e.g. READY or TRANSIENT_FAILURE)
+	State string
+	// Open is the number of users of the connection
+	Open int
+	// LastClose is the time at which the connection was last released (zero if it was never released)
+	LastClose time.Time
+}
+
 // NewCachingConnector creates a GRPC connection cache. The cache is governed by
 // sweepTime and idleTime.
 func NewCachingConnector(sweepTime time.Duration, idleTime time.Duration) *CachingConnector {
@@ -135,6 +148,24 @@ func (cc *CachingConnector) DialContext(ctx context.Context, target string, opts
 	return c.conn, nil
 }
 
+// Connections returns information about the cached connections sorted by target
+func (cc *CachingConnector) Connections() []ConnectionInfo {
+	cc.lock.RLock()
+	defer cc.lock.RUnlock()
+
+	var conns []ConnectionInfo
+	for _, c := range cc.index {
+		conns = append(conns, ConnectionInfo{
+			Target:    c.target,
+			State:     c.conn.GetState().String(),
+			Open:      c.open,
+			LastClose: c.lastClose,
+		})
+	}
+	sort.Slice(conns, func(i, j int) bool { return conns[i].Target < conns[j].Target })
+	return conns
+}
+
 // ReleaseConn notifies the cache that the connection is no longer in use.
 func (cc *CachingConnector) ReleaseConn(conn *grpc.ClientConn) {
 	cc.lock.Lock()
@@ -59,6 +59,23 @@ func TestConnectorHappyPath(t *testing.T) {
 
 	assert.Nil(t, err, "DialContext should have succeeded")
 	assert.NotEqual(t, unsafe.Pointer(conn1), unsafe.Pointer(conn3), "connections should not match")
+
+	conns := connector.Connections()
+	require.Len(t, conns, 2, "expecting two cached connections")
+	for _, c := range conns {
+		assert.Contains(t, endorserAddr[:2], c.Target)
+		assert.Equal(t, connectivity.Ready.String(), c.State)
+	}
+	connector.ReleaseConn(conn3)
+	conns = connector.Connections()
+	for _, c := range conns {
+		if c.Target == endorserAddr[1] {
+			assert.Equal(t, 0, c.Open, "connection should have no users")
+			assert.False(t, c.LastClose.IsZero(), "last close time should be set")
+		} else {
+			assert.Equal(t, 2, c.Open, "connection should have two users")
+		}
+	}
 }
 
 func TestConnectorDoubleClose(t *testing.T) {
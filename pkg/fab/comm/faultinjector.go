@@ -0,0 +1,237 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+const (
+	// AllEndpoints may be used as the target of a fault in order to inject the fault into all endpoints
+	AllEndpoints = "*"
+
+	processProposalMethod = "/protos.Endorser/ProcessProposal"
+)
+
+// Fault describes the faults injected into the GRPC calls to an endpoint
+type Fault struct {
+	// Latency is added to each call (and to each message received on a stream)
+	Latency time.Duration
+
+	// ErrorRate is the probability (0 to 1) that a call fails with ErrorCode. For streams
+	// the error is also injected into received messages in order to simulate connection resets.
+	ErrorRate float64
+
+	// ErrorCode is the GRPC status code of injected errors (Unavailable if not set)
+	ErrorCode codes.Code
+
+	// FailDial causes connections to the endpoint to fail
+	FailDial bool
+
+	// EndorsementFailureRate is the probability (0 to 1) that a proposal sent to the endpoint
+	// is rejected by the endorser with EndorsementFailureStatus
+	EndorsementFailureRate float64
+
+	// EndorsementFailureStatus is the status of injected endorsement failures (500 if not set)
+	EndorsementFailureStatus int32
+}
+
+// FaultInjector is a CommManager that injects faults (latency, connection resets, failed dials and
+// endorsement failures) into the GRPC calls to selected endpoints. It wraps the comm manager that
+// creates the connections and is intended for testing the retry logic of applications.
+//
+// Faults may be enabled and cleared at any time since they are evaluated on each call.
+type FaultInjector struct {
+	fab.CommManager
+	mutex  sync.RWMutex
+	faults map[string]Fault
+	rand   *rand.Rand
+}
+
+// NewFaultInjector returns a new fault injector which wraps the given comm manager
+func NewFaultInjector(commManager fab.CommManager) *FaultInjector {
+	return &FaultInjector{
+		CommManager: commManager,
+		faults:      make(map[string]Fault),
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())), // nolint: gas
+	}
+}
+
+// Inject enables the given fault for the given target (host:port) or for AllEndpoints
+func (f *FaultInjector) Inject(target string, fault Fault) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	logger.Debugf("Injecting fault for target [%s]: %+v", target, fault)
+	f.faults[target] = fault
+}
+
+// Clear removes the fault for the given target
+func (f *FaultInjector) Clear(target string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.faults, target)
+}
+
+// ClearAll removes all faults
+func (f *FaultInjector) ClearAll() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.faults = make(map[string]Fault)
+}
+
+// DialContext creates a connection (using the wrapped comm manager) with interceptors that inject
+// the faults for the given target
+func (f *FaultInjector) DialContext(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if fault, ok := f.fault(target); ok && fault.FailDial {
+		logger.Debugf("Injecting dial failure for target [%s]", target)
+		return nil, grpcstatus.Errorf(fault.errorCode(), "injected dial failure for target [%s]", target)
+	}
+
+	opts = append(opts,
+		grpc.WithUnaryInterceptor(f.unaryInterceptor(target)),
+		grpc.WithStreamInterceptor(f.streamInterceptor(target)),
+	)
+
+	return f.CommManager.DialContext(ctx, target, opts...)
+}
+
+func (f *FaultInjector) fault(target string) (Fault, bool) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if fault, ok := f.faults[target]; ok {
+		return fault, true
+	}
+	fault, ok := f.faults[AllEndpoints]
+	return fault, ok
+}
+
+// occurs returns true with the given probability
+func (f *FaultInjector) occurs(probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.rand.Float64() < probability
+}
+
+func (f *FaultInjector) unaryInterceptor(target string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		fault, ok := f.fault(target)
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		if err := delay(ctx, fault.Latency); err != nil {
+			return err
+		}
+
+		if f.occurs(fault.ErrorRate) {
+			logger.Debugf("Injecting error for method [%s] on target [%s]", method, target)
+			return grpcstatus.Errorf(fault.errorCode(), "injected error for target [%s]", target)
+		}
+
+		if method == processProposalMethod && f.occurs(fault.EndorsementFailureRate) {
+			if resp, ok := reply.(*pb.ProposalResponse); ok {
+				logger.Debugf("Injecting endorsement failure on target [%s]", target)
+				resp.Response = &pb.Response{Status: fault.endorsementFailureStatus(), Message: "injected endorsement failure"}
+				return nil
+			}
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func (f *FaultInjector) streamInterceptor(target string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if fault, ok := f.fault(target); ok {
+			if err := delay(ctx, fault.Latency); err != nil {
+				return nil, err
+			}
+			if f.occurs(fault.ErrorRate) {
+				logger.Debugf("Injecting stream error for method [%s] on target [%s]", method, target)
+				return nil, grpcstatus.Errorf(fault.errorCode(), "injected error for target [%s]", target)
+			}
+		}
+
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &faultStream{ClientStream: stream, injector: f, target: target}, nil
+	}
+}
+
+// faultStream injects faults into the messages received on a stream
+type faultStream struct {
+	grpc.ClientStream
+	injector *FaultInjector
+	target   string
+}
+
+// RecvMsg receives a message from the stream. A connection reset is simulated
+// by returning an error instead of the message.
+func (s *faultStream) RecvMsg(m interface{}) error {
+	fault, ok := s.injector.fault(s.target)
+	if !ok {
+		return s.ClientStream.RecvMsg(m)
+	}
+
+	if err := delay(s.Context(), fault.Latency); err != nil {
+		return err
+	}
+
+	if s.injector.occurs(fault.ErrorRate) {
+		logger.Debugf("Injecting connection reset on target [%s]", s.target)
+		return grpcstatus.Errorf(fault.errorCode(), "injected connection reset for target [%s]", s.target)
+	}
+
+	return s.ClientStream.RecvMsg(m)
+}
+
+func (f Fault) errorCode() codes.Code {
+	if f.ErrorCode == codes.OK {
+		return codes.Unavailable
+	}
+	return f.ErrorCode
+}
+
+func (f Fault) endorsementFailureStatus() int32 {
+	if f.EndorsementFailureStatus == 0 {
+		return 500
+	}
+	return f.EndorsementFailureStatus
+}
+
+func delay(ctx context.Context, latency time.Duration) error {
+	if latency <= 0 {
+		return nil
+	}
+
+	select {
+	case <-time.After(latency):
+		return nil
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return grpcstatus.Error(codes.DeadlineExceeded, ctx.Err().Error())
+		}
+		return grpcstatus.Error(codes.Canceled, ctx.Err().Error())
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+func processProposal(t *testing.T, injector *FaultInjector, target string) (*pb.ProposalResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	defer cancel()
+
+	conn, err := injector.DialContext(ctx, target, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer injector.ReleaseConn(conn)
+
+	return pb.NewEndorserClient(conn).ProcessProposal(ctx, &pb.SignedProposal{})
+}
+
+func TestFaultInjectorErrors(t *testing.T) {
+	injector := NewFaultInjector(&MockCommManager{})
+
+	resp, err := processProposal(t, injector, endorserAddr[0])
+	require.NoError(t, err)
+	assert.Equal(t, int32(200), resp.Response.Status)
+
+	injector.Inject(endorserAddr[0], Fault{ErrorRate: 1})
+	_, err = processProposal(t, injector, endorserAddr[0])
+	require.Error(t, err)
+	assert.Equal(t, codes.Unavailable, grpcstatus.Code(err))
+
+	_, err = processProposal(t, injector, endorserAddr[1])
+	assert.NoError(t, err, "fault should only be injected for the given target")
+
+	injector.Inject(AllEndpoints, Fault{ErrorRate: 1, ErrorCode: codes.ResourceExhausted})
+	_, err = processProposal(t, injector, endorserAddr[1])
+	assert.Equal(t, codes.ResourceExhausted, grpcstatus.Code(err))
+
+	injector.ClearAll()
+	_, err = processProposal(t, injector, endorserAddr[0])
+	assert.NoError(t, err)
+}
+
+func TestFaultInjectorEndorsementFailure(t *testing.T) {
+	injector := NewFaultInjector(&MockCommManager{})
+
+	injector.Inject(endorserAddr[0], Fault{EndorsementFailureRate: 1})
+	resp, err := processProposal(t, injector, endorserAddr[0])
+	require.NoError(t, err)
+	assert.Equal(t, int32(500), resp.Response.Status)
+
+	injector.Inject(endorserAddr[0], Fault{EndorsementFailureRate: 1, EndorsementFailureStatus: 403})
+	resp, err = processProposal(t, injector, endorserAddr[0])
+	require.NoError(t, err)
+	assert.Equal(t, int32(403), resp.Response.Status)
+
+	injector.Clear(endorserAddr[0])
+	resp, err = processProposal(t, injector, endorserAddr[0])
+	require.NoError(t, err)
+	assert.Equal(t, int32(200), resp.Response.Status)
+}
+
+func TestFaultInjectorLatencyAndDial(t *testing.T) {
+	injector := NewFaultInjector(&MockCommManager{})
+
+	injector.Inject(endorserAddr[0], Fault{Latency: 100 * time.Millisecond})
+	start := time.Now()
+	_, err := processProposal(t, injector, endorserAddr[0])
+	require.NoError(t, err)
+	assert.True(t, time.Since(start) >= 100*time.Millisecond, "expecting latency to be injected")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	conn, err := injector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	require.NoError(t, err)
+	defer injector.ReleaseConn(conn)
+	_, err = pb.NewEndorserClient(conn).ProcessProposal(ctx, &pb.SignedProposal{})
+	assert.Equal(t, codes.DeadlineExceeded, grpcstatus.Code(err))
+
+	injector.Inject(endorserAddr[1], Fault{FailDial: true})
+	_, err = injector.DialContext(context.Background(), endorserAddr[1], grpc.WithInsecure())
+	assert.Equal(t, codes.Unavailable, grpcstatus.Code(err))
+}
+
+func TestFaultInjectorStream(t *testing.T) {
+	injector := NewFaultInjector(&MockCommManager{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	defer cancel()
+
+	conn, err := injector.DialContext(ctx, peerAddress, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer injector.ReleaseConn(conn)
+
+	injector.Inject(peerAddress, Fault{ErrorRate: 1})
+	_, err = pb.NewDeliverClient(conn).Deliver(ctx)
+	assert.Equal(t, codes.Unavailable, grpcstatus.Code(err))
+
+	injector.Clear(peerAddress)
+	stream, err := pb.NewDeliverClient(conn).Deliver(ctx)
+	require.NoError(t, err)
+
+	injector.Inject(peerAddress, Fault{ErrorRate: 1, ErrorCode: codes.Internal})
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Internal, grpcstatus.Code(err), "expecting injected connection reset")
+}
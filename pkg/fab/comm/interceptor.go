@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"google.golang.org/grpc"
+)
+
+// Interceptors is a CommManager that adds the registered unary and stream client interceptors to the GRPC
+// connections (to peers and orderers) created by the wrapped comm manager. Interceptors may be registered
+// for a target (host:port) or for AllEndpoints, e.g. in order to add auth tokens or custom metadata to
+// outgoing calls or to collect telemetry.
+//
+// The interceptors are looked up on each call so they may also be registered after a (cached) connection
+// has been created. The interceptors for AllEndpoints are invoked before the ones for the target and each
+// set is invoked in the order of registration.
+//
+// Note that a GRPC connection has a single unary and a single stream interceptor so this comm manager
+// mustn't be combined with another comm manager which installs interceptors (e.g. the FaultInjector).
+type Interceptors struct {
+	fab.CommManager
+	mutex  sync.RWMutex
+	unary  map[string][]grpc.UnaryClientInterceptor
+	stream map[string][]grpc.StreamClientInterceptor
+}
+
+// NewInterceptors returns a new comm manager which wraps the given comm manager
+func NewInterceptors(commManager fab.CommManager) *Interceptors {
+	return &Interceptors{
+		CommManager: commManager,
+		unary:       make(map[string][]grpc.UnaryClientInterceptor),
+		stream:      make(map[string][]grpc.StreamClientInterceptor),
+	}
+}
+
+// AddUnaryInterceptor registers unary client interceptors for the given target (host:port) or for AllEndpoints
+func (i *Interceptors) AddUnaryInterceptor(target string, interceptors ...grpc.UnaryClientInterceptor) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	logger.Debugf("Adding %d unary interceptor(s) for target [%s]", len(interceptors), target)
+	i.unary[target] = append(i.unary[target], interceptors...)
+}
+
+// AddStreamInterceptor registers stream client interceptors for the given target (host:port) or for AllEndpoints
+func (i *Interceptors) AddStreamInterceptor(target string, interceptors ...grpc.StreamClientInterceptor) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	logger.Debugf("Adding %d stream interceptor(s) for target [%s]", len(interceptors), target)
+	i.stream[target] = append(i.stream[target], interceptors...)
+}
+
+// DialContext creates a connection (using the wrapped comm manager) which invokes the interceptors
+// registered for the given target
+func (i *Interceptors) DialContext(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append(opts,
+		grpc.WithUnaryInterceptor(i.unaryInterceptor(target)),
+		grpc.WithStreamInterceptor(i.streamInterceptor(target)),
+	)
+
+	return i.CommManager.DialContext(ctx, target, opts...)
+}
+
+func (i *Interceptors) unaryInterceptors(target string) []grpc.UnaryClientInterceptor {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	var interceptors []grpc.UnaryClientInterceptor
+	interceptors = append(interceptors, i.unary[AllEndpoints]...)
+	if target != AllEndpoints {
+		interceptors = append(interceptors, i.unary[target]...)
+	}
+	return interceptors
+}
+
+func (i *Interceptors) streamInterceptors(target string) []grpc.StreamClientInterceptor {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	var interceptors []grpc.StreamClientInterceptor
+	interceptors = append(interceptors, i.stream[AllEndpoints]...)
+	if target != AllEndpoints {
+		interceptors = append(interceptors, i.stream[target]...)
+	}
+	return interceptors
+}
+
+// unaryInterceptor chains the unary interceptors of the target such that each interceptor invokes the next one
+func (i *Interceptors) unaryInterceptor(target string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		interceptors := i.unaryInterceptors(target)
+
+		var next func(n int) grpc.UnaryInvoker
+		next = func(n int) grpc.UnaryInvoker {
+			if n == len(interceptors) {
+				return invoker
+			}
+			return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				return interceptors[n](ctx, method, req, reply, cc, next(n+1), opts...)
+			}
+		}
+
+		return next(0)(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// streamInterceptor chains the stream interceptors of the target such that each interceptor invokes the next one
+func (i *Interceptors) streamInterceptor(target string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		interceptors := i.streamInterceptors(target)
+
+		var next func(n int) grpc.Streamer
+		next = func(n int) grpc.Streamer {
+			if n == len(interceptors) {
+				return streamer
+			}
+			return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return interceptors[n](ctx, desc, cc, method, next(n+1), opts...)
+			}
+		}
+
+		return next(0)(ctx, desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+type callRecorder struct {
+	mutex sync.Mutex
+	calls []string
+}
+
+func (r *callRecorder) record(call string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *callRecorder) recorded() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string{}, r.calls...)
+}
+
+func (r *callRecorder) unary(name string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		r.record(name)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func (r *callRecorder) stream(name string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		r.record(name)
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+func dialAndProcessProposal(t *testing.T, interceptors *Interceptors, target string) (*pb.ProposalResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	defer cancel()
+
+	conn, err := interceptors.DialContext(ctx, target, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer interceptors.ReleaseConn(conn)
+
+	return pb.NewEndorserClient(conn).ProcessProposal(ctx, &pb.SignedProposal{})
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	interceptors := NewInterceptors(&MockCommManager{})
+	recorder := &callRecorder{}
+
+	interceptors.AddUnaryInterceptor(endorserAddr[0], recorder.unary("target1"), recorder.unary("target2"))
+	interceptors.AddUnaryInterceptor(AllEndpoints, recorder.unary("all"))
+
+	_, err := dialAndProcessProposal(t, interceptors, endorserAddr[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"all", "target1", "target2"}, recorder.recorded(), "expecting global interceptors to be invoked first")
+
+	recorder.calls = nil
+	_, err = dialAndProcessProposal(t, interceptors, endorserAddr[1])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"all"}, recorder.recorded())
+
+	interceptors.AddUnaryInterceptor(endorserAddr[1], func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return grpcstatus.Error(codes.Unauthenticated, "missing token")
+	})
+	_, err = dialAndProcessProposal(t, interceptors, endorserAddr[1])
+	assert.Equal(t, codes.Unauthenticated, grpcstatus.Code(err), "expecting interceptor to short-circuit the call")
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	interceptors := NewInterceptors(&MockCommManager{})
+	recorder := &callRecorder{}
+
+	interceptors.AddStreamInterceptor(AllEndpoints, recorder.stream("all"))
+	interceptors.AddStreamInterceptor(endorserAddr[0], recorder.stream("target"))
+
+	stream := interceptors.streamInterceptor(endorserAddr[0])
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		recorder.record("streamer")
+		return nil, nil
+	}
+
+	_, err := stream(context.Background(), &grpc.StreamDesc{}, nil, "/protos.Deliver/Deliver", streamer)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"all", "target", "streamer"}, recorder.recorded())
+}
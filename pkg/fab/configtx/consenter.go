@@ -0,0 +1,250 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer/etcdraft"
+)
+
+const (
+	// ConsensusTypeEtcdRaft is the consensus type of a Raft ordering service
+	ConsensusTypeEtcdRaft = "etcdraft"
+
+	// ConsensusTypeBFT is the consensus type of a BFT ordering service
+	ConsensusTypeBFT = "BFT"
+
+	// OrderersKey is the key of the orderer group value which holds the consenter mapping of a BFT ordering service
+	OrderersKey = "Orderers"
+)
+
+// Consenter is a consenter (ordering node) of a Raft or BFT ordering service.
+// ID, MSPID and Identity only apply to BFT consenters.
+type Consenter struct {
+	ID            uint32 `json:"id,omitempty"`
+	Host          string `json:"host"`
+	Port          uint32 `json:"port"`
+	MSPID         string `json:"mspId,omitempty"`
+	Identity      []byte `json:"identity,omitempty"`
+	ClientTLSCert []byte `json:"clientTlsCert,omitempty"`
+	ServerTLSCert []byte `json:"serverTlsCert,omitempty"`
+}
+
+// Consenters returns the consenters of the updated configuration. An error is returned if the
+// ordering service uses neither Raft nor BFT.
+func (e *Editor) Consenters() ([]Consenter, error) {
+	ordererGroup, err := e.group(OrdererGroupKey)
+	if err != nil {
+		return nil, err
+	}
+
+	consensusType, err := consenterConsensusType(ordererGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeConsenters(ordererGroup, consensusType)
+}
+
+// AddConsenter adds the given consenter to the Raft or BFT ordering service. The consenter of a BFT
+// ordering service also requires the MSP ID and the (serialized) identity of the ordering node; if its
+// ID is zero then the next free ID is assigned. Note that the address of the new ordering node should
+// also be added to the orderer addresses of the channel.
+func (e *Editor) AddConsenter(consenter Consenter) error {
+	if consenter.Host == "" || consenter.Port == 0 {
+		return errors.New("consenter host and port are required")
+	}
+	if len(consenter.ClientTLSCert) == 0 || len(consenter.ServerTLSCert) == 0 {
+		return errors.New("consenter client and server TLS certificates are required")
+	}
+
+	return e.updateConsenters(func(consenters []Consenter, bft bool) ([]Consenter, error) {
+		if bft && (consenter.MSPID == "" || len(consenter.Identity) == 0) {
+			return nil, errors.New("BFT consenter MSP ID and identity are required")
+		}
+
+		var maxID uint32
+		for _, c := range consenters {
+			if c.Host == consenter.Host && c.Port == consenter.Port {
+				return nil, errors.Errorf("consenter [%s:%d] already exists", consenter.Host, consenter.Port)
+			}
+			if bft && consenter.ID != 0 && c.ID == consenter.ID {
+				return nil, errors.Errorf("consenter ID [%d] already exists", consenter.ID)
+			}
+			if c.ID > maxID {
+				maxID = c.ID
+			}
+		}
+		if bft && consenter.ID == 0 {
+			consenter.ID = maxID + 1
+		}
+		return append(consenters, consenter), nil
+	})
+}
+
+// RemoveConsenter removes the consenter with the given host and port from the Raft or BFT ordering service
+func (e *Editor) RemoveConsenter(host string, port uint32) error {
+	return e.updateConsenters(func(consenters []Consenter, bft bool) ([]Consenter, error) {
+		for i, c := range consenters {
+			if c.Host == host && c.Port == port {
+				consenters = append(consenters[:i], consenters[i+1:]...)
+				if len(consenters) == 0 {
+					return nil, errors.New("cannot remove the last consenter")
+				}
+				return consenters, nil
+			}
+		}
+		return nil, errors.Errorf("consenter [%s:%d] not found", host, port)
+	})
+}
+
+// updateConsenters updates the consenters of a Raft ordering service, which are held in the metadata
+// of the consensus type, or of a BFT ordering service, which are held in the Orderers value
+func (e *Editor) updateConsenters(update func(consenters []Consenter, bft bool) ([]Consenter, error)) error {
+	ordererGroup, err := e.group(OrdererGroupKey)
+	if err != nil {
+		return err
+	}
+
+	consensusType, err := consenterConsensusType(ordererGroup)
+	if err != nil {
+		return err
+	}
+
+	consenters, err := decodeConsenters(ordererGroup, consensusType)
+	if err != nil {
+		return err
+	}
+
+	bft := consensusType.Type == ConsensusTypeBFT
+	consenters, err = update(consenters, bft)
+	if err != nil {
+		return err
+	}
+
+	if bft {
+		return setValue(ordererGroup, OrderersKey, &common.Orderers{ConsenterMapping: toBFTConsenters(consenters)})
+	}
+
+	metadata := &etcdraft.ConfigMetadata{}
+	if err := proto.Unmarshal(consensusType.Metadata, metadata); err != nil {
+		return errors.Wrap(err, "unmarshal etcdraft metadata failed")
+	}
+	metadata.Consenters = toRaftConsenters(consenters)
+
+	consensusType.Metadata, err = proto.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "marshal etcdraft metadata failed")
+	}
+
+	return setValue(ordererGroup, channelConfig.ConsensusTypeKey, consensusType)
+}
+
+// consenterConsensusType returns the consensus type of the orderer group, which must be either Raft or BFT
+func consenterConsensusType(ordererGroup *common.ConfigGroup) (*ab.ConsensusType, error) {
+	value, ok := ordererGroup.Values[channelConfig.ConsensusTypeKey]
+	if !ok {
+		return nil, errors.New("consensus type not found in orderer config")
+	}
+
+	consensusType := &ab.ConsensusType{}
+	if err := proto.Unmarshal(value.Value, consensusType); err != nil {
+		return nil, errors.Wrap(err, "unmarshal consensus type failed")
+	}
+	if consensusType.Type != ConsensusTypeEtcdRaft && consensusType.Type != ConsensusTypeBFT {
+		return nil, errors.Errorf("consenters are not supported for consensus type [%s]", consensusType.Type)
+	}
+
+	return consensusType, nil
+}
+
+// decodeConsenters returns the consenters of the orderer group, if any, for the given consensus type
+func decodeConsenters(ordererGroup *common.ConfigGroup, consensusType *ab.ConsensusType) ([]Consenter, error) {
+	switch consensusType.Type {
+	case ConsensusTypeEtcdRaft:
+		metadata := &etcdraft.ConfigMetadata{}
+		if err := proto.Unmarshal(consensusType.Metadata, metadata); err != nil {
+			return nil, errors.Wrap(err, "unmarshal etcdraft metadata failed")
+		}
+		return fromRaftConsenters(metadata.Consenters), nil
+	case ConsensusTypeBFT:
+		value, ok := ordererGroup.Values[OrderersKey]
+		if !ok {
+			return nil, nil
+		}
+		orderers := &common.Orderers{}
+		if err := proto.Unmarshal(value.Value, orderers); err != nil {
+			return nil, errors.Wrap(err, "unmarshal orderers failed")
+		}
+		return fromBFTConsenters(orderers.ConsenterMapping), nil
+	default:
+		return nil, nil
+	}
+}
+
+func fromRaftConsenters(raftConsenters []*etcdraft.Consenter) []Consenter {
+	var consenters []Consenter
+	for _, c := range raftConsenters {
+		consenters = append(consenters, Consenter{
+			Host:          c.Host,
+			Port:          c.Port,
+			ClientTLSCert: c.ClientTlsCert,
+			ServerTLSCert: c.ServerTlsCert,
+		})
+	}
+	return consenters
+}
+
+func toRaftConsenters(consenters []Consenter) []*etcdraft.Consenter {
+	var raftConsenters []*etcdraft.Consenter
+	for _, c := range consenters {
+		raftConsenters = append(raftConsenters, &etcdraft.Consenter{
+			Host:          c.Host,
+			Port:          c.Port,
+			ClientTlsCert: c.ClientTLSCert,
+			ServerTlsCert: c.ServerTLSCert,
+		})
+	}
+	return raftConsenters
+}
+
+func fromBFTConsenters(bftConsenters []*common.Consenter) []Consenter {
+	var consenters []Consenter
+	for _, c := range bftConsenters {
+		consenters = append(consenters, Consenter{
+			ID:            c.Id,
+			Host:          c.Host,
+			Port:          c.Port,
+			MSPID:         c.MspId,
+			Identity:      c.Identity,
+			ClientTLSCert: c.ClientTlsCert,
+			ServerTLSCert: c.ServerTlsCert,
+		})
+	}
+	return consenters
+}
+
+func toBFTConsenters(consenters []Consenter) []*common.Consenter {
+	var bftConsenters []*common.Consenter
+	for _, c := range consenters {
+		bftConsenters = append(bftConsenters, &common.Consenter{
+			Id:            c.ID,
+			Host:          c.Host,
+			Port:          c.Port,
+			MspId:         c.MSPID,
+			Identity:      c.Identity,
+			ClientTlsCert: c.ClientTLSCert,
+			ServerTlsCert: c.ServerTLSCert,
+		})
+	}
+	return bftConsenters
+}
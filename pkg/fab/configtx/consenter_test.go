@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer/etcdraft"
+)
+
+func newRaftConfig(t *testing.T, consenters ...*etcdraft.Consenter) *Config {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org1MSP"},
+			OrdererAddress: "localhost:7050",
+			RootCA:         "root-ca",
+		},
+	}
+
+	config, err := DecodeConfigBlock(builder.Build())
+	require.NoError(t, err)
+	config.ChannelID = "mychannel"
+
+	metadata, err := proto.Marshal(&etcdraft.ConfigMetadata{Consenters: consenters})
+	require.NoError(t, err)
+	consensusType, err := proto.Marshal(&ab.ConsensusType{Type: ConsensusTypeEtcdRaft, Metadata: metadata})
+	require.NoError(t, err)
+	config.Raw().ChannelGroup.Groups[OrdererGroupKey].Values["ConsensusType"].Value = consensusType
+
+	return config
+}
+
+func TestEditorConsenters(t *testing.T) {
+	config := newRaftConfig(t, &etcdraft.Consenter{Host: "orderer1.example.com", Port: 7050, ClientTlsCert: []byte("c1"), ServerTlsCert: []byte("s1")})
+
+	decoded, err := DecodeConfig(config.Raw())
+	require.NoError(t, err)
+	assert.Equal(t, []Consenter{{Host: "orderer1.example.com", Port: 7050, ClientTLSCert: []byte("c1"), ServerTLSCert: []byte("s1")}}, decoded.Orderer.Consenters)
+
+	editor, err := NewEditor(config)
+	require.NoError(t, err)
+
+	consenter2 := Consenter{Host: "orderer2.example.com", Port: 7050, ClientTLSCert: []byte("c2"), ServerTLSCert: []byte("s2")}
+	require.NoError(t, editor.AddConsenter(consenter2))
+	assert.Error(t, editor.AddConsenter(consenter2), "expecting error for duplicate consenter")
+	assert.Error(t, editor.AddConsenter(Consenter{Host: "orderer3.example.com", Port: 7050}), "expecting error for missing TLS certs")
+
+	consenters, err := editor.Consenters()
+	require.NoError(t, err)
+	assert.Len(t, consenters, 2)
+
+	require.NoError(t, editor.RemoveConsenter("orderer1.example.com", 7050))
+	assert.Error(t, editor.RemoveConsenter("orderer1.example.com", 7050), "expecting error for unknown consenter")
+	assert.Error(t, editor.RemoveConsenter("orderer2.example.com", 7050), "expecting error for removing the last consenter")
+
+	consenters, err = editor.Consenters()
+	require.NoError(t, err)
+	assert.Equal(t, []Consenter{consenter2}, consenters)
+
+	update, err := editor.ComputeUpdate()
+	require.NoError(t, err)
+	value := update.WriteSet.Groups[OrdererGroupKey].Values["ConsensusType"]
+	require.NotNil(t, value)
+	assert.Equal(t, uint64(1), value.Version)
+}
+
+func TestEditorConsentersUnsupportedType(t *testing.T) {
+	editor := newTestEditor(t)
+
+	_, err := editor.Consenters()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "consenters are not supported")
+
+	err = editor.AddConsenter(Consenter{Host: "orderer2.example.com", Port: 7050, ClientTLSCert: []byte("c2"), ServerTLSCert: []byte("s2")})
+	assert.Error(t, err)
+}
+
+func TestEditorConsentersBFT(t *testing.T) {
+	config := newRaftConfig(t)
+	consensusType, err := proto.Marshal(&ab.ConsensusType{Type: ConsensusTypeBFT})
+	require.NoError(t, err)
+	ordererGroup := config.Raw().ChannelGroup.Groups[OrdererGroupKey]
+	ordererGroup.Values["ConsensusType"].Value = consensusType
+	orderers, err := proto.Marshal(&common.Orderers{ConsenterMapping: []*common.Consenter{
+		{Id: 1, Host: "orderer1.example.com", Port: 7050, MspId: "OrdererMSP", Identity: []byte("id1"), ClientTlsCert: []byte("c1"), ServerTlsCert: []byte("s1")},
+	}})
+	require.NoError(t, err)
+	ordererGroup.Values[OrderersKey] = &common.ConfigValue{Value: orderers, ModPolicy: "Admins"}
+
+	consenter1 := Consenter{ID: 1, Host: "orderer1.example.com", Port: 7050, MSPID: "OrdererMSP", Identity: []byte("id1"), ClientTLSCert: []byte("c1"), ServerTLSCert: []byte("s1")}
+
+	decoded, err := DecodeConfig(config.Raw())
+	require.NoError(t, err)
+	assert.Equal(t, []Consenter{consenter1}, decoded.Orderer.Consenters)
+
+	editor, err := NewEditor(config)
+	require.NoError(t, err)
+
+	consenter2 := Consenter{Host: "orderer2.example.com", Port: 7050, MSPID: "OrdererMSP", Identity: []byte("id2"), ClientTLSCert: []byte("c2"), ServerTLSCert: []byte("s2")}
+	assert.Error(t, editor.AddConsenter(Consenter{Host: "orderer2.example.com", Port: 7050, ClientTLSCert: []byte("c2"), ServerTLSCert: []byte("s2")}), "expecting error for missing MSP ID and identity")
+	assert.Error(t, editor.AddConsenter(Consenter{ID: 1, Host: "orderer3.example.com", Port: 7050, MSPID: "OrdererMSP", Identity: []byte("id3"), ClientTLSCert: []byte("c3"), ServerTLSCert: []byte("s3")}), "expecting error for duplicate consenter ID")
+	require.NoError(t, editor.AddConsenter(consenter2))
+	assert.Error(t, editor.AddConsenter(consenter2), "expecting error for duplicate consenter")
+
+	consenter2.ID = 2
+	consenters, err := editor.Consenters()
+	require.NoError(t, err)
+	assert.Equal(t, []Consenter{consenter1, consenter2}, consenters)
+
+	require.NoError(t, editor.RemoveConsenter("orderer1.example.com", 7050))
+	assert.Error(t, editor.RemoveConsenter("orderer1.example.com", 7050), "expecting error for unknown consenter")
+	assert.Error(t, editor.RemoveConsenter("orderer2.example.com", 7050), "expecting error for removing the last consenter")
+
+	consenters, err = editor.Consenters()
+	require.NoError(t, err)
+	assert.Equal(t, []Consenter{consenter2}, consenters)
+
+	update, err := editor.ComputeUpdate()
+	require.NoError(t, err)
+	value := update.WriteSet.Groups[OrdererGroupKey].Values[OrderersKey]
+	require.NotNil(t, value)
+	assert.Equal(t, uint64(1), value.Version)
+	assert.Nil(t, update.WriteSet.Groups[OrdererGroupKey].Values["ConsensusType"], "consensus type should not be updated")
+}
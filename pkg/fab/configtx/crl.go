@@ -0,0 +1,161 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+// AddRevocationList installs a CRL (PEM or DER encoded) into the MSP of the given organization (name
+// or MSP ID), in both the Application and the Orderer group if the organization belongs to both.
+// The CRL must be signed by one of the root or intermediate CAs of the organization. A CRL previously
+// installed for the same CA is replaced, since the CRL of a Fabric CA lists all of its unexpired
+// revoked certificates.
+func (e *Editor) AddRevocationList(org string, crl []byte) error {
+	crlPEM, list, err := parseCRL(crl)
+	if err != nil {
+		return err
+	}
+
+	groups := e.orgGroups(org)
+	if len(groups) == 0 {
+		return errors.Errorf("organization [%s] not found in channel config", org)
+	}
+
+	for _, group := range groups {
+		if err := updateFabricMSPConfig(group, func(mspConfig *mb.FabricMSPConfig) error {
+			if err := checkCRLIssuer(mspConfig, list); err != nil {
+				return err
+			}
+
+			revocationList := [][]byte{crlPEM}
+			for _, existing := range mspConfig.RevocationList {
+				_, existingList, err := parseCRL(existing)
+				if err == nil && sameIssuer(existingList, list) {
+					continue
+				}
+				revocationList = append(revocationList, existing)
+			}
+			mspConfig.RevocationList = revocationList
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orgGroups returns the Application and Orderer groups of the organization with the given name or MSP ID
+func (e *Editor) orgGroups(org string) []*common.ConfigGroup {
+	var groups []*common.ConfigGroup
+	for _, key := range []string{ApplicationGroupKey, OrdererGroupKey} {
+		parent, ok := e.updated.ChannelGroup.Groups[key]
+		if !ok {
+			continue
+		}
+		for name, group := range parent.Groups {
+			if name == org {
+				groups = append(groups, group)
+				continue
+			}
+			if mspConfig, _, err := fabricMSPConfig(group); err == nil && mspConfig.Name == org {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups
+}
+
+func fabricMSPConfig(orgGroup *common.ConfigGroup) (*mb.FabricMSPConfig, *mb.MSPConfig, error) {
+	value, ok := orgGroup.Values[channelConfig.MSPKey]
+	if !ok {
+		return nil, nil, errors.New("MSP config not found in organization group")
+	}
+
+	mspConfig := &mb.MSPConfig{}
+	if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal MSP config failed")
+	}
+	if mspConfig.Type != fabricMSPType {
+		return nil, nil, errors.Errorf("unsupported MSP type [%d]", mspConfig.Type)
+	}
+
+	fabricConfig := &mb.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal fabric MSP config failed")
+	}
+	return fabricConfig, mspConfig, nil
+}
+
+func updateFabricMSPConfig(orgGroup *common.ConfigGroup, update func(mspConfig *mb.FabricMSPConfig) error) error {
+	fabricConfig, mspConfig, err := fabricMSPConfig(orgGroup)
+	if err != nil {
+		return err
+	}
+
+	if err := update(fabricConfig); err != nil {
+		return err
+	}
+
+	mspConfig.Config, err = proto.Marshal(fabricConfig)
+	if err != nil {
+		return errors.Wrap(err, "marshal fabric MSP config failed")
+	}
+	return setValue(orgGroup, channelConfig.MSPKey, mspConfig)
+}
+
+// parseCRL returns the PEM encoding and the parsed CRL
+func parseCRL(crl []byte) ([]byte, *pkix.CertificateList, error) {
+	der := crl
+	if block, _ := pem.Decode(crl); block != nil {
+		der = block.Bytes
+	}
+
+	list, err := x509.ParseDERCRL(der)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid CRL")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), list, nil
+}
+
+// checkCRLIssuer verifies that the CRL is signed by a root or intermediate CA of the MSP
+func checkCRLIssuer(mspConfig *mb.FabricMSPConfig, list *pkix.CertificateList) error {
+	var caCerts [][]byte
+	caCerts = append(caCerts, mspConfig.RootCerts...)
+	caCerts = append(caCerts, mspConfig.IntermediateCerts...)
+	for _, certPEM := range caCerts {
+		block, _ := pem.Decode(certPEM)
+		if block == nil {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if cert.CheckCRLSignature(list) == nil {
+			return nil
+		}
+	}
+	return errors.Errorf("CRL is not signed by a CA of MSP [%s]", mspConfig.Name)
+}
+
+func sameIssuer(a, b *pkix.CertificateList) bool {
+	issuerA, errA := asn1.Marshal(a.TBSCertList.Issuer)
+	issuerB, errB := asn1.Marshal(b.TBSCertList.Issuer)
+	return errA == nil && errB == nil && bytes.Equal(issuerA, issuerB)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (ca *testCA) crl(t *testing.T, serials ...int64) []byte {
+	var revoked []pkix.RevokedCertificate
+	for _, serial := range serials {
+		revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: big.NewInt(serial), RevocationTime: time.Now()})
+	}
+	der, err := ca.cert.CreateCRL(rand.Reader, ca.key, revoked, time.Now(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+}
+
+func newTestEditorWithCA(t *testing.T, ca *testCA) *Editor {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org1MSP", "Org2MSP"},
+			OrdererAddress: "localhost:7050",
+			RootCA:         string(ca.pem),
+		},
+	}
+
+	config, err := DecodeConfigBlock(builder.Build())
+	require.NoError(t, err)
+	config.ChannelID = "mychannel"
+
+	editor, err := NewEditor(config)
+	require.NoError(t, err)
+	return editor
+}
+
+func TestAddRevocationList(t *testing.T) {
+	ca := newTestCA(t, "ca.org1.example.com")
+	editor := newTestEditorWithCA(t, ca)
+
+	require.NoError(t, editor.AddRevocationList("Org1MSP", ca.crl(t, 10)))
+
+	config, err := editor.Config()
+	require.NoError(t, err)
+	require.Len(t, config.Application.Organizations["Org1MSP"].RevocationList, 1)
+	assert.Empty(t, config.Application.Organizations["Org2MSP"].RevocationList)
+
+	// A newer CRL of the same CA replaces the installed CRL; DER encoding is accepted
+	newCRL := ca.crl(t, 10, 11)
+	block, _ := pem.Decode(newCRL)
+	require.NoError(t, editor.AddRevocationList("Org1MSP", block.Bytes))
+
+	config, err = editor.Config()
+	require.NoError(t, err)
+	revocationList := config.Application.Organizations["Org1MSP"].RevocationList
+	require.Len(t, revocationList, 1)
+	assert.Equal(t, newCRL, revocationList[0], "expecting CRL to be stored PEM encoded")
+
+	update, err := editor.ComputeUpdate()
+	require.NoError(t, err)
+	orgWriteSet := update.WriteSet.Groups[ApplicationGroupKey].Groups["Org1MSP"]
+	require.NotNil(t, orgWriteSet)
+	assert.Equal(t, uint64(1), orgWriteSet.Values["MSP"].Version)
+	assert.Nil(t, update.WriteSet.Groups[ApplicationGroupKey].Groups["Org2MSP"])
+
+	// Orderer organizations are updated as well
+	require.NoError(t, editor.AddRevocationList("OrdererMSP", ca.crl(t, 12)))
+	config, err = editor.Config()
+	require.NoError(t, err)
+	assert.Len(t, config.Orderer.Organizations["OrdererMSP"].RevocationList, 1)
+}
+
+func TestAddRevocationListErrors(t *testing.T) {
+	ca := newTestCA(t, "ca.org1.example.com")
+	editor := newTestEditorWithCA(t, ca)
+
+	assert.Error(t, editor.AddRevocationList("Org1MSP", []byte("invalid")))
+	assert.Error(t, editor.AddRevocationList("OrgXMSP", ca.crl(t, 10)))
+
+	err := editor.AddRevocationList("Org1MSP", newTestCA(t, "ca.other.example.com").crl(t, 10))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CRL is not signed by a CA of MSP [Org1MSP]")
+}
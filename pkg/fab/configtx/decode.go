@@ -0,0 +1,440 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package configtx decodes channel configuration into navigable Go structs and
+// computes the config updates required to apply changes to a channel configuration.
+package configtx
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const (
+	// ApplicationGroupKey is the key of the Application config group
+	ApplicationGroupKey = "Application"
+
+	// OrdererGroupKey is the key of the Orderer config group
+	OrdererGroupKey = channelConfig.OrdererGroupKey
+
+	// ACLsKey is the key of the ACLs config value in the Application group
+	ACLsKey = "ACLs"
+)
+
+// Config is the decoded channel configuration
+type Config struct {
+	ChannelID                      string             `json:"channelId"`
+	BlockNumber                    uint64             `json:"blockNumber"`
+	Sequence                       uint64             `json:"sequence"`
+	Consortium                     string             `json:"consortium,omitempty"`
+	HashingAlgorithm               string             `json:"hashingAlgorithm,omitempty"`
+	BlockDataHashingStructureWidth uint32             `json:"blockDataHashingStructureWidth,omitempty"`
+	OrdererAddresses               []string           `json:"ordererAddresses,omitempty"`
+	Capabilities                   []string           `json:"capabilities,omitempty"`
+	Policies                       map[string]*Policy `json:"policies,omitempty"`
+	Application                    *Application       `json:"application,omitempty"`
+	Orderer                        *Orderer           `json:"orderer,omitempty"`
+
+	raw *common.Config
+}
+
+// Application is the decoded Application config group
+type Application struct {
+	Organizations map[string]*Organization `json:"organizations,omitempty"`
+	ACLs          map[string]string        `json:"acls,omitempty"`
+	Capabilities  []string                 `json:"capabilities,omitempty"`
+	Policies      map[string]*Policy       `json:"policies,omitempty"`
+}
+
+// Orderer is the decoded Orderer config group
+type Orderer struct {
+	ConsensusType     string                   `json:"consensusType,omitempty"`
+	ConsensusMetadata []byte                   `json:"consensusMetadata,omitempty"`
+	Consenters        []Consenter              `json:"consenters,omitempty"`
+	BatchSize         BatchSize                `json:"batchSize"`
+	BatchTimeout      string                   `json:"batchTimeout,omitempty"`
+	MaxChannels       uint64                   `json:"maxChannels,omitempty"`
+	KafkaBrokers      []string                 `json:"kafkaBrokers,omitempty"`
+	Organizations     map[string]*Organization `json:"organizations,omitempty"`
+	Capabilities      []string                 `json:"capabilities,omitempty"`
+	Policies          map[string]*Policy       `json:"policies,omitempty"`
+}
+
+// BatchSize contains the orderer batch size settings
+type BatchSize struct {
+	MaxMessageCount   uint32 `json:"maxMessageCount"`
+	AbsoluteMaxBytes  uint32 `json:"absoluteMaxBytes"`
+	PreferredMaxBytes uint32 `json:"preferredMaxBytes"`
+}
+
+// Organization is a decoded organization config group
+type Organization struct {
+	Name              string             `json:"name"`
+	MSPID             string             `json:"mspId,omitempty"`
+	RootCerts         [][]byte           `json:"rootCerts,omitempty"`
+	IntermediateCerts [][]byte           `json:"intermediateCerts,omitempty"`
+	Admins            [][]byte           `json:"admins,omitempty"`
+	TLSRootCerts      [][]byte           `json:"tlsRootCerts,omitempty"`
+	RevocationList    [][]byte           `json:"revocationList,omitempty"`
+	AnchorPeers       []AnchorPeer       `json:"anchorPeers,omitempty"`
+	Policies          map[string]*Policy `json:"policies,omitempty"`
+}
+
+// AnchorPeer is an anchor peer of an application organization
+type AnchorPeer struct {
+	Host string `json:"host"`
+	Port int32  `json:"port"`
+}
+
+// Policy is a decoded config policy
+type Policy struct {
+	Type      string `json:"type"`
+	ModPolicy string `json:"modPolicy,omitempty"`
+
+	// Rule and SubPolicy are set for implicit meta policies (e.g. "ANY Readers")
+	Rule      string `json:"rule,omitempty"`
+	SubPolicy string `json:"subPolicy,omitempty"`
+
+	// Signature is set for signature policies
+	Signature *common.SignaturePolicyEnvelope `json:"signature,omitempty"`
+}
+
+// Raw returns the underlying config proto
+func (c *Config) Raw() *common.Config {
+	return c.raw
+}
+
+// JSON returns the JSON representation of the config
+func (c *Config) JSON() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+// DecodeConfigBlock decodes the channel configuration in the given config block
+func DecodeConfigBlock(block *common.Block) (*Config, error) {
+	if block == nil || block.Header == nil {
+		return nil, errors.New("expected header in block")
+	}
+	if block.Data == nil || len(block.Data.Data) == 0 {
+		return nil, errors.New("expected data in block")
+	}
+
+	configEnvelope, err := resource.CreateConfigEnvelope(block.Data.Data[0])
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := &common.Envelope{}
+	if err := proto.Unmarshal(block.Data.Data[0], envelope); err != nil {
+		return nil, errors.Wrap(err, "unmarshal envelope from config block failed")
+	}
+	channelID, err := channelIDFromEnvelope(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := DecodeConfig(configEnvelope.Config)
+	if err != nil {
+		return nil, err
+	}
+	config.ChannelID = channelID
+	config.BlockNumber = block.Header.Number
+
+	return config, nil
+}
+
+// DecodeConfig decodes the given channel configuration
+func DecodeConfig(config *common.Config) (*Config, error) {
+	if config == nil || config.ChannelGroup == nil {
+		return nil, errors.New("channel group is required")
+	}
+
+	group := config.ChannelGroup
+	decoded := &Config{
+		Sequence: config.Sequence,
+		raw:      config,
+	}
+
+	var err error
+	if decoded.Policies, err = decodePolicies(group.Policies); err != nil {
+		return nil, err
+	}
+	if err := decodeChannelValues(decoded, group.Values); err != nil {
+		return nil, err
+	}
+
+	if appGroup, ok := group.Groups[ApplicationGroupKey]; ok {
+		if decoded.Application, err = decodeApplication(appGroup); err != nil {
+			return nil, errors.WithMessage(err, "failed to decode Application group")
+		}
+	}
+
+	if ordererGroup, ok := group.Groups[OrdererGroupKey]; ok {
+		if decoded.Orderer, err = decodeOrderer(ordererGroup); err != nil {
+			return nil, errors.WithMessage(err, "failed to decode Orderer group")
+		}
+	}
+
+	return decoded, nil
+}
+
+func channelIDFromEnvelope(envelope *common.Envelope) (string, error) {
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil {
+		return "", errors.Wrap(err, "unmarshal payload from envelope failed")
+	}
+	if payload.Header == nil {
+		return "", errors.New("expected header in payload")
+	}
+	channelHeader := &common.ChannelHeader{}
+	if err := proto.Unmarshal(payload.Header.ChannelHeader, channelHeader); err != nil {
+		return "", errors.Wrap(err, "unmarshal channel header failed")
+	}
+	return channelHeader.ChannelId, nil
+}
+
+func decodeChannelValues(config *Config, values map[string]*common.ConfigValue) error {
+	for key, value := range values {
+		var err error
+		switch key {
+		case channelConfig.ConsortiumKey:
+			consortium := &common.Consortium{}
+			err = proto.Unmarshal(value.Value, consortium)
+			config.Consortium = consortium.Name
+		case channelConfig.HashingAlgorithmKey:
+			hashingAlgorithm := &common.HashingAlgorithm{}
+			err = proto.Unmarshal(value.Value, hashingAlgorithm)
+			config.HashingAlgorithm = hashingAlgorithm.Name
+		case channelConfig.BlockDataHashingStructureKey:
+			bdhs := &common.BlockDataHashingStructure{}
+			err = proto.Unmarshal(value.Value, bdhs)
+			config.BlockDataHashingStructureWidth = bdhs.Width
+		case channelConfig.OrdererAddressesKey:
+			addresses := &common.OrdererAddresses{}
+			err = proto.Unmarshal(value.Value, addresses)
+			config.OrdererAddresses = addresses.Addresses
+		case channelConfig.CapabilitiesKey:
+			config.Capabilities, err = decodeCapabilities(value)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "unmarshal channel config value [%s] failed", key)
+		}
+	}
+	return nil
+}
+
+func decodeApplication(group *common.ConfigGroup) (*Application, error) {
+	app := &Application{
+		Organizations: make(map[string]*Organization),
+	}
+
+	var err error
+	if app.Policies, err = decodePolicies(group.Policies); err != nil {
+		return nil, err
+	}
+
+	for key, value := range group.Values {
+		switch key {
+		case ACLsKey:
+			acls := &pb.ACLs{}
+			if err := proto.Unmarshal(value.Value, acls); err != nil {
+				return nil, errors.Wrap(err, "unmarshal ACLs failed")
+			}
+			app.ACLs = make(map[string]string)
+			for name, apiResource := range acls.Acls {
+				app.ACLs[name] = apiResource.PolicyRef
+			}
+		case channelConfig.CapabilitiesKey:
+			if app.Capabilities, err = decodeCapabilities(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for name, orgGroup := range group.Groups {
+		org, err := decodeOrganization(name, orgGroup)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to decode organization "+name)
+		}
+		app.Organizations[name] = org
+	}
+
+	return app, nil
+}
+
+func decodeOrderer(group *common.ConfigGroup) (*Orderer, error) {
+	orderer := &Orderer{
+		Organizations: make(map[string]*Organization),
+	}
+
+	var err error
+	if orderer.Policies, err = decodePolicies(group.Policies); err != nil {
+		return nil, err
+	}
+
+	for key, value := range group.Values {
+		switch key {
+		case channelConfig.ConsensusTypeKey:
+			consensusType := &ab.ConsensusType{}
+			err = proto.Unmarshal(value.Value, consensusType)
+			orderer.ConsensusType = consensusType.Type
+			orderer.ConsensusMetadata = consensusType.Metadata
+			if err == nil {
+				orderer.Consenters, err = decodeConsenters(group, consensusType)
+			}
+		case channelConfig.BatchSizeKey:
+			batchSize := &ab.BatchSize{}
+			err = proto.Unmarshal(value.Value, batchSize)
+			orderer.BatchSize = BatchSize{
+				MaxMessageCount:   batchSize.MaxMessageCount,
+				AbsoluteMaxBytes:  batchSize.AbsoluteMaxBytes,
+				PreferredMaxBytes: batchSize.PreferredMaxBytes,
+			}
+		case channelConfig.BatchTimeoutKey:
+			batchTimeout := &ab.BatchTimeout{}
+			err = proto.Unmarshal(value.Value, batchTimeout)
+			orderer.BatchTimeout = batchTimeout.Timeout
+		case channelConfig.ChannelRestrictionsKey:
+			restrictions := &ab.ChannelRestrictions{}
+			err = proto.Unmarshal(value.Value, restrictions)
+			orderer.MaxChannels = restrictions.MaxCount
+		case channelConfig.KafkaBrokersKey:
+			brokers := &ab.KafkaBrokers{}
+			err = proto.Unmarshal(value.Value, brokers)
+			orderer.KafkaBrokers = brokers.Brokers
+		case channelConfig.CapabilitiesKey:
+			orderer.Capabilities, err = decodeCapabilities(value)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "unmarshal orderer config value [%s] failed", key)
+		}
+	}
+
+	for name, orgGroup := range group.Groups {
+		org, err := decodeOrganization(name, orgGroup)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to decode organization "+name)
+		}
+		orderer.Organizations[name] = org
+	}
+
+	return orderer, nil
+}
+
+func decodeOrganization(name string, group *common.ConfigGroup) (*Organization, error) {
+	org := &Organization{Name: name}
+
+	var err error
+	if org.Policies, err = decodePolicies(group.Policies); err != nil {
+		return nil, err
+	}
+
+	for key, value := range group.Values {
+		switch key {
+		case channelConfig.MSPKey:
+			if err := decodeMSP(org, value); err != nil {
+				return nil, err
+			}
+		case channelConfig.AnchorPeersKey:
+			anchorPeers := &pb.AnchorPeers{}
+			if err := proto.Unmarshal(value.Value, anchorPeers); err != nil {
+				return nil, errors.Wrap(err, "unmarshal anchor peers failed")
+			}
+			for _, ap := range anchorPeers.AnchorPeers {
+				org.AnchorPeers = append(org.AnchorPeers, AnchorPeer{Host: ap.Host, Port: ap.Port})
+			}
+		}
+	}
+
+	return org, nil
+}
+
+func decodeMSP(org *Organization, value *common.ConfigValue) error {
+	mspConfig := &mb.MSPConfig{}
+	if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+		return errors.Wrap(err, "unmarshal MSP config failed")
+	}
+
+	fabricMSPConfig := &mb.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, fabricMSPConfig); err != nil {
+		return errors.Wrap(err, "unmarshal fabric MSP config failed")
+	}
+
+	org.MSPID = fabricMSPConfig.Name
+	org.RootCerts = fabricMSPConfig.RootCerts
+	org.IntermediateCerts = fabricMSPConfig.IntermediateCerts
+	org.Admins = fabricMSPConfig.Admins
+	org.TLSRootCerts = fabricMSPConfig.TlsRootCerts
+	org.RevocationList = fabricMSPConfig.RevocationList
+	return nil
+}
+
+func decodeCapabilities(value *common.ConfigValue) ([]string, error) {
+	capabilities := &common.Capabilities{}
+	if err := proto.Unmarshal(value.Value, capabilities); err != nil {
+		return nil, errors.Wrap(err, "unmarshal capabilities failed")
+	}
+	var names []string
+	for name := range capabilities.Capabilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func decodePolicies(policies map[string]*common.ConfigPolicy) (map[string]*Policy, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	decoded := make(map[string]*Policy)
+	for name, configPolicy := range policies {
+		policy, err := decodePolicy(configPolicy)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to decode policy "+name)
+		}
+		decoded[name] = policy
+	}
+	return decoded, nil
+}
+
+func decodePolicy(configPolicy *common.ConfigPolicy) (*Policy, error) {
+	policy := &Policy{ModPolicy: configPolicy.ModPolicy}
+	if configPolicy.Policy == nil {
+		policy.Type = common.Policy_UNKNOWN.String()
+		return policy, nil
+	}
+
+	policyType := common.Policy_PolicyType(configPolicy.Policy.Type)
+	policy.Type = policyType.String()
+
+	switch policyType {
+	case common.Policy_SIGNATURE:
+		sigPolicyEnv := &common.SignaturePolicyEnvelope{}
+		if err := proto.Unmarshal(configPolicy.Policy.Value, sigPolicyEnv); err != nil {
+			return nil, errors.Wrap(err, "unmarshal signature policy envelope failed")
+		}
+		policy.Signature = sigPolicyEnv
+	case common.Policy_IMPLICIT_META:
+		implicitMetaPolicy := &common.ImplicitMetaPolicy{}
+		if err := proto.Unmarshal(configPolicy.Policy.Value, implicitMetaPolicy); err != nil {
+			return nil, errors.Wrap(err, "unmarshal implicit meta policy failed")
+		}
+		policy.Rule = implicitMetaPolicy.Rule.String()
+		policy.SubPolicy = implicitMetaPolicy.SubPolicy
+	}
+
+	return policy, nil
+}
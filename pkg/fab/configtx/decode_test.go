@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeConfigBlock(t *testing.T) {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:               "Admins",
+			MSPNames:                []string{"Org1MSP", "Org2MSP"},
+			OrdererAddress:          "localhost:7050",
+			RootCA:                  "root-ca",
+			ChannelCapabilities:     []string{"V1_2"},
+			ApplicationCapabilities: []string{"V1_2", "V1_1"},
+		},
+		Index: 5,
+	}
+
+	config, err := DecodeConfigBlock(builder.Build())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(5), config.BlockNumber)
+	assert.Equal(t, []string{"localhost:7050"}, config.OrdererAddresses)
+	assert.Equal(t, []string{"V1_2"}, config.Capabilities)
+	assert.Len(t, config.Policies, 4)
+
+	require.NotNil(t, config.Application)
+	assert.Equal(t, []string{"V1_1", "V1_2"}, config.Application.Capabilities)
+	require.Len(t, config.Application.Organizations, 2)
+	org1 := config.Application.Organizations["Org1MSP"]
+	require.NotNil(t, org1)
+	assert.Equal(t, "Org1MSP", org1.MSPID)
+	assert.Equal(t, [][]byte{[]byte("root-ca")}, org1.RootCerts)
+	require.NotNil(t, org1.Policies["Admins"])
+	assert.Equal(t, common.Policy_SIGNATURE.String(), org1.Policies["Admins"].Type)
+	assert.NotNil(t, org1.Policies["Admins"].Signature)
+	assert.Equal(t, "Admins", org1.Policies["Admins"].ModPolicy)
+
+	require.NotNil(t, config.Orderer)
+	assert.Equal(t, "sample-Consensus-Type", config.Orderer.ConsensusType)
+	assert.Equal(t, uint32(10), config.Orderer.BatchSize.MaxMessageCount)
+	assert.Equal(t, "123", config.Orderer.BatchTimeout)
+	assert.Equal(t, uint64(200), config.Orderer.MaxChannels)
+	assert.NotNil(t, config.Orderer.Organizations["OrdererMSP"])
+
+	assert.NotNil(t, config.Raw())
+
+	jsonBytes, err := config.JSON()
+	require.NoError(t, err)
+	decoded := &Config{}
+	require.NoError(t, json.Unmarshal(jsonBytes, decoded))
+	assert.Equal(t, config.Orderer.BatchSize, decoded.Orderer.BatchSize)
+}
+
+func TestDecodeConfigErrors(t *testing.T) {
+	_, err := DecodeConfigBlock(&common.Block{})
+	assert.Error(t, err, "expecting error for block without header")
+
+	_, err = DecodeConfigBlock(&common.Block{Header: &common.BlockHeader{}})
+	assert.Error(t, err, "expecting error for block without data")
+
+	_, err = DecodeConfig(&common.Config{})
+	assert.Error(t, err, "expecting error for config without channel group")
+}
@@ -0,0 +1,444 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const (
+	// AdminsPolicyKey is the key of the Admins policy, which is the default mod policy of new config elements
+	AdminsPolicyKey = channelConfig.AdminsPolicyKey
+
+	fabricMSPType = 0
+)
+
+// Editor applies changes to a copy of a channel configuration and produces
+// the config update that is submitted (using resmgmt.SaveChannel) to apply them.
+//
+//  Basic Flow:
+//  1) Query the decoded channel config (resmgmt.QueryDecodedConfigFromOrderer)
+//  2) Create an editor and apply changes
+//  3) Create the config update envelope, signed by the required admins
+//  4) Submit the envelope using resmgmt.SaveChannel
+type Editor struct {
+	channelID string
+	original  *common.Config
+	updated   *common.Config
+}
+
+// NewEditor returns an editor for the given channel configuration
+func NewEditor(config *Config) (*Editor, error) {
+	if config == nil || config.raw == nil || config.raw.ChannelGroup == nil {
+		return nil, errors.New("config is required")
+	}
+	if config.ChannelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+
+	return &Editor{
+		channelID: config.ChannelID,
+		original:  config.raw,
+		updated:   proto.Clone(config.raw).(*common.Config),
+	}, nil
+}
+
+// SetACL sets the policy reference (e.g. "/Channel/Application/Writers") of the given ACL resource
+func (e *Editor) SetACL(resourceName, policyRef string) error {
+	if resourceName == "" || policyRef == "" {
+		return errors.New("resource name and policy reference are required")
+	}
+
+	return e.updateACLs(func(acls *pb.ACLs) {
+		acls.Acls[resourceName] = &pb.APIResource{PolicyRef: policyRef}
+	})
+}
+
+// RemoveACL removes the ACL of the given resource (so that the default policy of the resource applies)
+func (e *Editor) RemoveACL(resourceName string) error {
+	return e.updateACLs(func(acls *pb.ACLs) {
+		delete(acls.Acls, resourceName)
+	})
+}
+
+// SetPolicy sets (adds or replaces) the named policy of a config group. The group is given by its path
+// below the channel group, e.g. "" for the channel itself, "Application", "Orderer" or "Application/Org1MSP".
+// If the policy doesn't specify a mod policy then the mod policy of the replaced policy is kept.
+//  Parameters:
+//  groupPath is the path of the group
+//  name is the name of the policy (e.g. "Writers" or "Endorsement")
+//  policy is the signature or implicit meta policy
+func (e *Editor) SetPolicy(groupPath, name string, policy *Policy) error {
+	if name == "" {
+		return errors.New("policy name is required")
+	}
+	group, err := e.groupAt(groupPath)
+	if err != nil {
+		return err
+	}
+
+	configPolicy, err := newConfigPolicy(policy)
+	if err != nil {
+		return errors.WithMessage(err, "invalid policy "+name)
+	}
+
+	if group.Policies == nil {
+		group.Policies = make(map[string]*common.ConfigPolicy)
+	}
+	if existing, ok := group.Policies[name]; ok {
+		if policy.ModPolicy == "" {
+			configPolicy.ModPolicy = existing.ModPolicy
+		}
+		configPolicy.Version = existing.Version
+	}
+	group.Policies[name] = configPolicy
+	return nil
+}
+
+// RemovePolicy removes the named policy of the config group with the given path (see SetPolicy)
+func (e *Editor) RemovePolicy(groupPath, name string) error {
+	group, err := e.groupAt(groupPath)
+	if err != nil {
+		return err
+	}
+	if _, ok := group.Policies[name]; !ok {
+		return errors.Errorf("policy [%s] not found in group [%s]", name, groupPath)
+	}
+
+	delete(group.Policies, name)
+	return nil
+}
+
+// AddOrganization adds the given organization to the Application group
+func (e *Editor) AddOrganization(org *Organization) error {
+	appGroup, err := e.group(ApplicationGroupKey)
+	if err != nil {
+		return err
+	}
+	if org == nil || org.Name == "" {
+		return errors.New("organization name is required")
+	}
+	if _, ok := appGroup.Groups[org.Name]; ok {
+		return errors.Errorf("organization [%s] already exists", org.Name)
+	}
+
+	orgGroup, err := NewOrganizationGroup(org)
+	if err != nil {
+		return err
+	}
+
+	if appGroup.Groups == nil {
+		appGroup.Groups = make(map[string]*common.ConfigGroup)
+	}
+	appGroup.Groups[org.Name] = orgGroup
+	return nil
+}
+
+// RemoveOrganization removes the given organization from the Application group
+func (e *Editor) RemoveOrganization(name string) error {
+	appGroup, err := e.group(ApplicationGroupKey)
+	if err != nil {
+		return err
+	}
+	if _, ok := appGroup.Groups[name]; !ok {
+		return errors.Errorf("organization [%s] not found", name)
+	}
+
+	delete(appGroup.Groups, name)
+	return nil
+}
+
+// SetAnchorPeers replaces the anchor peers of the given application organization
+func (e *Editor) SetAnchorPeers(orgName string, anchorPeers []AnchorPeer) error {
+	appGroup, err := e.group(ApplicationGroupKey)
+	if err != nil {
+		return err
+	}
+	orgGroup, ok := appGroup.Groups[orgName]
+	if !ok {
+		return errors.Errorf("organization [%s] not found", orgName)
+	}
+
+	if len(anchorPeers) == 0 {
+		delete(orgGroup.Values, channelConfig.AnchorPeersKey)
+		return nil
+	}
+
+	return setValue(orgGroup, channelConfig.AnchorPeersKey, newAnchorPeers(anchorPeers))
+}
+
+// SetBatchSize sets the batch size of the orderer
+func (e *Editor) SetBatchSize(batchSize BatchSize) error {
+	if batchSize.MaxMessageCount == 0 || batchSize.AbsoluteMaxBytes == 0 || batchSize.PreferredMaxBytes == 0 {
+		return errors.New("batch size values must be greater than zero")
+	}
+	if batchSize.PreferredMaxBytes > batchSize.AbsoluteMaxBytes {
+		return errors.New("preferred max bytes must not be greater than absolute max bytes")
+	}
+
+	ordererGroup, err := e.group(OrdererGroupKey)
+	if err != nil {
+		return err
+	}
+
+	return setValue(ordererGroup, channelConfig.BatchSizeKey, &ab.BatchSize{
+		MaxMessageCount:   batchSize.MaxMessageCount,
+		AbsoluteMaxBytes:  batchSize.AbsoluteMaxBytes,
+		PreferredMaxBytes: batchSize.PreferredMaxBytes,
+	})
+}
+
+// SetBatchTimeout sets the batch timeout of the orderer (e.g. "2s")
+func (e *Editor) SetBatchTimeout(timeout string) error {
+	d, err := time.ParseDuration(timeout)
+	if err != nil {
+		return errors.Wrapf(err, "invalid batch timeout [%s]", timeout)
+	}
+	if d <= 0 {
+		return errors.New("batch timeout must be greater than zero")
+	}
+
+	ordererGroup, err := e.group(OrdererGroupKey)
+	if err != nil {
+		return err
+	}
+
+	return setValue(ordererGroup, channelConfig.BatchTimeoutKey, &ab.BatchTimeout{Timeout: timeout})
+}
+
+// Config returns the updated channel configuration
+func (e *Editor) Config() (*Config, error) {
+	config, err := DecodeConfig(e.updated)
+	if err != nil {
+		return nil, err
+	}
+	config.ChannelID = e.channelID
+	return config, nil
+}
+
+// ComputeUpdate computes the config update between the original and the updated configuration
+func (e *Editor) ComputeUpdate() (*common.ConfigUpdate, error) {
+	return ComputeUpdate(e.channelID, e.original, e.updated)
+}
+
+// ConfigUpdateEnvelope computes the config update and returns it wrapped in an envelope,
+// signed by each of the given signers. The returned bytes may be used as the ChannelConfig
+// of resmgmt.SaveChannelRequest, which submits the signatures of the envelope along with
+// those of its signing identities.
+func (e *Editor) ConfigUpdateEnvelope(signers ...context.Client) ([]byte, error) {
+	configUpdate, err := e.ComputeUpdate()
+	if err != nil {
+		return nil, err
+	}
+
+	configUpdateBytes, err := proto.Marshal(configUpdate)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal config update failed")
+	}
+
+	var signatures []*common.ConfigSignature
+	for _, signer := range signers {
+		signature, err := resource.CreateConfigSignature(signer, configUpdateBytes)
+		if err != nil {
+			return nil, errors.WithMessage(err, "signing config update failed")
+		}
+		signatures = append(signatures, signature)
+	}
+
+	return createConfigUpdateEnvelope(e.channelID, configUpdateBytes, signatures...)
+}
+
+// NewOrganizationGroup creates an organization config group from the given organization. The group
+// contains the MSP, the anchor peers and the default Readers, Writers and Admins policies of the
+// organization (the policies of the organization are used instead if provided).
+func NewOrganizationGroup(org *Organization) (*common.ConfigGroup, error) {
+	if org == nil || org.Name == "" {
+		return nil, errors.New("organization name is required")
+	}
+	if org.MSPID == "" {
+		return nil, errors.New("MSP ID is required")
+	}
+	if len(org.RootCerts) == 0 {
+		return nil, errors.New("at least one root certificate is required")
+	}
+
+	group := &common.ConfigGroup{
+		ModPolicy: AdminsPolicyKey,
+		Values:    make(map[string]*common.ConfigValue),
+		Policies:  make(map[string]*common.ConfigPolicy),
+	}
+
+	fabricMSPConfig, err := proto.Marshal(&mb.FabricMSPConfig{
+		Name:              org.MSPID,
+		RootCerts:         org.RootCerts,
+		IntermediateCerts: org.IntermediateCerts,
+		Admins:            org.Admins,
+		TlsRootCerts:      org.TLSRootCerts,
+		RevocationList:    org.RevocationList,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal fabric MSP config failed")
+	}
+	if err := setValue(group, channelConfig.MSPKey, &mb.MSPConfig{Type: fabricMSPType, Config: fabricMSPConfig}); err != nil {
+		return nil, err
+	}
+
+	if len(org.AnchorPeers) > 0 {
+		if err := setValue(group, channelConfig.AnchorPeersKey, newAnchorPeers(org.AnchorPeers)); err != nil {
+			return nil, err
+		}
+	}
+
+	policies := org.Policies
+	if len(policies) == 0 {
+		policies = map[string]*Policy{
+			channelConfig.ReadersPolicyKey: {Signature: cauthdsl.SignedByMspMember(org.MSPID)},
+			channelConfig.WritersPolicyKey: {Signature: cauthdsl.SignedByMspMember(org.MSPID)},
+			channelConfig.AdminsPolicyKey:  {Signature: cauthdsl.SignedByMspAdmin(org.MSPID)},
+		}
+	}
+	for name, policy := range policies {
+		configPolicy, err := newConfigPolicy(policy)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid policy "+name)
+		}
+		group.Policies[name] = configPolicy
+	}
+
+	return group, nil
+}
+
+func (e *Editor) group(key string) (*common.ConfigGroup, error) {
+	group, ok := e.updated.ChannelGroup.Groups[key]
+	if !ok {
+		return nil, errors.Errorf("%s group not found in channel config", key)
+	}
+	return group, nil
+}
+
+// groupAt returns the group with the given slash-separated path below the channel group
+func (e *Editor) groupAt(path string) (*common.ConfigGroup, error) {
+	group := e.updated.ChannelGroup
+	if path == "" {
+		return group, nil
+	}
+
+	for _, key := range strings.Split(path, "/") {
+		child, ok := group.Groups[key]
+		if !ok {
+			return nil, errors.Errorf("group [%s] not found in channel config", path)
+		}
+		group = child
+	}
+	return group, nil
+}
+
+func (e *Editor) updateACLs(update func(acls *pb.ACLs)) error {
+	appGroup, err := e.group(ApplicationGroupKey)
+	if err != nil {
+		return err
+	}
+
+	acls := &pb.ACLs{}
+	if value, ok := appGroup.Values[ACLsKey]; ok {
+		if err := proto.Unmarshal(value.Value, acls); err != nil {
+			return errors.Wrap(err, "unmarshal ACLs failed")
+		}
+	}
+	if acls.Acls == nil {
+		acls.Acls = make(map[string]*pb.APIResource)
+	}
+
+	update(acls)
+
+	return setValue(appGroup, ACLsKey, acls)
+}
+
+func setValue(group *common.ConfigGroup, key string, value proto.Message) error {
+	// Values containing maps (e.g. ACLs) are marshalled deterministically so that
+	// unchanged values are not included in the config update
+	buffer := proto.NewBuffer(nil)
+	buffer.SetDeterministic(true)
+	if err := buffer.Marshal(value); err != nil {
+		return errors.Wrapf(err, "marshal config value [%s] failed", key)
+	}
+	valueBytes := buffer.Bytes()
+
+	if group.Values == nil {
+		group.Values = make(map[string]*common.ConfigValue)
+	}
+
+	configValue, ok := group.Values[key]
+	if !ok {
+		configValue = &common.ConfigValue{ModPolicy: AdminsPolicyKey}
+		group.Values[key] = configValue
+	}
+	configValue.Value = valueBytes
+	return nil
+}
+
+func newAnchorPeers(anchorPeers []AnchorPeer) *pb.AnchorPeers {
+	value := &pb.AnchorPeers{}
+	for _, ap := range anchorPeers {
+		value.AnchorPeers = append(value.AnchorPeers, &pb.AnchorPeer{Host: ap.Host, Port: ap.Port})
+	}
+	return value
+}
+
+func newConfigPolicy(policy *Policy) (*common.ConfigPolicy, error) {
+	if policy == nil {
+		return nil, errors.New("policy is required")
+	}
+
+	modPolicy := policy.ModPolicy
+	if modPolicy == "" {
+		modPolicy = AdminsPolicyKey
+	}
+
+	var policyType common.Policy_PolicyType
+	var value proto.Message
+	switch {
+	case policy.Signature != nil:
+		policyType = common.Policy_SIGNATURE
+		value = policy.Signature
+	case policy.SubPolicy != "":
+		rule, ok := common.ImplicitMetaPolicy_Rule_value[policy.Rule]
+		if !ok {
+			return nil, errors.Errorf("invalid implicit meta policy rule [%s]", policy.Rule)
+		}
+		policyType = common.Policy_IMPLICIT_META
+		value = &common.ImplicitMetaPolicy{SubPolicy: policy.SubPolicy, Rule: common.ImplicitMetaPolicy_Rule(rule)}
+	default:
+		return nil, errors.New("either a signature policy or an implicit meta policy is required")
+	}
+
+	valueBytes, err := proto.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal policy failed")
+	}
+
+	return &common.ConfigPolicy{
+		ModPolicy: modPolicy,
+		Policy: &common.Policy{
+			Type:  int32(policyType),
+			Value: valueBytes,
+		},
+	}, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func newTestEditor(t *testing.T) *Editor {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org1MSP", "Org2MSP"},
+			OrdererAddress: "localhost:7050",
+			RootCA:         "root-ca",
+		},
+	}
+
+	config, err := DecodeConfigBlock(builder.Build())
+	require.NoError(t, err)
+	config.ChannelID = "mychannel"
+
+	editor, err := NewEditor(config)
+	require.NoError(t, err)
+	return editor
+}
+
+func TestEditor(t *testing.T) {
+	editor := newTestEditor(t)
+
+	_, err := editor.ComputeUpdate()
+	assert.Error(t, err, "expecting error since nothing was changed")
+
+	require.NoError(t, editor.SetACL("peer/Propose", "/Channel/Application/Admins"))
+	require.NoError(t, editor.SetAnchorPeers("Org1MSP", []AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}}))
+	require.NoError(t, editor.RemoveOrganization("Org2MSP"))
+	require.NoError(t, editor.AddOrganization(&Organization{Name: "Org3MSP", MSPID: "Org3MSP", RootCerts: [][]byte{[]byte("org3-ca")}}))
+	require.NoError(t, editor.SetBatchSize(BatchSize{MaxMessageCount: 20, AbsoluteMaxBytes: 1000, PreferredMaxBytes: 500}))
+	require.NoError(t, editor.SetBatchTimeout("2s"))
+
+	config, err := editor.Config()
+	require.NoError(t, err)
+	assert.Equal(t, "/Channel/Application/Admins", config.Application.ACLs["peer/Propose"])
+	assert.Equal(t, []AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}}, config.Application.Organizations["Org1MSP"].AnchorPeers)
+	assert.Nil(t, config.Application.Organizations["Org2MSP"])
+	org3 := config.Application.Organizations["Org3MSP"]
+	require.NotNil(t, org3)
+	assert.Equal(t, "Org3MSP", org3.MSPID)
+	assert.Len(t, org3.Policies, 3)
+	assert.Equal(t, uint32(20), config.Orderer.BatchSize.MaxMessageCount)
+	assert.Equal(t, "2s", config.Orderer.BatchTimeout)
+
+	update, err := editor.ComputeUpdate()
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", update.ChannelId)
+
+	appWriteSet := update.WriteSet.Groups[ApplicationGroupKey]
+	require.NotNil(t, appWriteSet)
+	assert.Equal(t, uint64(1), appWriteSet.Version, "expecting application group version to be incremented since membership changed")
+	assert.Nil(t, appWriteSet.Groups["Org2MSP"])
+	assert.Equal(t, uint64(0), appWriteSet.Groups["Org3MSP"].Version)
+	assert.Equal(t, uint64(1), appWriteSet.Groups["Org1MSP"].Version, "expecting org group version to be incremented since anchor peers were added")
+	require.NotNil(t, appWriteSet.Groups["Org1MSP"].Values["AnchorPeers"])
+	assert.Equal(t, uint64(0), appWriteSet.Groups["Org1MSP"].Values["AnchorPeers"].Version)
+
+	ordererWriteSet := update.WriteSet.Groups[OrdererGroupKey]
+	require.NotNil(t, ordererWriteSet)
+	assert.Equal(t, uint64(1), ordererWriteSet.Values["BatchSize"].Version)
+	assert.Equal(t, uint64(1), ordererWriteSet.Values["BatchTimeout"].Version)
+	assert.Nil(t, ordererWriteSet.Values["ConsensusType"], "expecting unchanged values to be excluded from the write set")
+}
+
+func TestEditorConfigUpdateEnvelope(t *testing.T) {
+	editor := newTestEditor(t)
+	require.NoError(t, editor.SetBatchTimeout("5s"))
+
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP"))
+	envelope, err := editor.ConfigUpdateEnvelope(ctx, ctx)
+	require.NoError(t, err)
+
+	configUpdateBytes, err := resource.ExtractChannelConfig(envelope)
+	require.NoError(t, err)
+	configUpdate := &common.ConfigUpdate{}
+	require.NoError(t, proto.Unmarshal(configUpdateBytes, configUpdate))
+	assert.Equal(t, "mychannel", configUpdate.ChannelId)
+	assert.NotNil(t, configUpdate.WriteSet.Groups[OrdererGroupKey].Values["BatchTimeout"])
+}
+
+func TestEditorSetPolicy(t *testing.T) {
+	editor := newTestEditor(t)
+
+	signature := cauthdsl.SignedByAnyMember([]string{"Org1MSP", "Org2MSP"})
+	require.NoError(t, editor.SetPolicy("Application", "Endorsement", &Policy{Signature: signature}))
+	require.NoError(t, editor.SetPolicy("Application/Org1MSP", "Writers", &Policy{Signature: cauthdsl.SignedByAnyMember([]string{"Org1MSP"}), ModPolicy: "Writers"}))
+	require.NoError(t, editor.SetPolicy("", "Readers", &Policy{Rule: "MAJORITY", SubPolicy: "Readers"}))
+
+	config, err := editor.Config()
+	require.NoError(t, err)
+	endorsement := config.Application.Policies["Endorsement"]
+	require.NotNil(t, endorsement)
+	assert.True(t, proto.Equal(signature, endorsement.Signature))
+	assert.Equal(t, AdminsPolicyKey, endorsement.ModPolicy)
+	assert.Equal(t, "Writers", config.Application.Organizations["Org1MSP"].Policies["Writers"].ModPolicy)
+	assert.Equal(t, "MAJORITY", config.Policies["Readers"].Rule)
+
+	update, err := editor.ComputeUpdate()
+	require.NoError(t, err)
+	require.NotNil(t, update.WriteSet.Groups[ApplicationGroupKey].Policies["Endorsement"])
+	require.NotNil(t, update.WriteSet.Groups[ApplicationGroupKey].Groups["Org1MSP"].Policies["Writers"])
+	require.NotNil(t, update.WriteSet.Policies["Readers"])
+
+	require.NoError(t, editor.RemovePolicy("Application", "Endorsement"))
+	config, err = editor.Config()
+	require.NoError(t, err)
+	assert.Nil(t, config.Application.Policies["Endorsement"])
+
+	assert.Error(t, editor.SetPolicy("Application", "", &Policy{Signature: signature}), "expecting error for missing name")
+	assert.Error(t, editor.SetPolicy("Application/OrgXMSP", "Writers", &Policy{Signature: signature}), "expecting error for unknown group")
+	assert.Error(t, editor.SetPolicy("Application", "Writers", &Policy{}), "expecting error for empty policy")
+	assert.Error(t, editor.SetPolicy("Application", "Writers", &Policy{Rule: "SOME", SubPolicy: "Writers"}), "expecting error for invalid rule")
+	assert.Error(t, editor.RemovePolicy("Application", "Endorsement"), "expecting error for unknown policy")
+}
+
+func TestEditorErrors(t *testing.T) {
+	_, err := NewEditor(nil)
+	assert.Error(t, err)
+
+	_, err = NewEditor(&Config{raw: &common.Config{ChannelGroup: &common.ConfigGroup{}}})
+	assert.Error(t, err, "expecting error for missing channel ID")
+
+	editor := newTestEditor(t)
+	assert.Error(t, editor.SetACL("", "/Channel/Application/Admins"))
+	assert.Error(t, editor.SetAnchorPeers("OrgXMSP", nil))
+	assert.Error(t, editor.RemoveOrganization("OrgXMSP"))
+	assert.Error(t, editor.AddOrganization(&Organization{Name: "Org1MSP", MSPID: "Org1MSP", RootCerts: [][]byte{[]byte("ca")}}))
+	assert.Error(t, editor.AddOrganization(&Organization{Name: "Org3MSP"}))
+	assert.Error(t, editor.SetBatchSize(BatchSize{MaxMessageCount: 10, AbsoluteMaxBytes: 10, PreferredMaxBytes: 20}))
+	assert.Error(t, editor.SetBatchTimeout("abc"))
+	assert.Error(t, editor.SetBatchTimeout("0s"))
+}
@@ -0,0 +1,353 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer/etcdraft"
+)
+
+const (
+	// BlockValidationPolicyKey is the key of the orderer policy used to validate blocks
+	BlockValidationPolicyKey = "BlockValidation"
+
+	defaultHashingAlgorithm = "SHA256"
+	defaultBatchTimeout     = "2s"
+	msgVersion              = 1
+)
+
+var (
+	defaultBatchSize = BatchSize{
+		MaxMessageCount:   10,
+		AbsoluteMaxBytes:  99 * 1024 * 1024,
+		PreferredMaxBytes: 512 * 1024,
+	}
+
+	defaultRaftOptions = etcdraft.Options{
+		TickInterval:         "500ms",
+		ElectionTick:         10,
+		HeartbeatTick:        1,
+		MaxInflightBlocks:    5,
+		SnapshotIntervalSize: 16 * 1024 * 1024,
+	}
+)
+
+// GenesisProfile contains the settings used to create the genesis block of an application channel
+// on an ordering service which uses the channel participation API (i.e. without a system channel)
+type GenesisProfile struct {
+	// Orderer contains the Raft consenters, the ordering organizations and (optionally) the batch settings.
+	// The ConsensusType must be etcdraft (or empty).
+	Orderer *Orderer
+	// OrdererAddresses are the addresses of the ordering nodes (host:port)
+	OrdererAddresses []string
+	// Application contains the application organizations (including their anchor peers)
+	Application *Application
+	// Capabilities are the channel capabilities (e.g. V2_0)
+	Capabilities []string
+	// Policies are the channel policies. The default Readers, Writers and Admins implicit meta
+	// policies are used if not provided (the same defaults apply to the Orderer and Application).
+	Policies map[string]*Policy
+}
+
+// NewGenesisBlock creates the genesis block of the given application channel from the given profile.
+// The block is used to join the ordering nodes to the channel (using the channel participation API)
+// and to join the peers to the channel.
+func NewGenesisBlock(channelID string, profile *GenesisProfile) (*common.Block, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+	if profile == nil || profile.Orderer == nil {
+		return nil, errors.New("orderer section is required")
+	}
+	if profile.Application == nil {
+		return nil, errors.New("application section is required")
+	}
+
+	channelGroup, err := newChannelGroup(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := newConfigEnvelope(channelID, &common.Config{ChannelGroup: channelGroup})
+	if err != nil {
+		return nil, err
+	}
+
+	return newGenesisBlock(envelope)
+}
+
+func newChannelGroup(profile *GenesisProfile) (*common.ConfigGroup, error) {
+	group := &common.ConfigGroup{
+		ModPolicy: AdminsPolicyKey,
+		Groups:    make(map[string]*common.ConfigGroup),
+		Values:    make(map[string]*common.ConfigValue),
+		Policies:  make(map[string]*common.ConfigPolicy),
+	}
+
+	if err := setPolicies(group, withDefaultPolicies(profile.Policies)); err != nil {
+		return nil, err
+	}
+
+	if err := setValue(group, channelConfig.HashingAlgorithmKey, &common.HashingAlgorithm{Name: defaultHashingAlgorithm}); err != nil {
+		return nil, err
+	}
+	if err := setValue(group, channelConfig.BlockDataHashingStructureKey, &common.BlockDataHashingStructure{Width: math.MaxUint32}); err != nil {
+		return nil, err
+	}
+	if len(profile.OrdererAddresses) > 0 {
+		if err := setValue(group, channelConfig.OrdererAddressesKey, &common.OrdererAddresses{Addresses: profile.OrdererAddresses}); err != nil {
+			return nil, err
+		}
+	}
+	if err := setCapabilities(group, profile.Capabilities); err != nil {
+		return nil, err
+	}
+
+	ordererGroup, err := newOrdererGroup(profile.Orderer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create orderer group")
+	}
+	group.Groups[OrdererGroupKey] = ordererGroup
+
+	app := *profile.Application
+	app.Policies = withDefaultPolicies(app.Policies)
+	appGroup, err := newApplicationGroup(&app)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create application group")
+	}
+	group.Groups[ApplicationGroupKey] = appGroup
+
+	return group, nil
+}
+
+func newOrdererGroup(orderer *Orderer) (*common.ConfigGroup, error) {
+	if orderer.ConsensusType != "" && orderer.ConsensusType != ConsensusTypeEtcdRaft {
+		return nil, errors.Errorf("unsupported consensus type [%s]", orderer.ConsensusType)
+	}
+	if len(orderer.Consenters) == 0 {
+		return nil, errors.New("at least one consenter is required")
+	}
+	if len(orderer.Organizations) == 0 {
+		return nil, errors.New("at least one orderer organization is required")
+	}
+
+	group := &common.ConfigGroup{
+		ModPolicy: AdminsPolicyKey,
+		Groups:    make(map[string]*common.ConfigGroup),
+		Values:    make(map[string]*common.ConfigValue),
+		Policies:  make(map[string]*common.ConfigPolicy),
+	}
+
+	policies := withDefaultPolicies(orderer.Policies)
+	if _, ok := policies[BlockValidationPolicyKey]; !ok {
+		policies[BlockValidationPolicyKey] = &Policy{Rule: "ANY", SubPolicy: channelConfig.WritersPolicyKey}
+	}
+	if err := setPolicies(group, policies); err != nil {
+		return nil, err
+	}
+
+	options := defaultRaftOptions
+	metadata := &etcdraft.ConfigMetadata{Options: &options}
+	for _, c := range orderer.Consenters {
+		if c.Host == "" || c.Port == 0 || len(c.ClientTLSCert) == 0 || len(c.ServerTLSCert) == 0 {
+			return nil, errors.Errorf("consenter [%s:%d] requires host, port and client and server TLS certificates", c.Host, c.Port)
+		}
+		metadata.Consenters = append(metadata.Consenters, &etcdraft.Consenter{
+			Host:          c.Host,
+			Port:          c.Port,
+			ClientTlsCert: c.ClientTLSCert,
+			ServerTlsCert: c.ServerTLSCert,
+		})
+	}
+	metadataBytes, err := proto.Marshal(metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal etcdraft metadata failed")
+	}
+	if err := setValue(group, channelConfig.ConsensusTypeKey, &ab.ConsensusType{Type: ConsensusTypeEtcdRaft, Metadata: metadataBytes}); err != nil {
+		return nil, err
+	}
+
+	batchSize := orderer.BatchSize
+	if batchSize == (BatchSize{}) {
+		batchSize = defaultBatchSize
+	}
+	if err := setValue(group, channelConfig.BatchSizeKey, &ab.BatchSize{
+		MaxMessageCount:   batchSize.MaxMessageCount,
+		AbsoluteMaxBytes:  batchSize.AbsoluteMaxBytes,
+		PreferredMaxBytes: batchSize.PreferredMaxBytes,
+	}); err != nil {
+		return nil, err
+	}
+
+	batchTimeout := orderer.BatchTimeout
+	if batchTimeout == "" {
+		batchTimeout = defaultBatchTimeout
+	}
+	if err := setValue(group, channelConfig.BatchTimeoutKey, &ab.BatchTimeout{Timeout: batchTimeout}); err != nil {
+		return nil, err
+	}
+
+	if err := setCapabilities(group, orderer.Capabilities); err != nil {
+		return nil, err
+	}
+
+	for name, org := range orderer.Organizations {
+		orgGroup, err := NewOrganizationGroup(org)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to create organization group "+name)
+		}
+		group.Groups[name] = orgGroup
+	}
+
+	return group, nil
+}
+
+// withDefaultPolicies returns a copy of the given policies which includes the default
+// Readers, Writers and Admins implicit meta policies if they're not provided
+func withDefaultPolicies(policies map[string]*Policy) map[string]*Policy {
+	result := map[string]*Policy{
+		channelConfig.ReadersPolicyKey: {Rule: "ANY", SubPolicy: channelConfig.ReadersPolicyKey},
+		channelConfig.WritersPolicyKey: {Rule: "ANY", SubPolicy: channelConfig.WritersPolicyKey},
+		channelConfig.AdminsPolicyKey:  {Rule: "MAJORITY", SubPolicy: channelConfig.AdminsPolicyKey},
+	}
+	for name, policy := range policies {
+		result[name] = policy
+	}
+	return result
+}
+
+func setPolicies(group *common.ConfigGroup, policies map[string]*Policy) error {
+	for name, policy := range policies {
+		configPolicy, err := newConfigPolicy(policy)
+		if err != nil {
+			return errors.WithMessage(err, "invalid policy "+name)
+		}
+		group.Policies[name] = configPolicy
+	}
+	return nil
+}
+
+func setCapabilities(group *common.ConfigGroup, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	capabilities := &common.Capabilities{Capabilities: make(map[string]*common.Capability)}
+	for _, name := range names {
+		capabilities.Capabilities[name] = &common.Capability{}
+	}
+	return setValue(group, channelConfig.CapabilitiesKey, capabilities)
+}
+
+func newConfigEnvelope(channelID string, config *common.Config) (*common.Envelope, error) {
+	configEnvelopeBytes, err := proto.Marshal(&common.ConfigEnvelope{Config: config})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal config envelope failed")
+	}
+
+	nonce, err := crypto.GetRandomNonce()
+	if err != nil {
+		return nil, errors.WithMessage(err, "nonce creation failed")
+	}
+	signatureHeaderBytes, err := proto.Marshal(&common.SignatureHeader{Nonce: nonce})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal signature header failed")
+	}
+
+	timestamp, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create timestamp")
+	}
+	txID := sha256.Sum256(nonce)
+	channelHeaderBytes, err := proto.Marshal(&common.ChannelHeader{
+		Type:      int32(common.HeaderType_CONFIG),
+		Version:   msgVersion,
+		ChannelId: channelID,
+		Timestamp: timestamp,
+		TxId:      hex.EncodeToString(txID[:]),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal channel header failed")
+	}
+
+	payloadBytes, err := proto.Marshal(&common.Payload{
+		Header: &common.Header{ChannelHeader: channelHeaderBytes, SignatureHeader: signatureHeaderBytes},
+		Data:   configEnvelopeBytes,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal payload failed")
+	}
+
+	return &common.Envelope{Payload: payloadBytes}, nil
+}
+
+func newGenesisBlock(envelope *common.Envelope) (*common.Block, error) {
+	envelopeBytes, err := proto.Marshal(envelope)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal envelope failed")
+	}
+
+	data := &common.BlockData{Data: [][]byte{envelopeBytes}}
+	dataHash := sha256.Sum256(bytes.Join(data.Data, nil))
+
+	// The last config of the genesis block is the block itself. (Orderers that support the channel
+	// participation API read the last config from the signatures metadata.)
+	signaturesMetadata, err := newMetadata(&ordererBlockMetadata{LastConfig: &common.LastConfig{Index: 0}})
+	if err != nil {
+		return nil, err
+	}
+	lastConfigMetadata, err := newMetadata(&common.LastConfig{Index: 0})
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make([][]byte, len(common.BlockMetadataIndex_name))
+	metadata[common.BlockMetadataIndex_SIGNATURES] = signaturesMetadata
+	metadata[common.BlockMetadataIndex_LAST_CONFIG] = lastConfigMetadata
+
+	return &common.Block{
+		Header:   &common.BlockHeader{Number: 0, DataHash: dataHash[:]},
+		Data:     data,
+		Metadata: &common.BlockMetadata{Metadata: metadata},
+	}, nil
+}
+
+func newMetadata(value proto.Message) ([]byte, error) {
+	valueBytes, err := proto.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal metadata value failed")
+	}
+	metadata, err := proto.Marshal(&common.Metadata{Value: valueBytes})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal metadata failed")
+	}
+	return metadata, nil
+}
+
+// ordererBlockMetadata mirrors the OrdererBlockMetadata message of common/common.proto
+// (Fabric 2.x), which is not part of the protos included in third_party
+
+type ordererBlockMetadata struct {
+	LastConfig        *common.LastConfig `protobuf:"bytes,1,opt,name=last_config,json=lastConfig,proto3" json:"last_config,omitempty"`
+	ConsenterMetadata []byte             `protobuf:"bytes,2,opt,name=consenter_metadata,json=consenterMetadata,proto3" json:"consenter_metadata,omitempty"`
+}
+
+func (m *ordererBlockMetadata) Reset()         { *m = ordererBlockMetadata{} }
+func (m *ordererBlockMetadata) String() string { return proto.CompactTextString(m) }
+func (*ordererBlockMetadata) ProtoMessage()    {}
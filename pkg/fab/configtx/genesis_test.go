@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer/etcdraft"
+)
+
+func newTestGenesisProfile() *GenesisProfile {
+	return &GenesisProfile{
+		Orderer: &Orderer{
+			Consenters: []Consenter{
+				{Host: "orderer.example.com", Port: 7050, ClientTLSCert: []byte("client"), ServerTLSCert: []byte("server")},
+			},
+			Organizations: map[string]*Organization{
+				"OrdererOrg": {Name: "OrdererOrg", MSPID: "OrdererMSP", RootCerts: [][]byte{[]byte("orderer-ca")}},
+			},
+			Capabilities: []string{"V2_0"},
+		},
+		OrdererAddresses: []string{"orderer.example.com:7050"},
+		Application: &Application{
+			Organizations: map[string]*Organization{
+				"Org1MSP": {
+					Name:        "Org1MSP",
+					MSPID:       "Org1MSP",
+					RootCerts:   [][]byte{[]byte("org1-ca")},
+					AnchorPeers: []AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}},
+				},
+			},
+			Capabilities: []string{"V2_0"},
+			Policies: map[string]*Policy{
+				"Endorsement": {Rule: "ANY", SubPolicy: "Writers"},
+			},
+		},
+		Capabilities: []string{"V2_0"},
+	}
+}
+
+func TestNewGenesisBlock(t *testing.T) {
+	block, err := NewGenesisBlock("mychannel", newTestGenesisProfile())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(0), block.Header.Number)
+	dataHash := sha256.Sum256(bytes.Join(block.Data.Data, nil))
+	assert.Equal(t, dataHash[:], block.Header.DataHash)
+
+	lastConfig := &common.Metadata{}
+	require.NoError(t, proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG], lastConfig))
+
+	config, err := DecodeConfigBlock(block)
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", config.ChannelID)
+	assert.Equal(t, defaultHashingAlgorithm, config.HashingAlgorithm)
+	assert.Equal(t, []string{"orderer.example.com:7050"}, config.OrdererAddresses)
+	assert.Equal(t, []string{"V2_0"}, config.Capabilities)
+	assert.Len(t, config.Policies, 3)
+
+	require.NotNil(t, config.Orderer)
+	assert.Equal(t, ConsensusTypeEtcdRaft, config.Orderer.ConsensusType)
+	assert.Equal(t, "orderer.example.com", config.Orderer.Consenters[0].Host)
+	assert.Equal(t, defaultBatchSize, config.Orderer.BatchSize)
+	assert.Equal(t, defaultBatchTimeout, config.Orderer.BatchTimeout)
+	assert.Contains(t, config.Orderer.Policies, BlockValidationPolicyKey)
+	assert.Equal(t, "OrdererMSP", config.Orderer.Organizations["OrdererOrg"].MSPID)
+
+	metadata := &etcdraft.ConfigMetadata{}
+	require.NoError(t, proto.Unmarshal(config.Orderer.ConsensusMetadata, metadata))
+	require.NotNil(t, metadata.Options)
+	assert.Equal(t, defaultRaftOptions.TickInterval, metadata.Options.TickInterval)
+
+	require.NotNil(t, config.Application)
+	org1 := config.Application.Organizations["Org1MSP"]
+	require.NotNil(t, org1)
+	assert.Equal(t, []AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}}, org1.AnchorPeers)
+	assert.Len(t, config.Application.Policies, 4, "expecting default policies along with the Endorsement policy")
+}
+
+func TestNewGenesisBlockErrors(t *testing.T) {
+	_, err := NewGenesisBlock("", newTestGenesisProfile())
+	assert.Error(t, err, "expecting error for missing channel ID")
+
+	_, err = NewGenesisBlock("mychannel", &GenesisProfile{Application: &Application{}})
+	assert.Error(t, err, "expecting error for missing orderer section")
+
+	profile := newTestGenesisProfile()
+	profile.Orderer.ConsensusType = "kafka"
+	_, err = NewGenesisBlock("mychannel", profile)
+	assert.Error(t, err, "expecting error for unsupported consensus type")
+
+	profile = newTestGenesisProfile()
+	profile.Orderer.Consenters[0].ServerTLSCert = nil
+	_, err = NewGenesisBlock("mychannel", profile)
+	assert.Error(t, err, "expecting error for missing consenter TLS certificate")
+
+	profile = newTestGenesisProfile()
+	profile.Orderer.Organizations = nil
+	_, err = NewGenesisBlock("mychannel", profile)
+	assert.Error(t, err, "expecting error for missing orderer organizations")
+}
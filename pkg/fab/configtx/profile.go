@@ -0,0 +1,323 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// ChannelProfile contains the settings used to create an application channel
+// (the equivalent of a channel profile in configtx.yaml)
+type ChannelProfile struct {
+	Consortium  string
+	Application *Application
+}
+
+// CreateChannelCreateTx creates a channel creation transaction for the given channel from the given
+// profile. The returned bytes may be used as the ChannelConfig of resmgmt.SaveChannelRequest in place
+// of a channel transaction generated by configtxgen.
+func CreateChannelCreateTx(channelID string, profile *ChannelProfile) ([]byte, error) {
+	configUpdate, err := NewChannelCreateConfigUpdate(channelID, profile)
+	if err != nil {
+		return nil, err
+	}
+	return CreateConfigUpdateEnvelope(channelID, configUpdate)
+}
+
+// NewChannelCreateConfigUpdate creates the config update that creates the given channel from the given profile
+func NewChannelCreateConfigUpdate(channelID string, profile *ChannelProfile) (*common.ConfigUpdate, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+	if profile == nil || profile.Consortium == "" {
+		return nil, errors.New("consortium is required")
+	}
+	if profile.Application == nil {
+		return nil, errors.New("application section is required")
+	}
+
+	appGroup, err := newApplicationGroup(profile.Application)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create application group")
+	}
+
+	newChannelGroup := &common.ConfigGroup{
+		Groups: map[string]*common.ConfigGroup{ApplicationGroupKey: appGroup},
+	}
+
+	// The organizations are expected to be defined in the consortium, so they're
+	// part of the template (only the application values and policies are new)
+	template := proto.Clone(newChannelGroup).(*common.ConfigGroup)
+	template.Groups[ApplicationGroupKey].Values = nil
+	template.Groups[ApplicationGroupKey].Policies = nil
+
+	configUpdate, err := ComputeUpdate(channelID, &common.Config{ChannelGroup: template}, &common.Config{ChannelGroup: newChannelGroup})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to compute update")
+	}
+
+	consortium, err := proto.Marshal(&common.Consortium{Name: profile.Consortium})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal consortium failed")
+	}
+
+	// The consortium name is added to the read and write sets as required for channel creation
+	configUpdate.ReadSet.Values[channelConfig.ConsortiumKey] = &common.ConfigValue{Version: 0}
+	configUpdate.WriteSet.Values[channelConfig.ConsortiumKey] = &common.ConfigValue{
+		Version: 0,
+		Value:   consortium,
+	}
+
+	return configUpdate, nil
+}
+
+// ChannelProfileFromFile loads the given channel profile from a configtx.yaml file.
+// MSP directories are resolved relative to the directory of the file.
+func ChannelProfileFromFile(path, profileName string) (*ChannelProfile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read configtx file [%s]", path)
+	}
+	return ChannelProfileFromYAML(data, profileName, filepath.Dir(path))
+}
+
+// ChannelProfileFromYAML loads the given channel profile from configtx.yaml content.
+// MSP directories are resolved relative to baseDir.
+func ChannelProfileFromYAML(data []byte, profileName, baseDir string) (*ChannelProfile, error) {
+	configtx := &yamlConfigtx{}
+	if err := yaml.Unmarshal(data, configtx); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal configtx yaml")
+	}
+
+	p, ok := configtx.Profiles[profileName]
+	if !ok {
+		return nil, errors.Errorf("profile [%s] not found", profileName)
+	}
+
+	profile := &ChannelProfile{Consortium: p.Consortium}
+	if p.Application == nil {
+		return profile, nil
+	}
+
+	app := &Application{
+		Organizations: make(map[string]*Organization),
+		ACLs:          p.Application.ACLs,
+		Capabilities:  enabledCapabilities(p.Application.Capabilities),
+	}
+
+	var err error
+	if app.Policies, err = fromYAMLPolicies(p.Application.Policies); err != nil {
+		return nil, err
+	}
+
+	for _, o := range p.Application.Organizations {
+		org, err := fromYAMLOrganization(o, baseDir)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to load organization "+o.Name)
+		}
+		app.Organizations[org.Name] = org
+	}
+	profile.Application = app
+
+	return profile, nil
+}
+
+// OrganizationFromMSPDir loads the certificates of an organization from an MSP directory
+// (with the layout generated by cryptogen)
+func OrganizationFromMSPDir(name, mspID, dir string) (*Organization, error) {
+	org := &Organization{Name: name, MSPID: mspID}
+
+	var err error
+	if org.RootCerts, err = readPEMDir(filepath.Join(dir, "cacerts")); err != nil {
+		return nil, err
+	}
+	if org.IntermediateCerts, err = readPEMDir(filepath.Join(dir, "intermediatecerts")); err != nil {
+		return nil, err
+	}
+	if org.Admins, err = readPEMDir(filepath.Join(dir, "admincerts")); err != nil {
+		return nil, err
+	}
+	if org.TLSRootCerts, err = readPEMDir(filepath.Join(dir, "tlscacerts")); err != nil {
+		return nil, err
+	}
+	if org.RevocationList, err = readPEMDir(filepath.Join(dir, "crls")); err != nil {
+		return nil, err
+	}
+
+	if len(org.RootCerts) == 0 {
+		return nil, errors.Errorf("no root certificates found in MSP directory [%s]", dir)
+	}
+
+	return org, nil
+}
+
+func newApplicationGroup(app *Application) (*common.ConfigGroup, error) {
+	group := &common.ConfigGroup{
+		ModPolicy: AdminsPolicyKey,
+		Groups:    make(map[string]*common.ConfigGroup),
+		Values:    make(map[string]*common.ConfigValue),
+		Policies:  make(map[string]*common.ConfigPolicy),
+	}
+
+	if err := setPolicies(group, app.Policies); err != nil {
+		return nil, err
+	}
+
+	if len(app.ACLs) > 0 {
+		acls := &pb.ACLs{Acls: make(map[string]*pb.APIResource)}
+		for name, policyRef := range app.ACLs {
+			acls.Acls[name] = &pb.APIResource{PolicyRef: policyRef}
+		}
+		if err := setValue(group, ACLsKey, acls); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := setCapabilities(group, app.Capabilities); err != nil {
+		return nil, err
+	}
+
+	for name, org := range app.Organizations {
+		orgGroup, err := NewOrganizationGroup(org)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to create organization group "+name)
+		}
+		group.Groups[name] = orgGroup
+	}
+
+	return group, nil
+}
+
+type yamlConfigtx struct {
+	Profiles map[string]*yamlProfile `yaml:"Profiles"`
+}
+
+type yamlProfile struct {
+	Consortium  string           `yaml:"Consortium"`
+	Application *yamlApplication `yaml:"Application"`
+}
+
+type yamlApplication struct {
+	Organizations []*yamlOrganization    `yaml:"Organizations"`
+	ACLs          map[string]string      `yaml:"ACLs"`
+	Capabilities  map[string]bool        `yaml:"Capabilities"`
+	Policies      map[string]*yamlPolicy `yaml:"Policies"`
+}
+
+type yamlOrganization struct {
+	Name        string                 `yaml:"Name"`
+	ID          string                 `yaml:"ID"`
+	MSPDir      string                 `yaml:"MSPDir"`
+	Policies    map[string]*yamlPolicy `yaml:"Policies"`
+	AnchorPeers []*yamlAnchorPeer      `yaml:"AnchorPeers"`
+}
+
+type yamlPolicy struct {
+	Type string `yaml:"Type"`
+	Rule string `yaml:"Rule"`
+}
+
+type yamlAnchorPeer struct {
+	Host string `yaml:"Host"`
+	Port int32  `yaml:"Port"`
+}
+
+func fromYAMLOrganization(o *yamlOrganization, baseDir string) (*Organization, error) {
+	mspDir := o.MSPDir
+	if !filepath.IsAbs(mspDir) {
+		mspDir = filepath.Join(baseDir, mspDir)
+	}
+
+	org, err := OrganizationFromMSPDir(o.Name, o.ID, mspDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ap := range o.AnchorPeers {
+		org.AnchorPeers = append(org.AnchorPeers, AnchorPeer{Host: ap.Host, Port: ap.Port})
+	}
+
+	if org.Policies, err = fromYAMLPolicies(o.Policies); err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+func fromYAMLPolicies(policies map[string]*yamlPolicy) (map[string]*Policy, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	converted := make(map[string]*Policy)
+	for name, p := range policies {
+		switch p.Type {
+		case "ImplicitMeta":
+			fields := strings.Fields(p.Rule)
+			if len(fields) != 2 {
+				return nil, errors.Errorf("invalid implicit meta policy rule [%s] for policy %s", p.Rule, name)
+			}
+			converted[name] = &Policy{Rule: fields[0], SubPolicy: fields[1]}
+		case "Signature":
+			envelope, err := cauthdsl.FromString(p.Rule)
+			if err != nil {
+				return nil, errors.WithMessage(err, "invalid signature policy "+name)
+			}
+			converted[name] = &Policy{Signature: envelope}
+		default:
+			return nil, errors.Errorf("unknown policy type [%s] for policy %s", p.Type, name)
+		}
+	}
+	return converted, nil
+}
+
+func enabledCapabilities(capabilities map[string]bool) []string {
+	var names []string
+	for name, enabled := range capabilities {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readPEMDir(dir string) ([][]byte, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read directory [%s]", dir)
+	}
+
+	var contents [][]byte
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read file [%s]", f.Name())
+		}
+		contents = append(contents, content)
+	}
+	return contents, nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+const testConfigtx = `
+Organizations:
+    - &Org1
+        Name: Org1MSP
+        ID: Org1MSP
+        MSPDir: msp/org1
+        Policies: &Org1Policies
+            Readers:
+                Type: Signature
+                Rule: "OR('Org1MSP.member')"
+            Writers:
+                Type: Signature
+                Rule: "OR('Org1MSP.member')"
+            Admins:
+                Type: Signature
+                Rule: "OR('Org1MSP.admin')"
+        AnchorPeers:
+            - Host: peer0.org1.example.com
+              Port: 7051
+    - &Org2
+        Name: Org2MSP
+        ID: Org2MSP
+        MSPDir: msp/org2
+
+Capabilities:
+    Application: &ApplicationCapabilities
+        V1_2: true
+        V1_1: false
+
+Application: &ApplicationDefaults
+    ACLs:
+        peer/Propose: /Channel/Application/Writers
+    Organizations:
+    Policies:
+        Readers:
+            Type: ImplicitMeta
+            Rule: "ANY Readers"
+        Writers:
+            Type: ImplicitMeta
+            Rule: "ANY Writers"
+        Admins:
+            Type: ImplicitMeta
+            Rule: "MAJORITY Admins"
+    Capabilities:
+        <<: *ApplicationCapabilities
+
+Profiles:
+    TwoOrgsChannel:
+        Consortium: SampleConsortium
+        Application:
+            <<: *ApplicationDefaults
+            Organizations:
+                - *Org1
+                - *Org2
+`
+
+func writeTestMSPDirs(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "configtx")
+	require.NoError(t, err)
+
+	for _, org := range []string{"org1", "org2"} {
+		for _, sub := range []string{"cacerts", "admincerts"} {
+			certDir := filepath.Join(dir, "msp", org, sub)
+			require.NoError(t, os.MkdirAll(certDir, 0755))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(certDir, "cert.pem"), []byte(org+"-"+sub), 0644))
+		}
+	}
+	return dir
+}
+
+func TestChannelProfileFromYAML(t *testing.T) {
+	dir := writeTestMSPDirs(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "configtx.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(testConfigtx), 0644))
+
+	profile, err := ChannelProfileFromFile(path, "TwoOrgsChannel")
+	require.NoError(t, err)
+	assert.Equal(t, "SampleConsortium", profile.Consortium)
+	require.NotNil(t, profile.Application)
+	assert.Equal(t, []string{"V1_2"}, profile.Application.Capabilities)
+	assert.Equal(t, "/Channel/Application/Writers", profile.Application.ACLs["peer/Propose"])
+	assert.Len(t, profile.Application.Policies, 3)
+	assert.Equal(t, "MAJORITY", profile.Application.Policies["Admins"].Rule)
+
+	org1 := profile.Application.Organizations["Org1MSP"]
+	require.NotNil(t, org1)
+	assert.Equal(t, [][]byte{[]byte("org1-cacerts")}, org1.RootCerts)
+	assert.Equal(t, [][]byte{[]byte("org1-admincerts")}, org1.Admins)
+	assert.Equal(t, []AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}}, org1.AnchorPeers)
+	assert.NotNil(t, org1.Policies["Admins"].Signature)
+
+	_, err = ChannelProfileFromFile(path, "UnknownProfile")
+	assert.Error(t, err)
+
+	_, err = ChannelProfileFromYAML([]byte(testConfigtx), "TwoOrgsChannel", filepath.Join(dir, "unknown"))
+	assert.Error(t, err, "expecting error for missing MSP directories")
+}
+
+func TestCreateChannelCreateTx(t *testing.T) {
+	dir := writeTestMSPDirs(t)
+	defer os.RemoveAll(dir)
+
+	profile, err := ChannelProfileFromYAML([]byte(testConfigtx), "TwoOrgsChannel", dir)
+	require.NoError(t, err)
+
+	tx, err := CreateChannelCreateTx("mychannel", profile)
+	require.NoError(t, err)
+
+	configUpdateBytes, err := resource.ExtractChannelConfig(tx)
+	require.NoError(t, err)
+	configUpdate := &common.ConfigUpdate{}
+	require.NoError(t, proto.Unmarshal(configUpdateBytes, configUpdate))
+
+	assert.Equal(t, "mychannel", configUpdate.ChannelId)
+
+	consortium := &common.Consortium{}
+	require.NoError(t, proto.Unmarshal(configUpdate.WriteSet.Values["Consortium"].Value, consortium))
+	assert.Equal(t, "SampleConsortium", consortium.Name)
+	assert.NotNil(t, configUpdate.ReadSet.Values["Consortium"])
+
+	readApp := configUpdate.ReadSet.Groups[ApplicationGroupKey]
+	require.NotNil(t, readApp)
+	assert.Equal(t, uint64(0), readApp.Version)
+	assert.Len(t, readApp.Groups, 2, "expecting organizations in read set")
+
+	writeApp := configUpdate.WriteSet.Groups[ApplicationGroupKey]
+	require.NotNil(t, writeApp)
+	assert.Equal(t, uint64(1), writeApp.Version)
+	assert.Equal(t, AdminsPolicyKey, writeApp.ModPolicy)
+	assert.Len(t, writeApp.Policies, 3)
+	assert.NotNil(t, writeApp.Values[ACLsKey])
+	assert.NotNil(t, writeApp.Values["Capabilities"])
+	assert.Equal(t, uint64(0), writeApp.Groups["Org1MSP"].Version)
+
+	_, err = CreateChannelCreateTx("", profile)
+	assert.Error(t, err)
+
+	_, err = CreateChannelCreateTx("mychannel", &ChannelProfile{Application: profile.Application})
+	assert.Error(t, err, "expecting error for missing consortium")
+
+	_, err = CreateChannelCreateTx("mychannel", &ChannelProfile{Consortium: "SampleConsortium"})
+	assert.Error(t, err, "expecting error for missing application")
+}
@@ -0,0 +1,291 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// ComputeUpdate computes the config update (read set and write set) that transforms
+// the original config into the updated config
+func ComputeUpdate(channelID string, original, updated *common.Config) (*common.ConfigUpdate, error) {
+	if original == nil || original.ChannelGroup == nil {
+		return nil, errors.New("no channel group included for original config")
+	}
+	if updated == nil || updated.ChannelGroup == nil {
+		return nil, errors.New("no channel group included for updated config")
+	}
+
+	readSet, writeSet, groupUpdated := computeGroupUpdate(original.ChannelGroup, updated.ChannelGroup)
+	if !groupUpdated {
+		return nil, errors.New("no differences detected between original and updated config")
+	}
+
+	return &common.ConfigUpdate{
+		ChannelId: channelID,
+		ReadSet:   readSet,
+		WriteSet:  writeSet,
+	}, nil
+}
+
+// CreateConfigUpdateEnvelope wraps the given config update (along with optional signatures) into
+// a CONFIG_UPDATE envelope. The marshalled envelope may be passed as the ChannelConfig of
+// resmgmt.SaveChannelRequest.
+func CreateConfigUpdateEnvelope(channelID string, configUpdate *common.ConfigUpdate, signatures ...*common.ConfigSignature) ([]byte, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+	if configUpdate == nil {
+		return nil, errors.New("config update is required")
+	}
+
+	configUpdateBytes, err := proto.Marshal(configUpdate)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal config update failed")
+	}
+
+	return createConfigUpdateEnvelope(channelID, configUpdateBytes, signatures...)
+}
+
+// createConfigUpdateEnvelope wraps the marshalled config update; signatures must be
+// computed over exactly these bytes
+func createConfigUpdateEnvelope(channelID string, configUpdateBytes []byte, signatures ...*common.ConfigSignature) ([]byte, error) {
+	configUpdateEnvelopeBytes, err := proto.Marshal(&common.ConfigUpdateEnvelope{
+		ConfigUpdate: configUpdateBytes,
+		Signatures:   signatures,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal config update envelope failed")
+	}
+
+	channelHeaderBytes, err := proto.Marshal(&common.ChannelHeader{
+		Type:      int32(common.HeaderType_CONFIG_UPDATE),
+		ChannelId: channelID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal channel header failed")
+	}
+
+	payloadBytes, err := proto.Marshal(&common.Payload{
+		Header: &common.Header{ChannelHeader: channelHeaderBytes},
+		Data:   configUpdateEnvelopeBytes,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal payload failed")
+	}
+
+	envelopeBytes, err := proto.Marshal(&common.Envelope{Payload: payloadBytes})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal envelope failed")
+	}
+
+	return envelopeBytes, nil
+}
+
+func computePoliciesMapUpdate(original, updated map[string]*common.ConfigPolicy) (readSet, writeSet, sameSet map[string]*common.ConfigPolicy, updatedMembers bool) {
+	readSet = make(map[string]*common.ConfigPolicy)
+	writeSet = make(map[string]*common.ConfigPolicy)
+
+	// All modified config goes into the read/write sets, but in case the map membership changes, we retain the
+	// config which was the same to add to the read/write sets
+	sameSet = make(map[string]*common.ConfigPolicy)
+
+	for policyName, originalPolicy := range original {
+		updatedPolicy, ok := updated[policyName]
+		if !ok {
+			updatedMembers = true
+			continue
+		}
+
+		if originalPolicy.ModPolicy == updatedPolicy.ModPolicy && proto.Equal(originalPolicy.Policy, updatedPolicy.Policy) {
+			sameSet[policyName] = &common.ConfigPolicy{
+				Version: originalPolicy.Version,
+			}
+			continue
+		}
+
+		writeSet[policyName] = &common.ConfigPolicy{
+			Version:   originalPolicy.Version + 1,
+			ModPolicy: updatedPolicy.ModPolicy,
+			Policy:    updatedPolicy.Policy,
+		}
+	}
+
+	for policyName, updatedPolicy := range updated {
+		if _, ok := original[policyName]; ok {
+			// If the updatedPolicy is in the original set of policies, it was already handled
+			continue
+		}
+		updatedMembers = true
+		writeSet[policyName] = &common.ConfigPolicy{
+			Version:   0,
+			ModPolicy: updatedPolicy.ModPolicy,
+			Policy:    updatedPolicy.Policy,
+		}
+	}
+
+	return
+}
+
+func computeValuesMapUpdate(original, updated map[string]*common.ConfigValue) (readSet, writeSet, sameSet map[string]*common.ConfigValue, updatedMembers bool) {
+	readSet = make(map[string]*common.ConfigValue)
+	writeSet = make(map[string]*common.ConfigValue)
+
+	// All modified config goes into the read/write sets, but in case the map membership changes, we retain the
+	// config which was the same to add to the read/write sets
+	sameSet = make(map[string]*common.ConfigValue)
+
+	for valueName, originalValue := range original {
+		updatedValue, ok := updated[valueName]
+		if !ok {
+			updatedMembers = true
+			continue
+		}
+
+		if originalValue.ModPolicy == updatedValue.ModPolicy && bytes.Equal(originalValue.Value, updatedValue.Value) {
+			sameSet[valueName] = &common.ConfigValue{
+				Version: originalValue.Version,
+			}
+			continue
+		}
+
+		writeSet[valueName] = &common.ConfigValue{
+			Version:   originalValue.Version + 1,
+			ModPolicy: updatedValue.ModPolicy,
+			Value:     updatedValue.Value,
+		}
+	}
+
+	for valueName, updatedValue := range updated {
+		if _, ok := original[valueName]; ok {
+			// If the updatedValue is in the original set of values, it was already handled
+			continue
+		}
+		updatedMembers = true
+		writeSet[valueName] = &common.ConfigValue{
+			Version:   0,
+			ModPolicy: updatedValue.ModPolicy,
+			Value:     updatedValue.Value,
+		}
+	}
+
+	return
+}
+
+func computeGroupsMapUpdate(original, updated map[string]*common.ConfigGroup) (readSet, writeSet, sameSet map[string]*common.ConfigGroup, updatedMembers bool) {
+	readSet = make(map[string]*common.ConfigGroup)
+	writeSet = make(map[string]*common.ConfigGroup)
+
+	// All modified config goes into the read/write sets, but in case the map membership changes, we retain the
+	// config which was the same to add to the read/write sets
+	sameSet = make(map[string]*common.ConfigGroup)
+
+	for groupName, originalGroup := range original {
+		updatedGroup, ok := updated[groupName]
+		if !ok {
+			updatedMembers = true
+			continue
+		}
+
+		groupReadSet, groupWriteSet, groupUpdated := computeGroupUpdate(originalGroup, updatedGroup)
+		if !groupUpdated {
+			sameSet[groupName] = groupReadSet
+			continue
+		}
+
+		readSet[groupName] = groupReadSet
+		writeSet[groupName] = groupWriteSet
+	}
+
+	for groupName, updatedGroup := range updated {
+		if _, ok := original[groupName]; ok {
+			// If the updatedGroup is in the original set of groups, it was already handled
+			continue
+		}
+		updatedMembers = true
+		_, groupWriteSet, _ := computeGroupUpdate(&common.ConfigGroup{}, updatedGroup)
+		writeSet[groupName] = &common.ConfigGroup{
+			Version:   0,
+			ModPolicy: updatedGroup.ModPolicy,
+			Policies:  groupWriteSet.Policies,
+			Values:    groupWriteSet.Values,
+			Groups:    groupWriteSet.Groups,
+		}
+	}
+
+	return
+}
+
+func computeGroupUpdate(original, updated *common.ConfigGroup) (readSet, writeSet *common.ConfigGroup, updatedGroup bool) {
+	readSetPolicies, writeSetPolicies, sameSetPolicies, policiesMembersUpdated := computePoliciesMapUpdate(original.Policies, updated.Policies)
+	readSetValues, writeSetValues, sameSetValues, valuesMembersUpdated := computeValuesMapUpdate(original.Values, updated.Values)
+	readSetGroups, writeSetGroups, sameSetGroups, groupsMembersUpdated := computeGroupsMapUpdate(original.Groups, updated.Groups)
+
+	// If the updated group is 'Equal' to the updated group (none of the members nor the mod policy changed)
+	if !(policiesMembersUpdated || valuesMembersUpdated || groupsMembersUpdated || original.ModPolicy != updated.ModPolicy) {
+
+		// If there were no modified entries in any of the policies/values/groups maps
+		if len(readSetPolicies) == 0 &&
+			len(writeSetPolicies) == 0 &&
+			len(readSetValues) == 0 &&
+			len(writeSetValues) == 0 &&
+			len(readSetGroups) == 0 &&
+			len(writeSetGroups) == 0 {
+
+			return &common.ConfigGroup{
+				Version: original.Version,
+			}, &common.ConfigGroup{
+				Version: original.Version,
+			}, false
+		}
+
+		return &common.ConfigGroup{
+			Version:  original.Version,
+			Policies: readSetPolicies,
+			Values:   readSetValues,
+			Groups:   readSetGroups,
+		}, &common.ConfigGroup{
+			Version:  original.Version,
+			Policies: writeSetPolicies,
+			Values:   writeSetValues,
+			Groups:   writeSetGroups,
+		}, true
+	}
+
+	for k, samePolicy := range sameSetPolicies {
+		readSetPolicies[k] = samePolicy
+		writeSetPolicies[k] = samePolicy
+	}
+
+	for k, sameValue := range sameSetValues {
+		readSetValues[k] = sameValue
+		writeSetValues[k] = sameValue
+	}
+
+	for k, sameGroup := range sameSetGroups {
+		readSetGroups[k] = sameGroup
+		writeSetGroups[k] = sameGroup
+	}
+
+	return &common.ConfigGroup{
+		Version:  original.Version,
+		Policies: readSetPolicies,
+		Values:   readSetValues,
+		Groups:   readSetGroups,
+	}, &common.ConfigGroup{
+		Version:   original.Version + 1,
+		Policies:  writeSetPolicies,
+		Values:    writeSetValues,
+		Groups:    writeSetGroups,
+		ModPolicy: updated.ModPolicy,
+	}, true
+}
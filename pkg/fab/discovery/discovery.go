@@ -10,12 +10,14 @@ import (
 	"context"
 	"sync"
 
+	"github.com/golang/protobuf/proto"
 	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/discovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
 	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	corecomm "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/audit"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
@@ -86,11 +88,42 @@ func (c *Client) Send(ctx context.Context, req *discclient.Request, targets ...f
 	return responses, errs
 }
 
-func (c *Client) send(reqCtx context.Context, req *discclient.Request, target fab.PeerConfig) (discclient.Response, error) {
-	opts := comm.OptsFromPeerConfig(&target)
-	opts = append(opts, comm.WithConnectTimeout(c.ctx.EndpointConfig().Timeout(fab.DiscoveryConnection)))
+// SendRaw sends the given request to the given set of peers and returns the unprocessed responses of the
+// Discovery service. This allows callers to inspect information (such as the endorsement descriptors)
+// which isn't exposed by the processed responses returned by Send.
+func (c *Client) SendRaw(ctx context.Context, req *discclient.Request, targets ...fab.PeerConfig) ([]*RawResponse, error) {
+	if len(targets) == 0 {
+		return nil, errors.New("no targets specified")
+	}
+
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(len(targets))
+
+	var responses []*RawResponse
+	var errs error
+
+	for _, target := range targets {
+		pconfig := target
+		go func() {
+			defer wg.Done()
+			resp, err := c.sendRaw(ctx, req, pconfig)
+			lock.Lock()
+			if err != nil {
+				errs = multi.Append(errs, errors.WithMessage(err, "From target: "+pconfig.URL))
+			} else {
+				responses = append(responses, &RawResponse{Response: resp, Target: pconfig.URL})
+			}
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return responses, errs
+}
 
-	conn, err := comm.NewConnection(c.ctx, target.URL, opts...)
+func (c *Client) send(reqCtx context.Context, req *discclient.Request, target fab.PeerConfig) (discclient.Response, error) {
+	conn, err := c.connect(target)
 	if err != nil {
 		return nil, err
 	}
@@ -101,13 +134,64 @@ func (c *Client) send(reqCtx context.Context, req *discclient.Request, target fa
 			return conn.ClientConn(), nil
 		},
 		func(msg []byte) ([]byte, error) {
-			return c.ctx.SigningManager().Sign(msg, c.ctx.PrivateKey())
+			sig, err := c.ctx.SigningManager().Sign(msg, c.ctx.PrivateKey())
+			if err == nil {
+				audit.RecordSigned(c.ctx, audit.PurposeDiscoveryRequest, msg, target.URL)
+			}
+			return sig, err
 		},
 		signerCacheSize,
 	)
 	return discClient.Send(reqCtx, req, c.authInfo)
 }
 
+func (c *Client) sendRaw(reqCtx context.Context, req *discclient.Request, target fab.PeerConfig) (*discovery.Response, error) {
+	reqToBeSent := *req.Request
+	reqToBeSent.Authentication = c.authInfo
+	payload, err := proto.Marshal(&reqToBeSent)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshaling request to bytes")
+	}
+
+	sig, err := c.ctx.SigningManager().Sign(payload, c.ctx.PrivateKey())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed signing request")
+	}
+	audit.RecordSigned(c.ctx, audit.PurposeDiscoveryRequest, payload, target.URL)
+
+	conn, err := c.connect(target)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	resp, err := discovery.NewDiscoveryClient(conn.ClientConn()).Discover(reqCtx, &discovery.SignedRequest{
+		Payload:   payload,
+		Signature: sig,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "discovery service refused our request")
+	}
+	if n, expected := len(resp.Results), len(req.Queries); n != expected {
+		return nil, errors.Errorf("sent %d queries but received %d responses back", expected, n)
+	}
+	return resp, nil
+}
+
+func (c *Client) connect(target fab.PeerConfig) (*comm.GRPCConnection, error) {
+	opts := comm.OptsFromPeerConfig(&target)
+	opts = append(opts, comm.WithConnectTimeout(c.ctx.EndpointConfig().Timeout(fab.DiscoveryConnection)))
+
+	return comm.NewConnection(c.ctx, target.URL, opts...)
+}
+
+// RawResponse contains the unprocessed response from the Discovery service of a peer
+// along with the endpoint URL of the peer that was invoked.
+type RawResponse struct {
+	*discovery.Response
+	Target string
+}
+
 type response struct {
 	discclient.Response
 	target string
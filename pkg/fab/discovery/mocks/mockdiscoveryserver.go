@@ -13,6 +13,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/discovery"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
 	"github.com/pkg/errors"
 )
 
@@ -20,6 +21,7 @@ import (
 type MockDiscoveryServer struct {
 	localPeersByOrg map[string]*discovery.Peers
 	peersByOrg      map[string]*discovery.Peers
+	layouts         []*discovery.Layout
 }
 
 // MockDiscoveryServerOpt is an option for the MockDiscoveryServer
@@ -39,6 +41,17 @@ func WithLocalPeers(peers ...*MockDiscoveryPeerEndpoint) MockDiscoveryServerOpt
 	}
 }
 
+// WithEndorsementLayouts sets the layouts of the endorsement descriptors returned for chaincode queries.
+// Each layout maps a group to the number of peers required from the group. The groups are the MSP IDs
+// of the peers added with WithPeers.
+func WithEndorsementLayouts(layouts ...map[string]uint32) MockDiscoveryServerOpt {
+	return func(s *MockDiscoveryServer) {
+		for _, layout := range layouts {
+			s.layouts = append(s.layouts, &discovery.Layout{QuantitiesByGroup: layout})
+		}
+	}
+}
+
 // NewServer returns a new MockDiscoveryServer
 func NewServer(opts ...MockDiscoveryServerOpt) *MockDiscoveryServer {
 	s := &MockDiscoveryServer{}
@@ -146,6 +159,23 @@ func (s *MockDiscoveryServer) getConfigQueryResult(q *discovery.ConfigQuery) *di
 }
 
 func (s *MockDiscoveryServer) getCCQueryResult(q *discovery.ChaincodeQuery) *discovery.QueryResult {
+	if s.layouts != nil {
+		var descriptors []*discovery.EndorsementDescriptor
+		for _, interest := range q.Interests {
+			descriptors = append(descriptors, &discovery.EndorsementDescriptor{
+				Chaincode:         interest.Chaincodes[0].Name,
+				EndorsersByGroups: s.peersByOrg,
+				Layouts:           s.layouts,
+			})
+		}
+		return &discovery.QueryResult{
+			Result: &discovery.QueryResult_CcQueryRes{
+				CcQueryRes: &discovery.ChaincodeQueryResult{
+					Content: descriptors,
+				},
+			},
+		}
+	}
 	return &discovery.QueryResult{
 		Result: &discovery.QueryResult_Error{
 			Error: &discovery.Error{
@@ -178,7 +208,7 @@ func asDiscoveryPeer(p *MockDiscoveryPeerEndpoint) *discovery.Peer {
 		Content: &gossip.GossipMessage_StateInfo{
 			StateInfo: &gossip.StateInfo{
 				Properties: &gossip.Properties{
-					Chaincodes:   nil,
+					Chaincodes:   p.Chaincodes,
 					LedgerHeight: p.LedgerHeight,
 				},
 				Timestamp: &gossip.PeerTime{
@@ -193,7 +223,13 @@ func asDiscoveryPeer(p *MockDiscoveryPeerEndpoint) *discovery.Peer {
 		panic(err.Error())
 	}
 
+	identity, err := proto.Marshal(&msp.SerializedIdentity{Mspid: p.MSPID})
+	if err != nil {
+		panic(err.Error())
+	}
+
 	return &discovery.Peer{
+		Identity: identity,
 		MembershipInfo: &gossip.Envelope{
 			Payload: memInfoPayload,
 		},
@@ -208,6 +244,7 @@ type MockDiscoveryPeerEndpoint struct {
 	MSPID        string
 	Endpoint     string
 	LedgerHeight uint64
+	Chaincodes   []*gossip.Chaincode
 }
 
 func asPeersByOrg(peers []*MockDiscoveryPeerEndpoint) map[string]*discovery.Peers {
@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	commtls "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm/tls"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
@@ -517,6 +518,9 @@ func (c *EndpointConfig) loadNetworkConfig(configEntity *endpointConfigEntity) e
 			Orderers: chOrderers,
 			Policies: fab.ChannelPolicies{
 				QueryChannelConfig: c.getChannelPolicy(chNwCfg, len(chPeers)),
+				Timeouts:           getTimeoutPolicy(chID, chNwCfg.Policies.Timeouts),
+				Selection:          getSelectionPolicy(chID, chNwCfg.Policies.Selection),
+				Observer:           fab.ObserverPolicy{Enabled: chNwCfg.Policies.Observer.Enabled},
 			},
 		}
 	}
@@ -574,6 +578,59 @@ func (c *EndpointConfig) getChannelPolicy(chNwCfg ChannelEndpointConfig, NoOfChP
 	}
 }
 
+// timeoutTypesByName maps the names of the operation types in the channel timeout policy to timeout types
+var timeoutTypesByName = map[string]fab.TimeoutType{
+	"peerconnection":    fab.PeerConnection,
+	"peerresponse":      fab.PeerResponse,
+	"query":             fab.Query,
+	"execute":           fab.Execute,
+	"eventreg":          fab.EventReg,
+	"ordererconnection": fab.OrdererConnection,
+	"ordererresponse":   fab.OrdererResponse,
+	"resmgmt":           fab.ResMgmt,
+}
+
+func getTimeoutPolicy(chID string, policy TimeoutPolicy) fab.TimeoutPolicy {
+	timeoutPolicy := fab.TimeoutPolicy{
+		Operations: toTimeouts(chID, policy.Operations),
+		Chaincodes: make(map[string]map[fab.TimeoutType]time.Duration),
+	}
+	for ccID, timeouts := range policy.Chaincodes {
+		timeoutPolicy.Chaincodes[strings.ToLower(ccID)] = toTimeouts(chID, timeouts)
+	}
+	return timeoutPolicy
+}
+
+func toTimeouts(chID string, timeoutsByName map[string]time.Duration) map[fab.TimeoutType]time.Duration {
+	timeouts := make(map[fab.TimeoutType]time.Duration)
+	for name, timeout := range timeoutsByName {
+		tType, ok := timeoutTypesByName[strings.ToLower(name)]
+		if !ok {
+			logger.Warnf("Ignoring timeout override for unknown operation type [%s] in channel [%s]", name, chID)
+			continue
+		}
+		timeouts[tType] = timeout
+	}
+	return timeouts
+}
+
+func getSelectionPolicy(chID string, policy SelectionPolicy) fab.SelectionPolicy {
+	return fab.SelectionPolicy{Balancer: getBalancer(chID, policy.Balancer), PreferLocalOrg: policy.PreferLocalOrg}
+}
+
+func getBalancer(chID string, name string) fab.BalancerType {
+	if name == "" {
+		return fab.Random
+	}
+	for _, balancer := range []fab.BalancerType{fab.Random, fab.RoundRobin, fab.Weighted} {
+		if strings.EqualFold(name, string(balancer)) {
+			return balancer
+		}
+	}
+	logger.Warnf("Ignoring unknown selection balancer [%s] in channel [%s]", name, chID)
+	return fab.Random
+}
+
 func (c *EndpointConfig) loadAllPeerConfigs(networkConfig *fab.NetworkConfig, entityPeers map[string]PeerConfig) error {
 	networkConfig.Peers = make(map[string]fab.PeerConfig)
 	for name, peerConfig := range entityPeers {
@@ -585,11 +642,16 @@ func (c *EndpointConfig) loadAllPeerConfigs(networkConfig *fab.NetworkConfig, en
 		if err != nil {
 			return errors.WithMessage(err, "failed to load peer network config")
 		}
-		networkConfig.Peers[name] = c.addMissingPeerConfigItems(fab.PeerConfig{
-			URL:         peerConfig.URL,
-			GRPCOptions: peerConfig.GRPCOptions,
-			TLSCACert:   tlsCert,
+		peer := c.addMissingPeerConfigItems(fab.PeerConfig{
+			URL:           peerConfig.URL,
+			GRPCOptions:   peerConfig.GRPCOptions,
+			TLSCACert:     tlsCert,
+			OperationsURL: peerConfig.OperationsURL,
 		})
+		if _, err := comm.TLSOptionsFromGRPC(peer.GRPCOptions); err != nil {
+			return errors.WithMessage(err, "invalid TLS options of peer ["+name+"]")
+		}
+		networkConfig.Peers[name] = peer
 	}
 	return nil
 }
@@ -605,11 +667,17 @@ func (c *EndpointConfig) loadAllOrdererConfigs(networkConfig *fab.NetworkConfig,
 		if err != nil {
 			return errors.WithMessage(err, "failed to load orderer network config")
 		}
-		networkConfig.Orderers[name] = c.addMissingOrdererConfigItems(fab.OrdererConfig{
-			URL:         ordererConfig.URL,
-			GRPCOptions: ordererConfig.GRPCOptions,
-			TLSCACert:   tlsCert,
+		orderer := c.addMissingOrdererConfigItems(fab.OrdererConfig{
+			URL:           ordererConfig.URL,
+			GRPCOptions:   ordererConfig.GRPCOptions,
+			TLSCACert:     tlsCert,
+			OperationsURL: ordererConfig.OperationsURL,
+			AdminURL:      ordererConfig.AdminURL,
 		})
+		if _, err := comm.TLSOptionsFromGRPC(orderer.GRPCOptions); err != nil {
+			return errors.WithMessage(err, "invalid TLS options of orderer ["+name+"]")
+		}
+		networkConfig.Orderers[name] = orderer
 	}
 	return nil
 }
@@ -1176,9 +1244,10 @@ func (c *EndpointConfig) getMappedPeer(host string) *fab.PeerConfig {
 	}
 
 	mappedConfig := fab.PeerConfig{
-		URL:         peerConfig.URL,
-		TLSCACert:   peerConfig.TLSCACert,
-		GRPCOptions: make(map[string]interface{}),
+		URL:           peerConfig.URL,
+		TLSCACert:     peerConfig.TLSCACert,
+		GRPCOptions:   make(map[string]interface{}),
+		OperationsURL: peerConfig.OperationsURL,
 	}
 
 	for key, val := range peerConfig.GRPCOptions {
@@ -1267,9 +1336,11 @@ func (c *EndpointConfig) getMappedOrderer(host string) *fab.OrdererConfig {
 	}
 
 	mappedConfig := fab.OrdererConfig{
-		URL:         ordererConfig.URL,
-		TLSCACert:   ordererConfig.TLSCACert,
-		GRPCOptions: make(map[string]interface{}),
+		URL:           ordererConfig.URL,
+		TLSCACert:     ordererConfig.TLSCACert,
+		GRPCOptions:   make(map[string]interface{}),
+		OperationsURL: ordererConfig.OperationsURL,
+		AdminURL:      ordererConfig.AdminURL,
 	}
 
 	for key, val := range ordererConfig.GRPCOptions {
@@ -1466,6 +1537,31 @@ func (c *EventServiceConfig) BlockHeightMonitorPeriod() time.Duration {
 	return period
 }
 
+// ShareConnections - if true then a single event service connection is shared by all of the clients of an
+// organization (i.e. identities with the same MSP ID) which listen on the same channel with the same options.
+// The connection is established using the identity of the first client.
+func (c *EventServiceConfig) ShareConnections() bool {
+	return c.backend.GetBool("client.eventService.shareConnections")
+}
+
+// KeepAliveTime is the keep-alive time of the event (deliver) connections. If set to 0 then the
+// keep-alive time from the peer's gRPC options is used.
+func (c *EventServiceConfig) KeepAliveTime() time.Duration {
+	return c.backend.GetDuration("client.eventService.keepAliveTime")
+}
+
+// KeepAliveTimeout is the keep-alive timeout of the event (deliver) connections. If set to 0 then the
+// keep-alive timeout from the peer's gRPC options is used.
+func (c *EventServiceConfig) KeepAliveTimeout() time.Duration {
+	return c.backend.GetDuration("client.eventService.keepAliveTimeout")
+}
+
+// StreamIdleTimeout - if >0 then the event client will disconnect from the peer (and reconnect) if no event
+// was received on the deliver stream within the given time.
+func (c *EventServiceConfig) StreamIdleTimeout() time.Duration {
+	return c.backend.GetDuration("client.eventService.streamIdleTimeout")
+}
+
 //peerChannelConfigHookFunc returns hook function for unmarshalling 'fab.PeerChannelConfig'
 // Rule : default set to 'true' if not provided in config
 func peerChannelConfigHookFunc() mapstructure.DecodeHookFunc {
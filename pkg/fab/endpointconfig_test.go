@@ -198,6 +198,9 @@ func TestEventServiceConfig(t *testing.T) {
 	customBackend.KeyValueMap["client.eventService.blockHeightLagThreshold"] = "4"
 	customBackend.KeyValueMap["client.eventService.reconnectBlockHeightLagThreshold"] = "7"
 	customBackend.KeyValueMap["client.eventService.blockHeightMonitorPeriod"] = "7s"
+	customBackend.KeyValueMap["client.eventService.keepAliveTime"] = "20s"
+	customBackend.KeyValueMap["client.eventService.keepAliveTimeout"] = "10s"
+	customBackend.KeyValueMap["client.eventService.streamIdleTimeout"] = "5m"
 
 	endpointConfig, err := ConfigFromBackend(customBackend)
 	require.NoError(t, err)
@@ -206,6 +209,9 @@ func TestEventServiceConfig(t *testing.T) {
 	assert.Equalf(t, 4, eventServiceConfig.BlockHeightLagThreshold(), "invalid value for blockHeightLagThreshold")
 	assert.Equalf(t, 7, eventServiceConfig.ReconnectBlockHeightLagThreshold(), "invalid value for reconnectBlockHeightLagThreshold")
 	assert.Equalf(t, 7*time.Second, eventServiceConfig.BlockHeightMonitorPeriod(), "invalid value for blockHeightMonitorPeriod")
+	assert.Equalf(t, 20*time.Second, eventServiceConfig.KeepAliveTime(), "invalid value for keepAliveTime")
+	assert.Equalf(t, 10*time.Second, eventServiceConfig.KeepAliveTimeout(), "invalid value for keepAliveTimeout")
+	assert.Equalf(t, 5*time.Minute, eventServiceConfig.StreamIdleTimeout(), "invalid value for streamIdleTimeout")
 }
 
 func checkTimeouts(endpointConfig fab.EndpointConfig, t *testing.T, errStr string) {
@@ -1479,3 +1485,29 @@ func TestSetDefault(t *testing.T) {
 	setDefault(dataMap, key3, !key3Val)
 	assert.Equal(t, key3Val, dataMap[key3])
 }
+
+func TestGetTimeoutPolicy(t *testing.T) {
+	policy := getTimeoutPolicy("mychannel", TimeoutPolicy{
+		Operations: map[string]time.Duration{"execute": 2 * time.Minute, "peerresponse": 30 * time.Second, "unknown": time.Second},
+		Chaincodes: map[string]map[string]time.Duration{"SlowCC": {"peerResponse": 5 * time.Minute}},
+	})
+
+	assert.Len(t, policy.Operations, 2)
+	assert.Equal(t, 2*time.Minute, policy.Timeout("", fab.Execute))
+	assert.Equal(t, 30*time.Second, policy.Timeout("examplecc", fab.PeerResponse))
+	assert.Equal(t, 5*time.Minute, policy.Timeout("slowcc", fab.PeerResponse))
+	assert.Equal(t, 2*time.Minute, policy.Timeout("slowcc", fab.Execute))
+	assert.Equal(t, time.Duration(0), policy.Timeout("slowcc", fab.Query))
+}
+
+func TestGetSelectionPolicy(t *testing.T) {
+	assert.Equal(t, fab.Random, getSelectionPolicy("mychannel", SelectionPolicy{}).Balancer)
+	assert.Equal(t, fab.RoundRobin, getSelectionPolicy("mychannel", SelectionPolicy{Balancer: "roundrobin"}).Balancer)
+	assert.Equal(t, fab.Weighted, getSelectionPolicy("mychannel", SelectionPolicy{Balancer: "Weighted"}).Balancer)
+	assert.Equal(t, fab.Random, getSelectionPolicy("mychannel", SelectionPolicy{Balancer: "unknown"}).Balancer)
+	assert.False(t, getSelectionPolicy("mychannel", SelectionPolicy{}).PreferLocalOrg)
+
+	policy := getSelectionPolicy("mychannel", SelectionPolicy{Balancer: "RoundRobin", PreferLocalOrg: true})
+	assert.Equal(t, fab.RoundRobin, policy.Balancer)
+	assert.True(t, policy.PreferLocalOrg)
+}
@@ -122,13 +122,11 @@ func (c *Client) close(force bool) bool {
 
 	if !force {
 		// Check if there are any outstanding registrations
-		regInfoCh := make(chan *esdispatcher.RegistrationInfo)
-		err := c.Submit(esdispatcher.NewRegistrationInfoEvent(regInfoCh))
+		regInfo, err := c.RegistrationInfo()
 		if err != nil {
 			logger.Debugf("Submit failed %s", err)
 			return false
 		}
-		regInfo := <-regInfoCh
 
 		logger.Debugf("Outstanding registrations: %d", regInfo.TotalRegistrations)
 
@@ -312,6 +310,23 @@ func (c *Client) registerConnectionEvent() (fab.Registration, chan *dispatcher.C
 	}
 }
 
+// RegistrationInfo returns the number of outstanding event registrations by type. An error is returned
+// if the dispatcher doesn't respond within the response timeout.
+func (c *Client) RegistrationInfo() (*esdispatcher.RegistrationInfo, error) {
+	// The channel is buffered so that the dispatcher doesn't block on a response which arrives after the timeout
+	regInfoCh := make(chan *esdispatcher.RegistrationInfo, 1)
+	if err := c.Submit(esdispatcher.NewRegistrationInfoEvent(regInfoCh)); err != nil {
+		return nil, err
+	}
+
+	select {
+	case regInfo := <-regInfoCh:
+		return regInfo, nil
+	case <-time.After(c.respTimeout):
+		return nil, errors.New("timeout waiting for registration info")
+	}
+}
+
 // Stopped returns true if the client has been stopped (disconnected)
 // and is no longer usable.
 func (c *Client) Stopped() bool {
@@ -322,6 +337,20 @@ func (c *Client) setStoppped() bool {
 	return atomic.CompareAndSwapInt32(&c.stopped, 0, 1)
 }
 
+// StreamState returns the current state of the event stream, i.e. the peer to which the client is connected,
+// the last block received and the number of blocks by which the stream lags behind the channel's peers
+func (c *Client) StreamState() (*dispatcher.StreamState, error) {
+	d, ok := c.Dispatcher().(streamStateProvider)
+	if !ok {
+		return nil, errors.New("stream state is not supported by the event dispatcher")
+	}
+	return d.StreamState()
+}
+
+type streamStateProvider interface {
+	StreamState() (*dispatcher.StreamState, error)
+}
+
 // ConnectionState returns the connection state
 func (c *Client) ConnectionState() ConnectionState {
 	return ConnectionState(atomic.LoadInt32(&c.connectionState))
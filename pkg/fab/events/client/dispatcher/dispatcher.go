@@ -37,7 +37,9 @@ type Dispatcher struct {
 	connectionProvider     api.ConnectionProvider
 	discoveryService       fab.DiscoveryService
 	ticker                 *time.Ticker
+	idleMonitorDone        chan struct{}
 	peer                   fab.Peer
+	lastEventTime          int64
 	lock                   sync.RWMutex
 }
 
@@ -85,6 +87,7 @@ func (ed *Dispatcher) HandleStopEvent(e esdispatcher.Event) {
 	if ed.ticker != nil {
 		ed.ticker.Stop()
 	}
+	ed.stopIdleMonitor()
 
 	ed.Dispatcher.HandleStopEvent(e)
 }
@@ -122,7 +125,7 @@ func (ed *Dispatcher) HandleConnectEvent(e esdispatcher.Event) {
 		return
 	}
 
-	conn, err := ed.connectionProvider(ed.context, ed.chConfig, peer)
+	conn, err := ed.connectionProvider(ed.context, ed.chConfig, ed.withConnectionOpts(peer))
 	if err != nil {
 		logger.Warnf("error creating connection: %s", err)
 		evt.ErrCh <- errors.WithMessage(err, fmt.Sprintf("could not create client conn"))
@@ -186,6 +189,13 @@ func (ed *Dispatcher) HandleConnectedEvent(e esdispatcher.Event) {
 		ed.ticker = time.NewTicker(ed.blockHeightMonitorPeriod)
 		go ed.monitorBlockHeight()
 	}
+
+	ed.UpdateLastEventTime()
+	if ed.streamIdleTimeout > 0 {
+		ed.stopIdleMonitor()
+		ed.idleMonitorDone = make(chan struct{})
+		go ed.monitorIdleStream(ed.idleMonitorDone)
+	}
 }
 
 // HandleDisconnectedEvent sends a 'disconnected' event to any registered listener
@@ -213,6 +223,7 @@ func (ed *Dispatcher) HandleDisconnectedEvent(e esdispatcher.Event) {
 	if ed.ticker != nil {
 		ed.ticker.Stop()
 	}
+	ed.stopIdleMonitor()
 }
 
 func (ed *Dispatcher) registerHandlers() {
@@ -305,7 +316,7 @@ func (ed *Dispatcher) monitorBlockHeight() {
 func (ed *Dispatcher) checkBlockHeight() bool {
 	logger.Debugf("Checking block heights on channel [%s]...", ed.chConfig.ID())
 
-	connectedPeer := ed.connectedPeer()
+	connectedPeer := ed.ConnectedPeer()
 	if connectedPeer == nil {
 		logger.Debugf("Not connected yet")
 		return true
@@ -384,7 +395,8 @@ func (ed *Dispatcher) setConnectedPeer(peer fab.Peer) {
 	ed.peer = peer
 }
 
-func (ed *Dispatcher) connectedPeer() fab.Peer {
+// ConnectedPeer returns the peer to which the client is connected (nil if the client isn't connected)
+func (ed *Dispatcher) ConnectedPeer() fab.Peer {
 	ed.lock.RLock()
 	defer ed.lock.RUnlock()
 	return ed.peer
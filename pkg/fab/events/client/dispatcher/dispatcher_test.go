@@ -7,10 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package dispatcher
 
 import (
+	"math"
 	"testing"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/lbp"
 
 	clientmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/mocks"
@@ -20,6 +23,8 @@ import (
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/keepalive"
 )
 
 var (
@@ -322,6 +327,157 @@ func TestDisconnectIfBlockHeightLags(t *testing.T) {
 	}
 }
 
+func TestDisconnectIfStreamIdle(t *testing.T) {
+	channelID := "testchannel"
+
+	dispatcher := New(
+		fabmocks.NewMockContext(
+			mspmocks.NewMockSigningIdentity("user1", "Org1MSP"),
+		),
+		fabmocks.NewMockChannelCfg(channelID),
+		clientmocks.NewDiscoveryService(peer1),
+		clientmocks.NewProviderFactory().Provider(
+			clientmocks.NewMockConnection(
+				clientmocks.WithLedger(
+					servicemocks.NewMockLedger(servicemocks.FilteredBlockEventFactory, sourceURL),
+				),
+			),
+		),
+		WithStreamIdleTimeout(500*time.Millisecond),
+	)
+
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	if err != nil {
+		t.Fatalf("Error getting event channel from dispatcher: %s", err)
+	}
+
+	regerrch := make(chan error)
+	regch := make(chan fab.Registration)
+	connch := make(chan *ConnectionEvent, 10)
+	dispatcherEventch <- NewRegisterConnectionEvent(connch, regch, regerrch)
+	select {
+	case <-regch:
+	case err := <-regerrch:
+		t.Fatalf("Error registering for connection events: %s", err)
+	}
+
+	errch := make(chan error)
+	dispatcherEventch <- NewConnectEvent(errch)
+	if err := <-errch; err != nil {
+		t.Fatalf("Error connecting: %s", err)
+	}
+	dispatcherEventch <- NewConnectedEvent()
+
+	select {
+	case e := <-connch:
+		assert.Truef(t, e.Connected, "expecting connected event")
+	case <-time.After(time.Second):
+		t.Fatal("Expecting connected event but got none")
+	}
+
+	select {
+	case e := <-connch:
+		assert.Falsef(t, e.Connected, "expecting disconnected event since no events were received on the stream")
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expecting disconnected event but got none")
+	}
+	assert.Nil(t, dispatcher.ConnectedPeer())
+}
+
+func TestStreamState(t *testing.T) {
+	p1 := clientmocks.NewMockPeer("peer1", "grpcs://peer1.example.com:7051", 10)
+	p2 := clientmocks.NewMockPeer("peer2", "grpcs://peer2.example.com:7051", 10)
+
+	channelID := "testchannel"
+
+	dispatcher := New(
+		fabmocks.NewMockContext(
+			mspmocks.NewMockSigningIdentity("user1", "Org1MSP"),
+		),
+		fabmocks.NewMockChannelCfg(channelID),
+		clientmocks.NewDiscoveryService(p1, p2),
+		clientmocks.NewProviderFactory().Provider(
+			clientmocks.NewMockConnection(
+				clientmocks.WithLedger(
+					servicemocks.NewMockLedger(servicemocks.FilteredBlockEventFactory, sourceURL),
+				),
+			),
+		),
+	)
+
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	state, err := dispatcher.StreamState()
+	require.NoError(t, err)
+	assert.False(t, state.Connected())
+	assert.Equal(t, uint64(math.MaxUint64), state.LastBlockNum)
+	assert.Equal(t, uint64(10), state.BlockHeightLag)
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	require.NoError(t, err)
+
+	errch := make(chan error)
+	dispatcherEventch <- NewConnectEvent(errch)
+	require.NoError(t, <-errch)
+	dispatcherEventch <- NewConnectedEvent()
+
+	blockProducer := servicemocks.NewBlockProducer()
+	for i := 0; i < 4; i++ {
+		dispatcherEventch <- esdispatcher.NewBlockEvent(blockProducer.NewBlock(channelID), sourceURL)
+	}
+	time.Sleep(250 * time.Millisecond)
+
+	state, err = dispatcher.StreamState()
+	require.NoError(t, err)
+	assert.True(t, state.Connected())
+	assert.Contains(t, []string{p1.URL(), p2.URL()}, state.PeerURL)
+	assert.Equal(t, uint64(3), state.LastBlockNum)
+	assert.Equal(t, uint64(6), state.BlockHeightLag)
+	assert.False(t, state.LastEventTime.IsZero())
+
+	stopResp := make(chan error)
+	dispatcherEventch <- esdispatcher.NewStopEvent(stopResp)
+	require.NoError(t, <-stopResp)
+}
+
+func TestKeepAliveConnectionOpts(t *testing.T) {
+	kap := keepalive.ClientParameters{Time: 20 * time.Second, Timeout: 10 * time.Second}
+	dispatcher := New(
+		fabmocks.NewMockContext(
+			mspmocks.NewMockSigningIdentity("user1", "Org1MSP"),
+		),
+		fabmocks.NewMockChannelCfg("testchannel"),
+		clientmocks.NewDiscoveryService(peer1),
+		nil,
+		WithKeepAliveParams(kap),
+	)
+
+	assert.Equal(t, peer1, dispatcher.withConnectionOpts(peer1), "expecting peer which isn't an event endpoint to be unchanged")
+
+	endpoint := &mockEventEndpoint{Peer: peer1}
+	wrapped, ok := dispatcher.withConnectionOpts(endpoint).(api.EventEndpoint)
+	require.True(t, ok)
+	assert.Equal(t, peer1.URL(), wrapped.URL())
+
+	p := &params{}
+	options.Apply(p, wrapped.Opts())
+	assert.Equal(t, kap, p.keepAliveParams)
+}
+
+type mockEventEndpoint struct {
+	fab.Peer
+}
+
+func (e *mockEventEndpoint) Opts() []options.Opt {
+	return nil
+}
+
 func checkEvent(connch chan *ConnectionEvent, errch chan error, state, expectedDisconnectErr string) {
 	for {
 		select {
@@ -12,6 +12,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/lbp"
+	"google.golang.org/grpc/keepalive"
 )
 
 type params struct {
@@ -19,6 +20,8 @@ type params struct {
 	blockHeightMonitorPeriod         time.Duration
 	blockHeightLagThreshold          int
 	reconnectBlockHeightLagThreshold int
+	keepAliveParams                  keepalive.ClientParameters
+	streamIdleTimeout                time.Duration
 }
 
 func defaultParams(config fab.EventServiceConfig) *params {
@@ -27,6 +30,11 @@ func defaultParams(config fab.EventServiceConfig) *params {
 		blockHeightMonitorPeriod:         config.BlockHeightMonitorPeriod(),
 		blockHeightLagThreshold:          config.BlockHeightLagThreshold(),
 		reconnectBlockHeightLagThreshold: config.ReconnectBlockHeightLagThreshold(),
+		keepAliveParams: keepalive.ClientParameters{
+			Time:    config.KeepAliveTime(),
+			Timeout: config.KeepAliveTimeout(),
+		},
+		streamIdleTimeout: config.StreamIdleTimeout(),
 	}
 }
 
@@ -75,6 +83,27 @@ func WithBlockHeightMonitorPeriod(value time.Duration) options.Opt {
 	}
 }
 
+// WithKeepAliveParams overrides the gRPC keep-alive parameters of the peer's gRPC options for the event connection.
+func WithKeepAliveParams(value keepalive.ClientParameters) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(keepAliveParamsSetter); ok {
+			setter.SetKeepAliveParams(value)
+		}
+	}
+}
+
+// WithStreamIdleTimeout indicates that the event client is to disconnect from the peer (and reconnect) if no event
+// was received on the stream within the given time. If set to 0 then this feature is disabled.
+// NOTE: The value should be greater than the maximum expected time between blocks on the channel, otherwise the
+// event client will reconnect unnecessarily while the channel is quiet.
+func WithStreamIdleTimeout(value time.Duration) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(streamIdleTimeoutSetter); ok {
+			setter.SetStreamIdleTimeout(value)
+		}
+	}
+}
+
 type loadBalancePolicySetter interface {
 	SetLoadBalancePolicy(value lbp.LoadBalancePolicy)
 }
@@ -110,3 +139,21 @@ func (p *params) SetBlockHeightMonitorPeriod(value time.Duration) {
 	logger.Debugf("BlockHeightMonitorPeriod: %s", value)
 	p.blockHeightMonitorPeriod = value
 }
+
+type keepAliveParamsSetter interface {
+	SetKeepAliveParams(value keepalive.ClientParameters)
+}
+
+func (p *params) SetKeepAliveParams(value keepalive.ClientParameters) {
+	logger.Debugf("KeepAliveParams: %#v", value)
+	p.keepAliveParams = value
+}
+
+type streamIdleTimeoutSetter interface {
+	SetStreamIdleTimeout(value time.Duration)
+}
+
+func (p *params) SetStreamIdleTimeout(value time.Duration) {
+	logger.Debugf("StreamIdleTimeout: %s", value)
+	p.streamIdleTimeout = value
+}
@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
+	"github.com/pkg/errors"
+)
+
+// StreamState describes the state of the event stream
+type StreamState struct {
+	// PeerURL is the URL of the peer to which the client is connected (empty if the client isn't connected)
+	PeerURL string
+	// LastBlockNum is the number of the last block received (math.MaxUint64 if no block was received yet)
+	LastBlockNum uint64
+	// LastEventTime is the time at which the last event was received on the stream (or at which the
+	// client connected if no event was received since)
+	LastEventTime time.Time
+	// BlockHeightLag is the number of blocks by which the stream lags behind the highest
+	// block height of the channel's peers
+	BlockHeightLag uint64
+}
+
+// Connected returns true if the client is connected to a peer
+func (s *StreamState) Connected() bool {
+	return s.PeerURL != ""
+}
+
+// StreamState returns the current state of the event stream. The block height lag is computed from the
+// block heights of the channel's peers which are provided by the discovery service.
+func (ed *Dispatcher) StreamState() (*StreamState, error) {
+	state := &StreamState{
+		LastBlockNum:  ed.LastBlockNum(),
+		LastEventTime: ed.LastEventTime(),
+	}
+	if peer := ed.ConnectedPeer(); peer != nil {
+		state.PeerURL = peer.URL()
+	}
+
+	peers, err := ed.discoveryService.GetPeers()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error getting block heights of the channel's peers")
+	}
+
+	// Note that the block height is LastBlockNum+1 which is 0 if no block was received yet
+	if maxHeight, height := getMaxBlockHeight(peers), state.LastBlockNum+1; maxHeight > height {
+		state.BlockHeightLag = maxHeight - height
+	}
+	return state, nil
+}
+
+// UpdateLastEventTime records that an event was received on the stream
+func (ed *Dispatcher) UpdateLastEventTime() {
+	atomic.StoreInt64(&ed.lastEventTime, time.Now().UnixNano())
+}
+
+// LastEventTime returns the time at which the last event was received on the stream
+func (ed *Dispatcher) LastEventTime() time.Time {
+	t := atomic.LoadInt64(&ed.lastEventTime)
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, t)
+}
+
+func (ed *Dispatcher) monitorIdleStream(done <-chan struct{}) {
+	logger.Debugf("Starting idle stream monitor on channel [%s]. Idle timeout: %s", ed.chConfig.ID(), ed.streamIdleTimeout)
+
+	ticker := time.NewTicker(ed.streamIdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			logger.Debugf("Stopping idle stream monitor on channel [%s]", ed.chConfig.ID())
+			return
+		case <-ticker.C:
+			idle := time.Since(ed.LastEventTime())
+			if idle < ed.streamIdleTimeout {
+				continue
+			}
+
+			logger.Warnf("No events received on channel [%s] for %s. Disconnecting from the peer...", ed.chConfig.ID(), idle)
+			if err := ed.disconnect(); err != nil {
+				logger.Warnf("Error disconnecting event client from channel [%s]: %s", ed.chConfig.ID(), err)
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (ed *Dispatcher) stopIdleMonitor() {
+	if ed.idleMonitorDone != nil {
+		close(ed.idleMonitorDone)
+		ed.idleMonitorDone = nil
+	}
+}
+
+// withConnectionOpts returns the peer with the additional connection options of the dispatcher
+// (which take precedence over the options of the peer config)
+func (ed *Dispatcher) withConnectionOpts(peer fab.Peer) fab.Peer {
+	if ed.keepAliveParams.Time == 0 && ed.keepAliveParams.Timeout == 0 {
+		return peer
+	}
+
+	eventEndpoint, ok := peer.(api.EventEndpoint)
+	if !ok {
+		return peer
+	}
+
+	return &endpointWithOpts{
+		EventEndpoint: eventEndpoint,
+		opts:          []options.Opt{comm.WithKeepAliveParams(ed.keepAliveParams)},
+	}
+}
+
+type endpointWithOpts struct {
+	api.EventEndpoint
+	opts []options.Opt
+}
+
+// Opts returns the options of the endpoint followed by the additional options
+func (e *endpointWithOpts) Opts() []options.Opt {
+	return append(append([]options.Opt{}, e.EventEndpoint.Opts()...), e.opts...)
+}
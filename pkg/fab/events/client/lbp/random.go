@@ -7,10 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package lbp
 
 import (
-	"math/rand"
-
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/random"
 )
 
 var logger = logging.NewLogger("fabsdk/fab")
@@ -31,7 +30,7 @@ func (lbp *Random) Choose(peers []fab.Peer) (fab.Peer, error) {
 		return nil, nil
 	}
 
-	index := rand.Intn(len(peers))
+	index := random.Intn(len(peers))
 	logger.Debugf("Choosing peer at index %d", index)
 	return peers[index], nil
 }
@@ -121,14 +121,20 @@ func (c *Client) setSeekFromLastBlockReceived() error {
 
 	// Make sure that, when we reconnect, we receive all of the events that we've missed
 	lastBlockNum := c.Dispatcher().LastBlockNum()
+	if c.exactlyOnce {
+		// Resume from the last block whose events were dispatched to all registrations. Events
+		// which were already dispatched to a registration are suppressed by the dispatcher.
+		lastBlockNum = c.Dispatcher().CheckpointBlockNum()
+	}
+
 	if lastBlockNum < math.MaxUint64 {
 		c.seekType = seek.FromBlock
-		c.fromBlock = c.Dispatcher().LastBlockNum() + 1
+		c.fromBlock = lastBlockNum + 1
 		logger.Debugf("Setting seek info from last block received + 1: %d", c.fromBlock)
 	} else {
-		// We haven't received any blocks yet. Just ask for the newest
-		logger.Debugf("Setting seek info from newest")
-		c.seekType = seek.Newest
+		// We haven't received any blocks yet so seek from the original position (which is
+		// 'newest' by default) in order not to skip the blocks requested by the caller
+		logger.Debugf("No blocks received yet. Keeping seek info: %s", c.seekType)
 	}
 	return nil
 }
@@ -74,6 +74,7 @@ func (ed *Dispatcher) handleSeekEvent(e esdispatcher.Event) {
 func (ed *Dispatcher) handleEvent(e esdispatcher.Event) {
 	delevent := e.(*connection.Event)
 	evt := delevent.Event.(*pb.DeliverResponse)
+	ed.UpdateLastEventTime()
 	switch response := evt.Type.(type) {
 	case *pb.DeliverResponse_Status:
 		ed.handleDeliverResponseStatus(response)
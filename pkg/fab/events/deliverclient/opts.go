@@ -19,6 +19,7 @@ type params struct {
 	seekType     seek.Type
 	fromBlock    uint64
 	respTimeout  time.Duration
+	exactlyOnce  bool
 }
 
 func defaultParams() *params {
@@ -82,3 +83,8 @@ func (p *params) SetResponseTimeout(value time.Duration) {
 	logger.Debugf("ResponseTimeout: %s", value)
 	p.respTimeout = value
 }
+
+func (p *params) SetExactlyOnceDispatch(value bool) {
+	logger.Debugf("ExactlyOnceDispatch: %t", value)
+	p.exactlyOnce = value
+}
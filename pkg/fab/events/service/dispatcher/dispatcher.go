@@ -45,6 +45,7 @@ type HandlerRegistry map[reflect.Type]Handler
 type Dispatcher struct {
 	params
 	lastBlockNum               uint64
+	checkpointBlockNum         uint64
 	updateLastBlockInfoOnly    bool
 	state                      int32
 	eventch                    chan interface{}
@@ -63,13 +64,14 @@ func New(opts ...options.Opt) *Dispatcher {
 	options.Apply(params, opts)
 
 	return &Dispatcher{
-		params:          *params,
-		handlers:        make(map[reflect.Type]Handler),
-		eventch:         make(chan interface{}, params.eventConsumerBufferSize),
-		txRegistrations: make(map[string]*TxStatusReg),
-		ccRegistrations: make(map[string]*ChaincodeReg),
-		state:           dispatcherStateInitial,
-		lastBlockNum:    math.MaxUint64,
+		params:             *params,
+		handlers:           make(map[reflect.Type]Handler),
+		eventch:            make(chan interface{}, params.eventConsumerBufferSize),
+		txRegistrations:    make(map[string]*TxStatusReg),
+		ccRegistrations:    make(map[string]*ChaincodeReg),
+		state:              dispatcherStateInitial,
+		lastBlockNum:       math.MaxUint64,
+		checkpointBlockNum: math.MaxUint64,
 	}
 }
 
@@ -137,6 +139,19 @@ func (ed *Dispatcher) LastBlockNum() uint64 {
 	return atomic.LoadUint64(&ed.lastBlockNum)
 }
 
+// CheckpointBlockNum returns the block number of the last block whose events were dispatched to all registrations.
+// This may lag LastBlockNum if the dispatcher is still dispatching the events of the last block received.
+func (ed *Dispatcher) CheckpointBlockNum() uint64 {
+	return atomic.LoadUint64(&ed.checkpointBlockNum)
+}
+
+func (ed *Dispatcher) updateCheckpointBlockNum(blockNum uint64) {
+	checkpointBlockNum := atomic.LoadUint64(&ed.checkpointBlockNum)
+	if checkpointBlockNum == math.MaxUint64 || blockNum > checkpointBlockNum {
+		atomic.StoreUint64(&ed.checkpointBlockNum, blockNum)
+	}
+}
+
 // updateLastBlockNum updates the value of lastBlockNum and
 // returns the updated value.
 func (ed *Dispatcher) updateLastBlockNum(blockNum uint64) error {
@@ -302,36 +317,95 @@ func (ed *Dispatcher) handleRegistrationInfoEvent(e Event) {
 func (ed *Dispatcher) HandleBlock(block *cb.Block, sourceURL string) {
 	logger.Debugf("Handling block event - Block #%d", block.Header.Number)
 
-	if err := ed.updateLastBlockNum(block.Header.Number); err != nil {
-		logger.Error(err.Error())
-		return
-	}
-
-	if ed.updateLastBlockInfoOnly {
-		ed.updateLastBlockInfoOnly = false
+	if !ed.acceptBlock(block.Header.Number) {
 		return
 	}
 
 	ed.publishBlockEvents(block, sourceURL)
-	ed.publishFilteredBlockEvents(toFilteredBlock(block), sourceURL)
+	// The filtered block is only created if there are registrations which need it since it
+	// requires unmarshalling each of the transactions in the block
+	if ed.hasFilteredRegistrations() {
+		ed.publishFilteredBlockEvents(toFilteredBlock(block), sourceURL)
+	}
+	ed.updateCheckpointBlockNum(block.Header.Number)
 }
 
 // HandleFilteredBlock handles a filtered block event
 func (ed *Dispatcher) HandleFilteredBlock(fblock *pb.FilteredBlock, sourceURL string) {
 	logger.Debugf("Handling filtered block event - Block #%d", fblock.Number)
 
-	if err := ed.updateLastBlockNum(fblock.Number); err != nil {
-		logger.Error(err.Error())
+	if !ed.acceptBlock(fblock.Number) {
 		return
 	}
 
+	logger.Debug("Publishing filtered block event...")
+	ed.publishFilteredBlockEvents(fblock, sourceURL)
+	ed.updateCheckpointBlockNum(fblock.Number)
+}
+
+// acceptBlock returns true if the events of the given block are to be published
+func (ed *Dispatcher) acceptBlock(blockNum uint64) bool {
+	if err := ed.updateLastBlockNum(blockNum); err != nil {
+		if !ed.exactlyOnce {
+			logger.Error(err.Error())
+			return false
+		}
+		// The block may have been received again after a reconnect. Its events are
+		// only dispatched to the registrations which haven't yet received them.
+		logger.Debugf("Received block #%d again. Events which were already dispatched will be suppressed.", blockNum)
+	}
+
 	if ed.updateLastBlockInfoOnly {
 		ed.updateLastBlockInfoOnly = false
-		return
+		ed.updateCheckpointBlockNum(blockNum)
+		return false
 	}
+	return true
+}
 
-	logger.Debug("Publishing filtered block event...")
-	ed.publishFilteredBlockEvents(fblock, sourceURL)
+// consumerTimer is used for timing out the sends of an event to the registrations. A single timer is
+// reused for all of the registrations (rather than creating a timer for each send).
+type consumerTimer struct {
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+func newConsumerTimer(timeout time.Duration) *consumerTimer {
+	return &consumerTimer{timeout: timeout}
+}
+
+// C starts the timer and returns its channel
+func (t *consumerTimer) C() <-chan time.Time {
+	if t.timer == nil {
+		t.timer = time.NewTimer(t.timeout)
+	} else {
+		t.timer.Reset(t.timeout)
+	}
+	return t.timer.C
+}
+
+// Stop stops the timer (which hasn't fired) so that it may be started again
+func (t *consumerTimer) Stop() {
+	if !t.timer.Stop() {
+		select {
+		case <-t.timer.C:
+		default:
+		}
+	}
+}
+
+// hasFilteredRegistrations returns true if there are registrations for events which are published from filtered blocks
+func (ed *Dispatcher) hasFilteredRegistrations() bool {
+	return len(ed.filteredBlockRegistrations) > 0 || len(ed.ccRegistrations) > 0 || len(ed.txRegistrations) > 0
+}
+
+// consumerTimeout returns the timeout for sending an event to a registered consumer
+func (ed *Dispatcher) consumerTimeout() time.Duration {
+	if ed.exactlyOnce {
+		// Block until the event is accepted so that it's never dropped
+		return 0
+	}
+	return ed.eventConsumerTimeout
 }
 
 func (ed *Dispatcher) unregisterBlockEvents(registration *BlockReg) error {
@@ -386,24 +460,40 @@ func (ed *Dispatcher) unregisterTXEvents(registration *TxStatusReg) error {
 }
 
 func (ed *Dispatcher) publishBlockEvents(block *cb.Block, sourceURL string) {
+	timeout := ed.consumerTimeout()
+	timer := newConsumerTimer(timeout)
+
+	// The event (and the block) is shared by all of the registrations
+	var event *fab.BlockEvent
 	for _, reg := range ed.blockRegistrations {
+		if reg.dispatched(block.Header.Number) {
+			logger.Debugf("Not sending block event for block #%d since it was already sent.", block.Header.Number)
+			continue
+		}
+		reg.update(block.Header.Number)
+
 		if !reg.Filter(block) {
 			logger.Debugf("Not sending block event for block #%d since it was filtered out.", block.Header.Number)
 			continue
 		}
 
-		if ed.eventConsumerTimeout < 0 {
+		if event == nil {
+			event = NewBlockEvent(block, sourceURL)
+		}
+
+		if timeout < 0 {
 			select {
-			case reg.Eventch <- NewBlockEvent(block, sourceURL):
+			case reg.Eventch <- event:
 			default:
 				logger.Warn("Unable to send to block event channel.")
 			}
-		} else if ed.eventConsumerTimeout == 0 {
-			reg.Eventch <- NewBlockEvent(block, sourceURL)
+		} else if timeout == 0 {
+			reg.Eventch <- event
 		} else {
 			select {
-			case reg.Eventch <- NewBlockEvent(block, sourceURL):
-			case <-time.After(ed.eventConsumerTimeout):
+			case reg.Eventch <- event:
+				timer.Stop()
+			case <-timer.C():
 				logger.Warn("Timed out sending block event.")
 			}
 		}
@@ -436,22 +526,47 @@ func (ed *Dispatcher) publishFilteredBlockEvents(fblock *pb.FilteredBlock, sourc
 			}
 		}
 	}
+
+	// The chaincode and transaction status registrations may receive multiple events for
+	// a block so their checkpoints are only updated once all of the events were published
+	for _, reg := range ed.ccRegistrations {
+		reg.update(fblock.Number)
+	}
+	for _, reg := range ed.txRegistrations {
+		reg.update(fblock.Number)
+	}
 }
 
 func checkFilteredBlockRegistrations(ed *Dispatcher, fblock *pb.FilteredBlock, sourceURL string) {
+	timeout := ed.consumerTimeout()
+	timer := newConsumerTimer(timeout)
+
+	// The event (and the filtered block) is shared by all of the registrations
+	var event *fab.FilteredBlockEvent
 	for _, reg := range ed.filteredBlockRegistrations {
-		if ed.eventConsumerTimeout < 0 {
+		if reg.dispatched(fblock.Number) {
+			logger.Debugf("Not sending filtered block event for block #%d since it was already sent.", fblock.Number)
+			continue
+		}
+		reg.update(fblock.Number)
+
+		if event == nil {
+			event = NewFilteredBlockEvent(fblock, sourceURL)
+		}
+
+		if timeout < 0 {
 			select {
-			case reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL):
+			case reg.Eventch <- event:
 			default:
 				logger.Warn("Unable to send to filtered block event channel.")
 			}
-		} else if ed.eventConsumerTimeout == 0 {
-			reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL)
+		} else if timeout == 0 {
+			reg.Eventch <- event
 		} else {
 			select {
-			case reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL):
-			case <-time.After(ed.eventConsumerTimeout):
+			case reg.Eventch <- event:
+				timer.Stop()
+			case <-timer.C():
 				logger.Warn("Timed out sending filtered block event.")
 			}
 		}
@@ -461,20 +576,26 @@ func checkFilteredBlockRegistrations(ed *Dispatcher, fblock *pb.FilteredBlock, s
 func (ed *Dispatcher) publishTxStatusEvents(tx *pb.FilteredTransaction, blockNum uint64, sourceURL string) {
 	logger.Debugf("Publishing Tx Status event for TxID [%s]...", tx.Txid)
 	if reg, ok := ed.txRegistrations[tx.Txid]; ok {
+		if reg.dispatched(blockNum) {
+			logger.Debugf("Not sending Tx Status event for TxID [%s] since it was already sent.", tx.Txid)
+			return
+		}
+
 		logger.Debugf("Sending Tx Status event for TxID [%s] to registrant...", tx.Txid)
 
-		if ed.eventConsumerTimeout < 0 {
+		timeout := ed.consumerTimeout()
+		if timeout < 0 {
 			select {
 			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL):
 			default:
 				logger.Warn("Unable to send to Tx Status event channel.")
 			}
-		} else if ed.eventConsumerTimeout == 0 {
+		} else if timeout == 0 {
 			reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL)
 		} else {
 			select {
 			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL):
-			case <-time.After(ed.eventConsumerTimeout):
+			case <-time.After(timeout):
 				logger.Warn("Timed out sending Tx Status event.")
 			}
 		}
@@ -482,23 +603,37 @@ func (ed *Dispatcher) publishTxStatusEvents(tx *pb.FilteredTransaction, blockNum
 }
 
 func (ed *Dispatcher) publishCCEvents(ccEvent *pb.ChaincodeEvent, blockNum uint64, sourceURL string) {
+	timeout := ed.consumerTimeout()
+	timer := newConsumerTimer(timeout)
+
+	// The event (and its payload) is shared by all of the matching registrations
+	var event *fab.CCEvent
 	for _, reg := range ed.ccRegistrations {
+		if reg.dispatched(blockNum) {
+			continue
+		}
+
 		logger.Debugf("Matching CCEvent[%s,%s] against Reg[%s,%s] ...", ccEvent.ChaincodeId, ccEvent.EventName, reg.ChaincodeID, reg.EventFilter)
 		if reg.ChaincodeID == ccEvent.ChaincodeId && reg.EventRegExp.MatchString(ccEvent.EventName) {
 			logger.Debugf("... matched CCEvent[%s,%s] against Reg[%s,%s]", ccEvent.ChaincodeId, ccEvent.EventName, reg.ChaincodeID, reg.EventFilter)
 
-			if ed.eventConsumerTimeout < 0 {
+			if event == nil {
+				event = NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL)
+			}
+
+			if timeout < 0 {
 				select {
-				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL):
+				case reg.Eventch <- event:
 				default:
 					logger.Warn("Unable to send to CC event channel.")
 				}
-			} else if ed.eventConsumerTimeout == 0 {
-				reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL)
+			} else if timeout == 0 {
+				reg.Eventch <- event
 			} else {
 				select {
-				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL):
-				case <-time.After(ed.eventConsumerTimeout):
+				case reg.Eventch <- event:
+					timer.Stop()
+				case <-timer.C():
 					logger.Warn("Timed out sending CC event.")
 				}
 			}
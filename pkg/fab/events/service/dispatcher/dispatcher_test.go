@@ -8,6 +8,7 @@ package dispatcher
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 	"time"
 
@@ -820,3 +821,228 @@ func checkCCEvent(t *testing.T, event *fab.CCEvent, expectedCCID string, expecte
 		t.Fatalf("expecting one of [%v] but received [%s]", expectedEventNames, event.EventName)
 	}
 }
+
+func TestExactlyOnceDispatch(t *testing.T) {
+	channelID := "testchannel"
+	ccID := "mycc"
+
+	dispatcher := New(
+		WithExactlyOnceDispatch(true),
+		WithEventConsumerTimeout(-1),
+	)
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	if err != nil {
+		t.Fatalf("Error getting event channel from dispatcher: %s", err)
+	}
+
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+
+	beventch1 := make(chan *fab.BlockEvent, 10)
+	dispatcherEventch <- NewRegisterBlockEvent(blockfilter.AcceptAny, beventch1, regch, errch)
+	waitForRegistration(t, regch, errch)
+
+	cceventch := make(chan *fab.CCEvent, 10)
+	dispatcherEventch <- NewRegisterChaincodeEvent(ccID, ".*", cceventch, regch, errch)
+	waitForRegistration(t, regch, errch)
+
+	newBlock := func(blockNum uint64) *cb.Block {
+		block := servicemocks.NewBlock(channelID,
+			servicemocks.NewTransactionWithCCEvent("txid1", pb.TxValidationCode_VALID, ccID, "event1", nil),
+			servicemocks.NewTransactionWithCCEvent("txid2", pb.TxValidationCode_VALID, ccID, "event2", nil),
+		)
+		block.Header.Number = blockNum
+		return block
+	}
+
+	dispatcherEventch <- NewBlockEvent(newBlock(0), sourceURL)
+	dispatcherEventch <- NewBlockEvent(newBlock(1), sourceURL)
+
+	// Block 1 is received again (e.g. after a reconnect)
+	dispatcherEventch <- NewBlockEvent(newBlock(1), sourceURL)
+
+	// The new registration should receive block 1 since it wasn't previously dispatched to it
+	beventch2 := make(chan *fab.BlockEvent, 10)
+	dispatcherEventch <- NewRegisterBlockEvent(blockfilter.AcceptAny, beventch2, regch, errch)
+	waitForRegistration(t, regch, errch)
+
+	dispatcherEventch <- NewBlockEvent(newBlock(1), sourceURL)
+	dispatcherEventch <- NewBlockEvent(newBlock(2), sourceURL)
+
+	checkBlockNums(t, beventch1, 0, 1, 2)
+	checkBlockNums(t, beventch2, 1, 2)
+
+	var ccBlockNums []uint64
+	for i := 0; i < 6; i++ {
+		select {
+		case event := <-cceventch:
+			ccBlockNums = append(ccBlockNums, event.BlockNumber)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for CC event")
+		}
+	}
+	if !reflect.DeepEqual(ccBlockNums, []uint64{0, 0, 1, 1, 2, 2}) {
+		t.Fatalf("expecting CC events for blocks [0 0 1 1 2 2] but got %v", ccBlockNums)
+	}
+
+	select {
+	case event := <-cceventch:
+		t.Fatalf("unexpected CC event for block %d", event.BlockNumber)
+	case event := <-beventch1:
+		t.Fatalf("unexpected block event for block %d", event.Block.Header.Number)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if dispatcher.LastBlockNum() != 2 {
+		t.Fatalf("expecting last block number 2 but got %d", dispatcher.LastBlockNum())
+	}
+	if dispatcher.CheckpointBlockNum() != 2 {
+		t.Fatalf("expecting checkpoint block number 2 but got %d", dispatcher.CheckpointBlockNum())
+	}
+
+	stopResp := make(chan error)
+	dispatcherEventch <- NewStopEvent(stopResp)
+	if err := <-stopResp; err != nil {
+		t.Fatalf("Error stopping dispatcher: %s", err)
+	}
+}
+
+func TestDuplicateBlockRejected(t *testing.T) {
+	dispatcher := New()
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	if err != nil {
+		t.Fatalf("Error getting event channel from dispatcher: %s", err)
+	}
+
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+
+	beventch1 := make(chan *fab.BlockEvent, 10)
+	dispatcherEventch <- NewRegisterBlockEvent(blockfilter.AcceptAny, beventch1, regch, errch)
+	waitForRegistration(t, regch, errch)
+
+	block := servicemocks.NewBlock("testchannel")
+	dispatcherEventch <- NewBlockEvent(block, sourceURL)
+
+	beventch2 := make(chan *fab.BlockEvent, 10)
+	dispatcherEventch <- NewRegisterBlockEvent(blockfilter.AcceptAny, beventch2, regch, errch)
+	waitForRegistration(t, regch, errch)
+
+	// Without exactly-once dispatch, a block that was already received is not dispatched again
+	dispatcherEventch <- NewBlockEvent(block, sourceURL)
+
+	checkBlockNums(t, beventch1, 0)
+	select {
+	case event := <-beventch2:
+		t.Fatalf("unexpected block event for block %d", event.Block.Header.Number)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func waitForRegistration(t *testing.T, regch chan fab.Registration, errch chan error) fab.Registration {
+	select {
+	case reg := <-regch:
+		return reg
+	case err := <-errch:
+		t.Fatalf("Error registering for events: %s", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for registration")
+	}
+	return nil
+}
+
+func checkBlockNums(t *testing.T, eventch chan *fab.BlockEvent, expected ...uint64) {
+	for _, blockNum := range expected {
+		select {
+		case event := <-eventch:
+			if event.Block.Header.Number != blockNum {
+				t.Fatalf("expecting block %d but got block %d", blockNum, event.Block.Header.Number)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for block %d", blockNum)
+		}
+	}
+}
+
+func TestSharedBlockEvents(t *testing.T) {
+	channelID := "testchannel"
+	dispatcher := New(WithEventConsumerTimeout(0))
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	if err != nil {
+		t.Fatalf("Error getting event channel from dispatcher: %s", err)
+	}
+
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+
+	beventch1 := make(chan *fab.BlockEvent, 10)
+	dispatcherEventch <- NewRegisterBlockEvent(blockfilter.AcceptAny, beventch1, regch, errch)
+	waitForRegistration(t, regch, errch)
+
+	beventch2 := make(chan *fab.BlockEvent, 10)
+	dispatcherEventch <- NewRegisterBlockEvent(blockfilter.AcceptAny, beventch2, regch, errch)
+	waitForRegistration(t, regch, errch)
+
+	block := servicemocks.NewBlockProducer().NewBlock(channelID)
+	dispatcherEventch <- NewBlockEvent(block, sourceURL)
+
+	var events []*fab.BlockEvent
+	for _, eventch := range []chan *fab.BlockEvent{beventch1, beventch2} {
+		select {
+		case event := <-eventch:
+			events = append(events, event)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for block event")
+		}
+	}
+
+	if events[0] != events[1] || events[0].Block != block {
+		t.Fatal("expecting the block event to be shared by the registrations")
+	}
+
+	eventCopy := events[0].Copy()
+	if eventCopy.Block == block || !reflect.DeepEqual(eventCopy.Block, block) || eventCopy.SourceURL != sourceURL {
+		t.Fatal("expecting a deep copy of the block event")
+	}
+}
+
+func BenchmarkHandleBlock(b *testing.B) {
+	channelID := "testchannel"
+	dispatcher := New()
+
+	const numRegistrations = 100
+	eventchs := make([]chan *fab.BlockEvent, numRegistrations)
+	for i := range eventchs {
+		eventchs[i] = make(chan *fab.BlockEvent, 1)
+		dispatcher.blockRegistrations = append(dispatcher.blockRegistrations, &BlockReg{Filter: blockfilter.AcceptAny, Eventch: eventchs[i]})
+	}
+
+	producer := servicemocks.NewBlockProducer()
+	blocks := make([]*cb.Block, b.N)
+	for i := range blocks {
+		blocks[i] = producer.NewBlock(channelID,
+			servicemocks.NewTransactionWithCCEvent("txid", pb.TxValidationCode_VALID, "ccid", "event", []byte("payload")),
+		)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for _, block := range blocks {
+		dispatcher.HandleBlock(block, sourceURL)
+		for _, eventch := range eventchs {
+			<-eventch
+		}
+	}
+}
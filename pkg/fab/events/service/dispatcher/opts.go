@@ -15,6 +15,7 @@ import (
 type params struct {
 	eventConsumerBufferSize uint
 	eventConsumerTimeout    time.Duration
+	exactlyOnce             bool
 }
 
 func defaultParams() *params {
@@ -45,6 +46,18 @@ func WithEventConsumerTimeout(value time.Duration) options.Opt {
 	}
 }
 
+// WithExactlyOnceDispatch specifies whether or not each event is to be dispatched exactly once to each registration.
+// If true then the dispatcher blocks until the event is accepted by the consumer (the event consumer timeout
+// is ignored) and blocks which are received again (e.g. after a reconnect) are only dispatched to the registrations
+// which haven't yet received them.
+func WithExactlyOnceDispatch(value bool) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(exactlyOnceDispatchSetter); ok {
+			setter.SetExactlyOnceDispatch(value)
+		}
+	}
+}
+
 type eventConsumerBufferSizeSetter interface {
 	SetEventConsumerBufferSize(value uint)
 }
@@ -53,6 +66,10 @@ type eventEventConsumerTimeoutSetter interface {
 	SetEventConsumerTimeout(value time.Duration)
 }
 
+type exactlyOnceDispatchSetter interface {
+	SetExactlyOnceDispatch(value bool)
+}
+
 func (p *params) SetEventConsumerBufferSize(value uint) {
 	logger.Debugf("EventConsumerBufferSize: %d", value)
 	p.eventConsumerBufferSize = value
@@ -62,3 +79,8 @@ func (p *params) SetEventConsumerTimeout(value time.Duration) {
 	logger.Debugf("EventConsumerTimeout: %s", value)
 	p.eventConsumerTimeout = value
 }
+
+func (p *params) SetExactlyOnceDispatch(value bool) {
+	logger.Debugf("ExactlyOnceDispatch: %t", value)
+	p.exactlyOnce = value
+}
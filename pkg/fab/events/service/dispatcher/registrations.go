@@ -12,19 +12,41 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 )
 
+// checkpoint records the last block whose events were dispatched to a registration. It's used to
+// suppress the events of blocks which are received again, e.g. after the event client reconnects.
+type checkpoint struct {
+	blockNum uint64
+	set      bool
+}
+
+// dispatched returns true if the events of the given block were already dispatched to the registration
+func (c *checkpoint) dispatched(blockNum uint64) bool {
+	return c.set && blockNum <= c.blockNum
+}
+
+func (c *checkpoint) update(blockNum uint64) {
+	if !c.dispatched(blockNum) {
+		c.blockNum = blockNum
+		c.set = true
+	}
+}
+
 // BlockReg contains the data for a block registration
 type BlockReg struct {
+	checkpoint
 	Filter  fab.BlockFilter
 	Eventch chan<- *fab.BlockEvent
 }
 
 // FilteredBlockReg contains the data for a filtered block registration
 type FilteredBlockReg struct {
+	checkpoint
 	Eventch chan<- *fab.FilteredBlockEvent
 }
 
 // ChaincodeReg contains the data for a chaincode registration
 type ChaincodeReg struct {
+	checkpoint
 	ChaincodeID string
 	EventFilter string
 	EventRegExp *regexp.Regexp
@@ -33,6 +55,7 @@ type ChaincodeReg struct {
 
 // TxStatusReg contains the data for a transaction status registration
 type TxStatusReg struct {
+	checkpoint
 	TxID    string
 	Eventch chan<- *fab.TxStatusEvent
 }
@@ -44,6 +44,9 @@ type Dispatcher interface {
 
 	// LastBlockNum returns the block number of the last block for which an event was received.
 	LastBlockNum() uint64
+
+	// CheckpointBlockNum returns the block number of the last block whose events were dispatched to all registrations.
+	CheckpointBlockNum() uint64
 }
 
 // Service allows clients to register for channel events, such as filtered block, chaincode, and transaction status events.
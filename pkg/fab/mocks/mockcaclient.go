@@ -23,7 +23,7 @@ func NewMockCAClient(orgName string, cryptoProvider core.CryptoSuite) (api.CACli
 }
 
 // Enroll enrolls a user with a Fabric network
-func (mgr *MockCAClient) Enroll(enrollmentID string, enrollmentSecret string) error {
+func (mgr *MockCAClient) Enroll(request *api.EnrollmentRequest) error {
 	return errors.New("not implemented")
 }
 
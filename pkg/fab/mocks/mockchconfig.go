@@ -24,6 +24,7 @@ type MockChannelCfg struct {
 	MockVersions     *fab.Versions
 	MockMembership   fab.ChannelMembership
 	MockCapabilities map[fab.ConfigGroupKey]map[string]bool
+	MockHashingAlg   string
 }
 
 // NewMockChannelCfg ...
@@ -69,6 +70,11 @@ func (cfg *MockChannelCfg) Versions() *fab.Versions {
 	return cfg.MockVersions
 }
 
+// HashingAlgorithm returns the hashing algorithm
+func (cfg *MockChannelCfg) HashingAlgorithm() string {
+	return cfg.MockHashingAlg
+}
+
 // HasCapability indicates whether or not the given group has the given capability
 func (cfg *MockChannelCfg) HasCapability(group fab.ConfigGroupKey, capability string) bool {
 	capabilities, ok := cfg.MockCapabilities[group]
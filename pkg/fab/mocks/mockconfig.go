@@ -32,6 +32,8 @@ type MockConfig struct {
 	customRandomOrdererCfg *fab.OrdererConfig
 	EvtServiceConfig       fab.EventServiceConfig
 	CustomTLSCACertPool    fab.CertPool
+	ChannelTimeouts        fab.TimeoutPolicy
+	ChannelObserver        fab.ObserverPolicy
 }
 
 // NewMockCryptoConfig ...
@@ -246,9 +248,13 @@ func (c *MockConfig) ChannelConfig(name string) (*fab.ChannelEndpointConfig, boo
 	if ok && len(chPeers) > 0 {
 		queryDiscovery = len(chPeers)
 	}
-	return &fab.ChannelEndpointConfig{Policies: fab.ChannelPolicies{QueryChannelConfig: fab.QueryChannelConfigPolicy{
-		QueryDiscovery: queryDiscovery,
-	}}}, true
+	return &fab.ChannelEndpointConfig{Policies: fab.ChannelPolicies{
+		QueryChannelConfig: fab.QueryChannelConfigPolicy{
+			QueryDiscovery: queryDiscovery,
+		},
+		Timeouts: c.ChannelTimeouts,
+		Observer: c.ChannelObserver,
+	}}, true
 }
 
 // ChannelPeers returns the channel peers configuration
@@ -338,6 +344,10 @@ type MockEventServiceConfig struct {
 	LagThreshold          int
 	ReconnectLagThreshold int
 	HeightMonitorPeriod   time.Duration
+	SharedConnections     bool
+	KeepAlive             time.Duration
+	KeepAliveTO           time.Duration
+	IdleTimeout           time.Duration
 }
 
 // BlockHeightLagThreshold returns the block height lag threshold.
@@ -355,3 +365,23 @@ func (c *MockEventServiceConfig) ReconnectBlockHeightLagThreshold() int {
 func (c *MockEventServiceConfig) BlockHeightMonitorPeriod() time.Duration {
 	return c.HeightMonitorPeriod
 }
+
+// ShareConnections returns true if event service connections are shared by clients of the same organization
+func (c *MockEventServiceConfig) ShareConnections() bool {
+	return c.SharedConnections
+}
+
+// KeepAliveTime returns the keep-alive time of the event connections
+func (c *MockEventServiceConfig) KeepAliveTime() time.Duration {
+	return c.KeepAlive
+}
+
+// KeepAliveTimeout returns the keep-alive timeout of the event connections
+func (c *MockEventServiceConfig) KeepAliveTimeout() time.Duration {
+	return c.KeepAliveTO
+}
+
+// StreamIdleTimeout returns the time after which an idle deliver stream is reconnected
+func (c *MockEventServiceConfig) StreamIdleTimeout() time.Duration {
+	return c.IdleTimeout
+}
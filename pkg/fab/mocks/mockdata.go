@@ -344,7 +344,7 @@ func (b *MockConfigGroupBuilder) buildChannelRestrictions() *ab.ChannelRestricti
 
 func (b *MockConfigGroupBuilder) buildHashingAlgorithm() *common.HashingAlgorithm {
 	return &common.HashingAlgorithm{
-		Name: "SHA2",
+		Name: "SHA256",
 	}
 }
 
@@ -33,6 +33,11 @@ func (m *MockMembership) Verify(serializedID []byte, msg []byte, sig []byte) err
 	return m.VerifyErr
 }
 
+// ValidateEndorser mocks membership.ValidateEndorser
+func (m *MockMembership) ValidateEndorser(serializedID []byte, mspID string) error {
+	return m.ValidateErr
+}
+
 // ContainsMSP mocks membership.ContainsMSP
 func (m *MockMembership) ContainsMSP(msp string) bool {
 	for _, v := range m.excludeMSPs {
@@ -0,0 +1,286 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package operations provides a client for the operations endpoint of peers and orderers (health,
+// version, metrics and log level). The TLS material of the SDK's endpoint config is used so that the
+// operations endpoint may be accessed without a separate HTTP client and config source.
+package operations
+
+import (
+	"bytes"
+	reqContext "context"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/fab")
+
+const (
+	healthPath  = "/healthz"
+	versionPath = "/version"
+	metricsPath = "/metrics"
+	logSpecPath = "/logspec"
+
+	defaultTimeout = 10 * time.Second
+)
+
+// FailedCheck contains the component which failed a health check and the reason
+type FailedCheck struct {
+	Component string `json:"component"`
+	Reason    string `json:"reason"`
+}
+
+// HealthStatus is the health status of a node
+type HealthStatus struct {
+	// Status is either OK or Service Unavailable
+	Status       string        `json:"status"`
+	Time         time.Time     `json:"time"`
+	FailedChecks []FailedCheck `json:"failed_checks,omitempty"`
+}
+
+// Healthy returns true if all of the health checks of the node succeeded
+func (s *HealthStatus) Healthy() bool {
+	return len(s.FailedChecks) == 0 && s.Status == "OK"
+}
+
+// VersionInfo contains the version of a node
+type VersionInfo struct {
+	Version   string `json:"Version"`
+	CommitSHA string `json:"CommitSHA"`
+}
+
+type logSpec struct {
+	Spec string `json:"spec"`
+}
+
+type errorResponse struct {
+	Error string `json:"Error"`
+}
+
+// Client invokes the operations endpoint of a peer or orderer
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+type options struct {
+	tlsCACert  *x509.Certificate
+	serverName string
+	timeout    time.Duration
+}
+
+// Option is a functional option for the operations client
+type Option func(opts *options)
+
+// WithTLSCACert adds a TLS CA certificate (in addition to the TLS CA certificates of the endpoint config)
+// which is used to verify the certificate of the operations endpoint
+func WithTLSCACert(cert *x509.Certificate) Option {
+	return func(opts *options) {
+		opts.tlsCACert = cert
+	}
+}
+
+// WithServerName overrides the server name which is used to verify the certificate of the operations endpoint
+func WithServerName(serverName string) Option {
+	return func(opts *options) {
+		opts.serverName = serverName
+	}
+}
+
+// WithTimeout sets the timeout of the HTTP requests (the default is 10s)
+func WithTimeout(timeout time.Duration) Option {
+	return func(opts *options) {
+		opts.timeout = timeout
+	}
+}
+
+// New returns a client for the operations endpoint with the given URL (e.g. https://peer0.org1.example.com:9443).
+// The TLS CA certificates and the client certificates (for mutual TLS) of the endpoint config are used for https URLs.
+func New(config fab.EndpointConfig, url string, opts ...Option) (*Client, error) {
+	if url == "" {
+		return nil, errors.New("operations URL is required")
+	}
+
+	o := &options{timeout: defaultTimeout}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	transport := &http.Transport{}
+	if strings.HasPrefix(strings.ToLower(url), "https://") {
+		tlsConfig, err := comm.TLSConfig(o.tlsCACert, o.serverName, config)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get TLS config for operations client")
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &Client{
+		url:        strings.TrimSuffix(url, "/"),
+		httpClient: &http.Client{Transport: transport, Timeout: o.timeout},
+	}, nil
+}
+
+// NewForPeer returns a client for the operations endpoint of the given peer. The TLS CA certificate and
+// the TLS server name override of the peer are also used for the operations endpoint.
+func NewForPeer(config fab.EndpointConfig, peerCfg *fab.PeerConfig, opts ...Option) (*Client, error) {
+	if peerCfg.OperationsURL == "" {
+		return nil, errors.Errorf("operations URL is not configured for peer [%s]", peerCfg.URL)
+	}
+	return New(config, peerCfg.OperationsURL, append(endpointOpts(peerCfg.TLSCACert, peerCfg.GRPCOptions), opts...)...)
+}
+
+// NewForOrderer returns a client for the operations endpoint of the given orderer. The TLS CA certificate and
+// the TLS server name override of the orderer are also used for the operations endpoint.
+func NewForOrderer(config fab.EndpointConfig, ordererCfg *fab.OrdererConfig, opts ...Option) (*Client, error) {
+	if ordererCfg.OperationsURL == "" {
+		return nil, errors.Errorf("operations URL is not configured for orderer [%s]", ordererCfg.URL)
+	}
+	return New(config, ordererCfg.OperationsURL, append(endpointOpts(ordererCfg.TLSCACert, ordererCfg.GRPCOptions), opts...)...)
+}
+
+func endpointOpts(tlsCACert *x509.Certificate, grpcOptions map[string]interface{}) []Option {
+	opts := []Option{WithTLSCACert(tlsCACert)}
+	if serverName, ok := grpcOptions["ssl-target-name-override"].(string); ok {
+		opts = append(opts, WithServerName(serverName))
+	}
+	return opts
+}
+
+// URL returns the URL of the operations endpoint
+func (c *Client) URL() string {
+	return c.url
+}
+
+// Health returns the health status of the node. An unhealthy node is not treated as an error;
+// the failed checks are returned in the status.
+func (c *Client) Health(reqCtx reqContext.Context) (*HealthStatus, error) {
+	status := &HealthStatus{}
+	if err := c.do(reqCtx, http.MethodGet, healthPath, nil, status, http.StatusOK, http.StatusServiceUnavailable); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Version returns the version of the node
+func (c *Client) Version(reqCtx reqContext.Context) (*VersionInfo, error) {
+	version := &VersionInfo{}
+	if err := c.do(reqCtx, http.MethodGet, versionPath, nil, version, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// Metrics returns the metrics of the node in the Prometheus text format. The metrics provider of the
+// node must be configured as 'prometheus'.
+func (c *Client) Metrics(reqCtx reqContext.Context) (string, error) {
+	resp, err := c.send(reqCtx, http.MethodGet, metricsPath, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read metrics")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to get metrics from [%s]: %s", c.url, resp.Status)
+	}
+	return string(body), nil
+}
+
+// LogSpec returns the logging specification of the node (e.g. info:gossip=debug)
+func (c *Client) LogSpec(reqCtx reqContext.Context) (string, error) {
+	spec := &logSpec{}
+	if err := c.do(reqCtx, http.MethodGet, logSpecPath, nil, spec, http.StatusOK); err != nil {
+		return "", err
+	}
+	return spec.Spec, nil
+}
+
+// SetLogSpec sets the logging specification of the node
+func (c *Client) SetLogSpec(reqCtx reqContext.Context, spec string) error {
+	if spec == "" {
+		return errors.New("log spec is required")
+	}
+	return c.do(reqCtx, http.MethodPut, logSpecPath, &logSpec{Spec: spec}, nil, http.StatusNoContent, http.StatusOK)
+}
+
+func (c *Client) do(reqCtx reqContext.Context, method, path string, reqBody interface{}, respBody interface{}, expectedStatus ...int) error {
+	var body io.Reader
+	if reqBody != nil {
+		reqBytes, err := json.Marshal(reqBody)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal request")
+		}
+		body = bytes.NewReader(reqBytes)
+	}
+
+	resp, err := c.send(reqCtx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response")
+	}
+
+	if !containsStatus(expectedStatus, resp.StatusCode) {
+		errResp := &errorResponse{}
+		if json.Unmarshal(respBytes, errResp) == nil && errResp.Error != "" {
+			return errors.Errorf("%s %s%s failed: %s: %s", method, c.url, path, resp.Status, errResp.Error)
+		}
+		return errors.Errorf("%s %s%s failed: %s", method, c.url, path, resp.Status)
+	}
+
+	if respBody == nil || len(respBytes) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBytes, respBody); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal response from %s%s", c.url, path)
+	}
+	return nil
+}
+
+func (c *Client) send(reqCtx reqContext.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, c.url+path, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	logger.Debugf("Sending %s request to %s", method, req.URL)
+
+	resp, err := c.httpClient.Do(req.WithContext(reqCtx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s failed", method, req.URL)
+	}
+	return resp, nil
+}
+
+func containsStatus(statuses []int, status int) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
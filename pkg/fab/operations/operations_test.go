@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	reqContext "context"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockfab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockOperationsHandler emulates the operations endpoint of a peer
+type mockOperationsHandler struct {
+	logSpec string
+	healthy bool
+}
+
+func (h *mockOperationsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case healthPath:
+		if h.healthy {
+			writeJSON(w, http.StatusOK, &HealthStatus{Status: "OK"})
+			return
+		}
+		writeJSON(w, http.StatusServiceUnavailable, &HealthStatus{
+			Status:       "Service Unavailable",
+			FailedChecks: []FailedCheck{{Component: "couchdb", Reason: "failed to connect"}},
+		})
+	case versionPath:
+		writeJSON(w, http.StatusOK, &VersionInfo{Version: "1.4.0", CommitSHA: "abcd"})
+	case metricsPath:
+		io.WriteString(w, "ledger_blockchain_height{channel=\"mychannel\"} 10\n") // nolint: errcheck
+	case logSpecPath:
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, &logSpec{Spec: h.logSpec})
+		case http.MethodPut:
+			spec := &logSpec{}
+			if err := json.NewDecoder(r.Body).Decode(spec); err != nil || spec.Spec == "invalid" {
+				writeJSON(w, http.StatusBadRequest, &errorResponse{Error: "invalid log spec"})
+				return
+			}
+			h.logSpec = spec.Spec
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) // nolint: errcheck
+}
+
+func TestOperationsClient(t *testing.T) {
+	handler := &mockOperationsHandler{logSpec: "info", healthy: true}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client, err := New(mocks.NewMockEndpointConfig(), server.URL)
+	require.NoError(t, err)
+
+	ctx := reqContext.Background()
+
+	health, err := client.Health(ctx)
+	require.NoError(t, err)
+	assert.True(t, health.Healthy())
+
+	handler.healthy = false
+	health, err = client.Health(ctx)
+	require.NoError(t, err)
+	assert.False(t, health.Healthy())
+	assert.Equal(t, []FailedCheck{{Component: "couchdb", Reason: "failed to connect"}}, health.FailedChecks)
+
+	version, err := client.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", version.Version)
+
+	metrics, err := client.Metrics(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, metrics, "ledger_blockchain_height")
+
+	require.NoError(t, client.SetLogSpec(ctx, "info:gossip=debug"))
+	spec, err := client.LogSpec(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "info:gossip=debug", spec)
+
+	err = client.SetLogSpec(ctx, "invalid")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid log spec")
+}
+
+func TestOperationsClientTLS(t *testing.T) {
+	server := httptest.NewTLSServer(&mockOperationsHandler{healthy: true})
+	defer server.Close()
+
+	config := mocks.NewMockEndpointConfig()
+
+	// The certificate of the server isn't trusted
+	client, err := New(config, server.URL)
+	require.NoError(t, err)
+	_, err = client.Health(reqContext.Background())
+	assert.Error(t, err)
+
+	certPool := x509.NewCertPool()
+	certPool.AddCert(server.Certificate())
+	config.(*mocks.MockConfig).CustomTLSCACertPool = &mockfab.MockCertPool{CertPool: certPool}
+
+	client, err = NewForPeer(config, &fab.PeerConfig{
+		URL:           "peer0.org1.example.com:7051",
+		OperationsURL: server.URL,
+		GRPCOptions:   map[string]interface{}{"ssl-target-name-override": "example.com"},
+	})
+	require.NoError(t, err)
+	health, err := client.Health(reqContext.Background())
+	require.NoError(t, err)
+	assert.True(t, health.Healthy())
+
+	_, err = NewForOrderer(config, &fab.OrdererConfig{URL: "orderer.example.com:7050"})
+	assert.Error(t, err, "expecting error since the operations URL isn't configured")
+}
@@ -314,6 +314,22 @@ func (m *mockEventServiceConfigImpl) BlockHeightMonitorPeriod() time.Duration {
 	return time.Second
 }
 
+func (m *mockEventServiceConfigImpl) ShareConnections() bool {
+	return false
+}
+
+func (m *mockEventServiceConfigImpl) KeepAliveTime() time.Duration {
+	return 0
+}
+
+func (m *mockEventServiceConfigImpl) KeepAliveTimeout() time.Duration {
+	return 0
+}
+
+func (m *mockEventServiceConfigImpl) StreamIdleTimeout() time.Duration {
+	return 0
+}
+
 type mockTLSClientCerts struct{}
 
 func (m *mockTLSClientCerts) TLSClientCerts() []tls.Certificate {
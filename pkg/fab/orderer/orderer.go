@@ -43,6 +43,7 @@ const (
 type Orderer struct {
 	config         fab.EndpointConfig
 	url            string
+	target         string
 	serverName     string
 	tlsCACert      *x509.Certificate
 	grpcDialOption []grpc.DialOption
@@ -50,6 +51,10 @@ type Orderer struct {
 	dialTimeout    time.Duration
 	failFast       bool
 	allowInsecure  bool
+	lbPolicy       string
+	proxyURL       string
+	dialer         comm.Dialer
+	tlsOptions     *comm.TLSOptions
 	commManager    fab.CommManager
 }
 
@@ -84,6 +89,7 @@ func New(config fab.EndpointConfig, opts ...Option) (*Orderer, error) {
 		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
 			return verifier.VerifyPeerCertificate(rawCerts, verifiedChains)
 		}
+		orderer.tlsOptions.Apply(tlsConfig)
 
 		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	} else {
@@ -93,8 +99,21 @@ func New(config fab.EndpointConfig, opts ...Option) (*Orderer, error) {
 	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize),
 		grpc.MaxCallSendMsgSize(maxCallSendMsgSize)))
 
+	target, lbOpts, err := comm.DialTarget(endpoint.ToAddress(orderer.url), orderer.lbPolicy)
+	if err != nil {
+		return nil, err
+	}
+	grpcOpts = append(grpcOpts, lbOpts...)
+
+	proxyOpts, err := comm.DialOptions(orderer.proxyURL, orderer.dialer)
+	if err != nil {
+		return nil, err
+	}
+	grpcOpts = append(grpcOpts, proxyOpts...)
+
 	orderer.dialTimeout = config.Timeout(fab.OrdererConnection)
 	orderer.url = endpoint.ToAddress(orderer.url)
+	orderer.target = target
 	orderer.grpcDialOption = grpcOpts
 
 	return orderer, nil
@@ -136,6 +155,51 @@ func WithInsecure() Option {
 	}
 }
 
+// WithLoadBalancingPolicy is a functional option for the orderer.New constructor that configures the gRPC load
+// balancing policy used across all of the addresses that the orderer's host name resolves to
+func WithLoadBalancingPolicy(policy string) Option {
+	return func(o *Orderer) error {
+		o.lbPolicy = policy
+
+		return nil
+	}
+}
+
+// WithProxyURL is a functional option for the orderer.New constructor that configures the URL of the SOCKS5
+// or HTTP CONNECT proxy through which connections to the orderer are established
+func WithProxyURL(proxyURL string) Option {
+	return func(o *Orderer) error {
+		o.proxyURL = proxyURL
+
+		return nil
+	}
+}
+
+// WithDialer is a functional option for the orderer.New constructor that configures a custom dialer
+// with which connections to the orderer are established
+func WithDialer(dialer comm.Dialer) Option {
+	return func(o *Orderer) error {
+		o.dialer = dialer
+
+		return nil
+	}
+}
+
+// WithTLSOptions is a functional option for the orderer.New constructor that configures the TLS versions
+// and cipher suites allowed for connections to the orderer as well as the additional verifications of its certificate
+func WithTLSOptions(tlsOptions *comm.TLSOptions) Option {
+	return func(o *Orderer) error {
+		if tlsOptions != nil {
+			if err := tlsOptions.Validate(); err != nil {
+				return err
+			}
+		}
+		o.tlsOptions = tlsOptions
+
+		return nil
+	}
+}
+
 // FromOrdererConfig is a functional option for the orderer.New constructor that configures a new orderer
 // from a apiconfig.OrdererConfig struct
 func FromOrdererConfig(ordererCfg *fab.OrdererConfig) Option {
@@ -156,6 +220,19 @@ func FromOrdererConfig(ordererCfg *fab.OrdererConfig) Option {
 		o.kap = getKeepAliveOptions(ordererCfg)
 		o.failFast = getFailFast(ordererCfg)
 		o.allowInsecure = isInsecureConnectionAllowed(ordererCfg)
+		o.lbPolicy = comm.LoadBalancingPolicy(ordererCfg.GRPCOptions)
+		o.proxyURL = comm.ProxyURL(ordererCfg.GRPCOptions)
+
+		tlsOptions, err := comm.TLSOptionsFromGRPC(ordererCfg.GRPCOptions)
+		if err != nil {
+			return errors.WithMessage(err, "invalid TLS options of orderer ["+ordererCfg.URL+"]")
+		}
+		o.tlsOptions = tlsOptions
+
+		o.dialer, err = comm.DialerFromGRPC(ordererCfg.GRPCOptions)
+		if err != nil {
+			return errors.WithMessage(err, "invalid dialer of orderer ["+ordererCfg.URL+"]")
+		}
 
 		return nil
 	}
@@ -224,7 +301,7 @@ func (o *Orderer) conn(ctx reqContext.Context) (*grpc.ClientConn, error) {
 		commManager = o.commManager
 	}
 
-	return commManager.DialContext(ctx, o.url, o.grpcDialOption...)
+	return commManager.DialContext(ctx, o.target, o.grpcDialOption...)
 }
 
 func (o *Orderer) releaseConn(ctx reqContext.Context, conn *grpc.ClientConn) {
@@ -251,7 +328,7 @@ func (o *Orderer) SendBroadcast(ctx reqContext.Context, envelope *fab.SignedEnve
 			return nil, errors.WithMessage(status.NewFromGRPCStatus(rpcStatus), "connection failed")
 		}
 
-		return nil, status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), err.Error(), nil)
+		return nil, status.WithEndpoint(status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), err.Error(), nil), o.url)
 	}
 	defer o.releaseConn(ctx, conn)
 
@@ -274,13 +351,18 @@ func (o *Orderer) SendBroadcast(ctx reqContext.Context, envelope *fab.SignedEnve
 		Signature: envelope.Signature,
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to send envelope to orderer")
+		rpcStatus, ok := grpcstatus.FromError(err)
+		if ok {
+			err = status.NewFromGRPCStatus(rpcStatus)
+		}
+		return nil, status.WithEndpoint(errors.Wrap(err, "failed to send envelope to orderer"), o.url)
 	}
 	if err = broadcastClient.CloseSend(); err != nil {
 		logger.Debugf("unable to close broadcast client [%s]", err)
 	}
 
-	return wrapStreamStatusRPC(responses, errs)
+	resp, err := wrapStreamStatusRPC(responses, errs)
+	return resp, status.WithEndpoint(err, o.url)
 }
 
 // wrapStreamStatusRPC returns the last response and err and blocks until the chan is closed.
@@ -0,0 +1,259 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package participation provides a client for the channel participation API of orderers (Fabric 2.3+),
+// which is used to join ordering nodes to application channels on networks without a system channel.
+// The API is served on the admin endpoint of the orderer and requires mutual TLS; the TLS material of
+// the SDK's endpoint config is used.
+package participation
+
+import (
+	"bytes"
+	reqContext "context"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/fab")
+
+const (
+	channelsPath   = "/participation/v1/channels"
+	configBlockKey = "config-block"
+
+	defaultTimeout = 10 * time.Second
+)
+
+// ErrChannelNotFound is returned if the orderer is not a member of the channel
+var ErrChannelNotFound = errors.New("channel not found")
+
+// ChannelInfo contains the status of a channel on an orderer
+type ChannelInfo struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// ConsensusRelation is consenter, follower, config-tracker or other
+	ConsensusRelation string `json:"consensusRelation"`
+	// Status is onboarding, active or inactive
+	Status string `json:"status"`
+	Height uint64 `json:"height"`
+}
+
+// ChannelInfoShort contains the name and URL of a channel
+type ChannelInfoShort struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// ChannelList contains the channels of an orderer
+type ChannelList struct {
+	SystemChannel *ChannelInfoShort  `json:"systemChannel"`
+	Channels      []ChannelInfoShort `json:"channels"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Client invokes the channel participation API of an orderer
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+type options struct {
+	tlsCACert  *x509.Certificate
+	serverName string
+	timeout    time.Duration
+}
+
+// Option is a functional option for the participation client
+type Option func(opts *options)
+
+// WithTLSCACert adds a TLS CA certificate (in addition to the TLS CA certificates of the endpoint config)
+// which is used to verify the certificate of the admin endpoint
+func WithTLSCACert(cert *x509.Certificate) Option {
+	return func(opts *options) {
+		opts.tlsCACert = cert
+	}
+}
+
+// WithServerName overrides the server name which is used to verify the certificate of the admin endpoint
+func WithServerName(serverName string) Option {
+	return func(opts *options) {
+		opts.serverName = serverName
+	}
+}
+
+// WithTimeout sets the timeout of the HTTP requests (the default is 10s)
+func WithTimeout(timeout time.Duration) Option {
+	return func(opts *options) {
+		opts.timeout = timeout
+	}
+}
+
+// New returns a client for the admin endpoint with the given URL (e.g. https://orderer.example.com:7053).
+// The TLS CA certificates and the client certificates (for mutual TLS) of the endpoint config are used for https URLs.
+func New(config fab.EndpointConfig, url string, opts ...Option) (*Client, error) {
+	if url == "" {
+		return nil, errors.New("admin URL is required")
+	}
+
+	o := &options{timeout: defaultTimeout}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	transport := &http.Transport{}
+	if strings.HasPrefix(strings.ToLower(url), "https://") {
+		tlsConfig, err := comm.TLSConfig(o.tlsCACert, o.serverName, config)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get TLS config for participation client")
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &Client{
+		url:        strings.TrimSuffix(url, "/"),
+		httpClient: &http.Client{Transport: transport, Timeout: o.timeout},
+	}, nil
+}
+
+// NewForOrderer returns a client for the admin endpoint of the given orderer. The TLS CA certificate and
+// the TLS server name override of the orderer are also used for the admin endpoint.
+func NewForOrderer(config fab.EndpointConfig, ordererCfg *fab.OrdererConfig, opts ...Option) (*Client, error) {
+	if ordererCfg.AdminURL == "" {
+		return nil, errors.Errorf("admin URL is not configured for orderer [%s]", ordererCfg.URL)
+	}
+
+	endpointOpts := []Option{WithTLSCACert(ordererCfg.TLSCACert)}
+	if serverName, ok := ordererCfg.GRPCOptions["ssl-target-name-override"].(string); ok {
+		endpointOpts = append(endpointOpts, WithServerName(serverName))
+	}
+
+	return New(config, ordererCfg.AdminURL, append(endpointOpts, opts...)...)
+}
+
+// URL returns the URL of the admin endpoint
+func (c *Client) URL() string {
+	return c.url
+}
+
+// Join joins the orderer to the channel of the given config block (the genesis block of a new channel
+// or the latest config block of an existing channel)
+func (c *Client) Join(reqCtx reqContext.Context, configBlock *common.Block) (*ChannelInfo, error) {
+	if configBlock == nil {
+		return nil, errors.New("config block is required")
+	}
+
+	blockBytes, err := proto.Marshal(configBlock)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal config block failed")
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(configBlockKey, "config.block")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create form file")
+	}
+	if _, err := part.Write(blockBytes); err != nil {
+		return nil, errors.Wrap(err, "failed to write config block")
+	}
+	if err := writer.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close multipart writer")
+	}
+
+	info := &ChannelInfo{}
+	if err := c.do(reqCtx, http.MethodPost, channelsPath, body, writer.FormDataContentType(), info, http.StatusCreated); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// ListChannels returns the channels of the orderer
+func (c *Client) ListChannels(reqCtx reqContext.Context) (*ChannelList, error) {
+	list := &ChannelList{}
+	if err := c.do(reqCtx, http.MethodGet, channelsPath, nil, "", list, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ChannelInfo returns the status of the given channel on the orderer. ErrChannelNotFound is
+// returned if the orderer is not a member of the channel.
+func (c *Client) ChannelInfo(reqCtx reqContext.Context, channelID string) (*ChannelInfo, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+
+	info := &ChannelInfo{}
+	if err := c.do(reqCtx, http.MethodGet, channelsPath+"/"+channelID, nil, "", info, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Remove removes the orderer from the given channel
+func (c *Client) Remove(reqCtx reqContext.Context, channelID string) error {
+	if channelID == "" {
+		return errors.New("channel ID is required")
+	}
+	return c.do(reqCtx, http.MethodDelete, channelsPath+"/"+channelID, nil, "", nil, http.StatusNoContent)
+}
+
+func (c *Client) do(reqCtx reqContext.Context, method, path string, body io.Reader, contentType string, respBody interface{}, expectedStatus int) error {
+	req, err := http.NewRequest(method, c.url+path, body)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	logger.Debugf("Sending %s request to %s", method, req.URL)
+
+	resp, err := c.httpClient.Do(req.WithContext(reqCtx))
+	if err != nil {
+		return errors.Wrapf(err, "%s %s failed", method, req.URL)
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response")
+	}
+
+	if resp.StatusCode != expectedStatus {
+		if resp.StatusCode == http.StatusNotFound && method == http.MethodGet && path != channelsPath {
+			return ErrChannelNotFound
+		}
+		errResp := &errorResponse{}
+		if json.Unmarshal(respBytes, errResp) == nil && errResp.Error != "" {
+			return errors.Errorf("%s %s%s failed: %s: %s", method, c.url, path, resp.Status, errResp.Error)
+		}
+		return errors.Errorf("%s %s%s failed: %s", method, c.url, path, resp.Status)
+	}
+
+	if respBody == nil || len(respBytes) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBytes, respBody); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal response from %s%s", c.url, path)
+	}
+	return nil
+}
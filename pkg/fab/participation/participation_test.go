@@ -0,0 +1,149 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package participation
+
+import (
+	reqContext "context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockParticipationHandler emulates the channel participation API of an orderer
+type mockParticipationHandler struct {
+	channels map[string]*ChannelInfo
+}
+
+func (h *mockParticipationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == channelsPath && r.Method == http.MethodGet:
+		list := &ChannelList{}
+		for name, info := range h.channels {
+			list.Channels = append(list.Channels, ChannelInfoShort{Name: name, URL: info.URL})
+		}
+		writeJSON(w, http.StatusOK, list)
+	case r.URL.Path == channelsPath && r.Method == http.MethodPost:
+		h.join(w, r)
+	case strings.HasPrefix(r.URL.Path, channelsPath+"/"):
+		name := strings.TrimPrefix(r.URL.Path, channelsPath+"/")
+		info, ok := h.channels[name]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, &errorResponse{Error: "channel does not exist"})
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, info)
+		case http.MethodDelete:
+			delete(h.channels, name)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (h *mockParticipationHandler) join(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile(configBlockKey)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, &errorResponse{Error: "missing config block"})
+		return
+	}
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, &errorResponse{Error: "invalid config block"})
+		return
+	}
+	block := &common.Block{}
+	if err := proto.Unmarshal(data, block); err != nil || block.Header == nil {
+		writeJSON(w, http.StatusBadRequest, &errorResponse{Error: "invalid config block"})
+		return
+	}
+
+	name := string(block.Header.DataHash)
+	if _, ok := h.channels[name]; ok {
+		writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error: "cannot join: channel already exists"})
+		return
+	}
+	info := &ChannelInfo{Name: name, URL: channelsPath + "/" + name, ConsensusRelation: "consenter", Status: "onboarding", Height: 1}
+	h.channels[name] = info
+	writeJSON(w, http.StatusCreated, info)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) // nolint: errcheck
+}
+
+func TestParticipationClient(t *testing.T) {
+	handler := &mockParticipationHandler{channels: make(map[string]*ChannelInfo)}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client, err := New(mocks.NewMockEndpointConfig(), server.URL)
+	require.NoError(t, err)
+
+	ctx := reqContext.Background()
+
+	// The mock uses the data hash as the channel name
+	block := &common.Block{Header: &common.BlockHeader{DataHash: []byte("mychannel")}}
+
+	_, err = client.ChannelInfo(ctx, "mychannel")
+	assert.Equal(t, ErrChannelNotFound, err)
+
+	info, err := client.Join(ctx, block)
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", info.Name)
+	assert.Equal(t, "consenter", info.ConsensusRelation)
+
+	_, err = client.Join(ctx, block)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "channel already exists")
+
+	info, err = client.ChannelInfo(ctx, "mychannel")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), info.Height)
+
+	list, err := client.ListChannels(ctx)
+	require.NoError(t, err)
+	require.Len(t, list.Channels, 1)
+	assert.Equal(t, "mychannel", list.Channels[0].Name)
+	assert.Nil(t, list.SystemChannel)
+
+	require.NoError(t, client.Remove(ctx, "mychannel"))
+	_, err = client.ChannelInfo(ctx, "mychannel")
+	assert.Equal(t, ErrChannelNotFound, err)
+
+	_, err = client.Join(ctx, nil)
+	assert.Error(t, err)
+}
+
+func TestNewForOrderer(t *testing.T) {
+	config := mocks.NewMockEndpointConfig()
+
+	_, err := NewForOrderer(config, &fab.OrdererConfig{URL: "orderer.example.com:7050"})
+	assert.Error(t, err, "expecting error since admin URL is not configured")
+
+	client, err := NewForOrderer(config, &fab.OrdererConfig{
+		URL:         "orderer.example.com:7050",
+		AdminURL:    "https://orderer.example.com:7053/",
+		GRPCOptions: map[string]interface{}{"ssl-target-name-override": "orderer.example.com"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://orderer.example.com:7053", client.URL())
+}
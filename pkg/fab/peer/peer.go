@@ -10,14 +10,19 @@ import (
 	reqContext "context"
 
 	"crypto/x509"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cast"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peerstats"
 )
 
 var logger = logging.NewLogger("fabsdk/fab")
@@ -34,6 +39,10 @@ type Peer struct {
 	kap         keepalive.ClientParameters
 	failFast    bool
 	inSecure    bool
+	lbPolicy    string
+	proxyURL    string
+	dialer      comm.Dialer
+	tlsOptions  *comm.TLSOptions
 	commManager fab.CommManager
 }
 
@@ -65,6 +74,10 @@ func New(config fab.EndpointConfig, opts ...Option) (*Peer, error) {
 			kap:                peer.kap,
 			failFast:           peer.failFast,
 			allowInsecure:      peer.inSecure,
+			lbPolicy:           peer.lbPolicy,
+			proxyURL:           peer.proxyURL,
+			dialer:             peer.dialer,
+			tlsOptions:         peer.tlsOptions,
 			commManager:        peer.commManager,
 		}
 		processor, err := newPeerEndorser(&endorseRequest)
@@ -114,6 +127,36 @@ func WithInsecure() Option {
 	}
 }
 
+// WithLoadBalancingPolicy is a functional option for the peer.New constructor that configures the gRPC load
+// balancing policy used across all of the addresses that the peer's host name resolves to
+func WithLoadBalancingPolicy(policy string) Option {
+	return func(p *Peer) error {
+		p.lbPolicy = policy
+
+		return nil
+	}
+}
+
+// WithProxyURL is a functional option for the peer.New constructor that configures the URL of the SOCKS5
+// or HTTP CONNECT proxy through which connections to the peer are established
+func WithProxyURL(proxyURL string) Option {
+	return func(p *Peer) error {
+		p.proxyURL = proxyURL
+
+		return nil
+	}
+}
+
+// WithDialer is a functional option for the peer.New constructor that configures a custom dialer
+// with which connections to the peer are established
+func WithDialer(dialer comm.Dialer) Option {
+	return func(p *Peer) error {
+		p.dialer = dialer
+
+		return nil
+	}
+}
+
 // WithMSPID is a functional option for the peer.New constructor that configures the peer's msp ID
 func WithMSPID(mspID string) Option {
 	return func(p *Peer) error {
@@ -123,6 +166,21 @@ func WithMSPID(mspID string) Option {
 	}
 }
 
+// WithTLSOptions is a functional option for the peer.New constructor that configures the TLS versions
+// and cipher suites allowed for connections to the peer as well as the additional verifications of its certificate
+func WithTLSOptions(tlsOptions *comm.TLSOptions) Option {
+	return func(p *Peer) error {
+		if tlsOptions != nil {
+			if err := tlsOptions.Validate(); err != nil {
+				return err
+			}
+		}
+		p.tlsOptions = tlsOptions
+
+		return nil
+	}
+}
+
 // FromPeerConfig is a functional option for the peer.New constructor that configures a new peer
 // from a apiconfig.NetworkPeer struct
 func FromPeerConfig(peerCfg *fab.NetworkPeer) Option {
@@ -146,6 +204,16 @@ func FromPeerConfig(peerCfg *fab.NetworkPeer) Option {
 		p.mspID = peerCfg.MSPID
 		p.kap = getKeepAliveOptions(peerCfg)
 		p.failFast = getFailFast(peerCfg)
+		p.lbPolicy = comm.LoadBalancingPolicy(peerCfg.GRPCOptions)
+		p.proxyURL = comm.ProxyURL(peerCfg.GRPCOptions)
+		p.tlsOptions, err = comm.TLSOptionsFromGRPC(peerCfg.GRPCOptions)
+		if err != nil {
+			return errors.WithMessage(err, "invalid TLS options of peer ["+peerCfg.URL+"]")
+		}
+		p.dialer, err = comm.DialerFromGRPC(peerCfg.GRPCOptions)
+		if err != nil {
+			return errors.WithMessage(err, "invalid dialer of peer ["+peerCfg.URL+"]")
+		}
 		return nil
 	}
 }
@@ -213,7 +281,26 @@ func (p *Peer) URL() string {
 
 // ProcessTransactionProposal sends the created proposal to peer for endorsement.
 func (p *Peer) ProcessTransactionProposal(ctx reqContext.Context, proposal fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
-	return p.processor.ProcessTransactionProposal(ctx, proposal)
+	start := time.Now()
+	resp, err := p.processor.ProcessTransactionProposal(ctx, proposal)
+	peerstats.Default().Record(p.url, time.Since(start), isPeerFailure(err))
+	if resp != nil {
+		resp.EndorserMSPID = p.mspID
+	}
+	return resp, err
+}
+
+// isPeerFailure returns true if the error indicates that the peer is unavailable or degraded
+// (as opposed to an error returned by the chaincode)
+func isPeerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return true
+	}
+	return s.Group != status.ChaincodeStatus && s.Group != status.EndorserServerStatus
 }
 
 func (p *Peer) String() string {
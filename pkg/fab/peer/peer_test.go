@@ -8,6 +8,7 @@ package peer
 
 import (
 	reqContext "context"
+	"crypto/tls"
 	"reflect"
 	"testing"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockfab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 )
 
 const (
@@ -210,6 +212,43 @@ func TestPeerOptions(t *testing.T) {
 	}
 }
 
+// TestPeerTLSOptions validates the TLS options of the peer config
+func TestPeerTLSOptions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	config := mockfab.DefaultMockConfig(mockCtrl)
+
+	networkPeer := &fab.NetworkPeer{
+		PeerConfig: fab.PeerConfig{
+			URL: "abc.com",
+			GRPCOptions: map[string]interface{}{
+				"allow-insecure":           true,
+				comm.TLSMinVersionOption:   "1.2",
+				comm.TLSCipherSuitesOption: "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+			},
+		},
+	}
+
+	p, err := New(config, FromPeerConfig(networkPeer))
+	if err != nil {
+		t.Fatalf("Failed to create new peer FromPeerConfig (%s)", err)
+	}
+	if p.tlsOptions == nil || p.tlsOptions.MinVersion != tls.VersionTLS12 || len(p.tlsOptions.CipherSuites) != 1 {
+		t.Fatalf("Unexpected TLS options: %+v", p.tlsOptions)
+	}
+
+	networkPeer.GRPCOptions[comm.TLSMaxVersionOption] = "1.1"
+	_, err = New(config, FromPeerConfig(networkPeer))
+	if err == nil {
+		t.Fatal("Expected error since TLS min version is greater than max version")
+	}
+
+	_, err = New(config, WithURL("grpcs://0.0.0.0:1234"), WithTLSOptions(&comm.TLSOptions{MinVersion: tls.VersionTLS12}))
+	if err != nil {
+		t.Fatalf("Failed to create new peer WithTLSOptions (%s)", err)
+	}
+}
+
 // TestNewPeerSecured validates that insecure option
 func TestNewPeerSecured(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
@@ -42,6 +42,7 @@ const (
 type peerEndorser struct {
 	grpcDialOption []grpc.DialOption
 	target         string
+	dialTarget     string
 	dialTimeout    time.Duration
 	commManager    fab.CommManager
 }
@@ -54,6 +55,10 @@ type peerEndorserRequest struct {
 	kap                keepalive.ClientParameters
 	failFast           bool
 	allowInsecure      bool
+	lbPolicy           string
+	proxyURL           string
+	dialer             comm.Dialer
+	tlsOptions         *comm.TLSOptions
 	commManager        fab.CommManager
 }
 
@@ -78,6 +83,7 @@ func newPeerEndorser(endorseReq *peerEndorserRequest) (*peerEndorser, error) {
 		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
 			return verifier.VerifyPeerCertificate(rawCerts, verifiedChains)
 		}
+		endorseReq.tlsOptions.Apply(tlsConfig)
 		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	} else {
 		grpcOpts = append(grpcOpts, grpc.WithInsecure())
@@ -86,11 +92,25 @@ func newPeerEndorser(endorseReq *peerEndorserRequest) (*peerEndorser, error) {
 	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize),
 		grpc.MaxCallSendMsgSize(maxCallSendMsgSize)))
 
+	target := endpoint.ToAddress(endorseReq.target)
+	dialTarget, lbOpts, err := comm.DialTarget(target, endorseReq.lbPolicy)
+	if err != nil {
+		return nil, err
+	}
+	grpcOpts = append(grpcOpts, lbOpts...)
+
+	proxyOpts, err := comm.DialOptions(endorseReq.proxyURL, endorseReq.dialer)
+	if err != nil {
+		return nil, err
+	}
+	grpcOpts = append(grpcOpts, proxyOpts...)
+
 	timeout := endorseReq.config.Timeout(fab.PeerConnection)
 
 	pc := &peerEndorser{
 		grpcDialOption: grpcOpts,
-		target:         endpoint.ToAddress(endorseReq.target),
+		target:         target,
+		dialTarget:     dialTarget,
 		dialTimeout:    timeout,
 		commManager:    endorseReq.commManager,
 	}
@@ -105,7 +125,7 @@ func (p *peerEndorser) ProcessTransactionProposal(ctx reqContext.Context, reques
 	proposalResponse, err := p.sendProposal(ctx, request)
 	if err != nil {
 		tpr := fab.TransactionProposalResponse{Endorser: p.target}
-		return &tpr, errors.Wrapf(err, "Transaction processing for endorser [%s]", p.target)
+		return &tpr, errors.Wrapf(status.WithEndpoint(err, p.target), "Transaction processing for endorser [%s]", p.target)
 	}
 
 	chaincodeStatus, err := getChaincodeResponseStatus(proposalResponse)
@@ -131,7 +151,7 @@ func (p *peerEndorser) conn(ctx reqContext.Context) (*grpc.ClientConn, error) {
 	ctx, cancel := reqContext.WithTimeout(ctx, p.dialTimeout)
 	defer cancel()
 
-	return commManager.DialContext(ctx, p.target, p.grpcDialOption...)
+	return commManager.DialContext(ctx, p.dialTarget, p.grpcDialOption...)
 }
 
 func (p *peerEndorser) releaseConn(ctx reqContext.Context, conn *grpc.ClientConn) {
@@ -236,6 +256,9 @@ func extractChaincodeErrorFromResponse(resp *pb.ProposalResponse) error {
 			return status.New(status.EndorserClientStatus, int32(status.ChaincodeNameNotFound), resp.Response.Message, details)
 		} else if strings.Contains(resp.Response.Message, "cannot get package for chaincode") {
 			return status.New(status.EndorserClientStatus, int32(status.ChaincodeNameNotFound), resp.Response.Message, details)
+		} else if resp.Response.Status == int32(common.Status_FORBIDDEN) {
+			// The endorser denied access (matches status.ErrAccessDenied)
+			return status.New(status.EndorserServerStatus, resp.Response.Status, resp.Response.Message, details)
 		}
 		return status.New(status.ChaincodeStatus, resp.Response.Status, resp.Response.Message, details)
 	}
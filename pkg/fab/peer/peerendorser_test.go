@@ -22,6 +22,7 @@ import (
 	grpcstatus "google.golang.org/grpc/status"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
@@ -423,4 +424,23 @@ func TestChaincodeStatusFromResponse(t *testing.T) {
 	assert.Equal(t, "cannot get package for chaincode (vl5knffa37:v0)", s.Message)
 	assert.Equal(t, int32(status.ChaincodeNameNotFound), s.Code)
 	assert.Equal(t, status.EndorserClientStatus, s.Group)
+	assert.True(t, status.Is(err, status.ErrChaincodeNotFound))
+
+	//For error response - access denied
+	response = &pb.ProposalResponse{
+		Response: &pb.Response{Status: int32(common.Status_FORBIDDEN), Message: "access denied: channel [mychannel] creator org [Org1MSP]"},
+	}
+	err = extractChaincodeErrorFromResponse(response)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, int32(common.Status_FORBIDDEN), s.Code)
+	assert.Equal(t, status.EndorserServerStatus, s.Group)
+	assert.True(t, status.Is(err, status.ErrAccessDenied))
+
+	//For error response - a message which mentions access denied but with a different status is not matched
+	response = &pb.ProposalResponse{
+		Response: &pb.Response{Status: 500, Message: "chaincode error: access denied to resource"},
+	}
+	err = extractChaincodeErrorFromResponse(response)
+	assert.False(t, status.Is(err, status.ErrAccessDenied))
 }
@@ -0,0 +1,223 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package peerstats tracks the recent latency and error rate of the requests sent to each peer
+// so that the selection balancers may shift traffic away from degraded peers.
+package peerstats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+const (
+	// DefaultDecay is the weight given to the most recent request in the moving averages
+	DefaultDecay = 0.2
+
+	// DefaultExpiry is the time after which the stats of a peer that hasn't received
+	// a request are discarded (so that a peer which recovers is given traffic again)
+	DefaultExpiry = time.Minute
+
+	// DefaultReferenceLatency is the latency at which the weight of a peer is halved
+	DefaultReferenceLatency = 500 * time.Millisecond
+
+	// DefaultBlockHeightLagThreshold is the number of blocks that a peer may lag behind
+	// the highest peer before its weight is reduced
+	DefaultBlockHeightLagThreshold = 5
+
+	// minWeight ensures that a degraded peer is still chosen occasionally
+	minWeight = 0.01
+)
+
+// Stats contains the recent stats of a peer
+type Stats struct {
+	// Latency is the moving average of the request latency
+	Latency time.Duration
+	// ErrorRate is the moving average of the error rate (between 0 and 1)
+	ErrorRate float64
+	// Requests is the number of requests tracked
+	Requests uint64
+	// Updated is the time of the last request
+	Updated time.Time
+}
+
+// Tracker tracks the stats of peers by URL
+type Tracker struct {
+	mutex            sync.RWMutex
+	stats            map[string]*Stats
+	decay            float64
+	expiry           time.Duration
+	referenceLatency time.Duration
+	lagThreshold     uint64
+}
+
+// Opt is a tracker option
+type Opt func(t *Tracker)
+
+// WithDecay sets the weight (between 0 and 1) given to the most recent request in the moving averages
+func WithDecay(value float64) Opt {
+	return func(t *Tracker) {
+		t.decay = value
+	}
+}
+
+// WithExpiry sets the time after which the stats of an idle peer are discarded
+func WithExpiry(value time.Duration) Opt {
+	return func(t *Tracker) {
+		t.expiry = value
+	}
+}
+
+// WithReferenceLatency sets the latency at which the weight of a peer is halved
+func WithReferenceLatency(value time.Duration) Opt {
+	return func(t *Tracker) {
+		t.referenceLatency = value
+	}
+}
+
+// WithBlockHeightLagThreshold sets the number of blocks that a peer may lag behind
+// the highest peer before its weight is reduced
+func WithBlockHeightLagThreshold(value uint64) Opt {
+	return func(t *Tracker) {
+		t.lagThreshold = value
+	}
+}
+
+// NewTracker returns a new stats tracker
+func NewTracker(opts ...Opt) *Tracker {
+	t := &Tracker{
+		stats:            make(map[string]*Stats),
+		decay:            DefaultDecay,
+		expiry:           DefaultExpiry,
+		referenceLatency: DefaultReferenceLatency,
+		lagThreshold:     DefaultBlockHeightLagThreshold,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.decay <= 0 || t.decay > 1 {
+		t.decay = DefaultDecay
+	}
+	return t
+}
+
+var defaultTracker = NewTracker()
+
+// Default returns the tracker to which the SDK peers report their requests
+func Default() *Tracker {
+	return defaultTracker
+}
+
+// Record records the latency and outcome of a request sent to the peer with the given URL
+func (t *Tracker) Record(url string, latency time.Duration, failed bool) {
+	errValue := 0.0
+	if failed {
+		errValue = 1.0
+	}
+
+	now := time.Now()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	s, ok := t.stats[url]
+	if !ok || t.expired(s, now) {
+		t.stats[url] = &Stats{Latency: latency, ErrorRate: errValue, Requests: 1, Updated: now}
+		return
+	}
+
+	s.Latency = time.Duration(t.decay*float64(latency) + (1-t.decay)*float64(s.Latency))
+	s.ErrorRate = t.decay*errValue + (1-t.decay)*s.ErrorRate
+	s.Requests++
+	s.Updated = now
+}
+
+// Get returns the stats of the peer with the given URL. False is returned
+// if no requests were recently sent to the peer.
+func (t *Tracker) Get(url string) (Stats, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	s, ok := t.stats[url]
+	if !ok || t.expired(s, time.Now()) {
+		return Stats{}, false
+	}
+	return *s, true
+}
+
+// Weight returns the weight of the given peer (between 0 and 1) given the highest ledger height
+// of the peers being considered. The weight is reduced by the error rate and the latency of the peer
+// and by its ledger height if it lags too far behind. (A peer with no recent stats has weight 1.)
+func (t *Tracker) Weight(peer fab.Peer, maxHeight uint64) float64 {
+	weight := 1.0
+
+	if s, ok := t.Get(peer.URL()); ok {
+		weight *= 1 - s.ErrorRate
+		if t.referenceLatency > 0 {
+			weight /= 1 + float64(s.Latency)/float64(t.referenceLatency)
+		}
+	}
+
+	if height := blockHeight(peer); height > 0 && height+t.lagThreshold < maxHeight {
+		weight /= float64(1 + maxHeight - height - t.lagThreshold)
+	}
+
+	if weight < minWeight {
+		return minWeight
+	}
+	return weight
+}
+
+// Compare gives priority to the peer with the higher weight or, if their weights are the same,
+// to the peer with the higher ledger height. A positive value means peer1 has priority; a negative
+// value means peer2 has priority. (The function may be used as a selection priority selector.)
+func (t *Tracker) Compare(peer1, peer2 fab.Peer) int {
+	maxHeight := MaxBlockHeight([]fab.Peer{peer1, peer2})
+
+	w1 := t.Weight(peer1, maxHeight)
+	w2 := t.Weight(peer2, maxHeight)
+	switch {
+	case w1 > w2:
+		return 1
+	case w1 < w2:
+		return -1
+	}
+
+	h1 := blockHeight(peer1)
+	h2 := blockHeight(peer2)
+	switch {
+	case h1 > h2:
+		return 1
+	case h1 < h2:
+		return -1
+	}
+	return 0
+}
+
+func (t *Tracker) expired(s *Stats, now time.Time) bool {
+	return t.expiry > 0 && now.Sub(s.Updated) > t.expiry
+}
+
+// MaxBlockHeight returns the highest ledger height of the given peers
+// (zero if none of the peers provide their ledger height)
+func MaxBlockHeight(peers []fab.Peer) uint64 {
+	var maxHeight uint64
+	for _, peer := range peers {
+		if height := blockHeight(peer); height > maxHeight {
+			maxHeight = height
+		}
+	}
+	return maxHeight
+}
+
+func blockHeight(peer fab.Peer) uint64 {
+	if state, ok := peer.(fab.PeerState); ok {
+		return state.BlockHeight()
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peerstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type peerWithHeight struct {
+	*mocks.MockPeer
+	blockHeight uint64
+}
+
+func (p *peerWithHeight) BlockHeight() uint64 {
+	return p.blockHeight
+}
+
+func newPeer(url string, blockHeight uint64) *peerWithHeight {
+	return &peerWithHeight{MockPeer: mocks.NewMockPeer(url, url), blockHeight: blockHeight}
+}
+
+func TestRecord(t *testing.T) {
+	tracker := NewTracker(WithDecay(0.5))
+
+	_, ok := tracker.Get("peer1")
+	assert.False(t, ok)
+
+	tracker.Record("peer1", 100*time.Millisecond, false)
+	tracker.Record("peer1", 300*time.Millisecond, true)
+
+	s, ok := tracker.Get("peer1")
+	require.True(t, ok)
+	assert.Equal(t, 200*time.Millisecond, s.Latency)
+	assert.Equal(t, 0.5, s.ErrorRate)
+	assert.Equal(t, uint64(2), s.Requests)
+}
+
+func TestExpiry(t *testing.T) {
+	tracker := NewTracker(WithExpiry(50 * time.Millisecond))
+
+	tracker.Record("peer1", time.Second, true)
+	_, ok := tracker.Get("peer1")
+	assert.True(t, ok)
+
+	time.Sleep(100 * time.Millisecond)
+	_, ok = tracker.Get("peer1")
+	assert.False(t, ok, "expecting stats of idle peer to expire")
+	assert.Equal(t, 1.0, tracker.Weight(newPeer("peer1", 0), 0))
+}
+
+func TestWeight(t *testing.T) {
+	tracker := NewTracker(WithReferenceLatency(100*time.Millisecond), WithBlockHeightLagThreshold(2))
+
+	healthy := newPeer("healthy", 10)
+	slow := newPeer("slow", 10)
+	failing := newPeer("failing", 10)
+	lagging := newPeer("lagging", 5)
+
+	tracker.Record(slow.URL(), 100*time.Millisecond, false)
+	tracker.Record(failing.URL(), 10*time.Millisecond, true)
+
+	assert.Equal(t, 1.0, tracker.Weight(healthy, 10))
+	assert.Equal(t, 0.5, tracker.Weight(slow, 10))
+	assert.Equal(t, minWeight, tracker.Weight(failing, 10))
+	assert.Equal(t, 0.25, tracker.Weight(lagging, 10))
+	assert.Equal(t, 1.0, tracker.Weight(lagging, 7), "expecting no penalty within the lag threshold")
+
+	assert.Equal(t, 1, tracker.Compare(healthy, slow))
+	assert.Equal(t, -1, tracker.Compare(failing, slow))
+	assert.Equal(t, 1, tracker.Compare(healthy, lagging))
+	assert.Equal(t, 1, tracker.Compare(newPeer("peer1", 11), newPeer("peer2", 10)), "expecting higher ledger to have priority")
+	assert.Equal(t, 0, tracker.Compare(newPeer("peer1", 10), newPeer("peer2", 10)))
+
+	assert.Equal(t, uint64(10), MaxBlockHeight([]fab.Peer{healthy, lagging}))
+	assert.Equal(t, uint64(0), MaxBlockHeight(nil))
+}
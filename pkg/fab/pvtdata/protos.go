@@ -0,0 +1,42 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdata
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset"
+)
+
+// The messages below are defined by the deliver service of Fabric 1.4 (and later) peers (peer/events.proto),
+// which are not included in the Fabric protos vendored by the SDK.
+
+const deliverWithPrivateDataMethod = "/protos.Deliver/DeliverWithPrivateData"
+
+// deliverResponse is the response of the deliver service. The fields correspond to the members of the
+// 'Type' oneof of protos.DeliverResponse (which is encoded the same way as optional fields). The
+// filtered block (field 3) is never returned by DeliverWithPrivateData.
+type deliverResponse struct {
+	Status              common.Status        `protobuf:"varint,1,opt,name=status,enum=common.Status" json:"status,omitempty"`
+	Block               *common.Block        `protobuf:"bytes,2,opt,name=block" json:"block,omitempty"`
+	BlockAndPrivateData *blockAndPrivateData `protobuf:"bytes,4,opt,name=block_and_private_data,json=blockAndPrivateData" json:"block_and_private_data,omitempty"`
+}
+
+func (m *deliverResponse) Reset()         { *m = deliverResponse{} }
+func (m *deliverResponse) String() string { return proto.CompactTextString(m) }
+func (*deliverResponse) ProtoMessage()    {}
+
+// blockAndPrivateData contains a block and the private data of its transactions, keyed
+// by the sequence number of the transaction in the block
+type blockAndPrivateData struct {
+	Block          *common.Block                      `protobuf:"bytes,1,opt,name=block" json:"block,omitempty"`
+	PrivateDataMap map[uint64]*rwset.TxPvtReadWriteSet `protobuf:"bytes,2,rep,name=private_data_map,json=privateDataMap" json:"private_data_map,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *blockAndPrivateData) Reset()         { *m = blockAndPrivateData{} }
+func (m *blockAndPrivateData) String() string { return proto.CompactTextString(m) }
+func (*blockAndPrivateData) ProtoMessage()    {}
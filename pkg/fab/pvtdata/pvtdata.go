@@ -0,0 +1,480 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package pvtdata detects private data which is missing on a peer, e.g. because it was never disseminated to the
+// peer's organization. The blocks are requested from the peer's deliver service along with the private data which
+// the peer has stored (DeliverWithPrivateData, Fabric 1.4 or later) and the private data is compared with the
+// hashed read-write sets of the valid transactions.
+//
+// The peer only returns the private data of the collections which the requester is eligible for (according to
+// the collection's member policy). Therefore the requester should be a member of the peer's organization and
+// the check should be restricted to the collections of which the organization is a member. Private data which
+// was purged (see the collection's blockToLive) is reported as missing as well, except for the private data of
+// keys which are purged explicitly (PurgePrivateData, Fabric 2.5 or later) within the checked block range.
+package pvtdata
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	ccomm "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/audit"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+var logger = logging.NewLogger("fabsdk/fab")
+
+// Collection identifies a private data collection of a chaincode
+type Collection struct {
+	Namespace string
+	Name      string
+}
+
+// MissingPrivateData identifies the private data of a collection which was written by a
+// transaction but isn't available on the peer
+type MissingPrivateData struct {
+	BlockNumber uint64
+	TxNum       uint64
+	TxID        string
+	Collection  Collection
+}
+
+// PurgeMarker records the purge of a private data key by a transaction (PurgePrivateData, Fabric 2.5 or later).
+// When the transaction is committed, the peers remove the private data history of the key.
+type PurgeMarker struct {
+	BlockNumber uint64
+	TxNum       uint64
+	TxID        string
+	Collection  Collection
+	KeyHash     []byte
+}
+
+// BlockAndPrivateData contains a block and the private data of its transactions which is available on the peer,
+// keyed by the sequence number of the transaction in the block
+type BlockAndPrivateData struct {
+	Block       *common.Block
+	PrivateData map[uint64]*rwset.TxPvtReadWriteSet
+}
+
+// Client queries the blocks and private data of a peer
+type Client struct {
+	ctx fabcontext.Client
+}
+
+// New returns a new private data client
+func New(ctx fabcontext.Client) *Client {
+	return &Client{ctx: ctx}
+}
+
+// QueryMissing returns the private data of the given blocks (inclusive) which isn't available on the peer.
+// Only the given collections are checked; if no collections are given then all collections are checked.
+// The private data of keys which are purged by a later transaction in the range isn't reported.
+func (c *Client) QueryMissing(reqCtx context.Context, target fab.PeerConfig, channelID string, fromBlock, toBlock uint64, collections ...Collection) ([]MissingPrivateData, error) {
+	var missing []*missingWrites
+	var purges []PurgeMarker
+	err := c.deliver(reqCtx, target, channelID, fromBlock, toBlock, func(b *BlockAndPrivateData) error {
+		m, err := missingWritesOf(b, collections...)
+		if err != nil {
+			return err
+		}
+		missing = append(missing, m...)
+
+		p, err := PurgeMarkers(b.Block)
+		if err != nil {
+			return err
+		}
+		purges = append(purges, p...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return excludePurged(missing, purges), nil
+}
+
+// Deliver returns the given blocks (inclusive) along with the private data which is available on the peer
+func (c *Client) Deliver(reqCtx context.Context, target fab.PeerConfig, channelID string, fromBlock, toBlock uint64) ([]*BlockAndPrivateData, error) {
+	var blocks []*BlockAndPrivateData
+	err := c.deliver(reqCtx, target, channelID, fromBlock, toBlock, func(b *BlockAndPrivateData) error {
+		blocks = append(blocks, b)
+		return nil
+	})
+	return blocks, err
+}
+
+func (c *Client) deliver(reqCtx context.Context, target fab.PeerConfig, channelID string, fromBlock, toBlock uint64, handle func(b *BlockAndPrivateData) error) error {
+	if channelID == "" {
+		return errors.New("channel ID is required")
+	}
+	if fromBlock > toBlock {
+		return errors.Errorf("invalid block range [%d, %d]", fromBlock, toBlock)
+	}
+
+	envelope, err := c.newSeekEnvelope(channelID, fromBlock, toBlock, target.URL)
+	if err != nil {
+		return err
+	}
+
+	opts := comm.OptsFromPeerConfig(&target)
+	opts = append(opts, comm.WithConnectTimeout(c.ctx.EndpointConfig().Timeout(fab.PeerConnection)))
+
+	conn, err := comm.NewConnection(c.ctx, target.URL, opts...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	streamCtx, cancel := context.WithCancel(reqCtx)
+	defer cancel()
+
+	stream, err := conn.ClientConn().NewStream(streamCtx, &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}, deliverWithPrivateDataMethod)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open deliver stream to [%s]", target.URL)
+	}
+	if err := stream.SendMsg(envelope); err != nil {
+		return errors.Wrapf(err, "failed to send deliver request to [%s]", target.URL)
+	}
+	if err := stream.CloseSend(); err != nil {
+		logger.Debugf("unable to close deliver stream: %s", err)
+	}
+
+	logger.Debugf("Requested blocks [%d, %d] of channel [%s] with private data from [%s]", fromBlock, toBlock, channelID, target.URL)
+
+	for {
+		resp := &deliverResponse{}
+		if err := stream.RecvMsg(resp); err != nil {
+			if err == io.EOF {
+				return errors.Errorf("deliver stream of [%s] closed without status", target.URL)
+			}
+			return errors.Wrapf(err, "failed to receive from [%s]", target.URL)
+		}
+
+		switch {
+		case resp.BlockAndPrivateData != nil:
+			b := resp.BlockAndPrivateData
+			if err := handle(&BlockAndPrivateData{Block: b.Block, PrivateData: b.PrivateDataMap}); err != nil {
+				return err
+			}
+		case resp.Block != nil:
+			return errors.Errorf("peer [%s] returned a block without private data", target.URL)
+		case resp.Status == common.Status_SUCCESS:
+			return nil
+		default:
+			return errors.Errorf("deliver request to [%s] failed with status %s", target.URL, resp.Status)
+		}
+	}
+}
+
+func (c *Client) newSeekEnvelope(channelID string, fromBlock, toBlock uint64, endpoint string) (*common.Envelope, error) {
+	th, err := txn.NewHeader(c.ctx, channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "generating TX ID failed")
+	}
+
+	hash, err := ccomm.TLSCertHash(c.ctx.EndpointConfig())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get tls cert hash")
+	}
+
+	channelHeader, err := txn.CreateChannelHeader(common.HeaderType_DELIVER_SEEK_INFO, txn.ChannelHeaderOpts{TxnHeader: th, TLSCertHash: hash})
+	if err != nil {
+		return nil, errors.WithMessage(err, "CreateChannelHeader failed")
+	}
+	signatureHeader, err := txn.CreateSignatureHeader(th)
+	if err != nil {
+		return nil, errors.WithMessage(err, "CreateSignatureHeader failed")
+	}
+
+	seekInfo := &ab.SeekInfo{
+		Start:    &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: fromBlock}}},
+		Stop:     &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: toBlock}}},
+		Behavior: ab.SeekInfo_FAIL_IF_NOT_READY,
+	}
+
+	payloadBytes, err := marshalPayload(channelHeader, signatureHeader, seekInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := c.ctx.SigningManager().Sign(payloadBytes, c.ctx.PrivateKey())
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing of deliver request failed")
+	}
+	audit.RecordSigned(c.ctx, audit.PurposeDeliverRequest, payloadBytes, endpoint)
+
+	return &common.Envelope{Payload: payloadBytes, Signature: signature}, nil
+}
+
+func marshalPayload(channelHeader *common.ChannelHeader, signatureHeader *common.SignatureHeader, seekInfo *ab.SeekInfo) ([]byte, error) {
+	channelHeaderBytes, err := proto.Marshal(channelHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of channel header failed")
+	}
+	signatureHeaderBytes, err := proto.Marshal(signatureHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of signature header failed")
+	}
+	seekInfoBytes, err := proto.Marshal(seekInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of seek info failed")
+	}
+
+	payloadBytes, err := proto.Marshal(&common.Payload{
+		Header: &common.Header{ChannelHeader: channelHeaderBytes, SignatureHeader: signatureHeaderBytes},
+		Data:   seekInfoBytes,
+	})
+	return payloadBytes, errors.Wrap(err, "marshal of payload failed")
+}
+
+// Missing returns the private data which was written by the valid transactions of the block but isn't contained
+// in the private data of the block. Only the given collections are checked; if no collections are given then
+// all collections are checked.
+func Missing(b *BlockAndPrivateData, collections ...Collection) ([]MissingPrivateData, error) {
+	m, err := missingWritesOf(b, collections...)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []MissingPrivateData
+	for _, w := range m {
+		missing = append(missing, w.MissingPrivateData)
+	}
+	return missing, nil
+}
+
+// PurgeMarkers returns the private data keys which are purged by the valid transactions of the block
+func PurgeMarkers(block *common.Block) ([]PurgeMarker, error) {
+	var purges []PurgeMarker
+	err := forEachValidTx(block, func(txNum uint64, txID string, writes []*collectionWrites) error {
+		for _, w := range writes {
+			for _, keyHash := range w.purgedKeys {
+				purges = append(purges, PurgeMarker{BlockNumber: block.Header.Number, TxNum: txNum, TxID: txID, Collection: w.collection, KeyHash: keyHash})
+			}
+		}
+		return nil
+	})
+	return purges, err
+}
+
+// KeyHash returns the hash of a private data key as it's recorded in the hashed read-write sets,
+// e.g. in order to find the purge markers of the key
+func KeyHash(key string) []byte {
+	hash := sha256.Sum256([]byte(key))
+	return hash[:]
+}
+
+// missingWrites is missing private data along with the hashes of the keys written by the transaction
+type missingWrites struct {
+	MissingPrivateData
+	keyHashes [][]byte
+}
+
+func missingWritesOf(b *BlockAndPrivateData, collections ...Collection) ([]*missingWrites, error) {
+	if b == nil {
+		return nil, errors.New("block is incomplete")
+	}
+
+	include := make(map[Collection]bool)
+	for _, coll := range collections {
+		include[coll] = true
+	}
+
+	var missing []*missingWrites
+	err := forEachValidTx(b.Block, func(txNum uint64, txID string, writes []*collectionWrites) error {
+		available := availableCollections(b.PrivateData[txNum])
+		for _, w := range writes {
+			if len(include) > 0 && !include[w.collection] {
+				continue
+			}
+			if len(w.keyHashes) == 0 && len(w.purgedKeys) > 0 {
+				// the private data of a transaction which only purges keys is removed along with the keys
+				continue
+			}
+			if !available[w.collection] {
+				missing = append(missing, &missingWrites{
+					MissingPrivateData: MissingPrivateData{BlockNumber: b.Block.Header.Number, TxNum: txNum, TxID: txID, Collection: w.collection},
+					keyHashes:          w.keyHashes,
+				})
+			}
+		}
+		return nil
+	})
+	return missing, err
+}
+
+// excludePurged returns the missing private data except for the data of which all keys are purged by a later transaction
+func excludePurged(missing []*missingWrites, purges []PurgeMarker) []MissingPrivateData {
+	var result []MissingPrivateData
+	for _, m := range missing {
+		if !allPurged(m, purges) {
+			result = append(result, m.MissingPrivateData)
+		}
+	}
+	return result
+}
+
+func allPurged(m *missingWrites, purges []PurgeMarker) bool {
+	for _, keyHash := range m.keyHashes {
+		purged := false
+		for _, p := range purges {
+			if p.Collection == m.Collection && bytes.Equal(p.KeyHash, keyHash) && isAfter(p, m.MissingPrivateData) {
+				purged = true
+				break
+			}
+		}
+		if !purged {
+			return false
+		}
+	}
+	return true
+}
+
+func isAfter(p PurgeMarker, m MissingPrivateData) bool {
+	return p.BlockNumber > m.BlockNumber || (p.BlockNumber == m.BlockNumber && p.TxNum > m.TxNum)
+}
+
+// collectionWrites are the hashed private writes of a transaction to a collection
+type collectionWrites struct {
+	collection Collection
+	// keyHashes are the hashes of the keys which are written or deleted
+	keyHashes [][]byte
+	// purgedKeys are the hashes of the keys which are purged
+	purgedKeys [][]byte
+}
+
+// forEachValidTx invokes the handler with the private writes of each valid transaction of the block
+func forEachValidTx(block *common.Block, handle func(txNum uint64, txID string, writes []*collectionWrites) error) error {
+	if block == nil || block.Header == nil || block.Data == nil {
+		return errors.New("block is incomplete")
+	}
+
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = ledgerutil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	for i, data := range block.Data.Data {
+		if i < len(txFilter) && !txFilter.IsValid(i) {
+			// private data isn't stored for invalid transactions
+			continue
+		}
+
+		txID, writes, err := privateWrites(data)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("failed to decode transaction %d of block %d", i, block.Header.Number))
+		}
+		if err := handle(uint64(i), txID, writes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// privateWrites returns the ID of the transaction and its hashed private writes
+func privateWrites(data []byte) (string, []*collectionWrites, error) {
+	env, err := utils.GetEnvelopeFromBlock(data)
+	if err != nil {
+		return "", nil, err
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return "", nil, err
+	}
+	if payload.Header == nil {
+		return "", nil, errors.New("payload header is missing")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return "", nil, err
+	}
+	if chdr.Type != int32(common.HeaderType_ENDORSER_TRANSACTION) {
+		return chdr.TxId, nil, nil
+	}
+
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var writes []*collectionWrites
+	for _, action := range tx.Actions {
+		ccActionPayload, err := utils.GetChaincodeActionPayload(action.Payload)
+		if err != nil {
+			return "", nil, err
+		}
+		if ccActionPayload.Action == nil {
+			return "", nil, errors.New("chaincode endorsed action is missing")
+		}
+		prp, err := utils.GetProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+		if err != nil {
+			return "", nil, err
+		}
+		ccAction, err := utils.GetChaincodeAction(prp.Extension)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(ccAction.Results) == 0 {
+			continue
+		}
+
+		txRWSet := &rwsetutil.TxRwSet{}
+		if err := txRWSet.FromProtoBytes(ccAction.Results); err != nil {
+			return "", nil, errors.Wrap(err, "failed to unmarshal read-write set")
+		}
+		for _, ns := range txRWSet.NsRwSets {
+			for _, coll := range ns.CollHashedRwSets {
+				// the hash of the private read-write set is only set if there are private writes
+				if len(coll.PvtRwSetHash) == 0 {
+					continue
+				}
+				writes = append(writes, newCollectionWrites(Collection{Namespace: ns.NameSpace, Name: coll.CollectionName}, coll.HashedRwSet))
+			}
+		}
+	}
+	return chdr.TxId, writes, nil
+}
+
+func newCollectionWrites(coll Collection, hashedRWSet *kvrwset.HashedRWSet) *collectionWrites {
+	w := &collectionWrites{collection: coll}
+	for _, write := range hashedRWSet.GetHashedWrites() {
+		if write.IsPurge {
+			w.purgedKeys = append(w.purgedKeys, write.KeyHash)
+		} else {
+			w.keyHashes = append(w.keyHashes, write.KeyHash)
+		}
+	}
+	return w
+}
+
+func availableCollections(pvtData *rwset.TxPvtReadWriteSet) map[Collection]bool {
+	available := make(map[Collection]bool)
+	if pvtData == nil {
+		return available
+	}
+	for _, ns := range pvtData.NsPvtRwset {
+		for _, coll := range ns.CollectionPvtRwset {
+			available[Collection{Namespace: ns.Namespace, Name: coll.CollectionName}] = true
+		}
+	}
+	return available
+}
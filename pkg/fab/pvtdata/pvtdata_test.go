@@ -0,0 +1,323 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdata
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+const (
+	peerAddress = "localhost:9995"
+	channelID   = "mychannel"
+)
+
+var (
+	coll1 = Collection{Namespace: "cc1", Name: "coll1"}
+	coll2 = Collection{Namespace: "cc1", Name: "coll2"}
+)
+
+var deliverServer = &mockDeliverServer{}
+
+func TestMissing(t *testing.T) {
+	block := newBlock(t, 5, []pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT},
+		[]Collection{coll1, coll2}, []Collection{coll1}, []Collection{coll1})
+
+	b := &BlockAndPrivateData{
+		Block: block,
+		PrivateData: map[uint64]*rwset.TxPvtReadWriteSet{
+			0: newPvtData(coll1),
+		},
+	}
+
+	missing, err := Missing(b)
+	require.NoError(t, err)
+	// The private data of the invalid transaction isn't missing
+	assert.Equal(t, []MissingPrivateData{
+		{BlockNumber: 5, TxNum: 0, TxID: "tx0", Collection: coll2},
+		{BlockNumber: 5, TxNum: 1, TxID: "tx1", Collection: coll1},
+	}, missing)
+
+	missing, err = Missing(b, coll2)
+	require.NoError(t, err)
+	assert.Equal(t, []MissingPrivateData{{BlockNumber: 5, TxNum: 0, TxID: "tx0", Collection: coll2}}, missing)
+
+	_, err = Missing(&BlockAndPrivateData{})
+	assert.Error(t, err, "expecting error for incomplete block")
+}
+
+func TestPurgedPrivateData(t *testing.T) {
+	write := func(key string) *kvrwset.KVWriteHash {
+		return &kvrwset.KVWriteHash{KeyHash: KeyHash(key), ValueHash: []byte("value")}
+	}
+	purge := func(key string) *kvrwset.KVWriteHash {
+		return &kvrwset.KVWriteHash{KeyHash: KeyHash(key), IsDelete: true, IsPurge: true}
+	}
+
+	block1 := newBlock(t, 1, nil)
+	block1.Data.Data = [][]byte{
+		newTransactionWithWrites(t, "tx0", collWrites{coll: coll1, writes: []*kvrwset.KVWriteHash{write("a")}}, collWrites{coll: coll2, writes: []*kvrwset.KVWriteHash{write("a")}}),
+		newTransactionWithWrites(t, "tx1", collWrites{coll: coll1, writes: []*kvrwset.KVWriteHash{write("a"), write("b")}}),
+	}
+	block2 := newBlock(t, 2, nil)
+	block2.Data.Data = [][]byte{
+		newTransactionWithWrites(t, "tx2", collWrites{coll: coll1, writes: []*kvrwset.KVWriteHash{purge("a")}}),
+	}
+
+	purges, err := PurgeMarkers(block2)
+	require.NoError(t, err)
+	assert.Equal(t, []PurgeMarker{{BlockNumber: 2, TxNum: 0, TxID: "tx2", Collection: coll1, KeyHash: KeyHash("a")}}, purges)
+
+	noPurges, err := PurgeMarkers(block1)
+	require.NoError(t, err)
+	assert.Empty(t, noPurges)
+
+	_, err = PurgeMarkers(&common.Block{})
+	assert.Error(t, err, "expecting error for incomplete block")
+
+	missing1, err := missingWritesOf(&BlockAndPrivateData{Block: block1})
+	require.NoError(t, err)
+	missing2, err := missingWritesOf(&BlockAndPrivateData{Block: block2})
+	require.NoError(t, err)
+	assert.Empty(t, missing2, "expecting private data of purge transaction not to be reported")
+
+	// Key "a" of coll1 is purged but tx1 also wrote key "b"
+	assert.Equal(t, []MissingPrivateData{
+		{BlockNumber: 1, TxNum: 0, TxID: "tx0", Collection: coll2},
+		{BlockNumber: 1, TxNum: 1, TxID: "tx1", Collection: coll1},
+	}, excludePurged(append(missing1, missing2...), purges))
+
+	// Purges only apply to the private data written before
+	purges[0].BlockNumber = 1
+	purges[0].TxNum = 0
+	assert.Len(t, excludePurged(missing1, purges), 3)
+}
+
+func TestQueryMissing(t *testing.T) {
+	client := New(newMockContext())
+	target := fab.PeerConfig{
+		URL:         peerAddress,
+		GRPCOptions: map[string]interface{}{"allow-insecure": true},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	missing, err := client.QueryMissing(ctx, target, channelID, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []MissingPrivateData{{BlockNumber: 1, TxNum: 0, TxID: "tx0", Collection: coll2}}, missing)
+
+	blocks, err := client.Deliver(ctx, target, channelID, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	assert.EqualValues(t, 1, blocks[0].Block.Header.Number)
+	assert.NotNil(t, blocks[0].PrivateData[0])
+
+	_, err = client.QueryMissing(ctx, target, channelID, 0, 5)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NOT_FOUND")
+
+	_, err = client.QueryMissing(ctx, target, channelID, 2, 1)
+	assert.Error(t, err, "expecting error for invalid range")
+
+	_, err = client.QueryMissing(ctx, target, "", 0, 1)
+	assert.Error(t, err, "expecting error for missing channel ID")
+}
+
+func TestMain(m *testing.M) {
+	grpcServer := grpc.NewServer()
+	lis, err := net.Listen("tcp", peerAddress)
+	if err != nil {
+		panic(fmt.Sprintf("Error starting deliver listener %s", err))
+	}
+
+	grpcServer.RegisterService(&deliverServiceDesc, deliverServer)
+	go grpcServer.Serve(lis)
+
+	rc := m.Run()
+	grpcServer.Stop()
+	os.Exit(rc)
+}
+
+func newMockContext() *mocks.MockContext {
+	context := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "test"))
+	context.SetCustomInfraProvider(comm.NewMockInfraProvider())
+	return context
+}
+
+// mockDeliverServer delivers blocks 0 and 1. Block 1 contains a transaction which writes to coll1
+// and coll2 but only the private data of coll1 is available.
+type mockDeliverServer struct{}
+
+func (s *mockDeliverServer) deliver(stream grpc.ServerStream) error {
+	env := &common.Envelope{}
+	if err := stream.RecvMsg(env); err != nil {
+		return err
+	}
+	if len(env.Signature) == 0 {
+		return errors.New("missing signature")
+	}
+
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(env.Payload, payload); err != nil {
+		return err
+	}
+	seekInfo := &ab.SeekInfo{}
+	if err := proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		return err
+	}
+
+	from := seekInfo.Start.GetSpecified().GetNumber()
+	to := seekInfo.Stop.GetSpecified().GetNumber()
+	if to > 1 {
+		return stream.SendMsg(&deliverResponse{Status: common.Status_NOT_FOUND})
+	}
+
+	for n := from; n <= to; n++ {
+		var b *blockAndPrivateData
+		if n == 0 {
+			b = &blockAndPrivateData{Block: newBlock(nil, 0, nil)}
+		} else {
+			b = &blockAndPrivateData{
+				Block:          newBlock(nil, 1, nil, []Collection{coll1, coll2}),
+				PrivateDataMap: map[uint64]*rwset.TxPvtReadWriteSet{0: newPvtData(coll1)},
+			}
+		}
+		if err := stream.SendMsg(&deliverResponse{BlockAndPrivateData: b}); err != nil {
+			return err
+		}
+	}
+	return stream.SendMsg(&deliverResponse{Status: common.Status_SUCCESS})
+}
+
+var deliverServiceDesc = grpc.ServiceDesc{
+	ServiceName: "protos.Deliver",
+	HandlerType: (*interface{})(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: "DeliverWithPrivateData",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(*mockDeliverServer).deliver(stream)
+			},
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+}
+
+// newBlock creates a block with a transaction for each of the given lists of collections
+// to which the transaction writes
+func newBlock(t *testing.T, number uint64, codes []pb.TxValidationCode, txCollections ...[]Collection) *common.Block {
+	block := &common.Block{
+		Header:   &common.BlockHeader{Number: number},
+		Data:     &common.BlockData{},
+		Metadata: &common.BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))},
+	}
+
+	txFilter := ledgerutil.NewTxValidationFlags(len(txCollections))
+	for i, collections := range txCollections {
+		txFilter[i] = uint8(pb.TxValidationCode_VALID)
+		if i < len(codes) {
+			txFilter[i] = uint8(codes[i])
+		}
+		block.Data.Data = append(block.Data.Data, newTransaction(t, fmt.Sprintf("tx%d", i), collections))
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txFilter
+
+	return block
+}
+
+func newTransaction(t *testing.T, txID string, collections []Collection) []byte {
+	var writes []collWrites
+	for _, coll := range collections {
+		writes = append(writes, collWrites{coll: coll, writes: []*kvrwset.KVWriteHash{{KeyHash: []byte("key"), ValueHash: []byte("value")}}})
+	}
+	return newTransactionWithWrites(t, txID, writes...)
+}
+
+type collWrites struct {
+	coll   Collection
+	writes []*kvrwset.KVWriteHash
+}
+
+func newTransactionWithWrites(t *testing.T, txID string, collections ...collWrites) []byte {
+	nsRWSets := make(map[string]*rwsetutil.NsRwSet)
+	txRWSet := &rwsetutil.TxRwSet{}
+	for _, w := range collections {
+		coll := w.coll
+		ns, ok := nsRWSets[coll.Namespace]
+		if !ok {
+			ns = &rwsetutil.NsRwSet{NameSpace: coll.Namespace, KvRwSet: &kvrwset.KVRWSet{}}
+			nsRWSets[coll.Namespace] = ns
+			txRWSet.NsRwSets = append(txRWSet.NsRwSets, ns)
+		}
+		ns.CollHashedRwSets = append(ns.CollHashedRwSets, &rwsetutil.CollHashedRwSet{
+			CollectionName: coll.Name,
+			HashedRwSet:    &kvrwset.HashedRWSet{HashedWrites: w.writes},
+			PvtRwSetHash:   []byte("hash"),
+		})
+	}
+
+	results, err := txRWSet.ToProtoBytes()
+	mustNotFail(t, err)
+	ccAction := mustMarshal(t, &pb.ChaincodeAction{Results: results})
+	prp := mustMarshal(t, &pb.ProposalResponsePayload{Extension: ccAction})
+	ccActionPayload := mustMarshal(t, &pb.ChaincodeActionPayload{Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: prp}})
+	tx := mustMarshal(t, &pb.Transaction{Actions: []*pb.TransactionAction{{Payload: ccActionPayload}}})
+	chdr := mustMarshal(t, &common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION), ChannelId: channelID, TxId: txID})
+	payload := mustMarshal(t, &common.Payload{Header: &common.Header{ChannelHeader: chdr}, Data: tx})
+	return mustMarshal(t, &common.Envelope{Payload: payload, Signature: []byte("signature")})
+}
+
+func newPvtData(collections ...Collection) *rwset.TxPvtReadWriteSet {
+	pvtData := &rwset.TxPvtReadWriteSet{}
+	for _, coll := range collections {
+		pvtData.NsPvtRwset = append(pvtData.NsPvtRwset, &rwset.NsPvtReadWriteSet{
+			Namespace:          coll.Namespace,
+			CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{{CollectionName: coll.Name, Rwset: []byte("rwset")}},
+		})
+	}
+	return pvtData
+}
+
+func mustMarshal(t *testing.T, msg proto.Message) []byte {
+	bytes, err := proto.Marshal(msg)
+	mustNotFail(t, err)
+	return bytes
+}
+
+// mustNotFail fails the test (or panics if called by the mock server, in which case t is nil)
+func mustNotFail(t *testing.T, err error) {
+	if err == nil {
+		return
+	}
+	if t == nil {
+		panic(err)
+	}
+	t.Fatal(err)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/crypto"
 	fcutils "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/audit"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 )
 
@@ -47,6 +48,7 @@ func CreateConfigSignature(ctx context.Client, config []byte) (*common.ConfigSig
 	if err != nil {
 		return nil, errors.WithMessage(err, "signing of channel config failed")
 	}
+	audit.RecordSigned(ctx, audit.PurposeConfigSignature, signingBytes, "")
 
 	// build the return object
 	configSignature := common.ConfigSignature{
@@ -58,6 +60,24 @@ func CreateConfigSignature(ctx context.Client, config []byte) (*common.ConfigSig
 
 // ExtractChannelConfig extracts the protobuf 'ConfigUpdate' object out of the 'ConfigEnvelope'.
 func ExtractChannelConfig(configEnvelope []byte) ([]byte, error) {
+	configUpdateEnvelope, err := extractConfigUpdateEnvelope(configEnvelope)
+	if err != nil {
+		return nil, err
+	}
+	return configUpdateEnvelope.ConfigUpdate, nil
+}
+
+// ExtractConfigSignatures extracts the signatures of the 'ConfigUpdate' out of the 'ConfigEnvelope'
+// (e.g. the signatures collected by configtx.Editor.ConfigUpdateEnvelope).
+func ExtractConfigSignatures(configEnvelope []byte) ([]*common.ConfigSignature, error) {
+	configUpdateEnvelope, err := extractConfigUpdateEnvelope(configEnvelope)
+	if err != nil {
+		return nil, err
+	}
+	return configUpdateEnvelope.Signatures, nil
+}
+
+func extractConfigUpdateEnvelope(configEnvelope []byte) (*common.ConfigUpdateEnvelope, error) {
 
 	envelope := &common.Envelope{}
 	err := proto.Unmarshal(configEnvelope, envelope)
@@ -77,7 +97,7 @@ func ExtractChannelConfig(configEnvelope []byte) ([]byte, error) {
 		return nil, errors.Wrap(err, "unmarshal config update envelope")
 	}
 
-	return configUpdateEnvelope.ConfigUpdate, nil
+	return configUpdateEnvelope, nil
 }
 
 // CreateConfigEnvelope creates configuration envelope proto
@@ -24,6 +24,19 @@ func TestExtractChannelConfig(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	signatures, err := ExtractConfigSignatures(configTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signatures) != 0 {
+		t.Fatalf("expecting no signatures in unsigned channel config but got %d", len(signatures))
+	}
+
+	_, err = ExtractConfigSignatures([]byte("invalid"))
+	if err == nil {
+		t.Fatal("expecting error extracting signatures from invalid envelope")
+	}
 }
 
 func TestCreateConfigSignature(t *testing.T) {
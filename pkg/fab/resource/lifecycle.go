@@ -0,0 +1,615 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	reqContext "context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	lifecycleCC                           = "_lifecycle"
+	lifecycleInstallFcn                   = "InstallChaincode"
+	lifecycleApproveFcn                   = "ApproveChaincodeDefinitionForMyOrg"
+	lifecycleCommitFcn                    = "CommitChaincodeDefinition"
+	lifecycleQueryInstalledFcn            = "QueryInstalledChaincodes"
+	lifecycleQueryApprovedCCDefinitionFcn = "QueryApprovedChaincodeDefinition"
+	lifecycleCheckCommitReadinessFcn      = "CheckCommitReadiness"
+	lifecycleQueryCCDefinitionFcn         = "QueryChaincodeDefinition"
+	lifecycleQueryCCDefinitionsFcn        = "QueryChaincodeDefinitions"
+)
+
+// LifecycleChaincodeDefinition contains the parameters of a chaincode definition
+// which is approved by the organizations of a channel (Fabric 2.0 chaincode lifecycle)
+type LifecycleChaincodeDefinition struct {
+	Name                string
+	Version             string
+	Sequence            int64
+	EndorsementPlugin   string
+	ValidationPlugin    string
+	SignaturePolicy     *common.SignaturePolicyEnvelope
+	ChannelConfigPolicy string
+	CollectionConfig    []*common.CollectionConfig
+	InitRequired        bool
+	// ValidationParameter is the raw parameter of a custom validation plugin. It's used in place of the
+	// signature policy or channel config policy, which are only understood by the default (vscc) plugin.
+	ValidationParameter []byte
+}
+
+// LifecycleApprovedChaincodeDefinition contains the chaincode definition approved by an organization
+type LifecycleApprovedChaincodeDefinition struct {
+	LifecycleChaincodeDefinition
+
+	// PackageID is the ID of the chaincode package approved for the organization (empty if
+	// the definition was approved without a package)
+	PackageID string
+}
+
+// LifecycleCommittedChaincodeDefinition contains a chaincode definition which is committed to a channel
+type LifecycleCommittedChaincodeDefinition struct {
+	LifecycleChaincodeDefinition
+
+	// Approvals contains the approval status (per MSP ID) of the committed definition. It is only
+	// returned when querying the definition of a single chaincode.
+	Approvals map[string]bool
+}
+
+// LifecycleInstallChaincode installs the given chaincode package (as created by 'peer lifecycle chaincode package')
+// on the given peer and returns the ID of the installed package
+func LifecycleInstallChaincode(reqCtx reqContext.Context, pkg []byte, peer fab.ProposalProcessor, opts ...Opt) (string, error) {
+	if peer == nil {
+		return "", errors.New("peer required")
+	}
+	if len(pkg) == 0 {
+		return "", errors.New("chaincode package is required")
+	}
+
+	argsBytes, err := proto.Marshal(&installChaincodeArgs{ChaincodeInstallPackage: pkg})
+	if err != nil {
+		return "", errors.Wrap(err, "marshal of InstallChaincodeArgs failed")
+	}
+
+	cir := fab.ChaincodeInvokeRequest{
+		ChaincodeID: lifecycleCC,
+		Fcn:         lifecycleInstallFcn,
+		Args:        [][]byte{argsBytes},
+	}
+
+	payload, err := queryChaincodeWithTarget(reqCtx, cir, peer, getOpts(opts...))
+	if err != nil {
+		return "", errors.WithMessage(err, "_lifecycle.InstallChaincode failed")
+	}
+
+	result := &installChaincodeResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return "", errors.Wrap(err, "unmarshal InstallChaincodeResult failed")
+	}
+
+	return result.PackageId, nil
+}
+
+// LifecycleInstalledChaincode contains the ID and label of a chaincode package installed on a peer
+type LifecycleInstalledChaincode struct {
+	PackageID string
+	Label     string
+}
+
+// LifecycleQueryInstalledChaincodes queries the chaincode packages installed on the given peer
+func LifecycleQueryInstalledChaincodes(reqCtx reqContext.Context, peer fab.ProposalProcessor, opts ...Opt) ([]LifecycleInstalledChaincode, error) {
+	if peer == nil {
+		return nil, errors.New("peer required")
+	}
+
+	argsBytes, err := proto.Marshal(&queryInstalledChaincodesArgs{})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of QueryInstalledChaincodesArgs failed")
+	}
+
+	cir := fab.ChaincodeInvokeRequest{
+		ChaincodeID: lifecycleCC,
+		Fcn:         lifecycleQueryInstalledFcn,
+		Args:        [][]byte{argsBytes},
+	}
+
+	payload, err := queryChaincodeWithTarget(reqCtx, cir, peer, getOpts(opts...))
+	if err != nil {
+		return nil, errors.WithMessage(err, "_lifecycle.QueryInstalledChaincodes failed")
+	}
+
+	result := &queryInstalledChaincodesResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return nil, errors.Wrap(err, "unmarshal QueryInstalledChaincodesResult failed")
+	}
+
+	var installed []LifecycleInstalledChaincode
+	for _, cc := range result.InstalledChaincodes {
+		installed = append(installed, LifecycleInstalledChaincode{PackageID: cc.PackageId, Label: cc.Label})
+	}
+	return installed, nil
+}
+
+// CreateLifecycleApproveProposal creates a proposal which approves the given chaincode definition (and package)
+// for the organization of the endorsing peers
+func CreateLifecycleApproveProposal(txh fab.TransactionHeader, def LifecycleChaincodeDefinition, packageID string) (*fab.TransactionProposal, error) {
+	args, err := newCommitReadinessArgs(def)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &chaincodeSource{Unavailable: &chaincodeSourceUnavailable{}}
+	if packageID != "" {
+		source = &chaincodeSource{LocalPackage: &chaincodeSourceLocal{PackageId: packageID}}
+	}
+
+	argsBytes, err := proto.Marshal(&approveChaincodeDefinitionForMyOrgArgs{
+		Sequence:            args.Sequence,
+		Name:                args.Name,
+		Version:             args.Version,
+		EndorsementPlugin:   args.EndorsementPlugin,
+		ValidationPlugin:    args.ValidationPlugin,
+		ValidationParameter: args.ValidationParameter,
+		Collections:         args.Collections,
+		InitRequired:        args.InitRequired,
+		Source:              source,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of ApproveChaincodeDefinitionForMyOrgArgs failed")
+	}
+
+	return txn.CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{
+		ChaincodeID: lifecycleCC,
+		Fcn:         lifecycleApproveFcn,
+		Args:        [][]byte{argsBytes},
+	})
+}
+
+// CreateLifecycleCommitProposal creates a proposal which commits the given chaincode definition to the channel
+func CreateLifecycleCommitProposal(txh fab.TransactionHeader, def LifecycleChaincodeDefinition) (*fab.TransactionProposal, error) {
+	args, err := newCommitReadinessArgs(def)
+	if err != nil {
+		return nil, err
+	}
+
+	// CommitChaincodeDefinitionArgs has the same fields as CheckCommitReadinessArgs
+	argsBytes, err := proto.Marshal(args)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of CommitChaincodeDefinitionArgs failed")
+	}
+
+	return txn.CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{
+		ChaincodeID: lifecycleCC,
+		Fcn:         lifecycleCommitFcn,
+		Args:        [][]byte{argsBytes},
+	})
+}
+
+// QueryApprovedChaincodeDefinition queries the chaincode definition approved by the organization of the
+// given peer for the given sequence. If sequence is 0 then the latest approved definition is returned.
+func QueryApprovedChaincodeDefinition(reqCtx reqContext.Context, channelID, name string, sequence int64, peer fab.ProposalProcessor, opts ...Opt) (*LifecycleApprovedChaincodeDefinition, error) {
+	if peer == nil {
+		return nil, errors.New("peer required")
+	}
+
+	argsBytes, err := proto.Marshal(&queryApprovedChaincodeDefinitionArgs{Name: name, Sequence: sequence})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of QueryApprovedChaincodeDefinitionArgs failed")
+	}
+
+	cir := fab.ChaincodeInvokeRequest{
+		ChaincodeID: lifecycleCC,
+		Fcn:         lifecycleQueryApprovedCCDefinitionFcn,
+		Args:        [][]byte{argsBytes},
+	}
+
+	payload, err := queryChaincodeOnChannel(reqCtx, channelID, cir, peer, getOpts(opts...))
+	if err != nil {
+		return nil, errors.WithMessage(err, "_lifecycle.QueryApprovedChaincodeDefinition failed")
+	}
+
+	result := &queryApprovedChaincodeDefinitionResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return nil, errors.Wrap(err, "unmarshal QueryApprovedChaincodeDefinitionResult failed")
+	}
+
+	def, err := newLifecycleChaincodeDefinition(name, result.Sequence, result.Version, result.EndorsementPlugin, result.ValidationPlugin, result.ValidationParameter, result.Collections, result.InitRequired)
+	if err != nil {
+		return nil, err
+	}
+
+	approved := &LifecycleApprovedChaincodeDefinition{LifecycleChaincodeDefinition: *def}
+	if result.Source != nil && result.Source.LocalPackage != nil {
+		approved.PackageID = result.Source.LocalPackage.PackageId
+	}
+
+	return approved, nil
+}
+
+// CheckCommitReadiness returns the approval status (per MSP ID) of the given chaincode definition
+// by the organizations of the channel
+func CheckCommitReadiness(reqCtx reqContext.Context, channelID string, def LifecycleChaincodeDefinition, peer fab.ProposalProcessor, opts ...Opt) (map[string]bool, error) {
+	if peer == nil {
+		return nil, errors.New("peer required")
+	}
+
+	args, err := newCommitReadinessArgs(def)
+	if err != nil {
+		return nil, err
+	}
+
+	argsBytes, err := proto.Marshal(args)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of CheckCommitReadinessArgs failed")
+	}
+
+	cir := fab.ChaincodeInvokeRequest{
+		ChaincodeID: lifecycleCC,
+		Fcn:         lifecycleCheckCommitReadinessFcn,
+		Args:        [][]byte{argsBytes},
+	}
+
+	payload, err := queryChaincodeOnChannel(reqCtx, channelID, cir, peer, getOpts(opts...))
+	if err != nil {
+		return nil, errors.WithMessage(err, "_lifecycle.CheckCommitReadiness failed")
+	}
+
+	result := &checkCommitReadinessResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return nil, errors.Wrap(err, "unmarshal CheckCommitReadinessResult failed")
+	}
+
+	return result.Approvals, nil
+}
+
+// QueryCommittedChaincodeDefinition queries the definition of the given chaincode which is committed to the channel
+func QueryCommittedChaincodeDefinition(reqCtx reqContext.Context, channelID, name string, peer fab.ProposalProcessor, opts ...Opt) (*LifecycleCommittedChaincodeDefinition, error) {
+	if peer == nil {
+		return nil, errors.New("peer required")
+	}
+
+	argsBytes, err := proto.Marshal(&queryChaincodeDefinitionArgs{Name: name})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of QueryChaincodeDefinitionArgs failed")
+	}
+
+	cir := fab.ChaincodeInvokeRequest{
+		ChaincodeID: lifecycleCC,
+		Fcn:         lifecycleQueryCCDefinitionFcn,
+		Args:        [][]byte{argsBytes},
+	}
+
+	payload, err := queryChaincodeOnChannel(reqCtx, channelID, cir, peer, getOpts(opts...))
+	if err != nil {
+		return nil, errors.WithMessage(err, "_lifecycle.QueryChaincodeDefinition failed")
+	}
+
+	result := &queryChaincodeDefinitionResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return nil, errors.Wrap(err, "unmarshal QueryChaincodeDefinitionResult failed")
+	}
+
+	def, err := newLifecycleChaincodeDefinition(name, result.Sequence, result.Version, result.EndorsementPlugin, result.ValidationPlugin, result.ValidationParameter, result.Collections, result.InitRequired)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LifecycleCommittedChaincodeDefinition{LifecycleChaincodeDefinition: *def, Approvals: result.Approvals}, nil
+}
+
+// QueryCommittedChaincodeDefinitions queries the definitions of all chaincodes which are committed to the channel
+func QueryCommittedChaincodeDefinitions(reqCtx reqContext.Context, channelID string, peer fab.ProposalProcessor, opts ...Opt) ([]LifecycleCommittedChaincodeDefinition, error) {
+	if peer == nil {
+		return nil, errors.New("peer required")
+	}
+
+	argsBytes, err := proto.Marshal(&queryChaincodeDefinitionsArgs{})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of QueryChaincodeDefinitionsArgs failed")
+	}
+
+	cir := fab.ChaincodeInvokeRequest{
+		ChaincodeID: lifecycleCC,
+		Fcn:         lifecycleQueryCCDefinitionsFcn,
+		Args:        [][]byte{argsBytes},
+	}
+
+	payload, err := queryChaincodeOnChannel(reqCtx, channelID, cir, peer, getOpts(opts...))
+	if err != nil {
+		return nil, errors.WithMessage(err, "_lifecycle.QueryChaincodeDefinitions failed")
+	}
+
+	result := &queryChaincodeDefinitionsResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return nil, errors.Wrap(err, "unmarshal QueryChaincodeDefinitionsResult failed")
+	}
+
+	var committed []LifecycleCommittedChaincodeDefinition
+	for _, cc := range result.ChaincodeDefinitions {
+		def, err := newLifecycleChaincodeDefinition(cc.Name, cc.Sequence, cc.Version, cc.EndorsementPlugin, cc.ValidationPlugin, cc.ValidationParameter, cc.Collections, cc.InitRequired)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid definition of chaincode "+cc.Name)
+		}
+		committed = append(committed, LifecycleCommittedChaincodeDefinition{LifecycleChaincodeDefinition: *def})
+	}
+	return committed, nil
+}
+
+func newLifecycleChaincodeDefinition(name string, sequence int64, version, endorsementPlugin, validationPlugin string, validationParameter []byte, collections *common.CollectionConfigPackage, initRequired bool) (*LifecycleChaincodeDefinition, error) {
+	def := &LifecycleChaincodeDefinition{
+		Name:              name,
+		Version:           version,
+		Sequence:          sequence,
+		EndorsementPlugin: endorsementPlugin,
+		ValidationPlugin:  validationPlugin,
+		InitRequired:      initRequired,
+	}
+
+	policy := &applicationPolicy{}
+	if err := proto.Unmarshal(validationParameter, policy); err == nil && (policy.SignaturePolicy != nil || policy.ChannelConfigPolicyReference != "") {
+		def.SignaturePolicy = policy.SignaturePolicy
+		def.ChannelConfigPolicy = policy.ChannelConfigPolicyReference
+	} else if len(validationParameter) > 0 {
+		// Not an application policy, so it's the parameter of a custom validation plugin
+		def.ValidationParameter = validationParameter
+	}
+
+	if collections != nil {
+		def.CollectionConfig = collections.Config
+	}
+	return def, nil
+}
+
+func newCommitReadinessArgs(def LifecycleChaincodeDefinition) (*checkCommitReadinessArgs, error) {
+	validationParameter, err := lifecycleValidationParameter(def)
+	if err != nil {
+		return nil, err
+	}
+
+	args := &checkCommitReadinessArgs{
+		Sequence:            def.Sequence,
+		Name:                def.Name,
+		Version:             def.Version,
+		EndorsementPlugin:   def.EndorsementPlugin,
+		ValidationPlugin:    def.ValidationPlugin,
+		ValidationParameter: validationParameter,
+		InitRequired:        def.InitRequired,
+	}
+	if len(def.CollectionConfig) > 0 {
+		args.Collections = &common.CollectionConfigPackage{Config: def.CollectionConfig}
+	}
+	return args, nil
+}
+
+func lifecycleValidationParameter(def LifecycleChaincodeDefinition) ([]byte, error) {
+	policies := 0
+	if def.SignaturePolicy != nil {
+		policies++
+	}
+	if def.ChannelConfigPolicy != "" {
+		policies++
+	}
+	if def.ValidationParameter != nil {
+		policies++
+	}
+	if policies > 1 {
+		return nil, errors.New("only one of signature policy, channel config policy and validation parameter may be specified")
+	}
+
+	if def.ValidationParameter != nil {
+		return def.ValidationParameter, nil
+	}
+
+	validationParameter, err := proto.Marshal(&applicationPolicy{SignaturePolicy: def.SignaturePolicy, ChannelConfigPolicyReference: def.ChannelConfigPolicy})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of ApplicationPolicy failed")
+	}
+	return validationParameter, nil
+}
+
+// The messages below are defined by the _lifecycle system chaincode of Fabric 2.0 peers
+// (peer/lifecycle/lifecycle.proto), which is not included in the Fabric protos vendored by the SDK.
+
+type installChaincodeArgs struct {
+	ChaincodeInstallPackage []byte `protobuf:"bytes,1,opt,name=chaincode_install_package,json=chaincodeInstallPackage,proto3" json:"chaincode_install_package,omitempty"`
+}
+
+func (m *installChaincodeArgs) Reset()         { *m = installChaincodeArgs{} }
+func (m *installChaincodeArgs) String() string { return proto.CompactTextString(m) }
+func (*installChaincodeArgs) ProtoMessage()    {}
+
+type installChaincodeResult struct {
+	PackageId string `protobuf:"bytes,1,opt,name=package_id,json=packageId" json:"package_id,omitempty"`
+	Label     string `protobuf:"bytes,2,opt,name=label" json:"label,omitempty"`
+}
+
+func (m *installChaincodeResult) Reset()         { *m = installChaincodeResult{} }
+func (m *installChaincodeResult) String() string { return proto.CompactTextString(m) }
+func (*installChaincodeResult) ProtoMessage()    {}
+
+type approveChaincodeDefinitionForMyOrgArgs struct {
+	Sequence            int64                           `protobuf:"varint,1,opt,name=sequence" json:"sequence,omitempty"`
+	Name                string                          `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Version             string                          `protobuf:"bytes,3,opt,name=version" json:"version,omitempty"`
+	EndorsementPlugin   string                          `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin    string                          `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin" json:"validation_plugin,omitempty"`
+	ValidationParameter []byte                          `protobuf:"bytes,6,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections         *common.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections" json:"collections,omitempty"`
+	InitRequired        bool                            `protobuf:"varint,8,opt,name=init_required,json=initRequired" json:"init_required,omitempty"`
+	Source              *chaincodeSource                `protobuf:"bytes,9,opt,name=source" json:"source,omitempty"`
+}
+
+func (m *approveChaincodeDefinitionForMyOrgArgs) Reset() {
+	*m = approveChaincodeDefinitionForMyOrgArgs{}
+}
+func (m *approveChaincodeDefinitionForMyOrgArgs) String() string { return proto.CompactTextString(m) }
+func (*approveChaincodeDefinitionForMyOrgArgs) ProtoMessage()    {}
+
+type queryInstalledChaincodesArgs struct{}
+
+func (m *queryInstalledChaincodesArgs) Reset()         { *m = queryInstalledChaincodesArgs{} }
+func (m *queryInstalledChaincodesArgs) String() string { return proto.CompactTextString(m) }
+func (*queryInstalledChaincodesArgs) ProtoMessage()    {}
+
+type queryInstalledChaincodesResult struct {
+	InstalledChaincodes []*installedChaincode `protobuf:"bytes,1,rep,name=installed_chaincodes,json=installedChaincodes" json:"installed_chaincodes,omitempty"`
+}
+
+func (m *queryInstalledChaincodesResult) Reset()         { *m = queryInstalledChaincodesResult{} }
+func (m *queryInstalledChaincodesResult) String() string { return proto.CompactTextString(m) }
+func (*queryInstalledChaincodesResult) ProtoMessage()    {}
+
+// installedChaincode omits the references (field 3) to the chaincode definitions using the package
+type installedChaincode struct {
+	PackageId string `protobuf:"bytes,1,opt,name=package_id,json=packageId" json:"package_id,omitempty"`
+	Label     string `protobuf:"bytes,2,opt,name=label" json:"label,omitempty"`
+}
+
+func (m *installedChaincode) Reset()         { *m = installedChaincode{} }
+func (m *installedChaincode) String() string { return proto.CompactTextString(m) }
+func (*installedChaincode) ProtoMessage()    {}
+
+type queryApprovedChaincodeDefinitionArgs struct {
+	Name     string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Sequence int64  `protobuf:"varint,2,opt,name=sequence" json:"sequence,omitempty"`
+}
+
+func (m *queryApprovedChaincodeDefinitionArgs) Reset()         { *m = queryApprovedChaincodeDefinitionArgs{} }
+func (m *queryApprovedChaincodeDefinitionArgs) String() string { return proto.CompactTextString(m) }
+func (*queryApprovedChaincodeDefinitionArgs) ProtoMessage()    {}
+
+type queryApprovedChaincodeDefinitionResult struct {
+	Sequence            int64                           `protobuf:"varint,1,opt,name=sequence" json:"sequence,omitempty"`
+	Version             string                          `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
+	EndorsementPlugin   string                          `protobuf:"bytes,3,opt,name=endorsement_plugin,json=endorsementPlugin" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin    string                          `protobuf:"bytes,4,opt,name=validation_plugin,json=validationPlugin" json:"validation_plugin,omitempty"`
+	ValidationParameter []byte                          `protobuf:"bytes,5,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections         *common.CollectionConfigPackage `protobuf:"bytes,6,opt,name=collections" json:"collections,omitempty"`
+	InitRequired        bool                            `protobuf:"varint,7,opt,name=init_required,json=initRequired" json:"init_required,omitempty"`
+	Source              *chaincodeSource                `protobuf:"bytes,8,opt,name=source" json:"source,omitempty"`
+}
+
+func (m *queryApprovedChaincodeDefinitionResult) Reset() {
+	*m = queryApprovedChaincodeDefinitionResult{}
+}
+func (m *queryApprovedChaincodeDefinitionResult) String() string { return proto.CompactTextString(m) }
+func (*queryApprovedChaincodeDefinitionResult) ProtoMessage()    {}
+
+// chaincodeSource is a oneof (unavailable or local_package) in lifecycle.proto and is
+// encoded the same way as two optional fields
+type chaincodeSource struct {
+	Unavailable  *chaincodeSourceUnavailable `protobuf:"bytes,1,opt,name=unavailable" json:"unavailable,omitempty"`
+	LocalPackage *chaincodeSourceLocal       `protobuf:"bytes,2,opt,name=local_package,json=localPackage" json:"local_package,omitempty"`
+}
+
+func (m *chaincodeSource) Reset()         { *m = chaincodeSource{} }
+func (m *chaincodeSource) String() string { return proto.CompactTextString(m) }
+func (*chaincodeSource) ProtoMessage()    {}
+
+type chaincodeSourceUnavailable struct{}
+
+func (m *chaincodeSourceUnavailable) Reset()         { *m = chaincodeSourceUnavailable{} }
+func (m *chaincodeSourceUnavailable) String() string { return proto.CompactTextString(m) }
+func (*chaincodeSourceUnavailable) ProtoMessage()    {}
+
+type chaincodeSourceLocal struct {
+	PackageId string `protobuf:"bytes,1,opt,name=package_id,json=packageId" json:"package_id,omitempty"`
+}
+
+func (m *chaincodeSourceLocal) Reset()         { *m = chaincodeSourceLocal{} }
+func (m *chaincodeSourceLocal) String() string { return proto.CompactTextString(m) }
+func (*chaincodeSourceLocal) ProtoMessage()    {}
+
+type checkCommitReadinessArgs struct {
+	Sequence            int64                           `protobuf:"varint,1,opt,name=sequence" json:"sequence,omitempty"`
+	Name                string                          `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Version             string                          `protobuf:"bytes,3,opt,name=version" json:"version,omitempty"`
+	EndorsementPlugin   string                          `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin    string                          `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin" json:"validation_plugin,omitempty"`
+	ValidationParameter []byte                          `protobuf:"bytes,6,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections         *common.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections" json:"collections,omitempty"`
+	InitRequired        bool                            `protobuf:"varint,8,opt,name=init_required,json=initRequired" json:"init_required,omitempty"`
+}
+
+func (m *checkCommitReadinessArgs) Reset()         { *m = checkCommitReadinessArgs{} }
+func (m *checkCommitReadinessArgs) String() string { return proto.CompactTextString(m) }
+func (*checkCommitReadinessArgs) ProtoMessage()    {}
+
+type checkCommitReadinessResult struct {
+	Approvals map[string]bool `protobuf:"bytes,1,rep,name=approvals" json:"approvals,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+}
+
+func (m *checkCommitReadinessResult) Reset()         { *m = checkCommitReadinessResult{} }
+func (m *checkCommitReadinessResult) String() string { return proto.CompactTextString(m) }
+func (*checkCommitReadinessResult) ProtoMessage()    {}
+
+// applicationPolicy is a oneof (signature_policy or channel_config_policy_reference) in
+// peer/policy.proto and is encoded the same way as two optional fields
+type applicationPolicy struct {
+	SignaturePolicy              *common.SignaturePolicyEnvelope `protobuf:"bytes,1,opt,name=signature_policy,json=signaturePolicy" json:"signature_policy,omitempty"`
+	ChannelConfigPolicyReference string                          `protobuf:"bytes,2,opt,name=channel_config_policy_reference,json=channelConfigPolicyReference" json:"channel_config_policy_reference,omitempty"`
+}
+
+func (m *applicationPolicy) Reset()         { *m = applicationPolicy{} }
+func (m *applicationPolicy) String() string { return proto.CompactTextString(m) }
+func (*applicationPolicy) ProtoMessage()    {}
+
+type queryChaincodeDefinitionArgs struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *queryChaincodeDefinitionArgs) Reset()         { *m = queryChaincodeDefinitionArgs{} }
+func (m *queryChaincodeDefinitionArgs) String() string { return proto.CompactTextString(m) }
+func (*queryChaincodeDefinitionArgs) ProtoMessage()    {}
+
+type queryChaincodeDefinitionResult struct {
+	Sequence            int64                           `protobuf:"varint,1,opt,name=sequence" json:"sequence,omitempty"`
+	Version             string                          `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
+	EndorsementPlugin   string                          `protobuf:"bytes,3,opt,name=endorsement_plugin,json=endorsementPlugin" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin    string                          `protobuf:"bytes,4,opt,name=validation_plugin,json=validationPlugin" json:"validation_plugin,omitempty"`
+	ValidationParameter []byte                          `protobuf:"bytes,5,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections         *common.CollectionConfigPackage `protobuf:"bytes,6,opt,name=collections" json:"collections,omitempty"`
+	InitRequired        bool                            `protobuf:"varint,7,opt,name=init_required,json=initRequired" json:"init_required,omitempty"`
+	Approvals           map[string]bool                 `protobuf:"bytes,8,rep,name=approvals" json:"approvals,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+}
+
+func (m *queryChaincodeDefinitionResult) Reset()         { *m = queryChaincodeDefinitionResult{} }
+func (m *queryChaincodeDefinitionResult) String() string { return proto.CompactTextString(m) }
+func (*queryChaincodeDefinitionResult) ProtoMessage()    {}
+
+type queryChaincodeDefinitionsArgs struct{}
+
+func (m *queryChaincodeDefinitionsArgs) Reset()         { *m = queryChaincodeDefinitionsArgs{} }
+func (m *queryChaincodeDefinitionsArgs) String() string { return proto.CompactTextString(m) }
+func (*queryChaincodeDefinitionsArgs) ProtoMessage()    {}
+
+type queryChaincodeDefinitionsResult struct {
+	ChaincodeDefinitions []*committedChaincodeDefinition `protobuf:"bytes,1,rep,name=chaincode_definitions,json=chaincodeDefinitions" json:"chaincode_definitions,omitempty"`
+}
+
+func (m *queryChaincodeDefinitionsResult) Reset()         { *m = queryChaincodeDefinitionsResult{} }
+func (m *queryChaincodeDefinitionsResult) String() string { return proto.CompactTextString(m) }
+func (*queryChaincodeDefinitionsResult) ProtoMessage()    {}
+
+// committedChaincodeDefinition is QueryChaincodeDefinitionsResult.ChaincodeDefinition in lifecycle.proto
+type committedChaincodeDefinition struct {
+	Name                string                          `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Sequence            int64                           `protobuf:"varint,2,opt,name=sequence" json:"sequence,omitempty"`
+	Version             string                          `protobuf:"bytes,3,opt,name=version" json:"version,omitempty"`
+	EndorsementPlugin   string                          `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin    string                          `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin" json:"validation_plugin,omitempty"`
+	ValidationParameter []byte                          `protobuf:"bytes,6,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections         *common.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections" json:"collections,omitempty"`
+	InitRequired        bool                            `protobuf:"varint,8,opt,name=init_required,json=initRequired" json:"init_required,omitempty"`
+}
+
+func (m *committedChaincodeDefinition) Reset()         { *m = committedChaincodeDefinition{} }
+func (m *committedChaincodeDefinition) String() string { return proto.CompactTextString(m) }
+func (*committedChaincodeDefinition) ProtoMessage()    {}
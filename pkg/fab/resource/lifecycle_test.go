@@ -0,0 +1,213 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryApprovedChaincodeDefinition(t *testing.T) {
+	ctx := setupContext()
+	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeout(10*time.Second))
+	defer cancel()
+
+	sigPolicy := &common.SignaturePolicyEnvelope{Version: 0}
+	policyBytes, err := proto.Marshal(&applicationPolicy{SignaturePolicy: sigPolicy})
+	require.NoError(t, err)
+
+	payload, err := proto.Marshal(&queryApprovedChaincodeDefinitionResult{
+		Sequence:            2,
+		Version:             "v2",
+		EndorsementPlugin:   "escc",
+		ValidationPlugin:    "vscc",
+		ValidationParameter: policyBytes,
+		InitRequired:        true,
+		Source:              &chaincodeSource{LocalPackage: &chaincodeSourceLocal{PackageId: "examplecc:1234"}},
+	})
+	require.NoError(t, err)
+
+	peer := &mocks.MockPeer{MockName: "Peer1", MockURL: "peer1.example.com", Payload: payload, Status: 200}
+
+	approved, err := QueryApprovedChaincodeDefinition(reqCtx, "mychannel", "examplecc", 2, peer)
+	require.NoError(t, err)
+	assert.Equal(t, "examplecc", approved.Name)
+	assert.Equal(t, "v2", approved.Version)
+	assert.Equal(t, int64(2), approved.Sequence)
+	assert.Equal(t, "escc", approved.EndorsementPlugin)
+	assert.Equal(t, "vscc", approved.ValidationPlugin)
+	assert.True(t, proto.Equal(sigPolicy, approved.SignaturePolicy))
+	assert.Empty(t, approved.ChannelConfigPolicy)
+	assert.True(t, approved.InitRequired)
+	assert.Equal(t, "examplecc:1234", approved.PackageID)
+
+	_, err = QueryApprovedChaincodeDefinition(reqCtx, "mychannel", "examplecc", 2, nil)
+	assert.Error(t, err)
+
+	peer.Status = 500
+	_, err = QueryApprovedChaincodeDefinition(reqCtx, "mychannel", "examplecc", 2, peer)
+	assert.Error(t, err)
+}
+
+func TestQueryCommittedChaincodeDefinition(t *testing.T) {
+	ctx := setupContext()
+	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeout(10*time.Second))
+	defer cancel()
+
+	policyBytes, err := proto.Marshal(&applicationPolicy{ChannelConfigPolicyReference: "/Channel/Application/Endorsement"})
+	require.NoError(t, err)
+
+	collections := &common.CollectionConfigPackage{Config: []*common.CollectionConfig{{}}}
+	payload, err := proto.Marshal(&queryChaincodeDefinitionResult{
+		Sequence:            3,
+		Version:             "v3",
+		EndorsementPlugin:   "escc",
+		ValidationPlugin:    "vscc",
+		ValidationParameter: policyBytes,
+		Collections:         collections,
+		InitRequired:        true,
+		Approvals:           map[string]bool{"Org1MSP": true, "Org2MSP": false},
+	})
+	require.NoError(t, err)
+
+	peer := &mocks.MockPeer{MockName: "Peer1", MockURL: "peer1.example.com", Payload: payload, Status: 200}
+
+	committed, err := QueryCommittedChaincodeDefinition(reqCtx, "mychannel", "examplecc", peer)
+	require.NoError(t, err)
+	assert.Equal(t, "examplecc", committed.Name)
+	assert.Equal(t, "v3", committed.Version)
+	assert.Equal(t, int64(3), committed.Sequence)
+	assert.Equal(t, "escc", committed.EndorsementPlugin)
+	assert.Equal(t, "vscc", committed.ValidationPlugin)
+	assert.Nil(t, committed.SignaturePolicy)
+	assert.Equal(t, "/Channel/Application/Endorsement", committed.ChannelConfigPolicy)
+	assert.Len(t, committed.CollectionConfig, 1)
+	assert.True(t, committed.InitRequired)
+	assert.Equal(t, map[string]bool{"Org1MSP": true, "Org2MSP": false}, committed.Approvals)
+
+	_, err = QueryCommittedChaincodeDefinition(reqCtx, "mychannel", "examplecc", nil)
+	assert.Error(t, err)
+
+	peer.Status = 500
+	_, err = QueryCommittedChaincodeDefinition(reqCtx, "mychannel", "examplecc", peer)
+	assert.Error(t, err)
+}
+
+func TestQueryCommittedChaincodeDefinitions(t *testing.T) {
+	ctx := setupContext()
+	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeout(10*time.Second))
+	defer cancel()
+
+	sigPolicy := &common.SignaturePolicyEnvelope{Version: 0}
+	policyBytes, err := proto.Marshal(&applicationPolicy{SignaturePolicy: sigPolicy})
+	require.NoError(t, err)
+
+	payload, err := proto.Marshal(&queryChaincodeDefinitionsResult{
+		ChaincodeDefinitions: []*committedChaincodeDefinition{
+			{Name: "cc1", Sequence: 1, Version: "v1", EndorsementPlugin: "escc", ValidationPlugin: "vscc", ValidationParameter: policyBytes},
+			{Name: "cc2", Sequence: 2, Version: "v2", EndorsementPlugin: "escc", ValidationPlugin: "vscc", ValidationParameter: policyBytes, InitRequired: true},
+		},
+	})
+	require.NoError(t, err)
+
+	peer := &mocks.MockPeer{MockName: "Peer1", MockURL: "peer1.example.com", Payload: payload, Status: 200}
+
+	committed, err := QueryCommittedChaincodeDefinitions(reqCtx, "mychannel", peer)
+	require.NoError(t, err)
+	require.Len(t, committed, 2)
+	assert.Equal(t, "cc1", committed[0].Name)
+	assert.Equal(t, int64(1), committed[0].Sequence)
+	assert.False(t, committed[0].InitRequired)
+	assert.True(t, proto.Equal(sigPolicy, committed[0].SignaturePolicy))
+	assert.Equal(t, "cc2", committed[1].Name)
+	assert.Equal(t, "v2", committed[1].Version)
+	assert.True(t, committed[1].InitRequired)
+	assert.Nil(t, committed[1].Approvals)
+
+	_, err = QueryCommittedChaincodeDefinitions(reqCtx, "mychannel", nil)
+	assert.Error(t, err)
+}
+
+func TestCheckCommitReadiness(t *testing.T) {
+	ctx := setupContext()
+	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeout(10*time.Second))
+	defer cancel()
+
+	payload, err := proto.Marshal(&checkCommitReadinessResult{Approvals: map[string]bool{"Org1MSP": true, "Org2MSP": false}})
+	require.NoError(t, err)
+
+	peer := &mocks.MockPeer{MockName: "Peer1", MockURL: "peer1.example.com", Payload: payload, Status: 200}
+
+	def := LifecycleChaincodeDefinition{Name: "examplecc", Version: "v1", Sequence: 1, ChannelConfigPolicy: "/Channel/Application/Endorsement"}
+	approvals, err := CheckCommitReadiness(reqCtx, "mychannel", def, peer)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"Org1MSP": true, "Org2MSP": false}, approvals)
+
+	def.SignaturePolicy = &common.SignaturePolicyEnvelope{}
+	_, err = CheckCommitReadiness(reqCtx, "mychannel", def, peer)
+	assert.Error(t, err, "expecting error since both signature policy and channel config policy are specified")
+
+	def.SignaturePolicy = nil
+	def.ValidationParameter = []byte("params")
+	_, err = CheckCommitReadiness(reqCtx, "mychannel", def, peer)
+	assert.Error(t, err, "expecting error since both channel config policy and validation parameter are specified")
+}
+
+func TestLifecycleCustomValidationPlugin(t *testing.T) {
+	def := LifecycleChaincodeDefinition{
+		Name:                "tokencc",
+		Version:             "v1",
+		Sequence:            1,
+		EndorsementPlugin:   "tokenescc",
+		ValidationPlugin:    "tokenvscc",
+		ValidationParameter: []byte("params"),
+	}
+
+	args, err := newCommitReadinessArgs(def)
+	require.NoError(t, err)
+	assert.Equal(t, "tokenescc", args.EndorsementPlugin)
+	assert.Equal(t, "tokenvscc", args.ValidationPlugin)
+	assert.Equal(t, []byte("params"), args.ValidationParameter)
+
+	decoded, err := newLifecycleChaincodeDefinition(args.Name, args.Sequence, args.Version, args.EndorsementPlugin, args.ValidationPlugin, args.ValidationParameter, args.Collections, args.InitRequired)
+	require.NoError(t, err)
+	assert.Equal(t, def, *decoded)
+}
+
+func TestLifecycleInstallAndQueryInstalled(t *testing.T) {
+	ctx := setupContext()
+	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeout(10*time.Second))
+	defer cancel()
+
+	payload, err := proto.Marshal(&installChaincodeResult{PackageId: "examplecc_1:abcd", Label: "examplecc_1"})
+	require.NoError(t, err)
+
+	peer := &mocks.MockPeer{MockName: "Peer1", MockURL: "peer1.example.com", Payload: payload, Status: 200}
+
+	packageID, err := LifecycleInstallChaincode(reqCtx, []byte("package"), peer)
+	require.NoError(t, err)
+	assert.Equal(t, "examplecc_1:abcd", packageID)
+
+	_, err = LifecycleInstallChaincode(reqCtx, nil, peer)
+	assert.Error(t, err, "expecting error for missing package")
+
+	peer.Payload, err = proto.Marshal(&queryInstalledChaincodesResult{
+		InstalledChaincodes: []*installedChaincode{{PackageId: "examplecc_1:abcd", Label: "examplecc_1"}},
+	})
+	require.NoError(t, err)
+
+	installed, err := LifecycleQueryInstalledChaincodes(reqCtx, peer)
+	require.NoError(t, err)
+	assert.Equal(t, []LifecycleInstalledChaincode{{PackageID: "examplecc_1:abcd", Label: "examplecc_1"}}, installed)
+}
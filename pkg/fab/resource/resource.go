@@ -137,6 +137,19 @@ func GenesisBlockFromOrderer(reqCtx reqContext.Context, channelName string, orde
 	return retrieveBlock(reqCtx, []fab.Orderer{orderer}, channelName, newSpecificSeekPosition(0), optionsValue)
 }
 
+// NewestBlockFromOrderer returns the newest block of the channel from the given orderer
+func NewestBlockFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer, opts ...Opt) (*common.Block, error) {
+	optionsValue := getOpts(opts...)
+	return retrieveBlock(reqCtx, []fab.Orderer{orderer}, channelName, newNewestSeekPosition(), optionsValue)
+}
+
+// BlockFromOrderer returns the block with the given number from the given orderer. The orderer waits
+// for the block if it hasn't been cut yet so the request context should have a timeout.
+func BlockFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer, blockNumber uint64, opts ...Opt) (*common.Block, error) {
+	optionsValue := getOpts(opts...)
+	return retrieveBlock(reqCtx, []fab.Orderer{orderer}, channelName, newSpecificSeekPosition(blockNumber), optionsValue)
+}
+
 // LastConfigFromOrderer fetches the current configuration block for the specified channel
 // from the given orderer
 func LastConfigFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer, opts ...Opt) (*common.Block, error) {
@@ -200,7 +213,7 @@ func JoinChannel(reqCtx reqContext.Context, request JoinChannelRequest, targets
 			defer wg.Done()
 			if _, err := queryChaincodeWithTarget(reqCtx, cir, target, optionsValue); err != nil {
 				mutex.Lock()
-				errors1 = append(errors1, err)
+				errors1 = append(errors1, txn.NewTargetError(target, err))
 				mutex.Unlock()
 			}
 		}()
@@ -371,6 +384,10 @@ func InstallChaincode(reqCtx reqContext.Context, req InstallChaincodeRequest, ta
 }
 
 func queryChaincodeWithTarget(reqCtx reqContext.Context, request fab.ChaincodeInvokeRequest, target fab.ProposalProcessor, opts options) ([]byte, error) {
+	return queryChaincodeOnChannel(reqCtx, fab.SystemChannel, request, target, opts)
+}
+
+func queryChaincodeOnChannel(reqCtx reqContext.Context, channelID string, request fab.ChaincodeInvokeRequest, target fab.ProposalProcessor, opts options) ([]byte, error) {
 
 	targets := []fab.ProposalProcessor{target}
 
@@ -379,7 +396,7 @@ func queryChaincodeWithTarget(reqCtx reqContext.Context, request fab.ChaincodeIn
 		return nil, errors.New("failed get client context from reqContext for txn header")
 	}
 
-	txh, err := txn.NewHeader(ctx, fab.SystemChannel)
+	txh, err := txn.NewHeader(ctx, channelID)
 	if err != nil {
 		return nil, errors.WithMessage(err, "create transaction ID failed")
 	}
@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// The messages below are defined by the snapshot service of Fabric 2.4 peers (peer/snapshot.proto),
+// which is not included in the Fabric protos vendored by the SDK.
+
+const (
+	generateMethod      = "/protos.Snapshot/Generate"
+	cancelMethod        = "/protos.Snapshot/Cancel"
+	queryPendingsMethod = "/protos.Snapshot/QueryPendings"
+)
+
+// snapshotRequest is used for generating a snapshot or cancelling a snapshot request
+type snapshotRequest struct {
+	SignatureHeader *common.SignatureHeader `protobuf:"bytes,1,opt,name=signature_header,json=signatureHeader" json:"signature_header,omitempty"`
+	ChannelId       string                  `protobuf:"bytes,2,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	BlockNumber     uint64                  `protobuf:"varint,3,opt,name=block_number,json=blockNumber" json:"block_number,omitempty"`
+}
+
+func (m *snapshotRequest) Reset()         { *m = snapshotRequest{} }
+func (m *snapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*snapshotRequest) ProtoMessage()    {}
+
+// snapshotQuery is used for querying the pending snapshot requests
+type snapshotQuery struct {
+	SignatureHeader *common.SignatureHeader `protobuf:"bytes,1,opt,name=signature_header,json=signatureHeader" json:"signature_header,omitempty"`
+	ChannelId       string                  `protobuf:"bytes,2,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+}
+
+func (m *snapshotQuery) Reset()         { *m = snapshotQuery{} }
+func (m *snapshotQuery) String() string { return proto.CompactTextString(m) }
+func (*snapshotQuery) ProtoMessage()    {}
+
+// signedSnapshotRequest contains a marshalled snapshotRequest or snapshotQuery and
+// the signature of the requester
+type signedSnapshotRequest struct {
+	Request   []byte `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *signedSnapshotRequest) Reset()         { *m = signedSnapshotRequest{} }
+func (m *signedSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*signedSnapshotRequest) ProtoMessage()    {}
+
+// queryPendingSnapshotsResponse contains the block numbers of the pending snapshot requests
+type queryPendingSnapshotsResponse struct {
+	BlockNumbers []uint64 `protobuf:"varint,1,rep,packed,name=block_numbers,json=blockNumbers" json:"block_numbers,omitempty"`
+}
+
+func (m *queryPendingSnapshotsResponse) Reset()         { *m = queryPendingSnapshotsResponse{} }
+func (m *queryPendingSnapshotsResponse) String() string { return proto.CompactTextString(m) }
+func (*queryPendingSnapshotsResponse) ProtoMessage()    {}
+
+// empty corresponds to google.protobuf.Empty
+type empty struct{}
+
+func (m *empty) Reset()         { *m = empty{} }
+func (m *empty) String() string { return proto.CompactTextString(m) }
+func (*empty) ProtoMessage()    {}
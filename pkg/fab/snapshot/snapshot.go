@@ -0,0 +1,172 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package snapshot provides a client for the ledger snapshot service of peers (Fabric 2.4 or later).
+// Snapshot requests may be submitted for a block number, cancelled while they are pending and the
+// pending requests may be queried. Snapshots which have been generated are stored on the peer's file
+// system; ListCompleted may be used to list them if the peer's snapshot directory is accessible.
+package snapshot
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/audit"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/fab")
+
+const completedDir = "completed"
+
+// Client implements a client for the snapshot service of a peer
+type Client struct {
+	ctx fabcontext.Client
+}
+
+// New returns a new snapshot client
+func New(ctx fabcontext.Client) *Client {
+	return &Client{ctx: ctx}
+}
+
+// Generate submits a request to generate a snapshot of the given channel at the given block number.
+// If the block number is 0 then the snapshot is generated at the last committed block.
+func (c *Client) Generate(reqCtx context.Context, target fab.PeerConfig, channelID string, blockNumber uint64) error {
+	signedReq, err := c.newSignedRequest(channelID, blockNumber)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Submitting snapshot request for channel [%s] and block [%d] to [%s]", channelID, blockNumber, target.URL)
+	return c.invoke(reqCtx, target, generateMethod, signedReq, &empty{})
+}
+
+// Cancel cancels the pending snapshot request of the given channel for the given block number
+func (c *Client) Cancel(reqCtx context.Context, target fab.PeerConfig, channelID string, blockNumber uint64) error {
+	signedReq, err := c.newSignedRequest(channelID, blockNumber)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Cancelling snapshot request for channel [%s] and block [%d] on [%s]", channelID, blockNumber, target.URL)
+	return c.invoke(reqCtx, target, cancelMethod, signedReq, &empty{})
+}
+
+// QueryPendings returns the block numbers of the pending snapshot requests of the given channel
+func (c *Client) QueryPendings(reqCtx context.Context, target fab.PeerConfig, channelID string) ([]uint64, error) {
+	sigHeader, err := c.newSignatureHeader(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	signedReq, err := c.sign(&snapshotQuery{SignatureHeader: sigHeader, ChannelId: channelID})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &queryPendingSnapshotsResponse{}
+	if err := c.invoke(reqCtx, target, queryPendingsMethod, signedReq, resp); err != nil {
+		return nil, err
+	}
+	return resp.BlockNumbers, nil
+}
+
+func (c *Client) invoke(reqCtx context.Context, target fab.PeerConfig, method string, req *signedSnapshotRequest, resp proto.Message) error {
+	opts := comm.OptsFromPeerConfig(&target)
+	opts = append(opts, comm.WithConnectTimeout(c.ctx.EndpointConfig().Timeout(fab.PeerConnection)))
+
+	conn, err := comm.NewConnection(c.ctx, target.URL, opts...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	audit.RecordSigned(c.ctx, audit.PurposeSnapshotRequest, req.Request, target.URL)
+	if err := conn.ClientConn().Invoke(reqCtx, method, req, resp); err != nil {
+		return errors.Wrapf(err, "snapshot request to [%s] failed", target.URL)
+	}
+	return nil
+}
+
+func (c *Client) newSignedRequest(channelID string, blockNumber uint64) (*signedSnapshotRequest, error) {
+	sigHeader, err := c.newSignatureHeader(channelID)
+	if err != nil {
+		return nil, err
+	}
+	return c.sign(&snapshotRequest{SignatureHeader: sigHeader, ChannelId: channelID, BlockNumber: blockNumber})
+}
+
+func (c *Client) newSignatureHeader(channelID string) (*common.SignatureHeader, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+
+	creator, err := c.ctx.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to serialize identity")
+	}
+
+	nonce, err := crypto.GetRandomNonce()
+	if err != nil {
+		return nil, errors.WithMessage(err, "nonce creation failed")
+	}
+
+	return &common.SignatureHeader{Creator: creator, Nonce: nonce}, nil
+}
+
+func (c *Client) sign(req proto.Message) (*signedSnapshotRequest, error) {
+	reqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of snapshot request failed")
+	}
+
+	signature, err := c.ctx.SigningManager().Sign(reqBytes, c.ctx.PrivateKey())
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing of snapshot request failed")
+	}
+
+	return &signedSnapshotRequest{Request: reqBytes, Signature: signature}, nil
+}
+
+// ListCompleted returns the block numbers of the snapshots of the given channel which have been generated
+// in the given snapshot root directory (the peer's ledger.snapshots.rootDir), in ascending order
+func ListCompleted(rootDir, channelID string) ([]uint64, error) {
+	dir := filepath.Join(rootDir, completedDir, channelID)
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read snapshot directory [%s]", dir)
+	}
+
+	var blockNumbers []uint64
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		blockNumber, err := strconv.ParseUint(entry.Name(), 10, 64)
+		if err != nil {
+			logger.Debugf("Ignoring directory [%s] in snapshot directory [%s]", entry.Name(), dir)
+			continue
+		}
+		blockNumbers = append(blockNumbers, blockNumber)
+	}
+
+	sort.Slice(blockNumbers, func(i, j int) bool { return blockNumbers[i] < blockNumbers[j] })
+	return blockNumbers, nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+const (
+	peerAddress = "localhost:9996"
+	channelID   = "mychannel"
+)
+
+var snapshotServer = &mockSnapshotServer{pending: make(map[string][]uint64)}
+
+func TestSnapshotRequests(t *testing.T) {
+	client := New(newMockContext())
+	target := fab.PeerConfig{
+		URL:         peerAddress,
+		GRPCOptions: map[string]interface{}{"allow-insecure": true},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, client.Generate(ctx, target, channelID, 100))
+	require.NoError(t, client.Generate(ctx, target, channelID, 200))
+
+	pending, err := client.QueryPendings(ctx, target, channelID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{100, 200}, pending)
+
+	require.NoError(t, client.Cancel(ctx, target, channelID, 100))
+	pending, err = client.QueryPendings(ctx, target, channelID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{200}, pending)
+
+	err = client.Cancel(ctx, target, channelID, 300)
+	assert.Error(t, err, "expecting error for cancelling an unknown request")
+
+	err = client.Generate(ctx, target, "", 100)
+	assert.Error(t, err, "expecting error for missing channel ID")
+}
+
+func TestListCompleted(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "snapshots")
+	require.NoError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	blockNumbers, err := ListCompleted(rootDir, channelID)
+	require.NoError(t, err)
+	assert.Empty(t, blockNumbers)
+
+	for _, dir := range []string{"20", "3", "tmp"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(rootDir, completedDir, channelID, dir), 0755))
+	}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(rootDir, completedDir, channelID, "5"), nil, 0644))
+
+	blockNumbers, err = ListCompleted(rootDir, channelID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{3, 20}, blockNumbers)
+}
+
+func TestMain(m *testing.M) {
+	grpcServer := grpc.NewServer()
+	lis, err := net.Listen("tcp", peerAddress)
+	if err != nil {
+		panic(fmt.Sprintf("Error starting snapshot listener %s", err))
+	}
+
+	grpcServer.RegisterService(&snapshotServiceDesc, snapshotServer)
+	go grpcServer.Serve(lis)
+
+	rc := m.Run()
+	grpcServer.Stop()
+	os.Exit(rc)
+}
+
+func newMockContext() *mocks.MockContext {
+	context := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "test"))
+	context.SetCustomInfraProvider(comm.NewMockInfraProvider())
+	return context
+}
+
+// mockSnapshotServer keeps track of the pending snapshot requests per channel
+type mockSnapshotServer struct {
+	mutex   sync.Mutex
+	pending map[string][]uint64
+}
+
+func (s *mockSnapshotServer) generate(req *snapshotRequest) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.pending[req.ChannelId] = append(s.pending[req.ChannelId], req.BlockNumber)
+	return nil
+}
+
+func (s *mockSnapshotServer) cancel(req *snapshotRequest) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	pending := s.pending[req.ChannelId]
+	for i, blockNumber := range pending {
+		if blockNumber == req.BlockNumber {
+			s.pending[req.ChannelId] = append(pending[:i], pending[i+1:]...)
+			return nil
+		}
+	}
+	return errors.Errorf("no pending snapshot request for block [%d]", req.BlockNumber)
+}
+
+func (s *mockSnapshotServer) queryPendings(query *snapshotQuery) []uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.pending[query.ChannelId]
+}
+
+func unmarshalSignedRequest(dec func(interface{}) error, req proto.Message) error {
+	signedReq := &signedSnapshotRequest{}
+	if err := dec(signedReq); err != nil {
+		return err
+	}
+	if len(signedReq.Signature) == 0 {
+		return errors.New("missing signature")
+	}
+	return proto.Unmarshal(signedReq.Request, req)
+}
+
+func requestHandler(handle func(s *mockSnapshotServer, req *snapshotRequest) error) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		req := &snapshotRequest{}
+		if err := unmarshalSignedRequest(dec, req); err != nil {
+			return nil, err
+		}
+		if req.SignatureHeader == nil || len(req.SignatureHeader.Nonce) == 0 {
+			return nil, errors.New("invalid signature header")
+		}
+		return &empty{}, handle(srv.(*mockSnapshotServer), req)
+	}
+}
+
+var snapshotServiceDesc = grpc.ServiceDesc{
+	ServiceName: "protos.Snapshot",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Generate",
+			Handler:    requestHandler((*mockSnapshotServer).generate),
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    requestHandler((*mockSnapshotServer).cancel),
+		},
+		{
+			MethodName: "QueryPendings",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				query := &snapshotQuery{}
+				if err := unmarshalSignedRequest(dec, query); err != nil {
+					return nil, err
+				}
+				return &queryPendingSnapshotsResponse{BlockNumbers: srv.(*mockSnapshotServer).queryPendings(query)}, nil
+			},
+		},
+	},
+}
@@ -0,0 +1,89 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	reqContext "context"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+type artifactsKey struct{}
+
+// Artifacts captures the marshalled signed proposal and transaction envelope which are sent to the
+// endorsers and orderers within a request context, e.g. so that they may be archived.
+type Artifacts struct {
+	mutex          sync.RWMutex
+	signedProposal []byte
+	envelope       []byte
+}
+
+// WithArtifacts returns a child context of the given request context in which the signed proposals and
+// envelopes are captured by the given artifacts
+func WithArtifacts(ctx reqContext.Context, artifacts *Artifacts) reqContext.Context {
+	return reqContext.WithValue(ctx, artifactsKey{}, artifacts)
+}
+
+// SignedProposal returns the marshalled pb.SignedProposal which was last sent to the endorsers (nil if none)
+func (a *Artifacts) SignedProposal() []byte {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.signedProposal
+}
+
+// Envelope returns the marshalled common.Envelope which was last sent to the orderers (nil if none)
+func (a *Artifacts) Envelope() []byte {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.envelope
+}
+
+// Reset clears the captured artifacts (e.g. before a request is retried)
+func (a *Artifacts) Reset() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.signedProposal = nil
+	a.envelope = nil
+}
+
+func captureSignedProposal(ctx reqContext.Context, signedProposal *pb.SignedProposal) {
+	artifacts, ok := ctx.Value(artifactsKey{}).(*Artifacts)
+	if !ok {
+		return
+	}
+
+	signedProposalBytes, err := proto.Marshal(signedProposal)
+	if err != nil {
+		logger.Warnf("Failed to marshal signed proposal for capture: %s", err)
+		return
+	}
+
+	artifacts.mutex.Lock()
+	defer artifacts.mutex.Unlock()
+	artifacts.signedProposal = signedProposalBytes
+}
+
+func captureEnvelope(ctx reqContext.Context, envelope *fab.SignedEnvelope) {
+	artifacts, ok := ctx.Value(artifactsKey{}).(*Artifacts)
+	if !ok {
+		return
+	}
+
+	envelopeBytes, err := proto.Marshal(&common.Envelope{Payload: envelope.Payload, Signature: envelope.Signature})
+	if err != nil {
+		logger.Warnf("Failed to marshal envelope for capture: %s", err)
+		return
+	}
+
+	artifacts.mutex.Lock()
+	defer artifacts.mutex.Unlock()
+	artifacts.envelope = envelopeBytes
+}
@@ -0,0 +1,71 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestArtifacts(t *testing.T) {
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("test", "1234"))
+
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second))
+	defer cancel()
+
+	artifacts := &Artifacts{}
+	reqCtx = WithArtifacts(reqCtx, artifacts)
+
+	txh, err := NewHeader(ctx, testChannel)
+	require.NoError(t, err)
+	tp, err := CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{ChaincodeID: "cc", Fcn: "invoke"})
+	require.NoError(t, err)
+
+	peer := &mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Status: 200, Payload: []byte("A")}
+	_, err = SendProposal(reqCtx, tp, []fab.ProposalProcessor{peer})
+	require.NoError(t, err)
+
+	signedProposal := &pb.SignedProposal{}
+	require.NoError(t, proto.Unmarshal(artifacts.SignedProposal(), signedProposal))
+	proposalBytes, err := proto.Marshal(tp.Proposal)
+	require.NoError(t, err)
+	assert.Equal(t, proposalBytes, signedProposal.ProposalBytes)
+	assert.NotEmpty(t, signedProposal.Signature)
+	assert.Nil(t, artifacts.Envelope(), "expecting no envelope before the transaction is sent")
+
+	tx := &fab.Transaction{Proposal: tp, Transaction: &pb.Transaction{}}
+	_, err = Send(reqCtx, tx, []fab.Orderer{mocks.NewMockOrderer("", nil)})
+	require.NoError(t, err)
+
+	envelope := &common.Envelope{}
+	require.NoError(t, proto.Unmarshal(artifacts.Envelope(), envelope))
+	payload := &common.Payload{}
+	require.NoError(t, proto.Unmarshal(envelope.Payload, payload))
+	assert.Equal(t, tp.Proposal.Header, mustMarshal(t, payload.Header))
+	assert.NotEmpty(t, envelope.Signature)
+
+	artifacts.Reset()
+	assert.Nil(t, artifacts.SignedProposal())
+	assert.Nil(t, artifacts.Envelope())
+}
+
+func mustMarshal(t *testing.T, msg proto.Message) []byte {
+	b, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return b
+}
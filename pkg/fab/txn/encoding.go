@@ -0,0 +1,168 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// Field numbers of the marshalled messages which are read (or written) without unmarshalling them
+const (
+	headerChannelHeaderField    = 1
+	headerSignatureHeaderField  = 2
+	channelHeaderExtensionField = 7
+	ccHeaderExtVisibilityField  = 1
+	ccProposalPayloadInputField = 1
+	ccInputIsInitField          = 3
+	payloadHeaderField          = 1
+	payloadDataField            = 2
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// maxPooledBufferCapacity is the capacity above which buffers aren't returned to the pool
+const maxPooledBufferCapacity = 1024 * 1024
+
+// bufferPool holds the buffers used for marshalling messages whose bytes aren't retained
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return proto.NewBuffer(nil)
+	},
+}
+
+func getBuffer() *proto.Buffer {
+	buf := bufferPool.Get().(*proto.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *proto.Buffer) {
+	if cap(buf.Bytes()) > maxPooledBufferCapacity {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// rawField returns the value of the given length-delimited field of the marshalled message without
+// unmarshalling the message (nil is returned if the field isn't set). The returned slice refers to the message.
+func rawField(msg []byte, field uint64) ([]byte, error) {
+	var value []byte
+	for len(msg) > 0 {
+		num, wireType, v, rest, err := nextField(msg)
+		if err != nil {
+			return nil, err
+		}
+		if num == field {
+			if wireType != wireBytes {
+				return nil, errors.Errorf("unexpected wire type %d for field %d", wireType, num)
+			}
+			value = v
+		}
+		msg = rest
+	}
+	return value, nil
+}
+
+// hasOnlyField returns true if the marshalled message contains no fields other than a single occurrence
+// of the given field, i.e. if marshalling the message with just that field would produce the same bytes
+func hasOnlyField(msg []byte, field uint64) (bool, error) {
+	only := true
+	count := 0
+	for len(msg) > 0 {
+		num, _, _, rest, err := nextField(msg)
+		if err != nil {
+			return false, err
+		}
+		if num == field {
+			count++
+		}
+		if num != field || count > 1 {
+			only = false
+		}
+		msg = rest
+	}
+	return only, nil
+}
+
+// nextField decodes the first field of the marshalled message. The value of a length-delimited
+// field refers to the message (it isn't copied).
+func nextField(msg []byte) (num, wireType uint64, value, rest []byte, err error) {
+	key, n := proto.DecodeVarint(msg)
+	if n == 0 {
+		return 0, 0, nil, nil, errors.New("invalid field key")
+	}
+	msg = msg[n:]
+
+	num, wireType = key>>3, key&7
+	if num == 0 {
+		return 0, 0, nil, nil, errors.New("invalid field number")
+	}
+
+	var size int
+	switch wireType {
+	case wireVarint:
+		if _, size = proto.DecodeVarint(msg); size == 0 {
+			return 0, 0, nil, nil, errors.New("invalid varint field")
+		}
+	case wireFixed64:
+		size = 8
+	case wireFixed32:
+		size = 4
+	case wireBytes:
+		l, n := proto.DecodeVarint(msg)
+		if n == 0 || l > uint64(len(msg)-n) {
+			return 0, 0, nil, nil, errors.New("invalid length-delimited field")
+		}
+		value = msg[n : n+int(l)]
+		size = n + int(l)
+	default:
+		return 0, 0, nil, nil, errors.Errorf("unsupported wire type %d", wireType)
+	}
+
+	if size > len(msg) {
+		return 0, 0, nil, nil, errors.New("unexpected end of message")
+	}
+	return num, wireType, value, msg[size:], nil
+}
+
+// appendBytesField appends the given length-delimited field to the marshalled message
+func appendBytesField(msg []byte, field uint64, value []byte) []byte {
+	msg = appendVarint(msg, field<<3|wireBytes)
+	msg = appendVarint(msg, uint64(len(value)))
+	return append(msg, value...)
+}
+
+// appendBoolField appends the given varint encoded bool field to the marshalled message
+func appendBoolField(msg []byte, field uint64, value bool) []byte {
+	msg = appendVarint(msg, field<<3|wireVarint)
+	if value {
+		return append(msg, 1)
+	}
+	return append(msg, 0)
+}
+
+// bytesFieldSize returns the marshalled size of the given length-delimited field
+func bytesFieldSize(field uint64, value []byte) int {
+	return proto.SizeVarint(field<<3|wireBytes) + proto.SizeVarint(uint64(len(value))) + len(value)
+}
+
+func appendVarint(b []byte, x uint64) []byte {
+	for x >= 0x80 {
+		b = append(b, byte(x)|0x80)
+		x >>= 7
+	}
+	return append(b, byte(x))
+}
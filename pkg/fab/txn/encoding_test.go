@@ -0,0 +1,61 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func TestRawField(t *testing.T) {
+	chdr := &common.ChannelHeader{Type: 3, Version: 1, ChannelId: "mychannel", Epoch: 7, Extension: []byte("ext")}
+	chdrBytes, err := proto.Marshal(chdr)
+	require.NoError(t, err)
+
+	value, err := rawField(chdrBytes, channelHeaderExtensionField)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ext"), value)
+
+	value, err = rawField(chdrBytes, 100)
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	_, err = rawField(chdrBytes, 1)
+	assert.Error(t, err, "expecting error for field which isn't length-delimited")
+
+	_, err = rawField(chdrBytes[:len(chdrBytes)-1], channelHeaderExtensionField)
+	assert.Error(t, err, "expecting error for truncated message")
+
+	_, err = rawField([]byte("TEST"), 1)
+	assert.Error(t, err, "expecting error for invalid message")
+}
+
+func TestHasOnlyField(t *testing.T) {
+	only, err := hasOnlyField(nil, 1)
+	require.NoError(t, err)
+	assert.True(t, only)
+
+	msg := appendBytesField(nil, 1, []byte("input"))
+	assert.Len(t, msg, bytesFieldSize(1, []byte("input")))
+
+	only, err = hasOnlyField(msg, 1)
+	require.NoError(t, err)
+	assert.True(t, only)
+
+	only, err = hasOnlyField(appendBytesField(msg, 2, []byte("transient")), 1)
+	require.NoError(t, err)
+	assert.False(t, only)
+
+	only, err = hasOnlyField(appendBytesField(msg, 1, []byte("input")), 1)
+	require.NoError(t, err)
+	assert.False(t, only, "expecting false for repeated field")
+}
@@ -19,6 +19,7 @@ import (
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/audit"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
@@ -51,9 +52,29 @@ func (th *TransactionHeader) ChannelID() string {
 	return th.channelID
 }
 
+// HeaderOpt is an option for creating a transaction header
+type HeaderOpt func(opts *headerOptions)
+
+type headerOptions struct {
+	hashingAlgorithm string
+}
+
+// WithHashingAlgorithm sets the hashing algorithm (e.g. SHA256 or SHA3_256) used to compute the
+// transaction ID. It should match the hashing algorithm of the channel. The default is SHA256.
+func WithHashingAlgorithm(algorithm string) HeaderOpt {
+	return func(opts *headerOptions) {
+		opts.hashingAlgorithm = algorithm
+	}
+}
+
 // NewHeader computes a TransactionID from the current user context and holds
 // metadata to create transaction proposals.
-func NewHeader(ctx contextApi.Client, channelID string) (*TransactionHeader, error) {
+func NewHeader(ctx contextApi.Client, channelID string, opts ...HeaderOpt) (*TransactionHeader, error) {
+	o := headerOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	// generate a random nonce
 	nonce, err := crypto.GetRandomNonce()
 	if err != nil {
@@ -65,7 +86,11 @@ func NewHeader(ctx contextApi.Client, channelID string) (*TransactionHeader, err
 		return nil, errors.WithMessage(err, "identity from context failed")
 	}
 
-	ho := cryptosuite.GetSHA256Opts() // TODO: make configurable
+	ho, err := cryptosuite.GetHashOpts(o.hashingAlgorithm)
+	if err != nil {
+		return nil, errors.WithMessage(err, "hash options creation failed")
+	}
+
 	h, err := ctx.CryptoSuite().GetHash(ho)
 	if err != nil {
 		return nil, errors.WithMessage(err, "hash function creation failed")
@@ -87,10 +112,10 @@ func NewHeader(ctx contextApi.Client, channelID string) (*TransactionHeader, err
 }
 
 func computeTxnID(nonce, creator []byte, h hash.Hash) (string, error) {
-	b := append(nonce, creator...)
-
-	_, err := h.Write(b)
-	if err != nil {
+	if _, err := h.Write(nonce); err != nil {
+		return "", err
+	}
+	if _, err := h.Write(creator); err != nil {
 		return "", err
 	}
 	digest := h.Sum(nil)
@@ -105,12 +130,17 @@ func signPayload(ctx contextApi.Client, payload *common.Payload) (*fab.SignedEnv
 	if err != nil {
 		return nil, errors.WithMessage(err, "marshaling of payload failed")
 	}
+	return signPayloadBytes(ctx, payloadBytes)
+}
 
+// signPayloadBytes signs the marshalled payload
+func signPayloadBytes(ctx contextApi.Client, payloadBytes []byte) (*fab.SignedEnvelope, error) {
 	signingMgr := ctx.SigningManager()
 	signature, err := signingMgr.Sign(payloadBytes, ctx.PrivateKey())
 	if err != nil {
 		return nil, errors.WithMessage(err, "signing of payload failed")
 	}
+	audit.RecordSigned(ctx, audit.PurposeEnvelope, payloadBytes, "")
 	return &fab.SignedEnvelope{Payload: payloadBytes, Signature: signature}, nil
 }
 
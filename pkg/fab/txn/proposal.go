@@ -17,6 +17,7 @@ import (
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/audit"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
@@ -39,10 +40,17 @@ func CreateChaincodeInvokeProposal(txh fab.TransactionHeader, request fab.Chainc
 		argsArray[i+1] = arg
 	}
 
+	input := &pb.ChaincodeInput{Args: argsArray}
+	if request.IsInit {
+		// The is_init field (Fabric 2.0) isn't defined by this version of ChaincodeInput so it's
+		// added as an unrecognized field, which is marshalled along with the known fields
+		input.XXX_unrecognized = appendBoolField(nil, ccInputIsInitField, true)
+	}
+
 	// create invocation spec to target a chaincode with arguments
 	ccis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{
 		Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: request.ChaincodeID},
-		Input: &pb.ChaincodeInput{Args: argsArray}}}
+		Input: input}}
 
 	proposal, _, err := protos_utils.CreateChaincodeProposalWithTxIDNonceAndTransient(string(txh.TransactionID()), common.HeaderType_ENDORSER_TRANSACTION, txh.ChannelID(), ccis, txh.Nonce(), txh.Creator(), request.TransientMap)
 	if err != nil {
@@ -73,6 +81,7 @@ func signProposal(ctx contextApi.Client, proposal *pb.Proposal) (*pb.SignedPropo
 	if err != nil {
 		return nil, errors.WithMessage(err, "sign failed")
 	}
+	audit.RecordSigned(ctx, audit.PurposeProposal, proposalBytes, "")
 
 	return &pb.SignedProposal{ProposalBytes: proposalBytes, Signature: signature}, nil
 }
@@ -104,6 +113,7 @@ func SendProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal,
 	if err != nil {
 		return nil, errors.WithMessage(err, "sign proposal failed")
 	}
+	captureSignedProposal(reqCtx, signedProposal)
 
 	request := fab.ProcessProposalRequest{SignedProposal: signedProposal}
 
@@ -123,7 +133,7 @@ func SendProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal,
 			if err != nil {
 				logger.Debugf("Received error response from txn proposal processing: %s", err)
 				responseMtx.Lock()
-				errs = append(errs, err)
+				errs = append(errs, NewTargetError(processor, err))
 				responseMtx.Unlock()
 				return
 			}
@@ -138,6 +148,16 @@ func SendProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal,
 	return transactionProposalResponses, errs.ToError()
 }
 
+// NewTargetError associates the error with the target if the target is a peer, so that
+// the error is included in multi.ByTarget
+func NewTargetError(target fab.ProposalProcessor, err error) error {
+	p, ok := target.(fab.Peer)
+	if !ok {
+		return err
+	}
+	return multi.NewTargetError(p.URL(), p.MSPID(), err)
+}
+
 // getTargetsWithoutDuplicates returns a list of targets without duplicates
 func getTargetsWithoutDuplicates(targets []fab.ProposalProcessor) []fab.ProposalProcessor {
 	peerUrlsToTargets := map[string]fab.ProposalProcessor{}
@@ -60,6 +60,39 @@ func TestNewTransactionProposal(t *testing.T) {
 	}
 }
 
+func TestNewTransactionProposalIsInit(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	txh, err := NewHeader(ctx, testChannel)
+	assert.NoError(t, err)
+
+	isInit := func(request fab.ChaincodeInvokeRequest) bool {
+		tp, err := CreateChaincodeInvokeProposal(txh, request)
+		assert.NoError(t, err)
+
+		ccProposalPayload := &pb.ChaincodeProposalPayload{}
+		assert.NoError(t, proto.Unmarshal(tp.Payload, ccProposalPayload))
+		ccis := &pb.ChaincodeInvocationSpec{}
+		assert.NoError(t, proto.Unmarshal(ccProposalPayload.Input, ccis))
+
+		input := ccis.ChaincodeSpec.Input
+		assert.Equal(t, [][]byte{[]byte("init"), []byte("a")}, input.Args)
+		if len(input.XXX_unrecognized) == 0 {
+			return false
+		}
+		num, wireType, _, rest, err := nextField(input.XXX_unrecognized)
+		assert.NoError(t, err)
+		assert.Empty(t, rest)
+		assert.Equal(t, uint64(ccInputIsInitField), num)
+		assert.Equal(t, uint64(wireVarint), wireType)
+		return input.XXX_unrecognized[len(input.XXX_unrecognized)-1] == 1
+	}
+
+	assert.False(t, isInit(fab.ChaincodeInvokeRequest{ChaincodeID: "cc", Fcn: "init", Args: [][]byte{[]byte("a")}}))
+	assert.True(t, isInit(fab.ChaincodeInvokeRequest{ChaincodeID: "cc", Fcn: "init", Args: [][]byte{[]byte("a")}, IsInit: true}))
+}
+
 func TestSendTransactionProposal(t *testing.T) {
 	user := mspmocks.NewMockSigningIdentity("test", "1234")
 	ctx := mocks.NewMockContext(user)
@@ -266,6 +299,39 @@ func TestProposalResponseError(t *testing.T) {
 	assert.Equal(t, testError, errs[0])
 }
 
+func TestProposalResponseErrorByTarget(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second))
+	defer cancel()
+
+	testError := fmt.Errorf("endorsement failed")
+	peer1 := &mocks.MockPeer{MockName: "peer1", MockURL: "peer1.org1.example.com", MockMSP: "Org1MSP", Status: 200, Error: testError}
+	peer2 := &mocks.MockPeer{MockName: "peer2", MockURL: "peer2.org1.example.com", MockMSP: "Org1MSP", Status: 200}
+	peer3 := &mocks.MockPeer{MockName: "peer3", MockURL: "peer1.org2.example.com", MockMSP: "Org2MSP", Status: 200, Error: testError}
+
+	_, err := SendProposal(reqCtx, &fab.TransactionProposal{
+		Proposal: &pb.Proposal{},
+	}, []fab.ProposalProcessor{peer1, peer2, peer3})
+	assert.Error(t, err)
+
+	errs := multi.ByTarget(err)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, testError, errs["peer1.org1.example.com"].Err)
+	assert.Equal(t, "Org2MSP", errs["peer1.org2.example.com"].MSPID)
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP"}, errs.FailedOrgs())
+
+	targets := map[string]string{
+		"peer1.org1.example.com": "Org1MSP",
+		"peer2.org1.example.com": "Org1MSP",
+		"peer1.org2.example.com": "Org2MSP",
+	}
+	assert.Equal(t, []string{"Org1MSP"}, errs.SucceededOrgs(targets))
+	assert.True(t, multi.SucceededOnOrgs(err, targets, 1))
+	assert.False(t, multi.SucceededOnOrgs(err, targets, 2))
+}
+
 func setupMassiveTestPeers(numberOfPeers int) []fab.ProposalProcessor {
 	peers := []fab.ProposalProcessor{}
 
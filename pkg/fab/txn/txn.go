@@ -9,14 +9,16 @@ package txn
 
 import (
 	reqContext "context"
-	"math/rand"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/audit"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/random"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
@@ -41,20 +43,14 @@ func New(request fab.TransactionRequest) (*fab.Transaction, error) {
 
 	proposal := request.Proposal
 
-	// the original header
-	hdr, err := protos_utils.GetHeader(proposal.Header)
+	// the signature header and the payload visibility are read from the original header without unmarshalling it
+	signatureHeader, visibility, err := proposalHeaderFields(proposal.Header)
 	if err != nil {
 		return nil, errors.Wrap(err, "unmarshal proposal header failed")
 	}
 
-	// the original payload
-	pPayl, err := protos_utils.GetChaincodeProposalPayload(proposal.Payload)
-	if err != nil {
-		return nil, errors.Wrap(err, "unmarshal proposal payload failed")
-	}
-
-	// get header extensions so we have the visibility field
-	hdrExt, err := protos_utils.GetChaincodeHeaderExtension(hdr)
+	// obtain the bytes of the proposal payload that will go to the transaction
+	propPayloadBytes, err := proposalPayloadForTx(proposal.Payload, visibility)
 	if err != nil {
 		return nil, err
 	}
@@ -73,12 +69,6 @@ func New(request fab.TransactionRequest) (*fab.Transaction, error) {
 	// create ChaincodeEndorsedAction
 	cea := &pb.ChaincodeEndorsedAction{ProposalResponsePayload: responsePayload, Endorsements: endorsements}
 
-	// obtain the bytes of the proposal payload that will go to the transaction
-	propPayloadBytes, err := protos_utils.GetBytesProposalPayloadForTx(pPayl, hdrExt.PayloadVisibility)
-	if err != nil {
-		return nil, err
-	}
-
 	// serialize the chaincode action payload
 	cap := &pb.ChaincodeActionPayload{ChaincodeProposalPayload: propPayloadBytes, Action: cea}
 	capBytes, err := protos_utils.GetBytesChaincodeActionPayload(cap)
@@ -87,7 +77,7 @@ func New(request fab.TransactionRequest) (*fab.Transaction, error) {
 	}
 
 	// create a transaction
-	taa := &pb.TransactionAction{Header: hdr.SignatureHeader, Payload: capBytes}
+	taa := &pb.TransactionAction{Header: signatureHeader, Payload: capBytes}
 	taas := make([]*pb.TransactionAction, 1)
 	taas[0] = taa
 
@@ -97,6 +87,48 @@ func New(request fab.TransactionRequest) (*fab.Transaction, error) {
 	}, nil
 }
 
+// proposalHeaderFields returns the signature header and the payload visibility (from the chaincode
+// header extension of the channel header) of the marshalled proposal header
+func proposalHeaderFields(header []byte) (signatureHeader, visibility []byte, err error) {
+	signatureHeader, err = rawField(header, headerSignatureHeaderField)
+	if err != nil {
+		return nil, nil, err
+	}
+	channelHeader, err := rawField(header, headerChannelHeaderField)
+	if err != nil {
+		return nil, nil, err
+	}
+	extension, err := rawField(channelHeader, channelHeaderExtensionField)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "invalid channel header")
+	}
+	visibility, err = rawField(extension, ccHeaderExtVisibilityField)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "invalid chaincode header extension")
+	}
+	return signatureHeader, visibility, nil
+}
+
+// proposalPayloadForTx returns the bytes of the proposal payload for the transaction (i.e. without the
+// transient data). The original bytes are used if the payload doesn't contain any transient data.
+func proposalPayloadForTx(payload, visibility []byte) ([]byte, error) {
+	if visibility == nil {
+		onlyInput, err := hasOnlyField(payload, ccProposalPayloadInputField)
+		if err != nil {
+			return nil, errors.Wrap(err, "unmarshal proposal payload failed")
+		}
+		if onlyInput {
+			return payload, nil
+		}
+	}
+
+	pPayl, err := protos_utils.GetChaincodeProposalPayload(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal proposal payload failed")
+	}
+	return protos_utils.GetBytesProposalPayloadForTx(pPayl, visibility)
+}
+
 func validateProposalResponses(responses []*fab.TransactionProposalResponse) error {
 	for _, r := range responses {
 		if r.ProposalResponse.Response.Status < int32(common.Status_SUCCESS) || r.ProposalResponse.Response.Status >= int32(common.Status_BAD_REQUEST) {
@@ -118,26 +150,46 @@ func Send(reqCtx reqContext.Context, tx *fab.Transaction, orderers []fab.Orderer
 		return nil, errors.New("proposal is nil")
 	}
 
-	// the original header
-	hdr, err := protos_utils.GetHeader(tx.Proposal.Proposal.Header)
-	if err != nil {
-		return nil, errors.Wrap(err, "unmarshal proposal header failed")
+	ctx, ok := context.RequestClientContext(reqCtx)
+	if !ok {
+		return nil, errors.New("failed get client context from reqContext for signPayload")
 	}
-	// serialize the tx
-	txBytes, err := protos_utils.GetBytesTransaction(tx.Transaction)
+
+	// the payload is created from the original header bytes
+	payloadBytes, err := transactionPayload(tx.Proposal.Proposal.Header, tx.Transaction)
 	if err != nil {
 		return nil, err
 	}
 
-	// create the payload
-	payload := common.Payload{Header: hdr, Data: txBytes}
-
-	transactionResponse, err := BroadcastPayload(reqCtx, &payload, orderers)
+	envelope, err := signPayloadBytes(ctx, payloadBytes)
 	if err != nil {
 		return nil, err
 	}
+	captureEnvelope(reqCtx, envelope)
+
+	return broadcastEnvelope(reqCtx, envelope, orderers)
+}
+
+// transactionPayload returns the marshalled payload with the given (marshalled) header and transaction
+func transactionPayload(header []byte, tx *pb.Transaction) ([]byte, error) {
+	if _, err := rawField(header, headerSignatureHeaderField); err != nil {
+		return nil, errors.Wrap(err, "unmarshal proposal header failed")
+	}
+
+	// the serialized tx is only needed for creating the payload so a pooled buffer is used
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := buf.Marshal(tx); err != nil {
+		return nil, errors.Wrap(err, "marshal transaction failed")
+	}
+	txBytes := buf.Bytes()
 
-	return transactionResponse, nil
+	payload := make([]byte, 0, bytesFieldSize(payloadHeaderField, header)+bytesFieldSize(payloadDataField, txBytes))
+	payload = appendBytesField(payload, payloadHeaderField, header)
+	if len(txBytes) > 0 {
+		payload = appendBytesField(payload, payloadDataField, txBytes)
+	}
+	return payload, nil
 }
 
 // BroadcastPayload will send the given payload to some orderer, picking random endpoints
@@ -160,8 +212,22 @@ func BroadcastPayload(reqCtx reqContext.Context, payload *common.Payload, ordere
 	return broadcastEnvelope(reqCtx, envelope, orderers)
 }
 
+// BroadcastEnvelope sends a previously constructed and signed envelope to some orderer, picking random
+// endpoints until all are exhausted. The envelope is sent as is (it isn't signed again).
+func BroadcastEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+	if envelope == nil || len(envelope.Payload) == 0 {
+		return nil, errors.New("envelope is nil")
+	}
+	captureEnvelope(reqCtx, envelope)
+
+	return broadcastEnvelope(reqCtx, envelope, orderers)
+}
+
 // broadcastEnvelope will send the given envelope to some orderer, picking random endpoints
-// until all are exhausted
+// until all are exhausted. The envelope is re-broadcast to the next orderer if an orderer is unavailable
+// (e.g. it returns SERVICE_UNAVAILABLE or the stream is reset). Since the same envelope (with the same
+// transaction ID) is re-broadcast, a transaction which ends up being ordered twice is invalidated by the
+// committing peers as a duplicate. If an orderer rejects the envelope then it's not re-broadcast.
 func broadcastEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
 	// Check if orderers are defined
 	if len(orderers) == 0 {
@@ -174,21 +240,47 @@ func broadcastEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope,
 
 	// Iterate them in a random order and try broadcasting 1 by 1
 	var errResp error
-	for _, i := range rand.Perm(len(randOrderers)) {
+	var failed []string
+	for _, i := range random.Perm(len(randOrderers)) {
 		resp, err := sendBroadcast(reqCtx, envelope, randOrderers[i])
-		if err != nil {
-			errResp = err
-		} else {
+		if err == nil {
+			resp.FailedOrderers = failed
 			return resp, nil
 		}
+
+		errResp = err
+		failed = append(failed, randOrderers[i].URL())
+
+		if !isFailover(err) {
+			logger.Debugf("Envelope was rejected by orderer [%s]: %s", randOrderers[i].URL(), err)
+			break
+		}
+		if reqCtx.Err() != nil {
+			break
+		}
+		logger.Debugf("Orderer [%s] is unavailable - re-broadcasting envelope to next orderer: %s", randOrderers[i].URL(), err)
 	}
 	return nil, errResp
 }
 
+// isFailover returns false if the error is a rejection of the envelope by the ordering service,
+// in which case broadcasting it to another orderer would fail as well
+func isFailover(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok || s.Group != status.OrdererServerStatus {
+		return true
+	}
+	return s.Code == int32(common.Status_SERVICE_UNAVAILABLE)
+}
+
 func sendBroadcast(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderer fab.Orderer) (*fab.TransactionResponse, error) {
 	logger.Debugf("Broadcasting envelope to orderer :%s\n", orderer.URL())
 	// Send request
-	if _, err := orderer.SendBroadcast(reqCtx, envelope); err != nil {
+	_, err := orderer.SendBroadcast(reqCtx, envelope)
+	if ctx, ok := context.RequestClientContext(reqCtx); ok {
+		audit.RecordSubmitted(ctx, envelope.Payload, orderer.URL(), err)
+	}
+	if err != nil {
 		logger.Debugf("Receive Error Response from orderer :%s\n", err)
 		return nil, errors.Wrapf(err, "calling orderer '%s' failed", orderer.URL())
 	}
@@ -218,7 +310,7 @@ func SendPayload(reqCtx reqContext.Context, payload *common.Payload, orderers []
 
 	// Iterate them in a random order and try broadcasting 1 by 1
 	var errResp error
-	for _, i := range rand.Perm(len(randOrderers)) {
+	for _, i := range random.Perm(len(randOrderers)) {
 		resp, err := sendEnvelope(reqCtx, envelope, randOrderers[i])
 		if err != nil {
 			errResp = err
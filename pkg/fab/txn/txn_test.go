@@ -8,6 +8,8 @@ package txn
 
 import (
 	reqContext "context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"strconv"
@@ -15,15 +17,22 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/sha3"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/audit"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
 )
 
 func TestNewTransaction(t *testing.T) {
@@ -190,6 +199,47 @@ func TestBroadcastEnvelope(t *testing.T) {
 	checkBroadcastCount(broadcastCount, orderer1, orderer2, reqCtx, sigEnvelope, orderers, t)
 }
 
+func TestBroadcastEnvelopeFailover(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	orderer1 := mocks.NewMockOrderer("orderer1", nil)
+	orderer2 := mocks.NewMockOrderer("orderer2", nil)
+	orderers := []fab.Orderer{orderer1, orderer2}
+
+	sigEnvelope := &fab.SignedEnvelope{
+		Signature: []byte(""),
+		Payload:   []byte(""),
+	}
+
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second))
+	defer cancel()
+
+	// The envelope is re-broadcast to orderer2 if orderer1 is unavailable
+	for i := 0; i < 10; i++ {
+		orderer1.EnqueueSendBroadcastError(status.New(status.OrdererServerStatus, int32(common.Status_SERVICE_UNAVAILABLE), "service unavailable", nil))
+
+		res, err := broadcastEnvelope(reqCtx, sigEnvelope, orderers)
+		assert.NoError(t, err)
+		assert.Equal(t, "orderer2", res.Orderer)
+		if len(res.FailedOrderers) > 0 {
+			assert.Equal(t, []string{"orderer1"}, res.FailedOrderers)
+		} else {
+			// orderer2 was selected first so the error is still queued
+			<-orderer1.BroadcastErrors
+		}
+	}
+
+	// The envelope isn't re-broadcast if it's rejected
+	badRequest := status.New(status.OrdererServerStatus, int32(common.Status_BAD_REQUEST), "bad request", nil)
+	orderer1.EnqueueSendBroadcastError(badRequest)
+	orderer2.EnqueueSendBroadcastError(badRequest)
+
+	_, err := broadcastEnvelope(reqCtx, sigEnvelope, orderers)
+	assert.Contains(t, err.Error(), "bad request")
+	assert.Equal(t, 1, len(orderer1.BroadcastErrors)+len(orderer2.BroadcastErrors), "expecting only one orderer to be called")
+}
+
 func checkBroadcastCount(broadcastCount int, orderer1 *mocks.MockOrderer, orderer2 *mocks.MockOrderer, reqCtx reqContext.Context, sigEnvelope *fab.SignedEnvelope, orderers []fab.Orderer, t *testing.T) {
 	for i := 0; i < broadcastCount; i++ {
 		orderer1.EnqueueSendBroadcastError(errors.New("Service Unavailable"))
@@ -279,6 +329,87 @@ func testSendTransaction(reqCtx reqContext.Context, orderers []fab.Orderer, t *t
 	}
 }
 
+type auditSink struct {
+	records []*audit.Record
+}
+
+func (s *auditSink) Record(record *audit.Record) {
+	s.records = append(s.records, record)
+}
+
+// auditInfraProvider is an infra provider with an audit sink
+type auditInfraProvider struct {
+	*mocks.MockInfraProvider
+	sink audit.Sink
+}
+
+func (p *auditInfraProvider) AuditSink() audit.Sink {
+	return p.sink
+}
+
+func TestSendTransactionAudit(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	sink := &auditSink{}
+	ctx.SetCustomInfraProvider(&auditInfraProvider{MockInfraProvider: &mocks.MockInfraProvider{}, sink: sink})
+
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second))
+	defer cancel()
+
+	orderer := mocks.NewMockOrderer("", nil)
+	txn := fab.Transaction{
+		Proposal: &fab.TransactionProposal{
+			Proposal: &pb.Proposal{Header: []byte(""), Payload: []byte(""), Extension: []byte("")},
+		},
+		Transaction: &pb.Transaction{},
+	}
+	_, err := Send(reqCtx, &txn, []fab.Orderer{orderer})
+	assert.NoError(t, err)
+
+	assert.Len(t, sink.records, 2)
+	assert.Equal(t, audit.Signed, sink.records[0].Action)
+	assert.Equal(t, audit.PurposeEnvelope, sink.records[0].Purpose)
+	assert.Equal(t, "1234", sink.records[0].MSPID)
+	assert.Equal(t, audit.Submitted, sink.records[1].Action)
+	assert.Equal(t, orderer.URL(), sink.records[1].Endpoint)
+	assert.Equal(t, sink.records[0].Digest, sink.records[1].Digest, "expecting the submitted envelope to be the signed one")
+}
+
+type sha3Context struct {
+	*mocks.MockContext
+	cryptoSuite core.CryptoSuite
+}
+
+func (c *sha3Context) CryptoSuite() core.CryptoSuite {
+	return c.cryptoSuite
+}
+
+func TestNewHeaderWithHashingAlgorithm(t *testing.T) {
+	cryptoSuite, err := sw.GetSuiteWithDefaultEphemeral()
+	assert.NoError(t, err)
+
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := &sha3Context{MockContext: mocks.NewMockContext(user), cryptoSuite: cryptoSuite}
+
+	txh, err := NewHeader(ctx, "test", WithHashingAlgorithm("SHA3_256"))
+	assert.NoError(t, err)
+
+	h := sha3.New256()
+	h.Write(txh.Nonce())
+	h.Write(txh.Creator())
+	assert.Equal(t, hex.EncodeToString(h.Sum(nil)), string(txh.TransactionID()))
+
+	txh, err = NewHeader(ctx, "test")
+	assert.NoError(t, err)
+
+	digest := sha256.Sum256(append(append([]byte{}, txh.Nonce()...), txh.Creator()...))
+	assert.Equal(t, hex.EncodeToString(digest[:]), string(txh.TransactionID()), "expecting SHA256 by default")
+
+	_, err = NewHeader(ctx, "test", WithHashingAlgorithm("SHA2"))
+	assert.Error(t, err, "expecting error for unsupported hashing algorithm")
+}
+
 func TestBuildChannelHeader(t *testing.T) {
 	user := mspmocks.NewMockSigningIdentity("test", "1234")
 	ctx := mocks.NewMockContext(user)
@@ -352,3 +483,125 @@ func setupMassiveTestOrderer(numberOfOrderers int) []fab.Orderer {
 
 	return orderers
 }
+
+func TestNewTransactionPayload(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	for _, transientMap := range []map[string][]byte{nil, {"key": []byte("secret")}} {
+		request := newTestTransactionRequest(t, ctx, transientMap)
+
+		tx, err := New(request)
+		assert.NoError(t, err)
+
+		// The transaction must be the same as the one created from the unmarshalled proposal
+		hdr, err := protos_utils.GetHeader(request.Proposal.Header)
+		assert.NoError(t, err)
+		pPayl, err := protos_utils.GetChaincodeProposalPayload(request.Proposal.Payload)
+		assert.NoError(t, err)
+		propPayloadBytes, err := protos_utils.GetBytesProposalPayloadForTx(pPayl, nil)
+		assert.NoError(t, err)
+
+		capBytes, err := protos_utils.GetBytesChaincodeActionPayload(&pb.ChaincodeActionPayload{
+			ChaincodeProposalPayload: propPayloadBytes,
+			Action: &pb.ChaincodeEndorsedAction{
+				ProposalResponsePayload: request.ProposalResponses[0].ProposalResponse.Payload,
+				Endorsements:            []*pb.Endorsement{request.ProposalResponses[0].ProposalResponse.Endorsement},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, hdr.SignatureHeader, tx.Transaction.Actions[0].Header)
+		assert.Equal(t, capBytes, tx.Transaction.Actions[0].Payload)
+		assert.NotContains(t, string(tx.Transaction.Actions[0].Payload), "secret")
+
+		// The payload must be the same as the one marshalled from the unmarshalled header
+		txBytes, err := protos_utils.GetBytesTransaction(tx.Transaction)
+		assert.NoError(t, err)
+		expectedPayload, err := proto.Marshal(&common.Payload{Header: hdr, Data: txBytes})
+		assert.NoError(t, err)
+		payload, err := transactionPayload(request.Proposal.Header, tx.Transaction)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPayload, payload)
+	}
+}
+
+func newTestTransactionRequest(t testing.TB, ctx *mocks.MockContext, transientMap map[string][]byte) fab.TransactionRequest {
+	txh, err := NewHeader(ctx, testChannel)
+	if err != nil {
+		t.Fatalf("create transaction header failed: %s", err)
+	}
+
+	args := [][]byte{[]byte("a"), []byte("b"), make([]byte, 4096)}
+	tp, err := CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{ChaincodeID: "example", Fcn: "invoke", Args: args, TransientMap: transientMap})
+	if err != nil {
+		t.Fatalf("create proposal failed: %s", err)
+	}
+
+	proposalResp := &fab.TransactionProposalResponse{
+		Endorser: "http://peer1.com",
+		ProposalResponse: &pb.ProposalResponse{
+			Response:    &pb.Response{Status: 200},
+			Payload:     make([]byte, 1024),
+			Endorsement: &pb.Endorsement{Endorser: []byte("endorser"), Signature: []byte("signature")},
+		},
+	}
+
+	return fab.TransactionRequest{Proposal: tp, ProposalResponses: []*fab.TransactionProposalResponse{proposalResp}}
+}
+
+func BenchmarkCreateProposal(b *testing.B) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+	args := [][]byte{[]byte("a"), []byte("b"), make([]byte, 4096)}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		txh, err := NewHeader(ctx, testChannel)
+		if err != nil {
+			b.Fatal(err)
+		}
+		tp, err := CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{ChaincodeID: "example", Fcn: "invoke", Args: args})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := signProposal(ctx, tp.Proposal); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNewTransaction(b *testing.B) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+	request := newTestTransactionRequest(b, ctx, nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := New(request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSendTransaction(b *testing.B) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+	tx, err := New(newTestTransactionRequest(b, ctx, nil))
+	if err != nil {
+		b.Fatal(err)
+	}
+	orderers := []fab.Orderer{mocks.NewMockOrderer("", nil)}
+
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second))
+	defer cancel()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Send(reqCtx, tx, orderers); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package warmstart allows cached state (such as the channel configuration and the discovered peers)
+// to be persisted in a key-value store so that a restarted client may serve requests immediately with
+// the last known state while the state is refreshed in the background.
+package warmstart
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/fab")
+
+// RetryInterval is the interval at which a reference which holds a persisted value
+// is refreshed until the refresh succeeds
+var RetryInterval = time.Second
+
+// Loader decodes a persisted value
+type Loader func(data []byte) (interface{}, error)
+
+// NewRef returns a lazy reference which is initialized with the given initializer and is refreshed at the
+// given interval (see lazyref.WithRefreshInterval). If a value for the key is persisted in the store then
+// the reference is first initialized with the persisted value (decoded with the loader) and is refreshed
+// with the initializer in the background. Note that the initializer is responsible for persisting the
+// value (see Save).
+func NewRef(store core.KVStore, key string, load Loader, initializer lazyref.Initializer, initialInit, refresh time.Duration) *lazyref.Reference {
+	ref := &warmRef{
+		key:         key,
+		load:        load,
+		initializer: initializer,
+		refresh:     refresh,
+	}
+
+	if data, err := Load(store, key); err != nil {
+		logger.Warnf("Error loading persisted value for [%s]: %s", key, err)
+	} else if data != nil {
+		ref.data = data
+		ref.warm = 1
+	}
+
+	return lazyref.New(
+		ref.initialize,
+		lazyref.WithRefreshInterval(initialInit, refresh),
+		lazyref.WithExpirationProvider(ref.expiration, lazyref.Refreshing),
+	)
+}
+
+type warmRef struct {
+	key         string
+	load        Loader
+	initializer lazyref.Initializer
+	refresh     time.Duration
+	mutex       sync.Mutex
+	data        []byte
+	warm        int32
+}
+
+func (r *warmRef) initialize() (interface{}, error) {
+	if data := r.takeData(); data != nil {
+		value, err := r.load(data)
+		if err == nil {
+			logger.Debugf("Initialized [%s] with persisted value", r.key)
+			return value, nil
+		}
+		logger.Warnf("Error decoding persisted value for [%s]: %s", r.key, err)
+		atomic.StoreInt32(&r.warm, 0)
+	}
+
+	value, err := r.initializer()
+	if err != nil {
+		return nil, err
+	}
+
+	if atomic.CompareAndSwapInt32(&r.warm, 1, 0) {
+		logger.Debugf("Refreshed persisted value for [%s]", r.key)
+	}
+	return value, nil
+}
+
+// takeData returns the persisted data the first time it is called and nil thereafter
+func (r *warmRef) takeData() []byte {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	data := r.data
+	r.data = nil
+	return data
+}
+
+// expiration returns the retry interval while the reference holds the persisted value
+// and the refresh interval thereafter
+func (r *warmRef) expiration() time.Duration {
+	if atomic.LoadInt32(&r.warm) == 1 {
+		return RetryInterval
+	}
+	return r.refresh
+}
+
+// Load returns the value for the key in the store or nil if the store doesn't contain the key
+func Load(store core.KVStore, key string) ([]byte, error) {
+	value, err := store.Load(key)
+	if err != nil {
+		if err == core.ErrKeyValueNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	data, ok := value.([]byte)
+	if !ok {
+		return nil, errors.Errorf("unexpected value type for [%s]: %T", key, value)
+	}
+	return data, nil
+}
+
+// Save persists the value for the key in the store. Errors are logged since a failure to
+// persist the value doesn't affect the client.
+func Save(store core.KVStore, key string, data []byte) {
+	if err := store.Store(key, data); err != nil {
+		logger.Warnf("Error persisting value for [%s]: %s", key, err)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package warmstart
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const key = "chconfig/mychannel"
+
+func newStore(t *testing.T) (*keyvaluestore.FileKeyValueStore, func()) {
+	path, err := ioutil.TempDir("", "warmstart")
+	require.NoError(t, err)
+
+	store, err := keyvaluestore.New(&keyvaluestore.FileKeyValueStoreOptions{Path: path})
+	require.NoError(t, err)
+
+	return store, func() { os.RemoveAll(path) }
+}
+
+func load(data []byte) (interface{}, error) {
+	if string(data) == "invalid" {
+		return nil, errors.New("invalid data")
+	}
+	return string(data), nil
+}
+
+func TestNewRefColdStart(t *testing.T) {
+	store, cleanup := newStore(t)
+	defer cleanup()
+
+	ref := NewRef(store, key, load, func() (interface{}, error) {
+		Save(store, key, []byte("live"))
+		return "live", nil
+	}, lazyref.InitOnFirstAccess, time.Minute)
+	defer ref.Close()
+
+	value, err := ref.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "live", value)
+
+	data, err := Load(store, key)
+	require.NoError(t, err)
+	assert.Equal(t, "live", string(data))
+}
+
+func TestNewRefWarmStart(t *testing.T) {
+	store, cleanup := newStore(t)
+	defer cleanup()
+
+	defer func(interval time.Duration) { RetryInterval = interval }(RetryInterval)
+	RetryInterval = 50 * time.Millisecond
+
+	Save(store, key, []byte("persisted"))
+
+	attempts := 0
+	ref := NewRef(store, key, load, func() (interface{}, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("peers are unavailable")
+		}
+		return "live", nil
+	}, lazyref.InitOnFirstAccess, time.Minute)
+	defer ref.Close()
+
+	value, err := ref.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "persisted", value, "expecting persisted value before the refresh")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for value != "live" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		value, err = ref.Get()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, "live", value, "expecting the value to be refreshed in the background")
+	assert.Equal(t, 2, attempts, "expecting a failed refresh to be retried")
+}
+
+func TestNewRefInvalidData(t *testing.T) {
+	store, cleanup := newStore(t)
+	defer cleanup()
+
+	Save(store, key, []byte("invalid"))
+
+	ref := NewRef(store, key, load, func() (interface{}, error) {
+		return "live", nil
+	}, lazyref.InitOnFirstAccess, time.Minute)
+	defer ref.Close()
+
+	value, err := ref.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "live", value)
+}
+
+func TestLoadNotFound(t *testing.T) {
+	store, cleanup := newStore(t)
+	defer cleanup()
+
+	data, err := Load(store, "unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+}
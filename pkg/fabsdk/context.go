@@ -15,6 +15,8 @@ type identityOptions struct {
 	signingIdentity msp.SigningIdentity
 	orgName         string
 	username        string
+	cert            []byte
+	privateKey      []byte
 }
 
 // ContextOption provides parameters for creating a session (primarily from a fabric identity/user)
@@ -36,6 +38,17 @@ func WithIdentity(signingIdentity msp.SigningIdentity) ContextOption {
 	}
 }
 
+// WithCredentials creates the identity for the session from the given PEM-encoded certificate and private key
+// (in the organization given by WithOrg or the client's organization) without using the credential store or
+// the MSP directories. If the private key is nil then it must be available in the key store of the crypto suite.
+func WithCredentials(cert, privateKey []byte) ContextOption {
+	return func(o *identityOptions) error {
+		o.cert = cert
+		o.privateKey = privateKey
+		return nil
+	}
+}
+
 // WithOrg uses the named organization
 func WithOrg(org string) ContextOption {
 	return func(o *identityOptions) error {
@@ -60,7 +73,7 @@ func (sdk *FabricSDK) newIdentity(options ...ContextOption) (msp.SigningIdentity
 		}
 	}
 
-	if opts.signingIdentity == nil && opts.username == "" {
+	if opts.signingIdentity == nil && opts.username == "" && opts.cert == nil {
 		return nil, ErrAnonymousIdentity
 	}
 
@@ -68,7 +81,7 @@ func (sdk *FabricSDK) newIdentity(options ...ContextOption) (msp.SigningIdentity
 		return opts.signingIdentity, nil
 	}
 
-	if opts.username == "" || opts.orgName == "" {
+	if (opts.username == "" && opts.cert == nil) || opts.orgName == "" {
 		return nil, errors.New("invalid options to create identity")
 	}
 
@@ -77,6 +90,10 @@ func (sdk *FabricSDK) newIdentity(options ...ContextOption) (msp.SigningIdentity
 		return nil, errors.New("invalid options to create identity, invalid org name")
 	}
 
+	if opts.cert != nil {
+		return mgr.CreateSigningIdentity(msp.WithCert(opts.cert), msp.WithPrivateKey(opts.privateKey))
+	}
+
 	user, err := mgr.GetSigningIdentity(opts.username)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/dynamicdiscovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/chpvdr"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
+	"github.com/pkg/errors"
+)
+
+// publishMutex guards the check for an existing expvar variable and the publishing of the variable
+var publishMutex sync.Mutex
+
+// Diagnostics is a snapshot of the internal state of the SDK which is intended for troubleshooting
+type Diagnostics struct {
+	// Time is the time at which the snapshot was taken
+	Time time.Time
+	// Connections are the open GRPC connections to peers and orderers
+	Connections []comm.ConnectionInfo
+	// Caches contains the size and the hit rate of the channel provider's caches
+	Caches []lazycache.Stats
+	// EventClients contains the state and the registrations of the event clients
+	EventClients []chpvdr.EventClientInfo
+	// Discovery contains the time of the last refresh of the discovered peers of each channel
+	Discovery []dynamicdiscovery.RefreshState
+}
+
+type connectionsProvider interface {
+	Connections() []comm.ConnectionInfo
+}
+
+type diagnosticsProvider interface {
+	Diagnostics() *chpvdr.Diagnostics
+}
+
+// Diagnostics returns a snapshot of the internal state of the SDK. The state is only available for the
+// default infra and channel providers (custom providers may implement the Connections and Diagnostics functions).
+func (sdk *FabricSDK) Diagnostics() *Diagnostics {
+	d := &Diagnostics{Time: time.Now()}
+
+	if p, ok := sdk.provider.InfraProvider().(connectionsProvider); ok {
+		d.Connections = p.Connections()
+	}
+
+	if p, ok := sdk.provider.ChannelProvider().(diagnosticsProvider); ok {
+		chDiagnostics := p.Diagnostics()
+		d.Caches = chDiagnostics.Caches
+		d.EventClients = chDiagnostics.EventClients
+		d.Discovery = chDiagnostics.Discovery
+	}
+
+	return d
+}
+
+// DiagnosticsHandler returns an HTTP handler which responds with the diagnostics of the SDK in JSON format.
+// The handler should only be exposed on an internal (admin) listener.
+func (sdk *FabricSDK) DiagnosticsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(sdk.Diagnostics()); err != nil {
+			logger.Warnf("Error writing diagnostics: %s", err)
+		}
+	})
+}
+
+// PublishDiagnostics publishes the diagnostics of the SDK as an expvar variable with the given name so
+// that it's included in /debug/vars. An error is returned if a variable with the name is already published
+// (expvar variables can't be removed, so each SDK instance requires a distinct name).
+func (sdk *FabricSDK) PublishDiagnostics(name string) error {
+	publishMutex.Lock()
+	defer publishMutex.Unlock()
+
+	if expvar.Get(name) != nil {
+		return errors.Errorf("expvar variable [%s] is already published", name)
+	}
+
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return sdk.Diagnostics()
+	}))
+	return nil
+}
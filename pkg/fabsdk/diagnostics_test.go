@@ -0,0 +1,57 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+)
+
+func TestDiagnosticsHandler(t *testing.T) {
+	sdk, err := New(configImpl.FromFile(sdkConfigFile))
+	if err != nil {
+		t.Fatalf("Error initializing SDK: %s", err)
+	}
+	defer sdk.Close()
+
+	rec := httptest.NewRecorder()
+	sdk.DiagnosticsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/diagnostics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Unexpected status: %d", rec.Code)
+	}
+
+	var d Diagnostics
+	if err := json.Unmarshal(rec.Body.Bytes(), &d); err != nil {
+		t.Fatalf("Error unmarshalling diagnostics: %s", err)
+	}
+	if d.Time.IsZero() {
+		t.Fatal("Expected time of diagnostics to be set")
+	}
+	if len(d.Caches) == 0 {
+		t.Fatal("Expected cache statistics of the channel provider")
+	}
+}
+
+func TestPublishDiagnostics(t *testing.T) {
+	sdk, err := New(configImpl.FromFile(sdkConfigFile))
+	if err != nil {
+		t.Fatalf("Error initializing SDK: %s", err)
+	}
+	defer sdk.Close()
+
+	if err := sdk.PublishDiagnostics("fabsdk_test_diagnostics"); err != nil {
+		t.Fatalf("Error publishing diagnostics: %s", err)
+	}
+	if err := sdk.PublishDiagnostics("fabsdk_test_diagnostics"); err == nil {
+		t.Fatal("Expected error publishing diagnostics with a duplicate name")
+	}
+}
@@ -9,6 +9,7 @@ package fabsdk
 
 import (
 	"math/rand"
+	"sync"
 	"time"
 
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
@@ -22,9 +23,13 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/audit"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	sdkApi "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
 	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/random"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 )
 
 var logger = logging.NewLogger("fabsdk")
@@ -34,6 +39,8 @@ type FabricSDK struct {
 	opts        options
 	provider    *context.Provider
 	cryptoSuite core.CryptoSuite
+	logBinding  *logging.Binding
+	logger      *logging.Logger
 }
 
 type configs struct {
@@ -51,6 +58,12 @@ type options struct {
 	endpointConfig    fab.EndpointConfig
 	IdentityConfig    msp.IdentityConfig
 	ConfigBackend     []core.ConfigBackend
+	warmStartStore    core.KVStore
+	auditSink         audit.Sink
+	interceptors      []func(i *comm.Interceptors)
+	instanceLogger    api.LoggerProvider
+	cryptoSuites      *cryptoSuiteCache
+	randomSource      rand.Source
 }
 
 // Option configures the SDK.
@@ -60,6 +73,18 @@ type closeable interface {
 	Close()
 }
 
+type warmStartStoreSetter interface {
+	SetWarmStartStore(store core.KVStore)
+}
+
+type auditSinkSetter interface {
+	SetAuditSink(sink audit.Sink)
+}
+
+type commManagerWrapper interface {
+	WrapCommManager(wrap func(commManager fab.CommManager) fab.CommManager)
+}
+
 // New initializes the SDK based on the set of options provided.
 // ConfigOptions provides the application configuration.
 func New(configProvider core.ConfigProvider, opts ...Option) (*FabricSDK, error) {
@@ -149,6 +174,49 @@ func WithIdentityConfig(identityConfigs ...interface{}) Option {
 	}
 }
 
+// WithWarmStartStore persists the channel configuration and the discovered peers in the given store
+// (e.g. a file key-value store) so that, after a restart, requests may be served immediately with the
+// last known topology while the topology is refreshed in the background.
+func WithWarmStartStore(store core.KVStore) Option {
+	return func(opts *options) error {
+		opts.warmStartStore = store
+		return nil
+	}
+}
+
+// WithAuditSink passes a record of every signature produced by the SDK and of every envelope submitted
+// to an orderer to the given sink (see package audit). The sink only receives the records of this SDK instance.
+func WithAuditSink(sink audit.Sink) Option {
+	return func(opts *options) error {
+		opts.auditSink = sink
+		return nil
+	}
+}
+
+// WithUnaryInterceptor adds a GRPC unary client interceptor to the connections to the given target (host:port)
+// or, if the target is comm.AllEndpoints, to the connections to all peers and orderers. For example, an
+// interceptor may add an auth token or custom metadata to the outgoing calls.
+func WithUnaryInterceptor(target string, interceptor grpc.UnaryClientInterceptor) Option {
+	return func(opts *options) error {
+		opts.interceptors = append(opts.interceptors, func(i *comm.Interceptors) {
+			i.AddUnaryInterceptor(target, interceptor)
+		})
+		return nil
+	}
+}
+
+// WithStreamInterceptor adds a GRPC stream client interceptor (e.g. for the deliver streams of the event service)
+// to the connections to the given target (host:port) or, if the target is comm.AllEndpoints, to the connections
+// to all peers and orderers.
+func WithStreamInterceptor(target string, interceptor grpc.StreamClientInterceptor) Option {
+	return func(opts *options) error {
+		opts.interceptors = append(opts.interceptors, func(i *comm.Interceptors) {
+			i.AddStreamInterceptor(target, interceptor)
+		})
+		return nil
+	}
+}
+
 // WithCorePkg injects the core implementation into the SDK.
 func WithCorePkg(core sdkApi.CoreProviderFactory) Option {
 	return func(opts *options) error {
@@ -181,6 +249,33 @@ func WithLoggerPkg(logger api.LoggerProvider) Option {
 	}
 }
 
+// WithInstanceLoggerPkg binds the loggers of the SDK instance to the given logger provider (e.g. a
+// modlog.NewProvider with its own output and log levels) rather than to the process-wide provider.
+// The provider may be swapped later with the SDK's LogBinding.
+func WithInstanceLoggerPkg(logger api.LoggerProvider) Option {
+	return func(opts *options) error {
+		opts.instanceLogger = logger
+		return nil
+	}
+}
+
+// WithRandomSeed makes the choice of peers and orderers (target selection, load balancing, etc.)
+// deterministic by seeding the source of randomness with the given seed, which is useful for
+// reproducing a bug or for integration tests. Note that the source is shared by all SDK instances in
+// the process. By default the source is seeded from crypto/rand.
+func WithRandomSeed(seed int64) Option {
+	return WithRandomSource(rand.NewSource(seed))
+}
+
+// WithRandomSource injects the source of randomness used for choosing peers and orderers
+// (see WithRandomSeed).
+func WithRandomSource(src rand.Source) Option {
+	return func(opts *options) error {
+		opts.randomSource = src
+		return nil
+	}
+}
+
 // providerInit interface allows for initializing providers
 // TODO: minimize interface
 type providerInit interface {
@@ -201,6 +296,13 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 	}
 	logging.Initialize(sdk.opts.Logger)
 
+	if sdk.opts.instanceLogger != nil {
+		sdk.logBinding = logging.NewBinding(sdk.opts.instanceLogger)
+	} else {
+		sdk.logBinding = logging.NewBinding(sdk.opts.Logger)
+	}
+	sdk.logger = sdk.logBinding.NewLogger("fabsdk")
+
 	//Initialize configs if not passed through options
 	cfg, err := sdk.loadConfigs(configProvider)
 	if err != nil {
@@ -209,6 +311,9 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 
 	// Initialize rand (TODO: should probably be optional)
 	rand.Seed(time.Now().UnixNano())
+	if sdk.opts.randomSource != nil {
+		random.SetSource(sdk.opts.randomSource)
+	}
 
 	// Initialize state store
 	userStore, err := sdk.opts.MSP.CreateUserStore(cfg.identityConfig)
@@ -234,6 +339,28 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		return errors.WithMessage(err, "failed to create infra provider")
 	}
 
+	if sdk.opts.auditSink != nil {
+		setter, ok := infraProvider.(auditSinkSetter)
+		if !ok {
+			return errors.New("infra provider does not support an audit sink")
+		}
+		setter.SetAuditSink(sdk.opts.auditSink)
+	}
+
+	if len(sdk.opts.interceptors) > 0 {
+		wrapper, ok := infraProvider.(commManagerWrapper)
+		if !ok {
+			return errors.New("infra provider does not support GRPC interceptors")
+		}
+		wrapper.WrapCommManager(func(commManager fab.CommManager) fab.CommManager {
+			interceptors := comm.NewInterceptors(commManager)
+			for _, add := range sdk.opts.interceptors {
+				add(interceptors)
+			}
+			return interceptors
+		})
+	}
+
 	// Initialize local discovery provider
 	localDiscoveryProvider, err := sdk.opts.Service.CreateLocalDiscoveryProvider(cfg.endpointConfig)
 	if err != nil {
@@ -245,6 +372,14 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		return errors.WithMessage(err, "failed to create channel provider")
 	}
 
+	if sdk.opts.warmStartStore != nil {
+		setter, ok := channelProvider.(warmStartStoreSetter)
+		if !ok {
+			return errors.New("channel provider does not support a warm-start store")
+		}
+		setter.SetWarmStartStore(sdk.opts.warmStartStore)
+	}
+
 	//update sdk providers list since all required providers are initialized
 	sdk.provider = context.NewProvider(context.WithCryptoSuiteConfig(cfg.cryptoSuiteConfig),
 		context.WithEndpointConfig(cfg.endpointConfig),
@@ -284,20 +419,30 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 
 // Close frees up caches and connections being maintained by the SDK
 func (sdk *FabricSDK) Close() {
-	logger.Debug("Closing SDK... checking if local discovery provider is closable...")
+	sdk.logger.Debug("Closing SDK... checking if local discovery provider is closable...")
 	if pvdr, ok := sdk.provider.LocalDiscoveryProvider().(closeable); ok {
-		logger.Debug("... closing local discovery provider")
+		sdk.logger.Debug("... closing local discovery provider")
 		pvdr.Close()
 	}
-	logger.Debug("... checking if channel provider is closable...")
+	sdk.logger.Debug("... checking if channel provider is closable...")
 	if pvdr, ok := sdk.provider.ChannelProvider().(closeable); ok {
-		logger.Debug("... closing channel provider")
+		sdk.logger.Debug("... closing channel provider")
 		pvdr.Close()
 	}
-	logger.Debug("... closing infra provider")
+	sdk.logger.Debug("... closing infra provider")
 	sdk.provider.InfraProvider().Close()
 }
 
+// LogBinding returns the logging binding of the SDK instance, which may be used for swapping its logger provider
+func (sdk *FabricSDK) LogBinding() *logging.Binding {
+	return sdk.logBinding
+}
+
+// Logger returns a logger for the given module which is bound to the logger provider of the SDK instance
+func (sdk *FabricSDK) Logger(module string) *logging.Logger {
+	return sdk.logBinding.NewLogger(module)
+}
+
 //Config returns config backend used by all SDK config types
 func (sdk *FabricSDK) Config() (core.ConfigBackend, error) {
 	if sdk.opts.ConfigBackend == nil {
@@ -334,10 +479,42 @@ func (sdk *FabricSDK) ChannelContext(channelID string, options ...ContextOption)
 	return channelProvider
 }
 
+//MultiChannelContext creates and returns a provider of channel contexts for any channel. The client context
+//(and therefore the identity) is created once and shared by the channel contexts of all channels.
+func (sdk *FabricSDK) MultiChannelContext(options ...ContextOption) contextApi.MultiChannelProvider {
+	var mutex sync.Mutex
+	var client contextApi.Client
+
+	// The client context is only cached once it has been created successfully
+	clientCtxProvider := func() (contextApi.Client, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if client != nil {
+			return client, nil
+		}
+
+		c, err := sdk.Context(options...)()
+		if err != nil {
+			return nil, err
+		}
+		client = c
+		return client, nil
+	}
+
+	return func(channelID string) (contextApi.Channel, error) {
+		return context.NewChannel(clientCtxProvider, channelID)
+	}
+}
+
 // initializeCryptoSuite Initializes crypto provider
 func (sdk *FabricSDK) initializeCryptoSuite(cryptoSuiteConfig core.CryptoSuiteConfig) error {
 	var err error
-	sdk.cryptoSuite, err = sdk.opts.Core.CreateCryptoSuiteProvider(cryptoSuiteConfig)
+	if sdk.opts.cryptoSuites != nil {
+		sdk.cryptoSuite, err = sdk.opts.cryptoSuites.get(cryptoSuiteConfig, sdk.opts.Core.CreateCryptoSuiteProvider)
+	} else {
+		sdk.cryptoSuite, err = sdk.opts.Core.CreateCryptoSuiteProvider(cryptoSuiteConfig)
+	}
 	if err != nil {
 		return errors.WithMessage(err, "failed to initialize crypto suite")
 	}
@@ -0,0 +1,171 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+// NetworkManager hosts multiple independent SDK instances in one process, each of which is connected to a
+// different Fabric network (or consortium) and is registered under a network name. The crypto suite is shared
+// between the networks whose crypto suite configs are identical (including the key store path); all other
+// providers (connections, caches, user stores, etc.) are owned by each SDK instance.
+type NetworkManager struct {
+	mutex        sync.RWMutex
+	networks     map[string]*FabricSDK
+	cryptoSuites *cryptoSuiteCache
+	closed       bool
+}
+
+// NewNetworkManager returns a new, empty network manager
+func NewNetworkManager() *NetworkManager {
+	return &NetworkManager{
+		networks:     make(map[string]*FabricSDK),
+		cryptoSuites: newCryptoSuiteCache(),
+	}
+}
+
+// Add creates an SDK instance for the network with the given config and options and registers it under the
+// given name
+//  Parameters:
+//  name is the unique name of the network
+//  configProvider provides the config of the network
+//  opts are the options of the SDK instance
+//
+//  Returns:
+//  the SDK instance of the network
+func (m *NetworkManager) Add(name string, configProvider core.ConfigProvider, opts ...Option) (*FabricSDK, error) {
+	if name == "" {
+		return nil, errors.New("network name is required")
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.closed {
+		return nil, errors.New("network manager is closed")
+	}
+	if _, ok := m.networks[name]; ok {
+		return nil, errors.Errorf("network [%s] already exists", name)
+	}
+
+	sdk, err := New(configProvider, append(opts, withCryptoSuiteCache(m.cryptoSuites))...)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to create SDK for network [%s]", name))
+	}
+
+	logger.Debugf("Added network [%s]", name)
+	m.networks[name] = sdk
+
+	return sdk, nil
+}
+
+// Network returns the SDK instance of the network with the given name
+func (m *NetworkManager) Network(name string) (*FabricSDK, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	sdk, ok := m.networks[name]
+	if !ok {
+		return nil, errors.Errorf("network [%s] not found", name)
+	}
+	return sdk, nil
+}
+
+// Networks returns the names of the registered networks in alphabetical order
+func (m *NetworkManager) Networks() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var names []string
+	for name := range m.networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Remove closes the SDK instance of the network with the given name and unregisters it
+func (m *NetworkManager) Remove(name string) error {
+	m.mutex.Lock()
+	sdk, ok := m.networks[name]
+	delete(m.networks, name)
+	m.mutex.Unlock()
+
+	if !ok {
+		return errors.Errorf("network [%s] not found", name)
+	}
+
+	logger.Debugf("Closing network [%s]", name)
+	sdk.Close()
+	return nil
+}
+
+// Close closes the SDK instances of all networks. No networks may be added after the manager is closed.
+func (m *NetworkManager) Close() {
+	m.mutex.Lock()
+	networks := m.networks
+	m.networks = make(map[string]*FabricSDK)
+	m.closed = true
+	m.mutex.Unlock()
+
+	for name, sdk := range networks {
+		logger.Debugf("Closing network [%s]", name)
+		sdk.Close()
+	}
+}
+
+// cryptoSuiteCache holds the crypto suites keyed by their crypto suite config
+type cryptoSuiteCache struct {
+	mutex  sync.Mutex
+	suites map[string]core.CryptoSuite
+}
+
+func newCryptoSuiteCache() *cryptoSuiteCache {
+	return &cryptoSuiteCache{suites: make(map[string]core.CryptoSuite)}
+}
+
+// get returns the crypto suite for the given config, which is created if no crypto suite with an
+// identical config exists
+func (c *cryptoSuiteCache) get(config core.CryptoSuiteConfig, create func(config core.CryptoSuiteConfig) (core.CryptoSuite, error)) (core.CryptoSuite, error) {
+	key := cryptoSuiteKey(config)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if suite, ok := c.suites[key]; ok {
+		logger.Debugf("Sharing existing crypto suite")
+		return suite, nil
+	}
+
+	suite, err := create(config)
+	if err != nil {
+		return nil, err
+	}
+	c.suites[key] = suite
+	return suite, nil
+}
+
+func cryptoSuiteKey(config core.CryptoSuiteConfig) string {
+	return fmt.Sprintf("%t|%s|%d|%s|%t|%s|%s|%s|%s",
+		config.IsSecurityEnabled(), config.SecurityAlgorithm(), config.SecurityLevel(), config.SecurityProvider(),
+		config.SoftVerify(), config.SecurityProviderLibPath(), config.SecurityProviderPin(),
+		config.SecurityProviderLabel(), config.KeyStorePath())
+}
+
+// withCryptoSuiteCache shares the crypto suite with the other SDK instances which use the same cache
+func withCryptoSuiteCache(cache *cryptoSuiteCache) Option {
+	return func(opts *options) error {
+		opts.cryptoSuites = cache
+		return nil
+	}
+}
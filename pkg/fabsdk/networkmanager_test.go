@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"reflect"
+	"testing"
+
+	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+)
+
+func TestNetworkManager(t *testing.T) {
+	manager := NewNetworkManager()
+	defer manager.Close()
+
+	sdk1, err := manager.Add("network1", configImpl.FromFile(sdkConfigFile))
+	if err != nil {
+		t.Fatalf("Error adding network: %s", err)
+	}
+	sdk2, err := manager.Add("network2", configImpl.FromFile(sdkConfigFile))
+	if err != nil {
+		t.Fatalf("Error adding network: %s", err)
+	}
+
+	if _, err := manager.Add("network1", configImpl.FromFile(sdkConfigFile)); err == nil {
+		t.Fatal("Expected error adding duplicate network")
+	}
+
+	if sdk1.cryptoSuite != sdk2.cryptoSuite {
+		t.Fatal("Expected crypto suite to be shared between networks with the same crypto config")
+	}
+	if sdk1.provider.InfraProvider() == sdk2.provider.InfraProvider() {
+		t.Fatal("Expected networks to have their own infra providers")
+	}
+
+	sdk, err := manager.Network("network2")
+	if err != nil {
+		t.Fatalf("Error getting network: %s", err)
+	}
+	if sdk != sdk2 {
+		t.Fatal("Unexpected SDK returned for network")
+	}
+
+	if names := manager.Networks(); !reflect.DeepEqual(names, []string{"network1", "network2"}) {
+		t.Fatalf("Unexpected networks: %v", names)
+	}
+
+	if err := manager.Remove("network1"); err != nil {
+		t.Fatalf("Error removing network: %s", err)
+	}
+	if _, err := manager.Network("network1"); err == nil {
+		t.Fatal("Expected error getting removed network")
+	}
+}
+
+func TestNetworkManagerErrors(t *testing.T) {
+	manager := NewNetworkManager()
+
+	if _, err := manager.Add("", configImpl.FromFile(sdkConfigFile)); err == nil {
+		t.Fatal("Expected error adding network without name")
+	}
+	if _, err := manager.Add("network1", configImpl.FromFile("notarealfile")); err == nil {
+		t.Fatal("Expected error adding network with bad config")
+	}
+	if _, err := manager.Network("network1"); err == nil {
+		t.Fatal("Expected error getting network which failed to be added")
+	}
+	if err := manager.Remove("network1"); err == nil {
+		t.Fatal("Expected error removing unknown network")
+	}
+
+	manager.Close()
+	if _, err := manager.Add("network1", configImpl.FromFile(sdkConfigFile)); err == nil {
+		t.Fatal("Expected error adding network after close")
+	}
+}
@@ -55,7 +55,7 @@ type eventCacheKey struct {
 
 // newEventCacheKey returns a new eventCacheKey
 func newEventCacheKey(ctx fab.ClientContext, chConfig fab.ChannelCfg, opts ...options.Opt) (*eventCacheKey, error) {
-	identity, err := ctx.Serialize()
+	identity, err := eventClientIdentity(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -77,6 +77,15 @@ func newEventCacheKey(ctx fab.ClientContext, chConfig fab.ChannelCfg, opts ...op
 	}, nil
 }
 
+// eventClientIdentity returns the identity portion of the event service cache key. If connections are shared
+// then the MSP ID is used so that all clients of the organization share the same event service.
+func eventClientIdentity(ctx fab.ClientContext) ([]byte, error) {
+	if ctx.EndpointConfig().EventServiceConfig().ShareConnections() {
+		return []byte("msp:" + ctx.Identifier().MSPID), nil
+	}
+	return ctx.Serialize()
+}
+
 // Opts returns the options to use for creating events service
 func (k *eventCacheKey) Opts() []options.Opt {
 	return k.opts
@@ -84,6 +93,7 @@ func (k *eventCacheKey) Opts() []options.Opt {
 
 type params struct {
 	permitBlockEvents bool
+	exactlyOnce       bool
 }
 
 func defaultParams() *params {
@@ -94,8 +104,15 @@ func (p *params) PermitBlockEvents() {
 	p.permitBlockEvents = true
 }
 
+func (p *params) SetExactlyOnceDispatch(value bool) {
+	p.exactlyOnce = value
+}
+
 func (p *params) getOptKey() string {
 	//	Construct opts portion
 	optKey := "blockEvents:" + strconv.FormatBool(p.permitBlockEvents)
+	if p.exactlyOnce {
+		optKey += ",exactlyOnce:true"
+	}
 	return optKey
 }
@@ -8,19 +8,24 @@ package chpvdr
 
 import (
 	reqContext "context"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/dynamicdiscovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/staticdiscovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection/pgresolver"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/fabricselection"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/staticselection"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	channelImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel/membership"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peerstats"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
 	"github.com/pkg/errors"
 )
@@ -43,6 +48,8 @@ type ChannelProvider struct {
 	selectionServiceCache cache
 	chCfgCache            cache
 	membershipCache       cache
+	chConfigRefresh       time.Duration
+	warmStartStore        core.KVStore
 }
 
 // New creates a ChannelProvider based on a context
@@ -54,6 +61,7 @@ func New(config fab.EndpointConfig) (*ChannelProvider, error) {
 	cp := ChannelProvider{
 		chCfgCache:      chconfig.NewRefCache(chConfigRefresh),
 		membershipCache: membership.NewRefCache(membershipRefresh),
+		chConfigRefresh: chConfigRefresh,
 	}
 
 	cp.discoveryServiceCache = lazycache.New(
@@ -76,12 +84,14 @@ func New(config fab.EndpointConfig) (*ChannelProvider, error) {
 		"Event_Service_Cache",
 		func(key lazycache.Key) (interface{}, error) {
 			ck := key.(*eventCacheKey)
-			return NewEventClientRef(
+			ref := NewEventClientRef(
 				eventIdleTime,
 				func() (fab.EventClient, error) {
 					return cp.createEventClient(ck.context, ck.channelConfig, ck.opts...)
 				},
-			), nil
+			)
+			ref.channelID = ck.channelConfig.ID()
+			return ref, nil
 		},
 	)
 
@@ -94,6 +104,14 @@ func (cp *ChannelProvider) Initialize(providers context.Providers) error {
 	return nil
 }
 
+// SetWarmStartStore sets the store in which the channel configuration and the discovered peers are persisted.
+// This function must be called before any channel service is used.
+func (cp *ChannelProvider) SetWarmStartStore(store core.KVStore) {
+	cp.warmStartStore = store
+	cp.chCfgCache.Close()
+	cp.chCfgCache = chconfig.NewRefCache(cp.chConfigRefresh, chconfig.WithWarmStartStore(store))
+}
+
 // Close frees resources and caches.
 func (cp *ChannelProvider) Close() {
 	logger.Debug("Closing event service cache...")
@@ -134,6 +152,10 @@ func (cp *ChannelProvider) createEventClient(ctx context.Client, chConfig fab.Ch
 }
 
 func (cp *ChannelProvider) createDiscoveryService(ctx context.Client, chConfig fab.ChannelCfg) (fab.DiscoveryService, error) {
+	if isObserver(ctx, chConfig.ID()) {
+		logger.Debugf("Using Static Discovery for observer of channel [%s].", chConfig.ID())
+		return staticdiscovery.NewService(ctx.EndpointConfig(), ctx.InfraProvider(), chConfig.ID())
+	}
 	if chConfig.HasCapability(fab.ApplicationGroupKey, fab.V1_2Capability) {
 		logger.Debugf("Using Dynamic Discovery based on V1_2 capability.")
 		cs := ChannelService{
@@ -145,7 +167,11 @@ func (cp *ChannelProvider) createDiscoveryService(ctx context.Client, chConfig f
 		if err != nil {
 			return nil, errors.WithMessage(err, "failed to create discovery service")
 		}
-		return dynamicdiscovery.NewChannelService(ctx, membership, chConfig.ID())
+		var opts []options.Opt
+		if cp.warmStartStore != nil {
+			opts = append(opts, dynamicdiscovery.WithWarmStartStore(cp.warmStartStore))
+		}
+		return dynamicdiscovery.NewChannelService(ctx, membership, chConfig.ID(), opts...)
 	}
 	return staticdiscovery.NewService(ctx.EndpointConfig(), ctx.InfraProvider(), chConfig.ID())
 }
@@ -172,11 +198,51 @@ func (cp *ChannelProvider) createSelectionService(ctx context.Client, chConfig f
 		return nil, err
 	}
 
+	if isObserver(ctx, chConfig.ID()) {
+		logger.Debugf("Using Static Selection for observer of channel [%s].", chConfig.ID())
+		return staticselection.NewService(discovery)
+	}
+
+	balancer := fab.Random
+	preferLocalOrg := false
+	if chSdkCfg, ok := ctx.EndpointConfig().ChannelConfig(chConfig.ID()); ok {
+		if chSdkCfg.Policies.Selection.Balancer != "" {
+			balancer = chSdkCfg.Policies.Selection.Balancer
+		}
+		preferLocalOrg = chSdkCfg.Policies.Selection.PreferLocalOrg
+	}
+
 	if chConfig.HasCapability(fab.ApplicationGroupKey, fab.V1_2Capability) {
 		logger.Debugf("Using Fabric Selection based on V1_2 capability.")
-		return fabricselection.New(ctx, chConfig.ID(), discovery)
+		var opts []options.Opt
+		if balancer == fab.Weighted {
+			opts = append(opts, fabricselection.WithPrioritySelector(peerstats.Default().Compare))
+		}
+		if preferLocalOrg {
+			opts = append(opts, fabricselection.WithLocalOrgPreference(ctx.Identifier().MSPID))
+		}
+		return fabricselection.New(ctx, chConfig.ID(), discovery, opts...)
+	}
+
+	if preferLocalOrg {
+		logger.Warnf("Ignoring local org preference of channel [%s] since it requires Fabric Selection (V1_2 capability)", chConfig.ID())
 	}
-	return dynamicselection.NewService(ctx, chConfig.ID(), discovery)
+
+	var opts []dynamicselection.Opt
+	switch balancer {
+	case fab.RoundRobin:
+		opts = append(opts, dynamicselection.WithLoadBalancePolicy(pgresolver.NewRoundRobinLBP()))
+	case fab.Weighted:
+		opts = append(opts, dynamicselection.WithLoadBalancePolicy(pgresolver.NewWeightedLBP(peerstats.Default())))
+	}
+	return dynamicselection.NewService(ctx, chConfig.ID(), discovery, opts...)
+}
+
+// isObserver returns true if the observer policy is enabled for the channel, i.e. the client's
+// organization isn't a member of the channel
+func isObserver(ctx context.Client, channelID string) bool {
+	chSdkCfg, ok := ctx.EndpointConfig().ChannelConfig(channelID)
+	return ok && chSdkCfg.Policies.Observer.Enabled
 }
 
 func (cp *ChannelProvider) getSelectionService(context fab.ClientContext, channelID string) (fab.SelectionService, error) {
@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/staticdiscovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/fabricselection"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/staticselection"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -113,3 +114,138 @@ func TestBasicValidChannel(t *testing.T) {
 	_, ok = selection.(*fabricselection.Service)
 	assert.Truef(t, ok, "Expecting selection to be Fabric for v1_2")
 }
+
+func TestObserverChannel(t *testing.T) {
+	ctx := mocks.NewMockProviderContext()
+	ctx.EndpointConfig().(*mocks.MockConfig).ChannelObserver = fab.ObserverPolicy{Enabled: true}
+
+	clientCtx := &mockClientContext{
+		Providers:       ctx,
+		SigningIdentity: mspmocks.NewMockSigningIdentity("user", "user"),
+	}
+
+	cp, err := New(clientCtx.EndpointConfig())
+	require.NoError(t, err)
+	require.NoError(t, cp.Initialize(ctx))
+
+	testChannelCfg := mocks.NewMockChannelCfg("testchannel")
+	testChannelCfg.MockCapabilities[fab.ApplicationGroupKey][fab.V1_2Capability] = true
+	mockChConfigCache := newMockChCfgCache(chconfig.NewChannelCfg(""))
+	mockChConfigCache.Put(testChannelCfg)
+	cp.chCfgCache = mockChConfigCache
+
+	// Dynamic discovery and Fabric selection aren't used by an observer, even with v1_2 capabilities
+	channelService, err := cp.ChannelService(clientCtx, "testchannel")
+	require.NoError(t, err)
+
+	discovery, err := channelService.Discovery()
+	require.NoError(t, err)
+	_, ok := discovery.(*staticdiscovery.DiscoveryService)
+	assert.Truef(t, ok, "Expecting discovery to be Static for observer")
+
+	selection, err := channelService.Selection()
+	require.NoError(t, err)
+	_, ok = selection.(*staticselection.SelectionService)
+	assert.Truef(t, ok, "Expecting selection to be Static for observer")
+}
+
+type mockSigningIdentity struct {
+	*mspmocks.MockSigningIdentity
+	serializedID string
+}
+
+func (m *mockSigningIdentity) Serialize() ([]byte, error) {
+	return []byte(m.serializedID), nil
+}
+
+func TestEventServiceSharing(t *testing.T) {
+	newClientCtx := func(shared bool, id, mspID string) *mockClientContext {
+		ctx := mocks.NewMockProviderContext()
+		config := mocks.NewMockEndpointConfig().(*mocks.MockConfig)
+		config.EvtServiceConfig = &mocks.MockEventServiceConfig{SharedConnections: shared}
+		ctx.SetEndpointConfig(config)
+		return &mockClientContext{
+			Providers:       ctx,
+			SigningIdentity: &mockSigningIdentity{MockSigningIdentity: mspmocks.NewMockSigningIdentity(id, mspID), serializedID: mspID + "/" + id},
+		}
+	}
+
+	newEventService := func(cp *ChannelProvider, ctx *mockClientContext) fab.EventService {
+		channelService, err := cp.ChannelService(ctx, "mychannel")
+		require.NoError(t, err)
+		eventService, err := channelService.EventService()
+		require.NoError(t, err)
+		return eventService
+	}
+
+	newChannelProvider := func(ctx *mockClientContext) *ChannelProvider {
+		cp, err := New(ctx.EndpointConfig())
+		require.NoError(t, err)
+		require.NoError(t, cp.Initialize(ctx))
+		cp.chCfgCache = newMockChCfgCache(chconfig.NewChannelCfg("mychannel"))
+		return cp
+	}
+
+	user1 := newClientCtx(false, "user1", "Org1MSP")
+	user2 := newClientCtx(false, "user2", "Org1MSP")
+	cp := newChannelProvider(user1)
+	defer cp.Close()
+	assert.True(t, newEventService(cp, user1) == newEventService(cp, user1), "expecting the same event service for the same identity")
+	assert.False(t, newEventService(cp, user1) == newEventService(cp, user2), "expecting separate event services for different identities")
+
+	user1 = newClientCtx(true, "user1", "Org1MSP")
+	user2 = newClientCtx(true, "user2", "Org1MSP")
+	user3 := newClientCtx(true, "user3", "Org2MSP")
+	cp = newChannelProvider(user1)
+	defer cp.Close()
+	assert.True(t, newEventService(cp, user1) == newEventService(cp, user2), "expecting a shared event service for identities of the same organization")
+	assert.False(t, newEventService(cp, user1) == newEventService(cp, user3), "expecting separate event services for different organizations")
+}
+
+func TestDiagnostics(t *testing.T) {
+	ctx := mocks.NewMockProviderContext()
+
+	clientCtx := &mockClientContext{
+		Providers:       ctx,
+		SigningIdentity: mspmocks.NewMockSigningIdentity("user", "user"),
+	}
+
+	cp, err := New(clientCtx.EndpointConfig())
+	require.NoError(t, err)
+	require.NoError(t, cp.Initialize(ctx))
+	defer cp.Close()
+
+	testChannelCfg := mocks.NewMockChannelCfg("testchannel")
+	testChannelCfg.MockCapabilities[fab.ApplicationGroupKey][fab.V1_2Capability] = true
+	mockChConfigCache := newMockChCfgCache(chconfig.NewChannelCfg(""))
+	mockChConfigCache.Put(chconfig.NewChannelCfg("mychannel"))
+	mockChConfigCache.Put(testChannelCfg)
+	cp.chCfgCache = mockChConfigCache
+
+	channelService, err := cp.ChannelService(clientCtx, "testchannel")
+	require.NoError(t, err)
+	_, err = channelService.Discovery()
+	require.NoError(t, err)
+
+	channelService, err = cp.ChannelService(clientCtx, "mychannel")
+	require.NoError(t, err)
+	_, err = channelService.EventService()
+	require.NoError(t, err)
+
+	d := cp.Diagnostics()
+
+	// The mock channel config cache doesn't provide statistics
+	assert.Len(t, d.Caches, 4)
+	for _, stats := range d.Caches {
+		if stats.Name == "Discovery_Service_Cache" {
+			assert.Equal(t, 1, stats.Size, "expecting only the discovery service of testchannel")
+		}
+	}
+
+	require.Len(t, d.Discovery, 1, "expecting only the dynamic discovery service of testchannel")
+	assert.Equal(t, "testchannel", d.Discovery[0].ChannelID)
+
+	require.Len(t, d.EventClients, 1)
+	assert.Equal(t, "mychannel", d.EventClients[0].ChannelID)
+	assert.False(t, d.EventClients[0].Connected, "event client shouldn't be connected before it's used")
+}
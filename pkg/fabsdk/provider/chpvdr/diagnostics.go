@@ -0,0 +1,129 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chpvdr
+
+import (
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/dynamicdiscovery"
+	esdispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
+)
+
+// Diagnostics is a snapshot of the internal state of the channel provider
+type Diagnostics struct {
+	// Caches contains the size and the hit rate of each cache
+	Caches []lazycache.Stats
+	// EventClients contains the state of the cached event clients
+	EventClients []EventClientInfo
+	// Discovery contains the freshness of the dynamic discovery services
+	Discovery []dynamicdiscovery.RefreshState
+}
+
+// EventClientInfo describes a cached event client
+type EventClientInfo struct {
+	ChannelID string
+	// Connected is false if the event client was closed because it was idle (it is reconnected on demand)
+	Connected bool
+	// Unavailable is true if the event client didn't respond, e.g. because its dispatcher is blocked
+	Unavailable bool
+	// PeerURL is the URL of the peer to which the event client is connected
+	PeerURL       string
+	LastBlockNum  uint64
+	LastEventTime time.Time
+	Registrations *esdispatcher.RegistrationInfo
+	// Error contains the errors which occurred while gathering the state of the event client
+	Error string
+}
+
+type statsProvider interface {
+	Stats() lazycache.Stats
+}
+
+type rangeProvider interface {
+	Range(f func(key string, value interface{}) bool)
+}
+
+type registrationInfoProvider interface {
+	RegistrationInfo() (*esdispatcher.RegistrationInfo, error)
+}
+
+type refreshStateProvider interface {
+	RefreshState() dynamicdiscovery.RefreshState
+}
+
+// Diagnostics returns a snapshot of the internal state of the channel provider (cache statistics, event
+// clients and discovery freshness). Event clients which were closed because they were idle aren't reconnected.
+func (cp *ChannelProvider) Diagnostics() *Diagnostics {
+	d := &Diagnostics{}
+
+	for _, c := range []cache{cp.chCfgCache, cp.membershipCache, cp.discoveryServiceCache, cp.selectionServiceCache, cp.eventServiceCache} {
+		if sp, ok := c.(statsProvider); ok {
+			d.Caches = append(d.Caches, sp.Stats())
+		}
+	}
+
+	if rp, ok := cp.discoveryServiceCache.(rangeProvider); ok {
+		rp.Range(func(key string, value interface{}) bool {
+			if sp, ok := value.(refreshStateProvider); ok {
+				d.Discovery = append(d.Discovery, sp.RefreshState())
+			}
+			return true
+		})
+	}
+	sort.Slice(d.Discovery, func(i, j int) bool { return d.Discovery[i].ChannelID < d.Discovery[j].ChannelID })
+
+	// The event clients are queried outside of Range so that an unresponsive client doesn't block the cache
+	var refs []*EventClientRef
+	if rp, ok := cp.eventServiceCache.(rangeProvider); ok {
+		rp.Range(func(key string, value interface{}) bool {
+			if ref, ok := value.(*EventClientRef); ok && !ref.Closed() {
+				refs = append(refs, ref)
+			}
+			return true
+		})
+	}
+	for _, ref := range refs {
+		d.EventClients = append(d.EventClients, ref.info())
+	}
+	sort.Slice(d.EventClients, func(i, j int) bool { return d.EventClients[i].ChannelID < d.EventClients[j].ChannelID })
+
+	return d
+}
+
+func (ref *EventClientRef) info() EventClientInfo {
+	info := EventClientInfo{ChannelID: ref.channelID}
+
+	client := ref.connectedClient()
+	if client == nil {
+		return info
+	}
+	info.Connected = true
+
+	if rp, ok := client.(registrationInfoProvider); ok {
+		regInfo, err := rp.RegistrationInfo()
+		if err != nil {
+			info.Unavailable = true
+			info.Error = err.Error()
+			return info
+		}
+		info.Registrations = regInfo
+	}
+
+	if sp, ok := client.(streamStateProvider); ok {
+		state, err := sp.StreamState()
+		if err != nil {
+			info.Error = err.Error()
+		} else {
+			info.PeerURL = state.PeerURL
+			info.LastBlockNum = state.LastBlockNum
+			info.LastEventTime = state.LastEventTime
+		}
+	}
+	return info
+}
@@ -7,10 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package chpvdr
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
 	"github.com/pkg/errors"
 )
@@ -30,7 +32,9 @@ type EventClientRef struct {
 	ref         *lazyref.Reference
 	provider    eventClientProvider
 	eventClient fab.EventClient
+	clientLock  sync.RWMutex
 	closed      int32
+	channelID   string
 }
 
 // NewEventClientRef returns a new EventClientRef
@@ -113,6 +117,36 @@ func (ref *EventClientRef) Unregister(reg fab.Registration) {
 	}
 }
 
+// StreamState returns the current state of the event client's stream.
+func (ref *EventClientRef) StreamState() (*dispatcher.StreamState, error) {
+	service, err := ref.get()
+	if err != nil {
+		return nil, err
+	}
+	stateProvider, ok := service.(streamStateProvider)
+	if !ok {
+		return nil, errors.Errorf("stream state is not supported by event client of type %T", service)
+	}
+	return stateProvider.StreamState()
+}
+
+type streamStateProvider interface {
+	StreamState() (*dispatcher.StreamState, error)
+}
+
+// connectedClient returns the current event client without creating one (nil if there's none)
+func (ref *EventClientRef) connectedClient() fab.EventClient {
+	ref.clientLock.RLock()
+	defer ref.clientLock.RUnlock()
+	return ref.eventClient
+}
+
+func (ref *EventClientRef) setEventClient(eventClient fab.EventClient) {
+	ref.clientLock.Lock()
+	defer ref.clientLock.Unlock()
+	ref.eventClient = eventClient
+}
+
 func (ref *EventClientRef) get() (fab.EventService, error) {
 	if ref.Closed() {
 		return nil, errors.New("event client is closed")
@@ -141,7 +175,7 @@ func (ref *EventClientRef) initializer() lazyref.Initializer {
 		if err := eventClient.Connect(); err != nil {
 			return nil, err
 		}
-		ref.eventClient = eventClient
+		ref.setEventClient(eventClient)
 		logger.Debug("...event client successfully connected.")
 		return eventClient, nil
 	}
@@ -160,7 +194,7 @@ func (ref *EventClientRef) finalizer() lazyref.Finalizer {
 				// Only close the client if there are not outstanding registrations
 				if ref.eventClient.CloseIfIdle() {
 					logger.Debug("... closed event client.")
-					ref.eventClient = nil
+					ref.setEventClient(nil)
 				} else {
 					logger.Debug("... event client was not closed since there are outstanding registrations.")
 				}
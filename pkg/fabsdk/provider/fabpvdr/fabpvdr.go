@@ -10,6 +10,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/audit"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
 	peerImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
@@ -22,16 +23,50 @@ var logger = logging.NewLogger("fabsdk")
 type InfraProvider struct {
 	providerContext context.Providers
 	commManager     *comm.CachingConnector
+	commWrapper     fab.CommManager
+	auditSink       audit.Sink
+}
+
+// Opt is an InfraProvider option
+type Opt func(*InfraProvider)
+
+// WithCommManagerWrapper wraps the default (caching) comm manager with the comm manager
+// returned by the given function. For example, a comm.FaultInjector may be installed in
+// order to test the application's handling of failures.
+func WithCommManagerWrapper(wrap func(commManager fab.CommManager) fab.CommManager) Opt {
+	return func(f *InfraProvider) {
+		f.commWrapper = wrap(f.commManager)
+	}
+}
+
+// WrapCommManager wraps the comm manager (which may already be wrapped) with the comm manager returned
+// by the given function. It must be invoked before any connections are created.
+func (f *InfraProvider) WrapCommManager(wrap func(commManager fab.CommManager) fab.CommManager) {
+	f.commWrapper = wrap(f.CommManager())
+}
+
+// SetAuditSink sets the sink which receives the audit records of the SDK (see package audit)
+func (f *InfraProvider) SetAuditSink(sink audit.Sink) {
+	f.auditSink = sink
+}
+
+// AuditSink returns the sink which receives the audit records of the SDK (nil if auditing is disabled)
+func (f *InfraProvider) AuditSink() audit.Sink {
+	return f.auditSink
 }
 
 // New creates a InfraProvider enabling access to core Fabric objects and functionality.
-func New(config fab.EndpointConfig) *InfraProvider {
+func New(config fab.EndpointConfig, opts ...Opt) *InfraProvider {
 	idleTime := config.Timeout(fab.ConnectionIdle)
 	sweepTime := config.Timeout(fab.CacheSweepInterval)
 
-	return &InfraProvider{
+	f := &InfraProvider{
 		commManager: comm.NewCachingConnector(sweepTime, idleTime),
 	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
 // Initialize sets the provider context
@@ -46,8 +81,16 @@ func (f *InfraProvider) Close() {
 	f.commManager.Close()
 }
 
+// Connections returns information about the cached GRPC connections to peers and orderers
+func (f *InfraProvider) Connections() []comm.ConnectionInfo {
+	return f.commManager.Connections()
+}
+
 // CommManager provides comm support such as GRPC onnections
 func (f *InfraProvider) CommManager() fab.CommManager {
+	if f.commWrapper != nil {
+		return f.commWrapper
+	}
 	return f.commManager
 }
 
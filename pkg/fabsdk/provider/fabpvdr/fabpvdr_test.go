@@ -18,6 +18,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	peerImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
@@ -28,6 +29,26 @@ func TestCreateInfraProvider(t *testing.T) {
 	newInfraProvider(t)
 }
 
+func TestCommManagerWrapper(t *testing.T) {
+	p := newInfraProvider(t)
+	defer p.Close()
+
+	if _, ok := p.CommManager().(*comm.CachingConnector); !ok {
+		t.Fatal("Expecting caching connector as default comm manager")
+	}
+
+	var injector *comm.FaultInjector
+	wp := New(p.providerContext.EndpointConfig(), WithCommManagerWrapper(func(commManager fab.CommManager) fab.CommManager {
+		injector = comm.NewFaultInjector(commManager)
+		return injector
+	}))
+	defer wp.Close()
+
+	if wp.CommManager() != injector {
+		t.Fatal("Expecting wrapped comm manager")
+	}
+}
+
 func verifyPeer(t *testing.T, peer fab.Peer, url string) {
 	_, ok := peer.(*peerImpl.Peer)
 	if !ok {
@@ -17,7 +17,7 @@ var (
 
 // CAClient provides management of identities in a Fabric network
 type CAClient interface {
-	Enroll(enrollmentID string, enrollmentSecret string) error
+	Enroll(request *EnrollmentRequest) error
 	Reenroll(enrollmentID string) error
 	Register(request *RegistrationRequest) (string, error)
 	Revoke(request *RevocationRequest) (*RevocationResponse, error)
@@ -34,6 +34,17 @@ type AttributeRequest struct {
 	Optional bool
 }
 
+// EnrollmentRequest is a request to enroll an identity
+type EnrollmentRequest struct {
+	// The identity name to enroll
+	Name string
+	// The secret returned via Register
+	Secret string
+	// AttrReqs are requests for attributes to add to the certificate.
+	// Each attribute is added only if the requestor owns the attribute.
+	AttrReqs []*AttributeRequest
+}
+
 // RegistrationRequest defines the attributes required to register a user with the CA
 type RegistrationRequest struct {
 	// Name is the unique name of the identity
@@ -77,6 +88,8 @@ type RevocationRequest struct {
 	Reason string
 	// CAName is the name of the CA to connect to
 	CAName string
+	// GenCRL requests the CA to return a CRL with all unexpired revoked certificates in the response
+	GenCRL bool
 }
 
 // RevocationResponse represents response from the server for a revocation request
@@ -93,27 +93,26 @@ func NewCAClient(orgName string, ctx contextApi.Client) (*CAClientImpl, error) {
 // enrollment certificate issued by the CA are stored in SDK stores.
 // They can be retrieved by calling IdentityManager.GetSigningIdentity().
 //
-// enrollmentID The registered ID to use for enrollment
-// enrollmentSecret The secret associated with the enrollment ID
-func (c *CAClientImpl) Enroll(enrollmentID string, enrollmentSecret string) error {
+// request holds the registered ID and secret to use for enrollment, and the
+// attributes to request for the enrollment certificate
+func (c *CAClientImpl) Enroll(request *api.EnrollmentRequest) error {
 
 	if c.adapter == nil {
 		return fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
-	if enrollmentID == "" {
+	if request == nil || request.Name == "" {
 		return errors.New("enrollmentID is required")
 	}
-	if enrollmentSecret == "" {
+	if request.Secret == "" {
 		return errors.New("enrollmentSecret is required")
 	}
-	// TODO add attributes
-	cert, err := c.adapter.Enroll(enrollmentID, enrollmentSecret)
+	cert, err := c.adapter.Enroll(request)
 	if err != nil {
 		return errors.Wrap(err, "enroll failed")
 	}
 	userData := &msp.UserData{
 		MSPID: c.orgMSPID,
-		ID:    enrollmentID,
+		ID:    request.Name,
 		EnrollmentCertificate: cert,
 	}
 	err = c.userStore.Store(userData)
@@ -362,7 +361,7 @@ func (c *CAClientImpl) getRegistrar(enrollID string, enrollSecret string) (msp.S
 		}
 
 		// Attempt to enroll the registrar
-		err = c.Enroll(enrollID, enrollSecret)
+		err = c.Enroll(&api.EnrollmentRequest{Name: enrollID, Secret: enrollSecret})
 		if err != nil {
 			return nil, err
 		}
@@ -44,15 +44,17 @@ func newFabricCAAdapter(orgName string, cryptoSuite core.CryptoSuite, config msp
 }
 
 // Enroll handles enrollment.
-func (c *fabricCAAdapter) Enroll(enrollmentID string, enrollmentSecret string) ([]byte, error) {
+func (c *fabricCAAdapter) Enroll(request *api.EnrollmentRequest) ([]byte, error) {
 
-	logger.Debugf("Enrolling user [%s]", enrollmentID)
+	logger.Debugf("Enrolling user [%s]", request.Name)
 
-	// TODO add attributes
 	careq := &caapi.EnrollmentRequest{
 		CAName: c.caClient.Config.CAName,
-		Name:   enrollmentID,
-		Secret: enrollmentSecret,
+		Name:   request.Name,
+		Secret: request.Secret,
+	}
+	for _, attrReq := range request.AttrReqs {
+		careq.AttrReqs = append(careq.AttrReqs, &caapi.AttributeRequest{Name: attrReq.Name, Optional: attrReq.Optional})
 	}
 	caresp, err := c.caClient.Enroll(careq)
 	if err != nil {
@@ -127,6 +129,7 @@ func (c *fabricCAAdapter) Revoke(key core.Key, cert []byte, request *api.Revocat
 		Serial: request.Serial,
 		AKI:    request.AKI,
 		Reason: request.Reason,
+		GenCRL: request.GenCRL,
 	}
 
 	registrar, err := c.newIdentity(key, cert)
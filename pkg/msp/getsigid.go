@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"strings"
 
@@ -67,6 +70,76 @@ func (mgr *IdentityManager) GetSigningIdentity(id string) (msp.SigningIdentity,
 	return user, nil
 }
 
+// CreateSigningIdentity creates a signing identity for the org from the given certificate and private key
+// without loading or storing the credentials in the user store or the MSP directories
+func (mgr *IdentityManager) CreateSigningIdentity(opts ...msp.SigningIdentityOption) (msp.SigningIdentity, error) {
+	opt := msp.IdentityOption{}
+	for _, param := range opts {
+		if err := param(&opt); err != nil {
+			return nil, errors.WithMessage(err, "failed to create identity")
+		}
+	}
+
+	return NewSigningIdentity(mgr.orgMSPID, opt.Cert, opt.PrivateKey, mgr.cryptoSuite)
+}
+
+// NewSigningIdentity creates a signing identity in the given MSP from the given PEM-encoded certificate and
+// private key without an identity manager. If the private key is nil then it must be available in the key
+// store of the crypto suite.
+func NewSigningIdentity(mspID string, cert, privateKey []byte, cryptoSuite core.CryptoSuite) (msp.SigningIdentity, error) {
+	if mspID == "" {
+		return nil, errors.New("missing MSP ID")
+	}
+	if len(cert) == 0 {
+		return nil, errors.New("missing certificate")
+	}
+
+	id, err := commonName(cert)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := cryptoutil.GetPublicKeyFromCert(cert, cryptoSuite)
+	if err != nil {
+		return nil, errors.WithMessage(err, "fetching public key from cert failed")
+	}
+
+	var key core.Key
+	if len(privateKey) == 0 {
+		key, err = cryptoSuite.GetKey(pubKey.SKI())
+		if err != nil {
+			return nil, errors.WithMessage(err, "private key not provided and not found in the key store")
+		}
+	} else {
+		key, err = fabricCaUtil.ImportBCCSPKeyFromPEMBytes(privateKey, cryptoSuite, true)
+		if err != nil {
+			return nil, errors.Wrap(err, "import private key failed")
+		}
+		if !bytes.Equal(key.SKI(), pubKey.SKI()) {
+			return nil, errors.New("private key does not match the certificate")
+		}
+	}
+
+	return &User{
+		id:    id,
+		mspID: mspID,
+		enrollmentCertificate: cert,
+		privateKey:            key,
+	}, nil
+}
+
+func commonName(cert []byte) (string, error) {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return "", errors.New("certificate is not PEM encoded")
+	}
+	x509Cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", errors.Wrap(err, "parse certificate failed")
+	}
+	return x509Cert.Subject.CommonName, nil
+}
+
 // GetUser returns a user for the given user name
 func (mgr *IdentityManager) GetUser(username string) (*User, error) { //nolint
 
@@ -96,6 +96,59 @@ func TestGetSigningIdentity(t *testing.T) {
 	enrollUser1(cryptoSuite, t, mspID, testUsername, userStore, mgr)
 }
 
+func TestCreateSigningIdentity(t *testing.T) {
+	cryptoSuite, err := sw.GetSuiteWithDefaultEphemeral()
+	if err != nil {
+		t.Fatalf("Failed to setup cryptoSuite: %s", err)
+	}
+	mgr := &IdentityManager{orgName: orgName, orgMSPID: "Org1MSP", cryptoSuite: cryptoSuite}
+
+	if _, err = mgr.CreateSigningIdentity(); err == nil {
+		t.Fatal("Should have failed to create signing identity without certificate")
+	}
+
+	if _, err = mgr.CreateSigningIdentity(msp.WithCert([]byte(testCert))); err == nil {
+		t.Fatal("Should have failed to create signing identity without private key (not in key store)")
+	}
+
+	if _, err = mgr.CreateSigningIdentity(msp.WithCert([]byte("invalid")), msp.WithPrivateKey([]byte(testPrivKey))); err == nil {
+		t.Fatal("Should have failed to create signing identity with invalid certificate")
+	}
+
+	identity, err := mgr.CreateSigningIdentity(msp.WithCert([]byte(testCert)), msp.WithPrivateKey([]byte(testPrivKey)))
+	if err != nil {
+		t.Fatalf("Failed to create signing identity: %s", err)
+	}
+	if identity.Identifier().ID != "User1@org1.example.com" || identity.Identifier().MSPID != "Org1MSP" {
+		t.Fatalf("Unexpected identifier: %#v", identity.Identifier())
+	}
+	if string(identity.EnrollmentCertificate()) != testCert {
+		t.Fatal("Unexpected enrollment certificate")
+	}
+	if identity.PrivateKey() == nil || !identity.PrivateKey().Private() {
+		t.Fatal("Expecting private key")
+	}
+}
+
+func TestNewSigningIdentity(t *testing.T) {
+	cryptoSuite, err := sw.GetSuiteWithDefaultEphemeral()
+	if err != nil {
+		t.Fatalf("Failed to setup cryptoSuite: %s", err)
+	}
+
+	if _, err = NewSigningIdentity("", []byte(testCert), []byte(testPrivKey), cryptoSuite); err == nil {
+		t.Fatal("Should have failed to create signing identity without MSP ID")
+	}
+
+	identity, err := NewSigningIdentity("Org2MSP", []byte(testCert), []byte(testPrivKey), cryptoSuite)
+	if err != nil {
+		t.Fatalf("Failed to create signing identity: %s", err)
+	}
+	if identity.Identifier().ID != "User1@org1.example.com" || identity.Identifier().MSPID != "Org2MSP" {
+		t.Fatalf("Unexpected identifier: %#v", identity.Identifier())
+	}
+}
+
 func getConfigs(t *testing.T) (core.CryptoSuiteConfig, providersFab.EndpointConfig, msp.IdentityConfig, providersFab.OrganizationConfig) {
 	configBackend, err := config.FromFile("../../pkg/core/config/testdata/config_test.yaml")()
 	if err != nil {
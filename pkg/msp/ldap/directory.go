@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ldap
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+)
+
+// Entry is an entry of the directory
+type Entry struct {
+	// DN is the distinguished name of the entry
+	DN string
+	// Attributes holds the values of the requested attributes by attribute name
+	Attributes map[string][][]byte
+}
+
+// value returns the first value of the attribute (attribute names are case insensitive)
+func (e *Entry) value(attribute string) []byte {
+	values := e.values(attribute)
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+func (e *Entry) values(attribute string) [][]byte {
+	for name, values := range e.Attributes {
+		if strings.EqualFold(name, attribute) {
+			return values
+		}
+	}
+	return nil
+}
+
+// Directory searches the entries of an LDAP directory. The SDK doesn't implement the LDAP protocol:
+// a Directory is backed by an LDAP client library (e.g. gopkg.in/ldap.v2, as used by Fabric CA), which
+// handles the connection, the bind and the transport security (ldaps or StartTLS).
+type Directory interface {
+	// Search returns the entries under the base DN which match the filter, with the requested attributes
+	Search(filter string, attributes []string) ([]*Entry, error)
+}
+
+// DirectoryFunc is an adapter to allow the use of a function as a Directory
+type DirectoryFunc func(filter string, attributes []string) ([]*Entry, error)
+
+// Search calls f(filter, attributes)
+func (f DirectoryFunc) Search(filter string, attributes []string) ([]*Entry, error) {
+	return f(filter, attributes)
+}
+
+// EscapeFilterValue escapes the characters that have a special meaning in a search filter (RFC 4515)
+func EscapeFilterValue(value string) string {
+	var escaped bytes.Buffer
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '*', '(', ')', '\\', 0:
+			escaped.WriteString(`\` + hex.EncodeToString([]byte{c}))
+		default:
+			escaped.WriteByte(c)
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,252 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ldap provides a user store that resolves users from an LDAP directory, so that the
+// identity manager can create signing identities of directory-managed users (e.g. users of a
+// Fabric CA server in LDAP mode) who were never registered in the SDK's user store.
+//
+// The directory is accessed through the Directory interface, which is implemented with an LDAP
+// client library. For example, with gopkg.in/ldap.v2:
+//
+//  directory := ldap.DirectoryFunc(func(filter string, attributes []string) ([]*ldap.Entry, error) {
+//  	conn, err := ldapv2.DialTLS("tcp", "ldap.example.org:636", tlsConfig)
+//  	...
+//  	result, err := conn.Search(ldapv2.NewSearchRequest(baseDN, ldapv2.ScopeWholeSubtree,
+//  		ldapv2.NeverDerefAliases, 2, 10, false, filter, attributes, nil))
+//  	...
+//  })
+//
+//  Basic Flow:
+//  1) Create the store with a directory, the LDAP settings of the Fabric CA server and a store for the enrolled users
+//  2) Return the store from the CreateUserStore function of a custom MSP provider factory
+//  3) Enroll directory users with their LDAP enrollment ID and password (see AttributeRequests)
+//  4) Create contexts with any name the user filter accepts (e.g. a mail address)
+package ldap
+
+import (
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/msp")
+
+// Defaults of the Fabric CA server LDAP configuration
+const (
+	DefaultUserFilter            = "(uid=%s)"
+	DefaultEnrollmentIDAttribute = "uid"
+	DefaultCertificateAttribute  = "userCertificate;binary"
+)
+
+// Config holds the LDAP settings, which match the user settings of the ldap section of the Fabric CA
+// server configuration. The connection settings are those of the Directory.
+type Config struct {
+	// UserFilter is the search filter of a user, where %s is replaced by the user name (default "(uid=%s)")
+	UserFilter string
+	// EnrollmentIDAttribute holds the enrollment ID of a user (default "uid")
+	EnrollmentIDAttribute string
+	// CertificateAttribute holds the DER or PEM encoded enrollment certificate of a user, if the
+	// directory publishes it (default "userCertificate;binary")
+	CertificateAttribute string
+	// AttributeNames are the LDAP attributes added to the user's attributes under the same name
+	AttributeNames []string
+	// Converters add attributes named by the keys with the value of the LDAP attributes named by the values
+	Converters map[string]string
+}
+
+// UserStore resolves users from an LDAP directory. The directory maps a user name to an enrollment
+// ID and attributes; the enrollment certificate is loaded from the store of enrolled users, or from
+// the directory if it publishes certificates. Users that aren't in the directory are loaded from
+// the store of enrolled users.
+type UserStore struct {
+	directory  Directory
+	config     Config
+	attributes []string
+	enrolled   msp.UserStore
+}
+
+// NewUserStore returns a user store backed by the LDAP directory
+//  Parameters:
+//  config holds the LDAP settings
+//  directory searches the LDAP directory
+//  enrolled stores the certificates of enrolled users (optional: without it, certificates are only
+//  read from the directory and Store fails)
+//
+//  Returns:
+//  the user store
+func NewUserStore(config *Config, directory Directory, enrolled msp.UserStore) (*UserStore, error) {
+	if directory == nil {
+		return nil, errors.New("LDAP directory is required")
+	}
+
+	var c Config
+	if config != nil {
+		c = *config
+	}
+	if c.UserFilter == "" {
+		c.UserFilter = DefaultUserFilter
+	}
+	if c.EnrollmentIDAttribute == "" {
+		c.EnrollmentIDAttribute = DefaultEnrollmentIDAttribute
+	}
+	if c.CertificateAttribute == "" {
+		c.CertificateAttribute = DefaultCertificateAttribute
+	}
+	if !strings.Contains(c.UserFilter, "%s") {
+		return nil, errors.Errorf("user filter [%s] must contain %%s", c.UserFilter)
+	}
+
+	attributes := []string{c.EnrollmentIDAttribute, c.CertificateAttribute}
+	attributes = append(attributes, c.AttributeNames...)
+	for _, ldapAttribute := range c.Converters {
+		attributes = append(attributes, ldapAttribute)
+	}
+
+	return &UserStore{directory: directory, config: c, attributes: attributes, enrolled: enrolled}, nil
+}
+
+// Store stores the enrollment certificate of a user in the store of enrolled users
+func (s *UserStore) Store(user *msp.UserData) error {
+	if s.enrolled == nil {
+		return errors.New("LDAP user store is read only")
+	}
+	return s.enrolled.Store(user)
+}
+
+// Load returns the user with the given name. The ID of the returned user is its enrollment ID.
+func (s *UserStore) Load(id msp.IdentityIdentifier) (*msp.UserData, error) {
+	e, err := s.lookup(id.ID)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		if s.enrolled == nil {
+			return nil, msp.ErrUserNotFound
+		}
+		return s.enrolled.Load(id)
+	}
+
+	enrollmentID := s.enrollmentID(id.ID, e)
+	if s.enrolled != nil {
+		userData, err := s.enrolled.Load(msp.IdentityIdentifier{MSPID: id.MSPID, ID: enrollmentID})
+		if err == nil {
+			return userData, nil
+		}
+		if err != msp.ErrUserNotFound {
+			return nil, err
+		}
+	}
+
+	cert := e.value(s.config.CertificateAttribute)
+	if len(cert) == 0 {
+		logger.Debugf("Directory user [%s] (enrollment ID [%s]) is not enrolled", id.ID, enrollmentID)
+		return nil, msp.ErrUserNotFound
+	}
+	if block, _ := pem.Decode(cert); block == nil {
+		cert = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})
+	}
+
+	return &msp.UserData{ID: enrollmentID, MSPID: id.MSPID, EnrollmentCertificate: cert}, nil
+}
+
+// EnrollmentID returns the enrollment ID of the directory user
+func (s *UserStore) EnrollmentID(username string) (string, error) {
+	e, err := s.lookup(username)
+	if err != nil {
+		return "", err
+	}
+	if e == nil {
+		return "", msp.ErrUserNotFound
+	}
+	return s.enrollmentID(username, e), nil
+}
+
+// Attributes returns the attributes of the directory user mapped from its LDAP attributes,
+// which Fabric CA in LDAP mode includes in the enrollment certificate when requested
+func (s *UserStore) Attributes(username string) ([]mspapi.Attribute, error) {
+	e, err := s.lookup(username)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, msp.ErrUserNotFound
+	}
+
+	var attributes []mspapi.Attribute
+	add := func(name, ldapAttribute string) {
+		values := e.values(ldapAttribute)
+		if len(values) == 0 {
+			return
+		}
+		var value []string
+		for _, v := range values {
+			value = append(value, string(v))
+		}
+		// Multiple values are joined as by Fabric CA
+		attributes = append(attributes, mspapi.Attribute{Name: name, Value: strings.Join(value, ","), ECert: true})
+	}
+	for _, name := range s.config.AttributeNames {
+		add(name, name)
+	}
+	var names []string
+	for name := range s.config.Converters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add(name, s.config.Converters[name])
+	}
+	return attributes, nil
+}
+
+// AttributeRequests returns the requests for the attributes of the directory user, to be passed
+// to the enrollment so that they are included in the enrollment certificate
+func (s *UserStore) AttributeRequests(username string) ([]*mspapi.AttributeRequest, error) {
+	attributes, err := s.Attributes(username)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []*mspapi.AttributeRequest
+	for _, a := range attributes {
+		requests = append(requests, &mspapi.AttributeRequest{Name: a.Name, Optional: true})
+	}
+	return requests, nil
+}
+
+func (s *UserStore) enrollmentID(username string, e *Entry) string {
+	if id := e.value(s.config.EnrollmentIDAttribute); len(id) > 0 {
+		return string(id)
+	}
+	return username
+}
+
+// lookup returns the directory entry of the user, or nil if the user isn't in the directory
+func (s *UserStore) lookup(username string) (*Entry, error) {
+	if username == "" {
+		return nil, nil
+	}
+
+	entries, err := s.directory.Search(fmt.Sprintf(s.config.UserFilter, EscapeFilterValue(username)), s.attributes)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to look up user [%s]", username))
+	}
+
+	switch len(entries) {
+	case 0:
+		return nil, nil
+	case 1:
+		return entries[0], nil
+	default:
+		return nil, errors.Errorf("user filter matches multiple entries for user [%s]", username)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ldap
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	mspimpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCert = "-----BEGIN CERTIFICATE-----\nY2VydA==\n-----END CERTIFICATE-----\n"
+
+// mockDirectory answers searches with an equality filter, e.g. (mail=jdoe@example.org)
+type mockDirectory struct {
+	entries []map[string][]string
+
+	mutex   sync.Mutex
+	filters []string
+}
+
+func newMockDirectory(entries ...map[string][]string) *mockDirectory {
+	return &mockDirectory{entries: entries}
+}
+
+func (d *mockDirectory) lastFilter() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.filters[len(d.filters)-1]
+}
+
+func (d *mockDirectory) Search(filter string, attributes []string) ([]*Entry, error) {
+	d.mutex.Lock()
+	d.filters = append(d.filters, filter)
+	d.mutex.Unlock()
+
+	item := strings.SplitN(strings.TrimSuffix(strings.TrimPrefix(filter, "("), ")"), "=", 2)
+	if len(item) != 2 {
+		return nil, errors.Errorf("unsupported filter [%s]", filter)
+	}
+
+	var entries []*Entry
+	for _, e := range d.entries {
+		if !contains(e[item[0]], item[1]) {
+			continue
+		}
+		entry := &Entry{DN: "uid=" + e["uid"][0], Attributes: make(map[string][][]byte)}
+		for _, name := range attributes {
+			for _, v := range e[name] {
+				entry.Attributes[name] = append(entry.Attributes[name], []byte(v))
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func newTestStore(t *testing.T, d Directory, enrolled msp.UserStore) *UserStore {
+	store, err := NewUserStore(&Config{
+		UserFilter:     "(mail=%s)",
+		AttributeNames: []string{"department"},
+		Converters:     map[string]string{"app.role": "employeeType"},
+	}, d, enrolled)
+	require.NoError(t, err)
+	return store
+}
+
+func TestLoad(t *testing.T) {
+	d := newMockDirectory(
+		map[string][]string{"uid": {"jdoe"}, "mail": {"john.doe@example.org"}, "department": {"sales", "marketing"}, "employeeType": {"manager"}},
+		map[string][]string{"uid": {"asmith"}, "mail": {"alice.smith@example.org"}, "userCertificate;binary": {"cert"}},
+	)
+
+	enrolled := mspimpl.NewMemoryUserStore()
+	require.NoError(t, enrolled.Store(&msp.UserData{ID: "jdoe", MSPID: "Org1MSP", EnrollmentCertificate: []byte(testCert)}))
+	require.NoError(t, enrolled.Store(&msp.UserData{ID: "Admin", MSPID: "Org1MSP", EnrollmentCertificate: []byte(testCert)}))
+
+	store := newTestStore(t, d, enrolled)
+
+	// Enrolled directory user
+	userData, err := store.Load(msp.IdentityIdentifier{MSPID: "Org1MSP", ID: "john.doe@example.org"})
+	require.NoError(t, err)
+	assert.Equal(t, "jdoe", userData.ID)
+	assert.Equal(t, []byte(testCert), userData.EnrollmentCertificate)
+	assert.Equal(t, "(mail=john.doe@example.org)", d.lastFilter())
+
+	// Directory user whose certificate is published in the directory
+	userData, err = store.Load(msp.IdentityIdentifier{MSPID: "Org1MSP", ID: "alice.smith@example.org"})
+	require.NoError(t, err)
+	assert.Equal(t, "asmith", userData.ID)
+	assert.Equal(t, testCert, string(userData.EnrollmentCertificate))
+
+	// User that isn't in the directory
+	userData, err = store.Load(msp.IdentityIdentifier{MSPID: "Org1MSP", ID: "Admin"})
+	require.NoError(t, err)
+	assert.Equal(t, "Admin", userData.ID)
+
+	_, err = store.Load(msp.IdentityIdentifier{MSPID: "Org1MSP", ID: "unknown"})
+	assert.Equal(t, msp.ErrUserNotFound, err)
+
+	// Special characters are escaped
+	_, err = store.Load(msp.IdentityIdentifier{MSPID: "Org1MSP", ID: "*"})
+	assert.Equal(t, msp.ErrUserNotFound, err)
+	assert.Equal(t, `(mail=\2a)`, d.lastFilter(), "expecting equality match rather than presence filter")
+
+	require.NoError(t, store.Store(&msp.UserData{ID: "asmith", MSPID: "Org1MSP", EnrollmentCertificate: []byte("enrolled")}))
+	userData, err = store.Load(msp.IdentityIdentifier{MSPID: "Org1MSP", ID: "alice.smith@example.org"})
+	require.NoError(t, err)
+	assert.Equal(t, "enrolled", string(userData.EnrollmentCertificate), "expecting enrolled certificate to take precedence")
+}
+
+func TestAttributes(t *testing.T) {
+	d := newMockDirectory(
+		map[string][]string{"uid": {"jdoe"}, "mail": {"john.doe@example.org"}, "department": {"sales", "marketing"}, "employeeType": {"manager"}},
+	)
+
+	store := newTestStore(t, d, nil)
+
+	enrollmentID, err := store.EnrollmentID("john.doe@example.org")
+	require.NoError(t, err)
+	assert.Equal(t, "jdoe", enrollmentID)
+
+	attributes, err := store.Attributes("john.doe@example.org")
+	require.NoError(t, err)
+	assert.Equal(t, []mspapi.Attribute{
+		{Name: "department", Value: "sales,marketing", ECert: true},
+		{Name: "app.role", Value: "manager", ECert: true},
+	}, attributes)
+
+	requests, err := store.AttributeRequests("john.doe@example.org")
+	require.NoError(t, err)
+	assert.Equal(t, []*mspapi.AttributeRequest{{Name: "department", Optional: true}, {Name: "app.role", Optional: true}}, requests)
+
+	_, err = store.EnrollmentID("unknown")
+	assert.Equal(t, msp.ErrUserNotFound, err)
+
+	_, err = store.Load(msp.IdentityIdentifier{MSPID: "Org1MSP", ID: "john.doe@example.org"})
+	assert.Equal(t, msp.ErrUserNotFound, err, "expecting not found for directory user without certificate")
+	assert.Error(t, store.Store(&msp.UserData{ID: "jdoe"}), "expecting error for read only store")
+}
+
+func TestSearchFailure(t *testing.T) {
+	d := DirectoryFunc(func(filter string, attributes []string) ([]*Entry, error) {
+		return nil, errors.New("LDAP bind failed")
+	})
+
+	store, err := NewUserStore(nil, d, nil)
+	require.NoError(t, err)
+	_, err = store.Load(msp.IdentityIdentifier{MSPID: "Org1MSP", ID: "jdoe"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to look up user [jdoe]: LDAP bind failed")
+
+	d = DirectoryFunc(func(filter string, attributes []string) ([]*Entry, error) {
+		return []*Entry{{DN: "uid=jdoe"}, {DN: "uid=jdoe2"}}, nil
+	})
+
+	store, err = NewUserStore(nil, d, nil)
+	require.NoError(t, err)
+	_, err = store.EnrollmentID("jdoe")
+	assert.Error(t, err, "expecting error for user filter matching multiple entries")
+}
+
+func TestNewUserStore(t *testing.T) {
+	_, err := NewUserStore(&Config{}, nil, nil)
+	assert.Error(t, err, "expecting error without directory")
+
+	d := newMockDirectory()
+	_, err = NewUserStore(&Config{UserFilter: "(uid=jdoe)"}, d, nil)
+	assert.Error(t, err, "expecting error for user filter without placeholder")
+
+	store, err := NewUserStore(nil, d, nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultUserFilter, store.config.UserFilter)
+	assert.Equal(t, []string{DefaultEnrollmentIDAttribute, DefaultCertificateAttribute}, store.attributes)
+
+	_, err = store.EnrollmentID("jdoe")
+	assert.Equal(t, msp.ErrUserNotFound, err)
+	assert.Equal(t, "(uid=jdoe)", d.lastFilter())
+}
+
+func TestEscapeFilterValue(t *testing.T) {
+	assert.Equal(t, `a\2a\28b\29\5c`, EscapeFilterValue(`a*(b)\`))
+	assert.Equal(t, "john.doe@example.org", EscapeFilterValue("john.doe@example.org"))
+}
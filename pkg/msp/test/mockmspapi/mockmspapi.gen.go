@@ -48,15 +48,15 @@ func (mr *MockCAClientMockRecorder) CreateIdentity(arg0 interface{}) *gomock.Cal
 }
 
 // Enroll mocks base method
-func (m *MockCAClient) Enroll(arg0, arg1 string) error {
-	ret := m.ctrl.Call(m, "Enroll", arg0, arg1)
+func (m *MockCAClient) Enroll(arg0 *api.EnrollmentRequest) error {
+	ret := m.ctrl.Call(m, "Enroll", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Enroll indicates an expected call of Enroll
-func (mr *MockCAClientMockRecorder) Enroll(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockCAClient)(nil).Enroll), arg0, arg1)
+func (mr *MockCAClientMockRecorder) Enroll(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockCAClient)(nil).Enroll), arg0)
 }
 
 // GetAllIdentities mocks base method
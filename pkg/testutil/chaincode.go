@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testutil
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	fabAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// InstallChaincodeWithOrgContexts installs the given chaincode to orgs
+func InstallChaincodeWithOrgContexts(orgs []*OrgContext, ccPkg *resource.CCPackage, ccPath, ccID, ccVersion string) error {
+	for _, orgCtx := range orgs {
+		if err := InstallChaincode(orgCtx.ResMgmt, ccPkg, ccPath, ccID, ccVersion, orgCtx.Peers); err != nil {
+			return errors.Wrapf(err, "failed to install chaincode to peers in org [%s]", orgCtx.OrgID)
+		}
+	}
+
+	return nil
+}
+
+// InstallChaincode installs the given chaincode to the given peers
+func InstallChaincode(resMgmt *resmgmt.Client, ccPkg *resource.CCPackage, ccPath, ccName, ccVersion string, localPeers []fabAPI.Peer) error {
+	installCCReq := resmgmt.InstallCCRequest{Name: ccName, Path: ccPath, Version: ccVersion, Package: ccPkg}
+	_, err := resMgmt.InstallCC(installCCReq, resmgmt.WithRetry(retry.DefaultResMgmtOpts))
+	if err != nil {
+		return err
+	}
+
+	installed, err := queryInstalledCC(resMgmt, ccName, ccVersion, localPeers)
+
+	if err != nil {
+		return err
+	}
+
+	if !installed {
+		return errors.New("chaincode was not installed on all peers")
+	}
+
+	return nil
+}
+
+// InstantiateChaincode instantiates the given chaincode to the given channel
+func InstantiateChaincode(resMgmt *resmgmt.Client, channelID, ccName, ccPath, ccVersion string, ccPolicyStr string, args [][]byte, collConfigs ...*cb.CollectionConfig) (resmgmt.InstantiateCCResponse, error) {
+	ccPolicy, err := cauthdsl.FromString(ccPolicyStr)
+	if err != nil {
+		return resmgmt.InstantiateCCResponse{}, errors.Wrapf(err, "error creating CC policy [%s]", ccPolicyStr)
+	}
+
+	return resMgmt.InstantiateCC(
+		channelID,
+		resmgmt.InstantiateCCRequest{
+			Name:       ccName,
+			Path:       ccPath,
+			Version:    ccVersion,
+			Args:       args,
+			Policy:     ccPolicy,
+			CollConfig: collConfigs,
+		},
+		resmgmt.WithRetry(retry.DefaultResMgmtOpts),
+	)
+}
+
+func queryInstalledCC(resMgmt *resmgmt.Client, ccName, ccVersion string, peers []fabAPI.Peer) (bool, error) {
+	installed, err := retry.NewInvoker(retry.New(retry.TestRetryOpts)).Invoke(
+		func() (interface{}, error) {
+			ok, err := isCCInstalled(resMgmt, ccName, ccVersion, peers)
+			if err != nil {
+				return &ok, err
+			}
+			if !ok {
+				return &ok, status.New(status.TestStatus, status.GenericTransient.ToInt32(), fmt.Sprintf("Chaincode [%s:%s] is not installed on all peers", ccName, ccVersion), nil)
+			}
+			return &ok, nil
+		},
+	)
+
+	if err != nil {
+		s, ok := status.FromError(err)
+		if ok && s.Code == status.GenericTransient.ToInt32() {
+			return false, nil
+		}
+		return false, errors.WithMessage(err, "isCCInstalled invocation failed")
+	}
+
+	return *(installed).(*bool), nil
+}
+
+func isCCInstalled(resMgmt *resmgmt.Client, ccName, ccVersion string, peers []fabAPI.Peer) (bool, error) {
+	installedOnAllPeers := true
+	for _, peer := range peers {
+		resp, err := resMgmt.QueryInstalledChaincodes(resmgmt.WithTargets(peer))
+		if err != nil {
+			return false, errors.WithMessage(err, "querying for installed chaincodes failed")
+		}
+
+		found := false
+		for _, ccInfo := range resp.Chaincodes {
+			if ccInfo.Name == ccName && ccInfo.Version == ccVersion {
+				found = true
+				break
+			}
+		}
+		if !found {
+			installedOnAllPeers = false
+		}
+	}
+	return installedOnAllPeers, nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testutil
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	fabAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/pkg/errors"
+)
+
+// ChannelConfig describes the channel to be created and the orderer used to create it
+type ChannelConfig struct {
+	ChannelID string
+
+	// ChannelTxFile is the channel creation transaction. The anchor peer update transactions
+	// are given by the AnchorPeerConfigFile of each org context.
+	ChannelTxFile string
+
+	// ConfigDir is the directory of the channel and anchor peer transactions (used if the
+	// file names are not absolute paths)
+	ConfigDir string
+
+	OrdererOrgName   string
+	OrdererAdminUser string
+	OrdererEndpoint  string
+}
+
+func (c ChannelConfig) path(file string) string {
+	if filepath.IsAbs(file) || c.ConfigDir == "" {
+		return file
+	}
+	return filepath.Join(c.ConfigDir, file)
+}
+
+// IsJoinedChannel returns true if the given peer has joined the given channel
+func IsJoinedChannel(channelID string, resMgmtClient *resmgmt.Client, peer fabAPI.Peer) (bool, error) {
+	resp, err := resMgmtClient.QueryChannels(resmgmt.WithTargets(peer))
+	if err != nil {
+		return false, err
+	}
+	for _, chInfo := range resp.Channels {
+		if chInfo.ChannelId == channelID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateChannelAndUpdateAnchorPeers creates the channel and updates all of the anchor peers for all orgs
+func CreateChannelAndUpdateAnchorPeers(sdk *fabsdk.FabricSDK, cfg ChannelConfig, orgsContext []*OrgContext) error {
+	ordererCtx := sdk.Context(fabsdk.WithUser(cfg.OrdererAdminUser), fabsdk.WithOrg(cfg.OrdererOrgName))
+
+	// Channel management client is responsible for managing channels (create/update channel)
+	chMgmtClient, err := resmgmt.New(ordererCtx)
+	if err != nil {
+		return errors.New("failed to get a new resmgmt client for orderer")
+	}
+
+	var lastConfigBlock uint64
+	var signingIdentities []msp.SigningIdentity
+	for _, orgCtx := range orgsContext {
+		signingIdentities = append(signingIdentities, orgCtx.SigningIdentity)
+	}
+
+	req := resmgmt.SaveChannelRequest{
+		ChannelID:         cfg.ChannelID,
+		ChannelConfigPath: cfg.path(cfg.ChannelTxFile),
+		SigningIdentities: signingIdentities,
+	}
+	_, err = chMgmtClient.SaveChannel(req, resmgmt.WithRetry(retry.DefaultResMgmtOpts), resmgmt.WithOrdererEndpoint(cfg.OrdererEndpoint))
+	if err != nil {
+		return err
+	}
+
+	lastConfigBlock, err = WaitForOrdererConfigUpdate(orgsContext[0].ResMgmt, cfg.ChannelID, cfg.OrdererEndpoint, true, lastConfigBlock)
+	if err != nil {
+		return err
+	}
+
+	for _, orgCtx := range orgsContext {
+		req := resmgmt.SaveChannelRequest{
+			ChannelID:         cfg.ChannelID,
+			ChannelConfigPath: cfg.path(orgCtx.AnchorPeerConfigFile),
+			SigningIdentities: []msp.SigningIdentity{orgCtx.SigningIdentity},
+		}
+		if _, err := orgCtx.ResMgmt.SaveChannel(req, resmgmt.WithRetry(retry.DefaultResMgmtOpts), resmgmt.WithOrdererEndpoint(cfg.OrdererEndpoint)); err != nil {
+			return err
+		}
+
+		lastConfigBlock, err = WaitForOrdererConfigUpdate(orgCtx.ResMgmt, cfg.ChannelID, cfg.OrdererEndpoint, false, lastConfigBlock)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// JoinPeersToChannel joins all peers in all of the given orgs to the given channel
+func JoinPeersToChannel(channelID, ordererEndpoint string, orgsContext []*OrgContext) error {
+	for _, orgCtx := range orgsContext {
+		err := orgCtx.ResMgmt.JoinChannel(
+			channelID,
+			resmgmt.WithRetry(retry.DefaultResMgmtOpts),
+			resmgmt.WithOrdererEndpoint(ordererEndpoint),
+			resmgmt.WithTargets(orgCtx.Peers...),
+		)
+		if err != nil {
+			return errors.Wrapf(err, "failed to join peers in org [%s] to channel [%s]", orgCtx.OrgID, channelID)
+		}
+	}
+	return nil
+}
+
+// EnsureChannelCreatedAndPeersJoined creates a channel, joins all peers in the given orgs to the channel and updates the anchor peers of each org.
+func EnsureChannelCreatedAndPeersJoined(sdk *fabsdk.FabricSDK, cfg ChannelConfig, orgsContext []*OrgContext) error {
+	if len(orgsContext) == 0 || len(orgsContext[0].Peers) == 0 {
+		return errors.New("at least one org context with peers is required")
+	}
+
+	joined, err := IsJoinedChannel(cfg.ChannelID, orgsContext[0].ResMgmt, orgsContext[0].Peers[0])
+	if err != nil {
+		return err
+	}
+
+	if joined {
+		return nil
+	}
+
+	// Create the channel and update anchor peers for all orgs
+	if err := CreateChannelAndUpdateAnchorPeers(sdk, cfg, orgsContext); err != nil {
+		return err
+	}
+
+	return JoinPeersToChannel(cfg.ChannelID, cfg.OrdererEndpoint, orgsContext)
+}
+
+// WaitForOrdererConfigUpdate waits until the config block update has been committed.
+// In Fabric 1.0 there is a bug that panics the orderer if more than one config update is added to the same block.
+// This function may be invoked after each config update as a workaround. The number of the config block is returned.
+func WaitForOrdererConfigUpdate(client *resmgmt.Client, channelID, ordererEndpoint string, genesis bool, lastConfigBlock uint64) (uint64, error) {
+
+	blockNum, err := retry.NewInvoker(retry.New(retry.TestRetryOpts)).Invoke(
+		func() (interface{}, error) {
+			chConfig, err := client.QueryConfigFromOrderer(channelID, resmgmt.WithOrdererEndpoint(ordererEndpoint))
+			if err != nil {
+				return nil, status.New(status.TestStatus, status.GenericTransient.ToInt32(), err.Error(), nil)
+			}
+
+			currentBlock := chConfig.BlockNumber()
+			if currentBlock <= lastConfigBlock && !genesis {
+				return nil, status.New(status.TestStatus, status.GenericTransient.ToInt32(), fmt.Sprintf("Block number was not incremented [%d, %d]", currentBlock, lastConfigBlock), nil)
+			}
+			return &currentBlock, nil
+		},
+	)
+
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed waiting for orderer config update")
+	}
+	return *blockNum.(*uint64), nil
+}
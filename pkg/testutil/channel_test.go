@@ -0,0 +1,30 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelConfigPath(t *testing.T) {
+	cfg := ChannelConfig{ConfigDir: "/fixtures/channel"}
+	assert.Equal(t, "/fixtures/channel/mychannel.tx", cfg.path("mychannel.tx"))
+	assert.Equal(t, "/other/mychannel.tx", cfg.path("/other/mychannel.tx"))
+
+	cfg = ChannelConfig{}
+	assert.Equal(t, "mychannel.tx", cfg.path("mychannel.tx"))
+}
+
+func TestEnsureChannelCreatedAndPeersJoinedNoOrgs(t *testing.T) {
+	err := EnsureChannelCreatedAndPeersJoined(nil, ChannelConfig{ChannelID: "mychannel"}, nil)
+	assert.Error(t, err)
+
+	err = EnsureChannelCreatedAndPeersJoined(nil, ChannelConfig{ChannelID: "mychannel"}, []*OrgContext{{OrgID: "Org1"}})
+	assert.Error(t, err)
+}
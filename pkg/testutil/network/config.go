@@ -0,0 +1,252 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package network
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	cryptoConfigFile      = "crypto-config.yaml"
+	configtxFile          = "configtx.yaml"
+	connectionProfileFile = "config.yaml"
+	cryptoDir             = "crypto-config"
+	genesisBlockFile      = "genesis.block"
+	channelTxFile         = "channel.tx"
+)
+
+// cryptoConfig is the cryptogen configuration of the orderer org and the two peer orgs
+const cryptoConfig = `
+OrdererOrgs:
+  - Name: Orderer
+    Domain: example.com
+    Specs:
+      - Hostname: orderer
+PeerOrgs:
+  - Name: Org1
+    Domain: org1.example.com
+    Template:
+      Count: 1
+    Users:
+      Count: 1
+  - Name: Org2
+    Domain: org2.example.com
+    Template:
+      Count: 1
+    Users:
+      Count: 1
+`
+
+// configtx is the configtxgen configuration of the genesis block and of the channel
+const configtx = `
+Organizations:
+  - &OrdererOrg
+    Name: OrdererMSP
+    ID: OrdererMSP
+    MSPDir: crypto-config/ordererOrganizations/example.com/msp
+  - &Org1
+    Name: Org1MSP
+    ID: Org1MSP
+    MSPDir: crypto-config/peerOrganizations/org1.example.com/msp
+    AnchorPeers:
+      - Host: peer0.org1.example.com
+        Port: 7051
+  - &Org2
+    Name: Org2MSP
+    ID: Org2MSP
+    MSPDir: crypto-config/peerOrganizations/org2.example.com/msp
+    AnchorPeers:
+      - Host: peer0.org2.example.com
+        Port: 7051
+
+Capabilities:
+  Channel: &ChannelCapabilities
+    V1_1: true
+  Orderer: &OrdererCapabilities
+    V1_1: true
+  Application: &ApplicationCapabilities
+    V1_2: true
+
+Orderer: &OrdererDefaults
+  OrdererType: solo
+  Addresses:
+    - orderer.example.com:7050
+  BatchTimeout: 500ms
+  BatchSize:
+    MaxMessageCount: 10
+    AbsoluteMaxBytes: 98 MB
+    PreferredMaxBytes: 512 KB
+  Organizations:
+  Capabilities:
+    <<: *OrdererCapabilities
+
+Application: &ApplicationDefaults
+  Organizations:
+  Capabilities:
+    <<: *ApplicationCapabilities
+
+Profiles:
+  TwoOrgsOrdererGenesis:
+    Capabilities:
+      <<: *ChannelCapabilities
+    Orderer:
+      <<: *OrdererDefaults
+      Organizations:
+        - *OrdererOrg
+    Consortiums:
+      SampleConsortium:
+        Organizations:
+          - *Org1
+          - *Org2
+  TwoOrgsChannel:
+    Consortium: SampleConsortium
+    Application:
+      <<: *ApplicationDefaults
+      Organizations:
+        - *Org1
+        - *Org2
+`
+
+// profileTemplate is the template of the connection profile of the network. The endpoints in the
+// channel config (and returned by discovery) are mapped to the host ports of the containers.
+var profileTemplate = template.Must(template.New("profile").Parse(`
+version: 1.0.0
+
+client:
+  organization: org1
+  logging:
+    level: info
+  cryptoconfig:
+    path: "{{.CryptoPath}}"
+  credentialStore:
+    path: "{{.Dir}}/state-store"
+    cryptoStore:
+      path: "{{.Dir}}/msp"
+  BCCSP:
+    security:
+      enabled: true
+      default:
+        provider: "SW"
+      hashAlgorithm: "SHA2"
+      softVerify: true
+      level: 256
+  tlsCerts:
+    systemCertPool: false
+
+channels:
+  {{.ChannelID}}:
+    peers:
+      peer0.org1.example.com:
+        endorsingPeer: true
+        chaincodeQuery: true
+        ledgerQuery: true
+        eventSource: true
+      peer0.org2.example.com:
+        endorsingPeer: true
+        chaincodeQuery: true
+        ledgerQuery: true
+        eventSource: true
+
+organizations:
+  org1:
+    mspid: Org1MSP
+    cryptoPath: peerOrganizations/org1.example.com/users/{username}@org1.example.com/msp
+    peers:
+      - peer0.org1.example.com
+  org2:
+    mspid: Org2MSP
+    cryptoPath: peerOrganizations/org2.example.com/users/{username}@org2.example.com/msp
+    peers:
+      - peer0.org2.example.com
+  ordererorg:
+    mspID: OrdererMSP
+    cryptoPath: ordererOrganizations/example.com/users/{username}@example.com/msp
+
+orderers:
+  orderer.example.com:
+    url: localhost:{{.OrdererPort}}
+    grpcOptions:
+      ssl-target-name-override: orderer.example.com
+      fail-fast: false
+      allow-insecure: false
+    tlsCACerts:
+      path: "{{.CryptoPath}}/ordererOrganizations/example.com/tlsca/tlsca.example.com-cert.pem"
+
+peers:
+  peer0.org1.example.com:
+    url: localhost:{{.Org1PeerPort}}
+    grpcOptions:
+      ssl-target-name-override: peer0.org1.example.com
+      fail-fast: false
+      allow-insecure: false
+    tlsCACerts:
+      path: "{{.CryptoPath}}/peerOrganizations/org1.example.com/tlsca/tlsca.org1.example.com-cert.pem"
+  peer0.org2.example.com:
+    url: localhost:{{.Org2PeerPort}}
+    grpcOptions:
+      ssl-target-name-override: peer0.org2.example.com
+      fail-fast: false
+      allow-insecure: false
+    tlsCACerts:
+      path: "{{.CryptoPath}}/peerOrganizations/org2.example.com/tlsca/tlsca.org2.example.com-cert.pem"
+
+entityMatchers:
+  peer:
+    - pattern: peer0.org1.example.com(:\d+)?
+      urlSubstitutionExp: localhost:{{.Org1PeerPort}}
+      sslTargetOverrideUrlSubstitutionExp: peer0.org1.example.com
+      mappedHost: peer0.org1.example.com
+    - pattern: peer0.org2.example.com(:\d+)?
+      urlSubstitutionExp: localhost:{{.Org2PeerPort}}
+      sslTargetOverrideUrlSubstitutionExp: peer0.org2.example.com
+      mappedHost: peer0.org2.example.com
+  orderer:
+    - pattern: orderer.example.com(:\d+)?
+      urlSubstitutionExp: localhost:{{.OrdererPort}}
+      sslTargetOverrideUrlSubstitutionExp: orderer.example.com
+      mappedHost: orderer.example.com
+`))
+
+// writeToolsConfig writes the configuration used to generate the crypto material and the
+// channel artifacts to the given directory
+func writeToolsConfig(dir string) error {
+	if err := ioutil.WriteFile(filepath.Join(dir, cryptoConfigFile), []byte(cryptoConfig), 0644); err != nil {
+		return errors.Wrap(err, "failed to write crypto config")
+	}
+	return errors.Wrap(ioutil.WriteFile(filepath.Join(dir, configtxFile), []byte(configtx), 0644), "failed to write configtx")
+}
+
+// writeConnectionProfile writes the connection profile of the network to its directory
+func (n *Network) writeConnectionProfile() error {
+	f, err := os.Create(n.ConnectionProfile())
+	if err != nil {
+		return errors.Wrap(err, "failed to create connection profile")
+	}
+	defer f.Close() // nolint: errcheck
+
+	data := struct {
+		Dir          string
+		CryptoPath   string
+		ChannelID    string
+		OrdererPort  int
+		Org1PeerPort int
+		Org2PeerPort int
+	}{
+		Dir:          n.dir,
+		CryptoPath:   filepath.Join(n.dir, cryptoDir),
+		ChannelID:    n.channelID,
+		OrdererPort:  n.ports.Orderer,
+		Org1PeerPort: n.ports.Org1Peer,
+		Org2PeerPort: n.ports.Org2Peer,
+	}
+	return errors.Wrap(profileTemplate.Execute(f, data), "failed to write connection profile")
+}
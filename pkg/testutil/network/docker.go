@@ -0,0 +1,245 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultDockerHost is the Docker daemon endpoint used if DOCKER_HOST isn't set
+	DefaultDockerHost = "unix:///var/run/docker.sock"
+
+	dockerAPIVersion = "v1.25"
+)
+
+// dockerClient is a minimal client of the Docker Engine API which supports
+// the operations needed to run the network
+type dockerClient struct {
+	client  *http.Client
+	baseURL string
+}
+
+// containerConfig is the configuration of a container (see the Docker Engine API ContainerCreate)
+type containerConfig struct {
+	Image            string
+	Cmd              []string            `json:",omitempty"`
+	Env              []string            `json:",omitempty"`
+	WorkingDir       string              `json:",omitempty"`
+	User             string              `json:",omitempty"`
+	ExposedPorts     map[string]struct{} `json:",omitempty"`
+	HostConfig       hostConfig
+	NetworkingConfig networkingConfig
+}
+
+type hostConfig struct {
+	Binds        []string                 `json:",omitempty"`
+	PortBindings map[string][]portBinding `json:",omitempty"`
+	NetworkMode  string                   `json:",omitempty"`
+}
+
+type portBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string
+}
+
+type networkingConfig struct {
+	EndpointsConfig map[string]endpointSettings `json:",omitempty"`
+}
+
+type endpointSettings struct {
+	Aliases []string `json:",omitempty"`
+}
+
+// newDockerClient returns a client for the Docker daemon at the given host,
+// e.g. unix:///var/run/docker.sock or tcp://localhost:2375
+func newDockerClient(host string) (*dockerClient, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid docker host [%s]", host)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+		return &dockerClient{client: &http.Client{Transport: transport}, baseURL: "http://docker"}, nil
+	case "tcp", "http":
+		return &dockerClient{client: &http.Client{}, baseURL: "http://" + u.Host}, nil
+	default:
+		return nil, errors.Errorf("unsupported docker host [%s]", host)
+	}
+}
+
+// pullImage pulls the given image unless it is already present
+func (c *dockerClient) pullImage(image string) error {
+	if err := c.do(http.MethodGet, "/images/"+image+"/json", nil, nil, nil); err == nil {
+		return nil
+	}
+
+	name, tag := image, "latest"
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		name, tag = image[:i], image[i+1:]
+	}
+
+	logger.Infof("Pulling image [%s]", image)
+	query := url.Values{"fromImage": {name}, "tag": {tag}}
+	return errors.WithMessage(c.do(http.MethodPost, "/images/create", query, nil, nil), "failed to pull image "+image)
+}
+
+// createNetwork creates a bridge network and returns its ID
+func (c *dockerClient) createNetwork(name string) (string, error) {
+	var resp struct {
+		ID string `json:"Id"`
+	}
+	req := map[string]interface{}{"Name": name, "CheckDuplicate": true}
+	if err := c.do(http.MethodPost, "/networks/create", nil, req, &resp); err != nil {
+		return "", errors.WithMessage(err, "failed to create network "+name)
+	}
+	return resp.ID, nil
+}
+
+// removeNetwork removes the network along with any containers which are still attached to it
+// (e.g. chaincode containers launched by the peers)
+func (c *dockerClient) removeNetwork(id string) error {
+	var resp struct{ Containers map[string]interface{} }
+	if err := c.do(http.MethodGet, "/networks/"+id, nil, nil, &resp); err != nil {
+		return errors.WithMessage(err, "failed to inspect network "+id)
+	}
+	for containerID := range resp.Containers {
+		if err := c.removeContainer(containerID); err != nil {
+			return err
+		}
+	}
+	return errors.WithMessage(c.do(http.MethodDelete, "/networks/"+id, nil, nil, nil), "failed to remove network "+id)
+}
+
+// createContainer creates a container with the given name and returns its ID
+func (c *dockerClient) createContainer(name string, config *containerConfig) (string, error) {
+	var resp struct {
+		ID string `json:"Id"`
+	}
+	query := url.Values{"name": {name}}
+	if err := c.do(http.MethodPost, "/containers/create", query, config, &resp); err != nil {
+		return "", errors.WithMessage(err, "failed to create container "+name)
+	}
+	return resp.ID, nil
+}
+
+func (c *dockerClient) startContainer(id string) error {
+	return errors.WithMessage(c.do(http.MethodPost, "/containers/"+id+"/start", nil, nil, nil), "failed to start container "+id)
+}
+
+// waitContainer waits for the container to exit and returns its exit code
+func (c *dockerClient) waitContainer(id string) (int, error) {
+	var resp struct{ StatusCode int }
+	if err := c.do(http.MethodPost, "/containers/"+id+"/wait", nil, nil, &resp); err != nil {
+		return 0, errors.WithMessage(err, "failed to wait for container "+id)
+	}
+	return resp.StatusCode, nil
+}
+
+// containerLogs returns the combined stdout and stderr of the container
+func (c *dockerClient) containerLogs(id string) (string, error) {
+	resp, err := c.send(http.MethodGet, "/containers/"+id+"/logs", url.Values{"stdout": {"1"}, "stderr": {"1"}}, nil)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to get logs of container "+id)
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	return demuxLogs(resp.Body)
+}
+
+func (c *dockerClient) removeContainer(id string) error {
+	query := url.Values{"force": {"1"}, "v": {"1"}}
+	return errors.WithMessage(c.do(http.MethodDelete, "/containers/"+id, query, nil, nil), "failed to remove container "+id)
+}
+
+// do sends the request and decodes the JSON response into resp (if not nil)
+func (c *dockerClient) do(method, path string, query url.Values, req interface{}, resp interface{}) error {
+	httpResp, err := c.send(method, path, query, req)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close() // nolint: errcheck
+
+	if resp == nil {
+		// The body must be consumed (e.g. for image pulls the pull completes when the stream ends)
+		_, err = io.Copy(ioutil.Discard, httpResp.Body)
+		return errors.Wrap(err, "failed to read response")
+	}
+	return errors.Wrap(json.NewDecoder(httpResp.Body).Decode(resp), "failed to decode response")
+}
+
+func (c *dockerClient) send(method, path string, query url.Values, req interface{}) (*http.Response, error) {
+	var body io.Reader
+	if req != nil {
+		b, err := json.Marshal(req)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal request")
+		}
+		body = bytes.NewReader(b)
+	}
+
+	u := c.baseURL + "/" + dockerAPIVersion + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	httpReq, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s failed", method, path)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()             // nolint: errcheck
+		msg, _ := ioutil.ReadAll(resp.Body) // nolint: errcheck
+		return nil, errors.Errorf("%s %s failed with status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// demuxLogs reads a multiplexed log stream of a container (i.e. started without a TTY) in which each
+// frame has an 8 byte header holding the stream type and the size of the frame
+func demuxLogs(r io.Reader) (string, error) {
+	var out bytes.Buffer
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return out.String(), nil
+			}
+			return out.String(), errors.Wrap(err, "failed to read log frame header")
+		}
+		if _, err := io.CopyN(&out, r, int64(binary.BigEndian.Uint32(header[4:]))); err != nil {
+			return out.String(), errors.Wrap(err, "failed to read log frame")
+		}
+	}
+}
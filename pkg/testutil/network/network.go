@@ -0,0 +1,450 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package network launches an ephemeral Fabric network (one orderer and two orgs) in Docker so that
+// end-to-end tests can be run from Go code without the SDK Makefile scaffolding.
+//
+// The network is self-contained: the crypto material, the genesis block and the channel configuration
+// transaction are generated (using the Fabric tools image) from configuration embedded in this package,
+// and the containers are run using the Docker Engine API. The readiness of the network is determined by
+// waiting for the orderer and peer endpoints to accept connections. Only a Docker daemon is required.
+//
+//  Basic Flow:
+//  1) Start the network using Start
+//  2) Create the SDK using the connection profile returned by Network.ConfigProvider
+//  3) Create the channel using the transaction returned by Network.ChannelTxPath
+//  4) Stop the network using Network.Stop
+package network
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/testutil")
+
+const (
+	// DefaultProjectName is the name of the Docker network (and the prefix of the container names)
+	DefaultProjectName = "fabsdkgo"
+
+	// DefaultImageTag is the tag of the Fabric images used for the network
+	DefaultImageTag = "amd64-1.2.0"
+
+	// DefaultChannelID is the ID of the channel whose configuration transaction is generated
+	DefaultChannelID = "mychannel"
+
+	// DefaultReadyTimeout is the maximum time to wait for the network endpoints to become available
+	DefaultReadyTimeout = 2 * time.Minute
+
+	toolsImage   = "hyperledger/fabric-tools"
+	ordererImage = "hyperledger/fabric-orderer"
+	peerImage    = "hyperledger/fabric-peer"
+
+	pollInterval = time.Second
+)
+
+// Ports are the host ports on which the orderer and peers of the network are published
+type Ports struct {
+	Orderer  int
+	Org1Peer int
+	Org2Peer int
+}
+
+// DefaultPorts are the default host ports of the network
+var DefaultPorts = Ports{Orderer: 7050, Org1Peer: 7051, Org2Peer: 8051}
+
+// Network is an ephemeral Fabric network running in Docker
+type Network struct {
+	projectName  string
+	dockerHost   string
+	imageTag     string
+	channelID    string
+	ports        Ports
+	dir          string
+	removeDir    bool
+	readyTimeout time.Duration
+	docker       *dockerClient
+	networkID    string
+	containers   []string
+}
+
+// Option configures the network
+type Option func(*Network) error
+
+// WithProjectName sets the name of the Docker network (and the prefix of the container names)
+func WithProjectName(name string) Option {
+	return func(n *Network) error {
+		n.projectName = name
+		return nil
+	}
+}
+
+// WithDockerHost sets the Docker daemon endpoint, e.g. tcp://localhost:2375.
+// The default is the DOCKER_HOST environment variable or DefaultDockerHost.
+func WithDockerHost(host string) Option {
+	return func(n *Network) error {
+		n.dockerHost = host
+		return nil
+	}
+}
+
+// WithImageTag sets the tag of the Fabric images
+func WithImageTag(tag string) Option {
+	return func(n *Network) error {
+		n.imageTag = tag
+		return nil
+	}
+}
+
+// WithChannelID sets the ID of the channel whose configuration transaction is generated
+func WithChannelID(channelID string) Option {
+	return func(n *Network) error {
+		n.channelID = channelID
+		return nil
+	}
+}
+
+// WithPorts sets the host ports on which the orderer and peers are published
+func WithPorts(ports Ports) Option {
+	return func(n *Network) error {
+		if ports.Orderer == 0 || ports.Org1Peer == 0 || ports.Org2Peer == 0 {
+			return errors.New("all ports are required")
+		}
+		n.ports = ports
+		return nil
+	}
+}
+
+// WithDir sets the directory to which the crypto material, channel artifacts and connection profile
+// are written. By default a temporary directory is used which is removed when the network is stopped.
+func WithDir(dir string) Option {
+	return func(n *Network) error {
+		n.dir = dir
+		return nil
+	}
+}
+
+// WithReadyTimeout sets the maximum time to wait for the network to become ready
+func WithReadyTimeout(timeout time.Duration) Option {
+	return func(n *Network) error {
+		n.readyTimeout = timeout
+		return nil
+	}
+}
+
+// Start launches the network and waits until it is ready. If the network doesn't become
+// ready then it is stopped and an error is returned.
+func Start(opts ...Option) (*Network, error) {
+	n := &Network{
+		projectName:  DefaultProjectName,
+		dockerHost:   os.Getenv("DOCKER_HOST"),
+		imageTag:     DefaultImageTag,
+		channelID:    DefaultChannelID,
+		ports:        DefaultPorts,
+		readyTimeout: DefaultReadyTimeout,
+	}
+
+	for _, opt := range opts {
+		if err := opt(n); err != nil {
+			return nil, errors.WithMessage(err, "failed to apply network option")
+		}
+	}
+
+	if n.dockerHost == "" {
+		n.dockerHost = DefaultDockerHost
+	}
+	docker, err := newDockerClient(n.dockerHost)
+	if err != nil {
+		return nil, err
+	}
+	n.docker = docker
+
+	if n.dir == "" {
+		dir, err := ioutil.TempDir("", n.projectName)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create network directory")
+		}
+		n.dir = dir
+		n.removeDir = true
+	}
+
+	if err := n.start(); err != nil {
+		if stopErr := n.Stop(); stopErr != nil {
+			logger.Warnf("Error stopping network: %s", stopErr)
+		}
+		return nil, err
+	}
+	return n, nil
+}
+
+func (n *Network) start() error {
+	logger.Infof("Starting Fabric network [%s]", n.projectName)
+
+	for _, image := range []string{toolsImage, ordererImage, peerImage} {
+		if err := n.docker.pullImage(n.image(image)); err != nil {
+			return err
+		}
+	}
+
+	networkID, err := n.docker.createNetwork(n.projectName)
+	if err != nil {
+		return err
+	}
+	n.networkID = networkID
+
+	if err := n.generateArtifacts(); err != nil {
+		return errors.WithMessage(err, "failed to generate network artifacts")
+	}
+
+	if err := n.runOrderer(); err != nil {
+		return err
+	}
+	if err := n.runPeer("org1", "Org1MSP", n.ports.Org1Peer); err != nil {
+		return err
+	}
+	if err := n.runPeer("org2", "Org2MSP", n.ports.Org2Peer); err != nil {
+		return err
+	}
+
+	if err := n.writeConnectionProfile(); err != nil {
+		return err
+	}
+
+	if err := waitForEndpoints(n.endpoints(), n.readyTimeout); err != nil {
+		return errors.WithMessage(err, "network did not become ready")
+	}
+
+	logger.Infof("Fabric network [%s] is ready", n.projectName)
+	return nil
+}
+
+// Stop stops the network and removes its containers (and its directory unless it was set using WithDir)
+func (n *Network) Stop() error {
+	logger.Infof("Stopping Fabric network [%s]", n.projectName)
+
+	var lastErr error
+	for _, id := range n.containers {
+		if err := n.docker.removeContainer(id); err != nil {
+			logger.Warnf("Error removing container: %s", err)
+			lastErr = err
+		}
+	}
+	n.containers = nil
+
+	if n.networkID != "" {
+		if err := n.docker.removeNetwork(n.networkID); err != nil {
+			logger.Warnf("Error removing network: %s", err)
+			lastErr = err
+		}
+		n.networkID = ""
+	}
+
+	if n.removeDir {
+		if err := os.RemoveAll(n.dir); err != nil {
+			lastErr = errors.Wrap(err, "failed to remove network directory")
+		}
+	}
+	return lastErr
+}
+
+// ConnectionProfile returns the path of the connection profile of the network
+func (n *Network) ConnectionProfile() string {
+	return filepath.Join(n.dir, connectionProfileFile)
+}
+
+// ConfigProvider returns the config provider for the connection profile of the network
+func (n *Network) ConfigProvider() core.ConfigProvider {
+	return config.FromFile(n.ConnectionProfile())
+}
+
+// ChannelID returns the ID of the channel whose configuration transaction was generated
+func (n *Network) ChannelID() string {
+	return n.channelID
+}
+
+// ChannelTxPath returns the path of the configuration transaction which creates the channel
+func (n *Network) ChannelTxPath() string {
+	return filepath.Join(n.dir, channelTxFile)
+}
+
+// generateArtifacts generates the crypto material, the genesis block and the channel
+// configuration transaction in the network directory using the Fabric tools image
+func (n *Network) generateArtifacts() error {
+	if err := writeToolsConfig(n.dir); err != nil {
+		return err
+	}
+
+	script := fmt.Sprintf("cryptogen generate --config=%s --output=%s && "+
+		"configtxgen -profile TwoOrgsOrdererGenesis -outputBlock %s && "+
+		"configtxgen -profile TwoOrgsChannel -channelID %s -outputCreateChannelTx %s",
+		cryptoConfigFile, cryptoDir, genesisBlockFile, n.channelID, channelTxFile)
+
+	id, err := n.docker.createContainer(n.projectName+"-tools", &containerConfig{
+		Image:      n.image(toolsImage),
+		Cmd:        []string{"sh", "-c", script},
+		Env:        []string{"FABRIC_CFG_PATH=/work"},
+		WorkingDir: "/work",
+		// The generated files are owned by the current user so that they may be read by the SDK
+		User:       currentUser(),
+		HostConfig: hostConfig{Binds: []string{n.dir + ":/work"}},
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := n.docker.removeContainer(id); err != nil {
+			logger.Warnf("Error removing tools container: %s", err)
+		}
+	}()
+
+	if err := n.docker.startContainer(id); err != nil {
+		return err
+	}
+	code, err := n.docker.waitContainer(id)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		logs, err := n.docker.containerLogs(id)
+		if err != nil {
+			logger.Warnf("Error getting logs of tools container: %s", err)
+		}
+		return errors.Errorf("tools exited with code %d: %s", code, logs)
+	}
+	return nil
+}
+
+func (n *Network) runOrderer() error {
+	const host = "orderer.example.com"
+	ordererDir := filepath.Join(n.dir, cryptoDir, "ordererOrganizations/example.com/orderers", host)
+
+	return n.runContainer(host, 7050, n.ports.Orderer, &containerConfig{
+		Image: n.image(ordererImage),
+		Cmd:   []string{"orderer"},
+		Env: []string{
+			"ORDERER_GENERAL_LISTENADDRESS=0.0.0.0",
+			"ORDERER_GENERAL_GENESISMETHOD=file",
+			"ORDERER_GENERAL_GENESISFILE=/etc/hyperledger/configtx/" + genesisBlockFile,
+			"ORDERER_GENERAL_LOCALMSPID=OrdererMSP",
+			"ORDERER_GENERAL_LOCALMSPDIR=/etc/hyperledger/msp",
+			"ORDERER_GENERAL_TLS_ENABLED=true",
+			"ORDERER_GENERAL_TLS_PRIVATEKEY=/etc/hyperledger/tls/server.key",
+			"ORDERER_GENERAL_TLS_CERTIFICATE=/etc/hyperledger/tls/server.crt",
+			"ORDERER_GENERAL_TLS_ROOTCAS=[/etc/hyperledger/tls/ca.crt]",
+		},
+		HostConfig: hostConfig{Binds: []string{
+			filepath.Join(n.dir, genesisBlockFile) + ":/etc/hyperledger/configtx/" + genesisBlockFile,
+			filepath.Join(ordererDir, "msp") + ":/etc/hyperledger/msp",
+			filepath.Join(ordererDir, "tls") + ":/etc/hyperledger/tls",
+		}},
+	})
+}
+
+func (n *Network) runPeer(org, mspID string, hostPort int) error {
+	domain := org + ".example.com"
+	host := "peer0." + domain
+	peerDir := filepath.Join(n.dir, cryptoDir, "peerOrganizations", domain, "peers", host)
+
+	return n.runContainer(host, 7051, hostPort, &containerConfig{
+		Image:      n.image(peerImage),
+		Cmd:        []string{"peer", "node", "start"},
+		WorkingDir: "/opt/gopath/src/github.com/hyperledger/fabric",
+		Env: []string{
+			"CORE_PEER_ID=" + host,
+			"CORE_PEER_LOCALMSPID=" + mspID,
+			"CORE_PEER_MSPCONFIGPATH=/etc/hyperledger/msp",
+			"CORE_PEER_LISTENADDRESS=0.0.0.0:7051",
+			"CORE_PEER_ADDRESS=" + host + ":7051",
+			"CORE_PEER_CHAINCODELISTENADDRESS=0.0.0.0:7052",
+			"CORE_PEER_CHAINCODEADDRESS=" + host + ":7052",
+			"CORE_PEER_GOSSIP_EXTERNALENDPOINT=" + host + ":7051",
+			"CORE_PEER_TLS_ENABLED=true",
+			"CORE_PEER_TLS_KEY_FILE=/etc/hyperledger/tls/server.key",
+			"CORE_PEER_TLS_CERT_FILE=/etc/hyperledger/tls/server.crt",
+			"CORE_PEER_TLS_ROOTCERT_FILE=/etc/hyperledger/tls/ca.crt",
+			// Chaincode containers are launched on the network of the peers
+			"CORE_VM_ENDPOINT=unix:///host/var/run/docker.sock",
+			"CORE_VM_DOCKER_HOSTCONFIG_NETWORKMODE=" + n.projectName,
+		},
+		HostConfig: hostConfig{Binds: []string{
+			"/var/run/docker.sock:/host/var/run/docker.sock",
+			filepath.Join(peerDir, "msp") + ":/etc/hyperledger/msp",
+			filepath.Join(peerDir, "tls") + ":/etc/hyperledger/tls",
+		}},
+	})
+}
+
+// runContainer starts a container on the network with the given host name, publishing the container port on the host port
+func (n *Network) runContainer(host string, containerPort, hostPort int, cfg *containerConfig) error {
+	port := strconv.Itoa(containerPort) + "/tcp"
+	cfg.ExposedPorts = map[string]struct{}{port: {}}
+	cfg.HostConfig.PortBindings = map[string][]portBinding{port: {{HostPort: strconv.Itoa(hostPort)}}}
+	cfg.HostConfig.NetworkMode = n.projectName
+	cfg.NetworkingConfig = networkingConfig{
+		EndpointsConfig: map[string]endpointSettings{n.projectName: {Aliases: []string{host}}},
+	}
+
+	id, err := n.docker.createContainer(n.projectName+"-"+host, cfg)
+	if err != nil {
+		return err
+	}
+	n.containers = append(n.containers, id)
+
+	return n.docker.startContainer(id)
+}
+
+func (n *Network) image(name string) string {
+	return name + ":" + n.imageTag
+}
+
+// endpoints returns the host endpoints of the orderer and peers
+func (n *Network) endpoints() []string {
+	var endpoints []string
+	for _, port := range []int{n.ports.Orderer, n.ports.Org1Peer, n.ports.Org2Peer} {
+		endpoints = append(endpoints, net.JoinHostPort("localhost", strconv.Itoa(port)))
+	}
+	return endpoints
+}
+
+// currentUser returns the uid:gid of the current user (or an empty string, i.e. the user of the
+// image, if not supported by the platform)
+func currentUser() string {
+	uid, gid := os.Getuid(), os.Getgid()
+	if uid < 0 || gid < 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", uid, gid)
+}
+
+// waitForEndpoints waits until all of the given endpoints accept TCP connections
+func waitForEndpoints(endpoints []string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for _, endpoint := range endpoints {
+		for {
+			conn, err := net.DialTimeout("tcp", endpoint, pollInterval)
+			if err == nil {
+				conn.Close() // nolint: gas
+				break
+			}
+			if time.Now().After(deadline) {
+				return errors.Wrapf(err, "timed out waiting for endpoint [%s]", endpoint)
+			}
+			logger.Debugf("Endpoint [%s] is not ready: %s", endpoint, err)
+			time.Sleep(pollInterval)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package network
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDocker is a fake Docker daemon which records the containers that are created and removed
+type mockDocker struct {
+	mutex       sync.Mutex
+	toolsExit   int
+	toolsOutput string
+	created     map[string]containerConfig
+	removed     []string
+	networks    []string
+}
+
+func newMockDocker() *mockDocker {
+	return &mockDocker{created: make(map[string]containerConfig)}
+}
+
+func (d *mockDocker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/"+dockerAPIVersion)
+	switch {
+	case strings.HasPrefix(path, "/images/"):
+	case path == "/networks/create":
+		d.networks = append(d.networks, "net1")
+		fmt.Fprint(w, `{"Id":"net1"}`)
+	case strings.HasPrefix(path, "/networks/"):
+		if r.Method == http.MethodDelete {
+			d.networks = d.networks[:0]
+			return
+		}
+		fmt.Fprint(w, `{"Containers":{}}`)
+	case path == "/containers/create":
+		var cfg containerConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		name := r.URL.Query().Get("name")
+		d.created[name] = cfg
+		fmt.Fprintf(w, `{"Id":%q}`, name)
+	case strings.HasSuffix(path, "/wait"):
+		fmt.Fprintf(w, `{"StatusCode":%d}`, d.toolsExit)
+	case strings.HasSuffix(path, "/logs"):
+		header := make([]byte, 8)
+		header[0] = 2
+		binary.BigEndian.PutUint32(header[4:], uint32(len(d.toolsOutput)))
+		w.Write(append(header, d.toolsOutput...)) // nolint: errcheck
+	case strings.HasPrefix(path, "/containers/") && r.Method == http.MethodDelete:
+		d.removed = append(d.removed, strings.TrimPrefix(path, "/containers/"))
+	}
+}
+
+// listen returns listeners for the orderer and peer ports of the network
+func listen(t *testing.T) (Ports, func()) {
+	var ports []int
+	var listeners []net.Listener
+	for i := 0; i < 3; i++ {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		listeners = append(listeners, l)
+		ports = append(ports, l.Addr().(*net.TCPAddr).Port)
+	}
+	return Ports{Orderer: ports[0], Org1Peer: ports[1], Org2Peer: ports[2]}, func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}
+}
+
+func TestStartAndStop(t *testing.T) {
+	docker := newMockDocker()
+	server := httptest.NewServer(docker)
+	defer server.Close()
+
+	ports, closeListeners := listen(t)
+	defer closeListeners()
+
+	n, err := Start(WithDockerHost("tcp://"+server.Listener.Addr().String()), WithProjectName("testnet"),
+		WithPorts(ports), WithReadyTimeout(100*time.Millisecond))
+	require.NoError(t, err)
+
+	assert.Len(t, docker.created, 4)
+	tools := docker.created["testnet-tools"]
+	assert.Equal(t, toolsImage+":"+DefaultImageTag, tools.Image)
+	assert.Equal(t, []string{n.dir + ":/work"}, tools.HostConfig.Binds)
+	assert.Contains(t, tools.Cmd[2], "-channelID "+DefaultChannelID)
+
+	peer := docker.created["testnet-peer0.org2.example.com"]
+	assert.Equal(t, "testnet", peer.HostConfig.NetworkMode)
+	assert.Equal(t, []string{"peer0.org2.example.com"}, peer.NetworkingConfig.EndpointsConfig["testnet"].Aliases)
+	assert.Equal(t, fmt.Sprint(ports.Org2Peer), peer.HostConfig.PortBindings["7051/tcp"][0].HostPort)
+	assert.Contains(t, peer.Env, "CORE_PEER_LOCALMSPID=Org2MSP")
+
+	profile, err := ioutil.ReadFile(n.ConnectionProfile())
+	require.NoError(t, err)
+	assert.Contains(t, string(profile), fmt.Sprintf("url: localhost:%d", ports.Orderer))
+	assert.Contains(t, string(profile), n.dir+"/crypto-config")
+	backends, err := n.ConfigProvider()()
+	require.NoError(t, err)
+	require.Len(t, backends, 1)
+	_, ok := backends[0].Lookup("channels." + DefaultChannelID)
+	assert.True(t, ok)
+
+	require.NoError(t, n.Stop())
+	assert.Len(t, docker.removed, 4, "expecting the tools container and the network containers to be removed")
+	assert.Empty(t, docker.networks)
+	_, err = os.Stat(n.dir)
+	assert.True(t, os.IsNotExist(err), "expecting the network directory to be removed")
+}
+
+func TestStartToolsError(t *testing.T) {
+	docker := newMockDocker()
+	docker.toolsExit = 1
+	docker.toolsOutput = "cryptogen failed"
+	server := httptest.NewServer(docker)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "testnet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = Start(WithDockerHost("tcp://"+server.Listener.Addr().String()), WithDir(dir))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cryptogen failed")
+	assert.Len(t, docker.created, 1)
+	assert.Empty(t, docker.networks, "expecting the network to be removed")
+	_, err = os.Stat(dir)
+	assert.NoError(t, err, "expecting the directory set with WithDir to be kept")
+}
+
+func TestStartNotReady(t *testing.T) {
+	docker := newMockDocker()
+	server := httptest.NewServer(docker)
+	defer server.Close()
+
+	ports, closeListeners := listen(t)
+	closeListeners()
+
+	_, err := Start(WithDockerHost("tcp://"+server.Listener.Addr().String()), WithPorts(ports), WithReadyTimeout(100*time.Millisecond))
+	require.Error(t, err)
+	assert.Len(t, docker.removed, 4, "expecting the network to be stopped")
+}
+
+func TestOptions(t *testing.T) {
+	_, err := Start(WithPorts(Ports{Orderer: 7050}))
+	assert.Error(t, err)
+
+	_, err = Start(WithDockerHost("ftp://localhost"))
+	assert.Error(t, err)
+
+	n := &Network{}
+	for _, opt := range []Option{
+		WithProjectName("testnet"),
+		WithDockerHost("tcp://localhost:2375"),
+		WithImageTag("1.3.0"),
+		WithChannelID("orgchannel"),
+		WithPorts(Ports{Orderer: 1, Org1Peer: 2, Org2Peer: 3}),
+		WithDir("/tmp/testnet"),
+		WithReadyTimeout(time.Second),
+	} {
+		require.NoError(t, opt(n))
+	}
+	assert.Equal(t, "testnet", n.projectName)
+	assert.Equal(t, "tcp://localhost:2375", n.dockerHost)
+	assert.Equal(t, "1.3.0", n.imageTag)
+	assert.Equal(t, "orgchannel", n.ChannelID())
+	assert.Equal(t, Ports{Orderer: 1, Org1Peer: 2, Org2Peer: 3}, n.ports)
+	assert.Equal(t, "/tmp/testnet/channel.tx", n.ChannelTxPath())
+	assert.Equal(t, time.Second, n.readyTimeout)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package testutil provides helpers for setting up Fabric integration test scenarios
+// (multi-org client contexts, channel creation and join, chaincode install and instantiate)
+// so that applications can reproduce the SDK integration test setup in their own test suites.
+//
+//  Basic Flow:
+//  1) Create an org context for each org using NewOrgContext
+//  2) Create the channel and join the peers using EnsureChannelCreatedAndPeersJoined
+//  3) Install and instantiate chaincode using InstallChaincodeWithOrgContexts and InstantiateChaincode
+package testutil
+
+import (
+	"fmt"
+
+	mspclient "github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	contextAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	fabAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/pkg/errors"
+)
+
+// OrgContext provides SDK client context for a given org
+type OrgContext struct {
+	OrgID                string
+	CtxProvider          contextAPI.ClientProvider
+	SigningIdentity      msp.SigningIdentity
+	ResMgmt              *resmgmt.Client
+	Peers                []fabAPI.Peer
+	AnchorPeerConfigFile string
+}
+
+// NewOrgContext creates the client context of the given admin user of the given org. The local peers
+// of the org are discovered, waiting until at least the expected number of peers is available.
+func NewOrgContext(sdk *fabsdk.FabricSDK, orgName, adminUser string, expectedPeers int) (*OrgContext, error) {
+	adminContext := sdk.Context(fabsdk.WithUser(adminUser), fabsdk.WithOrg(orgName))
+	resMgmt, err := resmgmt.New(adminContext)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create resmgmt client")
+	}
+
+	mspClient, err := mspclient.New(sdk.Context(), mspclient.WithOrg(orgName))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create msp client")
+	}
+	admin, err := mspClient.GetSigningIdentity(adminUser)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get admin signing identity")
+	}
+
+	// Ensure that Gossip has propagated its view of local peers before invoking
+	// install since some peers may be missed if we call InstallCC too early
+	peers, err := DiscoverLocalPeers(adminContext, expectedPeers)
+	if err != nil {
+		return nil, errors.WithMessage(err, "discovery of local peers failed")
+	}
+
+	return &OrgContext{
+		OrgID:           orgName,
+		CtxProvider:     adminContext,
+		ResMgmt:         resMgmt,
+		Peers:           peers,
+		SigningIdentity: admin,
+	}, nil
+}
+
+// DiscoverLocalPeers queries the local peers for the given MSP context and returns all of the peers. If
+// the number of peers does not match the expected number then an error is returned.
+func DiscoverLocalPeers(ctxProvider contextAPI.ClientProvider, expectedPeers int) ([]fabAPI.Peer, error) {
+	ctx, err := contextImpl.NewLocal(ctxProvider)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating local context")
+	}
+
+	discoveredPeers, err := retry.NewInvoker(retry.New(retry.TestRetryOpts)).Invoke(
+		func() (interface{}, error) {
+			peers, err := ctx.LocalDiscoveryService().GetPeers()
+			if err != nil {
+				return nil, errors.Wrapf(err, "error getting peers for MSP [%s]", ctx.Identifier().MSPID)
+			}
+			if len(peers) < expectedPeers {
+				return nil, status.New(status.TestStatus, status.GenericTransient.ToInt32(), fmt.Sprintf("Expecting %d peers but got %d", expectedPeers, len(peers)), nil)
+			}
+			return peers, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return discoveredPeers.([]fabAPI.Peer), nil
+}
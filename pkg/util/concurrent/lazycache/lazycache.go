@@ -55,6 +55,16 @@ type Cache struct {
 	initializer EntryInitializerWithData
 	closed      int32
 	useRef      bool
+	hits        uint64
+	misses      uint64
+}
+
+// Stats contains the size and the hit rate of a cache
+type Stats struct {
+	Name   string
+	Size   int
+	Hits   uint64
+	Misses uint64
 }
 
 // New creates a new lazy cache.
@@ -106,6 +116,7 @@ func (c *Cache) Get(key Key, data ...interface{}) (interface{}, error) {
 
 	f, ok := c.m.Load(keyStr)
 	if ok {
+		atomic.AddUint64(&c.hits, 1)
 		v, err := f.(future).Get()
 		if err != nil {
 			return nil, err
@@ -126,6 +137,7 @@ func (c *Cache) Get(key Key, data ...interface{}) (interface{}, error) {
 	f, loaded := c.m.LoadOrStore(keyStr, newFuture)
 	if loaded {
 		// Another thread has added the key before us. Return the value.
+		atomic.AddUint64(&c.hits, 1)
 		v, err := f.(future).Get()
 		if err != nil {
 			return nil, err
@@ -134,6 +146,7 @@ func (c *Cache) Get(key Key, data ...interface{}) (interface{}, error) {
 	}
 
 	// We added the key. It must be initialized.
+	atomic.AddUint64(&c.misses, 1)
 	value, err := newFuture.Initialize()
 	if err != nil {
 		// Failed. Delete the key.
@@ -156,6 +169,38 @@ func (c *Cache) MustGet(key Key) interface{} {
 	return value
 }
 
+// Stats returns the number of entries in the cache and the number of hits and misses
+func (c *Cache) Stats() Stats {
+	size := 0
+	c.m.Range(func(key interface{}, value interface{}) bool {
+		size++
+		return true
+	})
+	return Stats{
+		Name:   c.name,
+		Size:   size,
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+	}
+}
+
+// Range invokes the given function for each entry in the cache whose value has been successfully
+// initialized. (For caches with lazy references the value is the *lazyref.Reference.)
+// Iteration stops if the function returns false.
+func (c *Cache) Range(f func(key string, value interface{}) bool) {
+	c.m.Range(func(key interface{}, value interface{}) bool {
+		fv := value.(future)
+		if !fv.IsSet() {
+			return true
+		}
+		v, err := fv.Get()
+		if err != nil || v == nil {
+			return true
+		}
+		return f(key.(string), v)
+	})
+}
+
 // Close does the following:
 // - calls Close on all values that implement a Close() function
 // - deletes all entries from the cache
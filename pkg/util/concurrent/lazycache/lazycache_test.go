@@ -398,3 +398,35 @@ func TestGetExpiringError(t *testing.T) {
 	finalizedTimesAfterClose := atomic.LoadInt32(&numTimesFinalized)
 	assert.Equalf(t, int32(0), finalizedTimesAfterClose, "Expecting finalizer not to be called due to error but it was called %d time(s)", finalizedTimesAfterClose)
 }
+
+func TestStatsAndRange(t *testing.T) {
+	cache := New("Stats_Cache", func(key Key) (interface{}, error) {
+		if key.String() == "error" {
+			return nil, fmt.Errorf("some error")
+		}
+		return "value_" + key.String(), nil
+	})
+	defer cache.Close()
+
+	_, err := cache.Get(NewStringKey("key1"))
+	require.NoError(t, err)
+	_, err = cache.Get(NewStringKey("key1"))
+	require.NoError(t, err)
+	_, err = cache.Get(NewStringKey("key2"))
+	require.NoError(t, err)
+	_, err = cache.Get(NewStringKey("error"))
+	require.Error(t, err)
+
+	stats := cache.Stats()
+	assert.Equal(t, "Stats_Cache", stats.Name)
+	assert.Equal(t, 2, stats.Size)
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(3), stats.Misses)
+
+	values := make(map[string]interface{})
+	cache.Range(func(key string, value interface{}) bool {
+		values[key] = value
+		return true
+	})
+	assert.Equal(t, map[string]interface{}{"key1": "value_key1", "key2": "value_key2"}, values)
+}
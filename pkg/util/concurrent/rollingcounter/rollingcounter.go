@@ -7,10 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package rollingcounter
 
 import (
-	"math/rand"
 	"sync/atomic"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/random"
 )
 
 var logger = logging.NewLogger("fabsdk/util")
@@ -41,7 +41,7 @@ func (c *Counter) Next(n int) int {
 		i := int(current)
 		if i == -1 {
 			// Choose a random index the first time
-			i = rand.Intn(n)
+			i = random.Intn(n)
 		} else {
 			i++
 			if i >= n {
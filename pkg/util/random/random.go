@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package random provides the source of randomness used for choosing peers and orderers
+// (target selection, load balancing, etc.). By default the source is seeded from crypto/rand.
+// Tests and bug reproductions may set a fixed seed (or a custom source) in order to get
+// deterministic choices.
+package random
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	mutex sync.Mutex
+	rnd   = rand.New(newCryptoSeededSource())
+)
+
+// lockedSource makes a source safe for concurrent use
+type lockedSource struct {
+	mutex sync.Mutex
+	src   rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.src.Seed(seed)
+}
+
+func newCryptoSeededSource() rand.Source {
+	var b [8]byte
+	seed := time.Now().UnixNano()
+	if _, err := cryptorand.Read(b[:]); err == nil {
+		seed = int64(binary.LittleEndian.Uint64(b[:]))
+	}
+	return &lockedSource{src: rand.NewSource(seed)}
+}
+
+// Seed replaces the source with a deterministic source initialized with the given seed.
+// The seed applies to all SDK instances in the process.
+func Seed(seed int64) {
+	SetSource(rand.NewSource(seed))
+}
+
+// SetSource replaces the source of randomness. If src is nil then the default
+// (crypto/rand seeded) source is restored.
+func SetSource(src rand.Source) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if src == nil {
+		rnd = rand.New(newCryptoSeededSource())
+		return
+	}
+	rnd = rand.New(&lockedSource{src: src})
+}
+
+func current() *rand.Rand {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return rnd
+}
+
+// Intn returns a random number in [0,n). It panics if n <= 0.
+func Intn(n int) int {
+	return current().Intn(n)
+}
+
+// Perm returns a random permutation of the integers [0,n)
+func Perm(n int) []int {
+	return current().Perm(n)
+}
+
+// Float64 returns a random number in [0.0,1.0)
+func Float64() float64 {
+	return current().Float64()
+}
+
+// Shuffle randomizes the order of n elements using the given swap function
+func Shuffle(n int, swap func(i, j int)) {
+	r := current()
+	for i := n - 1; i > 0; i-- {
+		j := r.Intn(i + 1)
+		swap(i, j)
+	}
+}
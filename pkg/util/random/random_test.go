@@ -0,0 +1,47 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package random
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeed(t *testing.T) {
+	defer SetSource(nil)
+
+	Seed(1234)
+	perm1 := Perm(10)
+	n1 := Intn(100)
+
+	Seed(1234)
+	assert.Equal(t, perm1, Perm(10))
+	assert.Equal(t, n1, Intn(100))
+
+	values := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	Seed(1234)
+	Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+	shuffled := append([]int{}, values...)
+
+	values = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	Seed(1234)
+	Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+	assert.Equal(t, shuffled, values)
+}
+
+func TestDefaultSource(t *testing.T) {
+	SetSource(nil)
+
+	for i := 0; i < 100; i++ {
+		n := Intn(5)
+		assert.True(t, n >= 0 && n < 5)
+		f := Float64()
+		assert.True(t, f >= 0 && f < 1)
+	}
+	assert.Len(t, Perm(7), 7)
+}
@@ -87,6 +87,7 @@ declare -a FILES=(
     "bccsp/sw/new.go"
     "bccsp/sw/rsa.go"
     "bccsp/sw/rsakey.go"
+    "bccsp/sw/sdkpatch_encryptedks.go"
 
     "bccsp/utils/errs.go"
     "bccsp/utils/io.go"
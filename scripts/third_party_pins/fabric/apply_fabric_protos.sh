@@ -25,6 +25,7 @@ declare -a PKGS=(
     "protos/ledger/rwset"
     "protos/ledger/rwset/kvrwset"
     "protos/orderer"
+    "protos/orderer/etcdraft"
 )
 
 declare -a FILES=(
@@ -54,6 +55,7 @@ declare -a FILES=(
     "protos/ledger/rwset/kvrwset/kv_rwset.pb.go"
 
     "protos/orderer/configuration.pb.go"
+    "protos/orderer/etcdraft/configuration.pb.go"
 )
 
 # Create directory structure for packages
@@ -96,6 +98,10 @@ do
     sed -i'' -e "/proto.RegisterType/s/orderer/${NAMESPACE_PREFIX}orderer/g" "${TMP_PROJECT_PATH}/${i}"
     sed -i'' -e "/proto.RegisterEnum/s/orderer/${NAMESPACE_PREFIX}orderer/g" "${TMP_PROJECT_PATH}/${i}"
   fi
+  if [[ ${i} == "protos/orderer/etcdraft"* ]]; then
+    sed -i'' -e "/proto.RegisterType/s/etcdraft/${NAMESPACE_PREFIX}etcdraft/g" "${TMP_PROJECT_PATH}/${i}"
+    sed -i'' -e "/proto.RegisterEnum/s/etcdraft/${NAMESPACE_PREFIX}etcdraft/g" "${TMP_PROJECT_PATH}/${i}"
+  fi
   if [[ ${i} == "protos/peer"* ]]; then
     sed -i'' -e "/proto.RegisterType/s/protos/${NAMESPACE_PREFIX}protos/g" "${TMP_PROJECT_PATH}/${i}"
     sed -i'' -e "/proto.RegisterEnum/s/protos/${NAMESPACE_PREFIX}protos/g" "${TMP_PROJECT_PATH}/${i}"
@@ -7,7 +7,6 @@ SPDX-License-Identifier: Apache-2.0
 package integration
 
 import (
-	"fmt"
 	"go/build"
 	"os"
 	"path"
@@ -16,18 +15,15 @@ import (
 
 	mspclient "github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
-	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
-	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	contextAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	fabAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
-	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/hyperledger/fabric-sdk-go/pkg/testutil"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/test"
 	"github.com/hyperledger/fabric-sdk-go/test/metadata"
-	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
 	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
@@ -82,16 +78,7 @@ func ExampleCCUpgradeArgs() [][]byte {
 
 // IsJoinedChannel returns true if the given peer has joined the given channel
 func IsJoinedChannel(channelID string, resMgmtClient *resmgmt.Client, peer fabAPI.Peer) (bool, error) {
-	resp, err := resMgmtClient.QueryChannels(resmgmt.WithTargets(peer))
-	if err != nil {
-		return false, err
-	}
-	for _, chInfo := range resp.Channels {
-		if chInfo.ChannelId == channelID {
-			return true, nil
-		}
-	}
-	return false, nil
+	return testutil.IsJoinedChannel(channelID, resMgmtClient, peer)
 }
 
 // Initialize reads configuration from file and sets up client and channel
@@ -175,241 +162,61 @@ func goPath() string {
 }
 
 // OrgContext provides SDK client context for a given org
-type OrgContext struct {
-	OrgID                string
-	CtxProvider          contextAPI.ClientProvider
-	SigningIdentity      msp.SigningIdentity
-	ResMgmt              *resmgmt.Client
-	Peers                []fabAPI.Peer
-	AnchorPeerConfigFile string
+type OrgContext = testutil.OrgContext
+
+// channelConfig returns the testutil channel config for the given channel using the test fixtures
+func channelConfig(channelID, channelTxFile string) testutil.ChannelConfig {
+	return testutil.ChannelConfig{
+		ChannelID:        channelID,
+		ChannelTxFile:    channelTxFile,
+		ConfigDir:        GetChannelConfigPath(""),
+		OrdererOrgName:   OrdererOrgName,
+		OrdererAdminUser: AdminUser,
+		OrdererEndpoint:  ordererEndpoint,
+	}
 }
 
 // CreateChannelAndUpdateAnchorPeers creates the channel and updates all of the anchor peers for all orgs
 func CreateChannelAndUpdateAnchorPeers(t *testing.T, sdk *fabsdk.FabricSDK, channelID string, channelConfigFile string, orgsContext []*OrgContext) error {
-	ordererCtx := sdk.Context(fabsdk.WithUser(AdminUser), fabsdk.WithOrg(OrdererOrgName))
-
-	// Channel management client is responsible for managing channels (create/update channel)
-	chMgmtClient, err := resmgmt.New(ordererCtx)
-	if err != nil {
-		return errors.New("failed to get a new resmgmt client for orderer")
-	}
-
-	var lastConfigBlock uint64
-	var signingIdentities []msp.SigningIdentity
-	for _, orgCtx := range orgsContext {
-		signingIdentities = append(signingIdentities, orgCtx.SigningIdentity)
-	}
-
-	req := resmgmt.SaveChannelRequest{
-		ChannelID:         channelID,
-		ChannelConfigPath: GetChannelConfigPath(channelConfigFile),
-		SigningIdentities: signingIdentities,
-	}
-	_, err = chMgmtClient.SaveChannel(req, resmgmt.WithRetry(retry.DefaultResMgmtOpts), resmgmt.WithOrdererEndpoint("orderer.example.com"))
-	if err != nil {
-		return err
-	}
-
-	lastConfigBlock = WaitForOrdererConfigUpdate(t, orgsContext[0].ResMgmt, channelID, true, lastConfigBlock)
-
-	for _, orgCtx := range orgsContext {
-		req := resmgmt.SaveChannelRequest{
-			ChannelID:         channelID,
-			ChannelConfigPath: GetChannelConfigPath(orgCtx.AnchorPeerConfigFile),
-			SigningIdentities: []msp.SigningIdentity{orgCtx.SigningIdentity},
-		}
-		if _, err := orgCtx.ResMgmt.SaveChannel(req, resmgmt.WithRetry(retry.DefaultResMgmtOpts), resmgmt.WithOrdererEndpoint("orderer.example.com")); err != nil {
-			return err
-		}
-
-		lastConfigBlock = WaitForOrdererConfigUpdate(t, orgCtx.ResMgmt, channelID, false, lastConfigBlock)
-	}
-
-	return nil
+	return testutil.CreateChannelAndUpdateAnchorPeers(sdk, channelConfig(channelID, channelConfigFile), orgsContext)
 }
 
 // JoinPeersToChannel joins all peers in all of the given orgs to the given channel
 func JoinPeersToChannel(channelID string, orgsContext []*OrgContext) error {
-	for _, orgCtx := range orgsContext {
-		err := orgCtx.ResMgmt.JoinChannel(
-			channelID,
-			resmgmt.WithRetry(retry.DefaultResMgmtOpts),
-			resmgmt.WithOrdererEndpoint("orderer.example.com"),
-			resmgmt.WithTargets(orgCtx.Peers...),
-		)
-		if err != nil {
-			return errors.Wrapf(err, "failed to join peers in org [%s] to channel [%s]", orgCtx.OrgID, channelID)
-		}
-	}
-	return nil
+	return testutil.JoinPeersToChannel(channelID, ordererEndpoint, orgsContext)
 }
 
 // InstallChaincodeWithOrgContexts installs the given chaincode to orgs
 func InstallChaincodeWithOrgContexts(orgs []*OrgContext, ccPkg *resource.CCPackage, ccPath, ccID, ccVersion string) error {
-	for _, orgCtx := range orgs {
-		if err := InstallChaincode(orgCtx.ResMgmt, ccPkg, ccPath, ccID, ccVersion, orgCtx.Peers); err != nil {
-			return errors.Wrapf(err, "failed to install chaincode to peers in org [%s]", orgCtx.OrgID)
-		}
-	}
-
-	return nil
+	return testutil.InstallChaincodeWithOrgContexts(orgs, ccPkg, ccPath, ccID, ccVersion)
 }
 
 // InstallChaincode installs the given chaincode to the given peers
 func InstallChaincode(resMgmt *resmgmt.Client, ccPkg *resource.CCPackage, ccPath, ccName, ccVersion string, localPeers []fabAPI.Peer) error {
-	installCCReq := resmgmt.InstallCCRequest{Name: ccName, Path: ccPath, Version: ccVersion, Package: ccPkg}
-	_, err := resMgmt.InstallCC(installCCReq, resmgmt.WithRetry(retry.DefaultResMgmtOpts))
-	if err != nil {
-		return err
-	}
-
-	installed, err := queryInstalledCC(resMgmt, ccName, ccVersion, localPeers)
-
-	if err != nil {
-		return err
-	}
-
-	if !installed {
-		return errors.New("chaincode was not installed on all peers")
-	}
-
-	return nil
+	return testutil.InstallChaincode(resMgmt, ccPkg, ccPath, ccName, ccVersion, localPeers)
 }
 
 // InstantiateChaincode instantiates the given chaincode to the given channel
 func InstantiateChaincode(resMgmt *resmgmt.Client, channelID, ccName, ccPath, ccVersion string, ccPolicyStr string, args [][]byte, collConfigs ...*cb.CollectionConfig) (resmgmt.InstantiateCCResponse, error) {
-	ccPolicy, err := cauthdsl.FromString(ccPolicyStr)
-	if err != nil {
-		return resmgmt.InstantiateCCResponse{}, errors.Wrapf(err, "error creating CC policy [%s]", ccPolicyStr)
-	}
-
-	return resMgmt.InstantiateCC(
-		channelID,
-		resmgmt.InstantiateCCRequest{
-			Name:       ccName,
-			Path:       ccPath,
-			Version:    ccVersion,
-			Args:       args,
-			Policy:     ccPolicy,
-			CollConfig: collConfigs,
-		},
-		resmgmt.WithRetry(retry.DefaultResMgmtOpts),
-	)
+	return testutil.InstantiateChaincode(resMgmt, channelID, ccName, ccPath, ccVersion, ccPolicyStr, args, collConfigs...)
 }
 
 // DiscoverLocalPeers queries the local peers for the given MSP context and returns all of the peers. If
 // the number of peers does not match the expected number then an error is returned.
 func DiscoverLocalPeers(ctxProvider contextAPI.ClientProvider, expectedPeers int) ([]fabAPI.Peer, error) {
-	ctx, err := contextImpl.NewLocal(ctxProvider)
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating local context")
-	}
-
-	discoveredPeers, err := retry.NewInvoker(retry.New(retry.TestRetryOpts)).Invoke(
-		func() (interface{}, error) {
-			peers, err := ctx.LocalDiscoveryService().GetPeers()
-			if err != nil {
-				return nil, errors.Wrapf(err, "error getting peers for MSP [%s]", ctx.Identifier().MSPID)
-			}
-			if len(peers) < expectedPeers {
-				return nil, status.New(status.TestStatus, status.GenericTransient.ToInt32(), fmt.Sprintf("Expecting %d peers but got %d", expectedPeers, len(peers)), nil)
-			}
-			return peers, nil
-		},
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	return discoveredPeers.([]fabAPI.Peer), nil
+	return testutil.DiscoverLocalPeers(ctxProvider, expectedPeers)
 }
 
 // EnsureChannelCreatedAndPeersJoined creates a channel, joins all peers in the given orgs to the channel and updates the anchor peers of each org.
 func EnsureChannelCreatedAndPeersJoined(t *testing.T, sdk *fabsdk.FabricSDK, channelID string, channelTxFile string, orgsContext []*OrgContext) error {
-	joined, err := IsJoinedChannel(channelID, orgsContext[0].ResMgmt, orgsContext[0].Peers[0])
-	if err != nil {
-		return err
-	}
-
-	if joined {
-		return nil
-	}
-
-	// Create the channel and update anchor peers for all orgs
-	if err := CreateChannelAndUpdateAnchorPeers(t, sdk, channelID, channelTxFile, orgsContext); err != nil {
-		return err
-	}
-
-	return JoinPeersToChannel(channelID, orgsContext)
+	return testutil.EnsureChannelCreatedAndPeersJoined(sdk, channelConfig(channelID, channelTxFile), orgsContext)
 }
 
 // WaitForOrdererConfigUpdate waits until the config block update has been committed.
 // In Fabric 1.0 there is a bug that panics the orderer if more than one config update is added to the same block.
 // This function may be invoked after each config update as a workaround.
 func WaitForOrdererConfigUpdate(t *testing.T, client *resmgmt.Client, channelID string, genesis bool, lastConfigBlock uint64) uint64 {
-
-	blockNum, err := retry.NewInvoker(retry.New(retry.TestRetryOpts)).Invoke(
-		func() (interface{}, error) {
-			chConfig, err := client.QueryConfigFromOrderer(channelID, resmgmt.WithOrdererEndpoint("orderer.example.com"))
-			if err != nil {
-				return nil, status.New(status.TestStatus, status.GenericTransient.ToInt32(), err.Error(), nil)
-			}
-
-			currentBlock := chConfig.BlockNumber()
-			if currentBlock <= lastConfigBlock && !genesis {
-				return nil, status.New(status.TestStatus, status.GenericTransient.ToInt32(), fmt.Sprintf("Block number was not incremented [%d, %d]", currentBlock, lastConfigBlock), nil)
-			}
-			return &currentBlock, nil
-		},
-	)
-
+	blockNum, err := testutil.WaitForOrdererConfigUpdate(client, channelID, ordererEndpoint, genesis, lastConfigBlock)
 	require.NoError(t, err)
-	return *blockNum.(*uint64)
-}
-
-func queryInstalledCC(resMgmt *resmgmt.Client, ccName, ccVersion string, peers []fabAPI.Peer) (bool, error) {
-	installed, err := retry.NewInvoker(retry.New(retry.TestRetryOpts)).Invoke(
-		func() (interface{}, error) {
-			ok, err := isCCInstalled(resMgmt, ccName, ccVersion, peers)
-			if err != nil {
-				return &ok, err
-			}
-			if !ok {
-				return &ok, status.New(status.TestStatus, status.GenericTransient.ToInt32(), fmt.Sprintf("Chaincode [%s:%s] is not installed on all peers in Org1", ccName, ccVersion), nil)
-			}
-			return &ok, nil
-		},
-	)
-
-	if err != nil {
-		s, ok := status.FromError(err)
-		if ok && s.Code == status.GenericTransient.ToInt32() {
-			return false, nil
-		}
-		return false, errors.WithMessage(err, "isCCInstalled invocation failed")
-	}
-
-	return *(installed).(*bool), nil
-}
-
-func isCCInstalled(resMgmt *resmgmt.Client, ccName, ccVersion string, peers []fabAPI.Peer) (bool, error) {
-	installedOnAllPeers := true
-	for _, peer := range peers {
-		resp, err := resMgmt.QueryInstalledChaincodes(resmgmt.WithTargets(peer))
-		if err != nil {
-			return false, errors.WithMessage(err, "querying for installed chaincodes failed")
-		}
-
-		found := false
-		for _, ccInfo := range resp.Chaincodes {
-			if ccInfo.Name == ccName && ccInfo.Version == ccVersion {
-				found = true
-				break
-			}
-		}
-		if !found {
-			installedOnAllPeers = false
-		}
-	}
-	return installedOnAllPeers, nil
+	return blockNum
 }
@@ -724,6 +724,22 @@ func (c *eventServiceConfig) BlockHeightMonitorPeriod() time.Duration {
 	return 5 * time.Second
 }
 
+func (c *eventServiceConfig) ShareConnections() bool {
+	return false
+}
+
+func (c *eventServiceConfig) KeepAliveTime() time.Duration {
+	return 0
+}
+
+func (c *eventServiceConfig) KeepAliveTimeout() time.Duration {
+	return 0
+}
+
+func (c *eventServiceConfig) StreamIdleTimeout() time.Duration {
+	return 0
+}
+
 type exampleTLSClientCerts struct {
 	RWLock sync.RWMutex
 }
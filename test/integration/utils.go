@@ -13,7 +13,6 @@ import (
 	"strings"
 	"testing"
 
-	mspclient "github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
@@ -22,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/testutil"
 	"github.com/pkg/errors"
 )
 
@@ -155,65 +155,19 @@ func OrgTargetPeers(orgs []string, configBackend ...core.ConfigBackend) ([]strin
 
 // SetupMultiOrgContext creates an OrgContext for two organizations in the org channel.
 func SetupMultiOrgContext(sdk *fabsdk.FabricSDK, org1Name string, org2Name string, org1AdminUser string, org2AdminUser string) ([]*OrgContext, error) {
-	org1AdminContext := sdk.Context(fabsdk.WithUser(org1AdminUser), fabsdk.WithOrg(org1Name))
-	org1ResMgmt, err := resmgmt.New(org1AdminContext)
+	org1Ctx, err := testutil.NewOrgContext(sdk, org1Name, org1AdminUser, 2)
 	if err != nil {
 		return nil, err
 	}
+	org1Ctx.AnchorPeerConfigFile = "orgchannelOrg1MSPanchors.tx"
 
-	org1MspClient, err := mspclient.New(sdk.Context(), mspclient.WithOrg(org1Name))
+	org2Ctx, err := testutil.NewOrgContext(sdk, org2Name, org2AdminUser, 1)
 	if err != nil {
 		return nil, err
 	}
-	org1Admin, err := org1MspClient.GetSigningIdentity(org1AdminUser)
-	if err != nil {
-		return nil, err
-	}
-
-	org2AdminContext := sdk.Context(fabsdk.WithUser(org2AdminUser), fabsdk.WithOrg(org2Name))
-	org2ResMgmt, err := resmgmt.New(org2AdminContext)
-	if err != nil {
-		return nil, err
-	}
-
-	org2MspClient, err := mspclient.New(sdk.Context(), mspclient.WithOrg(org2Name))
-	if err != nil {
-		return nil, err
-	}
-	org2Admin, err := org2MspClient.GetSigningIdentity(org2AdminUser)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ensure that Gossip has propagated its view of local peers before invoking
-	// install since some peers may be missed if we call InstallCC too early
-	org1Peers, err := DiscoverLocalPeers(org1AdminContext, 2)
-	if err != nil {
-		return nil, errors.WithMessage(err, "discovery of local peers failed")
-	}
-	org2Peers, err := DiscoverLocalPeers(org2AdminContext, 1)
-	if err != nil {
-		return nil, errors.WithMessage(err, "discovery of local peers failed")
-	}
+	org2Ctx.AnchorPeerConfigFile = "orgchannelOrg2MSPanchors.tx"
 
-	return []*OrgContext{
-		{
-			OrgID:                org1Name,
-			CtxProvider:          org1AdminContext,
-			ResMgmt:              org1ResMgmt,
-			Peers:                org1Peers,
-			SigningIdentity:      org1Admin,
-			AnchorPeerConfigFile: "orgchannelOrg1MSPanchors.tx",
-		},
-		{
-			OrgID:                org2Name,
-			CtxProvider:          org2AdminContext,
-			ResMgmt:              org2ResMgmt,
-			Peers:                org2Peers,
-			SigningIdentity:      org2Admin,
-			AnchorPeerConfigFile: "orgchannelOrg2MSPanchors.tx",
-		},
-	}, nil
+	return []*OrgContext{org1Ctx, org2Ctx}, nil
 }
 
 // HasPeerJoinedChannel checks whether the peer has already joined the channel.
@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package benchmarks
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
+	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const (
+	channelID = "mychannel"
+	ccID      = "examplecc"
+	numPeers  = 2
+)
+
+var (
+	executeRequest = channel.Request{ChaincodeID: ccID, Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+	queryRequest   = channel.Request{ChaincodeID: ccID, Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+)
+
+func BenchmarkExecute(b *testing.B) {
+	client := newChannelClient(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Execute(executeRequest); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkExecuteParallel(b *testing.B) {
+	client := newChannelClient(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := client.Execute(executeRequest); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkQuery(b *testing.B) {
+	client := newChannelClient(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Query(queryRequest); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQueryParallel(b *testing.B) {
+	client := newChannelClient(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := client.Query(queryRequest); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkEventDispatch measures the dispatching of a block to block, chaincode and
+// transaction status registrations
+func BenchmarkEventDispatch(b *testing.B) {
+	eventService := service.New(dispatcher.New())
+	if err := eventService.Start(); err != nil {
+		b.Fatal(err)
+	}
+	defer eventService.Stop()
+
+	blockReg, blockch, err := eventService.RegisterBlockEvent()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer eventService.Unregister(blockReg)
+
+	ccReg, ccch, err := eventService.RegisterChaincodeEvent(ccID, "event.*")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer eventService.Unregister(ccReg)
+
+	producer := servicemocks.NewBlockProducer()
+	blocks := make([]*fab.BlockEvent, b.N)
+	for i := range blocks {
+		txID := fmt.Sprintf("txid%d", i)
+		blocks[i] = &fab.BlockEvent{Block: producer.NewBlock(channelID,
+			servicemocks.NewTransactionWithCCEvent(txID, pb.TxValidationCode_VALID, ccID, "event1", []byte("payload")),
+		)}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i, event := range blocks {
+		txReg, txch, err := eventService.RegisterTxStatusEvent(fmt.Sprintf("txid%d", i))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := eventService.Submit(event); err != nil {
+			b.Fatal(err)
+		}
+		<-blockch
+		<-ccch
+		<-txch
+		eventService.Unregister(txReg)
+	}
+}
+
+// newChannelClient returns a channel client which endorses with in-process mock peers
+// and commits through a mock orderer and event service
+func newChannelClient(b *testing.B) *channel.Client {
+	var peers []fab.Peer
+	for i := 0; i < numPeers; i++ {
+		peer := fcmocks.NewMockPeer(fmt.Sprintf("peer%d", i), fmt.Sprintf("grpcs://peer%d.example.com:7051", i))
+		peer.Payload = []byte("value")
+		peer.SetRwSets(fcmocks.NewRwSet(ccID))
+		peers = append(peers, peer)
+	}
+
+	ctx := fcmocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP"))
+
+	chProvider, err := fcmocks.NewMockChannelProvider(ctx)
+	if err != nil {
+		b.Fatal(err)
+	}
+	chService, err := chProvider.ChannelService(ctx, channelID)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	mockChService := chService.(*fcmocks.MockChannelService)
+	mockChService.SetTransactor(&txnmocks.MockTransactor{
+		Ctx:       ctx,
+		ChannelID: channelID,
+		Orderers:  []fab.Orderer{fcmocks.NewMockOrderer("", nil)},
+	})
+	mockChService.SetDiscovery(txnmocks.NewMockDiscoveryService(nil, peers...))
+	mockChService.SetSelection(txnmocks.NewMockSelectionService(nil, peers...))
+	ctx.MockProviderContext.ChannelProvider().(*fcmocks.MockChannelProvider).SetCustomChannelService(chService)
+
+	clientProvider := func() (context.Client, error) {
+		return ctx, nil
+	}
+	client, err := channel.New(func() (context.Channel, error) {
+		return contextImpl.NewChannel(clientProvider, channelID)
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return client
+}
@@ -0,0 +1,11 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package benchmarks contains benchmarks of the channel client (Execute and Query) and of event
+// dispatching which run against in-process mock peers, orderers and event services. Unlike the
+// benchmarks under test/performance/pkg they don't require any fixtures or a metrics server, so they
+// may be used to measure the effect of changes to handlers, marshalling, caches, etc. (see README.md).
+package benchmarks
@@ -0,0 +1,124 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// loadgen drives a mix of read and write transactions against a network and prints the
+// throughput and latency percentiles of each stage, e.g.:
+//
+//  loadgen -config config.yaml -channel mychannel -org org1 -user User1 -cc kvcc \
+//    -read 80 -payload 100,1024 -stages 10:30s,50:1m
+//
+// The chaincode must provide a read function taking a key and a write function taking a key and a value
+// (see the -readfcn and -writefcn flags).
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/loadgen"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/pkg/errors"
+)
+
+func main() {
+	configFile := flag.String("config", "", "SDK configuration file")
+	channelID := flag.String("channel", "", "Channel ID")
+	org := flag.String("org", "", "Organization of the user")
+	user := flag.String("user", "User1", "User which signs the transactions")
+	ccID := flag.String("cc", "", "Chaincode ID")
+	readPercent := flag.Int("read", 50, "Percentage (0-100) of transactions which are reads")
+	readFcn := flag.String("readfcn", "get", "Chaincode function for reads (invoked with a key)")
+	writeFcn := flag.String("writefcn", "put", "Chaincode function for writes (invoked with a key and a value)")
+	keys := flag.Int("keys", 1000, "Number of distinct keys")
+	payload := flag.String("payload", "100", "Comma separated list of value sizes (bytes) for writes")
+	stages := flag.String("stages", "10:30s", "Comma separated list of stages (<concurrency>:<duration>)")
+	flag.Parse()
+
+	if err := run(*configFile, *channelID, *org, *user, loadgen.Config{
+		ChaincodeID: *ccID,
+		ReadPercent: *readPercent,
+		ReadFcn:     *readFcn,
+		WriteFcn:    *writeFcn,
+		KeySpace:    *keys,
+	}, *payload, *stages); err != nil {
+		fmt.Fprintf(os.Stderr, "loadgen failed: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(configFile, channelID, org, user string, cfg loadgen.Config, payload, stages string) error {
+	if configFile == "" || channelID == "" {
+		return errors.New("-config and -channel are required")
+	}
+
+	var err error
+	cfg.Stages, err = loadgen.ParseStages(stages)
+	if err != nil {
+		return err
+	}
+	cfg.PayloadSizes, err = parseSizes(payload)
+	if err != nil {
+		return err
+	}
+
+	sdk, err := fabsdk.New(config.FromFile(configFile))
+	if err != nil {
+		return errors.WithMessage(err, "failed to create SDK")
+	}
+	defer sdk.Close()
+
+	opts := []fabsdk.ContextOption{fabsdk.WithUser(user)}
+	if org != "" {
+		opts = append(opts, fabsdk.WithOrg(org))
+	}
+	client, err := channel.New(sdk.ChannelContext(channelID, opts...))
+	if err != nil {
+		return errors.WithMessage(err, "failed to create channel client")
+	}
+
+	generator, err := loadgen.New(client, cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	report, err := generator.Run(ctx)
+	if report != nil {
+		fmt.Print(report)
+	}
+	if err == context.Canceled {
+		return nil
+	}
+	return err
+}
+
+func parseSizes(s string) ([]int, error) {
+	var sizes []int
+	for _, v := range strings.Split(s, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || size < 0 {
+			return nil, errors.Errorf("invalid payload size [%s]", v)
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
@@ -0,0 +1,45 @@
+#!/bin/bash
+#
+# Copyright SecureKey Technologies Inc. All Rights Reserved.
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+# Runs the mock-backed benchmarks and compares the results with the published baseline.
+#
+# Environment variables that affect this script:
+# BENCH_FILTER: Regular expression selecting the benchmarks to run (default: all).
+# BENCH_COUNT: Number of times each benchmark is run (default: 5).
+# BENCH_OUTPUT: File to which the results are written (default: a temporary file).
+# BENCH_BASELINE: Baseline results to compare with (default: test/performance/benchmarks/baseline.txt).
+# BENCH_UPDATE_BASELINE: Boolean on whether to replace the baseline with the results.
+
+set -e
+
+GO_CMD="${GO_CMD:-go}"
+BENCH_FILTER="${BENCH_FILTER:-.}"
+BENCH_COUNT="${BENCH_COUNT:-5}"
+BENCH_OUTPUT="${BENCH_OUTPUT:-$(mktemp)}"
+BENCH_BASELINE="${BENCH_BASELINE:-test/performance/benchmarks/baseline.txt}"
+BENCH_UPDATE_BASELINE="${BENCH_UPDATE_BASELINE:-false}"
+
+declare -a PKGS=(
+    "./test/performance/benchmarks"
+    "./pkg/fab/txn"
+    "./pkg/fab/events/service/dispatcher"
+)
+
+echo "Running" $(basename "$0")
+
+${GO_CMD} test -run=^$ -bench="${BENCH_FILTER}" -benchmem -count="${BENCH_COUNT}" "${PKGS[@]}" | grep -v "^ok\|^PASS" | tee "${BENCH_OUTPUT}"
+
+if [ "${BENCH_UPDATE_BASELINE}" = "true" ]; then
+    cp "${BENCH_OUTPUT}" "${BENCH_BASELINE}"
+    echo "Baseline updated: ${BENCH_BASELINE}"
+    exit 0
+fi
+
+if command -v benchstat >/dev/null 2>&1; then
+    benchstat "${BENCH_BASELINE}" "${BENCH_OUTPUT}"
+else
+    echo "benchstat not found (go get golang.org/x/perf/cmd/benchstat) - results written to ${BENCH_OUTPUT}"
+fi
@@ -20,6 +20,7 @@ source ${SCRIPT_DIR}/lib/linter.sh
 
 # Find all packages that should be linted.
 declare -a PKG_SRC=(
+    "./cmd"
     "./pkg"
     "./test"
 )
@@ -35,6 +35,7 @@ echo "Running" $(basename "$0")
 
 # Find all packages that should be tested.
 declare -a PKG_SRC=(
+    "./cmd"
     "./pkg"
     "./test"
 )
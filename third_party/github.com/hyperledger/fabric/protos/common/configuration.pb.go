@@ -36,7 +36,7 @@ func (m *HashingAlgorithm) Reset()         { *m = HashingAlgorithm{} }
 func (m *HashingAlgorithm) String() string { return proto.CompactTextString(m) }
 func (*HashingAlgorithm) ProtoMessage()    {}
 func (*HashingAlgorithm) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_c60fbe5ebb3de531, []int{0}
+	return fileDescriptor_configuration_4cd8537264c91958, []int{0}
 }
 func (m *HashingAlgorithm) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_HashingAlgorithm.Unmarshal(m, b)
@@ -78,7 +78,7 @@ func (m *BlockDataHashingStructure) Reset()         { *m = BlockDataHashingStruc
 func (m *BlockDataHashingStructure) String() string { return proto.CompactTextString(m) }
 func (*BlockDataHashingStructure) ProtoMessage()    {}
 func (*BlockDataHashingStructure) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_c60fbe5ebb3de531, []int{1}
+	return fileDescriptor_configuration_4cd8537264c91958, []int{1}
 }
 func (m *BlockDataHashingStructure) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockDataHashingStructure.Unmarshal(m, b)
@@ -118,7 +118,7 @@ func (m *OrdererAddresses) Reset()         { *m = OrdererAddresses{} }
 func (m *OrdererAddresses) String() string { return proto.CompactTextString(m) }
 func (*OrdererAddresses) ProtoMessage()    {}
 func (*OrdererAddresses) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_c60fbe5ebb3de531, []int{2}
+	return fileDescriptor_configuration_4cd8537264c91958, []int{2}
 }
 func (m *OrdererAddresses) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_OrdererAddresses.Unmarshal(m, b)
@@ -157,7 +157,7 @@ func (m *Consortium) Reset()         { *m = Consortium{} }
 func (m *Consortium) String() string { return proto.CompactTextString(m) }
 func (*Consortium) ProtoMessage()    {}
 func (*Consortium) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_c60fbe5ebb3de531, []int{3}
+	return fileDescriptor_configuration_4cd8537264c91958, []int{3}
 }
 func (m *Consortium) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Consortium.Unmarshal(m, b)
@@ -225,7 +225,7 @@ func (m *Capabilities) Reset()         { *m = Capabilities{} }
 func (m *Capabilities) String() string { return proto.CompactTextString(m) }
 func (*Capabilities) ProtoMessage()    {}
 func (*Capabilities) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_c60fbe5ebb3de531, []int{4}
+	return fileDescriptor_configuration_4cd8537264c91958, []int{4}
 }
 func (m *Capabilities) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Capabilities.Unmarshal(m, b)
@@ -266,7 +266,7 @@ func (m *Capability) Reset()         { *m = Capability{} }
 func (m *Capability) String() string { return proto.CompactTextString(m) }
 func (*Capability) ProtoMessage()    {}
 func (*Capability) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_c60fbe5ebb3de531, []int{5}
+	return fileDescriptor_configuration_4cd8537264c91958, []int{5}
 }
 func (m *Capability) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Capability.Unmarshal(m, b)
@@ -286,6 +286,133 @@ func (m *Capability) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Capability proto.InternalMessageInfo
 
+// Consenter represents a consenting node (i.e. replica).
+type Consenter struct {
+	Id                   uint32   `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Host                 string   `protobuf:"bytes,2,opt,name=host" json:"host,omitempty"`
+	Port                 uint32   `protobuf:"varint,3,opt,name=port" json:"port,omitempty"`
+	MspId                string   `protobuf:"bytes,4,opt,name=msp_id,json=mspId" json:"msp_id,omitempty"`
+	Identity             []byte   `protobuf:"bytes,5,opt,name=identity,proto3" json:"identity,omitempty"`
+	ClientTlsCert        []byte   `protobuf:"bytes,6,opt,name=client_tls_cert,json=clientTlsCert,proto3" json:"client_tls_cert,omitempty"`
+	ServerTlsCert        []byte   `protobuf:"bytes,7,opt,name=server_tls_cert,json=serverTlsCert,proto3" json:"server_tls_cert,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Consenter) Reset()         { *m = Consenter{} }
+func (m *Consenter) String() string { return proto.CompactTextString(m) }
+func (*Consenter) ProtoMessage()    {}
+func (*Consenter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_configuration_4cd8537264c91958, []int{6}
+}
+func (m *Consenter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Consenter.Unmarshal(m, b)
+}
+func (m *Consenter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Consenter.Marshal(b, m, deterministic)
+}
+func (dst *Consenter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Consenter.Merge(dst, src)
+}
+func (m *Consenter) XXX_Size() int {
+	return xxx_messageInfo_Consenter.Size(m)
+}
+func (m *Consenter) XXX_DiscardUnknown() {
+	xxx_messageInfo_Consenter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Consenter proto.InternalMessageInfo
+
+func (m *Consenter) GetId() uint32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Consenter) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *Consenter) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *Consenter) GetMspId() string {
+	if m != nil {
+		return m.MspId
+	}
+	return ""
+}
+
+func (m *Consenter) GetIdentity() []byte {
+	if m != nil {
+		return m.Identity
+	}
+	return nil
+}
+
+func (m *Consenter) GetClientTlsCert() []byte {
+	if m != nil {
+		return m.ClientTlsCert
+	}
+	return nil
+}
+
+func (m *Consenter) GetServerTlsCert() []byte {
+	if m != nil {
+		return m.ServerTlsCert
+	}
+	return nil
+}
+
+// Orderers is encoded into the configuration transaction as a configuration item of type Orderer
+// with a Key of "Orderers" and a Value of Orderers as marshaled protobuf bytes
+type Orderers struct {
+	ConsenterMapping     []*Consenter `protobuf:"bytes,1,rep,name=consenter_mapping,json=consenterMapping" json:"consenter_mapping,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Orderers) Reset()         { *m = Orderers{} }
+func (m *Orderers) String() string { return proto.CompactTextString(m) }
+func (*Orderers) ProtoMessage()    {}
+func (*Orderers) Descriptor() ([]byte, []int) {
+	return fileDescriptor_configuration_4cd8537264c91958, []int{7}
+}
+func (m *Orderers) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Orderers.Unmarshal(m, b)
+}
+func (m *Orderers) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Orderers.Marshal(b, m, deterministic)
+}
+func (dst *Orderers) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Orderers.Merge(dst, src)
+}
+func (m *Orderers) XXX_Size() int {
+	return xxx_messageInfo_Orderers.Size(m)
+}
+func (m *Orderers) XXX_DiscardUnknown() {
+	xxx_messageInfo_Orderers.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Orderers proto.InternalMessageInfo
+
+func (m *Orderers) GetConsenterMapping() []*Consenter {
+	if m != nil {
+		return m.ConsenterMapping
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*HashingAlgorithm)(nil), "sdk.common.HashingAlgorithm")
 	proto.RegisterType((*BlockDataHashingStructure)(nil), "sdk.common.BlockDataHashingStructure")
@@ -294,32 +421,43 @@ func init() {
 	proto.RegisterType((*Capabilities)(nil), "sdk.common.Capabilities")
 	proto.RegisterMapType((map[string]*Capability)(nil), "common.Capabilities.CapabilitiesEntry")
 	proto.RegisterType((*Capability)(nil), "sdk.common.Capability")
+	proto.RegisterType((*Consenter)(nil), "sdk.common.Consenter")
+	proto.RegisterType((*Orderers)(nil), "sdk.common.Orderers")
 }
 
 func init() {
-	proto.RegisterFile("common/configuration.proto", fileDescriptor_configuration_c60fbe5ebb3de531)
-}
-
-var fileDescriptor_configuration_c60fbe5ebb3de531 = []byte{
-	// 314 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0x41, 0x6b, 0xf2, 0x40,
-	0x10, 0x86, 0x89, 0x7e, 0x0a, 0x8e, 0x7e, 0x60, 0x97, 0x1e, 0xac, 0xf4, 0x10, 0x42, 0x91, 0x40,
-	0x21, 0x69, 0xed, 0xa5, 0xf4, 0xa6, 0xb6, 0x50, 0x7a, 0x29, 0xc4, 0x5b, 0x6f, 0x9b, 0x64, 0x4c,
-	0x16, 0x93, 0x5d, 0x99, 0xdd, 0xb4, 0xe4, 0x57, 0xf5, 0x2f, 0x16, 0xb3, 0x16, 0x23, 0xf6, 0x36,
-	0xcf, 0xce, 0xf3, 0xce, 0xce, 0xb2, 0x30, 0x4d, 0x54, 0x59, 0x2a, 0x19, 0x26, 0x4a, 0x6e, 0x44,
-	0x56, 0x11, 0x37, 0x42, 0xc9, 0x60, 0x47, 0xca, 0x28, 0xd6, 0xb7, 0x3d, 0x6f, 0x06, 0xe3, 0x57,
-	0xae, 0x73, 0x21, 0xb3, 0x45, 0x91, 0x29, 0x12, 0x26, 0x2f, 0x19, 0x83, 0x7f, 0x92, 0x97, 0x38,
-	0x71, 0x5c, 0xc7, 0x1f, 0x44, 0x4d, 0xed, 0xdd, 0xc3, 0xd5, 0xb2, 0x50, 0xc9, 0xf6, 0x99, 0x1b,
-	0x7e, 0x08, 0xac, 0x0d, 0x55, 0x89, 0xa9, 0x08, 0xd9, 0x25, 0xf4, 0xbe, 0x44, 0x6a, 0xf2, 0x26,
-	0xf1, 0x3f, 0xb2, 0xe0, 0xdd, 0xc1, 0xf8, 0x9d, 0x52, 0x24, 0xa4, 0x45, 0x9a, 0x12, 0x6a, 0x8d,
-	0x9a, 0x5d, 0xc3, 0x80, 0xff, 0xc2, 0xc4, 0x71, 0xbb, 0xfe, 0x20, 0x3a, 0x1e, 0x78, 0x2e, 0xc0,
-	0x4a, 0x49, 0xad, 0xc8, 0x88, 0xea, 0xef, 0x35, 0xbe, 0x1d, 0x18, 0xad, 0xf8, 0x8e, 0xc7, 0xa2,
-	0x10, 0x46, 0xa0, 0x66, 0x6f, 0x30, 0x4a, 0x5a, 0xdc, 0xcc, 0x1c, 0xce, 0x67, 0x81, 0x7d, 0x5e,
-	0xd0, 0x76, 0x4f, 0xe0, 0x45, 0x1a, 0xaa, 0xa3, 0x93, 0xec, 0x74, 0x0d, 0x17, 0x67, 0x0a, 0x1b,
-	0x43, 0x77, 0x8b, 0xf5, 0x61, 0x89, 0x7d, 0xc9, 0x7c, 0xe8, 0x7d, 0xf2, 0xa2, 0xc2, 0x49, 0xc7,
-	0x75, 0xfc, 0xe1, 0x9c, 0x9d, 0xdd, 0x55, 0x47, 0x56, 0x78, 0xea, 0x3c, 0x3a, 0xde, 0x08, 0xe0,
-	0xd8, 0x58, 0xae, 0xe1, 0x46, 0x51, 0x16, 0xe4, 0xf5, 0x0e, 0xa9, 0xc0, 0x34, 0x43, 0x0a, 0x36,
-	0x3c, 0x26, 0x91, 0xd8, 0x6f, 0xd1, 0x87, 0x59, 0x1f, 0xb7, 0x99, 0x30, 0x79, 0x15, 0xef, 0x31,
-	0x6c, 0xc9, 0xa1, 0x95, 0x43, 0x2b, 0x87, 0x56, 0x8e, 0xfb, 0x0d, 0x3e, 0xfc, 0x04, 0x00, 0x00,
-	0xff, 0xff, 0xd6, 0x7e, 0xb4, 0x89, 0xf0, 0x01, 0x00, 0x00,
+	proto.RegisterFile("common/configuration.proto", fileDescriptor_configuration_4cd8537264c91958)
+}
+
+var fileDescriptor_configuration_4cd8537264c91958 = []byte{
+	// 456 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0xdf, 0x8a, 0xd3, 0x40,
+	0x14, 0xc6, 0x49, 0x77, 0x5b, 0xb7, 0x67, 0xbb, 0xda, 0x0e, 0x0a, 0xb1, 0x78, 0x51, 0x82, 0x94,
+	0x82, 0xd0, 0xea, 0x7a, 0x23, 0x5e, 0x08, 0xbb, 0x55, 0xd0, 0x05, 0x11, 0x52, 0xaf, 0xbc, 0x29,
+	0xd3, 0xe4, 0x6c, 0x32, 0x6c, 0x32, 0x13, 0xce, 0x9c, 0xac, 0xf4, 0xa9, 0x7c, 0x0b, 0x9f, 0x4b,
+	0x32, 0x93, 0xfe, 0x59, 0xd6, 0xbb, 0xf3, 0x7d, 0xf3, 0x3b, 0x27, 0xf3, 0xcd, 0x09, 0x8c, 0x13,
+	0x53, 0x96, 0x46, 0x2f, 0x12, 0xa3, 0x6f, 0x55, 0x56, 0x93, 0x64, 0x65, 0xf4, 0xbc, 0x22, 0xc3,
+	0x46, 0xf4, 0xfc, 0x59, 0x34, 0x85, 0xe1, 0x57, 0x69, 0x73, 0xa5, 0xb3, 0xab, 0x22, 0x33, 0xa4,
+	0x38, 0x2f, 0x85, 0x80, 0x53, 0x2d, 0x4b, 0x0c, 0x83, 0x49, 0x30, 0xeb, 0xc7, 0xae, 0x8e, 0xde,
+	0xc1, 0xcb, 0xeb, 0xc2, 0x24, 0x77, 0x9f, 0x25, 0xcb, 0xb6, 0x61, 0xc5, 0x54, 0x27, 0x5c, 0x13,
+	0x8a, 0xe7, 0xd0, 0xfd, 0xad, 0x52, 0xce, 0x5d, 0xc7, 0x45, 0xec, 0x45, 0xf4, 0x16, 0x86, 0x3f,
+	0x28, 0x45, 0x42, 0xba, 0x4a, 0x53, 0x42, 0x6b, 0xd1, 0x8a, 0x57, 0xd0, 0x97, 0x3b, 0x11, 0x06,
+	0x93, 0x93, 0x59, 0x3f, 0x3e, 0x18, 0xd1, 0x04, 0x60, 0x69, 0xb4, 0x35, 0xc4, 0xaa, 0xfe, 0xff,
+	0x35, 0xfe, 0x04, 0x30, 0x58, 0xca, 0x4a, 0x6e, 0x54, 0xa1, 0x58, 0xa1, 0x15, 0x37, 0x30, 0x48,
+	0x8e, 0xb4, 0x9b, 0x79, 0x7e, 0x39, 0x9d, 0xfb, 0x78, 0xf3, 0x63, 0xf6, 0x81, 0xf8, 0xa2, 0x99,
+	0xb6, 0xf1, 0x83, 0xde, 0xf1, 0x0a, 0x46, 0x8f, 0x10, 0x31, 0x84, 0x93, 0x3b, 0xdc, 0xb6, 0x97,
+	0x68, 0x4a, 0x31, 0x83, 0xee, 0xbd, 0x2c, 0x6a, 0x0c, 0x3b, 0x93, 0x60, 0x76, 0x7e, 0x29, 0x1e,
+	0x7d, 0x6b, 0x1b, 0x7b, 0xe0, 0x63, 0xe7, 0x43, 0x10, 0x0d, 0x00, 0x0e, 0x07, 0xd1, 0xdf, 0x00,
+	0xfa, 0x4d, 0x44, 0xd4, 0x8c, 0x24, 0x9e, 0x42, 0x47, 0xa5, 0xed, 0xa3, 0x75, 0x54, 0xda, 0x24,
+	0xce, 0x8d, 0x65, 0x37, 0xb8, 0x1f, 0xbb, 0xba, 0xf1, 0x2a, 0x43, 0x1c, 0x9e, 0x38, 0xca, 0xd5,
+	0xe2, 0x05, 0xf4, 0x4a, 0x5b, 0xad, 0x55, 0x1a, 0x9e, 0x3a, 0xb2, 0x5b, 0xda, 0xea, 0x5b, 0x2a,
+	0xc6, 0x70, 0xa6, 0x52, 0xd4, 0xac, 0x78, 0x1b, 0x76, 0x27, 0xc1, 0x6c, 0x10, 0xef, 0xb5, 0x98,
+	0xc2, 0xb3, 0xa4, 0x50, 0xa8, 0x79, 0xcd, 0x85, 0x5d, 0x27, 0x48, 0x1c, 0xf6, 0x1c, 0x72, 0xe1,
+	0xed, 0x9f, 0x85, 0x5d, 0x22, 0x71, 0xc3, 0x59, 0xa4, 0x7b, 0xa4, 0x03, 0xf7, 0xc4, 0x73, 0xde,
+	0x6e, 0xb9, 0xe8, 0x06, 0xce, 0xda, 0xe5, 0x5a, 0xf1, 0x09, 0x46, 0xc9, 0x2e, 0xd3, 0xba, 0x94,
+	0x55, 0xa5, 0x74, 0xd6, 0x2e, 0x62, 0xb4, 0x7f, 0x9c, 0x1d, 0x10, 0x0f, 0xf7, 0xec, 0x77, 0x8f,
+	0x5e, 0xaf, 0xe0, 0xb5, 0xa1, 0x6c, 0x9e, 0x6f, 0x2b, 0xa4, 0x02, 0xd3, 0x0c, 0x69, 0x7e, 0x2b,
+	0x37, 0xa4, 0x12, 0xff, 0xaf, 0xda, 0x76, 0xc6, 0xaf, 0x37, 0x99, 0xe2, 0xbc, 0xde, 0x34, 0x72,
+	0x71, 0x04, 0x2f, 0x3c, 0xbc, 0xf0, 0xf0, 0xc2, 0xc3, 0x9b, 0x9e, 0x93, 0xef, 0xff, 0x0d, 0x00,
+	0xcc, 0x50, 0x40, 0x23, 0x05, 0x03, 0x00, 0x00,
 }
@@ -38,7 +38,7 @@ func (m *KVRWSet) Reset()         { *m = KVRWSet{} }
 func (m *KVRWSet) String() string { return proto.CompactTextString(m) }
 func (*KVRWSet) ProtoMessage()    {}
 func (*KVRWSet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_rwset_b744a14a894993b5, []int{0}
+	return fileDescriptor_kv_rwset_92884ee3e633f685, []int{0}
 }
 func (m *KVRWSet) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KVRWSet.Unmarshal(m, b)
@@ -100,7 +100,7 @@ func (m *HashedRWSet) Reset()         { *m = HashedRWSet{} }
 func (m *HashedRWSet) String() string { return proto.CompactTextString(m) }
 func (*HashedRWSet) ProtoMessage()    {}
 func (*HashedRWSet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_rwset_b744a14a894993b5, []int{1}
+	return fileDescriptor_kv_rwset_92884ee3e633f685, []int{1}
 }
 func (m *HashedRWSet) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_HashedRWSet.Unmarshal(m, b)
@@ -155,7 +155,7 @@ func (m *KVRead) Reset()         { *m = KVRead{} }
 func (m *KVRead) String() string { return proto.CompactTextString(m) }
 func (*KVRead) ProtoMessage()    {}
 func (*KVRead) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_rwset_b744a14a894993b5, []int{2}
+	return fileDescriptor_kv_rwset_92884ee3e633f685, []int{2}
 }
 func (m *KVRead) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KVRead.Unmarshal(m, b)
@@ -203,7 +203,7 @@ func (m *KVWrite) Reset()         { *m = KVWrite{} }
 func (m *KVWrite) String() string { return proto.CompactTextString(m) }
 func (*KVWrite) ProtoMessage()    {}
 func (*KVWrite) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_rwset_b744a14a894993b5, []int{3}
+	return fileDescriptor_kv_rwset_92884ee3e633f685, []int{3}
 }
 func (m *KVWrite) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KVWrite.Unmarshal(m, b)
@@ -257,7 +257,7 @@ func (m *KVMetadataWrite) Reset()         { *m = KVMetadataWrite{} }
 func (m *KVMetadataWrite) String() string { return proto.CompactTextString(m) }
 func (*KVMetadataWrite) ProtoMessage()    {}
 func (*KVMetadataWrite) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_rwset_b744a14a894993b5, []int{4}
+	return fileDescriptor_kv_rwset_92884ee3e633f685, []int{4}
 }
 func (m *KVMetadataWrite) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KVMetadataWrite.Unmarshal(m, b)
@@ -306,7 +306,7 @@ func (m *KVReadHash) Reset()         { *m = KVReadHash{} }
 func (m *KVReadHash) String() string { return proto.CompactTextString(m) }
 func (*KVReadHash) ProtoMessage()    {}
 func (*KVReadHash) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_rwset_b744a14a894993b5, []int{5}
+	return fileDescriptor_kv_rwset_92884ee3e633f685, []int{5}
 }
 func (m *KVReadHash) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KVReadHash.Unmarshal(m, b)
@@ -345,6 +345,7 @@ type KVWriteHash struct {
 	KeyHash              []byte   `protobuf:"bytes,1,opt,name=key_hash,json=keyHash,proto3" json:"key_hash,omitempty"`
 	IsDelete             bool     `protobuf:"varint,2,opt,name=is_delete,json=isDelete" json:"is_delete,omitempty"`
 	ValueHash            []byte   `protobuf:"bytes,3,opt,name=value_hash,json=valueHash,proto3" json:"value_hash,omitempty"`
+	IsPurge              bool     `protobuf:"varint,4,opt,name=is_purge,json=isPurge" json:"is_purge,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -354,7 +355,7 @@ func (m *KVWriteHash) Reset()         { *m = KVWriteHash{} }
 func (m *KVWriteHash) String() string { return proto.CompactTextString(m) }
 func (*KVWriteHash) ProtoMessage()    {}
 func (*KVWriteHash) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_rwset_b744a14a894993b5, []int{6}
+	return fileDescriptor_kv_rwset_92884ee3e633f685, []int{6}
 }
 func (m *KVWriteHash) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KVWriteHash.Unmarshal(m, b)
@@ -395,6 +396,13 @@ func (m *KVWriteHash) GetValueHash() []byte {
 	return nil
 }
 
+func (m *KVWriteHash) GetIsPurge() bool {
+	if m != nil {
+		return m.IsPurge
+	}
+	return false
+}
+
 // KVMetadataWriteHash captures all the upserts to the metadata associated with a key hash
 type KVMetadataWriteHash struct {
 	KeyHash              []byte             `protobuf:"bytes,1,opt,name=key_hash,json=keyHash,proto3" json:"key_hash,omitempty"`
@@ -408,7 +416,7 @@ func (m *KVMetadataWriteHash) Reset()         { *m = KVMetadataWriteHash{} }
 func (m *KVMetadataWriteHash) String() string { return proto.CompactTextString(m) }
 func (*KVMetadataWriteHash) ProtoMessage()    {}
 func (*KVMetadataWriteHash) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_rwset_b744a14a894993b5, []int{7}
+	return fileDescriptor_kv_rwset_92884ee3e633f685, []int{7}
 }
 func (m *KVMetadataWriteHash) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KVMetadataWriteHash.Unmarshal(m, b)
@@ -455,7 +463,7 @@ func (m *KVMetadataEntry) Reset()         { *m = KVMetadataEntry{} }
 func (m *KVMetadataEntry) String() string { return proto.CompactTextString(m) }
 func (*KVMetadataEntry) ProtoMessage()    {}
 func (*KVMetadataEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_rwset_b744a14a894993b5, []int{8}
+	return fileDescriptor_kv_rwset_92884ee3e633f685, []int{8}
 }
 func (m *KVMetadataEntry) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KVMetadataEntry.Unmarshal(m, b)
@@ -505,7 +513,7 @@ func (m *Version) Reset()         { *m = Version{} }
 func (m *Version) String() string { return proto.CompactTextString(m) }
 func (*Version) ProtoMessage()    {}
 func (*Version) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_rwset_b744a14a894993b5, []int{9}
+	return fileDescriptor_kv_rwset_92884ee3e633f685, []int{9}
 }
 func (m *Version) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Version.Unmarshal(m, b)
@@ -562,7 +570,7 @@ func (m *RangeQueryInfo) Reset()         { *m = RangeQueryInfo{} }
 func (m *RangeQueryInfo) String() string { return proto.CompactTextString(m) }
 func (*RangeQueryInfo) ProtoMessage()    {}
 func (*RangeQueryInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_rwset_b744a14a894993b5, []int{10}
+	return fileDescriptor_kv_rwset_92884ee3e633f685, []int{10}
 }
 func (m *RangeQueryInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RangeQueryInfo.Unmarshal(m, b)
@@ -724,7 +732,7 @@ func (m *QueryReads) Reset()         { *m = QueryReads{} }
 func (m *QueryReads) String() string { return proto.CompactTextString(m) }
 func (*QueryReads) ProtoMessage()    {}
 func (*QueryReads) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_rwset_b744a14a894993b5, []int{11}
+	return fileDescriptor_kv_rwset_92884ee3e633f685, []int{11}
 }
 func (m *QueryReads) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryReads.Unmarshal(m, b)
@@ -769,7 +777,7 @@ func (m *QueryReadsMerkleSummary) Reset()         { *m = QueryReadsMerkleSummary
 func (m *QueryReadsMerkleSummary) String() string { return proto.CompactTextString(m) }
 func (*QueryReadsMerkleSummary) ProtoMessage()    {}
 func (*QueryReadsMerkleSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_rwset_b744a14a894993b5, []int{12}
+	return fileDescriptor_kv_rwset_92884ee3e633f685, []int{12}
 }
 func (m *QueryReadsMerkleSummary) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryReadsMerkleSummary.Unmarshal(m, b)
@@ -827,56 +835,56 @@ func init() {
 }
 
 func init() {
-	proto.RegisterFile("ledger/rwset/kvrwset/kv_rwset.proto", fileDescriptor_kv_rwset_b744a14a894993b5)
-}
-
-var fileDescriptor_kv_rwset_b744a14a894993b5 = []byte{
-	// 740 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xdf, 0x6b, 0xdb, 0x48,
-	0x10, 0x8e, 0x7f, 0xcb, 0x63, 0x3b, 0xf1, 0x6d, 0x72, 0x44, 0xc7, 0xdd, 0x81, 0x51, 0x38, 0x30,
-	0x79, 0xb0, 0xc1, 0x07, 0xc7, 0x85, 0xe3, 0x1e, 0x5a, 0xe2, 0x92, 0x92, 0x26, 0xd0, 0x0d, 0x24,
-	0xd0, 0x17, 0xb1, 0x8e, 0x26, 0xb6, 0xb0, 0x25, 0xa5, 0xab, 0x95, 0x6d, 0x3d, 0x95, 0xfe, 0x75,
-	0xfd, 0x47, 0xfa, 0x87, 0x94, 0x9d, 0x95, 0x63, 0xc5, 0x75, 0x0c, 0xed, 0x93, 0xb5, 0xf3, 0xcd,
-	0x37, 0x3b, 0xdf, 0x37, 0xde, 0x5d, 0x38, 0x99, 0xa1, 0x37, 0x46, 0xd9, 0x97, 0x8b, 0x18, 0x55,
-	0x7f, 0x3a, 0x5f, 0xfd, 0xba, 0xf4, 0xd1, 0x7b, 0x94, 0x91, 0x8a, 0x58, 0x2d, 0x8b, 0x3b, 0x5f,
-	0x0b, 0x50, 0xbb, 0xbc, 0xe5, 0x77, 0x37, 0xa8, 0xd8, 0x5f, 0x50, 0x91, 0x28, 0xbc, 0xd8, 0x2e,
-	0x74, 0x4a, 0xdd, 0xc6, 0xe0, 0xa0, 0x97, 0x25, 0xf5, 0x2e, 0x6f, 0x39, 0x0a, 0x8f, 0x1b, 0x94,
-	0x0d, 0x81, 0x49, 0x11, 0x8e, 0xd1, 0xfd, 0x98, 0xa0, 0xf4, 0x31, 0x76, 0xfd, 0xf0, 0x21, 0xb2,
-	0x8b, 0xc4, 0x39, 0x7e, 0xe2, 0x70, 0x9d, 0xf2, 0x3e, 0x41, 0x99, 0xbe, 0x0d, 0x1f, 0x22, 0xde,
-	0x96, 0xab, 0xb5, 0x8f, 0xb1, 0x8e, 0xb0, 0x2e, 0x54, 0x17, 0xd2, 0x57, 0x18, 0xdb, 0x25, 0xa2,
-	0xb6, 0x73, 0xdb, 0xdd, 0x69, 0x80, 0x67, 0x38, 0x7b, 0x05, 0x07, 0x01, 0x2a, 0xe1, 0x09, 0x25,
-	0xdc, 0x8c, 0x52, 0x26, 0x8a, 0x9d, 0xa3, 0x5c, 0x65, 0x19, 0x86, 0xba, 0x1f, 0xe4, 0x97, 0xb1,
-	0xf3, 0xa5, 0x00, 0x8d, 0x0b, 0x11, 0x4f, 0xd0, 0x33, 0x52, 0xff, 0x81, 0xe6, 0x84, 0x96, 0x6e,
-	0x5e, 0xf1, 0xe1, 0x86, 0x62, 0xcd, 0xe0, 0x0d, 0x93, 0xc8, 0x49, 0xfb, 0x19, 0xb4, 0x32, 0x5e,
-	0xd6, 0x88, 0x91, 0x7d, 0xb4, 0xd9, 0x3b, 0x31, 0xb3, 0x2d, 0x4c, 0x0b, 0x6c, 0xf8, 0xbd, 0x0a,
-	0x23, 0xfc, 0x8f, 0x97, 0x54, 0x50, 0x91, 0x4d, 0x25, 0x6f, 0xa0, 0x6a, 0x9a, 0x63, 0x6d, 0x28,
-	0x4d, 0x31, 0xb5, 0x0b, 0x9d, 0x42, 0xb7, 0xce, 0xf5, 0x27, 0x3b, 0x85, 0xda, 0x1c, 0x65, 0xec,
-	0x47, 0xa1, 0x5d, 0xec, 0x14, 0x9e, 0x79, 0x7a, 0x6b, 0xe2, 0x7c, 0x95, 0xe0, 0x5c, 0xeb, 0xb9,
-	0x53, 0xcd, 0x2d, 0x85, 0x7e, 0x87, 0xba, 0x1f, 0xbb, 0x1e, 0xce, 0x50, 0x21, 0x95, 0xb2, 0xb8,
-	0xe5, 0xc7, 0xe7, 0xb4, 0x66, 0x47, 0x50, 0x99, 0x8b, 0x59, 0x82, 0x76, 0xa9, 0x53, 0xe8, 0x36,
-	0xb9, 0x59, 0x38, 0x77, 0x70, 0xb0, 0xd1, 0xfe, 0x96, 0xba, 0x03, 0xa8, 0x61, 0xa8, 0xf4, 0x5f,
-	0x20, 0x33, 0x6e, 0xdb, 0x04, 0x87, 0xa1, 0x92, 0x29, 0x5f, 0x25, 0x3a, 0x37, 0x00, 0xeb, 0x69,
-	0xb0, 0xdf, 0xc0, 0x9a, 0x62, 0xea, 0x6a, 0x67, 0xa9, 0x70, 0x93, 0xd7, 0xa6, 0x98, 0x12, 0xf4,
-	0x23, 0xea, 0x3d, 0x68, 0xe4, 0x26, 0xb5, 0xab, 0xea, 0x4e, 0x2b, 0xfe, 0x04, 0x20, 0xf5, 0x86,
-	0x69, 0xfc, 0xa8, 0x53, 0x44, 0x73, 0x1d, 0x0f, 0x0e, 0xb7, 0x8c, 0x74, 0xd7, 0x6e, 0x3f, 0x63,
-	0xd0, 0x7f, 0x79, 0xe7, 0x09, 0x63, 0x0c, 0xca, 0xa1, 0x08, 0x30, 0xb3, 0x9e, 0xbe, 0xd7, 0x63,
-	0x2b, 0xe6, 0xc7, 0xf6, 0x3f, 0xd4, 0x32, 0x73, 0xb4, 0xd2, 0xd1, 0x2c, 0xba, 0x9f, 0xba, 0x61,
-	0x12, 0x10, 0xb3, 0xcc, 0x2d, 0x0a, 0x5c, 0x27, 0x01, 0xfb, 0x15, 0xaa, 0x6a, 0x49, 0x48, 0x91,
-	0x90, 0x8a, 0x5a, 0x5e, 0x27, 0x81, 0xf3, 0xb9, 0x08, 0xfb, 0xcf, 0x4f, 0xba, 0x2e, 0x13, 0x2b,
-	0x21, 0x95, 0xbb, 0x9e, 0xbd, 0x45, 0x81, 0x4b, 0x4c, 0xd9, 0xb1, 0xd6, 0xe7, 0x11, 0x54, 0x24,
-	0xa8, 0x8a, 0xa1, 0xa7, 0x81, 0x13, 0x68, 0xf9, 0x4a, 0xba, 0xb8, 0x9c, 0x88, 0x24, 0x56, 0xe8,
-	0x91, 0x99, 0x16, 0x6f, 0xfa, 0x4a, 0x0e, 0x57, 0x31, 0x36, 0x80, 0xba, 0x14, 0x8b, 0xec, 0xc8,
-	0x96, 0x69, 0xc6, 0xeb, 0x23, 0x4b, 0x1d, 0xd0, 0x29, 0xbd, 0xd8, 0xe3, 0x96, 0x14, 0x0b, 0x73,
-	0x62, 0x39, 0x1c, 0x52, 0xbe, 0x1b, 0xa0, 0x9c, 0xce, 0xcc, 0xa4, 0x30, 0xb6, 0x2b, 0xc4, 0xee,
-	0x6c, 0x61, 0x5f, 0x51, 0xde, 0x4d, 0x12, 0x04, 0x42, 0xa6, 0x17, 0x7b, 0xfc, 0x17, 0xb9, 0x8e,
-	0xd2, 0x15, 0x12, 0xbf, 0x6e, 0x02, 0x98, 0x9a, 0xfa, 0xe6, 0x73, 0xfe, 0x05, 0x58, 0xb3, 0xd9,
-	0x29, 0x58, 0xfa, 0xae, 0xdd, 0x75, 0x8f, 0xd6, 0xa6, 0x73, 0xca, 0x75, 0x3e, 0xc1, 0xf1, 0x0b,
-	0xfb, 0xea, 0x7f, 0x56, 0x20, 0x96, 0xae, 0x87, 0x63, 0x89, 0x66, 0x8e, 0x2d, 0x5e, 0x0f, 0xc4,
-	0xf2, 0x9c, 0x02, 0xda, 0x64, 0x0d, 0xcf, 0x70, 0x8e, 0x33, 0x72, 0xb2, 0xc5, 0xad, 0x40, 0x2c,
-	0xdf, 0xe9, 0x35, 0xeb, 0x42, 0xfb, 0x09, 0x5c, 0xe9, 0xd5, 0x57, 0x4d, 0x93, 0xef, 0xaf, 0x72,
-	0x32, 0x21, 0x11, 0x0c, 0x22, 0x39, 0xee, 0x4d, 0xd2, 0x47, 0x94, 0xe6, 0xd9, 0xe8, 0x3d, 0x88,
-	0x91, 0xf4, 0xef, 0xcd, 0x33, 0x11, 0xf7, 0xb2, 0xa0, 0x69, 0x3f, 0x93, 0xf1, 0xe1, 0x6c, 0xec,
-	0xab, 0x49, 0x32, 0xea, 0xdd, 0x47, 0x41, 0x3f, 0x47, 0xed, 0x1b, 0x6a, 0xdf, 0x50, 0xfb, 0xdb,
-	0x9e, 0xa1, 0x51, 0x95, 0xc0, 0xbf, 0xbf, 0x05, 0x00, 0x00, 0xff, 0xff, 0xe3, 0xe1, 0xb5, 0x07,
-	0xa5, 0x06, 0x00, 0x00,
+	proto.RegisterFile("ledger/rwset/kvrwset/kv_rwset.proto", fileDescriptor_kv_rwset_92884ee3e633f685)
+}
+
+var fileDescriptor_kv_rwset_92884ee3e633f685 = []byte{
+	// 752 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x51, 0x6f, 0xe2, 0x46,
+	0x10, 0x3e, 0x13, 0x82, 0xcd, 0x00, 0x81, 0x6e, 0xae, 0x8a, 0xab, 0xb6, 0x12, 0xf2, 0xa9, 0x12,
+	0xba, 0x07, 0x90, 0xa8, 0x54, 0xf5, 0x54, 0xf5, 0xa1, 0xd5, 0x51, 0xa5, 0x4a, 0x2f, 0x6a, 0x37,
+	0x52, 0x22, 0xf5, 0xc5, 0x5a, 0xe2, 0x09, 0x58, 0x60, 0x3b, 0xdd, 0x5d, 0x03, 0x7e, 0x3a, 0xf5,
+	0xd7, 0xf5, 0x8f, 0xf4, 0x87, 0x54, 0x3b, 0x6b, 0x07, 0x42, 0x09, 0x52, 0xfb, 0xc4, 0xce, 0x7c,
+	0xf3, 0x8d, 0xe7, 0x9b, 0x61, 0x67, 0xe1, 0xcd, 0x12, 0xa3, 0x19, 0xca, 0x91, 0x5c, 0x2b, 0xd4,
+	0xa3, 0xc5, 0xaa, 0xfa, 0x0d, 0xe9, 0x30, 0x7c, 0x94, 0x99, 0xce, 0x98, 0x5b, 0xfa, 0x83, 0xbf,
+	0x1d, 0x70, 0xaf, 0x6e, 0xf9, 0xdd, 0x0d, 0x6a, 0xf6, 0x15, 0x9c, 0x4a, 0x14, 0x91, 0xf2, 0x9d,
+	0xfe, 0xc9, 0xa0, 0x35, 0xee, 0x0e, 0xcb, 0xa0, 0xe1, 0xd5, 0x2d, 0x47, 0x11, 0x71, 0x8b, 0xb2,
+	0x09, 0x30, 0x29, 0xd2, 0x19, 0x86, 0x7f, 0xe4, 0x28, 0x63, 0x54, 0x61, 0x9c, 0x3e, 0x64, 0x7e,
+	0x8d, 0x38, 0x17, 0x4f, 0x1c, 0x6e, 0x42, 0x7e, 0xcb, 0x51, 0x16, 0x3f, 0xa7, 0x0f, 0x19, 0xef,
+	0xc9, 0xca, 0x8e, 0x51, 0x19, 0x0f, 0x1b, 0x40, 0x63, 0x2d, 0x63, 0x8d, 0xca, 0x3f, 0x21, 0x6a,
+	0x6f, 0xe7, 0x73, 0x77, 0x06, 0xe0, 0x25, 0xce, 0x7e, 0x80, 0x6e, 0x82, 0x5a, 0x44, 0x42, 0x8b,
+	0xb0, 0xa4, 0xd4, 0x89, 0xe2, 0xef, 0x50, 0x3e, 0x94, 0x11, 0x96, 0x7a, 0x96, 0xec, 0x9a, 0x2a,
+	0xf8, 0xcb, 0x81, 0xd6, 0xa5, 0x50, 0x73, 0x8c, 0xac, 0xd4, 0x6f, 0xa0, 0x3d, 0x27, 0x33, 0xdc,
+	0x55, 0x7c, 0xbe, 0xa7, 0xd8, 0x30, 0x78, 0xcb, 0x06, 0x72, 0xd2, 0xfe, 0x0e, 0x3a, 0x25, 0xaf,
+	0x2c, 0xc4, 0xca, 0x7e, 0xbd, 0x5f, 0x3b, 0x31, 0xcb, 0x4f, 0xd8, 0x12, 0xd8, 0xe4, 0xdf, 0x2a,
+	0xac, 0xf0, 0x2f, 0x5e, 0x52, 0x41, 0x49, 0xf6, 0x95, 0xfc, 0x04, 0x0d, 0x5b, 0x1c, 0xeb, 0xc1,
+	0xc9, 0x02, 0x0b, 0xdf, 0xe9, 0x3b, 0x83, 0x26, 0x37, 0x47, 0xf6, 0x16, 0xdc, 0x15, 0x4a, 0x15,
+	0x67, 0xa9, 0x5f, 0xeb, 0x3b, 0xcf, 0x7a, 0x7a, 0x6b, 0xfd, 0xbc, 0x0a, 0x08, 0xae, 0xcd, 0xdc,
+	0x29, 0xe7, 0x81, 0x44, 0x9f, 0x43, 0x33, 0x56, 0x61, 0x84, 0x4b, 0xd4, 0x48, 0xa9, 0x3c, 0xee,
+	0xc5, 0xea, 0x3d, 0xd9, 0xec, 0x35, 0x9c, 0xae, 0xc4, 0x32, 0x47, 0xff, 0xa4, 0xef, 0x0c, 0xda,
+	0xdc, 0x1a, 0xc1, 0x1d, 0x74, 0xf7, 0xca, 0x3f, 0x90, 0x77, 0x0c, 0x2e, 0xa6, 0x5a, 0xc6, 0x4f,
+	0x8d, 0x3b, 0x34, 0xc1, 0x49, 0xaa, 0x65, 0xc1, 0xab, 0xc0, 0xe0, 0x06, 0x60, 0x3b, 0x0d, 0xf6,
+	0x19, 0x78, 0x0b, 0x2c, 0x42, 0xd3, 0x59, 0x4a, 0xdc, 0xe6, 0xee, 0x02, 0x0b, 0x82, 0xfe, 0x8b,
+	0xfa, 0x8f, 0xd0, 0xda, 0x99, 0xd4, 0xb1, 0xac, 0x47, 0x5b, 0xf1, 0x25, 0x00, 0xa9, 0xb7, 0x4c,
+	0xdb, 0x8f, 0x26, 0x79, 0xaa, 0xb4, 0xb1, 0x0a, 0x1f, 0x73, 0x39, 0x43, 0xbf, 0x4e, 0x54, 0x37,
+	0x56, 0xbf, 0x1a, 0x33, 0x88, 0xe0, 0xfc, 0xc0, 0xb4, 0x8f, 0x15, 0xf2, 0x7f, 0x7a, 0xf7, 0x1d,
+	0x74, 0xf7, 0x30, 0xc6, 0xa0, 0x9e, 0x8a, 0x04, 0xcb, 0xa9, 0xd0, 0x79, 0x3b, 0xd1, 0xda, 0xee,
+	0x44, 0xbf, 0x07, 0xb7, 0xec, 0x9b, 0x69, 0xc2, 0x74, 0x99, 0xdd, 0x2f, 0xc2, 0x34, 0x4f, 0x88,
+	0x59, 0xe7, 0x1e, 0x39, 0xae, 0xf3, 0x84, 0x7d, 0x0a, 0x0d, 0xbd, 0x21, 0xa4, 0x46, 0xc8, 0xa9,
+	0xde, 0x5c, 0xe7, 0x49, 0xf0, 0x67, 0x0d, 0xce, 0x9e, 0x2f, 0x01, 0x93, 0x46, 0x69, 0x21, 0x75,
+	0xb8, 0xfd, 0x5b, 0x78, 0xe4, 0xb8, 0xc2, 0x82, 0x5d, 0x18, 0x7d, 0x11, 0x41, 0x35, 0x82, 0x1a,
+	0x98, 0x46, 0x06, 0x78, 0x03, 0x9d, 0x58, 0xcb, 0x10, 0x37, 0x73, 0x91, 0x2b, 0x8d, 0x11, 0xf5,
+	0xd9, 0xe3, 0xed, 0x58, 0xcb, 0x49, 0xe5, 0x63, 0x63, 0x68, 0x4a, 0xb1, 0x2e, 0x6f, 0x73, 0xbd,
+	0xef, 0x3c, 0xbb, 0xcd, 0x54, 0x01, 0x5d, 0xe0, 0xcb, 0x57, 0xdc, 0x93, 0x62, 0x4d, 0x67, 0xc6,
+	0xe1, 0x9c, 0xe2, 0xc3, 0x04, 0xe5, 0x62, 0x69, 0x87, 0x88, 0xca, 0x3f, 0x25, 0x76, 0xff, 0x00,
+	0xfb, 0x03, 0xc5, 0xdd, 0xe4, 0x49, 0x22, 0x64, 0x71, 0xf9, 0x8a, 0x7f, 0x22, 0xb7, 0x5e, 0xda,
+	0x2e, 0xea, 0xc7, 0x36, 0x80, 0xcd, 0x69, 0x96, 0x62, 0xf0, 0x2d, 0xc0, 0x96, 0xcd, 0xde, 0x82,
+	0x67, 0xd6, 0xf0, 0xb1, 0x15, 0xeb, 0x2e, 0x56, 0x14, 0x1b, 0x7c, 0x84, 0x8b, 0x17, 0xbe, 0x6b,
+	0xfe, 0x74, 0x89, 0xd8, 0x84, 0x11, 0xce, 0x24, 0xda, 0x39, 0x76, 0x78, 0x33, 0x11, 0x9b, 0xf7,
+	0xe4, 0x30, 0x4d, 0x36, 0xf0, 0x12, 0x57, 0xb8, 0xa4, 0x4e, 0x76, 0xb8, 0x97, 0x88, 0xcd, 0x2f,
+	0xc6, 0x66, 0x03, 0xe8, 0x3d, 0x81, 0x95, 0x5e, 0xb3, 0x85, 0xda, 0xfc, 0xac, 0x8a, 0x29, 0x85,
+	0x64, 0x30, 0xce, 0xe4, 0x6c, 0x38, 0x2f, 0x1e, 0x51, 0xda, 0x17, 0x65, 0xf8, 0x20, 0xa6, 0x32,
+	0xbe, 0xb7, 0x2f, 0x88, 0x1a, 0x96, 0x4e, 0x5b, 0x7e, 0x29, 0xe3, 0xf7, 0x77, 0xb3, 0x58, 0xcf,
+	0xf3, 0xe9, 0xf0, 0x3e, 0x4b, 0x46, 0x3b, 0xd4, 0x91, 0xa5, 0x8e, 0x2c, 0x75, 0x74, 0xe8, 0x85,
+	0x9a, 0x36, 0x08, 0xfc, 0xfa, 0x9f, 0x01, 0x00, 0x23, 0xb1, 0x54, 0xcc, 0xc0, 0x06, 0x00, 0x00,
 }
@@ -0,0 +1,249 @@
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: orderer/etcdraft/configuration.proto
+
+package etcdraft // import "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer/etcdraft"
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// ConfigMetadata is serialized and set as the value of ConsensusType.Metadata in
+// a channel configuration when the ConsensusType.Type is set "etcdraft".
+type ConfigMetadata struct {
+	Consenters           []*Consenter `protobuf:"bytes,1,rep,name=consenters,proto3" json:"consenters,omitempty"`
+	Options              *Options     `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ConfigMetadata) Reset()         { *m = ConfigMetadata{} }
+func (m *ConfigMetadata) String() string { return proto.CompactTextString(m) }
+func (*ConfigMetadata) ProtoMessage()    {}
+func (*ConfigMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6f12d215c949b072, []int{0}
+}
+
+func (m *ConfigMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConfigMetadata.Unmarshal(m, b)
+}
+func (m *ConfigMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConfigMetadata.Marshal(b, m, deterministic)
+}
+func (m *ConfigMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConfigMetadata.Merge(m, src)
+}
+func (m *ConfigMetadata) XXX_Size() int {
+	return xxx_messageInfo_ConfigMetadata.Size(m)
+}
+func (m *ConfigMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConfigMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConfigMetadata proto.InternalMessageInfo
+
+func (m *ConfigMetadata) GetConsenters() []*Consenter {
+	if m != nil {
+		return m.Consenters
+	}
+	return nil
+}
+
+func (m *ConfigMetadata) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+// Consenter represents a consenting node (i.e. replica).
+type Consenter struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Port                 uint32   `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	ClientTlsCert        []byte   `protobuf:"bytes,3,opt,name=client_tls_cert,json=clientTlsCert,proto3" json:"client_tls_cert,omitempty"`
+	ServerTlsCert        []byte   `protobuf:"bytes,4,opt,name=server_tls_cert,json=serverTlsCert,proto3" json:"server_tls_cert,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Consenter) Reset()         { *m = Consenter{} }
+func (m *Consenter) String() string { return proto.CompactTextString(m) }
+func (*Consenter) ProtoMessage()    {}
+func (*Consenter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6f12d215c949b072, []int{1}
+}
+
+func (m *Consenter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Consenter.Unmarshal(m, b)
+}
+func (m *Consenter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Consenter.Marshal(b, m, deterministic)
+}
+func (m *Consenter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Consenter.Merge(m, src)
+}
+func (m *Consenter) XXX_Size() int {
+	return xxx_messageInfo_Consenter.Size(m)
+}
+func (m *Consenter) XXX_DiscardUnknown() {
+	xxx_messageInfo_Consenter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Consenter proto.InternalMessageInfo
+
+func (m *Consenter) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *Consenter) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *Consenter) GetClientTlsCert() []byte {
+	if m != nil {
+		return m.ClientTlsCert
+	}
+	return nil
+}
+
+func (m *Consenter) GetServerTlsCert() []byte {
+	if m != nil {
+		return m.ServerTlsCert
+	}
+	return nil
+}
+
+// Options to be specified for all the etcd/raft nodes. These can be modified on a
+// per-channel basis.
+type Options struct {
+	TickInterval      string `protobuf:"bytes,1,opt,name=tick_interval,json=tickInterval,proto3" json:"tick_interval,omitempty"`
+	ElectionTick      uint32 `protobuf:"varint,2,opt,name=election_tick,json=electionTick,proto3" json:"election_tick,omitempty"`
+	HeartbeatTick     uint32 `protobuf:"varint,3,opt,name=heartbeat_tick,json=heartbeatTick,proto3" json:"heartbeat_tick,omitempty"`
+	MaxInflightBlocks uint32 `protobuf:"varint,4,opt,name=max_inflight_blocks,json=maxInflightBlocks,proto3" json:"max_inflight_blocks,omitempty"`
+	// Take snapshot when cumulative data exceeds certain size in bytes.
+	SnapshotIntervalSize uint32   `protobuf:"varint,5,opt,name=snapshot_interval_size,json=snapshotIntervalSize,proto3" json:"snapshot_interval_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Options) Reset()         { *m = Options{} }
+func (m *Options) String() string { return proto.CompactTextString(m) }
+func (*Options) ProtoMessage()    {}
+func (*Options) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6f12d215c949b072, []int{2}
+}
+
+func (m *Options) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Options.Unmarshal(m, b)
+}
+func (m *Options) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Options.Marshal(b, m, deterministic)
+}
+func (m *Options) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Options.Merge(m, src)
+}
+func (m *Options) XXX_Size() int {
+	return xxx_messageInfo_Options.Size(m)
+}
+func (m *Options) XXX_DiscardUnknown() {
+	xxx_messageInfo_Options.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Options proto.InternalMessageInfo
+
+func (m *Options) GetTickInterval() string {
+	if m != nil {
+		return m.TickInterval
+	}
+	return ""
+}
+
+func (m *Options) GetElectionTick() uint32 {
+	if m != nil {
+		return m.ElectionTick
+	}
+	return 0
+}
+
+func (m *Options) GetHeartbeatTick() uint32 {
+	if m != nil {
+		return m.HeartbeatTick
+	}
+	return 0
+}
+
+func (m *Options) GetMaxInflightBlocks() uint32 {
+	if m != nil {
+		return m.MaxInflightBlocks
+	}
+	return 0
+}
+
+func (m *Options) GetSnapshotIntervalSize() uint32 {
+	if m != nil {
+		return m.SnapshotIntervalSize
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ConfigMetadata)(nil), "sdk.etcdraft.ConfigMetadata")
+	proto.RegisterType((*Consenter)(nil), "sdk.etcdraft.Consenter")
+	proto.RegisterType((*Options)(nil), "sdk.etcdraft.Options")
+}
+
+func init() {
+	proto.RegisterFile("orderer/etcdraft/configuration.proto", fileDescriptor_6f12d215c949b072)
+}
+
+var fileDescriptor_6f12d215c949b072 = []byte{
+	// 392 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x92, 0x4f, 0x6b, 0xdc, 0x30,
+	0x10, 0xc5, 0x71, 0x37, 0x6d, 0x1a, 0x65, 0x9d, 0x12, 0xa5, 0x14, 0x1f, 0xcd, 0xf6, 0x0f, 0x86,
+	0x12, 0x19, 0x92, 0x1e, 0x7a, 0xce, 0x9e, 0x72, 0x28, 0x05, 0x37, 0xa7, 0x5e, 0x8c, 0x2c, 0x8f,
+	0x6d, 0x75, 0xb5, 0x96, 0x19, 0x4d, 0x42, 0x9a, 0x6b, 0xbf, 0x68, 0x3f, 0x4a, 0xb1, 0x64, 0x3b,
+	0x4b, 0x6f, 0xc3, 0x7b, 0xbf, 0x37, 0x7a, 0xa0, 0x61, 0x1f, 0x2c, 0xd6, 0x80, 0x80, 0x39, 0x90,
+	0xaa, 0x51, 0x36, 0x94, 0x2b, 0xdb, 0x37, 0xba, 0xbd, 0x47, 0x49, 0xda, 0xf6, 0x62, 0x40, 0x4b,
+	0x96, 0xbf, 0x9e, 0xdd, 0x0d, 0xb2, 0xb3, 0xad, 0x07, 0xbe, 0x01, 0xc9, 0x5a, 0x92, 0xe4, 0xd7,
+	0x8c, 0x29, 0xdb, 0x3b, 0xe8, 0x09, 0xd0, 0x25, 0x51, 0xba, 0xca, 0x4e, 0xaf, 0x2e, 0xc4, 0x1c,
+	0x10, 0xdb, 0xd9, 0x2b, 0x0e, 0x30, 0xfe, 0x99, 0x1d, 0xdb, 0x61, 0x7c, 0xc0, 0x25, 0x2f, 0xd2,
+	0x28, 0x3b, 0xbd, 0x3a, 0x7f, 0x4e, 0x7c, 0x0f, 0x46, 0x31, 0x13, 0x9b, 0x3f, 0x11, 0x3b, 0x59,
+	0xd6, 0x70, 0xce, 0x8e, 0x3a, 0xeb, 0x28, 0x89, 0xd2, 0x28, 0x3b, 0x29, 0xfc, 0x3c, 0x6a, 0x83,
+	0x45, 0xf2, 0xbb, 0xe2, 0xc2, 0xcf, 0xfc, 0x13, 0x7b, 0xa3, 0x8c, 0x86, 0x9e, 0x4a, 0x32, 0xae,
+	0x54, 0x80, 0x94, 0xac, 0xd2, 0x28, 0x5b, 0x17, 0x71, 0x90, 0xef, 0x8c, 0xdb, 0x42, 0xe0, 0x1c,
+	0xe0, 0x03, 0xe0, 0x33, 0x77, 0x14, 0xb8, 0x20, 0x4f, 0xdc, 0xe6, 0x6f, 0xc4, 0x8e, 0xa7, 0x6a,
+	0xfc, 0x3d, 0x8b, 0x49, 0xab, 0x5d, 0xa9, 0xc7, 0x46, 0x0f, 0xd2, 0x4c, 0x65, 0xd6, 0xa3, 0x78,
+	0x3b, 0x69, 0x23, 0x04, 0x06, 0xd4, 0x98, 0x28, 0x47, 0x63, 0x6a, 0xb7, 0x9e, 0xc5, 0x3b, 0xad,
+	0x76, 0xfc, 0x23, 0x3b, 0xeb, 0x40, 0x22, 0x55, 0x20, 0x29, 0x50, 0x2b, 0x4f, 0xc5, 0x8b, 0xea,
+	0x31, 0xc1, 0x2e, 0xf6, 0xf2, 0xb1, 0xd4, 0x7d, 0x63, 0x74, 0xdb, 0x51, 0x59, 0x19, 0xab, 0x76,
+	0xce, 0x17, 0x8d, 0x8b, 0xf3, 0xbd, 0x7c, 0xbc, 0x9d, 0x9c, 0x1b, 0x6f, 0xf0, 0x2f, 0xec, 0x9d,
+	0xeb, 0xe5, 0xe0, 0x3a, 0x4b, 0x4b, 0xc9, 0xd2, 0xe9, 0x27, 0x48, 0x5e, 0xfa, 0xc8, 0xdb, 0xd9,
+	0x9d, 0xdb, 0xfe, 0xd0, 0x4f, 0x70, 0xf3, 0x8b, 0x09, 0x8b, 0xad, 0xe8, 0x7e, 0x0f, 0x80, 0x06,
+	0xea, 0x16, 0x50, 0x34, 0xb2, 0x42, 0xad, 0xc2, 0x19, 0x38, 0x31, 0x1d, 0xcb, 0xf2, 0x57, 0x3f,
+	0xbf, 0xb6, 0x9a, 0xba, 0xfb, 0x4a, 0x28, 0xbb, 0xcf, 0x0f, 0x62, 0x79, 0x88, 0x5d, 0x86, 0xd8,
+	0x65, 0x6b, 0xf3, 0xff, 0xcf, 0xac, 0x7a, 0xe5, 0xbd, 0xeb, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff,
+	0x25, 0x12, 0x78, 0xc2, 0x81, 0x02, 0x00, 0x00,
+}